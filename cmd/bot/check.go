@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/schedule"
+)
+
+// checkConfig loads and validates the configuration without starting the
+// bot, for `ravenbot --check-config` before a deploy or in CI. Warnings go
+// to stderr; a summary of the jobs and when they next run goes to stdout.
+func checkConfig() int {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config is invalid: %v\n", err)
+		return 1
+	}
+
+	profile := ""
+	if cfg.Profile != "" {
+		profile = fmt.Sprintf(" (profile %s)", cfg.Profile)
+	}
+	fmt.Printf("config is valid%s: %s backend, %d MCP servers, %d jobs\n", profile, cfg.AIBackend, len(cfg.MCPServers), len(cfg.Jobs))
+	now := time.Now()
+	for _, job := range cfg.Jobs {
+		when := "after " + strings.Join(job.RunAfter, ", ")
+		if job.Schedule != "" {
+			s, err := schedule.Parse(job.Schedule, cfg.JobLocation(job))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "job %q: %v\n", job.Name, err)
+				return 1
+			}
+			when = "next " + s.Next(now).In(s.Location()).Format("Mon Jan 2 15:04 MST")
+		}
+		fmt.Printf("  %s (%s): %s\n", job.Name, job.Type, when)
+	}
+	return 0
+}
+
+// configCommand runs `ravenbot config validate [file]`, which checks a
+// config file like --check-config, and `ravenbot config schema`, which
+// prints the JSON Schema of config.json for editors and CI.
+func configCommand(args []string) int {
+	const usage = "usage: ravenbot config [validate [file] | schema]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "validate":
+		fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() > 1 {
+			fmt.Fprintln(os.Stderr, usage)
+			return 2
+		}
+		if fs.NArg() == 1 {
+			_ = os.Setenv("RAVENBOT_CONFIG", fs.Arg(0))
+		}
+		return checkConfig()
+	case "schema":
+		schema, err := config.Schema()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config schema: %v\n", err)
+			return 1
+		}
+		_, _ = os.Stdout.Write(schema)
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "unknown config command %q\n%s\n", args[0], usage)
+	return 2
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// options are the command-line flags. They override the environment and
+// config.json: --config, --profile and --data-dir by setting
+// RAVENBOT_CONFIG, RAVENBOT_PROFILE and DATA_DIR, so reloads and
+// subcommands see them too.
+type options struct {
+	configFile  string
+	profile     string
+	dataDir     string
+	logLevel    slog.Level
+	logFormat   string
+	checkConfig bool
+}
+
+// parseFlags parses the flags in args and returns them with the remaining
+// arguments, the subcommand if any. Invalid flags exit with usage.
+func parseFlags(args []string) (options, []string) {
+	fs := flag.NewFlagSet("ravenbot", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: ravenbot [flags] [export-state [-o file] | import-state file | mcp [-http addr] [-session id] | config validate [file] | config schema]\n\nflags:\n")
+		fs.PrintDefaults()
+	}
+	var opts options
+	level := envOr("LOG_LEVEL", "info")
+	fs.StringVar(&opts.configFile, "config", "", "config file to read (default config.json; also RAVENBOT_CONFIG)")
+	fs.StringVar(&opts.profile, "profile", "", "config profile to layer on the base config, e.g. dev or prod (also RAVENBOT_PROFILE)")
+	fs.StringVar(&opts.dataDir, "data-dir", "", "directory for the database, backups and MCP data (also DATA_DIR)")
+	fs.StringVar(&level, "log-level", level, "least severe log messages written: debug, info, warn or error (also LOG_LEVEL)")
+	fs.StringVar(&opts.logFormat, "log-format", envOr("LOG_FORMAT", "json"), "log format: json or text (also LOG_FORMAT)")
+	fs.BoolVar(&opts.checkConfig, "check-config", false, "validate the configuration and exit without starting the bot")
+	_ = fs.Parse(args)
+
+	if err := opts.logLevel.UnmarshalText([]byte(level)); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -log-level %q: must be debug, info, warn or error\n", level)
+		os.Exit(2)
+	}
+	if opts.logFormat != "json" && opts.logFormat != "text" {
+		fmt.Fprintf(os.Stderr, "invalid -log-format %q: must be json or text\n", opts.logFormat)
+		os.Exit(2)
+	}
+	if opts.configFile != "" {
+		_ = os.Setenv("RAVENBOT_CONFIG", opts.configFile)
+	}
+	if opts.profile != "" {
+		_ = os.Setenv("RAVENBOT_PROFILE", opts.profile)
+	}
+	if opts.dataDir != "" {
+		_ = os.Setenv("DATA_DIR", opts.dataDir)
+	}
+	return opts, fs.Args()
+}
+
+// logHandler returns the slog handler writing to w in the chosen format
+// and level.
+func (o options) logHandler(w io.Writer) slog.Handler {
+	handlerOpts := &slog.HandlerOptions{Level: o.logLevel}
+	if o.logFormat == "text" {
+		return slog.NewTextHandler(w, handlerOpts)
+	}
+	return slog.NewJSONHandler(w, handlerOpts)
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
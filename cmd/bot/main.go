@@ -15,13 +15,23 @@ import (
 	"github.com/raythurman2386/cronlib"
 	"github.com/raythurman2386/ravenbot/internal/agent"
 	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/crypt"
 	"github.com/raythurman2386/ravenbot/internal/db"
 	"github.com/raythurman2386/ravenbot/internal/handler"
-	"github.com/raythurman2386/ravenbot/internal/notifier"
 	"github.com/raythurman2386/ravenbot/internal/stats"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 )
 
 func main() {
+	opts, args := parseFlags(os.Args[1:])
+	if opts.checkConfig {
+		os.Exit(checkConfig())
+	}
+	if len(args) > 0 {
+		os.Exit(runCommand(args[0], args[1:]))
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -39,7 +49,7 @@ func main() {
 	defer logFile.Close()
 
 	multiWriter := io.MultiWriter(os.Stdout, logFile)
-	logger := slog.New(slog.NewJSONHandler(multiWriter, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	logger := slog.New(opts.logHandler(multiWriter))
 	slog.SetDefault(logger)
 
 	cfg, err := config.LoadConfig()
@@ -47,8 +57,11 @@ func main() {
 		slog.Error("Failed to load config", "error", err)
 		os.Exit(1)
 	}
+	applyPoliteness(cfg)
+	applyHTTPCache(cfg)
+	applyHostAccess(cfg)
 
-	database, err := db.InitDB(cfg.DBPath)
+	database, err := openDatabase(cfg)
 	if err != nil {
 		slog.Error("Failed to initialize database", "error", err)
 		os.Exit(1)
@@ -58,77 +71,32 @@ func main() {
 			slog.Error("Failed to close database", "error", err)
 		}
 	}()
+	botStats := stats.New()
+	database.Instrument(botStats, cfg.SlowQuery())
+	slog.Info("Database ready", "driver", database.Driver(), "encrypted", database.Cipher().Enabled())
 
 	scheduler := cronlib.NewCron()
-	botStats := stats.New()
 
-	bot, err := agent.NewAgent(ctx, cfg, database, botStats, &sqlite.Dialector{Conn: database.DB})
+	bot, err := agent.NewAgent(ctx, cfg, database, botStats, sessionDialector(database))
 	if err != nil {
 		slog.Error("Failed to create agent", "error", err)
 		os.Exit(1)
 	}
 
-	// Initialize Notifiers
-	var notifiers []notifier.Notifier
+	// Create handler with all dependencies; notifiers are attached by the app
+	// so they can be rebuilt on reload.
+	h := handler.New(bot, database, cfg, botStats, nil)
 
-	if cfg.TelegramBotToken != "" && cfg.TelegramChatID != 0 {
-		tn, err := notifier.NewTelegramNotifier(cfg.TelegramBotToken, cfg.TelegramChatID)
-		if err != nil {
-			slog.Warn("Failed to setup Telegram notifier", "error", err)
-		} else {
-			notifiers = append(notifiers, tn)
-		}
-	}
+	ravenApp := &app{ctx: ctx, scheduler: scheduler, bot: bot, h: h, cfg: cfg}
+	ravenApp.startNotifiers(cfg)
+	h.SetReloader(ravenApp.reload)
+	go ravenApp.watchConfig(ctx)
 
-	if cfg.DiscordBotToken != "" && cfg.DiscordChannelID != "" {
-		dn, err := notifier.NewDiscordNotifier(cfg.DiscordBotToken, cfg.DiscordChannelID)
-		if err != nil {
-			slog.Warn("Failed to setup Discord notifier", "error", err)
-		} else {
-			notifiers = append(notifiers, dn)
-		}
-	}
-
-	// Create handler with all dependencies
-	h := handler.New(bot, database, cfg, botStats, notifiers)
-
-	// Start Notifier Listeners
-	for _, n := range notifiers {
-		switch botNotifier := n.(type) {
-		case *notifier.TelegramNotifier:
-			go botNotifier.StartListener(ctx, func(chatID int64, text string) {
-				sessionID := fmt.Sprintf("telegram-%d", chatID)
-				h.HandleMessage(ctx, sessionID, text, botNotifier, func(reply string) {
-					if err := botNotifier.Send(ctx, reply); err != nil {
-						slog.Error("Failed to send Telegram reply", "error", err)
-					}
-				})
-			})
-		case *notifier.DiscordNotifier:
-			go botNotifier.StartListener(ctx, func(channelID string, text string) {
-				sessionID := fmt.Sprintf("discord-%s", channelID)
-				h.HandleMessage(ctx, sessionID, text, botNotifier, func(reply string) {
-					if err := botNotifier.Send(ctx, reply); err != nil {
-						slog.Error("Failed to send Discord reply", "error", err)
-					}
-				})
-			})
-		}
-	}
-
-	// Schedule jobs from config
-	for _, job := range cfg.Jobs {
-		_, err = scheduler.AddJobWithOptions(job.Schedule, func(ctx context.Context) {
-			h.RunJob(ctx, job)
-		}, cronlib.JobOptions{
-			Overlap: cronlib.OverlapForbid,
-		})
-		if err != nil {
-			slog.Error("Failed to schedule job", "name", job.Name, "error", err)
-			continue
-		}
-		slog.Info("Scheduled job", "name", job.Name, "schedule", job.Schedule)
-	}
+	// Schedule jobs from config, then make up runs missed while we were down
+	startup := time.Now()
+	ravenApp.scheduleJobs(cfg)
+	ravenApp.jobs.Go(func() { h.CatchUpJobs(ctx, startup) })
+	h.StartResourceWatches(ctx)
 
 	// Reminder check — runs every 30 seconds via cronlib
 	_, err = scheduler.AddJobWithOptions("*/30 * * * * *", func(ctx context.Context) {
@@ -142,17 +110,89 @@ func main() {
 		slog.Info("Scheduled reminder checker", "schedule", "*/30 * * * * *")
 	}
 
+	// Feed registry refresh — each feed is fetched when its interval is up
+	_, err = scheduler.AddJobWithOptions("0 */5 * * * *", func(ctx context.Context) {
+		h.RefreshFeeds(ctx)
+	}, cronlib.JobOptions{
+		Overlap: cronlib.OverlapForbid,
+	})
+	if err != nil {
+		slog.Error("Failed to schedule feed refresh", "error", err)
+	} else {
+		slog.Info("Scheduled feed refresh", "schedule", "0 */5 * * * *")
+	}
+
+	// Jules session polling — announces pull requests, questions and results
+	_, err = scheduler.AddJobWithOptions("0 */2 * * * *", func(ctx context.Context) {
+		h.PollJulesSessions(ctx)
+	}, cronlib.JobOptions{
+		Overlap: cronlib.OverlapForbid,
+	})
+	if err != nil {
+		slog.Error("Failed to schedule Jules session polling", "error", err)
+	} else {
+		slog.Info("Scheduled Jules session polling", "schedule", "0 */2 * * * *")
+	}
+
 	scheduler.Start()
 	slog.Info("ravenbot started", "time", time.Now().Format("15:04:05"))
 
-	// Graceful shutdown
+	// SIGHUP reloads the configuration; SIGINT/SIGTERM shut down gracefully.
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	for sig := range sigChan {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		slog.Info("Received SIGHUP, reloading configuration")
+		if _, err := ravenApp.reload(ctx); err != nil {
+			slog.Error("Config reload failed", "error", err)
+		}
+	}
 
-	<-sigChan
 	slog.Info("Shutting down ravenbot...")
 	cancel() // Signal context cancellation first so MCP children and goroutines stop
 	scheduler.Stop()
+	ravenApp.jobs.Wait()
+	if err := h.FlushUsage(context.Background()); err != nil {
+		slog.Error("Failed to save usage stats", "error", err)
+	}
 	bot.Close()
 	slog.Info("ravenbot stopped gracefully.")
 }
+
+// openDatabase opens the configured database (PostgreSQL if DATABASE_URL is
+// set, SQLite otherwise) with the encryption key, if any.
+func openDatabase(cfg *config.Config) (*db.DB, error) {
+	target := cfg.DBPath
+	if cfg.DatabaseURL != "" {
+		target = cfg.DatabaseURL
+	}
+	key, err := crypt.LoadKey(cfg.EncryptionKey, cfg.EncryptionKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encryption key: %w", err)
+	}
+	database, err := db.Open(target)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		c, err := crypt.New(key)
+		if err != nil {
+			_ = database.Close()
+			return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+		}
+		database.SetCipher(c)
+	}
+	return database, nil
+}
+
+// sessionDialector returns the GORM dialector for the ADK session service,
+// sharing the bot's connection pool.
+func sessionDialector(database *db.DB) gorm.Dialector {
+	if database.Driver() == db.DriverPostgres {
+		return postgres.New(postgres.Config{Conn: database.DB})
+	}
+	return &sqlite.Dialector{Conn: database.DB}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/mcpserver"
+	"github.com/raythurman2386/ravenbot/internal/stats"
+)
+
+// serveMCP runs ravenbot as an MCP server, over stdio or, with -http, over
+// Streamable HTTP, and returns the process exit code. Logs go to stderr so
+// they stay out of the stdio protocol stream.
+func serveMCP(args []string) int {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+	addr := fs.String("http", "", `serve Streamable HTTP on this address (e.g. "127.0.0.1:8765") instead of stdio`)
+	sessionID := fs.String("session", mcpserver.DefaultSession, "session that reminders and memories belong to")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	// Missions can run tools that reach the network and the filesystem, so
+	// only loopback clients may connect without a token.
+	token, err := config.SecretEnv("RAVENBOT_MCP_TOKEN")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mcp: %v\n", err)
+		return 2
+	}
+	if *addr != "" && token == "" && !mcpserver.IsLoopback(*addr) {
+		fmt.Fprintln(os.Stderr, "mcp: set RAVENBOT_MCP_TOKEN to serve on a non-loopback address")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	database, err := openDatabase(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open database: %v\n", err)
+		return 1
+	}
+	defer func() { _ = database.Close() }()
+
+	bot, err := agent.NewAgent(ctx, cfg, database, stats.New(), sessionDialector(database))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create agent: %v\n", err)
+		return 1
+	}
+	defer bot.Close()
+
+	server := mcpserver.New(bot, database, mcpserver.Options{SessionID: *sessionID, Location: cfg.Location()})
+	if *addr == "" {
+		err = server.Run(ctx, &officialmcp.StdioTransport{})
+	} else {
+		err = serveMCPHTTP(ctx, server, *addr, token)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "mcp: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// serveMCPHTTP serves server on addr until ctx ends. With a token, requests
+// must carry it as a bearer token; without one, only loopback clients are
+// accepted.
+func serveMCPHTTP(ctx context.Context, server *officialmcp.Server, addr, token string) error {
+	srv := &http.Server{Addr: addr, Handler: mcpserver.HTTPHandler(server, token), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	slog.Info("Serving MCP over HTTP", "addr", addr, "auth", token != "")
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raythurman2386/cronlib"
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/handler"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+	"github.com/raythurman2386/ravenbot/internal/schedule"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+)
+
+// app holds the parts of the process that are rebuilt when the configuration
+// is reloaded via /reload or SIGHUP. The agent, its sessions and the database
+// are kept as they are.
+type app struct {
+	ctx       context.Context
+	scheduler *cronlib.Cron
+	bot       *agent.Agent
+	h         *handler.Handler
+
+	mu            sync.Mutex
+	cfg           *config.Config
+	stopJobs      context.CancelFunc
+	jobs          sync.WaitGroup
+	stopListeners context.CancelFunc
+}
+
+// scheduleJobs replaces all config-driven jobs with those of cfg. Jobs run
+// on their own schedule loops rather than cronlib so their schedules follow
+// each job's timezone across DST changes.
+func (a *app) scheduleJobs(cfg *config.Config) int {
+	if a.stopJobs != nil {
+		a.stopJobs()
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.stopJobs = cancel
+
+	scheduled := 0
+	for _, job := range cfg.Jobs {
+		if job.Schedule == "" && len(job.RunAfter) > 0 {
+			slog.Info("Job runs after other jobs", "name", job.Name, "runAfter", job.RunAfter)
+			continue
+		}
+		s, err := schedule.Parse(job.Schedule, cfg.JobLocation(job))
+		if err != nil {
+			slog.Error("Failed to schedule job", "name", job.Name, "error", err)
+			continue
+		}
+		a.jobs.Go(func() {
+			schedule.Loop(ctx, s, job.JitterDuration(), func(ctx context.Context) { a.h.RunJob(ctx, job) })
+		})
+		scheduled++
+		slog.Info("Scheduled job", "name", job.Name, "schedule", job.Schedule, "timezone", s.Location().String(), "next", s.Next(time.Now()).Format(time.RFC3339))
+	}
+	return scheduled
+}
+
+// startNotifiers creates the notifiers for cfg, hands them to the handler
+// and starts their listeners. Any previous listeners are stopped first.
+func (a *app) startNotifiers(cfg *config.Config) {
+	if a.stopListeners != nil {
+		a.stopListeners()
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.stopListeners = cancel
+
+	var notifiers []notifier.Notifier
+
+	if cfg.TelegramBotToken != "" && cfg.TelegramChatID != 0 {
+		tn, err := notifier.NewTelegramNotifier(cfg.TelegramBotToken, cfg.TelegramChatID)
+		if err != nil {
+			slog.Warn("Failed to setup Telegram notifier", "error", err)
+		} else {
+			notifiers = append(notifiers, tn)
+		}
+	}
+
+	if cfg.DiscordBotToken != "" && cfg.DiscordChannelID != "" {
+		dn, err := notifier.NewDiscordNotifier(cfg.DiscordBotToken, cfg.DiscordChannelID)
+		if err != nil {
+			slog.Warn("Failed to setup Discord notifier", "error", err)
+		} else {
+			notifiers = append(notifiers, dn)
+		}
+	}
+
+	a.h.SetNotifiers(notifiers)
+
+	h := a.h
+	for _, n := range notifiers {
+		switch botNotifier := n.(type) {
+		case *notifier.TelegramNotifier:
+			go botNotifier.StartListener(ctx, func(chatID int64, text string, attachments []notifier.Attachment) {
+				sessionID := fmt.Sprintf("telegram-%d", chatID)
+				msgCtx := notifier.WithAttachments(notifier.WithChannel(a.ctx, strconv.FormatInt(chatID, 10)), attachments)
+				// Handled concurrently, as on Discord, so an answer to an MCP
+				// server's question reaches the tool call waiting for it.
+				go h.HandleMessage(msgCtx, sessionID, text, botNotifier, func(reply string) {
+					if err := botNotifier.Send(a.ctx, reply); err != nil {
+						slog.Error("Failed to send Telegram reply", "error", err)
+					}
+				})
+			})
+		case *notifier.DiscordNotifier:
+			go botNotifier.StartListener(ctx, func(channelID string, text string, attachments []notifier.Attachment) {
+				sessionID := fmt.Sprintf("discord-%s", channelID)
+				msgCtx := notifier.WithAttachments(notifier.WithChannel(a.ctx, channelID), attachments)
+				h.HandleMessage(msgCtx, sessionID, text, botNotifier, func(reply string) {
+					if err := botNotifier.Send(a.ctx, reply); err != nil {
+						slog.Error("Failed to send Discord reply", "error", err)
+					}
+				})
+			})
+		}
+	}
+}
+
+// applyPoliteness sets how the web tools pace their requests and whether
+// they obey robots.txt.
+func applyPoliteness(cfg *config.Config) {
+	ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+	p := cfg.Politeness
+	policy := tools.PolitenessPolicy{Delay: ms(p.DelayMs), IgnoreRobots: p.IgnoreRobots, Hosts: make(map[string]tools.HostPolicy, len(p.Hosts))}
+	for host, h := range p.Hosts {
+		policy.Hosts[strings.ToLower(host)] = tools.HostPolicy{Delay: ms(h.DelayMs), IgnoreRobots: h.IgnoreRobots}
+	}
+	tools.SetPolitenessPolicy(policy)
+}
+
+// applyHostAccess sets the hosts exempt from, and refused by, the SSRF
+// protection of the web tools and HTTP MCP servers.
+func applyHostAccess(cfg *config.Config) {
+	tools.SetHostAccess(tools.HostAccess{Allow: cfg.HostAccess.Allow, Deny: cfg.HostAccess.Deny})
+}
+
+// applyHTTPCache sets up the web tools' response cache in the data
+// directory; a cache that can't be opened is logged and left off.
+func applyHTTPCache(cfg *config.Config) {
+	maxBytes := int64(tools.DefaultHTTPCacheBytes)
+	if mb := cfg.HTTPCache.MaxMB; mb != 0 {
+		maxBytes = int64(mb) << 20
+	}
+	if err := tools.SetHTTPCache(filepath.Join(cfg.DataDir, "httpcache"), maxBytes); err != nil {
+		slog.Error("Failed to open HTTP cache", "error", err)
+	}
+}
+
+// configWatchInterval is how often the config files are checked for changes.
+const configWatchInterval = 2 * time.Second
+
+// watchConfig reloads the configuration whenever its files change, until
+// ctx is done. A config that fails to load or validate is rejected and the
+// running one kept.
+func (a *app) watchConfig(ctx context.Context) {
+	files := func() []string {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return a.cfg.Files()
+	}
+	config.Watch(ctx, files, configWatchInterval, func() {
+		slog.Info("Config files changed, reloading")
+		if _, err := a.reload(ctx); err != nil {
+			slog.Error("Rejected the changed configuration, keeping the running one", "error", err)
+		}
+	})
+}
+
+// reload re-reads the configuration and applies everything that can change
+// at runtime. On error the running configuration is left untouched.
+func (a *app) reload(_ context.Context) (string, error) {
+	next, err := config.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	restart := a.cfg.RestartRequired(next)
+	if len(restart) > 0 {
+		slog.Warn("Some config changes require a restart to take effect", "settings", restart)
+	}
+
+	notifiersChanged := a.cfg.TelegramBotToken != next.TelegramBotToken ||
+		a.cfg.TelegramChatID != next.TelegramChatID ||
+		a.cfg.DiscordBotToken != next.DiscordBotToken ||
+		a.cfg.DiscordChannelID != next.DiscordChannelID
+
+	a.bot.UpdateConfig(next)
+	a.h.UpdateConfig(next)
+	applyPoliteness(next)
+	applyHTTPCache(next)
+	applyHostAccess(next)
+	jobs := a.scheduleJobs(next)
+	if notifiersChanged {
+		a.startNotifiers(next)
+	}
+	a.cfg = next
+
+	slog.Info("Configuration reloaded", "jobs", jobs, "notifiersRestarted", notifiersChanged)
+
+	summary := fmt.Sprintf("Prompts updated, %d job(s) scheduled.", jobs)
+	if notifiersChanged {
+		summary += " Notifiers restarted."
+	}
+	if len(restart) > 0 {
+		summary += fmt.Sprintf(" Restart required for: %s.", strings.Join(restart, ", "))
+	}
+	return summary, nil
+}
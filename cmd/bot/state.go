@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+)
+
+// runCommand runs a maintenance subcommand against the configured database
+// and returns the process exit code.
+func runCommand(name string, args []string) int {
+	var run func(context.Context, *db.DB, []string) error
+	switch name {
+	case "export-state":
+		run = exportState
+	case "import-state":
+		run = importState
+	case "mcp":
+		return serveMCP(args)
+	case "config":
+		return configCommand(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\nusage: ravenbot [flags] [export-state [-o file] | import-state file | mcp [-http addr] [-session id] | config validate [file] | config schema]\n", name)
+		return 2
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	database, err := openDatabase(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open database: %v\n", err)
+		return 1
+	}
+	defer func() { _ = database.Close() }()
+
+	if err := run(context.Background(), database, args); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+// exportState writes the state archive as JSON to -o (stdout by default),
+// gzipped if the file name ends in ".gz".
+func exportState(ctx context.Context, database *db.DB, args []string) error {
+	fs := flag.NewFlagSet("export-state", flag.ContinueOnError)
+	out := fs.String("o", "-", `output file ("-" for stdout); a ".gz" suffix compresses it`)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	state, err := database.ExportState(ctx)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.OpenFile(*out, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create archive: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	if err := writeState(w, state, strings.HasSuffix(*out, ".gz")); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d briefings, %d reminders, %d memories, %d subscriptions, %d tasks, %d session summaries\n",
+		len(state.Briefings), len(state.Reminders), len(state.Memories), len(state.Subscriptions), len(state.Tasks), len(state.Summaries))
+	return nil
+}
+
+// importState reads a state archive (plain or gzipped JSON; "-" for stdin)
+// into the database.
+func importState(ctx context.Context, database *db.DB, args []string) error {
+	fs := flag.NewFlagSet("import-state", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: ravenbot import-state <file>")
+	}
+
+	var r io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open archive: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+	state, err := readState(r)
+	if err != nil {
+		return err
+	}
+
+	res, err := database.ImportState(ctx, state)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "imported %d briefings, %d reminders, %d memories, %d subscriptions, %d tasks, %d session summaries (%d already present)\n",
+		res.Briefings, res.Reminders, res.Memories, res.Subscriptions, res.Tasks, res.Summaries, res.Skipped)
+	return nil
+}
+
+func writeState(w io.Writer, state *db.State, compress bool) error {
+	if compress {
+		gz := gzip.NewWriter(w)
+		if err := writeState(gz, state, false); err != nil {
+			return err
+		}
+		return gz.Close()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(state); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// readState decodes an archive, detecting gzip by its magic bytes.
+func readState(r io.Reader) (*db.State, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	} else {
+		r = br
+	}
+	var state db.State
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if state.Version == 0 {
+		return nil, fmt.Errorf("not a ravenbot state archive")
+	}
+	return &state, nil
+}
@@ -5,19 +5,17 @@ import (
 	"fmt"
 	"iter"
 	"log/slog"
-	"os"
-	"os/exec"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/raythurman2386/ravenbot/internal/backend"
 	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/crypt"
 	raven "github.com/raythurman2386/ravenbot/internal/db"
 	"github.com/raythurman2386/ravenbot/internal/stats"
 	"github.com/raythurman2386/ravenbot/internal/tools"
 
-	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
 	"google.golang.org/adk/model"
@@ -26,15 +24,24 @@ import (
 	adkdb "google.golang.org/adk/session/database"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
-	"google.golang.org/adk/tool/mcptoolset"
 	"google.golang.org/genai"
 	"gorm.io/gorm"
 )
 
 const AppName = "ravenbot"
 
+// missionSessionPrefix marks the throwaway sessions created by RunMission,
+// which are excluded from per-session usage stats.
+const missionSessionPrefix = "mission-"
+
+// maxInstructionMemories caps how many /remember facts are injected into the
+// system prompt per turn.
+const maxInstructionMemories = 50
+
 type Agent struct {
+	// cfg is swapped by UpdateConfig on /reload; read it through config().
 	cfg   *config.Config
+	cfgMu sync.RWMutex
 	db    *raven.DB
 	stats *stats.Stats
 
@@ -51,15 +58,44 @@ type Agent struct {
 	researchAssistant agent.Agent
 	systemManager     agent.Agent
 	julesAgent        agent.Agent
+
+	// modelByAgent maps agent names (event authors) to the model they run
+	// on, for per-session usage stats.
+	modelByAgent map[string]string
+
+	// missionsActive counts running missions for Bot.MaxConcurrentMissions;
+	// missionDone is closed and replaced whenever one finishes.
+	missionsActive int
+	missionDone    chan struct{}
+	missionMu      sync.Mutex
+
+	// Tool catalog for /tools
+	builtinTools []ToolGroup
+
+	// browser is the headless Chrome of screenshot_page, started on first
+	// use.
+	browser *tools.BrowserManager
+
+	// The running MCP servers, by name, guarded by mcpMu as /mcp adds and
+	// removes them. mcpOverrides holds those changes: the configuration of
+	// each server added, nil for each configured one removed.
+	mcpMu        sync.RWMutex
+	mcpCtx       context.Context // servers run until it ends
+	mcpToolsets  map[string]tool.Toolset
+	mcpOwners    map[string]string
+	mcpServers   map[string]*mcpServer // for prompts and health
+	mcpOverrides map[string]*config.MCPServerConfig
+	onMCPUpdate  func(server, uri string) // see OnMCPResourceUpdated
 }
 
 func NewAgent(ctx context.Context, cfg *config.Config, database *raven.DB, botStats *stats.Stats, dialector gorm.Dialector) (*Agent, error) {
 	slog.Info("Initializing production agent", "backend", cfg.AIBackend)
 
 	a := &Agent{
-		cfg:   cfg,
-		db:    database,
-		stats: botStats,
+		cfg:     cfg,
+		db:      database,
+		stats:   botStats,
+		browser: tools.NewBrowserManager(cfg.Browser.RemoteURL, cfg.Browser.ExecPath, cfg.Browser.NoSandbox),
 	}
 
 	// 1. Initialize ADK Models (Flash & Pro) via configured backend
@@ -74,8 +110,12 @@ func NewAgent(ctx context.Context, cfg *config.Config, database *raven.DB, botSt
 		return nil, fmt.Errorf("failed to create Pro model: %w", err)
 	}
 
-	// 2. Initialize Session Service (SQLite Persistent via GORM Dialector)
-	sessionService, err := adkdb.NewSessionService(dialector)
+	// 2. Initialize Session Service (SQLite Persistent via GORM Dialector).
+	// Event content and actions are encrypted when the database has a key;
+	// its queries are timed like the bot's own.
+	sessionService, err := adkdb.NewSessionService(dialector,
+		crypt.GormOption(database.Cipher(), "events", "content", "actions", "custom_metadata"),
+		database.GormOption())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ADK session service: %w", err)
 	}
@@ -83,74 +123,39 @@ func NewAgent(ctx context.Context, cfg *config.Config, database *raven.DB, botSt
 	if err := adkdb.AutoMigrate(sessionService); err != nil {
 		return nil, fmt.Errorf("failed to auto-migrate session schema: %w", err)
 	}
+	if err := database.IndexSessionEvents(ctx); err != nil {
+		return nil, err
+	}
 	a.sessionService = sessionService
 
-	// 3. Initialize MCP Servers — keyed by server name for targeted assignment
-	mcpToolsetsByName := make(map[string]tool.Toolset)
+	// 3. Initialize MCP Servers — the configured ones, plus those added
+	// (less those removed) with /mcp. Each is given to the sub-agent that
+	// owns it through that agent's ownedToolsets.
+	a.mcpCtx = ctx
+	a.mcpServers = make(map[string]*mcpServer)
+	a.mcpToolsets = make(map[string]tool.Toolset)
+	a.mcpOwners = make(map[string]string)
+	a.loadMCPOverrides(ctx)
 	var mcpWG sync.WaitGroup
-	var mcpMu sync.Mutex
-
-	for name, serverCfg := range cfg.MCPServers {
+	for name, serverCfg := range a.mcpConfigs() {
 		mcpWG.Add(1)
 		go func(name string, serverCfg config.MCPServerConfig) {
 			defer mcpWG.Done()
 
 			slog.Info("Initializing official MCP Toolset", "name", name)
-			var transport officialmcp.Transport
-			if strings.HasPrefix(serverCfg.Command, "http://") || strings.HasPrefix(serverCfg.Command, "https://") {
-				transport = &officialmcp.SSEClientTransport{
-					Endpoint:   serverCfg.Command,
-					HTTPClient: tools.NewSafeClient(0),
-				}
-			} else {
-				cmd := exec.Command(serverCfg.Command, serverCfg.Args...)
-				if len(serverCfg.Env) > 0 {
-					cmd.Env = os.Environ()
-					for k, v := range serverCfg.Env {
-						cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, os.ExpandEnv(v)))
-					}
-				}
-				transport = &officialmcp.CommandTransport{Command: cmd}
-			}
-
-			ts, err := mcptoolset.New(mcptoolset.Config{
-				Transport: transport,
-			})
+			server, err := a.startMCPServer(name, serverCfg)
 			if err != nil {
-				slog.Error("Failed to create MCP toolset", "name", name, "error", err)
+				slog.Error("Failed to start MCP server", "name", name, "error", err)
 				return
 			}
-
-			mcpMu.Lock()
-			mcpToolsetsByName[name] = ts
-			mcpMu.Unlock()
+			a.register(server, mcpOwner(name, serverCfg))
 		}(name, serverCfg)
 	}
 	mcpWG.Wait()
 
-	// Build targeted MCP toolset slices per sub-agent.
-	// ResearchAssistant: weather, memory, filesystem, sequential-thinking
-	// SystemManager:     sysmetrics
-	// Jules:             github
-	researchMCPNames := []string{"weather", "memory", "filesystem", "sequential-thinking"}
-	systemMCPNames := []string{"sysmetrics"}
-	julesMCPNames := []string{"github"}
-
-	collectToolsets := func(names []string) []tool.Toolset {
-		var ts []tool.Toolset
-		for _, n := range names {
-			if t, ok := mcpToolsetsByName[n]; ok {
-				ts = append(ts, t)
-			} else {
-				slog.Warn("MCP toolset not available for agent assignment", "name", n)
-			}
-		}
-		return ts
-	}
-
-	researchToolsets := collectToolsets(researchMCPNames)
-	systemToolsets := collectToolsets(systemMCPNames)
-	julesToolsets := collectToolsets(julesMCPNames)
+	researchToolsets := []tool.Toolset{ownedToolsets{a, "ResearchAssistant"}}
+	systemToolsets := []tool.Toolset{ownedToolsets{a, "SystemManager"}}
+	julesToolsets := []tool.Toolset{ownedToolsets{a, "Jules"}}
 
 	// 5. Create Sub-Agents
 
@@ -159,37 +164,41 @@ func NewAgent(ctx context.Context, cfg *config.Config, database *raven.DB, botSt
 		Name:        "SystemManager",
 		Model:       a.flashLLM,
 		Description: "A specialized assistant for system diagnostics and health checks.",
-		Instruction: cfg.Bot.SystemManagerPrompt,
-		Toolsets:    systemToolsets,
+		InstructionProvider: func(agent.ReadonlyContext) (string, error) {
+			return a.config().Bot.SystemManagerPrompt, nil
+		},
+		Toolsets:             systemToolsets,
+		BeforeModelCallbacks: []llmagent.BeforeModelCallback{a.showToolImages},
+		AfterModelCallbacks:  []llmagent.AfterModelCallback{dispatchMCPCalls},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SystemManager: %w", err)
 	}
 	a.systemManager = systemManagerAgent
 
-	// JulesTask Tool
-	type JulesTaskArgs struct {
-		Repo string `json:"repo" jsonschema:"The repository in 'owner/repo' format."`
-		Task string `json:"task" jsonschema:"The coding task description."`
+	julesTaskTool, err := a.newJulesTaskTool()
+	if err != nil {
+		return nil, err
 	}
-	julesTaskTool, err := functiontool.New(functiontool.Config{
-		Name:        "JulesTask",
-		Description: "Delegates a coding task to the external Jules service. REQUIRED for any code modification, refactoring, or repository creation.",
-	}, func(ctx tool.Context, args JulesTaskArgs) (string, error) {
-		return tools.DelegateToJules(ctx, cfg.JulesAPIKey, args.Repo, args.Task)
-	})
+
+	githubTools, err := a.newGitHubTools()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create JulesTask tool: %w", err)
+		return nil, err
 	}
+	julesTools := append([]tool.Tool{julesTaskTool}, githubTools...)
 
 	// Create Jules Sub-Agent
 	julesAgent, err := llmagent.New(llmagent.Config{
 		Name:        "Jules",
 		Model:       a.proLLM,
 		Description: "A specialized AI software engineer for coding tasks and GitHub operations.",
-		Instruction: cfg.Bot.JulesPrompt,
-		Tools:       []tool.Tool{julesTaskTool},
-		Toolsets:    julesToolsets,
+		InstructionProvider: func(agent.ReadonlyContext) (string, error) {
+			return a.config().Bot.JulesPrompt, nil
+		},
+		Tools:                julesTools,
+		Toolsets:             julesToolsets,
+		BeforeModelCallbacks: []llmagent.BeforeModelCallback{a.showToolImages},
+		AfterModelCallbacks:  []llmagent.AfterModelCallback{dispatchMCPCalls},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Jules agent: %w", err)
@@ -197,31 +206,97 @@ func NewAgent(ctx context.Context, cfg *config.Config, database *raven.DB, botSt
 	a.julesAgent = julesAgent
 
 	// Create Research Assistant Sub-Agent
-	// Use a custom web_search function tool that wraps a standalone Gemini API
-	// call with GoogleSearch grounding. This avoids the Gemini API restriction
-	// that prevents mixing grounding tools with function-calling tools (which
-	// the ADK injects via transfer_to_agent and MCP toolsets).
+	// Use a custom web_search function tool over the configured search
+	// providers. The default wraps a standalone Gemini API call with
+	// GoogleSearch grounding. This avoids the Gemini API restriction that
+	// prevents mixing grounding tools with function-calling tools (which the
+	// ADK injects via transfer_to_agent and MCP toolsets).
+	search := newSearch(cfg)
 	type WebSearchArgs struct {
 		Query string `json:"query" jsonschema:"The search query to look up on the web."`
 	}
 	webSearchTool, err := functiontool.New(functiontool.Config{
 		Name:        "web_search",
-		Description: "Search the web using Google Search to find current, up-to-date information. Use this for any question requiring recent data, news, documentation, or facts you are unsure about.",
+		Description: "Search the web to find current, up-to-date information. Use this for any question requiring recent data, news, documentation, or facts you are unsure about.",
 	}, func(ctx tool.Context, args WebSearchArgs) (string, error) {
-		return tools.WebSearch(ctx, cfg.GeminiAPIKey, cfg.GeminiFlashModel, args.Query)
+		return search.Search(ctx, args.Query)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create web_search tool: %w", err)
 	}
 
-	researchTools := []tool.Tool{webSearchTool}
+	historyTool, err := a.newHistoryTool()
+	if err != nil {
+		return nil, err
+	}
+
+	arxivTools, err := newArxivTools()
+	if err != nil {
+		return nil, err
+	}
+
+	redditTool, err := a.newRedditTool()
+	if err != nil {
+		return nil, err
+	}
+
+	weatherTool, err := a.newWeatherTool()
+	if err != nil {
+		return nil, err
+	}
+
+	wikipediaTool, err := newWikipediaTool()
+	if err != nil {
+		return nil, err
+	}
+
+	fetchPageTool, err := newFetchPageTool()
+	if err != nil {
+		return nil, err
+	}
+
+	archiveTool, err := newArchiveFetchTool()
+	if err != nil {
+		return nil, err
+	}
+
+	pdfTool, err := newPDFTool()
+	if err != nil {
+		return nil, err
+	}
+
+	crawlTool, err := newCrawlTool()
+	if err != nil {
+		return nil, err
+	}
+
+	screenshotTool, err := a.newScreenshotTool()
+	if err != nil {
+		return nil, err
+	}
+
+	// calculate and run_code are shared with the root agents.
+	calculateTool, err := a.newCalculateTool()
+	if err != nil {
+		return nil, err
+	}
+	runCodeTool, err := a.newRunCodeTool()
+	if err != nil {
+		return nil, err
+	}
+
+	researchTools := append([]tool.Tool{historyTool, webSearchTool, wikipediaTool, fetchPageTool, archiveTool, pdfTool, crawlTool, screenshotTool, calculateTool, runCodeTool, redditTool, weatherTool}, arxivTools...)
 	researchAssistant, err := llmagent.New(llmagent.Config{
 		Name:        "ResearchAssistant",
 		Model:       a.flashLLM,
 		Description: "A specialized assistant for technical research and web searches.",
-		Instruction: cfg.Bot.ResearchSystemPrompt + "\n\nUse the web_search tool for all web searches to find up-to-date information.",
-		Tools:       researchTools,
-		Toolsets:    researchToolsets,
+		InstructionProvider: func(agent.ReadonlyContext) (string, error) {
+			return a.config().Bot.ResearchSystemPrompt + "\n\nUse the search_history tool to check earlier briefings first, the web_search tool for all web searches to find up-to-date information, wikipedia for background facts, fetch_page to read the articles and pages you find, archive_fetch for pages that are gone or paywalled, fetch_pdf to read sources that are PDFs, crawl_site to read documentation sites, screenshot_page to see dashboards and pages, calculate for every figure, unit conversion and date difference you state, run_code for data analysis, arxiv_search and arxiv_paper to find and cite research papers, reddit_top for community discussion, and get_weather for the weather.", nil
+		},
+		Tools:                researchTools,
+		Toolsets:             researchToolsets,
+		BeforeModelCallbacks: []llmagent.BeforeModelCallback{a.showToolImages},
+		AfterModelCallbacks:  []llmagent.AfterModelCallback{dispatchMCPCalls},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ResearchAssistant: %w", err)
@@ -231,29 +306,74 @@ func NewAgent(ctx context.Context, cfg *config.Config, database *raven.DB, botSt
 	// 6. Instruction provider logic
 	instructionProvider := func(ctx agent.ReadonlyContext) (string, error) {
 		var summary string
+		var memories []raven.Memory
+		var prompt *raven.SessionPrompt
 		var err error
 		if a.db != nil {
+			prompt, err = a.db.GetSessionPrompt(ctx, ctx.SessionID())
+			if err != nil {
+				slog.Error("Failed to fetch session prompt from DB", "sessionID", ctx.SessionID(), "error", err)
+			}
 			summary, err = a.db.GetSessionSummary(ctx, ctx.SessionID())
 			if err != nil {
 				slog.Error("Failed to fetch session summary from DB", "sessionID", ctx.SessionID(), "error", err)
 			}
+			memories, err = a.db.SearchMemories(ctx, ctx.SessionID(), "", maxInstructionMemories)
+			if err != nil {
+				slog.Error("Failed to fetch memories from DB", "sessionID", ctx.SessionID(), "error", err)
+			}
 		}
 
+		// A prompt set with /prompt set replaces the configured one or
+		// follows it.
+		instruction := a.config().Bot.SystemPrompt
+		switch {
+		case prompt == nil:
+		case prompt.Mode == raven.PromptReplace:
+			instruction = prompt.Prompt
+		default:
+			instruction = fmt.Sprintf("%s\n\n### INSTRUCTIONS FOR THIS CONVERSATION:\n%s", instruction, prompt.Prompt)
+		}
+		if len(memories) > 0 {
+			var sb strings.Builder
+			for _, m := range memories {
+				sb.WriteString("- " + m.Content + "\n")
+			}
+			instruction = fmt.Sprintf("%s\n\n### FACTS THE USER ASKED YOU TO REMEMBER:\n%s", instruction, strings.TrimRight(sb.String(), "\n"))
+		}
 		if summary != "" {
-			return fmt.Sprintf("%s\n\n### CONTEXT SUMMARY OF PREVIOUS CONVERSATION:\n%s", a.cfg.Bot.SystemPrompt, summary), nil
+			instruction = fmt.Sprintf("%s\n\n### CONTEXT SUMMARY OF PREVIOUS CONVERSATION:\n%s", instruction, summary)
 		}
-		return a.cfg.Bot.SystemPrompt, nil
+		return instruction, nil
 	}
 
 	// 7. Create Root ADK LLMAgents
 	allSubAgents := []agent.Agent{researchAssistant, systemManagerAgent, julesAgent}
 
+	rootTools, err := a.newTodoTools()
+	if err != nil {
+		return nil, err
+	}
+	workspaceTools, err := a.newWorkspaceTools()
+	if err != nil {
+		return nil, err
+	}
+	rootTools = append(rootTools, workspaceTools...)
+	rootTools = append(rootTools, calculateTool, runCodeTool)
+
+	a.builtinTools = []ToolGroup{
+		{Name: "Assistant", Owner: "ravenbot", Available: true, Tools: toolInfos(rootTools)},
+		searchGroup(ToolGroup{Name: "Research", Owner: "ResearchAssistant", Tools: toolInfos(researchTools)}, cfg),
+		requireKey(ToolGroup{Name: "Jules", Owner: "Jules", Tools: toolInfos([]tool.Tool{julesTaskTool})}, "JULES_API_KEY", cfg.JulesAPIKey),
+		requireKey(ToolGroup{Name: "GitHub", Owner: "Jules", Tools: toolInfos(githubTools)}, "GITHUB_PERSONAL_ACCESS_TOKEN", cfg.GitHubToken),
+	}
+
 	flashAgent, err := llmagent.New(llmagent.Config{
 		Name:                "ravenbot-flash",
 		Model:               a.flashLLM,
 		Description:         "RavenBot Flash Agent",
 		InstructionProvider: instructionProvider,
-		Tools:               nil,
+		Tools:               rootTools,
 		SubAgents:           allSubAgents,
 	})
 	if err != nil {
@@ -265,18 +385,29 @@ func NewAgent(ctx context.Context, cfg *config.Config, database *raven.DB, botSt
 		Model:               a.proLLM,
 		Description:         "RavenBot Pro Agent",
 		InstructionProvider: instructionProvider,
-		Tools:               nil,
+		Tools:               rootTools,
 		SubAgents:           allSubAgents,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create pro agent: %w", err)
 	}
 
-	// 8. Create ADK Runners
+	a.modelByAgent = map[string]string{
+		flashAgent.Name():         a.flashLLM.Name(),
+		researchAssistant.Name():  a.flashLLM.Name(),
+		systemManagerAgent.Name(): a.flashLLM.Name(),
+		proAgent.Name():           a.proLLM.Name(),
+		julesAgent.Name():         a.proLLM.Name(),
+	}
+
+	// 8. Create ADK Runners. Chat turns load only the recent events.
+	chatSessions := &recentEvents{Service: sessionService, limit: func(ctx context.Context) int {
+		return a.botConfig(ctx).SessionEvents()
+	}}
 	flashRunner, err := runner.New(runner.Config{
 		AppName:        AppName,
 		Agent:          flashAgent,
-		SessionService: sessionService,
+		SessionService: chatSessions,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Flash runner: %w", err)
@@ -286,7 +417,7 @@ func NewAgent(ctx context.Context, cfg *config.Config, database *raven.DB, botSt
 	proRunner, err := runner.New(runner.Config{
 		AppName:        AppName,
 		Agent:          proAgent,
-		SessionService: sessionService,
+		SessionService: chatSessions,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Pro runner: %w", err)
@@ -296,9 +427,52 @@ func NewAgent(ctx context.Context, cfg *config.Config, database *raven.DB, botSt
 	return a, nil
 }
 
+// modelFor returns the model name behind an event author, or "unknown".
+func (a *Agent) modelFor(author string) string {
+	if m, ok := a.modelByAgent[author]; ok {
+		return m
+	}
+	return "unknown"
+}
+
+// config returns the live configuration.
+func (a *Agent) config() *config.Config {
+	a.cfgMu.RLock()
+	defer a.cfgMu.RUnlock()
+	return a.cfg
+}
+
+// botConfig returns the bot settings with the runtime overrides stored by
+// /set applied on top of the configuration.
+func (a *Agent) botConfig(ctx context.Context) config.BotConfig {
+	b := a.config().Bot
+	if a.db == nil {
+		return b
+	}
+	values, err := a.db.Settings(ctx)
+	if err != nil {
+		slog.Warn("Failed to load runtime settings, using config values", "error", err)
+		return b
+	}
+	return b.WithSettings(values)
+}
+
+// UpdateConfig swaps in a reloaded configuration. Prompts, token limits and
+// API keys take effect on the next turn; models and MCP servers are fixed at
+// startup (bar /mcp, see AddMCPServer) and keep running unchanged, so
+// existing sessions are preserved.
+func (a *Agent) UpdateConfig(cfg *config.Config) {
+	a.cfgMu.Lock()
+	a.cfg = cfg
+	a.cfgMu.Unlock()
+}
+
+// Close stops the browser, if started. MCP servers stop with the context
+// NewAgent was given.
 func (a *Agent) Close() {
-	// No-op: retained for interface compatibility.
-	// Browser and MCP cleanup happens via context cancellation.
+	if a.browser != nil {
+		a.browser.Close()
+	}
 }
 
 func (a *Agent) ClearSession(sessionID string) {
@@ -358,7 +532,7 @@ Existing Summary:
 %s
 
 Conversation History:
-%s`, a.cfg.Bot.SummaryPrompt, existingSummary, history)
+%s`, a.config().Bot.SummaryPrompt, existingSummary, history)
 
 	respIter := a.flashLLM.GenerateContent(ctx, &model.LLMRequest{
 		Contents: []*genai.Content{{
@@ -397,7 +571,7 @@ Conversation History:
 }
 
 func (a *Agent) classifyPrompt(ctx context.Context, message string) string {
-	prompt := fmt.Sprintf(a.cfg.Bot.RoutingPrompt, message)
+	prompt := fmt.Sprintf(a.config().Bot.RoutingPrompt, message)
 	respIter := a.flashLLM.GenerateContent(ctx, &model.LLMRequest{
 		Contents: []*genai.Content{{
 			Role:  "user",
@@ -425,12 +599,15 @@ func (a *Agent) classifyPrompt(ctx context.Context, message string) string {
 
 func (a *Agent) Chat(ctx context.Context, sessionID, message string) (string, error) {
 	slog.Info("Agent.Chat called", "sessionID", sessionID, "messageLength", len(message))
+	ctx = withMCPDispatch(withToolImages(ctx))
 	userID := sessionID
 
+	// Only check that the session exists; the runner loads its events.
 	_, err := a.sessionService.Get(ctx, &session.GetRequest{
-		AppName:   AppName,
-		UserID:    userID,
-		SessionID: sessionID,
+		AppName:         AppName,
+		UserID:          userID,
+		SessionID:       sessionID,
+		NumRecentEvents: 1,
 	})
 	if err != nil {
 		slog.Info("Session not found, creating new one", "sessionID", sessionID)
@@ -444,18 +621,27 @@ func (a *Agent) Chat(ctx context.Context, sessionID, message string) (string, er
 		}
 	}
 
-	classification := a.classifyPrompt(ctx, message)
+	bot := a.botConfig(ctx)
+	var classification string
+	switch bot.DefaultModel {
+	case config.ModelFlash:
+		classification = "Simple"
+	case config.ModelPro:
+		classification = "Complex"
+	default:
+		classification = a.classifyPrompt(ctx, message)
+	}
 	var activeRunner *runner.Runner
 	var tokenLimit int64
 	if classification == "Simple" {
 		activeRunner = a.flashRunner
-		tokenLimit = a.cfg.Bot.FlashTokenLimit
+		tokenLimit = bot.FlashTokenLimit
 	} else {
 		activeRunner = a.proRunner
-		tokenLimit = a.cfg.Bot.ProTokenLimit
+		tokenLimit = bot.ProTokenLimit
 	}
 
-	slog.Info("Routed request", "classification", classification)
+	slog.Info("Routed request", "classification", classification, "defaultModel", bot.DefaultModel)
 
 	events := activeRunner.Run(ctx, userID, sessionID, &genai.Content{
 		Role:  "user",
@@ -466,10 +652,17 @@ func (a *Agent) Chat(ctx context.Context, sessionID, message string) (string, er
 }
 
 func (a *Agent) RunMission(ctx context.Context, prompt string) (string, error) {
-	missionID := fmt.Sprintf("mission-%d", time.Now().UnixNano())
+	ctx = withMCPDispatch(withToolImages(ctx))
+	release, err := a.acquireMission(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	missionID := fmt.Sprintf("%s%d", missionSessionPrefix, time.Now().UnixNano())
 	userID := "mission-user"
 
-	_, err := a.sessionService.Create(ctx, &session.CreateRequest{
+	_, err = a.sessionService.Create(ctx, &session.CreateRequest{
 		AppName:   AppName,
 		UserID:    userID,
 		SessionID: missionID,
@@ -515,6 +708,48 @@ func (a *Agent) RunMission(ctx context.Context, prompt string) (string, error) {
 	return a.consumeRunnerEvents(ctx, missionID, events, 0)
 }
 
+// acquireMission waits until fewer than Bot.MaxConcurrentMissions missions
+// are running and claims a slot. The limit is re-read each time a mission
+// finishes, so a /set change also applies to queued missions. The returned
+// func releases the slot.
+func (a *Agent) acquireMission(ctx context.Context) (func(), error) {
+	waited := false
+	for {
+		limit := a.botConfig(ctx).MaxConcurrentMissions
+		a.missionMu.Lock()
+		if limit <= 0 || a.missionsActive < limit {
+			a.missionsActive++
+			a.missionMu.Unlock()
+			return a.releaseMission, nil
+		}
+		if a.missionDone == nil {
+			a.missionDone = make(chan struct{})
+		}
+		done := a.missionDone
+		a.missionMu.Unlock()
+
+		if !waited {
+			slog.Info("Mission queued, concurrency limit reached", "limit", limit)
+			waited = true
+		}
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (a *Agent) releaseMission() {
+	a.missionMu.Lock()
+	defer a.missionMu.Unlock()
+	a.missionsActive--
+	if a.missionDone != nil {
+		close(a.missionDone)
+		a.missionDone = nil
+	}
+}
+
 func (a *Agent) consumeRunnerEvents(ctx context.Context, sessionID string, events iter.Seq2[*session.Event, error], tokenLimit int64) (string, error) {
 	var lastText string
 	var maxPromptTokens int64
@@ -549,11 +784,18 @@ func (a *Agent) consumeRunnerEvents(ctx context.Context, sessionID string, event
 
 		// Track token usage from every event
 		if event.UsageMetadata != nil {
+			stats.CountTokens(ctx, int64(event.UsageMetadata.PromptTokenCount), int64(event.UsageMetadata.CandidatesTokenCount))
 			if a.stats != nil {
-				a.stats.RecordTokens(
+				a.stats.RecordModelTokens(a.modelFor(event.Author),
 					int64(event.UsageMetadata.PromptTokenCount),
 					int64(event.UsageMetadata.CandidatesTokenCount),
 				)
+				if !strings.HasPrefix(sessionID, missionSessionPrefix) {
+					a.stats.RecordSessionTokens(sessionID, a.modelFor(event.Author),
+						int64(event.UsageMetadata.PromptTokenCount),
+						int64(event.UsageMetadata.CandidatesTokenCount),
+					)
+				}
 			}
 			if int64(event.UsageMetadata.PromptTokenCount) > maxPromptTokens {
 				maxPromptTokens = int64(event.UsageMetadata.PromptTokenCount)
@@ -567,6 +809,9 @@ func (a *Agent) consumeRunnerEvents(ctx context.Context, sessionID string, event
 				for _, part := range event.Content.Parts {
 					if part.FunctionCall != nil {
 						slog.Info("Model called tool", "name", part.FunctionCall.Name, "args", part.FunctionCall.Args)
+						if a.stats != nil {
+							a.stats.RecordToolCall(part.FunctionCall.Name)
+						}
 					}
 				}
 			}
@@ -587,7 +832,7 @@ func (a *Agent) consumeRunnerEvents(ctx context.Context, sessionID string, event
 	}
 
 	// Check if context compression is needed
-	if tokenLimit > 0 && maxPromptTokens > int64(float64(tokenLimit)*a.cfg.Bot.CompressionThreshold) {
+	if tokenLimit > 0 && maxPromptTokens > int64(float64(tokenLimit)*a.botConfig(ctx).CompressionThreshold) {
 		slog.Info("Context limit threshold exceeded, triggering compression", "maxPromptTokens", maxPromptTokens, "limit", tokenLimit)
 		if err := a.compressSession(ctx, sessionID); err != nil {
 			slog.Error("Failed to compress session", "sessionID", sessionID, "error", err)
@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// maxPaperChars caps the paper text arxiv_paper returns, about 10k tokens.
+const maxPaperChars = 40000
+
+type ArxivSearchArgs struct {
+	Query      string `json:"query" jsonschema:"Words to search for, or an arXiv API query such as 'ti:segmentation AND cat:cs.CV'."`
+	MaxResults int    `json:"max_results,omitempty" jsonschema:"How many papers to list (default 5, at most 20)."`
+}
+
+type ArxivPaperArgs struct {
+	ID       string `json:"id" jsonschema:"The arXiv ID (e.g. 2401.01234) or abs/pdf URL of the paper."`
+	FullText bool   `json:"full_text,omitempty" jsonschema:"Also return the text extracted from the paper's PDF."`
+}
+
+// newArxivTools returns the tools that search arXiv and read papers, so
+// research can cite the papers themselves.
+func newArxivTools() ([]tool.Tool, error) {
+	searchTool, err := functiontool.New(functiontool.Config{
+		Name:        "arxiv_search",
+		Description: "Searches arXiv for research papers, listing their titles, authors, dates, links and abstracts. Use for machine learning, geospatial and other scientific topics, to cite papers rather than blog posts.",
+	}, func(ctx tool.Context, args ArxivSearchArgs) (string, error) {
+		papers, err := tools.SearchArxiv(ctx, args.Query, args.MaxResults)
+		if err != nil {
+			return "", err
+		}
+		if len(papers) == 0 {
+			return fmt.Sprintf("No arXiv papers match %q.", args.Query), nil
+		}
+		parts := make([]string, 0, len(papers))
+		for i, p := range papers {
+			parts = append(parts, fmt.Sprintf("%d. %s", i+1, p.Markdown()))
+		}
+		return strings.Join(parts, "\n"), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create arxiv_search tool: %w", err)
+	}
+
+	paperTool, err := functiontool.New(functiontool.Config{
+		Name:        "arxiv_paper",
+		Description: "Gets an arXiv paper's details and abstract by ID, and with full_text its text from the PDF (first 40,000 characters). Read the full text only when the abstract is not enough.",
+	}, func(ctx tool.Context, args ArxivPaperArgs) (string, error) {
+		paper, err := tools.ArxivPaperByID(ctx, args.ID)
+		if err != nil {
+			return "", err
+		}
+		if !args.FullText {
+			return paper.Markdown(), nil
+		}
+		text, err := tools.ArxivPaperText(ctx, paper.ID, maxPaperChars)
+		if err != nil {
+			return "", err
+		}
+		return paper.Markdown() + "\n---\n\n" + text, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create arxiv_paper tool: %w", err)
+	}
+	return []tool.Tool{searchTool, paperTool}, nil
+}
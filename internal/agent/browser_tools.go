@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type ScreenshotPageArgs struct {
+	URL         string `json:"url" jsonschema:"The page to capture."`
+	FullPage    bool   `json:"full_page,omitempty" jsonschema:"Capture the whole page rather than the first screen."`
+	WaitSeconds int    `json:"wait_seconds,omitempty" jsonschema:"How long to wait after the page loads, for charts and dashboards to draw (default 1, at most 10)."`
+}
+
+// newScreenshotTool returns the tool that captures a page in headless
+// Chrome. The PNG is saved under the reports directory, shown to the model
+// and sent to the user like an MCP tool's image.
+func (a *Agent) newScreenshotTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "screenshot_page",
+		Description: "Captures a screenshot of a web page in a headless browser, which you can then see; it is also sent to the user. Use for dashboards, charts and checking how a page looks, not for reading text.",
+	}, func(ctx tool.Context, args ScreenshotPageArgs) (string, error) {
+		data, err := a.browser.Screenshot(ctx, args.URL, args.FullPage, time.Duration(args.WaitSeconds)*time.Second)
+		if err != nil {
+			return "", err
+		}
+		name := screenshotName(args.URL, time.Now())
+		dir := filepath.Join(a.config().ReportsDir, "screenshots")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create screenshots directory: %w", err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return "", fmt.Errorf("failed to save screenshot: %w", err)
+		}
+		size := fmt.Sprintf("%.1f KB", float64(len(data))/1024)
+		if len(data) > maxToolMediaBytes {
+			return fmt.Sprintf("Screenshot of %s saved to %s (%s), too large to show or send.", args.URL, path, size), nil
+		}
+		if images, ok := ctx.Value(toolImagesKey{}).(*toolImages); ok {
+			images.add("image/png", data)
+		}
+		if attach, ok := ctx.Value(attachmentKey{}).(func(MCPAttachment)); ok {
+			attach(MCPAttachment{Server: "browser", Tool: "screenshot_page", Name: name, MIMEType: "image/png", Data: data})
+			return fmt.Sprintf("Screenshot of %s saved to %s (%s) and sent to the user as an attachment.", args.URL, path, size), nil
+		}
+		return fmt.Sprintf("Screenshot of %s saved to %s (%s).", args.URL, path, size), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create screenshot_page tool: %w", err)
+	}
+	return t, nil
+}
+
+// screenshotName names a screenshot after the page's host and the time.
+func screenshotName(rawURL string, now time.Time) string {
+	host := "page"
+	if u, err := url.Parse(rawURL); err == nil && u.Hostname() != "" {
+		host = strings.NewReplacer(".", "-", ":", "-").Replace(u.Hostname())
+	}
+	return fmt.Sprintf("%s-%s.png", host, now.Format("20060102-150405"))
+}
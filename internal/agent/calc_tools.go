@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type CalculateArgs struct {
+	Expression string `json:"expression" jsonschema:"Arithmetic such as '1200 * (1 + 5%)^3' or '2^128', a conversion such as '26.2 mi to km' or '98.6 F to C', or date arithmetic such as '2026-03-01 + 90 days', '2026-12-25 - today' or 'days between 2026-01-01 and 2026-07-04'."`
+}
+
+// newCalculateTool returns the tool that evaluates expressions exactly, so
+// the figures in answers and reports aren't left to model arithmetic.
+func (a *Agent) newCalculateTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "calculate",
+		Description: "Evaluates a math expression exactly (arbitrarily large numbers, + - * / ^ mod, percentages, factorials, sqrt, log, round, min, max and more), converts units (length, mass, time, data sizes, volume, speed, temperature), or does date arithmetic. Use it for every figure you state instead of working it out yourself; use run_code for anything needing a program.",
+	}, func(ctx tool.Context, args CalculateArgs) (string, error) {
+		res, err := tools.Calculate(args.Expression, time.Now().In(a.config().Location()))
+		if err != nil {
+			return "", fmt.Errorf("can't evaluate %q: %w", args.Expression, err)
+		}
+		return args.Expression + " = " + res, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calculate tool: %w", err)
+	}
+	return t, nil
+}
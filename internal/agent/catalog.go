@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// mcpListTimeout bounds how long /tools waits for a single MCP server to
+// answer tools/list.
+const mcpListTimeout = 10 * time.Second
+
+// ToolInfo describes a single tool the model can call.
+type ToolInfo struct {
+	Name        string
+	Description string
+}
+
+// ToolGroup is a set of tools from one source: a built-in agent or an MCP
+// server. Unavailable groups carry the reason in Status.
+type ToolGroup struct {
+	Name      string
+	Owner     string // agent that can call these tools; empty if unassigned
+	MCP       bool
+	Available bool
+	Status    string
+	Tools     []ToolInfo
+}
+
+// ToolCatalog lists the built-in tools followed by every configured MCP
+// server, querying each running server for its current tool list.
+func (a *Agent) ToolCatalog(ctx context.Context) []ToolGroup {
+	groups := append([]ToolGroup(nil), a.builtinTools...)
+
+	names := slices.Sorted(maps.Keys(a.mcpConfigs()))
+	for _, name := range names {
+		a.mcpMu.RLock()
+		g := ToolGroup{Name: name, Owner: a.mcpOwners[name], MCP: true}
+		ts, ok := a.mcpToolsets[name]
+		a.mcpMu.RUnlock()
+		if !ok {
+			g.Status = "failed to start"
+			groups = append(groups, g)
+			continue
+		}
+
+		listCtx, cancel := context.WithTimeout(ctx, mcpListTimeout)
+		list, err := ts.Tools(catalogContext{listCtx})
+		cancel()
+		if err != nil {
+			g.Status = fmt.Sprintf("unreachable: %v", err)
+			groups = append(groups, g)
+			continue
+		}
+		g.Available = true
+		g.Tools = toolInfos(list)
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+func toolInfos(list []tool.Tool) []ToolInfo {
+	infos := make([]ToolInfo, 0, len(list))
+	for _, t := range list {
+		infos = append(infos, ToolInfo{Name: t.Name(), Description: t.Description()})
+	}
+	return infos
+}
+
+// requireKey marks g unavailable when the API key it depends on is unset.
+func requireKey(g ToolGroup, name, value string) ToolGroup {
+	g.Available = value != ""
+	if !g.Available {
+		g.Status = name + " not set"
+	}
+	return g
+}
+
+// catalogContext is the minimal agent.ReadonlyContext needed to list tools
+// outside of an agent invocation.
+type catalogContext struct {
+	context.Context
+}
+
+var _ agent.ReadonlyContext = catalogContext{}
+
+func (catalogContext) UserContent() *genai.Content          { return nil }
+func (catalogContext) InvocationID() string                 { return "" }
+func (catalogContext) AgentName() string                    { return "" }
+func (catalogContext) ReadonlyState() session.ReadonlyState { return nil }
+func (catalogContext) UserID() string                       { return "" }
+func (catalogContext) AppName() string                      { return AppName }
+func (catalogContext) SessionID() string                    { return "" }
+func (catalogContext) Branch() string                       { return "" }
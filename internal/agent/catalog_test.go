@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type fakeToolset struct {
+	tools []tool.Tool
+	err   error
+}
+
+func (f *fakeToolset) Name() string { return "fake" }
+
+func (f *fakeToolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) { return f.tools, f.err }
+
+func TestToolCatalog(t *testing.T) {
+	type noArgs struct{}
+	ping, err := functiontool.New(functiontool.Config{Name: "ping", Description: "Pings a host."},
+		func(tool.Context, noArgs) (string, error) { return "pong", nil })
+	require.NoError(t, err)
+
+	a := &Agent{
+		cfg: &config.Config{MCPServers: map[string]config.MCPServerConfig{
+			"weather": {}, "broken": {}, "offline": {},
+		}},
+		builtinTools: []ToolGroup{{Name: "Assistant", Available: true}},
+		mcpToolsets: map[string]tool.Toolset{
+			"weather": &fakeToolset{tools: []tool.Tool{ping}},
+			"offline": &fakeToolset{err: errors.New("connection refused")},
+		},
+		mcpOwners: map[string]string{"weather": "ResearchAssistant"},
+	}
+
+	groups := a.ToolCatalog(context.Background())
+	require.Len(t, groups, 4)
+	assert.Equal(t, "Assistant", groups[0].Name)
+
+	// MCP servers are sorted by name
+	assert.Equal(t, "broken", groups[1].Name)
+	assert.False(t, groups[1].Available)
+	assert.Equal(t, "failed to start", groups[1].Status)
+
+	assert.Equal(t, "offline", groups[2].Name)
+	assert.False(t, groups[2].Available)
+	assert.Contains(t, groups[2].Status, "connection refused")
+
+	assert.Equal(t, "weather", groups[3].Name)
+	assert.True(t, groups[3].Available)
+	assert.Equal(t, "ResearchAssistant", groups[3].Owner)
+	assert.Equal(t, []ToolInfo{{Name: "ping", Description: "Pings a host."}}, groups[3].Tools)
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type RunCodeArgs struct {
+	Language string `json:"language" jsonschema:"python or go."`
+	Code     string `json:"code" jsonschema:"The program. Python runs as a script; Go needs package main and a main function. Print the results."`
+}
+
+// newRunCodeTool returns the tool that runs snippets in a sandbox
+// container, so calculations and data wrangling are computed rather than
+// guessed.
+func (a *Agent) newRunCodeTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "run_code",
+		Description: "Runs a short Python or Go program in an isolated sandbox with no network and returns its output. Use for calculations, statistics, date arithmetic and data transformations instead of working them out yourself; only the standard library is available.",
+	}, func(ctx tool.Context, args RunCodeArgs) (string, error) {
+		c := a.config().Sandbox
+		sandbox := tools.Sandbox{
+			Docker:      c.Docker,
+			PythonImage: c.PythonImage,
+			GoImage:     c.GoImage,
+			MemoryMB:    c.MemoryMB,
+			CPUs:        c.CPUs,
+			Timeout:     time.Duration(c.TimeoutSeconds) * time.Second,
+		}
+		res, err := sandbox.Run(ctx, args.Language, args.Code)
+		if err != nil {
+			return "", err
+		}
+		var sb strings.Builder
+		switch {
+		case res.TimedOut:
+			sb.WriteString(fmt.Sprintf("Timed out after %s.\n", res.Duration.Round(time.Millisecond)))
+		default:
+			sb.WriteString(fmt.Sprintf("Exit code %d (%s).\n", res.ExitCode, res.Duration.Round(time.Millisecond)))
+		}
+		if res.Stdout != "" {
+			sb.WriteString("\nstdout:\n```\n" + strings.TrimRight(res.Stdout, "\n") + "\n```\n")
+		}
+		if res.Stderr != "" {
+			sb.WriteString("\nstderr:\n```\n" + strings.TrimRight(res.Stderr, "\n") + "\n```\n")
+		}
+		if res.Stdout == "" && res.Stderr == "" {
+			sb.WriteString("No output.\n")
+		}
+		return sb.String(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run_code tool: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// CompleteArgumentArgs are the arguments of a server's complete tool.
+type CompleteArgumentArgs struct {
+	Ref      string            `json:"ref" jsonschema:"The prompt name or resource template URI the argument belongs to, as listed in the tool description."`
+	Argument string            `json:"argument" jsonschema:"The argument (or URI template variable) to complete."`
+	Value    string            `json:"value,omitempty" jsonschema:"What the value starts with, if anything; an empty value lists the first suggestions."`
+	Context  map[string]string `json:"context,omitempty" jsonschema:"Values already chosen for other arguments, which may narrow the suggestions (e.g. the owner when completing a repository)."`
+}
+
+// templateVar matches the variables of a URI template ({owner},
+// {+path}, {?q,page}).
+var templateVar = regexp.MustCompile(`\{[+#./;?&]?([^}]+)\}`)
+
+// completeTool returns the tool that asks the server to complete an
+// argument of one of its prompts or resource templates
+// (completion/complete), so agents can look up a valid repository or
+// branch name instead of guessing one. It is nil for servers without the
+// completions capability or anything to complete, and until the server
+// connects. The tool is rebuilt only when what it describes changes.
+func (s *mcpServer) completeTool(ctx context.Context) (tool.Tool, error) {
+	s.mu.Lock()
+	cs := s.session
+	s.mu.Unlock()
+	if cs == nil {
+		return nil, nil
+	}
+	if caps := serverCapabilities(cs); caps == nil || caps.Completions == nil {
+		return nil, nil
+	}
+	refs, err := s.completionRefs(ctx)
+	if err != nil || refs == "" {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.completer != nil && s.completerRefs == refs {
+		return s.completer, nil
+	}
+	t, err := functiontool.New(functiontool.Config{
+		Name: completeToolName(s.name),
+		Description: fmt.Sprintf("Suggests valid values for an argument of the %s MCP server. Call it instead of guessing whenever a %s tool needs a name you are not sure of (a repository, branch, file…), then use one of the values returned. Completable: %s.",
+			s.name, s.name, refs),
+	}, s.complete)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s completion tool: %w", s.name, err)
+	}
+	s.completer, s.completerRefs = t, refs
+	return t, nil
+}
+
+// completeToolName is the name of the server's complete tool, kept to the
+// characters function names allow.
+func completeToolName(server string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, server)
+	return "complete_" + name + "_argument"
+}
+
+// completionRefs lists the arguments of the server's prompts and the
+// variables of its resource templates, or is empty if there are none.
+func (s *mcpServer) completionRefs(ctx context.Context) (string, error) {
+	prompts, err := s.listPrompts(ctx)
+	if err != nil {
+		return "", err
+	}
+	templates, err := s.listTemplates(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var refs []string
+	for _, p := range prompts {
+		var args []string
+		for _, arg := range p.Arguments {
+			args = append(args, arg.Name)
+		}
+		if len(args) > 0 {
+			refs = append(refs, fmt.Sprintf("prompt %q (%s)", p.Name, strings.Join(args, ", ")))
+		}
+	}
+	for _, uri := range templates {
+		if vars := templateVars(uri); len(vars) > 0 {
+			refs = append(refs, fmt.Sprintf("resource template %q (%s)", uri, strings.Join(vars, ", ")))
+		}
+	}
+	return strings.Join(refs, "; "), nil
+}
+
+// templateVars returns the variable names of a URI template.
+func templateVars(uri string) []string {
+	var vars []string
+	for _, m := range templateVar.FindAllStringSubmatch(uri, -1) {
+		for _, v := range strings.Split(m[1], ",") {
+			// Drop the explode (*) and prefix (:3) modifiers.
+			v, _, _ = strings.Cut(strings.TrimSuffix(v, "*"), ":")
+			if v = strings.TrimSpace(v); v != "" {
+				vars = append(vars, v)
+			}
+		}
+	}
+	return vars
+}
+
+// complete runs the complete tool.
+func (s *mcpServer) complete(ctx tool.Context, args CompleteArgumentArgs) (string, error) {
+	if args.Ref == "" || args.Argument == "" {
+		return "", fmt.Errorf("ref and argument are required")
+	}
+	cs, err := s.clientSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	ref := &officialmcp.CompleteReference{Type: "ref/prompt", Name: args.Ref}
+	if strings.Contains(args.Ref, "://") || strings.Contains(args.Ref, "{") {
+		ref = &officialmcp.CompleteReference{Type: "ref/resource", URI: args.Ref}
+	}
+	params := &officialmcp.CompleteParams{
+		Ref:      ref,
+		Argument: officialmcp.CompleteParamsArgument{Name: args.Argument, Value: args.Value},
+	}
+	if len(args.Context) > 0 {
+		params.Context = &officialmcp.CompleteContext{Arguments: args.Context}
+	}
+	res, err := cs.Complete(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to complete %s of %s on %s: %w", args.Argument, args.Ref, s.name, err)
+	}
+
+	values := res.Completion.Values
+	if len(values) == 0 {
+		return fmt.Sprintf("No values of %s start with %q.", args.Argument, args.Value), nil
+	}
+	out := "Values: " + strings.Join(values, ", ")
+	if more := res.Completion.Total - len(values); res.Completion.HasMore || more > 0 {
+		out += fmt.Sprintf(" (and %s more; give a longer value to narrow them down)", countOrSome(more))
+	}
+	return out, nil
+}
+
+func countOrSome(n int) string {
+	if n > 0 {
+		return fmt.Sprint(n)
+	}
+	return "some"
+}
+
+// listTemplates lists the URI templates of the server's resources, or none
+// if it does not offer resources. The list is cached while the server
+// announces changes to its resources.
+func (s *mcpServer) listTemplates(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	if s.templates != nil {
+		defer s.mu.Unlock()
+		return *s.templates, nil
+	}
+	gen := s.gen
+	s.mu.Unlock()
+
+	cs, err := s.clientSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if caps := serverCapabilities(cs); caps == nil || caps.Resources == nil {
+		return nil, nil
+	}
+	var templates []string
+	for rt, err := range cs.ResourceTemplates(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, rt.URITemplate)
+	}
+	sort.Strings(templates)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen == s.gen && announcesChanges(cs, func(c *officialmcp.ServerCapabilities) bool {
+		return c.Resources.ListChanged
+	}) {
+		s.templates = &templates
+	}
+	return templates, nil
+}
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGitCompletionServer returns a server whose review prompt and repo
+// template complete repository and branch names.
+func newGitCompletionServer(t *testing.T) (*mcpServer, *[]*officialmcp.CompleteParams) {
+	t.Helper()
+	var asked []*officialmcp.CompleteParams
+	repos := []string{"ravenbot", "raven-web", "cronlib"}
+	server := officialmcp.NewServer(&officialmcp.Implementation{Name: "git"}, &officialmcp.ServerOptions{
+		CompletionHandler: func(_ context.Context, req *officialmcp.CompleteRequest) (*officialmcp.CompleteResult, error) {
+			asked = append(asked, req.Params)
+			var values []string
+			if req.Params.Argument.Name == "repo" {
+				for _, r := range repos {
+					if strings.HasPrefix(r, req.Params.Argument.Value) {
+						values = append(values, r)
+					}
+				}
+			}
+			return &officialmcp.CompleteResult{Completion: officialmcp.CompletionResultDetails{Values: values, Total: len(values) + 3}}, nil
+		},
+	})
+	server.AddPrompt(&officialmcp.Prompt{Name: "review", Arguments: []*officialmcp.PromptArgument{{Name: "repo"}, {Name: "branch"}}},
+		func(context.Context, *officialmcp.GetPromptRequest) (*officialmcp.GetPromptResult, error) {
+			return &officialmcp.GetPromptResult{}, nil
+		})
+	server.AddResourceTemplate(&officialmcp.ResourceTemplate{Name: "tree", URITemplate: "git://{owner}/{repo}/tree{/path*}"},
+		func(context.Context, *officialmcp.ReadResourceRequest) (*officialmcp.ReadResourceResult, error) {
+			return &officialmcp.ReadResourceResult{}, nil
+		})
+	officialmcp.AddTool(server, &officialmcp.Tool{Name: "log"}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+		return &officialmcp.CallToolResult{}, nil, nil
+	})
+
+	clientTransport, serverTransport := officialmcp.NewInMemoryTransports()
+	ss, err := server.Connect(context.Background(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ss.Close() })
+	s, err := newMCPServer("git", clientTransport, nil, nil, nil)
+	require.NoError(t, err)
+	return s, &asked
+}
+
+func TestAgentToolset_CompleteTool(t *testing.T) {
+	s, asked := newGitCompletionServer(t)
+	ctx := context.Background()
+
+	tools, err := agentToolset{s}.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+	complete := tools[1].(functionTool)
+	assert.Equal(t, "complete_git_argument", complete.Name())
+	assert.Contains(t, complete.Description(), `prompt "review" (repo, branch); resource template "git://{owner}/{repo}/tree{/path*}" (owner, repo, path)`)
+
+	tctx := &startedCallContext{CallbackContext: callbackContext{catalogContext{ctx}}, id: "1"}
+	res, err := complete.Run(tctx, map[string]any{
+		"ref": "git://{owner}/{repo}/tree{/path*}", "argument": "repo", "value": "rav",
+		"context": map[string]any{"owner": "raythurman2386"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Values: ravenbot, raven-web (and 3 more; give a longer value to narrow them down)", res["result"])
+	require.Len(t, *asked, 1)
+	assert.Equal(t, &officialmcp.CompleteReference{Type: "ref/resource", URI: "git://{owner}/{repo}/tree{/path*}"}, (*asked)[0].Ref)
+	assert.Equal(t, map[string]string{"owner": "raythurman2386"}, (*asked)[0].Context.Arguments)
+
+	res, err = complete.Run(tctx, map[string]any{"ref": "review", "argument": "branch"})
+	require.NoError(t, err)
+	assert.Equal(t, `No values of branch start with "".`, res["result"])
+	assert.Equal(t, &officialmcp.CompleteReference{Type: "ref/prompt", Name: "review"}, (*asked)[1].Ref)
+
+	again, err := agentToolset{s}.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	assert.Same(t, tools[1], again[1], "the tool is reused while its references stay the same")
+}
+
+func TestAgentToolset_NoCompletions(t *testing.T) {
+	s := newTestMCPServer(t, "weather", func(server *officialmcp.Server) {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "forecast"}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+			return &officialmcp.CallToolResult{}, nil, nil
+		})
+	})
+	tools, err := agentToolset{s}.Tools(catalogContext{context.Background()})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "forecast", tools[0].Name())
+}
+
+func TestTemplateVars(t *testing.T) {
+	assert.Equal(t, []string{"owner", "repo"}, templateVars("git://{owner}/{repo}"))
+	assert.Equal(t, []string{"path", "q", "page"}, templateVars("file://{+path}{?q,page:3}"))
+	assert.Empty(t, templateVars("file:///etc/hosts"))
+}
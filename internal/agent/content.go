@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"path"
+	"strings"
+	"sync"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// maxToolMediaBytes caps the size of an image, audio clip or binary
+// resource that a tool result may attach; larger ones are only described.
+const maxToolMediaBytes = 10 << 20
+
+// MCPAttachment is a file returned by an MCP tool call, such as a
+// screenshot.
+type MCPAttachment struct {
+	Server   string
+	Tool     string
+	Name     string // file name
+	MIMEType string
+	Data     []byte
+}
+
+type attachmentKey struct{}
+
+// WithMCPAttachments returns a context whose MCP tool calls pass the
+// images, audio and binary resources they return to fn.
+func WithMCPAttachments(ctx context.Context, fn func(MCPAttachment)) context.Context {
+	return context.WithValue(ctx, attachmentKey{}, fn)
+}
+
+// toolImages holds the images tool calls returned during a turn until the
+// next model call can see them.
+type toolImages struct {
+	mu      sync.Mutex
+	pending []*genai.Part
+}
+
+type toolImagesKey struct{}
+
+func withToolImages(ctx context.Context) context.Context {
+	return context.WithValue(ctx, toolImagesKey{}, &toolImages{})
+}
+
+func (t *toolImages) add(mimeType string, data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, &genai.Part{InlineData: &genai.Blob{MIMEType: mimeType, Data: data}})
+}
+
+func (t *toolImages) take() []*genai.Part {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parts := t.pending
+	t.pending = nil
+	return parts
+}
+
+// convertContent rewrites the non-text content of tools/call results,
+// which the ADK toolset drops, as text the model can read: embedded text
+// resources are inlined and images, audio and binary resources are
+// described and passed on with WithMCPAttachments (images also reach the
+// model, see showToolImages).
+func (s *mcpServer) convertContent(next officialmcp.MethodHandler) officialmcp.MethodHandler {
+	return func(ctx context.Context, method string, req officialmcp.Request) (officialmcp.Result, error) {
+		result, err := next(ctx, method, req)
+		res, ok := result.(*officialmcp.CallToolResult)
+		if err != nil || !ok || method != "tools/call" {
+			return result, err
+		}
+		toolName := req.GetParams().(*officialmcp.CallToolParams).Name
+		attach, _ := ctx.Value(attachmentKey{}).(func(MCPAttachment))
+		images, _ := ctx.Value(toolImagesKey{}).(*toolImages)
+
+		// media handles a binary part, returning its description.
+		media := func(kind, name, mimeType string, data []byte) string {
+			size := fmt.Sprintf("%s, %.1f KB", mimeType, float64(len(data))/1024)
+			if len(data) > maxToolMediaBytes {
+				slog.Warn("MCP tool returned oversized content", "server", s.name, "tool", toolName, "type", mimeType, "bytes", len(data))
+				return fmt.Sprintf("[%s (%s) omitted: too large]", kind, size)
+			}
+			if kind == "image" && images != nil {
+				images.add(mimeType, data)
+			}
+			if attach == nil {
+				return fmt.Sprintf("[%s (%s)]", kind, size)
+			}
+			attach(MCPAttachment{Server: s.name, Tool: toolName, Name: name, MIMEType: mimeType, Data: data})
+			return fmt.Sprintf("[%s (%s), sent to the user as an attachment]", kind, size)
+		}
+
+		converted := make([]officialmcp.Content, 0, len(res.Content))
+		for i, c := range res.Content {
+			var text string
+			switch c := c.(type) {
+			case *officialmcp.TextContent:
+				converted = append(converted, c)
+				continue
+			case *officialmcp.ImageContent:
+				text = media("image", attachmentName(toolName, i, "", c.MIMEType), c.MIMEType, c.Data)
+			case *officialmcp.AudioContent:
+				text = media("audio", attachmentName(toolName, i, "", c.MIMEType), c.MIMEType, c.Data)
+			case *officialmcp.ResourceLink:
+				text = fmt.Sprintf("[resource %s: %s]", c.Name, c.URI)
+			case *officialmcp.EmbeddedResource:
+				r := c.Resource
+				if r == nil {
+					continue
+				}
+				if r.Blob == nil {
+					text = fmt.Sprintf("Resource %s:\n%s", r.URI, r.Text)
+					break
+				}
+				kind := "file"
+				if strings.HasPrefix(r.MIMEType, "image/") {
+					kind = "image"
+				}
+				text = fmt.Sprintf("Resource %s: %s", r.URI, media(kind, attachmentName(toolName, i, r.URI, r.MIMEType), r.MIMEType, r.Blob))
+			default:
+				continue
+			}
+			converted = append(converted, &officialmcp.TextContent{Text: text})
+		}
+		res.Content = converted
+		return res, nil
+	}
+}
+
+// attachmentName names an attachment after its resource URI or, failing
+// that, its tool.
+func attachmentName(toolName string, i int, uri, mimeType string) string {
+	if base := path.Base(uri); uri != "" && base != "/" && base != "." {
+		return base
+	}
+	ext := ""
+	if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
+		ext = exts[0]
+	}
+	return fmt.Sprintf("%s-%d%s", toolName, i+1, ext)
+}
+
+// showToolImages is a BeforeModelCallback that shows the model (when it is
+// a Gemini model, which accepts images) the images tools returned since
+// its last call, as a user message following the tool responses.
+func (a *Agent) showToolImages(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+	images, _ := ctx.Value(toolImagesKey{}).(*toolImages)
+	if images == nil {
+		return nil, nil
+	}
+	parts := images.take()
+	if len(parts) == 0 || a.config().AIBackend != config.BackendGemini {
+		return nil, nil
+	}
+	req.Contents = append(req.Contents, &genai.Content{
+		Role:  genai.RoleUser,
+		Parts: append([]*genai.Part{{Text: "Images returned by the tools:"}}, parts...),
+	})
+	return nil, nil
+}
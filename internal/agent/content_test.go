@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/genai"
+)
+
+var png = []byte("\x89PNG\r\n\x1a\nfake")
+
+func newBrowserServer(t *testing.T) *officialmcp.ClientSession {
+	t.Helper()
+	client := newTestMCPServer(t, "browser", func(server *officialmcp.Server) {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "screenshot"}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+			return &officialmcp.CallToolResult{Content: []officialmcp.Content{
+				&officialmcp.TextContent{Text: "Captured the page."},
+				&officialmcp.ImageContent{MIMEType: "image/png", Data: png},
+				&officialmcp.EmbeddedResource{Resource: &officialmcp.ResourceContents{URI: "page://title", MIMEType: "text/plain", Text: "Example Domain"}},
+				&officialmcp.EmbeddedResource{Resource: &officialmcp.ResourceContents{URI: "file:///tmp/page.pdf", MIMEType: "application/pdf", Blob: []byte("%PDF")}},
+				&officialmcp.ResourceLink{URI: "file:///tmp/trace.json", Name: "trace"},
+			}}, nil, nil
+		})
+	})
+	cs, err := client.clientSession(context.Background())
+	require.NoError(t, err)
+	return cs
+}
+
+func texts(res *officialmcp.CallToolResult) []string {
+	var out []string
+	for _, c := range res.Content {
+		out = append(out, c.(*officialmcp.TextContent).Text)
+	}
+	return out
+}
+
+func TestMCPServer_ConvertContent(t *testing.T) {
+	cs := newBrowserServer(t)
+
+	var attachments []MCPAttachment
+	ctx := withToolImages(WithMCPAttachments(context.Background(), func(a MCPAttachment) {
+		attachments = append(attachments, a)
+	}))
+	res, err := cs.CallTool(ctx, &officialmcp.CallToolParams{Name: "screenshot"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"Captured the page.",
+		"[image (image/png, 0.0 KB), sent to the user as an attachment]",
+		"Resource page://title:\nExample Domain",
+		"Resource file:///tmp/page.pdf: [file (application/pdf, 0.0 KB), sent to the user as an attachment]",
+		"[resource trace: file:///tmp/trace.json]",
+	}, texts(res))
+
+	require.Len(t, attachments, 2)
+	assert.Equal(t, MCPAttachment{Server: "browser", Tool: "screenshot", Name: "screenshot-2.png", MIMEType: "image/png", Data: png}, attachments[0])
+	assert.Equal(t, "page.pdf", attachments[1].Name)
+
+	images := ctx.Value(toolImagesKey{}).(*toolImages).take()
+	require.Len(t, images, 1, "only images are shown to the model")
+	assert.Equal(t, png, images[0].InlineData.Data)
+}
+
+func TestMCPServer_ConvertContentWithoutAttachments(t *testing.T) {
+	cs := newBrowserServer(t)
+
+	res, err := cs.CallTool(context.Background(), &officialmcp.CallToolParams{Name: "screenshot"})
+	require.NoError(t, err)
+	assert.Equal(t, "[image (image/png, 0.0 KB)]", texts(res)[1])
+}
+
+// callbackContext is a minimal agent.CallbackContext.
+type callbackContext struct {
+	catalogContext
+}
+
+func (callbackContext) Artifacts() agent.Artifacts { return nil }
+func (callbackContext) State() session.State       { return nil }
+
+func TestShowToolImages(t *testing.T) {
+	a := &Agent{cfg: &config.Config{AIBackend: config.BackendGemini}}
+	ctx := withToolImages(context.Background())
+	ctx.Value(toolImagesKey{}).(*toolImages).add("image/png", png)
+
+	req := &model.LLMRequest{Contents: []*genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{{Text: "Screenshot example.com"}}}}}
+	resp, err := a.showToolImages(callbackContext{catalogContext{ctx}}, req)
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+	require.Len(t, req.Contents, 2)
+	assert.Equal(t, png, req.Contents[1].Parts[1].InlineData.Data)
+
+	_, _ = a.showToolImages(callbackContext{catalogContext{ctx}}, req)
+	assert.Len(t, req.Contents, 2, "each image is shown once")
+
+	a.cfg.AIBackend = config.BackendOllama
+	ctx.Value(toolImagesKey{}).(*toolImages).add("image/png", png)
+	_, _ = a.showToolImages(callbackContext{catalogContext{ctx}}, req)
+	assert.Len(t, req.Contents, 2, "models without vision support get the description only")
+}
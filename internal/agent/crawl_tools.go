@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// maxCrawlChars caps the text crawl_site returns, about 15k tokens.
+const maxCrawlChars = 60000
+
+type CrawlSiteArgs struct {
+	URL      string `json:"url" jsonschema:"The page to start from, e.g. a documentation site's table of contents."`
+	Depth    int    `json:"depth,omitempty" jsonschema:"How many links away from the start page to follow (default 2, at most 3)."`
+	MaxPages int    `json:"max_pages,omitempty" jsonschema:"The most pages to read (default 10, at most 30)."`
+}
+
+// newCrawlTool returns the tool that reads a site's pages by following its
+// links, so documentation can be read as a whole.
+func newCrawlTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "crawl_site",
+		Description: "Reads a website by following its links from a start page, staying on the same site, and returns the text of each page read (60,000 characters in all). Use to read documentation or a project site as a whole; prefer a single search for single facts.",
+	}, func(ctx tool.Context, args CrawlSiteArgs) (string, error) {
+		pages, err := tools.CrawlSite(ctx, args.URL, args.Depth, args.MaxPages)
+		if err != nil {
+			return "", err
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Read %d page(s) from %s.\n", len(pages), args.URL))
+		for i, p := range pages {
+			title := p.Title
+			if title == "" {
+				title = p.URL
+			}
+			part := fmt.Sprintf("\n## %s\n%s\n\n%s\n", title, p.URL, p.Text)
+			if sb.Len()+len(part) > maxCrawlChars {
+				sb.WriteString(fmt.Sprintf("\n[… %d more page(s) left out]\n", len(pages)-i))
+				break
+			}
+			sb.WriteString(part)
+		}
+		return sb.String(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create crawl_site tool: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,208 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+	"google.golang.org/genai"
+)
+
+// functionTool and requestProcessor are the methods ADK calls on the tools
+// of an MCP toolset.
+type functionTool interface {
+	tool.Tool
+	Declaration() *genai.FunctionDeclaration
+	Run(ctx tool.Context, args any) (map[string]any, error)
+}
+
+type requestProcessor interface {
+	ProcessRequest(ctx tool.Context, req *model.LLMRequest) error
+}
+
+// mcpCallTool is an MCP tool as the agents see it. ADK runs the function
+// calls of a model response one after another; when the model asks for
+// several at once, dispatchMCPCalls starts this tool's call ahead of time
+// and Run waits for its result. Either way the server's concurrency cap
+// applies, and the call is timed for the server's usage stats.
+type mcpCallTool struct {
+	functionTool
+	server *mcpServer
+}
+
+// ProcessRequest offers the tool to the model, registering it with the
+// turn's dispatch so its calls can be started early.
+func (t *mcpCallTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if rp, ok := t.functionTool.(requestProcessor); ok {
+		if err := rp.ProcessRequest(ctx, req); err != nil {
+			return err
+		}
+	}
+	if req.Tools == nil {
+		req.Tools = make(map[string]any)
+	}
+	req.Tools[t.Name()] = t
+	if d, _ := ctx.Value(dispatchKey{}).(*mcpDispatch); d != nil {
+		d.offer(ctx.AgentName(), t)
+	}
+	return nil
+}
+
+// Run returns the result of the call, waiting for it if it was started
+// early.
+func (t *mcpCallTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	if d, _ := ctx.Value(dispatchKey{}).(*mcpDispatch); d != nil {
+		if call := d.take(ctx.FunctionCallID()); call != nil {
+			select {
+			case <-call.done:
+				return call.result, call.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	return t.run(ctx, args)
+}
+
+func (t *mcpCallTool) run(ctx tool.Context, args any) (map[string]any, error) {
+	release, err := t.server.acquireCall(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	start := time.Now()
+	result, err := t.functionTool.Run(ctx, args)
+	if t.server.stats != nil {
+		t.server.stats.RecordMCPCall(t.server.name, t.Name(), time.Since(start), err != nil)
+	}
+	return result, err
+}
+
+// acquireCall waits for one of the server's call slots.
+func (s *mcpServer) acquireCall(ctx context.Context) (func(), error) {
+	if s.calls == nil {
+		return func() {}, nil
+	}
+	select {
+	case s.calls <- struct{}{}:
+		return func() { <-s.calls }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// mcpDispatch is a turn's MCP tools, by agent, and the calls to them that
+// were started early, by function call ID.
+type mcpDispatch struct {
+	mu    sync.Mutex
+	tools map[string]*mcpCallTool
+	calls map[string]*startedCall
+}
+
+// startedCall is an MCP tool call running ahead of ADK.
+type startedCall struct {
+	done   chan struct{}
+	result map[string]any
+	err    error
+}
+
+type dispatchKey struct{}
+
+func withMCPDispatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dispatchKey{}, &mcpDispatch{
+		tools: make(map[string]*mcpCallTool),
+		calls: make(map[string]*startedCall),
+	})
+}
+
+func (d *mcpDispatch) offer(agentName string, t *mcpCallTool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tools[agentName+"/"+t.Name()] = t
+}
+
+func (d *mcpDispatch) take(id string) *startedCall {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	call := d.calls[id]
+	delete(d.calls, id)
+	return call
+}
+
+// dispatchMCPCalls is an AfterModelCallback that, when a response asks for
+// several function calls, starts its MCP tool calls at once instead of
+// leaving ADK to make them in turn (calls the model makes together are
+// independent of each other).
+func dispatchMCPCalls(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+	d, _ := ctx.Value(dispatchKey{}).(*mcpDispatch)
+	if d == nil || respErr != nil || resp == nil || resp.Content == nil {
+		return nil, nil
+	}
+	var calls []*genai.FunctionCall
+	for _, p := range resp.Content.Parts {
+		if p.FunctionCall != nil {
+			calls = append(calls, p.FunctionCall)
+		}
+	}
+	if len(calls) < 2 {
+		return nil, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	started := 0
+	for _, fc := range calls {
+		t := d.tools[ctx.AgentName()+"/"+fc.Name]
+		if t == nil {
+			continue
+		}
+		// ADK names calls that lack an ID after this callback, keeping
+		// any set here; its prefix marks them as ADK's own.
+		if fc.ID == "" {
+			fc.ID = "adk-" + uuid.NewString()
+		}
+		call := &startedCall{done: make(chan struct{})}
+		d.calls[fc.ID] = call
+		tctx := &startedCallContext{CallbackContext: ctx, id: fc.ID}
+		go func(args map[string]any) {
+			defer close(call.done)
+			call.result, call.err = t.run(tctx, args)
+		}(fc.Args)
+		started++
+	}
+	if started > 0 {
+		slog.Debug("Started MCP tool calls together", "agent", ctx.AgentName(), "calls", started, "of", len(calls))
+	}
+	return nil, nil
+}
+
+// startedCallContext is the tool.Context of a call started by
+// dispatchMCPCalls. MCP tools only use it as a context.Context.
+type startedCallContext struct {
+	agent.CallbackContext
+	id      string
+	actions session.EventActions
+}
+
+var _ tool.Context = (*startedCallContext)(nil)
+
+func (c *startedCallContext) FunctionCallID() string                               { return c.id }
+func (c *startedCallContext) Actions() *session.EventActions                       { return &c.actions }
+func (c *startedCallContext) ToolConfirmation() *toolconfirmation.ToolConfirmation { return nil }
+
+func (c *startedCallContext) SearchMemory(context.Context, string) (*memory.SearchResponse, error) {
+	return nil, errors.New("memory is not available to MCP tools")
+}
+
+func (c *startedCallContext) RequestConfirmation(string, any) error {
+	return errors.New("confirmation is not available to MCP tools")
+}
@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// newSlowWeatherServer returns a server whose tools take 200ms, and a
+// function reporting the most calls that ran at once.
+func newSlowWeatherServer(t *testing.T) (*mcpServer, func() int) {
+	t.Helper()
+	var mu sync.Mutex
+	running, peak := 0, 0
+	slow := func(text string) officialmcp.ToolHandlerFor[struct{}, any] {
+		return func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+			time.Sleep(200 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return &officialmcp.CallToolResult{Content: []officialmcp.Content{&officialmcp.TextContent{Text: text}}}, nil, nil
+		}
+	}
+	s := newTestMCPServer(t, "weather", func(server *officialmcp.Server) {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "forecast"}, slow("Sunny"))
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "alerts"}, slow("No alerts"))
+	})
+	return s, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return peak
+	}
+}
+
+// runWeatherTurn runs a turn in which the model calls both of the
+// server's tools at once, returning their responses by name.
+func runWeatherTurn(t *testing.T, s *mcpServer) map[string]any {
+	t.Helper()
+	llm := &MockLLM{QueuedResponses: [][]*model.LLMResponse{
+		{{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+			{FunctionCall: &genai.FunctionCall{Name: "forecast", Args: map[string]any{}}},
+			{FunctionCall: &genai.FunctionCall{Name: "alerts", Args: map[string]any{}}},
+		}}}},
+		{NewTextResponse("Sunny, no alerts.")},
+	}}
+	researcher, err := llmagent.New(llmagent.Config{
+		Name:                "ResearchAssistant",
+		Model:               llm,
+		Toolsets:            []tool.Toolset{agentToolset{s}},
+		AfterModelCallbacks: []llmagent.AfterModelCallback{dispatchMCPCalls},
+	})
+	require.NoError(t, err)
+	sessions := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: AppName, Agent: researcher, SessionService: sessions})
+	require.NoError(t, err)
+	ctx := withMCPDispatch(context.Background())
+	_, err = sessions.Create(ctx, &session.CreateRequest{AppName: AppName, UserID: "u", SessionID: "s"})
+	require.NoError(t, err)
+
+	responses := make(map[string]any)
+	for ev, err := range r.Run(ctx, "u", "s", genai.NewContentFromText("Weather?", genai.RoleUser), agent.RunConfig{}) {
+		require.NoError(t, err)
+		if ev.Content == nil {
+			continue
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				responses[p.FunctionResponse.Name] = p.FunctionResponse.Response["output"]
+			}
+		}
+	}
+	return responses
+}
+
+func TestDispatchMCPCalls(t *testing.T) {
+	s, peak := newSlowWeatherServer(t)
+	s.stats = stats.New()
+
+	start := time.Now()
+	responses := runWeatherTurn(t, s)
+	assert.Equal(t, map[string]any{"forecast": "Sunny", "alerts": "No alerts"}, responses)
+	assert.Equal(t, 2, peak(), "calls made together run in parallel")
+	assert.Less(t, time.Since(start), 390*time.Millisecond)
+
+	forecast := s.stats.MCPCalls()[stats.MCPTool{Server: "weather", Tool: "forecast"}]
+	assert.Equal(t, int64(1), forecast.Calls)
+	assert.Zero(t, forecast.Errors)
+	assert.GreaterOrEqual(t, forecast.Total, 200*time.Millisecond)
+	assert.Equal(t, int64(2), s.stats.MCPServerCalls()["weather"].Calls)
+}
+
+func TestMCPCallTool_RecordsFailures(t *testing.T) {
+	s := newTestMCPServer(t, "github", func(server *officialmcp.Server) {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "create_issue"}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+			return &officialmcp.CallToolResult{IsError: true, Content: []officialmcp.Content{&officialmcp.TextContent{Text: "rate limited"}}}, nil, nil
+		})
+	})
+	s.stats = stats.New()
+	ctx := context.Background()
+	tools, err := agentToolset{s}.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+
+	_, err = tools[0].(*mcpCallTool).Run(&startedCallContext{CallbackContext: callbackContext{catalogContext{ctx}}, id: "1"}, map[string]any{})
+	assert.ErrorContains(t, err, "rate limited")
+	assert.Equal(t, stats.MCPCallUsage{Calls: 1, Errors: 1}, withoutTimes(s.stats.MCPServerCalls()["github"]))
+}
+
+// withoutTimes drops the durations from u, which vary between runs.
+func withoutTimes(u stats.MCPCallUsage) stats.MCPCallUsage {
+	u.Total, u.Max = 0, 0
+	return u
+}
+
+func TestDispatchMCPCalls_ConcurrencyCap(t *testing.T) {
+	s, peak := newSlowWeatherServer(t)
+	s.calls = make(chan struct{}, 1)
+
+	responses := runWeatherTurn(t, s)
+	assert.Equal(t, map[string]any{"forecast": "Sunny", "alerts": "No alerts"}, responses)
+	assert.Equal(t, 1, peak(), "the server takes one call at a time")
+}
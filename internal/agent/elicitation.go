@@ -0,0 +1,189 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Errors an Elicitor returns for the user's choice not to answer.
+var (
+	ErrElicitationDeclined  = errors.New("user declined to answer")
+	ErrElicitationCancelled = errors.New("user cancelled the question")
+)
+
+// MCPElicitation is a question an MCP server asks the user during a tool
+// call (MCP elicitation).
+type MCPElicitation struct {
+	Server  string
+	Tool    string
+	Message string
+	Fields  []ElicitField // none for a plain confirmation
+}
+
+// ElicitField is one value a server asks for.
+type ElicitField struct {
+	Name        string
+	Title       string
+	Description string
+	Type        string   // "string", "number", "integer" or "boolean"
+	Enum        []string // allowed values, if restricted
+	Required    bool
+}
+
+// Parse converts the user's answer to the field's type.
+func (f ElicitField) Parse(answer string) (any, error) {
+	answer = strings.TrimSpace(answer)
+	switch f.Type {
+	case "boolean":
+		switch strings.ToLower(answer) {
+		case "yes", "y", "true", "sí", "si":
+			return true, nil
+		case "no", "n", "false":
+			return false, nil
+		}
+		return nil, errors.New("expected yes or no")
+	case "integer":
+		n, err := strconv.ParseInt(answer, 10, 64)
+		if err != nil {
+			return nil, errors.New("expected a whole number")
+		}
+		return n, nil
+	case "number":
+		n, err := strconv.ParseFloat(answer, 64)
+		if err != nil {
+			return nil, errors.New("expected a number")
+		}
+		return n, nil
+	}
+	if len(f.Enum) > 0 {
+		i := slices.IndexFunc(f.Enum, func(v string) bool { return strings.EqualFold(v, answer) })
+		if i < 0 {
+			return nil, fmt.Errorf("expected one of: %s", strings.Join(f.Enum, ", "))
+		}
+		return f.Enum[i], nil
+	}
+	if answer == "" {
+		return nil, errors.New("expected an answer")
+	}
+	return answer, nil
+}
+
+// Elicitor asks the user an MCPElicitation and returns the answers by
+// field name, or ErrElicitationDeclined or ErrElicitationCancelled.
+type Elicitor func(ctx context.Context, e MCPElicitation) (map[string]any, error)
+
+type elicitorKey struct{}
+
+// WithMCPElicitor returns a context whose MCP tool calls let their server
+// ask the user questions through ask.
+func WithMCPElicitor(ctx context.Context, ask Elicitor) context.Context {
+	return context.WithValue(ctx, elicitorKey{}, ask)
+}
+
+// elicitingCall is a tool call whose user can be asked questions.
+type elicitingCall struct {
+	tool string
+	ask  Elicitor
+	ctx  context.Context
+}
+
+// trackElicitors records the tool calls made with WithMCPElicitor while
+// they run. Elicitation requests do not say which call they belong to, so
+// they go to the user of the most recent one.
+func (s *mcpServer) trackElicitors(next officialmcp.MethodHandler) officialmcp.MethodHandler {
+	return func(ctx context.Context, method string, req officialmcp.Request) (officialmcp.Result, error) {
+		ask, _ := ctx.Value(elicitorKey{}).(Elicitor)
+		params, ok := req.GetParams().(*officialmcp.CallToolParams)
+		if method != "tools/call" || ask == nil || !ok {
+			return next(ctx, method, req)
+		}
+		call := &elicitingCall{tool: params.Name, ask: ask, ctx: ctx}
+		s.mu.Lock()
+		s.eliciting = append(s.eliciting, call)
+		s.mu.Unlock()
+		defer func() {
+			s.mu.Lock()
+			s.eliciting = slices.DeleteFunc(s.eliciting, func(c *elicitingCall) bool { return c == call })
+			s.mu.Unlock()
+		}()
+		return next(ctx, method, req)
+	}
+}
+
+// elicit handles elicitation/create.
+func (s *mcpServer) elicit(ctx context.Context, req *officialmcp.ElicitRequest) (*officialmcp.ElicitResult, error) {
+	s.mu.Lock()
+	var call *elicitingCall
+	if n := len(s.eliciting); n > 0 {
+		call = s.eliciting[n-1]
+	}
+	s.mu.Unlock()
+	if call == nil {
+		slog.Warn("MCP server asked a question outside of a chat", "server", s.name)
+		return &officialmcp.ElicitResult{Action: "cancel"}, nil
+	}
+
+	fields, err := elicitFields(req.Params.RequestedSchema)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("MCP server asking the user", "server", s.name, "tool", call.tool, "fields", len(fields))
+	// The question belongs to the tool call: it ends when the call does.
+	answers, err := call.ask(call.ctx, MCPElicitation{Server: s.name, Tool: call.tool, Message: req.Params.Message, Fields: fields})
+	switch {
+	case errors.Is(err, ErrElicitationDeclined):
+		return &officialmcp.ElicitResult{Action: "decline"}, nil
+	case err != nil:
+		slog.Info("MCP question not answered", "server", s.name, "tool", call.tool, "reason", err)
+		return &officialmcp.ElicitResult{Action: "cancel"}, nil
+	}
+	return &officialmcp.ElicitResult{Action: "accept", Content: answers}, nil
+}
+
+// elicitFields reads the flat object schema of an elicitation request;
+// required fields come first.
+func elicitFields(schema any) ([]ElicitField, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var s struct {
+		Properties map[string]struct {
+			Type        string `json:"type"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Enum        []any  `json:"enum"`
+		} `json:"properties"`
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("invalid elicitation schema: %w", err)
+	}
+	fields := make([]ElicitField, 0, len(s.Properties))
+	for name, p := range s.Properties {
+		f := ElicitField{Name: name, Title: p.Title, Description: p.Description, Type: p.Type, Required: slices.Contains(s.Required, name)}
+		for _, v := range p.Enum {
+			f.Enum = append(f.Enum, fmt.Sprint(v))
+		}
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].Required != fields[j].Required {
+			return fields[i].Required
+		}
+		return fields[i].Name < fields[j].Name
+	})
+	return fields, nil
+}
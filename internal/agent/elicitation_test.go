@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var issueSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"title":    map[string]any{"type": "string", "title": "Issue title"},
+		"priority": map[string]any{"type": "string", "enum": []any{"low", "high"}},
+		"draft":    map[string]any{"type": "boolean"},
+	},
+	"required": []any{"title"},
+}
+
+// newIssueServer returns a server whose create_issue tool asks for the
+// issue's details, and whose delete_branch tool asks for confirmation,
+// reporting the user's response.
+func newIssueServer(t *testing.T) *officialmcp.ClientSession {
+	t.Helper()
+	client := newTestMCPServer(t, "github", func(server *officialmcp.Server) {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "create_issue"}, func(ctx context.Context, req *officialmcp.CallToolRequest, _ struct{}) (*officialmcp.CallToolResult, any, error) {
+			res, err := req.Session.Elicit(ctx, &officialmcp.ElicitParams{Message: "Describe the issue", RequestedSchema: issueSchema})
+			if err != nil {
+				return nil, nil, err
+			}
+			text := res.Action
+			if res.Action == "accept" {
+				text += " " + res.Content["title"].(string)
+			}
+			return &officialmcp.CallToolResult{Content: []officialmcp.Content{&officialmcp.TextContent{Text: text}}}, nil, nil
+		})
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "delete_branch"}, func(ctx context.Context, req *officialmcp.CallToolRequest, _ struct{}) (*officialmcp.CallToolResult, any, error) {
+			res, err := req.Session.Elicit(ctx, &officialmcp.ElicitParams{Message: "Delete main?"})
+			if err != nil {
+				return nil, nil, err
+			}
+			return &officialmcp.CallToolResult{Content: []officialmcp.Content{&officialmcp.TextContent{Text: res.Action}}}, nil, nil
+		})
+	})
+	cs, err := client.clientSession(context.Background())
+	require.NoError(t, err)
+	return cs
+}
+
+func TestMCPServer_Elicitation(t *testing.T) {
+	cs := newIssueServer(t)
+
+	var asked MCPElicitation
+	ctx := WithMCPElicitor(context.Background(), func(_ context.Context, e MCPElicitation) (map[string]any, error) {
+		asked = e
+		return map[string]any{"title": "Flaky test", "draft": true}, nil
+	})
+	res, err := cs.CallTool(ctx, &officialmcp.CallToolParams{Name: "create_issue"})
+	require.NoError(t, err)
+	assert.Equal(t, "accept Flaky test", res.Content[0].(*officialmcp.TextContent).Text)
+
+	assert.Equal(t, "github", asked.Server)
+	assert.Equal(t, "create_issue", asked.Tool)
+	assert.Equal(t, "Describe the issue", asked.Message)
+	require.Len(t, asked.Fields, 3)
+	assert.Equal(t, ElicitField{Name: "title", Title: "Issue title", Type: "string", Required: true}, asked.Fields[0], "required fields come first")
+	assert.Equal(t, []string{"low", "high"}, asked.Fields[2].Enum)
+}
+
+func TestMCPServer_ElicitationRefused(t *testing.T) {
+	cs := newIssueServer(t)
+
+	var asked MCPElicitation
+	ctx := WithMCPElicitor(context.Background(), func(_ context.Context, e MCPElicitation) (map[string]any, error) {
+		asked = e
+		return nil, ErrElicitationDeclined
+	})
+	res, err := cs.CallTool(ctx, &officialmcp.CallToolParams{Name: "delete_branch"})
+	require.NoError(t, err)
+	assert.Equal(t, "decline", res.Content[0].(*officialmcp.TextContent).Text)
+	assert.Empty(t, asked.Fields, "a confirmation has no fields")
+
+	res, err = cs.CallTool(context.Background(), &officialmcp.CallToolParams{Name: "delete_branch"})
+	require.NoError(t, err)
+	assert.Equal(t, "cancel", res.Content[0].(*officialmcp.TextContent).Text, "calls without a user cannot be asked")
+}
+
+func TestElicitField_Parse(t *testing.T) {
+	tests := []struct {
+		field   ElicitField
+		answer  string
+		want    any
+		wantErr bool
+	}{
+		{ElicitField{Type: "string"}, " Flaky test ", "Flaky test", false},
+		{ElicitField{Type: "string"}, "", nil, true},
+		{ElicitField{Type: "string", Enum: []string{"low", "high"}}, "HIGH", "high", false},
+		{ElicitField{Type: "string", Enum: []string{"low", "high"}}, "urgent", nil, true},
+		{ElicitField{Type: "boolean"}, "yes", true, false},
+		{ElicitField{Type: "boolean"}, "no", false, false},
+		{ElicitField{Type: "boolean"}, "maybe", nil, true},
+		{ElicitField{Type: "integer"}, "42", int64(42), false},
+		{ElicitField{Type: "integer"}, "4.2", nil, true},
+		{ElicitField{Type: "number"}, "4.2", 4.2, false},
+	}
+	for _, tt := range tests {
+		got, err := tt.field.Parse(tt.answer)
+		if tt.wantErr {
+			assert.Error(t, err, tt.answer)
+			continue
+		}
+		require.NoError(t, err, tt.answer)
+		assert.Equal(t, tt.want, got, tt.answer)
+	}
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"context"
+
+	"google.golang.org/adk/session"
+)
+
+// recentEvents is the session service behind the chat runners. The runner
+// loads the whole session on every turn; recentEvents limits that load to
+// the configured window of most recent events, so the query and the
+// decoding stay bounded however long a conversation gets. Requests that
+// set NumRecentEvents themselves pass through unchanged.
+type recentEvents struct {
+	session.Service
+	limit func(ctx context.Context) int
+}
+
+func (s *recentEvents) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	n := 0
+	if req.NumRecentEvents == 0 {
+		n = s.limit(ctx)
+	}
+	if n <= 0 {
+		return s.Service.Get(ctx, req)
+	}
+	windowed := *req
+	windowed.NumRecentEvents = n
+	resp, err := s.Service.Get(ctx, &windowed)
+	if err != nil {
+		return nil, err
+	}
+
+	// A window that cut into a turn would start with tool calls or
+	// responses whose counterpart was left out, which models reject, so
+	// start it at the first user message instead.
+	events := resp.Session.Events()
+	if events.Len() < n {
+		return resp, nil
+	}
+	for i := 0; i < events.Len(); i++ {
+		if events.At(i).Author == "user" {
+			if i == 0 {
+				return resp, nil
+			}
+			windowed.NumRecentEvents = n - i
+			return s.Service.Get(ctx, &windowed)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	adkdb "google.golang.org/adk/session/database"
+	"google.golang.org/genai"
+)
+
+func TestRecentEvents(t *testing.T) {
+	t.Parallel()
+	database, err := db.InitDB(":memory:")
+	require.NoError(t, err)
+	defer func() { _ = database.Close() }()
+	ctx := t.Context()
+
+	svc, err := adkdb.NewSessionService(&sqlite.Dialector{Conn: database.DB})
+	require.NoError(t, err)
+	require.NoError(t, adkdb.AutoMigrate(svc))
+	require.NoError(t, database.IndexSessionEvents(ctx))
+	require.NoError(t, database.IndexSessionEvents(ctx), "indexing is idempotent")
+
+	created, err := svc.Create(ctx, &session.CreateRequest{AppName: AppName, UserID: "u", SessionID: "s"})
+	require.NoError(t, err)
+	// Two turns: user, tool call, tool response, answer.
+	start := time.Now().Add(-time.Hour)
+	for i, author := range []string{"user", "Agent", "Agent", "Agent", "user", "Agent", "Agent", "Agent"} {
+		event := session.NewEvent("inv")
+		event.Author = author
+		event.Timestamp = start.Add(time.Duration(i) * time.Second)
+		event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText(author, genai.RoleUser)}
+		require.NoError(t, svc.AppendEvent(ctx, created.Session, event))
+	}
+
+	limit := 6
+	recent := &recentEvents{Service: svc, limit: func(context.Context) int { return limit }}
+	get := func(req session.GetRequest) session.Events {
+		req.AppName, req.UserID, req.SessionID = AppName, "u", "s"
+		resp, err := recent.Get(ctx, &req)
+		require.NoError(t, err)
+		return resp.Session.Events()
+	}
+
+	events := get(session.GetRequest{})
+	assert.Equal(t, 4, events.Len(), "a window cutting into a turn starts at the next user message")
+	assert.Equal(t, "user", events.At(0).Author)
+
+	limit = 4
+	assert.Equal(t, 4, get(session.GetRequest{}).Len())
+
+	limit = 20
+	assert.Equal(t, 8, get(session.GetRequest{}).Len(), "short sessions load whole")
+
+	assert.Equal(t, 2, get(session.GetRequest{NumRecentEvents: 2}).Len(), "explicit requests pass through")
+
+	limit = 0
+	assert.Equal(t, 8, get(session.GetRequest{}).Len(), "no limit loads everything")
+}
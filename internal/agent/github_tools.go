@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type GitHubIssuesArgs struct {
+	Repo  string `json:"repo" jsonschema:"The repository in 'owner/repo' format."`
+	State string `json:"state,omitempty" jsonschema:"open (default), closed or all."`
+	Query string `json:"query,omitempty" jsonschema:"Words to search the issues or pull requests for; empty lists the most recently updated."`
+	Pulls bool   `json:"pulls,omitempty" jsonschema:"List pull requests instead of issues."`
+}
+
+type GitHubIssueArgs struct {
+	Repo   string `json:"repo" jsonschema:"The repository in 'owner/repo' format."`
+	Number int    `json:"number" jsonschema:"The issue or pull request number."`
+}
+
+type GitHubSearchReposArgs struct {
+	Query string `json:"query" jsonschema:"A GitHub repository search, e.g. 'language:go stars:>1000 mcp'."`
+}
+
+type GitHubNotificationsArgs struct {
+	All bool `json:"all,omitempty" jsonschema:"Include notifications already read."`
+}
+
+type GitHubCommitArgs struct {
+	Repo string `json:"repo" jsonschema:"The repository in 'owner/repo' format."`
+	Ref  string `json:"ref" jsonschema:"The commit SHA, branch or tag."`
+}
+
+// newGitHubTools returns read-only GitHub lookups over the REST API, so
+// quick questions about issues, pull requests and commits need no GitHub
+// MCP server.
+func (a *Agent) newGitHubTools() ([]tool.Tool, error) {
+	client := func() *tools.GitHub { return tools.NewGitHub(a.config().GitHubToken) }
+
+	issuesTool, err := functiontool.New(functiontool.Config{
+		Name:        "github_issues",
+		Description: "Lists or searches a GitHub repository's issues or pull requests with their numbers, state, author and links.",
+	}, func(ctx tool.Context, args GitHubIssuesArgs) (string, error) {
+		return client().Issues(ctx, args.Repo, args.State, args.Query, args.Pulls)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github_issues tool: %w", err)
+	}
+
+	issueTool, err := functiontool.New(functiontool.Config{
+		Name:        "github_issue",
+		Description: "Gets a GitHub issue or pull request by number: its description, latest comments and, for a pull request, its changed files and diff.",
+	}, func(ctx tool.Context, args GitHubIssueArgs) (string, error) {
+		return client().Issue(ctx, args.Repo, args.Number)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github_issue tool: %w", err)
+	}
+
+	searchTool, err := functiontool.New(functiontool.Config{
+		Name:        "github_search_repos",
+		Description: "Searches GitHub repositories, listing their stars, language, last push and description.",
+	}, func(ctx tool.Context, args GitHubSearchReposArgs) (string, error) {
+		return client().SearchRepos(ctx, args.Query)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github_search_repos tool: %w", err)
+	}
+
+	notificationsTool, err := functiontool.New(functiontool.Config{
+		Name:        "github_notifications",
+		Description: "Lists the user's GitHub notifications (review requests, mentions, assigned issues), unread ones unless all is set.",
+	}, func(ctx tool.Context, args GitHubNotificationsArgs) (string, error) {
+		return client().Notifications(ctx, args.All)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github_notifications tool: %w", err)
+	}
+
+	commitTool, err := functiontool.New(functiontool.Config{
+		Name:        "github_commit",
+		Description: "Gets a GitHub commit's message, author, stats and diff.",
+	}, func(ctx tool.Context, args GitHubCommitArgs) (string, error) {
+		return client().Commit(ctx, args.Repo, args.Ref)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create github_commit tool: %w", err)
+	}
+	return []tool.Tool{issuesTool, issueTool, searchTool, notificationsTool, commitTool}, nil
+}
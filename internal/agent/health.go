@@ -0,0 +1,179 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+)
+
+// MCP supervision timing: a healthy server is pinged every
+// mcpHealthInterval; a failing one is retried with a backoff doubling from
+// mcpMinBackoff to mcpMaxBackoff.
+const (
+	mcpHealthInterval = 30 * time.Second
+	mcpMinBackoff     = time.Second
+	mcpMaxBackoff     = 5 * time.Minute
+)
+
+// MCPHealth is the connection state of a configured MCP server.
+type MCPHealth struct {
+	Server   string
+	Healthy  bool
+	Err      string    // why it is down
+	Since    time.Time // start of the current state; zero before the first check
+	Restarts int       // recoveries since startup
+
+	LastCrash string // why the server's process last died, if it has
+	CrashedAt time.Time
+
+	// Protocol is the MCP revision negotiated with the server, such as
+	// "2025-03-26"; the SDK adapts its messages to it. Features newer than
+	// it (elicitation, structured tool output) are unavailable.
+	Protocol string
+}
+
+// LatestMCPProtocol is the newest MCP revision ravenbot offers servers.
+// Servers that only speak an older one (2024-11-05 or 2025-03-26) are
+// still used.
+const LatestMCPProtocol = "2025-06-18"
+
+// MCPHealth reports every configured MCP server, by name.
+func (a *Agent) MCPHealth() []MCPHealth {
+	names := slices.Sorted(maps.Keys(a.mcpConfigs()))
+	health := make([]MCPHealth, 0, len(names))
+	for _, name := range names {
+		s, ok := a.mcpServer(name)
+		if !ok {
+			health = append(health, MCPHealth{Server: name, Err: "failed to start"})
+			continue
+		}
+		health = append(health, s.health())
+	}
+	return health
+}
+
+// agentToolset is an MCP server as the agents see it. ADK fails a model
+// call when any of the agent's toolsets errors, so the tools of a server
+// that is down are left out instead, until it recovers. Its tools are
+// mcpCallTools, with its complete tool when it has one.
+type agentToolset struct {
+	tool.Toolset
+}
+
+var _ tool.Toolset = agentToolset{}
+
+func (t agentToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := t.Toolset.Tools(ctx)
+	if err != nil {
+		slog.Debug("Leaving out MCP tools", "server", t.Name(), "error", err)
+		return nil, nil
+	}
+	s, ok := t.Toolset.(*mcpServer)
+	if !ok {
+		return tools, nil
+	}
+	wrapped := make([]tool.Tool, 0, len(tools))
+	for _, tl := range tools {
+		if ft, ok := tl.(functionTool); ok {
+			tl = &mcpCallTool{functionTool: ft, server: s}
+		}
+		wrapped = append(wrapped, tl)
+	}
+	complete, err := s.completeTool(ctx)
+	if err != nil {
+		slog.Debug("Leaving out MCP completion tool", "server", t.Name(), "error", err)
+	} else if complete != nil {
+		wrapped = append(wrapped, complete)
+	}
+	return wrapped, nil
+}
+
+// supervise checks the server until ctx ends, reconnecting it when it dies.
+func (s *mcpServer) supervise(ctx context.Context) {
+	backoff := mcpMinBackoff
+	for {
+		err := s.check(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		s.recordHealth(err, time.Now())
+
+		wait := mcpHealthInterval
+		if err != nil {
+			wait = backoff
+			backoff = min(2*backoff, mcpMaxBackoff)
+		} else {
+			backoff = mcpMinBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// check pings the server. A session that does not answer is closed and the
+// toolset made to reconnect, which restarts a stdio server's process and
+// re-initializes the session; its tools are listed afresh.
+func (s *mcpServer) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, mcpListTimeout)
+	defer cancel()
+
+	cs, err := s.clientSession(ctx)
+	if err != nil {
+		return err
+	}
+	if err = cs.Ping(ctx, nil); err == nil {
+		return nil
+	}
+	slog.Warn("MCP server not responding, reconnecting", "server", s.name, "error", err)
+	_ = cs.Close()
+
+	// Listing tools on the closed session makes the toolset reconnect.
+	_, _ = s.toolset.Tools(catalogContext{ctx})
+	s.mu.Lock()
+	next := s.session
+	s.mu.Unlock()
+	if next == cs {
+		return err
+	}
+	return next.Ping(ctx, nil)
+}
+
+// recordHealth records the outcome of a check, logging changes of state.
+func (s *mcpServer) recordHealth(err error, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checked := !s.healthSince.IsZero()
+	switch {
+	case err != nil && (!checked || s.healthErr == nil):
+		slog.Warn("MCP server down", "server", s.name, "error", err)
+		s.healthSince = now
+	case err == nil && checked && s.healthErr != nil:
+		s.restarts++
+		slog.Info("MCP server recovered", "server", s.name, "downtime", now.Sub(s.healthSince).Round(time.Second))
+		s.healthSince = now
+	case !checked:
+		s.healthSince = now
+	}
+	s.healthErr = err
+}
+
+func (s *mcpServer) health() MCPHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := MCPHealth{Server: s.name, Since: s.healthSince, Restarts: s.restarts, LastCrash: s.lastCrash, CrashedAt: s.crashedAt, Protocol: s.protocol}
+	switch {
+	case s.healthErr != nil:
+		h.Err = s.healthErr.Error()
+	case !s.healthSince.IsZero():
+		h.Healthy = true
+	}
+	return h
+}
@@ -0,0 +1,175 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restartableTransport connects to server over a new in-memory pipe each
+// time, like a stdio server whose process is restarted.
+type restartableTransport struct {
+	server *officialmcp.Server
+
+	mu       sync.Mutex
+	sessions []*officialmcp.ServerSession
+	down     bool
+}
+
+func (t *restartableTransport) Connect(ctx context.Context) (officialmcp.Connection, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.down {
+		return nil, errors.New("server is down")
+	}
+	clientTransport, serverTransport := officialmcp.NewInMemoryTransports()
+	ss, err := t.server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.sessions = append(t.sessions, ss)
+	return clientTransport.Connect(ctx)
+}
+
+// crash ends the current session and, if down, refuses to reconnect.
+func (t *restartableTransport) crash(down bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.down = down
+	_ = t.sessions[len(t.sessions)-1].Close()
+}
+
+func TestMCPServer_CheckReconnects(t *testing.T) {
+	server := officialmcp.NewServer(&officialmcp.Implementation{Name: "notes"}, nil)
+	officialmcp.AddTool(server, &officialmcp.Tool{Name: "search"}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+		return &officialmcp.CallToolResult{}, nil, nil
+	})
+	transport := &restartableTransport{server: server}
+	s, err := newMCPServer("notes", transport, nil, nil, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, MCPHealth{Server: "notes"}, s.health(), "not checked yet")
+	require.NoError(t, s.check(ctx))
+	s.recordHealth(nil, now)
+	assert.Equal(t, MCPHealth{Server: "notes", Healthy: true, Since: now, Protocol: LatestMCPProtocol}, s.health())
+
+	// The process dies and cannot be restarted yet.
+	transport.crash(true)
+	err = s.check(ctx)
+	require.Error(t, err)
+	s.recordHealth(err, now.Add(time.Minute))
+	h := s.health()
+	assert.False(t, h.Healthy)
+	assert.NotEmpty(t, h.Err)
+	assert.Equal(t, now.Add(time.Minute), h.Since)
+
+	// Agents carry on without its tools.
+	tools, err := agentToolset{s}.Tools(catalogContext{ctx})
+	assert.NoError(t, err)
+	assert.Empty(t, tools)
+
+	// Once it can start again, the next check reconnects it.
+	transport.mu.Lock()
+	transport.down = false
+	transport.mu.Unlock()
+	require.NoError(t, s.check(ctx))
+	s.recordHealth(nil, now.Add(2*time.Minute))
+	assert.Equal(t, MCPHealth{Server: "notes", Healthy: true, Since: now.Add(2 * time.Minute), Restarts: 1, Protocol: LatestMCPProtocol}, s.health())
+	assert.Len(t, transport.sessions, 2)
+
+	tools, err = agentToolset{s}.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	assert.Len(t, tools, 1)
+
+	// A crash with an immediate restart is healed within one check.
+	transport.crash(false)
+	require.NoError(t, s.check(ctx))
+	assert.Len(t, transport.sessions, 3)
+}
+
+func TestAgentMCPHealth(t *testing.T) {
+	s, _ := connectTestMCPServer(t, "weather", func(*officialmcp.Server) {}, nil, nil)
+	s.recordHealth(nil, time.Now())
+	a := &Agent{
+		cfg:        &config.Config{MCPServers: map[string]config.MCPServerConfig{"weather": {}, "broken": {}}},
+		mcpServers: map[string]*mcpServer{"weather": s},
+	}
+
+	health := a.MCPHealth()
+	require.Len(t, health, 2)
+	assert.Equal(t, MCPHealth{Server: "broken", Err: "failed to start"}, health[0])
+	assert.Equal(t, "weather", health[1].Server)
+	assert.True(t, health[1].Healthy)
+}
+
+func TestCommandTransport_Reconnects(t *testing.T) {
+	ct := &commandTransport{command: "cat"}
+	for range 2 {
+		conn, err := ct.Connect(context.Background())
+		require.NoError(t, err, "each connection starts a new process")
+		require.NoError(t, conn.Close())
+	}
+}
+
+// oldProtocolTransport is a server's side of a connection that answers
+// initialize with an older MCP revision, like a server built on an older
+// SDK.
+type oldProtocolTransport struct {
+	officialmcp.Transport
+	version string
+}
+
+func (t *oldProtocolTransport) Connect(ctx context.Context) (officialmcp.Connection, error) {
+	conn, err := t.Transport.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &oldProtocolConn{Connection: conn, version: t.version}, nil
+}
+
+type oldProtocolConn struct {
+	officialmcp.Connection
+	version string
+}
+
+func (c *oldProtocolConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	if resp, ok := msg.(*jsonrpc.Response); ok {
+		resp.Result = bytes.Replace(resp.Result, []byte(`"protocolVersion":"`+LatestMCPProtocol+`"`), []byte(`"protocolVersion":"`+c.version+`"`), 1)
+	}
+	return c.Connection.Write(ctx, msg)
+}
+
+func TestMCPServer_OlderProtocol(t *testing.T) {
+	server := officialmcp.NewServer(&officialmcp.Implementation{Name: "legacy"}, nil)
+	officialmcp.AddTool(server, &officialmcp.Tool{Name: "lookup"}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+		return &officialmcp.CallToolResult{Content: []officialmcp.Content{&officialmcp.TextContent{Text: "found"}}}, nil, nil
+	})
+	clientTransport, serverTransport := officialmcp.NewInMemoryTransports()
+	ss, err := server.Connect(context.Background(), &oldProtocolTransport{Transport: serverTransport, version: "2024-11-05"}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ss.Close() })
+	s, err := newMCPServer("legacy", clientTransport, nil, nil, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tools, err := agentToolset{s}.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	require.Len(t, tools, 1, "a server on an older revision still offers its tools")
+	cs, err := s.clientSession(ctx)
+	require.NoError(t, err)
+	res, err := cs.CallTool(ctx, &officialmcp.CallToolParams{Name: "lookup"})
+	require.NoError(t, err)
+	assert.Equal(t, "found", res.Content[0].(*officialmcp.TextContent).Text)
+	assert.Equal(t, "2024-11-05", s.health().Protocol)
+}
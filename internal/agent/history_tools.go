@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type SearchHistoryArgs struct {
+	Query string `json:"query" jsonschema:"Keywords to look for in past briefings and feed headlines."`
+}
+
+type historySessionKey struct{}
+
+// WithHistorySession returns a context whose search_history calls also
+// search the private briefings of sessionID, such as its /research
+// reports. Without it only the shared briefings of scheduled jobs are
+// searched.
+func WithHistorySession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, historySessionKey{}, sessionID)
+}
+
+func historySession(ctx context.Context) string {
+	s, _ := ctx.Value(historySessionKey{}).(string)
+	return s
+}
+
+// newHistoryTool returns the tool that searches earlier research briefings
+// and seen feed headlines (the same index as /history), so research can
+// build on prior work before searching the web.
+func (a *Agent) newHistoryTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "search_history",
+		Description: "Searches past research briefings and feed headlines by keyword. Call this before web_search to reuse or build on earlier research.",
+	}, func(ctx tool.Context, args SearchHistoryArgs) (string, error) {
+		query := strings.TrimSpace(args.Query)
+		if query == "" {
+			return "", fmt.Errorf("query is required")
+		}
+		briefings, err := a.db.SearchBriefings(ctx, historySession(ctx), query, 5)
+		if err != nil {
+			return "", err
+		}
+		headlines, err := a.db.SearchHeadlines(ctx, query, 10)
+		if err != nil {
+			return "", err
+		}
+		if len(briefings) == 0 && len(headlines) == 0 {
+			return fmt.Sprintf("No past briefings or headlines match %q.", query), nil
+		}
+		var sb strings.Builder
+		for _, b := range briefings {
+			sb.WriteString(fmt.Sprintf("Briefing #%d (%s): %s\n", b.ID, b.CreatedAt.Format("2006-01-02"), strings.Join(strings.Fields(b.Snippet), " ")))
+		}
+		for _, h := range headlines {
+			sb.WriteString(fmt.Sprintf("Headline (%s): %s %s\n", h.CreatedAt.Format("2006-01-02"), h.Title, h.URL))
+		}
+		return sb.String(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create search_history tool: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type JulesTaskArgs struct {
+	Repo string `json:"repo" jsonschema:"The repository in 'owner/repo' format."`
+	Task string `json:"task" jsonschema:"The coding task description."`
+}
+
+// JulesStart describes a Jules session the JulesTask tool created.
+type JulesStart struct {
+	Repo    string
+	Task    string
+	Session tools.JulesSession
+}
+
+type julesStartKey struct{}
+
+// WithJulesSessions returns a context whose JulesTask calls pass the
+// sessions they create to fn, so they can be followed up on.
+func WithJulesSessions(ctx context.Context, fn func(JulesStart)) context.Context {
+	return context.WithValue(ctx, julesStartKey{}, fn)
+}
+
+// newJulesTaskTool returns the tool that hands a coding task to Jules.
+func (a *Agent) newJulesTaskTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "JulesTask",
+		Description: "Delegates a coding task to the external Jules service. REQUIRED for any code modification, refactoring, or repository creation.",
+	}, func(ctx tool.Context, args JulesTaskArgs) (string, error) {
+		s, err := tools.DelegateToJules(ctx, a.config().JulesAPIKey, args.Repo, args.Task)
+		if err != nil {
+			return "", err
+		}
+		if fn, ok := ctx.Value(julesStartKey{}).(func(JulesStart)); ok {
+			fn(JulesStart{Repo: args.Repo, Task: args.Task, Session: s})
+			return fmt.Sprintf("Jules task initiated successfully. Session: %s. The user will be told when Jules opens a pull request, needs input or finishes; /jules status shows its progress.", s.Name), nil
+		}
+		return fmt.Sprintf("Jules task initiated successfully. Session: %s", s.Name), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JulesTask tool: %w", err)
+	}
+	return t, nil
+}
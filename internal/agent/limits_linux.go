@@ -0,0 +1,46 @@
+//go:build linux
+
+package agent
+
+import (
+	"errors"
+	"os"
+	"syscall"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"golang.org/x/sys/unix"
+)
+
+// setLimits applies limits to the running process pid.
+func setLimits(pid int, limits config.MCPLimitsConfig) error {
+	var errs []error
+	if limits.MemoryMB > 0 {
+		n := uint64(limits.MemoryMB) << 20
+		errs = append(errs, unix.Prlimit(pid, unix.RLIMIT_DATA, &unix.Rlimit{Cur: n, Max: n}, nil))
+	}
+	if limits.CPUSeconds > 0 {
+		// SIGXCPU at the soft limit, SIGKILL a few seconds later if the
+		// server ignores it.
+		n := uint64(limits.CPUSeconds)
+		errs = append(errs, unix.Prlimit(pid, unix.RLIMIT_CPU, &unix.Rlimit{Cur: n, Max: n + 5}, nil))
+	}
+	if limits.Nice > 0 {
+		errs = append(errs, unix.Setpriority(unix.PRIO_PROCESS, pid, limits.Nice))
+	}
+	return errors.Join(errs...)
+}
+
+// signalCause explains the signals the kernel kills a server with.
+func signalCause(state *os.ProcessState) string {
+	ws, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return ""
+	}
+	switch ws.Signal() {
+	case syscall.SIGKILL:
+		return " (by the OOM killer or a resource limit)"
+	case syscall.SIGXCPU:
+		return " (CPU time limit reached)"
+	}
+	return ""
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package agent
+
+import (
+	"errors"
+	"os"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+)
+
+func setLimits(int, config.MCPLimitsConfig) error {
+	return errors.New("resource limits are only supported on Linux")
+}
+
+func signalCause(*os.ProcessState) string { return "" }
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+)
+
+// ErrNoLogging is returned by SetMCPLogLevel for a server that does not
+// support logging.
+var ErrNoLogging = errors.New("MCP server does not support logging")
+
+// SetMCPLogLevel sets the least severe of a server's log messages it
+// sends, such as "debug" while investigating it. The level is kept when the
+// server reconnects, until the MCP servers are next restarted.
+func (a *Agent) SetMCPLogLevel(ctx context.Context, server, level string) error {
+	s, ok := a.mcpServer(server)
+	if !ok {
+		return fmt.Errorf("no MCP server %q", server)
+	}
+	if !slices.Contains(config.MCPLogLevels, level) {
+		return fmt.Errorf("invalid log level %q: must be one of %s", level, strings.Join(config.MCPLogLevels, ", "))
+	}
+	cs, err := s.clientSession(ctx)
+	if err != nil {
+		return err
+	}
+	if caps := serverCapabilities(cs); caps == nil || caps.Logging == nil {
+		return ErrNoLogging
+	}
+	if err := cs.SetLoggingLevel(ctx, &officialmcp.SetLoggingLevelParams{Level: officialmcp.LoggingLevel(level)}); err != nil {
+		return fmt.Errorf("failed to set the log level of %s: %w", server, err)
+	}
+	s.mu.Lock()
+	s.logLevel = level
+	s.mu.Unlock()
+	slog.Info("MCP server log level changed", "server", server, "level", level)
+	return nil
+}
+
+// applyLogLevel asks a new session's server for messages at the chosen
+// level, if it supports logging.
+func (s *mcpServer) applyLogLevel(ctx context.Context, cs *officialmcp.ClientSession) {
+	s.mu.Lock()
+	level := s.logLevel
+	s.mu.Unlock()
+	if caps := serverCapabilities(cs); level == "" || caps == nil || caps.Logging == nil {
+		return
+	}
+	if err := cs.SetLoggingLevel(ctx, &officialmcp.SetLoggingLevelParams{Level: officialmcp.LoggingLevel(level)}); err != nil {
+		slog.Warn("Failed to set MCP server log level", "server", s.name, "level", level, "error", err)
+	}
+}
+
+// logMessage handles notifications/message, logging the server's message
+// with its name.
+func (s *mcpServer) logMessage(ctx context.Context, req *officialmcp.LoggingMessageRequest) {
+	p := req.Params
+	attrs := []any{"server", s.name}
+	if p.Logger != "" {
+		attrs = append(attrs, "logger", p.Logger)
+	}
+	msg, ok := p.Data.(string)
+	if !ok {
+		// Structured data is kept as an attribute.
+		msg = "MCP server log"
+		attrs = append(attrs, "data", p.Data)
+	}
+	slog.Log(ctx, slogLevel(p.Level), msg, attrs...)
+}
+
+// slogLevel maps an MCP log level to slog's.
+func slogLevel(level officialmcp.LoggingLevel) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "info", "notice":
+		return slog.LevelInfo
+	case "warning":
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
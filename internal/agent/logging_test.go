@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for a log handler and a test to share.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestMCPServer_Logging(t *testing.T) {
+	var logs syncBuffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	fs := newTestMCPServer(t, "fs", func(server *officialmcp.Server) {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "scan"}, func(ctx context.Context, req *officialmcp.CallToolRequest, _ struct{}) (*officialmcp.CallToolResult, any, error) {
+			_ = req.Session.Log(ctx, &officialmcp.LoggingMessageParams{Level: "debug", Logger: "scanner", Data: "reading /data"})
+			_ = req.Session.Log(ctx, &officialmcp.LoggingMessageParams{Level: "warning", Logger: "scanner", Data: "disk nearly full"})
+			return &officialmcp.CallToolResult{}, nil, nil
+		})
+	})
+	fs.logLevel = "warning"
+	a := &Agent{mcpServers: map[string]*mcpServer{"fs": fs}}
+	ctx := context.Background()
+
+	cs, err := fs.clientSession(ctx)
+	require.NoError(t, err)
+	// The level is set in the background once the session starts.
+	require.Eventually(t, func() bool {
+		_, err := cs.CallTool(ctx, &officialmcp.CallToolParams{Name: "scan"})
+		return err == nil && strings.Contains(logs.String(), `level=WARN msg="disk nearly full" server=fs logger=scanner`)
+	}, time.Second, 10*time.Millisecond)
+	assert.NotContains(t, logs.String(), "reading /data")
+
+	require.NoError(t, a.SetMCPLogLevel(ctx, "fs", "debug"))
+	_, err = cs.CallTool(ctx, &officialmcp.CallToolParams{Name: "scan"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), `level=DEBUG msg="reading /data" server=fs`)
+	}, time.Second, 10*time.Millisecond)
+
+	assert.ErrorContains(t, a.SetMCPLogLevel(ctx, "fs", "verbose"), "invalid log level")
+	assert.ErrorContains(t, a.SetMCPLogLevel(ctx, "git", "debug"), `no MCP server "git"`)
+}
+
+func TestSlogLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelDebug, slogLevel("debug"))
+	assert.Equal(t, slog.LevelInfo, slogLevel("notice"))
+	assert.Equal(t, slog.LevelWarn, slogLevel("warning"))
+	assert.Equal(t, slog.LevelError, slogLevel("critical"))
+}
@@ -0,0 +1,272 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/stats"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/mcptoolset"
+)
+
+// mcpServer is a configured MCP server: the ADK toolset its tools are
+// called through, and the client behind it. The toolset owns the
+// connection (and reconnects it); the client records the session the
+// toolset is using so ravenbot can make its own requests on it, such as
+// prompts/list.
+//
+// mcpServer is itself the tool.Toolset given to the agents. The ADK toolset
+// asks the server for its tools on every model call; mcpServer caches the
+// list (and the prompt and resource template lists) for servers that
+// announce changes, dropping it on a list_changed notification or a
+// reconnect. Until the server first connects it serves the tools saved by
+// an earlier run, so agents need not wait for every server to start.
+type mcpServer struct {
+	name    string
+	client  *officialmcp.Client
+	toolset tool.Toolset
+	filter  tool.Predicate // selects the tools exposed, nil for all
+
+	mu        sync.Mutex
+	session   *officialmcp.ClientSession
+	tools     *[]tool.Tool // cached tools/list, nil when stale
+	prompts   *[]MCPPrompt // cached prompts/list, nil when stale
+	templates *[]string    // cached resources/templates/list URIs, nil when stale
+	gen       int          // bumped on each invalidation
+
+	saved         []tool.Tool       // tools saved by an earlier run; see loadTools
+	savedData     string            // their definitions as last saved
+	onToolsListed func(data string) // saves the definitions when they change
+
+	completer     tool.Tool // the complete tool, built for completerRefs
+	completerRefs string
+
+	subscribed map[string]bool          // watched resource URIs
+	onUpdate   func(server, uri string) // called when one changes
+
+	progress  map[string]*progressCall // tool calls by progress token
+	eliciting []*elicitingCall         // running tool calls that may ask the user
+	logLevel  string                   // asked of each session; see applyLogLevel
+	calls     chan struct{}            // tool call slots, nil for no limit
+	stop      context.CancelFunc       // ends supervision; see close
+	protocol  string                   // MCP revision the session agreed on
+	stats     *stats.Stats             // records tool calls, if set
+
+	healthErr   error     // last check's failure
+	healthSince time.Time // when the current health state began
+	restarts    int
+	lastCrash   string // why the process last died, for stdio servers
+	crashedAt   time.Time
+}
+
+var _ tool.Toolset = (*mcpServer)(nil)
+
+// newMCPServer creates the server's client and toolset. The client offers
+// the roots capability only if there are roots to list; opts may enable
+// others (such as sampling). A non-nil filter selects the tools exposed.
+func newMCPServer(name string, transport officialmcp.Transport, opts *officialmcp.ClientOptions, roots []*officialmcp.Root, filter tool.Predicate) (*mcpServer, error) {
+	var clientOpts officialmcp.ClientOptions
+	if opts != nil {
+		clientOpts = *opts
+	}
+	if clientOpts.Capabilities == nil {
+		clientOpts.Capabilities = &officialmcp.ClientCapabilities{}
+		if len(roots) > 0 {
+			clientOpts.Capabilities.RootsV2 = &officialmcp.RootCapabilities{ListChanged: true}
+		}
+	}
+
+	s := &mcpServer{name: name, filter: filter, subscribed: make(map[string]bool), progress: make(map[string]*progressCall)}
+	clientOpts.ToolListChangedHandler = func(context.Context, *officialmcp.ToolListChangedRequest) {
+		slog.Info("MCP server tools changed", "server", name)
+		s.invalidate()
+	}
+	clientOpts.PromptListChangedHandler = func(context.Context, *officialmcp.PromptListChangedRequest) {
+		slog.Info("MCP server prompts changed", "server", name)
+		s.invalidate()
+	}
+	// ravenbot watches individual resources, not the list; a change only
+	// drops the cached resource templates.
+	clientOpts.ResourceListChangedHandler = func(context.Context, *officialmcp.ResourceListChangedRequest) {
+		slog.Debug("MCP server resources changed", "server", name)
+		s.invalidate()
+	}
+	clientOpts.ResourceUpdatedHandler = s.resourceUpdated
+	clientOpts.ProgressNotificationHandler = s.progressNotified
+	clientOpts.ElicitationHandler = s.elicit
+	clientOpts.LoggingMessageHandler = s.logMessage
+	s.client = officialmcp.NewClient(&officialmcp.Implementation{Name: AppName}, &clientOpts)
+	s.client.AddSendingMiddleware(s.trackSession, s.requestProgress, s.trackElicitors, s.convertContent)
+	s.client.AddRoots(roots...)
+
+	ts, err := mcptoolset.New(mcptoolset.Config{Client: s.client, Transport: transport, ToolFilter: filter})
+	if err != nil {
+		return nil, err
+	}
+	s.toolset = ts
+	return s, nil
+}
+
+// trackSession remembers the session of each request the toolset sends;
+// after a reconnect that is the new session, whose lists may differ and
+// which has none of the old session's resource subscriptions. Each new
+// session is given the server's log level, and its protocol revision is
+// recorded.
+func (s *mcpServer) trackSession(next officialmcp.MethodHandler) officialmcp.MethodHandler {
+	return func(ctx context.Context, method string, req officialmcp.Request) (officialmcp.Result, error) {
+		if cs, ok := req.GetSession().(*officialmcp.ClientSession); ok && method != "initialize" {
+			s.mu.Lock()
+			if s.session != cs {
+				reconnected := s.session != nil
+				if reconnected {
+					s.invalidateLocked()
+				}
+				if init := cs.InitializeResult(); init != nil {
+					s.protocol = init.ProtocolVersion
+					slog.Debug("MCP server connected", "server", s.name, "protocol", s.protocol)
+				}
+				go func() {
+					ctx, cancel := context.WithTimeout(context.Background(), mcpListTimeout)
+					defer cancel()
+					s.applyLogLevel(ctx, cs)
+					if reconnected {
+						s.resubscribe(ctx, cs)
+					}
+				}()
+			}
+			s.session = cs
+			s.mu.Unlock()
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// Name implements tool.Toolset.
+func (s *mcpServer) Name() string { return s.toolset.Name() }
+
+// Tools implements tool.Toolset, serving the cached list while it is fresh,
+// or the saved one while the server is starting.
+func (s *mcpServer) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	s.mu.Lock()
+	if s.tools != nil {
+		defer s.mu.Unlock()
+		return *s.tools, nil
+	}
+	if s.saved != nil && s.session == nil && s.healthErr == nil {
+		defer s.mu.Unlock()
+		return s.saved, nil
+	}
+	s.mu.Unlock()
+	return s.listTools(ctx)
+}
+
+// listTools asks the server for its tools, caching the list if the server
+// announces changes and saving their definitions.
+func (s *mcpServer) listTools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	s.mu.Lock()
+	gen := s.gen
+	s.mu.Unlock()
+
+	tools, err := s.toolset.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if gen == s.gen && announcesChanges(s.session, func(c *officialmcp.ServerCapabilities) bool {
+		return c.Tools != nil && c.Tools.ListChanged
+	}) {
+		s.tools = &tools
+	}
+	s.mu.Unlock()
+	s.saveTools(tools)
+	return tools, nil
+}
+
+// invalidate drops the cached lists.
+func (s *mcpServer) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.invalidateLocked()
+}
+
+func (s *mcpServer) invalidateLocked() {
+	s.tools, s.prompts, s.templates = nil, nil, nil
+	s.gen++
+}
+
+// announcesChanges reports whether the server behind cs promised
+// list_changed notifications for the list has checks; only then may the
+// list be cached.
+func announcesChanges(cs *officialmcp.ClientSession, has func(*officialmcp.ServerCapabilities) bool) bool {
+	if cs == nil {
+		return false
+	}
+	init := cs.InitializeResult()
+	return init != nil && init.Capabilities != nil && has(init.Capabilities)
+}
+
+// clientSession returns the live session, connecting through the toolset
+// if it has not connected yet.
+func (s *mcpServer) clientSession(ctx context.Context) (*officialmcp.ClientSession, error) {
+	s.mu.Lock()
+	cs := s.session
+	s.mu.Unlock()
+	if cs != nil {
+		return cs, nil
+	}
+	// Listing tools connects. A server without tools fails the listing
+	// but still leaves a session.
+	_, err := s.toolset.Tools(catalogContext{ctx})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session != nil {
+		return s.session, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("MCP server %q has no session", s.name)
+}
+
+// httpTransport returns the transport for a remote server. Streamable HTTP
+// (the current spec: one endpoint taking POSTs answered with JSON or an SSE
+// stream, an Mcp-Session-Id header, and resumable streams) is the default.
+func httpTransport(serverCfg config.MCPServerConfig, client *http.Client) officialmcp.Transport {
+	if serverCfg.Transport == config.MCPTransportSSE {
+		return &officialmcp.SSEClientTransport{Endpoint: serverCfg.Command, HTTPClient: client}
+	}
+	return &officialmcp.StreamableClientTransport{Endpoint: serverCfg.Command, HTTPClient: client}
+}
+
+// mcpRoots resolves a server's configured root directories to file URIs,
+// expanding them with expand. Directories that do not exist are logged and
+// left out, so a server is never pointed at a path it cannot open.
+func mcpRoots(server string, paths []string, expand func(string) string) []*officialmcp.Root {
+	var roots []*officialmcp.Root
+	for _, p := range paths {
+		dir, err := filepath.Abs(expand(p))
+		if err == nil {
+			var info os.FileInfo
+			if info, err = os.Stat(dir); err == nil && !info.IsDir() {
+				err = fmt.Errorf("not a directory")
+			}
+		}
+		if err != nil {
+			slog.Warn("Skipping MCP root", "server", server, "root", p, "error", err)
+			continue
+		}
+		uri := url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}
+		roots = append(roots, &officialmcp.Root{URI: uri.String(), Name: filepath.Base(dir)})
+	}
+	return roots
+}
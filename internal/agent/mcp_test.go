@@ -0,0 +1,197 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+)
+
+// newTestMCPServer connects an mcpServer to an in-memory MCP server that
+// setup configures.
+func newTestMCPServer(t *testing.T, name string, setup func(*officialmcp.Server)) *mcpServer {
+	t.Helper()
+	s, _ := connectTestMCPServer(t, name, setup, nil, nil)
+	return s
+}
+
+// connectTestMCPServer is newTestMCPServer with client options and roots,
+// also returning the server's side of the session.
+func connectTestMCPServer(t *testing.T, name string, setup func(*officialmcp.Server), opts *officialmcp.ClientOptions, roots []*officialmcp.Root) (*mcpServer, *officialmcp.ServerSession) {
+	t.Helper()
+	server := officialmcp.NewServer(&officialmcp.Implementation{Name: name}, nil)
+	setup(server)
+
+	clientTransport, serverTransport := officialmcp.NewInMemoryTransports()
+	ss, err := server.Connect(context.Background(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ss.Close() })
+
+	s, err := newMCPServer(name, clientTransport, opts, roots, nil)
+	require.NoError(t, err)
+	return s, ss
+}
+
+func TestMCPRoots(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0o600))
+	expand := func(s string) string { return strings.ReplaceAll(s, "$DATA_DIR", dir) }
+
+	roots := mcpRoots("fs", []string{"$DATA_DIR", file, filepath.Join(dir, "missing")}, expand)
+	require.Len(t, roots, 1, "files and missing directories are skipped")
+	assert.Equal(t, "file://"+filepath.ToSlash(dir), roots[0].URI)
+	assert.Equal(t, filepath.Base(dir), roots[0].Name)
+}
+
+func TestMCPServer_Roots(t *testing.T) {
+	roots := []*officialmcp.Root{{URI: "file:///srv/notes", Name: "notes"}}
+	client, ss := connectTestMCPServer(t, "fs", func(*officialmcp.Server) {}, nil, roots)
+	_, err := client.clientSession(context.Background())
+	require.NoError(t, err)
+
+	assert.NotNil(t, ss.InitializeParams().Capabilities.RootsV2, "roots capability is offered")
+	res, err := ss.ListRoots(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, res.Roots, 1)
+	assert.Equal(t, "file:///srv/notes", res.Roots[0].URI)
+}
+
+func TestMCPServer_NoRoots(t *testing.T) {
+	sampler, _, _ := newTestSampler(config.MCPSamplingConfig{})
+	client, ss := connectTestMCPServer(t, "plain", func(*officialmcp.Server) {},
+		&officialmcp.ClientOptions{CreateMessageHandler: sampler.createMessage}, nil)
+	_, err := client.clientSession(context.Background())
+	require.NoError(t, err)
+
+	caps := ss.InitializeParams().Capabilities
+	assert.Nil(t, caps.RootsV2, "no roots, no capability")
+	assert.NotNil(t, caps.Sampling, "other capabilities are still inferred")
+}
+
+func TestMCPServer_ListChanged(t *testing.T) {
+	noop := func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+		return &officialmcp.CallToolResult{}, nil, nil
+	}
+	noPrompt := func(context.Context, *officialmcp.GetPromptRequest) (*officialmcp.GetPromptResult, error) {
+		return &officialmcp.GetPromptResult{}, nil
+	}
+	var (
+		server *officialmcp.Server
+		lists  atomic.Int32
+	)
+	s := newTestMCPServer(t, "notes", func(srv *officialmcp.Server) {
+		server = srv
+		officialmcp.AddTool(srv, &officialmcp.Tool{Name: "search"}, noop)
+		srv.AddPrompt(&officialmcp.Prompt{Name: "summarize"}, noPrompt)
+		srv.AddReceivingMiddleware(func(next officialmcp.MethodHandler) officialmcp.MethodHandler {
+			return func(ctx context.Context, method string, req officialmcp.Request) (officialmcp.Result, error) {
+				if method == "tools/list" || method == "prompts/list" {
+					lists.Add(1)
+				}
+				return next(ctx, method, req)
+			}
+		})
+	})
+	ctx := catalogContext{context.Background()}
+
+	toolNames := func() []string {
+		tools, err := s.Tools(ctx)
+		require.NoError(t, err)
+		var names []string
+		for _, info := range toolInfos(tools) {
+			names = append(names, info.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"search"}, toolNames())
+	prompts, err := s.listPrompts(ctx)
+	require.NoError(t, err)
+	assert.Len(t, prompts, 1)
+	listed := lists.Load()
+
+	assert.Equal(t, []string{"search"}, toolNames())
+	_, err = s.listPrompts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, listed, lists.Load(), "unchanged lists are served from the cache")
+
+	officialmcp.AddTool(server, &officialmcp.Tool{Name: "write"}, noop)
+	require.Eventually(t, func() bool { return len(toolNames()) == 2 }, 5*time.Second, 10*time.Millisecond,
+		"a tools/list_changed notification refreshes the tools")
+
+	server.AddPrompt(&officialmcp.Prompt{Name: "outline"}, noPrompt)
+	require.Eventually(t, func() bool {
+		prompts, err := s.listPrompts(ctx)
+		return err == nil && len(prompts) == 2
+	}, 5*time.Second, 10*time.Millisecond, "a prompts/list_changed notification refreshes the prompts")
+}
+
+func TestHTTPTransport(t *testing.T) {
+	server := officialmcp.NewServer(&officialmcp.Implementation{Name: "remote"}, nil)
+	officialmcp.AddTool(server, &officialmcp.Tool{Name: "lookup"}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+		return &officialmcp.CallToolResult{}, nil, nil
+	})
+	getServer := func(*http.Request) *officialmcp.Server { return server }
+
+	for _, tc := range []struct {
+		name      string
+		transport string
+		handler   http.Handler
+	}{
+		{"streamable by default", "", officialmcp.NewStreamableHTTPHandler(getServer, nil)},
+		{"legacy sse", config.MCPTransportSSE, officialmcp.NewSSEHandler(getServer, nil)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(tc.handler)
+			defer ts.Close()
+
+			transport := httpTransport(config.MCPServerConfig{Command: ts.URL, Transport: tc.transport}, ts.Client())
+			s, err := newMCPServer("remote", transport, nil, nil, nil)
+			require.NoError(t, err)
+			tools, err := s.Tools(catalogContext{context.Background()})
+			require.NoError(t, err)
+			require.Len(t, tools, 1)
+			assert.Equal(t, "lookup", tools[0].Name())
+
+			cs, err := s.clientSession(context.Background())
+			require.NoError(t, err)
+			if tc.transport == "" {
+				assert.NotEmpty(t, cs.ID(), "streamable HTTP sessions carry Mcp-Session-Id")
+			}
+			require.NoError(t, cs.Close())
+		})
+	}
+}
+
+func TestMCPServer_ToolFilter(t *testing.T) {
+	server := officialmcp.NewServer(&officialmcp.Implementation{Name: "github"}, nil)
+	for _, name := range []string{"get_issue", "list_pulls", "create_issue", "delete_file"} {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: name}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+			return &officialmcp.CallToolResult{}, nil, nil
+		})
+	}
+	cfg := config.MCPServerConfig{ToolAllowlist: []string{"get_*", "list_*", "create_issue"}, ToolDenylist: []string{"create_*"}}
+	s, err := newMCPServer("github", &restartableTransport{server: server}, nil, nil,
+		func(_ agent.ReadonlyContext, t tool.Tool) bool { return cfg.ExposesTool(t.Name()) })
+	require.NoError(t, err)
+
+	tools, err := s.Tools(catalogContext{context.Background()})
+	require.NoError(t, err)
+	var names []string
+	for _, info := range toolInfos(tools) {
+		names = append(names, info.Name)
+	}
+	assert.ElementsMatch(t, []string{"get_issue", "list_pulls"}, names)
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// authClient returns a copy of client that adds auth's credentials to the
+// requests it sends to endpoint's host. Other hosts (such as redirect
+// targets) never see them. Credential values are expanded with expand.
+func authClient(ctx context.Context, auth *config.MCPAuthConfig, endpoint string, client *http.Client, expand func(string) string) (*http.Client, error) {
+	if auth == nil {
+		return client, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var rt http.RoundTripper
+	switch auth.Type {
+	case config.MCPAuthBearer:
+		token := expand(auth.Token)
+		rt = &authTransport{host: u.Host, base: base, apply: func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}}
+	case config.MCPAuthHeader:
+		header, value := auth.Header, expand(auth.Value)
+		rt = &authTransport{host: u.Host, base: base, apply: func(r *http.Request) {
+			r.Header.Set(header, value)
+		}}
+	case config.MCPAuthBasic:
+		user, pass := expand(auth.Username), expand(auth.Password)
+		rt = &authTransport{host: u.Host, base: base, apply: func(r *http.Request) {
+			r.SetBasicAuth(user, pass)
+		}}
+	case config.MCPAuthOAuth:
+		cc := clientcredentials.Config{
+			ClientID:     expand(auth.ClientID),
+			ClientSecret: expand(auth.ClientSecret),
+			TokenURL:     expand(auth.TokenURL),
+			Scopes:       auth.Scopes,
+		}
+		// Tokens are fetched with the same (safe) client and reused until
+		// they expire.
+		tokens := cc.TokenSource(context.WithValue(ctx, oauth2.HTTPClient, client))
+		rt = &authTransport{host: u.Host, base: base, token: tokens}
+	default:
+		return client, nil
+	}
+	c := *client
+	c.Transport = rt
+	return &c, nil
+}
+
+// authTransport sets credentials on requests to host, either with apply or
+// as a bearer token from token.
+type authTransport struct {
+	host  string
+	base  http.RoundTripper
+	apply func(*http.Request)
+	token oauth2.TokenSource
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	if err := t.authorize(req); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// authorize sets the credentials on req.
+func (t *authTransport) authorize(req *http.Request) error {
+	if t.token == nil {
+		t.apply(req)
+		return nil
+	}
+	tok, err := t.token.Token()
+	if err != nil {
+		return err
+	}
+	tok.SetAuthHeader(req)
+	return nil
+}
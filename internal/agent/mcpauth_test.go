@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthClient(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer srv.Close()
+	expand := func(s string) string { return strings.ReplaceAll(s, "$SECRET", "s3cret") }
+
+	for _, tc := range []struct {
+		name   string
+		auth   *config.MCPAuthConfig
+		header string
+		want   string
+	}{
+		{"bearer", &config.MCPAuthConfig{Type: config.MCPAuthBearer, Token: "$SECRET"}, "Authorization", "Bearer s3cret"},
+		{"api key", &config.MCPAuthConfig{Type: config.MCPAuthHeader, Header: "X-API-Key", Value: "$SECRET"}, "X-API-Key", "s3cret"},
+		{"basic", &config.MCPAuthConfig{Type: config.MCPAuthBasic, Username: "raven", Password: "$SECRET"}, "Authorization", "Basic cmF2ZW46czNjcmV0"},
+		{"none", nil, "Authorization", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := authClient(context.Background(), tc.auth, srv.URL, srv.Client(), expand)
+			require.NoError(t, err)
+			resp, err := client.Get(srv.URL + "/mcp")
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			assert.Equal(t, tc.want, got.Get(tc.header))
+		})
+	}
+}
+
+func TestAuthClient_OtherHosts(t *testing.T) {
+	var got string
+	other := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	}))
+	defer other.Close()
+
+	auth := &config.MCPAuthConfig{Type: config.MCPAuthBearer, Token: "secret"}
+	client, err := authClient(context.Background(), auth, "https://mcp.example.com/mcp", other.Client(), func(s string) string { return s })
+	require.NoError(t, err)
+	resp, err := client.Get(other.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Empty(t, got, "credentials only go to the server's host")
+}
+
+func TestAuthClient_OAuth(t *testing.T) {
+	var tokenRequests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "tok-1", "token_type": "Bearer", "expires_in": 3600})
+	})
+	var got string
+	mux.HandleFunc("/mcp", func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	auth := &config.MCPAuthConfig{Type: config.MCPAuthOAuth, TokenURL: srv.URL + "/token", ClientID: "raven", ClientSecret: "secret"}
+	client, err := authClient(context.Background(), auth, srv.URL+"/mcp", srv.Client(), func(s string) string { return s })
+	require.NoError(t, err)
+	for range 2 {
+		resp, err := client.Get(srv.URL + "/mcp")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	assert.Equal(t, "Bearer tok-1", got)
+	assert.Equal(t, 1, tokenRequests, "the token is reused until it expires")
+}
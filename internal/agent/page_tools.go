@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type FetchPageArgs struct {
+	URL string `json:"url" jsonschema:"The URL of the article or web page."`
+}
+
+// newFetchPageTool returns the tool that reads a web page's main content,
+// so the model sees the article rather than its menus and banners.
+func newFetchPageTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "fetch_page",
+		Description: "Reads a web page and returns its main content as text, without navigation, ads, cookie banners and comments, with its title, author, site and publication date (20,000 characters at most). Use it to read an article or page found by a search; use fetch_pdf for PDFs and crawl_site for whole sites.",
+	}, func(ctx tool.Context, args FetchPageArgs) (string, error) {
+		a, err := tools.ScrapePage(ctx, args.URL)
+		if err != nil {
+			var status tools.PageStatusError
+			if errors.As(err, &status) && archiveWorthy(status.StatusCode) {
+				return "", fmt.Errorf("%w; archive_fetch may have a copy", err)
+			}
+			return "", err
+		}
+		out := formatArticle(a)
+		if a.Paywalled {
+			out += "\n\n[The page marks its content as paywalled, so this may be only a teaser; archive_fetch may have the full text.]"
+		}
+		return out, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fetch_page tool: %w", err)
+	}
+	return t, nil
+}
+
+// archiveWorthy reports whether a page answering with status may still be
+// read from an archived copy: it is gone, or kept from us.
+func archiveWorthy(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusForbidden, http.StatusNotFound, http.StatusGone, http.StatusUnavailableForLegalReasons:
+		return true
+	}
+	return false
+}
+
+type ArchiveFetchArgs struct {
+	URL string `json:"url" jsonschema:"The original URL of the page, not a web.archive.org link."`
+}
+
+// newArchiveFetchTool returns the tool that reads a page's latest Wayback
+// Machine snapshot, for sources that have gone or are paywalled.
+func newArchiveFetchTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "archive_fetch",
+		Description: "Reads the most recent Wayback Machine (web.archive.org) snapshot of a page and returns its main content like fetch_page, with when it was captured. Use it when fetch_page fails with a 404, 403 or paywall, or a linked source has moved; cite the original URL and mention the snapshot date.",
+	}, func(ctx tool.Context, args ArchiveFetchArgs) (string, error) {
+		page, err := tools.ArchiveFetch(ctx, args.URL)
+		if err != nil {
+			return "", err
+		}
+		captured := "at an unknown date"
+		if !page.Captured.IsZero() {
+			captured = "on " + page.Captured.Format("2006-01-02 15:04 MST")
+		}
+		return fmt.Sprintf("Wayback Machine snapshot captured %s: %s\n%s", captured, page.SnapshotURL, formatArticle(page.Article)), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive_fetch tool: %w", err)
+	}
+	return t, nil
+}
+
+// formatArticle lays out an extracted article for the model: its title,
+// source and metadata, then its text.
+func formatArticle(a tools.Article) string {
+	var sb strings.Builder
+	if a.Title != "" {
+		sb.WriteString("# " + a.Title + "\n")
+	}
+	sb.WriteString("Source: " + a.URL + "\n")
+	var meta []string
+	if a.Byline != "" {
+		meta = append(meta, "By "+a.Byline)
+	}
+	if a.SiteName != "" {
+		meta = append(meta, a.SiteName)
+	}
+	if !a.Published.IsZero() {
+		meta = append(meta, "Published "+a.Published.Format("2006-01-02"))
+	}
+	if len(meta) > 0 {
+		sb.WriteString(strings.Join(meta, " · ") + "\n")
+	}
+	if a.Description != "" && !strings.HasPrefix(a.Text, a.Description) {
+		sb.WriteString("Summary: " + a.Description + "\n")
+	}
+	sb.WriteString("\n" + a.Text)
+	return sb.String()
+}
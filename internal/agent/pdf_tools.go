@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// pdfCacheSize is how many documents fetch_pdf keeps, so reading the later
+// chunks of a PDF doesn't download it again.
+const pdfCacheSize = 4
+
+type FetchPDFArgs struct {
+	URL   string `json:"url" jsonschema:"The URL of the PDF."`
+	Chunk int    `json:"chunk,omitempty" jsonschema:"Which chunk of the text to return, from 1 (the default)."`
+}
+
+// pdfCache holds the chunked text of the PDFs fetched last, most recent
+// last.
+type pdfCache struct {
+	mu   sync.Mutex
+	urls []string
+	docs [][]string
+}
+
+func (c *pdfCache) get(url string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, u := range c.urls {
+		if u == url {
+			return c.docs[i], true
+		}
+	}
+	return nil, false
+}
+
+func (c *pdfCache) put(url string, chunks []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.urls) == pdfCacheSize {
+		c.urls, c.docs = c.urls[1:], c.docs[1:]
+	}
+	c.urls, c.docs = append(c.urls, url), append(c.docs, chunks)
+}
+
+// newPDFTool returns the tool that reads the text of PDFs, which research
+// sources such as reports and papers often are.
+func newPDFTool() (tool.Tool, error) {
+	cache := &pdfCache{}
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "fetch_pdf",
+		Description: "Downloads a PDF and returns its text in chunks of about 12,000 characters, starting with chunk 1 and saying how many there are. Use for sources linked as PDFs; ask for later chunks only if needed.",
+	}, func(ctx tool.Context, args FetchPDFArgs) (string, error) {
+		url := strings.TrimSpace(args.URL)
+		if url == "" {
+			return "", fmt.Errorf("url is required")
+		}
+		chunks, ok := cache.get(url)
+		if !ok {
+			text, err := tools.FetchPDF(ctx, url)
+			if err != nil {
+				return "", err
+			}
+			chunks = tools.ChunkText(text, tools.DefaultChunkChars)
+			cache.put(url, chunks)
+		}
+		n := max(args.Chunk, 1)
+		if n > len(chunks) {
+			return "", fmt.Errorf("chunk %d is out of range: the PDF has %d", n, len(chunks))
+		}
+		return fmt.Sprintf("Chunk %d of %d of %s\n\n%s", n, len(chunks), url, chunks[n-1]), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fetch_pdf tool: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,230 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+)
+
+// Stdio servers' stderr goes to mcpLogDir/<server>.log, next to ravenbot's
+// own logs, rotated at mcpLogMaxBytes with mcpLogBackups older files kept.
+const (
+	mcpLogDir      = "logs/mcp"
+	mcpLogMaxBytes = 5 << 20
+	mcpLogBackups  = 3
+)
+
+// stderrTailBytes is how much of a server's last stderr output a crash
+// report quotes.
+const stderrTailBytes = 1024
+
+// commandTransport starts a new process for each connection, so a stdio
+// server that exited can be reconnected (an exec.Cmd only runs once).
+// Each process gets the server's resource limits and writes its stderr to
+// stderr; when one dies on its own, exited is told why.
+type commandTransport struct {
+	command string
+	args    []string
+	env     []string // nil inherits ravenbot's environment
+
+	limits *config.MCPLimitsConfig
+	stderr *serverLog
+	exited func(reason string)
+}
+
+func (t *commandTransport) Connect(ctx context.Context) (officialmcp.Connection, error) {
+	cmd := exec.Command(t.command, t.args...)
+	cmd.Env = t.env
+	if t.stderr != nil {
+		cmd.Stderr = t.stderr
+	}
+	conn, err := (&officialmcp.CommandTransport{Command: cmd}).Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t.limits != nil {
+		// The process has only just started; a limit set now still stops
+		// it from outgrowing its budget.
+		if err := setLimits(cmd.Process.Pid, *t.limits); err != nil {
+			slog.Warn("Failed to limit MCP server process", "command", t.command, "error", err)
+		}
+	}
+	return &processConn{Connection: conn, cmd: cmd, stderr: t.stderr, exited: t.exited}, nil
+}
+
+// processConn is the connection to a stdio server's process, noticing when
+// the process dies rather than being shut down.
+type processConn struct {
+	officialmcp.Connection
+	cmd    *exec.Cmd
+	stderr *serverLog
+	exited func(reason string)
+
+	mu      sync.Mutex
+	closing bool // Close was called
+	died    bool // reading failed before Close
+	once    sync.Once
+}
+
+func (c *processConn) Read(ctx context.Context) (msg jsonrpc.Message, err error) {
+	msg, err = c.Connection.Read(ctx)
+	if err != nil && ctx.Err() == nil {
+		c.mu.Lock()
+		if !c.closing {
+			c.died = true
+		}
+		c.mu.Unlock()
+	}
+	return msg, err
+}
+
+func (c *processConn) Close() error {
+	c.mu.Lock()
+	c.closing = true
+	died := c.died
+	c.mu.Unlock()
+
+	var err error
+	c.once.Do(func() {
+		// Close waits for the process to exit, so its state is known.
+		err = c.Connection.Close()
+		if !died || c.exited == nil {
+			return
+		}
+		var tail string
+		if c.stderr != nil {
+			tail = c.stderr.Tail()
+		}
+		c.exited(exitReason(c.cmd.ProcessState, tail))
+	})
+	return err
+}
+
+// exitReason describes how a server's process ended, with the end of its
+// stderr output.
+func exitReason(state *os.ProcessState, stderrTail string) string {
+	reason := "connection lost"
+	if state != nil {
+		reason = state.String() + signalCause(state)
+	}
+	if stderrTail = strings.TrimSpace(stderrTail); stderrTail != "" {
+		if i := strings.LastIndexByte(stderrTail, '\n'); i >= 0 && len(stderrTail)-i > 1 {
+			// The last line usually holds the error.
+			reason += ": " + stderrTail[i+1:]
+		} else {
+			reason += ": " + stderrTail
+		}
+	}
+	return reason
+}
+
+// serverLog is a server's stderr log file, rotated when it grows past
+// max, keeping the end of its output in memory for crash reports. One
+// serverLog serves every process of the server, so restarts append.
+type serverLog struct {
+	path    string
+	max     int64
+	backups int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+	tail []byte
+}
+
+func newServerLog(path string) *serverLog {
+	return &serverLog{path: path, max: mcpLogMaxBytes, backups: mcpLogBackups}
+}
+
+// serverLogPath is the stderr log of the named server.
+func serverLogPath(server string) string {
+	return filepath.Join(mcpLogDir, strings.ReplaceAll(server, string(filepath.Separator), "_")+".log")
+}
+
+func (l *serverLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tail = append(l.tail, p...)
+	if len(l.tail) > stderrTailBytes {
+		l.tail = l.tail[len(l.tail)-stderrTailBytes:]
+	}
+
+	if l.f != nil && l.size+int64(len(p)) > l.max {
+		if err := l.rotate(); err != nil {
+			slog.Warn("Failed to rotate MCP server log", "path", l.path, "error", err)
+		}
+	}
+	if l.f == nil {
+		if err := l.open(); err != nil {
+			// Losing the log must not break the server.
+			return len(p), nil
+		}
+	}
+	n, err := l.f.Write(p)
+	l.size += int64(n)
+	if err != nil {
+		return len(p), nil
+	}
+	return n, nil
+}
+
+func (l *serverLog) open() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l.f, l.size = f, info.Size()
+	return nil
+}
+
+// rotate renames the log to .1, shifting older ones up and dropping the
+// oldest.
+func (l *serverLog) rotate() error {
+	_ = l.f.Close()
+	l.f = nil
+	for i := l.backups - 1; i >= 1; i-- {
+		old := fmt.Sprintf("%s.%d", l.path, i)
+		if _, err := os.Stat(old); err == nil {
+			if err := os.Rename(old, fmt.Sprintf("%s.%d", l.path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	if l.backups < 1 {
+		return os.Remove(l.path)
+	}
+	return os.Rename(l.path, l.path+".1")
+}
+
+// Tail returns the end of the output written so far.
+func (l *serverLog) Tail() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return string(l.tail)
+}
+
+// recordCrash records why the server's process died.
+func (s *mcpServer) recordCrash(reason string) {
+	slog.Warn("MCP server process died", "server", s.name, "reason", reason)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastCrash, s.crashedAt = reason, time.Now()
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connectUntilExit starts t's process and reads until it exits, returning
+// the crash reason reported.
+func connectUntilExit(t *testing.T, ct *commandTransport) string {
+	t.Helper()
+	crashes := make(chan string, 1)
+	ct.exited = func(reason string) { crashes <- reason }
+
+	ctx := context.Background()
+	conn, err := ct.Connect(ctx)
+	require.NoError(t, err)
+	_, err = conn.Read(ctx)
+	require.Error(t, err)
+	// The SDK closes a connection whose read failed; Close reports the
+	// exit status.
+	_ = conn.Close()
+
+	select {
+	case reason := <-crashes:
+		return reason
+	case <-time.After(5 * time.Second):
+		t.Fatal("no crash reported")
+		return ""
+	}
+}
+
+func TestCommandTransport_Crash(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("needs /bin/sh")
+	}
+	log := newServerLog(filepath.Join(t.TempDir(), "broken.log"))
+	reason := connectUntilExit(t, &commandTransport{
+		command: "/bin/sh",
+		args:    []string{"-c", "echo starting >&2; echo 'fatal: no token' >&2; exit 3"},
+		stderr:  log,
+	})
+	assert.Equal(t, "exit status 3: fatal: no token", reason)
+
+	data, err := os.ReadFile(log.path)
+	require.NoError(t, err)
+	assert.Equal(t, "starting\nfatal: no token\n", string(data))
+}
+
+func TestCommandTransport_CloseIsNotACrash(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("needs /bin/sh")
+	}
+	ct := &commandTransport{command: "/bin/sh", args: []string{"-c", "cat >/dev/null"}}
+	ct.exited = func(reason string) { t.Errorf("shutdown reported as a crash: %s", reason) }
+
+	conn, err := ct.Connect(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
+
+func TestCommandTransport_Limits(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resource limits are Linux only")
+	}
+	log := newServerLog(filepath.Join(t.TempDir(), "limited.log"))
+	// The limits are set just after the process starts.
+	connectUntilExit(t, &commandTransport{
+		command: "/bin/sh",
+		args:    []string{"-c", "sleep 0.3; grep -e 'Max cpu time' -e 'Max data size' /proc/self/limits >&2; exit 1"},
+		limits:  &config.MCPLimitsConfig{MemoryMB: 512, CPUSeconds: 60},
+		stderr:  log,
+	})
+	limits := log.Tail()
+	assert.Regexp(t, `Max cpu time\s+60\s+65\s+seconds`, limits)
+	assert.Regexp(t, `Max data size\s+536870912\s+536870912\s+bytes`, limits)
+}
+
+func TestServerLog_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	log := newServerLog(filepath.Join(dir, "fs.log"))
+	log.max, log.backups = 10, 2
+
+	for _, line := range []string{"one 1234\n", "two 1234\n", "three 12\n", "four 123\n"} {
+		_, err := log.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "four 123\n", read("fs.log"))
+	assert.Equal(t, "three 12\n", read("fs.log.1"))
+	assert.Equal(t, "two 1234\n", read("fs.log.2"))
+	assert.NoFileExists(t, filepath.Join(dir, "fs.log.3"))
+}
+
+func TestServerLog_Tail(t *testing.T) {
+	log := newServerLog(filepath.Join(t.TempDir(), "fs.log"))
+	_, _ = log.Write([]byte(strings.Repeat("x", stderrTailBytes)))
+	_, _ = log.Write([]byte("panic: out of memory\n"))
+	tail := log.Tail()
+	assert.Len(t, tail, stderrTailBytes)
+	assert.True(t, strings.HasSuffix(tail, "panic: out of memory\n"))
+}
+
+func TestExitReason(t *testing.T) {
+	assert.Equal(t, "connection lost", exitReason(nil, ""))
+	assert.Equal(t, "connection lost: boom", exitReason(nil, "boom\n"))
+}
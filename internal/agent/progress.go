@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// mcpProgressInterval is the minimum time between two progress reports of
+// the same tool call; servers may send one per chunk cloned or row read.
+const mcpProgressInterval = 15 * time.Second
+
+// MCPProgress is a progress update from an MCP tool call.
+type MCPProgress struct {
+	Server   string
+	Tool     string
+	Message  string  // the server's description, if any
+	Progress float64 // work done so far
+	Total    float64 // total work; zero if unknown
+}
+
+type progressKey struct{}
+
+// WithMCPProgress returns a context whose MCP tool calls ask their servers
+// for progress notifications and report them to fn, at most once every
+// mcpProgressInterval per call.
+func WithMCPProgress(ctx context.Context, fn func(MCPProgress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// progressCall is a tool call waiting for progress notifications.
+type progressCall struct {
+	tool string
+	fn   func(MCPProgress)
+	last time.Time // when progress was last reported
+}
+
+var progressTokens atomic.Int64
+
+// requestProgress gives tools/call requests made with WithMCPProgress a
+// progress token, routing the server's notifications for it to the
+// context's function until the call returns.
+func (s *mcpServer) requestProgress(next officialmcp.MethodHandler) officialmcp.MethodHandler {
+	return func(ctx context.Context, method string, req officialmcp.Request) (officialmcp.Result, error) {
+		fn, _ := ctx.Value(progressKey{}).(func(MCPProgress))
+		params, ok := req.GetParams().(*officialmcp.CallToolParams)
+		if method != "tools/call" || fn == nil || !ok {
+			return next(ctx, method, req)
+		}
+		token := fmt.Sprintf("%s-%d", AppName, progressTokens.Add(1))
+		// SetProgressToken only sets the token in an existing Meta.
+		if params.Meta == nil {
+			params.Meta = officialmcp.Meta{}
+		}
+		params.SetProgressToken(token)
+		s.mu.Lock()
+		s.progress[token] = &progressCall{tool: params.Name, fn: fn}
+		s.mu.Unlock()
+		defer func() {
+			s.mu.Lock()
+			delete(s.progress, token)
+			s.mu.Unlock()
+		}()
+		return next(ctx, method, req)
+	}
+}
+
+// progressNotified handles notifications/progress.
+func (s *mcpServer) progressNotified(_ context.Context, req *officialmcp.ProgressNotificationClientRequest) {
+	p := req.Params
+	token, _ := p.ProgressToken.(string)
+	now := time.Now()
+	s.mu.Lock()
+	call, ok := s.progress[token]
+	if ok && !call.last.IsZero() && now.Sub(call.last) < mcpProgressInterval {
+		ok = false
+	}
+	if ok {
+		call.last = now
+	}
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	slog.Debug("MCP tool progress", "server", s.name, "tool", call.tool, "progress", p.Progress, "total", p.Total)
+	call.fn(MCPProgress{Server: s.name, Tool: call.tool, Message: p.Message, Progress: p.Progress, Total: p.Total})
+}
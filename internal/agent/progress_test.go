@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServer_Progress(t *testing.T) {
+	updates := make(chan MCPProgress, 4)
+	var tokens []any
+	client := newTestMCPServer(t, "git", func(server *officialmcp.Server) {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "clone"}, func(ctx context.Context, req *officialmcp.CallToolRequest, _ struct{}) (*officialmcp.CallToolResult, any, error) {
+			token := req.Params.GetProgressToken()
+			tokens = append(tokens, token)
+			if token == nil {
+				return &officialmcp.CallToolResult{}, nil, nil
+			}
+			for i, msg := range []string{"Receiving objects", "Resolving deltas", "Checking out files"} {
+				err := req.Session.NotifyProgress(ctx, &officialmcp.ProgressNotificationParams{
+					ProgressToken: token, Message: msg, Progress: float64(i + 1), Total: 4,
+				})
+				require.NoError(t, err)
+				if i == 0 {
+					select {
+					case <-updates:
+					case <-time.After(5 * time.Second):
+						t.Error("first progress update not reported")
+					}
+				}
+			}
+			return &officialmcp.CallToolResult{}, nil, nil
+		})
+	})
+	cs, err := client.clientSession(context.Background())
+	require.NoError(t, err)
+
+	var got []MCPProgress
+	ctx := WithMCPProgress(context.Background(), func(p MCPProgress) {
+		got = append(got, p)
+		updates <- p
+	})
+	_, err = cs.CallTool(ctx, &officialmcp.CallToolParams{Name: "clone"})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, got)
+	assert.Equal(t, MCPProgress{Server: "git", Tool: "clone", Message: "Receiving objects", Progress: 1, Total: 4}, got[0])
+	assert.Len(t, got, 1, "updates within the interval are dropped")
+
+	client.mu.Lock()
+	assert.Empty(t, client.progress, "the call's token is released")
+	client.mu.Unlock()
+
+	_, err = cs.CallTool(context.Background(), &officialmcp.CallToolParams{Name: "clone"})
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.NotNil(t, tokens[0])
+	assert.Nil(t, tokens[1], "no progress is requested without a reporter")
+}
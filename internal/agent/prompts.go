@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ErrUnknownPrompt is returned by RenderMCPPrompt for a server or prompt
+// that does not exist.
+var ErrUnknownPrompt = errors.New("unknown MCP prompt")
+
+// MCPPrompt is a prompt template offered by an MCP server.
+type MCPPrompt struct {
+	Server      string
+	Name        string
+	Description string
+	Arguments   []MCPPromptArgument
+}
+
+// MCPPromptArgument is a value a prompt template is filled in with.
+type MCPPromptArgument struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// MCPPrompts lists the prompts of every running MCP server that supports
+// them, by server and name. Unreachable servers are logged and skipped.
+func (a *Agent) MCPPrompts(ctx context.Context) []MCPPrompt {
+	var prompts []MCPPrompt
+	for _, s := range a.runningMCPServers() {
+		listCtx, cancel := context.WithTimeout(ctx, mcpListTimeout)
+		list, err := s.listPrompts(listCtx)
+		cancel()
+		if err != nil {
+			slog.Warn("Failed to list MCP prompts", "server", s.name, "error", err)
+			continue
+		}
+		prompts = append(prompts, list...)
+	}
+	return prompts
+}
+
+// RenderMCPPrompt fills in a server's prompt template with args and returns
+// the text of its messages, ready to send as a chat message.
+func (a *Agent) RenderMCPPrompt(ctx context.Context, server, name string, args map[string]string) (string, error) {
+	s, ok := a.mcpServer(server)
+	if !ok {
+		return "", fmt.Errorf("%w: no MCP server %q", ErrUnknownPrompt, server)
+	}
+	cs, err := s.clientSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	res, err := cs.GetPrompt(ctx, &officialmcp.GetPromptParams{Name: name, Arguments: args})
+	if err != nil {
+		return "", fmt.Errorf("failed to get prompt %q from %s: %w", name, server, err)
+	}
+
+	var parts []string
+	for _, m := range res.Messages {
+		switch c := m.Content.(type) {
+		case *officialmcp.TextContent:
+			parts = append(parts, c.Text)
+		case *officialmcp.EmbeddedResource:
+			if c.Resource != nil && c.Resource.Text != "" {
+				parts = append(parts, c.Resource.Text)
+			}
+		}
+	}
+	text := strings.TrimSpace(strings.Join(parts, "\n\n"))
+	if text == "" {
+		return "", fmt.Errorf("prompt %q from %s has no text", name, server)
+	}
+	return text, nil
+}
+
+// listPrompts lists the server's prompts, or none if it does not offer any.
+// Like its tools, the list is cached while the server announces changes.
+func (s *mcpServer) listPrompts(ctx context.Context) ([]MCPPrompt, error) {
+	s.mu.Lock()
+	if s.prompts != nil {
+		defer s.mu.Unlock()
+		return *s.prompts, nil
+	}
+	gen := s.gen
+	s.mu.Unlock()
+
+	cs, err := s.clientSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if init := cs.InitializeResult(); init == nil || init.Capabilities == nil || init.Capabilities.Prompts == nil {
+		return nil, nil
+	}
+	prompts, err := fetchPrompts(ctx, s.name, cs)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen == s.gen && announcesChanges(cs, func(c *officialmcp.ServerCapabilities) bool {
+		return c.Prompts.ListChanged
+	}) {
+		s.prompts = &prompts
+	}
+	return prompts, nil
+}
+
+// fetchPrompts asks the server for its prompts, by name.
+func fetchPrompts(ctx context.Context, server string, cs *officialmcp.ClientSession) ([]MCPPrompt, error) {
+	var prompts []MCPPrompt
+	for p, err := range cs.Prompts(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		prompt := MCPPrompt{Server: server, Name: p.Name, Description: p.Description}
+		for _, arg := range p.Arguments {
+			prompt.Arguments = append(prompt.Arguments, MCPPromptArgument{Name: arg.Name, Description: arg.Description, Required: arg.Required})
+		}
+		prompts = append(prompts, prompt)
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts, nil
+}
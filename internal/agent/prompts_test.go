@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPPrompts(t *testing.T) {
+	t.Parallel()
+	github := newTestMCPServer(t, "github", func(s *officialmcp.Server) {
+		s.AddPrompt(&officialmcp.Prompt{
+			Name:        "review-pr",
+			Description: "Review a pull request.",
+			Arguments:   []*officialmcp.PromptArgument{{Name: "number", Required: true}},
+		}, func(_ context.Context, req *officialmcp.GetPromptRequest) (*officialmcp.GetPromptResult, error) {
+			return &officialmcp.GetPromptResult{Messages: []*officialmcp.PromptMessage{
+				{Role: "user", Content: &officialmcp.TextContent{Text: "Review PR #" + req.Params.Arguments["number"] + "."}},
+				{Role: "user", Content: &officialmcp.EmbeddedResource{Resource: &officialmcp.ResourceContents{URI: "file:///guide.md", Text: "Follow the guide."}}},
+			}}, nil
+		})
+		s.AddPrompt(&officialmcp.Prompt{Name: "empty"}, func(context.Context, *officialmcp.GetPromptRequest) (*officialmcp.GetPromptResult, error) {
+			return &officialmcp.GetPromptResult{}, nil
+		})
+	})
+	// A server with tools but no prompts is skipped.
+	weather := newTestMCPServer(t, "weather", func(s *officialmcp.Server) {
+		officialmcp.AddTool(s, &officialmcp.Tool{Name: "forecast"}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+			return &officialmcp.CallToolResult{}, nil, nil
+		})
+	})
+	a := &Agent{mcpServers: map[string]*mcpServer{"github": github, "weather": weather}}
+	ctx := context.Background()
+
+	prompts := a.MCPPrompts(ctx)
+	require.Len(t, prompts, 2)
+	assert.Equal(t, MCPPrompt{
+		Server:      "github",
+		Name:        "review-pr",
+		Description: "Review a pull request.",
+		Arguments:   []MCPPromptArgument{{Name: "number", Required: true}},
+	}, prompts[1])
+
+	text, err := a.RenderMCPPrompt(ctx, "github", "review-pr", map[string]string{"number": "42"})
+	require.NoError(t, err)
+	assert.Equal(t, "Review PR #42.\n\nFollow the guide.", text)
+
+	_, err = a.RenderMCPPrompt(ctx, "github", "empty", nil)
+	assert.ErrorContains(t, err, "has no text")
+
+	_, err = a.RenderMCPPrompt(ctx, "gitlab", "review-pr", nil)
+	assert.True(t, errors.Is(err, ErrUnknownPrompt))
+}
@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type RedditTopArgs struct {
+	Subreddit string `json:"subreddit,omitempty" jsonschema:"The subreddit to read, e.g. golang. Empty reads the configured subreddits."`
+	Period    string `json:"period,omitempty" jsonschema:"The window of top posts: hour, day, week, month, year or all (default from the config, or day)."`
+}
+
+// newRedditTool returns the tool that reads the top posts and comments of
+// subreddits, leaving out the posts an earlier /digest covered.
+func (a *Agent) newRedditTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "reddit_top",
+		Description: "Reads the top posts of a subreddit, or of the configured subreddits, with their scores, links and top comments. Use for community discussion and reactions to news.",
+	}, func(ctx tool.Context, args RedditTopArgs) (string, error) {
+		cfg := a.config()
+		r := cfg.Reddit
+		subreddits := r.Names()
+		if args.Subreddit != "" {
+			name := config.SubredditName(args.Subreddit)
+			if name == "" {
+				return "", fmt.Errorf("invalid subreddit %q", args.Subreddit)
+			}
+			subreddits = []string{name}
+		}
+		if len(subreddits) == 0 {
+			return "", fmt.Errorf("subreddit is required: none are configured")
+		}
+		period := args.Period
+		if period == "" {
+			period = r.Period
+		}
+		client := tools.NewReddit(cfg.ExpandEnv(r.ClientID), cfg.ExpandEnv(r.ClientSecret))
+
+		var sb strings.Builder
+		skipped := 0
+		for _, s := range subreddits {
+			posts, err := client.TopPosts(ctx, s, period, r.Posts, r.Comments)
+			if err != nil {
+				sb.WriteString(fmt.Sprintf("r/%s: %v\n\n", s, err))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("## r/%s\n", s))
+			for _, p := range posts {
+				if seen, _ := a.db.HasHeadline(ctx, p.URL); seen {
+					skipped++
+					continue
+				}
+				sb.WriteString(fmt.Sprintf("- [%s](%s) — %d points, %d comments, %s\n", p.Title, p.URL, p.Score, p.NumComments, p.Permalink))
+				if p.Text != "" {
+					sb.WriteString("  " + p.Text + "\n")
+				}
+				for _, c := range p.Comments {
+					sb.WriteString(fmt.Sprintf("  > %s (%d points)\n", c.Body, c.Score))
+				}
+			}
+			sb.WriteString("\n")
+		}
+		if skipped > 0 {
+			sb.WriteString(fmt.Sprintf("(%d posts already covered by earlier digests are left out.)\n", skipped))
+		}
+		return sb.String(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reddit_top tool: %w", err)
+	}
+	return t, nil
+}
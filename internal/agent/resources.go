@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ErrNoSubscriptions is returned by WatchMCPResource for a server that does
+// not support resources/subscribe.
+var ErrNoSubscriptions = errors.New("MCP server does not support resource subscriptions")
+
+// OnMCPResourceUpdated sets the function called (in its own goroutine)
+// when a watched resource changes, on servers added later too.
+func (a *Agent) OnMCPResourceUpdated(fn func(server, uri string)) {
+	a.mcpMu.Lock()
+	a.onMCPUpdate = fn
+	a.mcpMu.Unlock()
+	for _, s := range a.runningMCPServers() {
+		s.mu.Lock()
+		s.onUpdate = fn
+		s.mu.Unlock()
+	}
+}
+
+// WatchMCPResource subscribes to updates of a server's resource. The
+// subscription is renewed whenever the server reconnects.
+func (a *Agent) WatchMCPResource(ctx context.Context, server, uri string) error {
+	s, ok := a.mcpServer(server)
+	if !ok {
+		return fmt.Errorf("no MCP server %q", server)
+	}
+	cs, err := s.clientSession(ctx)
+	if err != nil {
+		return err
+	}
+	if caps := serverCapabilities(cs); caps == nil || caps.Resources == nil || !caps.Resources.Subscribe {
+		return ErrNoSubscriptions
+	}
+	if err := cs.Subscribe(ctx, &officialmcp.SubscribeParams{URI: uri}); err != nil {
+		return fmt.Errorf("failed to subscribe to %s on %s: %w", uri, server, err)
+	}
+	s.mu.Lock()
+	s.subscribed[uri] = true
+	s.mu.Unlock()
+	return nil
+}
+
+// UnwatchMCPResource ends a subscription made by WatchMCPResource.
+func (a *Agent) UnwatchMCPResource(ctx context.Context, server, uri string) error {
+	s, ok := a.mcpServer(server)
+	if !ok {
+		return fmt.Errorf("no MCP server %q", server)
+	}
+	s.mu.Lock()
+	delete(s.subscribed, uri)
+	cs := s.session
+	s.mu.Unlock()
+	if cs == nil {
+		return nil
+	}
+	return cs.Unsubscribe(ctx, &officialmcp.UnsubscribeParams{URI: uri})
+}
+
+// ReadMCPResource returns the text of a server's resource.
+func (a *Agent) ReadMCPResource(ctx context.Context, server, uri string) (string, error) {
+	s, ok := a.mcpServer(server)
+	if !ok {
+		return "", fmt.Errorf("no MCP server %q", server)
+	}
+	cs, err := s.clientSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	res, err := cs.ReadResource(ctx, &officialmcp.ReadResourceParams{URI: uri})
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from %s: %w", uri, server, err)
+	}
+	var parts []string
+	for _, c := range res.Contents {
+		if c.Text != "" {
+			parts = append(parts, c.Text)
+		}
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+// resourceUpdated handles notifications/resources/updated.
+func (s *mcpServer) resourceUpdated(_ context.Context, req *officialmcp.ResourceUpdatedNotificationRequest) {
+	s.mu.Lock()
+	fn, watched := s.onUpdate, s.subscribed[req.Params.URI]
+	s.mu.Unlock()
+	if fn == nil || !watched {
+		return
+	}
+	slog.Info("MCP resource updated", "server", s.name, "uri", req.Params.URI)
+	// Handlers run on the session's read loop, which fn may need (to read
+	// the resource).
+	go fn(s.name, req.Params.URI)
+}
+
+// resubscribe renews the subscriptions on a new session.
+func (s *mcpServer) resubscribe(ctx context.Context, cs *officialmcp.ClientSession) {
+	s.mu.Lock()
+	uris := make([]string, 0, len(s.subscribed))
+	for uri := range s.subscribed {
+		uris = append(uris, uri)
+	}
+	s.mu.Unlock()
+	for _, uri := range uris {
+		if err := cs.Subscribe(ctx, &officialmcp.SubscribeParams{URI: uri}); err != nil {
+			slog.Warn("Failed to renew MCP resource subscription", "server", s.name, "uri", uri, "error", err)
+		}
+	}
+}
+
+func serverCapabilities(cs *officialmcp.ClientSession) *officialmcp.ServerCapabilities {
+	if init := cs.InitializeResult(); init != nil {
+		return init.Capabilities
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchMCPResource(t *testing.T) {
+	const uri = "file:///var/log/app.log"
+	server := officialmcp.NewServer(&officialmcp.Implementation{Name: "fs"}, &officialmcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *officialmcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *officialmcp.UnsubscribeRequest) error { return nil },
+	})
+	server.AddResource(&officialmcp.Resource{URI: uri, Name: "app.log"}, func(context.Context, *officialmcp.ReadResourceRequest) (*officialmcp.ReadResourceResult, error) {
+		return &officialmcp.ReadResourceResult{Contents: []*officialmcp.ResourceContents{{URI: uri, Text: "ERROR disk full"}}}, nil
+	})
+	transport := &restartableTransport{server: server}
+	fs, err := newMCPServer("fs", transport, nil, nil, nil)
+	require.NoError(t, err)
+	plain := newTestMCPServer(t, "weather", func(*officialmcp.Server) {})
+	a := &Agent{mcpServers: map[string]*mcpServer{"fs": fs, "weather": plain}}
+
+	updates := make(chan string, 4)
+	a.OnMCPResourceUpdated(func(server, uri string) { updates <- server + " " + uri })
+	ctx := context.Background()
+
+	require.NoError(t, a.WatchMCPResource(ctx, "fs", uri))
+	assert.ErrorIs(t, a.WatchMCPResource(ctx, "weather", "weather://today"), ErrNoSubscriptions)
+	assert.Error(t, a.WatchMCPResource(ctx, "gitlab", uri))
+
+	text, err := a.ReadMCPResource(ctx, "fs", uri)
+	require.NoError(t, err)
+	assert.Equal(t, "ERROR disk full", text)
+
+	updated := func() string {
+		require.NoError(t, server.ResourceUpdated(ctx, &officialmcp.ResourceUpdatedNotificationParams{URI: uri}))
+		select {
+		case u := <-updates:
+			return u
+		case <-time.After(5 * time.Second):
+			return ""
+		}
+	}
+	assert.Equal(t, "fs "+uri, updated())
+
+	// After a reconnect the subscription is renewed on the new session.
+	transport.crash(false)
+	require.NoError(t, fs.check(ctx))
+	require.Eventually(t, func() bool {
+		return server.ResourceUpdated(ctx, &officialmcp.ResourceUpdatedNotificationParams{URI: uri}) == nil && len(updates) > 0
+	}, 5*time.Second, 20*time.Millisecond)
+	drain := func() {
+		for {
+			select {
+			case <-updates:
+			case <-time.After(100 * time.Millisecond):
+				return
+			}
+		}
+	}
+	drain()
+
+	require.NoError(t, a.UnwatchMCPResource(ctx, "fs", uri))
+	require.NoError(t, server.ResourceUpdated(ctx, &officialmcp.ResourceUpdatedNotificationParams{URI: uri}))
+	select {
+	case u := <-updates:
+		t.Fatalf("unexpected update after unwatching: %s", u)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,166 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/stats"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// errSamplingQuota is returned to a server that used up its hourly
+// sampling requests.
+var errSamplingQuota = errors.New("sampling request denied: hourly limit reached")
+
+// sampler answers an MCP server's sampling/createMessage requests with
+// ravenbot's models, within the server's MCPSamplingConfig.
+type sampler struct {
+	server     string
+	policy     config.MCPSamplingConfig
+	flash, pro model.LLM
+	stats      *stats.Stats
+
+	mu       sync.Mutex
+	approved []time.Time // approvals in the last hour
+	now      func() time.Time
+}
+
+func newSampler(server string, policy config.MCPSamplingConfig, flash, pro model.LLM, s *stats.Stats) *sampler {
+	return &sampler{server: server, policy: policy, flash: flash, pro: pro, stats: s, now: time.Now}
+}
+
+// createMessage is the client's sampling handler.
+func (s *sampler) createMessage(ctx context.Context, req *officialmcp.CreateMessageRequest) (*officialmcp.CreateMessageResult, error) {
+	if !s.approve() {
+		slog.Warn("Denied MCP sampling request", "server", s.server, "reason", "hourly limit")
+		return nil, errSamplingQuota
+	}
+	p := req.Params
+	llm := s.model(p.ModelPreferences)
+
+	contents, err := samplingContents(p.Messages)
+	if err != nil {
+		return nil, err
+	}
+	maxTokens := s.policy.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = config.DefaultSamplingMaxTokens
+	}
+	if p.MaxTokens > 0 {
+		maxTokens = min(maxTokens, p.MaxTokens)
+	}
+	genCfg := &genai.GenerateContentConfig{
+		MaxOutputTokens: int32(maxTokens),
+		StopSequences:   p.StopSequences,
+	}
+	if p.SystemPrompt != "" {
+		genCfg.SystemInstruction = &genai.Content{Parts: []*genai.Part{{Text: p.SystemPrompt}}}
+	}
+	if p.Temperature > 0 {
+		genCfg.Temperature = genai.Ptr(float32(p.Temperature))
+	}
+	slog.Info("MCP sampling request", "server", s.server, "model", llm.Name(), "messages", len(contents), "maxTokens", maxTokens)
+
+	var (
+		text   strings.Builder
+		reason = "endTurn"
+	)
+	for resp, err := range llm.GenerateContent(ctx, &model.LLMRequest{Contents: contents, Config: genCfg}, false) {
+		if err != nil {
+			return nil, fmt.Errorf("sampling failed: %w", err)
+		}
+		if resp.Content != nil {
+			for _, part := range resp.Content.Parts {
+				text.WriteString(part.Text)
+			}
+		}
+		if resp.FinishReason == genai.FinishReasonMaxTokens {
+			reason = "maxTokens"
+		}
+		if resp.UsageMetadata != nil && s.stats != nil {
+			s.stats.RecordModelTokens(llm.Name(), int64(resp.UsageMetadata.PromptTokenCount), int64(resp.UsageMetadata.CandidatesTokenCount))
+		}
+	}
+	return &officialmcp.CreateMessageResult{
+		Content:    &officialmcp.TextContent{Text: text.String()},
+		Model:      llm.Name(),
+		Role:       "assistant",
+		StopReason: reason,
+	}, nil
+}
+
+// approve counts a request against the hourly limit, reporting whether it
+// is within it.
+func (s *sampler) approve() bool {
+	limit := s.policy.PerHour
+	if limit == 0 {
+		limit = config.DefaultSamplingPerHour
+	}
+	if limit < 0 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	recent := s.approved[:0]
+	for _, t := range s.approved {
+		if now.Sub(t) < time.Hour {
+			recent = append(recent, t)
+		}
+	}
+	s.approved = recent
+	if len(s.approved) >= limit {
+		return false
+	}
+	s.approved = append(s.approved, now)
+	return true
+}
+
+// model picks the LLM for a request. ModelAuto uses Pro when the server
+// ranks intelligence above speed and cost.
+func (s *sampler) model(prefs *officialmcp.ModelPreferences) model.LLM {
+	switch s.policy.Model {
+	case config.ModelPro:
+		return s.pro
+	case config.ModelAuto:
+		if prefs != nil && prefs.IntelligencePriority > max(prefs.SpeedPriority, prefs.CostPriority) {
+			return s.pro
+		}
+	}
+	return s.flash
+}
+
+// samplingContents converts the request's messages to model contents.
+func samplingContents(msgs []*officialmcp.SamplingMessage) ([]*genai.Content, error) {
+	contents := make([]*genai.Content, 0, len(msgs))
+	for _, m := range msgs {
+		role := genai.RoleUser
+		if m.Role == "assistant" {
+			role = genai.RoleModel
+		}
+		var part *genai.Part
+		switch c := m.Content.(type) {
+		case *officialmcp.TextContent:
+			part = &genai.Part{Text: c.Text}
+		case *officialmcp.ImageContent:
+			part = &genai.Part{InlineData: &genai.Blob{MIMEType: c.MIMEType, Data: c.Data}}
+		case *officialmcp.AudioContent:
+			part = &genai.Part{InlineData: &genai.Blob{MIMEType: c.MIMEType, Data: c.Data}}
+		default:
+			return nil, fmt.Errorf("unsupported sampling content %T", m.Content)
+		}
+		contents = append(contents, &genai.Content{Role: role, Parts: []*genai.Part{part}})
+	}
+	if len(contents) == 0 {
+		return nil, errors.New("sampling request has no messages")
+	}
+	return contents, nil
+}
@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"context"
+	"iter"
+	"testing"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// recordingLLM answers with a fixed response and keeps the last request.
+type recordingLLM struct {
+	name string
+	resp *model.LLMResponse
+	req  *model.LLMRequest
+}
+
+func (m *recordingLLM) Name() string { return m.name }
+
+func (m *recordingLLM) GenerateContent(_ context.Context, req *model.LLMRequest, _ bool) iter.Seq2[*model.LLMResponse, error] {
+	m.req = req
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(m.resp, nil)
+	}
+}
+
+func newTestSampler(policy config.MCPSamplingConfig) (*sampler, *recordingLLM, *recordingLLM) {
+	flash := &recordingLLM{name: "flash-model", resp: NewTextResponse("quick answer")}
+	pro := &recordingLLM{name: "pro-model", resp: NewTextResponse("careful answer")}
+	return newSampler("test", policy, flash, pro, stats.New()), flash, pro
+}
+
+func samplingRequest(params *officialmcp.CreateMessageParams) *officialmcp.CreateMessageRequest {
+	return &officialmcp.CreateMessageRequest{Params: params}
+}
+
+func TestSampler_CreateMessage(t *testing.T) {
+	s, flash, _ := newTestSampler(config.MCPSamplingConfig{MaxTokens: 200})
+
+	res, err := s.createMessage(context.Background(), samplingRequest(&officialmcp.CreateMessageParams{
+		SystemPrompt: "Be brief.",
+		MaxTokens:    500,
+		Temperature:  0.2,
+		Messages: []*officialmcp.SamplingMessage{
+			{Role: "user", Content: &officialmcp.TextContent{Text: "Summarize this."}},
+			{Role: "assistant", Content: &officialmcp.TextContent{Text: "Sure."}},
+			{Role: "user", Content: &officialmcp.ImageContent{MIMEType: "image/png", Data: []byte{1, 2}}},
+		},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "quick answer", res.Content.(*officialmcp.TextContent).Text)
+	assert.Equal(t, "flash-model", res.Model)
+	assert.Equal(t, officialmcp.Role("assistant"), res.Role)
+	assert.Equal(t, "endTurn", res.StopReason)
+
+	req := flash.req
+	require.NotNil(t, req)
+	assert.Equal(t, int32(200), req.Config.MaxOutputTokens, "the policy caps the server's maxTokens")
+	assert.Equal(t, "Be brief.", req.Config.SystemInstruction.Parts[0].Text)
+	assert.InDelta(t, 0.2, *req.Config.Temperature, 1e-6)
+	require.Len(t, req.Contents, 3)
+	assert.Equal(t, genai.RoleModel, req.Contents[1].Role)
+	assert.Equal(t, "image/png", req.Contents[2].Parts[0].InlineData.MIMEType)
+}
+
+func TestSampler_MaxTokensStopReason(t *testing.T) {
+	s, flash, _ := newTestSampler(config.MCPSamplingConfig{})
+	flash.resp = &model.LLMResponse{
+		Content:      &genai.Content{Parts: []*genai.Part{{Text: "cut o"}}},
+		FinishReason: genai.FinishReasonMaxTokens,
+	}
+
+	res, err := s.createMessage(context.Background(), samplingRequest(&officialmcp.CreateMessageParams{
+		Messages: []*officialmcp.SamplingMessage{{Role: "user", Content: &officialmcp.TextContent{Text: "hi"}}},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "maxTokens", res.StopReason)
+	assert.Equal(t, int32(config.DefaultSamplingMaxTokens), flash.req.Config.MaxOutputTokens)
+}
+
+func TestSampler_Model(t *testing.T) {
+	smart := &officialmcp.ModelPreferences{IntelligencePriority: 0.9, SpeedPriority: 0.2}
+	fast := &officialmcp.ModelPreferences{IntelligencePriority: 0.5, SpeedPriority: 0.8}
+
+	s, flash, pro := newTestSampler(config.MCPSamplingConfig{Model: config.ModelAuto})
+	assert.Same(t, pro, s.model(smart))
+	assert.Same(t, flash, s.model(fast))
+	assert.Same(t, flash, s.model(nil))
+
+	s.policy.Model = config.ModelPro
+	assert.Same(t, pro, s.model(fast))
+	s.policy.Model = ""
+	assert.Same(t, flash, s.model(smart), "flash unless configured otherwise")
+}
+
+func TestSampler_HourlyLimit(t *testing.T) {
+	s, _, _ := newTestSampler(config.MCPSamplingConfig{PerHour: 2})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	req := samplingRequest(&officialmcp.CreateMessageParams{
+		Messages: []*officialmcp.SamplingMessage{{Role: "user", Content: &officialmcp.TextContent{Text: "hi"}}},
+	})
+
+	for range 2 {
+		_, err := s.createMessage(context.Background(), req)
+		require.NoError(t, err)
+	}
+	_, err := s.createMessage(context.Background(), req)
+	assert.ErrorIs(t, err, errSamplingQuota)
+
+	now = now.Add(time.Hour)
+	_, err = s.createMessage(context.Background(), req)
+	assert.NoError(t, err, "requests older than an hour no longer count")
+
+	s.policy.PerHour = -1
+	for range 5 {
+		assert.True(t, s.approve())
+	}
+}
+
+func TestSampler_Errors(t *testing.T) {
+	s, _, _ := newTestSampler(config.MCPSamplingConfig{})
+
+	_, err := s.createMessage(context.Background(), samplingRequest(&officialmcp.CreateMessageParams{}))
+	assert.ErrorContains(t, err, "no messages")
+
+	_, err = s.createMessage(context.Background(), samplingRequest(&officialmcp.CreateMessageParams{
+		Messages: []*officialmcp.SamplingMessage{{Role: "user", Content: &officialmcp.ResourceLink{URI: "file:///x"}}},
+	}))
+	assert.ErrorContains(t, err, "unsupported")
+}
+
+func TestMCPServer_Sampling(t *testing.T) {
+	s, flash, _ := newTestSampler(config.MCPSamplingConfig{})
+	client, ss := connectTestMCPServer(t, "sampler", func(*officialmcp.Server) {},
+		&officialmcp.ClientOptions{CreateMessageHandler: s.createMessage}, nil)
+	_, err := client.clientSession(context.Background())
+	require.NoError(t, err)
+
+	res, err := ss.CreateMessage(context.Background(), &officialmcp.CreateMessageParams{
+		MaxTokens: 64,
+		Messages:  []*officialmcp.SamplingMessage{{Role: "user", Content: &officialmcp.TextContent{Text: "hello"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "quick answer", res.Content.(*officialmcp.TextContent).Text)
+	assert.Equal(t, int32(64), flash.req.Config.MaxOutputTokens)
+}
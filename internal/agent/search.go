@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+)
+
+// newSearch returns the web_search tool's providers, in the configured
+// order. Without any configured it searches with Gemini's grounding.
+func newSearch(cfg *config.Config) *tools.FallbackSearch {
+	if len(cfg.SearchProviders) == 0 {
+		return tools.NewFallbackSearch(tools.GeminiSearch{APIKey: cfg.GeminiAPIKey, Model: cfg.GeminiFlashModel})
+	}
+	providers := make([]tools.SearchProvider, 0, len(cfg.SearchProviders))
+	for _, p := range cfg.SearchProviders {
+		key := cfg.ExpandEnv(p.APIKey)
+		switch p.Type {
+		case config.SearchGemini:
+			providers = append(providers, tools.GeminiSearch{APIKey: cfg.GeminiAPIKey, Model: cfg.GeminiFlashModel})
+		case config.SearchSearxNG:
+			providers = append(providers, tools.SearxNG{URL: p.URL, MaxResults: p.MaxResults})
+		case config.SearchBrave:
+			providers = append(providers, tools.BraveSearch{APIKey: key, URL: p.URL, MaxResults: p.MaxResults})
+		case config.SearchGoogle:
+			providers = append(providers, tools.GoogleCSE{APIKey: key, CX: p.CX, URL: p.URL, MaxResults: p.MaxResults})
+		case config.SearchBing:
+			providers = append(providers, tools.BingSearch{APIKey: key, URL: p.URL, MaxResults: p.MaxResults})
+		}
+	}
+	return tools.NewFallbackSearch(providers...)
+}
+
+// searchGroup marks the Research tools unavailable when web_search has no
+// usable provider: Gemini's grounding is the default and needs its key.
+func searchGroup(g ToolGroup, cfg *config.Config) ToolGroup {
+	if len(cfg.SearchProviders) > 0 {
+		g.Available = true
+		return g
+	}
+	return requireKey(g, "GEMINI_API_KEY", cfg.GeminiAPIKey)
+}
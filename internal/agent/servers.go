@@ -0,0 +1,383 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+)
+
+// Errors returned by AddMCPServer and RemoveMCPServer.
+var (
+	ErrInvalidMCPName  = errors.New("MCP server names may only use letters, digits, '.', '-' and '_'")
+	ErrMCPServerExists = errors.New("MCP server already exists")
+	ErrNoMCPServer     = errors.New("no such MCP server")
+)
+
+// validMCPName matches the names servers can be added under; they appear
+// in commands (/prompt server/name) and log file names.
+var validMCPName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// defaultMCPOwners is the sub-agent each built-in MCP server is given to,
+// unless its configuration names another.
+var defaultMCPOwners = map[string]string{
+	"weather":             "ResearchAssistant",
+	"memory":              "ResearchAssistant",
+	"filesystem":          "ResearchAssistant",
+	"sequential-thinking": "ResearchAssistant",
+	"sysmetrics":          "SystemManager",
+	"github":              "Jules",
+}
+
+// defaultAddedMCPOwner is given the servers added with /mcp add that no
+// sub-agent claims.
+const defaultAddedMCPOwner = "ResearchAssistant"
+
+// mcpOwner is the sub-agent given the server's tools, or "" for none.
+func mcpOwner(name string, serverCfg config.MCPServerConfig) string {
+	if serverCfg.Agent != "" {
+		return serverCfg.Agent
+	}
+	return defaultMCPOwners[name]
+}
+
+// mcpConfigs returns the configured MCP servers with the runtime changes
+// made by AddMCPServer and RemoveMCPServer applied.
+func (a *Agent) mcpConfigs() map[string]config.MCPServerConfig {
+	configs := maps.Clone(a.config().MCPServers)
+	if configs == nil {
+		configs = make(map[string]config.MCPServerConfig)
+	}
+	a.mcpMu.RLock()
+	defer a.mcpMu.RUnlock()
+	for name, serverCfg := range a.mcpOverrides {
+		if serverCfg == nil {
+			delete(configs, name)
+		} else {
+			configs[name] = *serverCfg
+		}
+	}
+	return configs
+}
+
+// loadMCPOverrides reads the runtime changes to the MCP servers saved by
+// earlier AddMCPServer and RemoveMCPServer calls.
+func (a *Agent) loadMCPOverrides(ctx context.Context) {
+	a.mcpOverrides = make(map[string]*config.MCPServerConfig)
+	if a.db == nil {
+		return
+	}
+	saved, err := a.db.MCPServerOverrides(ctx)
+	if err != nil {
+		slog.Error("Failed to load MCP servers added at runtime", "error", err)
+		return
+	}
+	for name, data := range saved {
+		if data == "" {
+			a.mcpOverrides[name] = nil
+			continue
+		}
+		var serverCfg config.MCPServerConfig
+		if err := json.Unmarshal([]byte(data), &serverCfg); err != nil {
+			slog.Error("Skipping invalid MCP server added at runtime", "name", name, "error", err)
+			continue
+		}
+		a.mcpOverrides[name] = &serverCfg
+	}
+}
+
+// mcpServer returns the running server of that name.
+func (a *Agent) mcpServer(name string) (*mcpServer, bool) {
+	a.mcpMu.RLock()
+	defer a.mcpMu.RUnlock()
+	s, ok := a.mcpServers[name]
+	return s, ok
+}
+
+// runningMCPServers returns the running servers, by name.
+func (a *Agent) runningMCPServers() []*mcpServer {
+	a.mcpMu.RLock()
+	defer a.mcpMu.RUnlock()
+	names := slices.Sorted(maps.Keys(a.mcpServers))
+	servers := make([]*mcpServer, 0, len(names))
+	for _, name := range names {
+		servers = append(servers, a.mcpServers[name])
+	}
+	return servers
+}
+
+// startMCPServer creates a server and starts supervising it, until it is
+// closed. It is not yet given to any agent.
+func (a *Agent) startMCPServer(name string, serverCfg config.MCPServerConfig) (*mcpServer, error) {
+	ctx, cancel := context.WithCancel(a.mcpCtx)
+	cfg := a.config()
+	var transport officialmcp.Transport
+	if strings.HasPrefix(serverCfg.Command, "http://") || strings.HasPrefix(serverCfg.Command, "https://") {
+		client, err := authClient(ctx, serverCfg.Auth, serverCfg.Command, tools.NewSafeClient(0), cfg.ExpandEnv)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid MCP server URL: %w", err)
+		}
+		transport = httpTransport(serverCfg, client)
+	} else if strings.HasPrefix(serverCfg.Command, "ws://") || strings.HasPrefix(serverCfg.Command, "wss://") {
+		client, err := authClient(ctx, serverCfg.Auth, wsHTTPURL(serverCfg.Command), tools.NewSafeClient(0), cfg.ExpandEnv)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid MCP server URL: %w", err)
+		}
+		transport = &wsTransport{endpoint: serverCfg.Command, client: client}
+	} else {
+		ct := &commandTransport{command: serverCfg.Command, limits: serverCfg.Limits, stderr: newServerLog(serverLogPath(name))}
+		for _, arg := range serverCfg.Args {
+			ct.args = append(ct.args, cfg.ExpandEnv(arg))
+		}
+		if len(serverCfg.Env) > 0 {
+			ct.env = os.Environ()
+			for k, v := range serverCfg.Env {
+				ct.env = append(ct.env, fmt.Sprintf("%s=%s", k, cfg.ExpandEnv(v)))
+			}
+		}
+		transport = ct
+	}
+
+	// Only servers configured for sampling advertise the capability.
+	var opts *officialmcp.ClientOptions
+	if serverCfg.Sampling != nil {
+		opts = &officialmcp.ClientOptions{
+			CreateMessageHandler: newSampler(name, *serverCfg.Sampling, a.flashLLM, a.proLLM, a.stats).createMessage,
+		}
+	}
+	roots := mcpRoots(name, serverCfg.Roots, cfg.ExpandEnv)
+	var filter tool.Predicate
+	if len(serverCfg.ToolAllowlist) > 0 || len(serverCfg.ToolDenylist) > 0 {
+		filter = func(_ agent.ReadonlyContext, t tool.Tool) bool {
+			return serverCfg.ExposesTool(t.Name())
+		}
+	}
+	server, err := newMCPServer(name, transport, opts, roots, filter)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create MCP toolset: %w", err)
+	}
+	if ct, ok := transport.(*commandTransport); ok {
+		ct.exited = server.recordCrash
+	}
+	server.logLevel = serverCfg.LogLevel
+	if server.logLevel == "" {
+		server.logLevel = config.DefaultMCPLogLevel
+	}
+	if n := serverCfg.MaxConcurrentCalls; n > 0 {
+		server.calls = make(chan struct{}, n)
+	} else {
+		server.calls = make(chan struct{}, config.DefaultMCPMaxConcurrentCalls)
+	}
+	server.stop = cancel
+	server.stats = a.stats
+	if a.db != nil {
+		a.loadSavedTools(ctx, server)
+	}
+
+	go server.supervise(ctx)
+	return server, nil
+}
+
+// loadSavedTools gives the server the tool definitions it listed in an
+// earlier run, and has it save them whenever they change.
+func (a *Agent) loadSavedTools(ctx context.Context, s *mcpServer) {
+	data, err := a.db.MCPToolSchemas(ctx, s.name)
+	if err != nil {
+		slog.Warn("Failed to load saved MCP tools", "server", s.name, "error", err)
+	} else if data != "" {
+		if err := s.loadTools(data); err != nil {
+			slog.Warn("Ignoring invalid saved MCP tools", "server", s.name, "error", err)
+		}
+	}
+	s.onToolsListed = func(data string) {
+		if err := a.db.SetMCPToolSchemas(context.Background(), s.name, data); err != nil {
+			slog.Warn("Failed to save MCP tools", "server", s.name, "error", err)
+		}
+	}
+}
+
+// register gives a started server to the agent that owns it.
+func (a *Agent) register(s *mcpServer, owner string) {
+	a.mcpMu.Lock()
+	defer a.mcpMu.Unlock()
+	if a.onMCPUpdate != nil {
+		s.mu.Lock()
+		s.onUpdate = a.onMCPUpdate
+		s.mu.Unlock()
+	}
+	a.mcpServers[s.name] = s
+	a.mcpToolsets[s.name] = s
+	if owner != "" {
+		a.mcpOwners[s.name] = owner
+	}
+}
+
+// AddMCPServer starts a new MCP server and gives its tools to the agent
+// that owns it (the ResearchAssistant unless serverCfg or the server's
+// name says otherwise) from the next model call, returning that agent's
+// name. The server must connect; it is then saved, so it runs again after
+// a restart.
+func (a *Agent) AddMCPServer(ctx context.Context, name string, serverCfg config.MCPServerConfig) (string, error) {
+	if !validMCPName.MatchString(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidMCPName, name)
+	}
+	if _, ok := a.mcpConfigs()[name]; ok {
+		return "", fmt.Errorf("%w: %q", ErrMCPServerExists, name)
+	}
+	if mcpOwner(name, serverCfg) == "" {
+		serverCfg.Agent = defaultAddedMCPOwner
+	}
+	owner := mcpOwner(name, serverCfg)
+	data, err := json.Marshal(serverCfg)
+	if err != nil {
+		return "", err
+	}
+	s, err := a.startMCPServer(name, serverCfg)
+	if err != nil {
+		return "", err
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, mcpListTimeout)
+	_, err = s.clientSession(connectCtx)
+	cancel()
+	if err != nil {
+		s.close()
+		return "", fmt.Errorf("failed to connect to MCP server %q: %w", name, err)
+	}
+	if a.db != nil {
+		if err := a.db.SetMCPServerOverride(ctx, name, string(data)); err != nil {
+			s.close()
+			return "", err
+		}
+	}
+	a.register(s, owner)
+	a.mcpMu.Lock()
+	a.mcpOverrides[name] = &serverCfg
+	a.mcpMu.Unlock()
+	slog.Info("MCP server added", "name", name, "agent", owner)
+	return owner, nil
+}
+
+// RemoveMCPServer stops an MCP server and takes its tools away from the
+// agents. A configured server stays removed across restarts until it is
+// added again.
+func (a *Agent) RemoveMCPServer(ctx context.Context, name string) error {
+	if _, ok := a.mcpConfigs()[name]; !ok {
+		return fmt.Errorf("%w: %q", ErrNoMCPServer, name)
+	}
+	if a.db != nil {
+		var err error
+		if _, configured := a.config().MCPServers[name]; configured {
+			err = a.db.SetMCPServerOverride(ctx, name, "")
+		} else {
+			err = a.db.DeleteMCPServerOverride(ctx, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	a.mcpMu.Lock()
+	s := a.mcpServers[name]
+	delete(a.mcpServers, name)
+	delete(a.mcpToolsets, name)
+	delete(a.mcpOwners, name)
+	if _, configured := a.config().MCPServers[name]; configured {
+		a.mcpOverrides[name] = nil
+	} else {
+		delete(a.mcpOverrides, name)
+	}
+	a.mcpMu.Unlock()
+
+	if s != nil {
+		s.close()
+	}
+	if a.db != nil {
+		if err := a.db.DeleteMCPToolSchemas(ctx, name); err != nil {
+			slog.Warn("Failed to delete saved MCP tools", "server", name, "error", err)
+		}
+	}
+	slog.Info("MCP server removed", "name", name)
+	return nil
+}
+
+// RefreshMCPServer lists a server's tools afresh, replacing the
+// definitions saved for it, and returns how many it has. Agents get the
+// new definitions from their next model call.
+func (a *Agent) RefreshMCPServer(ctx context.Context, name string) (int, error) {
+	s, ok := a.mcpServer(name)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrNoMCPServer, name)
+	}
+	listCtx, cancel := context.WithTimeout(ctx, mcpListTimeout)
+	defer cancel()
+	tools, err := s.refresh(listCtx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the tools of MCP server %q: %w", name, err)
+	}
+	slog.Info("MCP server tools refreshed", "name", name, "tools", len(tools))
+	return len(tools), nil
+}
+
+// close stops supervising the server and ends its session, stopping a
+// stdio server's process.
+func (s *mcpServer) close() {
+	if s.stop != nil {
+		s.stop()
+	}
+	s.mu.Lock()
+	cs := s.session
+	s.mu.Unlock()
+	if cs != nil {
+		_ = cs.Close()
+	}
+}
+
+// ownedToolsets is the MCP servers one sub-agent has, as its toolset.
+// Servers are looked up on every model call, so those added or removed at
+// runtime take effect on the next one.
+type ownedToolsets struct {
+	a     *Agent
+	owner string
+}
+
+var _ tool.Toolset = ownedToolsets{}
+
+func (t ownedToolsets) Name() string { return "mcp:" + t.owner }
+
+func (t ownedToolsets) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	t.a.mcpMu.RLock()
+	var servers []*mcpServer
+	for name, owner := range t.a.mcpOwners {
+		if s, ok := t.a.mcpServers[name]; ok && owner == t.owner {
+			servers = append(servers, s)
+		}
+	}
+	t.a.mcpMu.RUnlock()
+	sort.Slice(servers, func(i, j int) bool { return servers[i].name < servers[j].name })
+
+	var all []tool.Tool
+	for _, s := range servers {
+		list, err := agentToolset{s}.Tools(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, list...)
+	}
+	return all, nil
+}
@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/tool"
+)
+
+// newServerAgent returns an agent configured with the weather and github
+// servers, none of them running yet.
+func newServerAgent(t *testing.T) *Agent {
+	t.Helper()
+	database, err := db.InitDB(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	a := &Agent{
+		cfg: &config.Config{MCPServers: map[string]config.MCPServerConfig{
+			"weather": {Command: "weather-mcp"}, "github": {Command: "github-mcp"},
+		}},
+		db:          database,
+		mcpCtx:      context.Background(),
+		mcpServers:  make(map[string]*mcpServer),
+		mcpToolsets: make(map[string]tool.Toolset),
+		mcpOwners:   make(map[string]string),
+	}
+	a.loadMCPOverrides(context.Background())
+	return a
+}
+
+func newToolServer(t *testing.T, name, toolName string) *mcpServer {
+	t.Helper()
+	return newTestMCPServer(t, name, func(server *officialmcp.Server) {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: toolName}, func(context.Context, *officialmcp.CallToolRequest, struct{}) (*officialmcp.CallToolResult, any, error) {
+			return &officialmcp.CallToolResult{}, nil, nil
+		})
+	})
+}
+
+func TestAgent_MCPServerOverrides(t *testing.T) {
+	a := newServerAgent(t)
+	ctx := context.Background()
+	require.NoError(t, a.db.SetMCPServerOverride(ctx, "github", ""))
+	require.NoError(t, a.db.SetMCPServerOverride(ctx, "notes", `{"command":"notes-mcp","agent":"Jules"}`))
+	require.NoError(t, a.db.SetMCPServerOverride(ctx, "broken", `{not json`))
+
+	a.loadMCPOverrides(ctx)
+	assert.Equal(t, map[string]config.MCPServerConfig{
+		"weather": {Command: "weather-mcp"},
+		"notes":   {Command: "notes-mcp", Agent: "Jules"},
+	}, a.mcpConfigs())
+
+	// A reloaded config keeps the changes.
+	a.UpdateConfig(&config.Config{MCPServers: map[string]config.MCPServerConfig{"github": {}, "time": {}}})
+	assert.ElementsMatch(t, []string{"time", "notes"}, slices.Collect(maps.Keys(a.mcpConfigs())))
+}
+
+func TestOwnedToolsets(t *testing.T) {
+	a := newServerAgent(t)
+	ctx := context.Background()
+	a.register(newToolServer(t, "weather", "forecast"), mcpOwner("weather", config.MCPServerConfig{}))
+	a.register(newToolServer(t, "github", "create_issue"), mcpOwner("github", config.MCPServerConfig{}))
+
+	research := ownedToolsets{a, "ResearchAssistant"}
+	tools, err := research.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "forecast", tools[0].Name())
+
+	// Removing a server takes its tools away at once, and for good.
+	require.NoError(t, a.RemoveMCPServer(ctx, "weather"))
+	tools, err = research.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	assert.Empty(t, tools)
+	assert.NotContains(t, a.mcpConfigs(), "weather")
+	saved, err := a.db.MCPServerOverrides(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"weather": ""}, saved)
+
+	tools, err = ownedToolsets{a, "Jules"}.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "create_issue", tools[0].Name())
+
+	assert.ErrorIs(t, a.RemoveMCPServer(ctx, "weather"), ErrNoMCPServer)
+	_, err = a.RefreshMCPServer(ctx, "weather")
+	assert.ErrorIs(t, err, ErrNoMCPServer)
+}
+
+func TestAddMCPServer_Errors(t *testing.T) {
+	a := newServerAgent(t)
+	ctx := context.Background()
+
+	_, err := a.AddMCPServer(ctx, "notes/v2", config.MCPServerConfig{Command: "notes-mcp"})
+	assert.ErrorIs(t, err, ErrInvalidMCPName)
+	_, err = a.AddMCPServer(ctx, "weather", config.MCPServerConfig{Command: "weather-mcp"})
+	assert.ErrorIs(t, err, ErrMCPServerExists)
+
+	_, err = a.AddMCPServer(ctx, "notes", config.MCPServerConfig{Command: "/nonexistent/notes-mcp"})
+	assert.ErrorContains(t, err, `failed to connect to MCP server "notes"`)
+	assert.NotContains(t, a.mcpConfigs(), "notes", "a server that cannot start is not kept")
+	_, running := a.mcpServer("notes")
+	assert.False(t, running)
+	saved, err := a.db.MCPServerOverrides(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotConfig_RuntimeSettings(t *testing.T) {
+	database, err := db.InitDB(":memory:")
+	require.NoError(t, err)
+	defer database.Close()
+	ctx := context.Background()
+
+	a := &Agent{cfg: &config.Config{Bot: config.BotConfig{CompressionThreshold: 0.8, FlashTokenLimit: 1000}}, db: database}
+	assert.Equal(t, 0.8, a.botConfig(ctx).CompressionThreshold)
+
+	require.NoError(t, database.SetSetting(ctx, "compressionThreshold", "0.5"))
+	require.NoError(t, database.SetSetting(ctx, "defaultModel", "pro"))
+	require.NoError(t, database.SetSetting(ctx, "flashTokenLimit", "not a number"))
+	b := a.botConfig(ctx)
+	assert.Equal(t, 0.5, b.CompressionThreshold)
+	assert.Equal(t, config.ModelPro, b.DefaultModel)
+	assert.Equal(t, int64(1000), b.FlashTokenLimit, "invalid overrides are ignored")
+
+	// A reloaded config keeps the overrides.
+	a.UpdateConfig(&config.Config{Bot: config.BotConfig{CompressionThreshold: 0.9}})
+	assert.Equal(t, 0.5, a.botConfig(ctx).CompressionThreshold)
+}
+
+func TestAcquireMission_Limit(t *testing.T) {
+	database, err := db.InitDB(":memory:")
+	require.NoError(t, err)
+	defer database.Close()
+	ctx := context.Background()
+
+	a := &Agent{cfg: &config.Config{Bot: config.BotConfig{MaxConcurrentMissions: 1}}, db: database}
+	release, err := a.acquireMission(ctx)
+	require.NoError(t, err)
+
+	acquired := make(chan func())
+	go func() {
+		r, err := a.acquireMission(ctx)
+		if err == nil {
+			acquired <- r
+		}
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("second mission should wait for the first")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case r := <-acquired:
+		r()
+	case <-time.After(time.Second):
+		t.Fatal("second mission did not start after the first finished")
+	}
+
+	// A queued mission gives up when its context ends.
+	release, err = a.acquireMission(ctx)
+	require.NoError(t, err)
+	defer release()
+	cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = a.acquireMission(cctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Raising the limit at runtime lets the next mission through.
+	require.NoError(t, database.SetSetting(ctx, "maxConcurrentMissions", "2"))
+	r, err := a.acquireMission(ctx)
+	require.NoError(t, err)
+	r()
+}
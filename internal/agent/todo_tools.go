@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type TodoAddArgs struct {
+	Title string `json:"title" jsonschema:"A short description of the task to add."`
+}
+
+type TodoListArgs struct {
+	IncludeDone bool `json:"include_done,omitempty" jsonschema:"Also include completed tasks."`
+}
+
+type TodoCompleteArgs struct {
+	ID int64 `json:"id" jsonschema:"The numeric task ID as shown by todo_list."`
+}
+
+// newTodoTools returns the tools that let the model read and update the
+// persistent todo list of the current session (the same list as /todo).
+func (a *Agent) newTodoTools() ([]tool.Tool, error) {
+	addTool, err := functiontool.New(functiontool.Config{
+		Name:        "todo_add",
+		Description: "Adds a task to the user's persistent todo list. Use when the user asks to remember, track, or add something to their todo list.",
+	}, func(ctx tool.Context, args TodoAddArgs) (string, error) {
+		title := strings.TrimSpace(args.Title)
+		if title == "" {
+			return "", fmt.Errorf("title is required")
+		}
+		id, err := a.db.AddTask(ctx, ctx.SessionID(), title)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Added task #%d: %s", id, title), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create todo_add tool: %w", err)
+	}
+
+	listTool, err := functiontool.New(functiontool.Config{
+		Name:        "todo_list",
+		Description: "Lists the tasks on the user's persistent todo list with their IDs.",
+	}, func(ctx tool.Context, args TodoListArgs) (string, error) {
+		tasks, err := a.db.ListTasks(ctx, ctx.SessionID(), args.IncludeDone)
+		if err != nil {
+			return "", err
+		}
+		if len(tasks) == 0 {
+			return "The todo list is empty.", nil
+		}
+		var sb strings.Builder
+		for _, t := range tasks {
+			status := "open"
+			if t.Done {
+				status = "done"
+			}
+			sb.WriteString(fmt.Sprintf("#%d [%s] %s\n", t.ID, status, t.Title))
+		}
+		return sb.String(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create todo_list tool: %w", err)
+	}
+
+	completeTool, err := functiontool.New(functiontool.Config{
+		Name:        "todo_complete",
+		Description: "Marks a task on the user's todo list as done by its ID. Call todo_list first if the ID is unknown.",
+	}, func(ctx tool.Context, args TodoCompleteArgs) (string, error) {
+		found, err := a.db.CompleteTask(ctx, ctx.SessionID(), args.ID)
+		if err != nil {
+			return "", err
+		}
+		if !found {
+			return fmt.Sprintf("No open task with ID #%d.", args.ID), nil
+		}
+		return fmt.Sprintf("Marked task #%d as done.", args.ID), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create todo_complete tool: %w", err)
+	}
+
+	return []tool.Tool{addTool, listTool, completeTool}, nil
+}
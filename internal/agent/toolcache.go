@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/genai"
+)
+
+// savedTool is the definition of an MCP tool as saved between runs.
+type savedTool struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	InputSchema  any    `json:"inputSchema,omitempty"`
+	OutputSchema any    `json:"outputSchema,omitempty"`
+}
+
+// encodeTools returns the definitions of tools, as saved between runs.
+func encodeTools(tools []tool.Tool) (string, error) {
+	defs := make([]savedTool, 0, len(tools))
+	for _, t := range tools {
+		ft, ok := t.(functionTool)
+		if !ok {
+			continue
+		}
+		d := ft.Declaration()
+		defs = append(defs, savedTool{Name: d.Name, Description: d.Description, InputSchema: d.ParametersJsonSchema, OutputSchema: d.ResponseJsonSchema})
+	}
+	data, err := json.Marshal(defs)
+	return string(data), err
+}
+
+// loadTools gives the server the tools saved by an earlier run, served
+// until it first connects. The server's tool filter still applies.
+func (s *mcpServer) loadTools(data string) error {
+	var defs []savedTool
+	if err := json.Unmarshal([]byte(data), &defs); err != nil {
+		return err
+	}
+	tools := make([]tool.Tool, 0, len(defs))
+	for _, d := range defs {
+		t := &savedMCPTool{
+			decl:   &genai.FunctionDeclaration{Name: d.Name, Description: d.Description, ParametersJsonSchema: d.InputSchema, ResponseJsonSchema: d.OutputSchema},
+			server: s,
+		}
+		if s.filter == nil || s.filter(catalogContext{context.Background()}, t) {
+			tools = append(tools, t)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved, s.savedData = tools, data
+	return nil
+}
+
+// saveTools saves the definitions of the tools the server listed, if
+// they changed since they were last saved or loaded.
+func (s *mcpServer) saveTools(tools []tool.Tool) {
+	s.mu.Lock()
+	save, last := s.onToolsListed, s.savedData
+	s.mu.Unlock()
+	if save == nil {
+		return
+	}
+	data, err := encodeTools(tools)
+	if err != nil {
+		slog.Warn("Failed to encode MCP tools", "server", s.name, "error", err)
+		return
+	}
+	if data == last {
+		return
+	}
+	save(data)
+	s.mu.Lock()
+	s.savedData = data
+	s.mu.Unlock()
+}
+
+// refresh drops the cached lists and lists the server's tools afresh,
+// saving their definitions. Agents get the new tools from their next model
+// call.
+func (s *mcpServer) refresh(ctx context.Context) ([]tool.Tool, error) {
+	s.mu.Lock()
+	s.invalidateLocked()
+	s.saved = nil
+	s.mu.Unlock()
+	return s.listTools(catalogContext{ctx})
+}
+
+// savedMCPTool is a tool of a server that has not connected yet, from the
+// definition saved by an earlier run. Calling it waits for the server and
+// calls the tool it now lists under that name.
+type savedMCPTool struct {
+	decl   *genai.FunctionDeclaration
+	server *mcpServer
+}
+
+var _ functionTool = (*savedMCPTool)(nil)
+
+func (t *savedMCPTool) Name() string                            { return t.decl.Name }
+func (t *savedMCPTool) Description() string                     { return t.decl.Description }
+func (t *savedMCPTool) IsLongRunning() bool                     { return false }
+func (t *savedMCPTool) Declaration() *genai.FunctionDeclaration { return t.decl }
+
+// ProcessRequest adds the tool's declaration to the request, as ADK does
+// for the tools of a connected server.
+func (t *savedMCPTool) ProcessRequest(_ tool.Context, req *model.LLMRequest) error {
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	for _, gt := range req.Config.Tools {
+		if gt != nil && gt.FunctionDeclarations != nil {
+			gt.FunctionDeclarations = append(gt.FunctionDeclarations, t.decl)
+			return nil
+		}
+	}
+	req.Config.Tools = append(req.Config.Tools, &genai.Tool{FunctionDeclarations: []*genai.FunctionDeclaration{t.decl}})
+	return nil
+}
+
+func (t *savedMCPTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	tools, err := t.server.listTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, live := range tools {
+		if ft, ok := live.(functionTool); ok && ft.Name() == t.Name() {
+			return ft.Run(ctx, args)
+		}
+	}
+	return nil, fmt.Errorf("MCP server %q no longer has tool %q", t.server.name, t.Name())
+}
@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/model"
+)
+
+type ForecastArgs struct {
+	City string `json:"city"`
+}
+
+func newForecastServer(t *testing.T) *mcpServer {
+	t.Helper()
+	return newTestMCPServer(t, "weather", func(server *officialmcp.Server) {
+		officialmcp.AddTool(server, &officialmcp.Tool{Name: "forecast", Description: "Forecast for a city."}, func(_ context.Context, _ *officialmcp.CallToolRequest, args ForecastArgs) (*officialmcp.CallToolResult, any, error) {
+			return &officialmcp.CallToolResult{Content: []officialmcp.Content{&officialmcp.TextContent{Text: "Sunny in " + args.City}}}, nil, nil
+		})
+	})
+}
+
+func TestMCPServer_SavedTools(t *testing.T) {
+	s := newForecastServer(t)
+	var saves []string
+	s.onToolsListed = func(data string) { saves = append(saves, data) }
+	require.NoError(t, s.loadTools(`[{"name":"forecast","description":"Old forecast.","inputSchema":{"type":"object","properties":{"city":{"type":"string"}}}}]`))
+	ctx := context.Background()
+
+	// Before the server connects, agents get the saved definitions.
+	tools, err := agentToolset{s}.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "Old forecast.", tools[0].Description())
+	assert.Nil(t, s.session, "serving saved tools does not connect")
+	req := &model.LLMRequest{}
+	require.NoError(t, tools[0].(*mcpCallTool).ProcessRequest(&startedCallContext{CallbackContext: callbackContext{catalogContext{ctx}}, id: "1"}, req))
+	require.Len(t, req.Config.Tools, 1)
+	assert.Equal(t, "forecast", req.Config.Tools[0].FunctionDeclarations[0].Name)
+	assert.Same(t, tools[0], req.Tools["forecast"])
+
+	// Calling one connects and calls the server's tool.
+	tctx := &startedCallContext{CallbackContext: callbackContext{catalogContext{ctx}}, id: "1"}
+	res, err := tools[0].(*mcpCallTool).Run(tctx, map[string]any{"city": "Oslo"})
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny in Oslo", res["output"])
+
+	// From then on the server's own list is used, and saved.
+	tools, err = agentToolset{s}.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "Forecast for a city.", tools[0].Description())
+	require.Len(t, saves, 1, "definitions are saved once while they stay the same")
+	assert.Contains(t, saves[0], `"description":"Forecast for a city."`)
+
+	require.NoError(t, s.loadTools(saves[0]))
+	assert.Equal(t, "Forecast for a city.", s.saved[0].Description())
+	assert.Equal(t, tools[0].(*mcpCallTool).Declaration().ParametersJsonSchema, s.saved[0].(functionTool).Declaration().ParametersJsonSchema)
+}
+
+func TestMCPServer_Refresh(t *testing.T) {
+	s := newForecastServer(t)
+	var saves []string
+	s.onToolsListed = func(data string) { saves = append(saves, data) }
+	ctx := context.Background()
+	_, err := s.Tools(catalogContext{ctx})
+	require.NoError(t, err)
+	cached := s.tools
+	require.NotNil(t, cached, "the server announces changes, so its list is cached")
+
+	tools, err := s.refresh(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.NotSame(t, cached, s.tools, "the cached list is replaced")
+	assert.Len(t, saves, 1, "unchanged definitions are not saved again")
+}
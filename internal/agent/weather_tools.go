@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type GetWeatherArgs struct {
+	Location string `json:"location,omitempty" jsonschema:"The place, e.g. 'Dallas, TX', 'Paris, FR' or 'latitude,longitude'. Empty uses the configured location."`
+	Days     int    `json:"days,omitempty" jsonschema:"How many days to forecast (default 3, at most 7)."`
+	Units    string `json:"units,omitempty" jsonschema:"metric or imperial; defaults to the configured units."`
+}
+
+// newWeatherTool returns the tool that reports current weather and a
+// forecast from Open-Meteo, which needs no API key.
+func (a *Agent) newWeatherTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "get_weather",
+		Description: "Gets the current weather and a daily forecast for a place. Use when asked for the weather or to include it in a briefing; leave location empty to use the user's configured location.",
+	}, func(ctx tool.Context, args GetWeatherArgs) (string, error) {
+		w := a.config().Weather
+		location := strings.TrimSpace(args.Location)
+		if location == "" {
+			location = w.Location
+		}
+		if location == "" {
+			return "", fmt.Errorf("location is required: none was given and weather.location is not configured")
+		}
+		imperial := w.Imperial()
+		if args.Units != "" {
+			imperial = args.Units == config.UnitsImperial
+		}
+		place, err := tools.Geocode(ctx, location)
+		if err != nil {
+			return "", err
+		}
+		return tools.Forecast(ctx, place, args.Days, imperial)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create get_weather tool: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// wsSubprotocol is the WebSocket subprotocol of MCP servers.
+const wsSubprotocol = "mcp"
+
+// wsTransport connects to a server at a ws:// or wss:// URL, sending each
+// JSON-RPC message as one text message. The connection is dialled like
+// client's requests: through its (SSRF-safe) dialer, with its credentials.
+type wsTransport struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (t *wsTransport) Connect(ctx context.Context) (officialmcp.Connection, error) {
+	dialer := websocket.Dialer{Subprotocols: []string{wsSubprotocol}, Proxy: http.ProxyFromEnvironment}
+	header := http.Header{}
+	rt := t.client.Transport
+	if auth, ok := rt.(*authTransport); ok {
+		// Credentials are set on the handshake, an HTTP request to the
+		// same host.
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, wsHTTPURL(t.endpoint), nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := auth.authorize(req); err != nil {
+			return nil, fmt.Errorf("failed to authorize MCP WebSocket: %w", err)
+		}
+		header = req.Header
+		rt = auth.base
+	}
+	if ht, ok := rt.(*http.Transport); ok {
+		dialer.NetDialContext = ht.DialContext
+		dialer.TLSClientConfig = ht.TLSClientConfig
+		if ht.Proxy != nil {
+			dialer.Proxy = ht.Proxy
+		}
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, t.endpoint, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("MCP WebSocket handshake failed: %s", resp.Status)
+		}
+		return nil, err
+	}
+	return newWSConn(conn), nil
+}
+
+// wsHTTPURL is the http(s) URL of a WebSocket handshake.
+func wsHTTPURL(endpoint string) string {
+	if rest, ok := strings.CutPrefix(endpoint, "ws://"); ok {
+		return "http://" + rest
+	}
+	return "https://" + strings.TrimPrefix(endpoint, "wss://")
+}
+
+// wsConn is an officialmcp.Connection over a WebSocket.
+type wsConn struct {
+	conn *websocket.Conn
+
+	writeMu sync.Mutex // gorilla allows one writer at a time
+
+	incoming  chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+	readErr   error // why the socket closed; set before closed is closed
+}
+
+func newWSConn(conn *websocket.Conn) *wsConn {
+	c := &wsConn{conn: conn, incoming: make(chan []byte), closed: make(chan struct{})}
+	go c.readLoop()
+	return c
+}
+
+// readLoop passes the messages read to Read until the connection fails.
+func (c *wsConn) readLoop() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.shutdown(err)
+			return
+		}
+		select {
+		case c.incoming <- data:
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+func (c *wsConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case data := <-c.incoming:
+		return jsonrpc.DecodeMessage(data)
+	case <-c.closed:
+		if c.readErr != nil && !websocket.IsCloseError(c.readErr, websocket.CloseNormalClosure) {
+			return nil, c.readErr
+		}
+		return nil, errors.New("MCP WebSocket closed")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *wsConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetWriteDeadline(deadline)
+		defer func() { _ = c.conn.SetWriteDeadline(time.Time{}) }()
+	}
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (c *wsConn) Close() error {
+	select {
+	case <-c.closed:
+	default:
+		// WriteControl may run alongside Write.
+		_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	}
+	c.shutdown(nil)
+	return nil
+}
+
+// shutdown closes the socket once, unblocking Read and readLoop.
+func (c *wsConn) shutdown(err error) {
+	c.closeOnce.Do(func() {
+		c.readErr = err
+		close(c.closed)
+		_ = c.conn.Close()
+	})
+}
+
+func (c *wsConn) SessionID() string { return "" }
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connTransport hands out an established connection.
+type connTransport struct{ conn officialmcp.Connection }
+
+func (t connTransport) Connect(context.Context) (officialmcp.Connection, error) { return t.conn, nil }
+
+func TestWSTransport(t *testing.T) {
+	server := officialmcp.NewServer(&officialmcp.Implementation{Name: "gateway"}, nil)
+	officialmcp.AddTool(server, &officialmcp.Tool{Name: "echo"}, func(_ context.Context, _ *officialmcp.CallToolRequest, in struct {
+		Text string `json:"text"`
+	}) (*officialmcp.CallToolResult, any, error) {
+		return &officialmcp.CallToolResult{Content: []officialmcp.Content{&officialmcp.TextContent{Text: in.Text}}}, nil, nil
+	})
+
+	var auth string
+	upgrader := websocket.Upgrader{Subprotocols: []string{wsSubprotocol}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		assert.Equal(t, wsSubprotocol, conn.Subprotocol())
+		ss, err := server.Connect(context.Background(), connTransport{newWSConn(conn)}, nil)
+		if err == nil {
+			t.Cleanup(func() { _ = ss.Close() })
+		}
+	}))
+	defer ts.Close()
+	endpoint := "ws" + strings.TrimPrefix(ts.URL, "http")
+
+	client, err := authClient(context.Background(), &config.MCPAuthConfig{Type: config.MCPAuthBearer, Token: "s3cret"}, wsHTTPURL(endpoint), &http.Client{}, func(s string) string { return s })
+	require.NoError(t, err)
+	s, err := newMCPServer("gateway", &wsTransport{endpoint: endpoint, client: client}, nil, nil, nil)
+	require.NoError(t, err)
+	cs, err := s.clientSession(context.Background())
+	require.NoError(t, err)
+	defer func() { _ = cs.Close() }()
+
+	res, err := cs.CallTool(context.Background(), &officialmcp.CallToolParams{Name: "echo", Arguments: map[string]any{"text": "over websocket"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"over websocket"}, texts(res))
+	assert.Equal(t, "Bearer s3cret", auth, "credentials go on the handshake")
+}
+
+func TestWSHTTPURL(t *testing.T) {
+	assert.Equal(t, "http://mcp.local:8080/ws", wsHTTPURL("ws://mcp.local:8080/ws"))
+	assert.Equal(t, "https://mcp.example.com/", wsHTTPURL("wss://mcp.example.com/"))
+}
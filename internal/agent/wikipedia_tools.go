@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/i18n"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type WikipediaArgs struct {
+	Query    string `json:"query" jsonschema:"What to look up: an article title or search terms."`
+	Language string `json:"language,omitempty" jsonschema:"The Wikipedia language code, e.g. en or es. Defaults to the user's language."`
+}
+
+// newWikipediaTool returns the tool that looks up background facts on
+// Wikipedia: the lead of the best-matching article, and the titles of the
+// other matches to look up next.
+func newWikipediaTool() (tool.Tool, error) {
+	t, err := functiontool.New(functiontool.Config{
+		Name:        "wikipedia",
+		Description: "Searches Wikipedia and returns the summary of the best-matching article with its link, plus other matching titles. Use for stable background facts (people, places, organisations, concepts, history) rather than web_search; use web_search for news.",
+	}, func(ctx tool.Context, args WikipediaArgs) (string, error) {
+		lang := args.Language
+		if lang == "" {
+			lang = i18n.FromContext(ctx)
+		}
+		results, err := tools.SearchWikipedia(ctx, lang, args.Query, tools.DefaultWikipediaResults)
+		if err != nil {
+			return "", err
+		}
+		if len(results) == 0 {
+			return fmt.Sprintf("No Wikipedia articles match %q.", args.Query), nil
+		}
+		s, err := tools.GetWikipediaSummary(ctx, lang, results[0].Title)
+		if err != nil {
+			return "", err
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("# %s\n", s.Title))
+		if s.Description != "" {
+			sb.WriteString("_" + s.Description + "_\n")
+		}
+		sb.WriteString("\n" + s.Extract + "\n")
+		if s.URL != "" {
+			sb.WriteString("\nSource: " + s.URL)
+			if !s.Modified.IsZero() {
+				sb.WriteString(" (last edited " + s.Modified.Format("2006-01-02") + ")")
+			}
+			sb.WriteString("\n")
+		}
+		if s.Disambiguation {
+			sb.WriteString("\nThis is a disambiguation page: look up one of the specific articles instead.\n")
+		}
+		if len(results) > 1 {
+			sb.WriteString("\nOther matching articles:\n")
+			for _, r := range results[1:] {
+				sb.WriteString("- " + r.Title)
+				if r.Description != "" {
+					sb.WriteString(" — " + r.Description)
+				}
+				sb.WriteString("\n")
+			}
+		}
+		return sb.String(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wikipedia tool: %w", err)
+	}
+	return t, nil
+}
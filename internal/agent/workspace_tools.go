@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type ReadFileArgs struct {
+	Path string `json:"path" jsonschema:"The file's path in the workspace, e.g. 'notes/todo.md'."`
+}
+
+type WriteFileArgs struct {
+	Path    string `json:"path" jsonschema:"The file's path in the workspace; missing directories are created."`
+	Content string `json:"content" jsonschema:"The text to write."`
+	Append  bool   `json:"append,omitempty" jsonschema:"Add to the end of the file instead of replacing it."`
+}
+
+type ListDirArgs struct {
+	Path string `json:"path,omitempty" jsonschema:"The directory in the workspace; empty for the top."`
+}
+
+// newWorkspaceTools returns the tools that read and write files in the
+// workspace directory, for drafts, scratch notes and reports assembled in
+// parts. Paths can't leave the workspace.
+func (a *Agent) newWorkspaceTools() ([]tool.Tool, error) {
+	workspace := func() tools.Workspace { return tools.Workspace{Dir: a.config().WorkspaceDir} }
+
+	readTool, err := functiontool.New(functiontool.Config{
+		Name:        "read_file",
+		Description: "Reads a text file from your workspace, a private directory for drafts and notes (files up to 1 MB).",
+	}, func(ctx tool.Context, args ReadFileArgs) (string, error) {
+		return workspace().ReadFile(args.Path)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create read_file tool: %w", err)
+	}
+
+	writeTool, err := functiontool.New(functiontool.Config{
+		Name:        "write_file",
+		Description: "Writes or appends text to a file in your workspace, a private directory for drafts, scratch notes and reports assembled in parts (files up to 1 MB).",
+	}, func(ctx tool.Context, args WriteFileArgs) (string, error) {
+		size, err := workspace().WriteFile(args.Path, args.Content, args.Append)
+		if err != nil {
+			return "", err
+		}
+		verb := "Wrote"
+		if args.Append {
+			verb = "Appended to"
+		}
+		return fmt.Sprintf("%s %s (%d bytes).", verb, strings.TrimPrefix(args.Path, "/"), size), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create write_file tool: %w", err)
+	}
+
+	listTool, err := functiontool.New(functiontool.Config{
+		Name:        "list_dir",
+		Description: "Lists the files and directories in your workspace, or in a directory of it.",
+	}, func(ctx tool.Context, args ListDirArgs) (string, error) {
+		entries, err := workspace().List(args.Path)
+		if err != nil {
+			return "", err
+		}
+		if len(entries) == 0 {
+			return "The directory is empty.", nil
+		}
+		var sb strings.Builder
+		for _, e := range entries {
+			if e.Dir {
+				sb.WriteString(fmt.Sprintf("%s/\n", e.Name))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("%s (%d bytes, %s)\n", e.Name, e.Size, e.Modified.Format("2006-01-02 15:04")))
+		}
+		return sb.String(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create list_dir tool: %w", err)
+	}
+	return []tool.Tool{readTool, writeTool, listTool}, nil
+}
@@ -0,0 +1,276 @@
+// Package backup writes timestamped database snapshots and report
+// archives, rotates old ones, and optionally copies each new file to an
+// off-site target.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDir is where snapshots are written, inside the data directory,
+	// unless params.dir is set.
+	DefaultDir = "backups"
+	// DefaultKeep is how many snapshots are kept unless params.keep is set.
+	DefaultKeep = 7
+
+	filePrefix    = "ravenbot-"
+	fileSuffix    = ".db"
+	archivePrefix = "ravenbot-reports-"
+	archiveSuffix = ".tar.gz"
+	stampLayout   = "20060102-150405.000"
+)
+
+// Options configures a backup run.
+type Options struct {
+	Dir  string
+	Keep int
+	// KeepDays also removes snapshots older than this many days; zero
+	// keeps them regardless of age. The newest snapshot is always kept.
+	KeepDays int
+	// Reports is a directory to archive next to the snapshot, such as the
+	// saved job reports; empty skips it.
+	Reports string
+	// Upload is an optional copy target: "s3://bucket/prefix" (via the aws
+	// CLI) or any rsync destination such as "nas:/volume1/backups".
+	Upload string
+}
+
+// OptionsFromParams reads the dir, keep, keep_days, reports and upload job
+// params. Without a dir, snapshots go to DefaultDir inside dataDir;
+// reports "true" archives reportsDir.
+func OptionsFromParams(params map[string]string, dataDir, reportsDir string) Options {
+	opts := Options{Dir: params["dir"], Upload: strings.TrimSpace(params["upload"])}
+	if opts.Dir == "" {
+		opts.Dir = filepath.Join(dataDir, DefaultDir)
+	}
+	opts.Keep = DefaultKeep
+	if v, err := strconv.Atoi(params["keep"]); err == nil && v > 0 {
+		opts.Keep = v
+	}
+	if v, err := strconv.Atoi(params["keep_days"]); err == nil && v > 0 {
+		opts.KeepDays = v
+	}
+	if params["reports"] == "true" {
+		opts.Reports = reportsDir
+	}
+	return opts
+}
+
+// Snapshotter writes a consistent copy of a database to a new file.
+type Snapshotter interface {
+	Snapshot(ctx context.Context, path string) error
+}
+
+// Result describes a completed snapshot.
+type Result struct {
+	Path    string
+	Size    int64
+	Archive string   // report archive written next to the snapshot, if any
+	Removed []string // rotated-out snapshots and archives
+	// Uploaded is the target the snapshot (and archive) was copied to, if
+	// any.
+	Uploaded string
+}
+
+// runCommand runs an external upload command; replaced in tests.
+var runCommand = func(ctx context.Context, name string, args ...string) error {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Run snapshots the database into opts.Dir, archives opts.Reports, deletes
+// the snapshots and archives past the retention settings, then uploads the
+// new files. If a later step fails, the local snapshot is kept and the
+// result is returned with the error.
+func Run(ctx context.Context, s Snapshotter, opts Options, now time.Time) (Result, error) {
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return Result{}, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	stamp := now.UTC().Format(stampLayout)
+	path := filepath.Join(opts.Dir, filePrefix+stamp+fileSuffix)
+	if err := s.Snapshot(ctx, path); err != nil {
+		return Result{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to stat snapshot: %w", err)
+	}
+	res := Result{Path: path, Size: info.Size()}
+
+	if opts.Reports != "" {
+		archive := filepath.Join(opts.Dir, archivePrefix+stamp+archiveSuffix)
+		ok, err := archiveDir(opts.Reports, archive)
+		if err != nil {
+			return res, fmt.Errorf("failed to archive reports: %w", err)
+		}
+		if ok {
+			res.Archive = archive
+		}
+	}
+
+	var cutoff time.Time
+	if opts.KeepDays > 0 {
+		cutoff = now.AddDate(0, 0, -opts.KeepDays)
+	}
+	for _, kind := range [][2]string{{filePrefix, fileSuffix}, {archivePrefix, archiveSuffix}} {
+		removed, err := rotate(opts.Dir, kind[0], kind[1], opts.Keep, cutoff)
+		res.Removed = append(res.Removed, removed...)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	if opts.Upload != "" {
+		for _, p := range []string{path, res.Archive} {
+			if p == "" {
+				continue
+			}
+			if err := upload(ctx, p, opts.Upload); err != nil {
+				return res, fmt.Errorf("failed to upload %s: %w", filepath.Base(p), err)
+			}
+		}
+		res.Uploaded = opts.Upload
+	}
+	return res, nil
+}
+
+// rotate removes the files in dir named prefix+timestamp+suffix beyond the
+// newest keep, and those older than cutoff unless cutoff is zero. The
+// newest is never removed. Names sort chronologically.
+func rotate(dir, prefix, suffix string, keep int, cutoff time.Time) ([]string, error) {
+	if keep <= 0 {
+		keep = DefaultKeep
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+	type backupFile struct {
+		name  string
+		taken time.Time
+	}
+	var files []backupFile
+	for _, e := range entries {
+		stamp, ok := strings.CutPrefix(e.Name(), prefix)
+		if e.IsDir() || !ok || !strings.HasSuffix(stamp, suffix) {
+			continue
+		}
+		taken, err := time.Parse(stampLayout, strings.TrimSuffix(stamp, suffix))
+		if err != nil {
+			continue // not one of ours
+		}
+		files = append(files, backupFile{e.Name(), taken})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+
+	var removed []string
+	for i, f := range files[:max(len(files)-1, 0)] {
+		if i >= len(files)-keep && (cutoff.IsZero() || !f.taken.Before(cutoff)) {
+			continue
+		}
+		path := filepath.Join(dir, f.name)
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove old backup: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// archiveDir writes the regular files under dir to a gzipped tarball at
+// dst. It reports false, without an error, when dir doesn't exist or is
+// empty.
+func archiveDir(dir, dst string) (bool, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(files) == 0) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	tmp := dst + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = os.Remove(tmp) }()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, path := range files {
+		if err := addFile(tw, dir, path); err != nil {
+			_ = f.Close()
+			return false, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		_ = f.Close()
+		return false, err
+	}
+	if err := gz.Close(); err != nil {
+		_ = f.Close()
+		return false, err
+	}
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp, dst)
+}
+
+func addFile(tw *tar.Writer, root, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return err
+	}
+	hdr.Name = filepath.ToSlash(rel)
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func upload(ctx context.Context, path, target string) error {
+	if strings.HasPrefix(target, "s3://") {
+		return runCommand(ctx, "aws", "s3", "cp", path, strings.TrimSuffix(target, "/")+"/"+filepath.Base(path))
+	}
+	return runCommand(ctx, "rsync", "-a", path, target)
+}
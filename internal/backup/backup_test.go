@@ -0,0 +1,175 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fileSnapshotter struct{ err error }
+
+func (f fileSnapshotter) Snapshot(_ context.Context, path string) error {
+	if f.err != nil {
+		return f.err
+	}
+	return os.WriteFile(path, []byte("snapshot"), 0644)
+}
+
+func TestOptionsFromParams(t *testing.T) {
+	opts := OptionsFromParams(nil, "/data", "/reports")
+	assert.Equal(t, Options{Dir: "/data/backups", Keep: DefaultKeep}, opts)
+
+	opts = OptionsFromParams(map[string]string{"dir": "/srv/backups", "keep": "3", "keep_days": "30", "reports": "true", "upload": " s3://bucket/raven "}, "/data", "/reports")
+	assert.Equal(t, Options{Dir: "/srv/backups", Keep: 3, KeepDays: 30, Reports: "/reports", Upload: "s3://bucket/raven"}, opts)
+
+	assert.Equal(t, DefaultKeep, OptionsFromParams(map[string]string{"keep": "-1"}, "data", "").Keep)
+	assert.Zero(t, OptionsFromParams(map[string]string{"keep_days": "soon"}, "data", "").KeepDays)
+}
+
+func TestRun_Rotates(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	opts := Options{Dir: dir, Keep: 2}
+
+	var last Result
+	for i := range 4 {
+		res, err := Run(context.Background(), fileSnapshotter{}, opts, start.AddDate(0, 0, i))
+		require.NoError(t, err)
+		last = res
+	}
+	assert.Equal(t, filepath.Join(dir, "ravenbot-20261004-030000.000.db"), last.Path)
+	assert.Equal(t, int64(len("snapshot")), last.Size)
+	assert.Equal(t, []string{filepath.Join(dir, "ravenbot-20261002-030000.000.db")}, last.Removed)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"ravenbot-20261003-030000.000.db", "ravenbot-20261004-030000.000.db"}, names)
+}
+
+func TestRun_KeepDays(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	opts := Options{Dir: dir, Keep: 10, KeepDays: 2}
+	for i := range 4 {
+		_, err := Run(context.Background(), fileSnapshotter{}, opts, start.AddDate(0, 0, i))
+		require.NoError(t, err)
+	}
+	// The first snapshot was more than two days old by the fourth run.
+	assert.NoFileExists(t, filepath.Join(dir, "ravenbot-20261001-030000.000.db"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ravenbot-notes.db"), nil, 0644))
+
+	// A month later only the new snapshot is within the window.
+	res, err := Run(context.Background(), fileSnapshotter{}, opts, start.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Len(t, res.Removed, 3)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"ravenbot-20261101-030000.000.db", "ravenbot-notes.db"}, names, "files that aren't snapshots are left alone")
+
+	// The newest snapshot is kept however old it is.
+	assert.Empty(t, mustRotate(t, dir, start.AddDate(1, 0, 0)))
+}
+
+func mustRotate(t *testing.T, dir string, cutoff time.Time) []string {
+	t.Helper()
+	removed, err := rotate(dir, filePrefix, fileSuffix, 1, cutoff)
+	require.NoError(t, err)
+	return removed
+}
+
+func TestRun_Reports(t *testing.T) {
+	dir, reports := t.TempDir(), t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(reports, "2026"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(reports, "2026", "Daily_Briefing.md"), []byte("# Briefing"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(reports, "notes.md"), []byte("notes"), 0644))
+
+	start := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	opts := Options{Dir: dir, Keep: 2, Reports: reports}
+	var res Result
+	for i := range 3 {
+		var err error
+		res, err = Run(context.Background(), fileSnapshotter{}, opts, start.AddDate(0, 0, i))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, filepath.Join(dir, "ravenbot-reports-20261003-030000.000.tar.gz"), res.Archive)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "ravenbot-20261001-030000.000.db"),
+		filepath.Join(dir, "ravenbot-reports-20261001-030000.000.tar.gz"),
+	}, res.Removed, "archives are rotated like snapshots")
+
+	f, err := os.Open(res.Archive)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+	assert.Equal(t, map[string]string{"2026/Daily_Briefing.md": "# Briefing", "notes.md": "notes"}, files)
+
+	// A missing reports directory is nothing to archive, not a failure.
+	res, err = Run(context.Background(), fileSnapshotter{}, Options{Dir: dir, Keep: 2, Reports: filepath.Join(reports, "missing")}, start.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	assert.Empty(t, res.Archive)
+}
+
+func TestRun_Upload(t *testing.T) {
+	var commands []string
+	orig := runCommand
+	defer func() { runCommand = orig }()
+	runCommand = func(_ context.Context, name string, args ...string) error {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		if strings.Contains(args[len(args)-1], "offline") {
+			return errors.New("host unreachable")
+		}
+		return nil
+	}
+
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 14, 7, 30, 0, 0, time.UTC)
+
+	res, err := Run(context.Background(), fileSnapshotter{}, Options{Dir: dir, Keep: 5, Upload: "s3://bucket/raven/"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "s3://bucket/raven/", res.Uploaded)
+
+	res, err = Run(context.Background(), fileSnapshotter{}, Options{Dir: dir, Keep: 5, Upload: "offline:/backups"}, now.Add(time.Minute))
+	require.Error(t, err)
+	assert.Empty(t, res.Uploaded)
+	assert.FileExists(t, res.Path, "local snapshot is kept when the upload fails")
+
+	require.Len(t, commands, 2)
+	assert.Equal(t, "aws s3 cp "+filepath.Join(dir, "ravenbot-20261014-073000.000.db")+" s3://bucket/raven/ravenbot-20261014-073000.000.db", commands[0])
+	assert.Equal(t, "rsync -a "+filepath.Join(dir, "ravenbot-20261014-073100.000.db")+" offline:/backups", commands[1])
+}
+
+func TestRun_SnapshotError(t *testing.T) {
+	_, err := Run(context.Background(), fileSnapshotter{err: errors.New("unsupported")}, Options{Dir: t.TempDir(), Keep: 1}, time.Now())
+	assert.Error(t, err)
+}
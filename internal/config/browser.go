@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// BrowserConfig sets how the browser tools run headless Chrome.
+type BrowserConfig struct {
+	// RemoteURL is the DevTools endpoint of a Chrome running elsewhere,
+	// such as a chromedp/headless-shell container ("ws://chrome:9222").
+	// Empty starts a local Chrome.
+	RemoteURL string `json:"remoteURL,omitempty"`
+	// ExecPath is the local Chrome binary; defaults to CHROME_BIN, then to
+	// the first Chrome or Chromium found on the PATH.
+	ExecPath string `json:"execPath,omitempty"`
+	// NoSandbox disables Chrome's sandbox, which containers without user
+	// namespaces need (CHROMEDP_NO_SANDBOX=true).
+	NoSandbox bool `json:"noSandbox,omitempty"`
+}
+
+// validate reports an invalid setting.
+func (b BrowserConfig) validate() error {
+	if b.RemoteURL == "" {
+		return nil
+	}
+	u, err := url.Parse(b.RemoteURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid remoteURL %q", b.RemoteURL)
+	}
+	switch u.Scheme {
+	case "ws", "wss", "http", "https":
+		return nil
+	default:
+		return fmt.Errorf("invalid remoteURL %q: must be a ws(s) or http(s) URL", b.RemoteURL)
+	}
+}
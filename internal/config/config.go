@@ -2,25 +2,213 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/i18n"
 )
 
 type MCPServerConfig struct {
-	Command string            `json:"command"`
-	Args    []string          `json:"args"`
-	Env     map[string]string `json:"env,omitempty"`
+	Command string `json:"command"`
+	// Args and Env values expand $VAR and ${VAR} (see Config.ExpandEnv)
+	// when the server starts, so tokens can come from the environment or
+	// the secrets file.
+	Args []string          `json:"args" expand:"-"`
+	Env  map[string]string `json:"env,omitempty" expand:"-"`
+
+	// Transport picks the protocol for a server whose Command is an
+	// http(s) URL: MCPTransportStreamable (the default) or MCPTransportSSE
+	// for servers that only speak the older HTTP+SSE transport. A ws(s)
+	// URL always connects over WebSocket.
+	Transport string `json:"transport,omitempty"`
+	// Auth is sent with every request to a remote server. Its values
+	// expand like Args.
+	Auth *MCPAuthConfig `json:"auth,omitempty" expand:"-"`
+
+	// ToolAllowlist, if set, limits the tools exposed to the agents to
+	// those matching one of its names or path.Match patterns ("get_*").
+	// ToolDenylist hides matching tools, and wins over the allowlist.
+	ToolAllowlist []string `json:"toolAllowlist,omitempty"`
+	ToolDenylist  []string `json:"toolDenylist,omitempty"`
+
+	// Roots are the directories the server may operate on, advertised
+	// with the MCP roots capability ($DATA_DIR and $REPORTS_DIR expand).
+	// Servers without roots are not offered the capability.
+	Roots []string `json:"roots,omitempty" expand:"-"`
+
+	// Sampling, when set, lets the server ask ravenbot's models for
+	// completions (MCP sampling). Servers without it cannot.
+	Sampling *MCPSamplingConfig `json:"sampling,omitempty"`
+
+	// LogLevel is the least severe of the server's log messages ravenbot
+	// asks for (one of MCPLogLevels; default DefaultMCPLogLevel). They are
+	// logged tagged with the server's name.
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// MaxConcurrentCalls caps the agents' tool calls running on the server
+	// at once (default DefaultMCPMaxConcurrentCalls); 1 makes them one at
+	// a time. Calls the model makes together run in parallel up to it.
+	MaxConcurrentCalls int `json:"maxConcurrentCalls,omitempty"`
+
+	// Limits caps the resources of a stdio server's process.
+	Limits *MCPLimitsConfig `json:"limits,omitempty"`
+
+	// Agent is the sub-agent given the server's tools (one of MCPAgents).
+	// By default the built-in servers go to theirs (weather to the
+	// ResearchAssistant, github to Jules, …) and others to none.
+	Agent string `json:"agent,omitempty"`
 }
 
-type JobConfig struct {
-	Name     string            `json:"name"`
-	Schedule string            `json:"schedule"`
-	Type     string            `json:"type"`
-	Params   map[string]string `json:"params"`
+// Remote reports whether the server is reached at a URL (http(s) or ws(s))
+// rather than started as a subprocess.
+func (s MCPServerConfig) Remote() bool {
+	for _, scheme := range []string{"http://", "https://", "ws://", "wss://"} {
+		if strings.HasPrefix(s.Command, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExposesTool reports whether the server's allow and deny lists let the
+// agents see the named tool.
+func (s MCPServerConfig) ExposesTool(name string) bool {
+	if matchesAny(s.ToolDenylist, name) {
+		return false
+	}
+	return len(s.ToolAllowlist) == 0 || matchesAny(s.ToolAllowlist, name)
 }
 
+func matchesAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MCPSamplingConfig is the approval policy for a server's sampling
+// requests.
+type MCPSamplingConfig struct {
+	// Model is ModelFlash (the default), ModelPro, or ModelAuto to follow
+	// the server's model preferences.
+	Model string `json:"model,omitempty"`
+	// MaxTokens caps each completion (default DefaultSamplingMaxTokens).
+	MaxTokens int64 `json:"maxTokens,omitempty"`
+	// PerHour is how many requests are approved per hour (default
+	// DefaultSamplingPerHour); negative removes the limit.
+	PerHour int `json:"perHour,omitempty"`
+}
+
+// MCPLimitsConfig caps a stdio server's process with resource limits
+// (rlimits, Linux only), so a leaking server is killed instead of taking
+// the host down with it. Zero leaves a resource unlimited.
+type MCPLimitsConfig struct {
+	// MemoryMB caps the process's data memory (RLIMIT_DATA): its heap and
+	// other private writable mappings, not the address space it merely
+	// reserves, so runtimes such as Node and Go still start.
+	MemoryMB int `json:"memoryMB,omitempty"`
+	// CPUSeconds is the CPU time the process may use before it is killed
+	// (RLIMIT_CPU); a restarted process starts afresh.
+	CPUSeconds int `json:"cpuSeconds,omitempty"`
+	// Nice lowers the process's scheduling priority (1-19), so a busy
+	// server leaves CPU for the bot.
+	Nice int `json:"nice,omitempty"`
+}
+
+// MCPAuthConfig holds the credentials for a remote MCP server. Values
+// expand environment variables, so secrets can stay out of config.json
+// (e.g. "token": "$NOTION_MCP_TOKEN").
+type MCPAuthConfig struct {
+	// Type is MCPAuthBearer (Token), MCPAuthHeader (an API key: Value in
+	// Header), MCPAuthBasic (Username, Password) or MCPAuthOAuth (an OAuth
+	// client-credentials grant from TokenURL, refreshed as it expires).
+	Type         string   `json:"type"`
+	Token        string   `json:"token,omitempty"`
+	Header       string   `json:"header,omitempty"`
+	Value        string   `json:"value,omitempty"`
+	Username     string   `json:"username,omitempty"`
+	Password     string   `json:"password,omitempty"`
+	TokenURL     string   `json:"tokenUrl,omitempty"`
+	ClientID     string   `json:"clientId,omitempty"`
+	ClientSecret string   `json:"clientSecret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+}
+
+// MCP auth types.
+const (
+	MCPAuthBearer = "bearer"
+	MCPAuthHeader = "header"
+	MCPAuthBasic  = "basic"
+	MCPAuthOAuth  = "oauth"
+)
+
+// validate reports a missing or unknown setting.
+func (a *MCPAuthConfig) validate() error {
+	var missing string
+	switch a.Type {
+	case MCPAuthBearer:
+		if a.Token == "" {
+			missing = "token"
+		}
+	case MCPAuthHeader:
+		if a.Header == "" {
+			missing = "header"
+		}
+	case MCPAuthBasic:
+		if a.Username == "" {
+			missing = "username"
+		}
+	case MCPAuthOAuth:
+		switch {
+		case a.TokenURL == "":
+			missing = "tokenUrl"
+		case a.ClientID == "":
+			missing = "clientId"
+		}
+	default:
+		return fmt.Errorf("invalid type %q: must be %q, %q, %q or %q", a.Type, MCPAuthBearer, MCPAuthHeader, MCPAuthBasic, MCPAuthOAuth)
+	}
+	if missing != "" {
+		return fmt.Errorf("%s auth requires %s", a.Type, missing)
+	}
+	return nil
+}
+
+// Remote MCP transports.
+const (
+	MCPTransportStreamable = "streamable"
+	MCPTransportSSE        = "sse"
+)
+
+// MCPAgents are the sub-agents MCP servers can be given to.
+var MCPAgents = []string{"ResearchAssistant", "SystemManager", "Jules"}
+
+// MCPLogLevels are the MCP log levels, least severe first.
+var MCPLogLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// MCP server defaults.
+const (
+	DefaultMCPLogLevel           = "warning" // asked of servers that set none
+	DefaultMCPMaxConcurrentCalls = 4
+)
+
+// Sampling defaults.
+const (
+	DefaultSamplingMaxTokens = 1024
+	DefaultSamplingPerHour   = 30
+)
+
 type BotConfig struct {
 	SystemPrompt         string  `json:"systemPrompt"`
 	ResearchSystemPrompt string  `json:"researchSystemPrompt"`
@@ -33,6 +221,89 @@ type BotConfig struct {
 	ProTokenLimit        int64   `json:"proTokenLimit"`
 	CompressionThreshold float64 `json:"compressionThreshold"`
 	SummaryPrompt        string  `json:"summaryPrompt"`
+
+	// MaxConcurrentMissions caps research missions running at once (0 for
+	// no limit); further missions wait. DefaultModel is ModelAuto (route
+	// each message with RoutingPrompt), ModelFlash or ModelPro. Both can be
+	// changed at runtime with /set, like the token limits and threshold.
+	MaxConcurrentMissions int    `json:"maxConcurrentMissions,omitempty"`
+	DefaultModel          string `json:"defaultModel,omitempty"`
+
+	// MaxSessionEvents is how many of a conversation's most recent events
+	// are loaded for each turn; see SessionEvents.
+	MaxSessionEvents int `json:"maxSessionEvents,omitempty"`
+
+	// ModelPricing maps model names to their token prices for /usage cost
+	// estimates.
+	ModelPricing map[string]ModelPrice `json:"modelPricing,omitempty"`
+
+	// Locale is the default language for bot replies ("en" if empty); chats
+	// can override it with /language. HelpMessages holds translated /help
+	// text keyed by locale, falling back to HelpMessage.
+	Locale       string            `json:"locale,omitempty"`
+	HelpMessages map[string]string `json:"helpMessages,omitempty"`
+
+	// FeedbackRepo ("owner/repo"), when set, makes /feedback also open a
+	// GitHub issue through the GitHub MCP tools.
+	FeedbackRepo string `json:"feedbackRepo,omitempty"`
+
+	// Admins lists the session IDs (e.g. "telegram-123456") allowed to run
+	// admin commands: /wipe-user, /reload, /backup now, changing /set, /job
+	// pause|resume, /mcp and /mcplog. Empty allows every chat the bot
+	// listens to.
+	Admins []string `json:"admins,omitempty"`
+}
+
+// DefaultSessionEvents is the event window used when MaxSessionEvents is 0.
+// Older context reaches the model through the compression summary.
+const DefaultSessionEvents = 200
+
+// SessionEvents returns how many recent events to load per turn, or 0 to
+// load the whole session (a negative MaxSessionEvents).
+func (b BotConfig) SessionEvents() int {
+	switch {
+	case b.MaxSessionEvents == 0:
+		return DefaultSessionEvents
+	case b.MaxSessionEvents < 0:
+		return 0
+	}
+	return b.MaxSessionEvents
+}
+
+// IsAdmin reports whether sessionID may run admin commands.
+func (b BotConfig) IsAdmin(sessionID string) bool {
+	return len(b.Admins) == 0 || slices.Contains(b.Admins, sessionID)
+}
+
+// Help returns the /help text for a locale.
+func (b BotConfig) Help(locale string) string {
+	if msg, ok := b.HelpMessages[locale]; ok && msg != "" {
+		return msg
+	}
+	return b.HelpMessage
+}
+
+// ModelPrice is the price in USD per million tokens.
+type ModelPrice struct {
+	InputPerMillion  float64 `json:"inputPerMillion"`
+	OutputPerMillion float64 `json:"outputPerMillion"`
+}
+
+// EstimateCost returns the estimated USD cost of the given token counts, or
+// false if the model has no configured price.
+func (b BotConfig) EstimateCost(model string, input, output int64) (float64, bool) {
+	p, ok := b.ModelPricing[model]
+	if !ok {
+		return 0, false
+	}
+	return (float64(input)*p.InputPerMillion + float64(output)*p.OutputPerMillion) / 1e6, true
+}
+
+// RateLimitConfig caps how fast a single session can use the bot. Zero
+// disables the corresponding limit.
+type RateLimitConfig struct {
+	MessagesPerMinute int `json:"messagesPerMinute"`
+	MissionsPerHour   int `json:"missionsPerHour"`
 }
 
 // Supported AI backend values.
@@ -41,6 +312,12 @@ const (
 	BackendOllama = "ollama"
 )
 
+// Default directories, relative to the working directory.
+const (
+	DefaultDataDir    = "data"
+	DefaultReportsDir = "daily_logs"
+)
+
 type Config struct {
 	// AI Backend selection ("gemini" or "ollama")
 	AIBackend string
@@ -56,18 +333,83 @@ type Config struct {
 	OllamaFlashModel string // Optional override for Flash
 	OllamaProModel   string // Optional override for Pro
 
-	TelegramBotToken string
-	TelegramChatID   int64
-	DiscordBotToken  string
-	DiscordChannelID string
-	JulesAPIKey      string
-	DBPath           string                     `json:"dbPath"`
-	Bot              BotConfig                  `json:"bot"`
-	MCPServers       map[string]MCPServerConfig `json:"mcpServers"`
-	Jobs             []JobConfig                `json:"jobs"`
+	TelegramBotToken  string
+	TelegramChatID    int64
+	DiscordBotToken   string
+	DiscordChannelID  string
+	JulesAPIKey       string
+	GitHubToken       string
+	DataDir           string                     `json:"dataDir,omitempty"`      // database, backups and MCP data (DATA_DIR)
+	ReportsDir        string                     `json:"reportsDir,omitempty"`   // saved job reports (REPORTS_DIR)
+	WorkspaceDir      string                     `json:"workspaceDir,omitempty"` // the agent's file tools; defaults to DataDir/workspace (WORKSPACE_DIR)
+	DBPath            string                     `json:"dbPath"`                 // SQLite file; defaults to DataDir/ravenbot.db
+	DatabaseURL       string                     // PostgreSQL URL; overrides DBPath when set
+	EncryptionKey     string                     // base64/hex AES-256 key for session data at rest
+	EncryptionKeyFile string                     // file holding EncryptionKey
+	SlowQueryMs       int                        `json:"slowQueryMs,omitempty"` // slow-query log threshold; 0 = default, <0 = off
+	SecretsFile       string                     `json:"secretsFile,omitempty"` // JSON object of values for ExpandEnv (RAVENBOT_SECRETS_FILE)
+	Secrets           map[string]string          `json:"-"`                     // loaded from Vault and SecretsFile
+	Timezone          string                     `json:"timezone"`
+	Bot               BotConfig                  `json:"bot"`
+	RateLimit         RateLimitConfig            `json:"rateLimit"`
+	MCPServers        map[string]MCPServerConfig `json:"mcpServers"`
+	Jobs              []JobConfig                `json:"jobs"`
+	JobCatchUp        string                     `json:"jobCatchUp,omitempty"`        // default catch-up window for jobs, e.g. "2h"; empty = off
+	MaxConcurrentJobs int                        `json:"maxConcurrentJobs,omitempty"` // jobs running at once; 0 = default, <0 = no limit
+
+	// SearchProviders are the web_search tool's providers, tried in order
+	// until one answers; a provider that fails is passed over for a few
+	// minutes. Empty uses Gemini's Google Search grounding alone.
+	SearchProviders []SearchProviderConfig `json:"searchProviders,omitempty"`
+
+	// Vault, when Path is set, reads secrets for ExpandEnv from HashiCorp
+	// Vault; the secrets file's values win over its.
+	Vault VaultConfig `json:"vault,omitempty"`
+
+	Reddit  RedditConfig  `json:"reddit,omitempty"`
+	Weather WeatherConfig `json:"weather,omitempty"`
+	Browser BrowserConfig `json:"browser,omitempty"`
+	Sandbox SandboxConfig `json:"sandbox,omitempty"`
+
+	Politeness PolitenessConfig `json:"politeness,omitempty"`
+	HTTPCache  HTTPCacheConfig  `json:"httpCache,omitempty"`
+	HostAccess HostAccessConfig `json:"hostAccess,omitempty"`
+
+	// Shortcuts maps a custom command (e.g. "/standup") to the text it
+	// expands to: another command or a canned chat prompt. "{{args}}" and
+	// "{{date}}" are substituted.
+	Shortcuts map[string]string `json:"shortcuts,omitempty"`
+
+	// Env sets environment variables, such as AI_BACKEND or
+	// TELEGRAM_BOT_TOKEN, for the settings only read from the environment;
+	// its values override the process's.
+	Env map[string]string `json:"env,omitempty" expand:"-"`
+	// Profiles are named sets of settings, such as "dev" and "prod",
+	// layered on the rest of the file when selected with --profile or
+	// RAVENBOT_PROFILE (see Config.applyProfile). A profile's env merges
+	// over Env.
+	Profiles map[string]json.RawMessage `json:"profiles,omitempty" expand:"-"`
+	Profile  string                     `json:"-"` // the profile applied, if any
+
+	// Schema is the "$schema" editors check the file against; it is
+	// otherwise ignored.
+	Schema string `json:"$schema,omitempty" expand:"-"`
 }
 
 func LoadConfig() (*Config, error) {
+	// 1. The config file's env, and its profile's, go before the
+	// environment is read
+	configPath := configFile()
+	data, err := os.ReadFile(configPath)
+	if err != nil && (!errors.Is(err, fs.ErrNotExist) || os.Getenv("RAVENBOT_CONFIG") != "") {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	profile := Profile()
+	if err != nil && profile != "" {
+		return nil, fmt.Errorf("profile %q selected, but there is no config file %s", profile, configPath)
+	}
+	applyConfigEnv(data, profile)
+
 	backend := strings.ToLower(os.Getenv("AI_BACKEND"))
 	if backend == "" {
 		backend = BackendGemini
@@ -77,21 +419,33 @@ func LoadConfig() (*Config, error) {
 	}
 
 	cfg := &Config{
-		AIBackend:        backend,
-		TelegramBotToken: os.Getenv("TELEGRAM_BOT_TOKEN"),
-		DiscordBotToken:  os.Getenv("DISCORD_BOT_TOKEN"),
-		DiscordChannelID: os.Getenv("DISCORD_CHANNEL_ID"),
-		JulesAPIKey:      os.Getenv("JULES_API_KEY"),
-		GeminiAPIKey:     os.Getenv("GEMINI_API_KEY"),
-		DBPath:           "data/ravenbot.db",
-		Bot:              BotConfig{},
+		AIBackend:         backend,
+		DiscordChannelID:  os.Getenv("DISCORD_CHANNEL_ID"),
+		EncryptionKey:     os.Getenv("RAVENBOT_ENCRYPTION_KEY"),
+		EncryptionKeyFile: os.Getenv("RAVENBOT_ENCRYPTION_KEY_FILE"),
+		Bot:               BotConfig{},
+	}
+	// Tokens may also be read from the files NAME_FILE points to
+	for name, dst := range map[string]*string{
+		"TELEGRAM_BOT_TOKEN":           &cfg.TelegramBotToken,
+		"DISCORD_BOT_TOKEN":            &cfg.DiscordBotToken,
+		"JULES_API_KEY":                &cfg.JulesAPIKey,
+		"GITHUB_PERSONAL_ACCESS_TOKEN": &cfg.GitHubToken,
+		"GEMINI_API_KEY":               &cfg.GeminiAPIKey,
+		"DATABASE_URL":                 &cfg.DatabaseURL,
+	} {
+		v, err := SecretEnv(name)
+		if err != nil {
+			return nil, err
+		}
+		*dst = v
 	}
 
 	// Backend-specific configuration
 	switch backend {
 	case BackendGemini:
 		if cfg.GeminiAPIKey == "" {
-			return nil, fmt.Errorf("GEMINI_API_KEY environment variable is required when AI_BACKEND=%s", BackendGemini)
+			return nil, fmt.Errorf("GEMINI_API_KEY (or GEMINI_API_KEY_FILE) is required when AI_BACKEND=%s", BackendGemini)
 		}
 		cfg.GeminiFlashModel = os.Getenv("GEMINI_FLASH_MODEL")
 		if cfg.GeminiFlashModel == "" {
@@ -115,21 +469,219 @@ func LoadConfig() (*Config, error) {
 	}
 
 	// 2. Load Configuration from JSON file
-	configPath := "config.json"
-	if _, err := os.Stat(configPath); err == nil {
-		file, err := os.Open(configPath)
+	if data != nil {
+		if err := decodeConfig(configPath, data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to decode config file: %w", err)
+		}
+		if profile != "" {
+			if err := cfg.applyProfile(configPath, data, profile); err != nil {
+				return nil, fmt.Errorf("failed to apply profile: %w", err)
+			}
+			cfg.Profile = profile
+		}
+		slog.Info("Loaded configuration", "file", configPath, "profile", profile)
+	} else {
+		slog.Warn("No config file found, relying on environment variables only", "file", configPath)
+	}
+
+	// Environment overrides the config file directories and timezone
+	if dir := os.Getenv("DATA_DIR"); dir != "" {
+		cfg.DataDir = dir
+	}
+	if dir := os.Getenv("REPORTS_DIR"); dir != "" {
+		cfg.ReportsDir = dir
+	}
+	if dir := os.Getenv("WORKSPACE_DIR"); dir != "" {
+		cfg.WorkspaceDir = dir
+	}
+	if cfg.DataDir == "" {
+		cfg.DataDir = DefaultDataDir
+	}
+	if cfg.ReportsDir == "" {
+		cfg.ReportsDir = DefaultReportsDir
+	}
+	if cfg.DBPath == "" {
+		cfg.DBPath = filepath.Join(cfg.DataDir, "ravenbot.db")
+	}
+	if cfg.WorkspaceDir == "" {
+		cfg.WorkspaceDir = filepath.Join(cfg.DataDir, "workspace")
+	}
+	if cfg.Browser.ExecPath == "" {
+		cfg.Browser.ExecPath = os.Getenv("CHROME_BIN")
+	}
+	if os.Getenv("CHROMEDP_NO_SANDBOX") == "true" {
+		cfg.Browser.NoSandbox = true
+	}
+	if tz := os.Getenv("TIMEZONE"); tz != "" {
+		cfg.Timezone = tz
+	}
+	if f := os.Getenv("RAVENBOT_SECRETS_FILE"); f != "" {
+		cfg.SecretsFile = f
+	}
+	if cfg.Vault.Path != "" {
+		secrets, err := cfg.Vault.load()
+		if err != nil {
+			return nil, err
+		}
+		cfg.Secrets = secrets
+	}
+	if cfg.SecretsFile != "" {
+		secrets, err := loadSecrets(cfg.SecretsFile)
 		if err != nil {
-			return nil, fmt.Errorf("failed to open config file: %w", err)
+			return nil, err
+		}
+		if cfg.Secrets == nil {
+			cfg.Secrets = secrets
 		}
-		defer func() { _ = file.Close() }()
+		for name, v := range secrets {
+			cfg.Secrets[name] = v
+		}
+	}
+	cfg.expandValues()
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", cfg.Timezone, err)
+		}
+	}
 
-		decoder := json.NewDecoder(file)
-		if err := decoder.Decode(cfg); err != nil {
-			return nil, fmt.Errorf("failed to decode config file: %w", err)
+	if cfg.Bot.Locale != "" {
+		locale, ok := i18n.Normalize(cfg.Bot.Locale)
+		if !ok {
+			return nil, fmt.Errorf("unsupported bot.locale %q (available: %s)", cfg.Bot.Locale, strings.Join(i18n.Supported(), ", "))
+		}
+		cfg.Bot.Locale = locale
+	}
+
+	for name, expansion := range cfg.Shortcuts {
+		if !strings.HasPrefix(name, "/") || strings.ContainsAny(name, " \t\n") || len(name) < 2 {
+			return nil, fmt.Errorf("invalid shortcut %q: must be a single word starting with '/'", name)
+		}
+		if strings.TrimSpace(expansion) == "" {
+			return nil, fmt.Errorf("shortcut %q has an empty expansion", name)
+		}
+	}
+
+	if cfg.Bot.DefaultModel != "" {
+		if s, _ := LookupSetting("defaultModel"); s.Validate(cfg.Bot.DefaultModel) != nil {
+			return nil, fmt.Errorf("invalid bot.defaultModel %q: must be %q, %q or %q", cfg.Bot.DefaultModel, ModelAuto, ModelFlash, ModelPro)
+		}
+	}
+	if cfg.Bot.MaxConcurrentMissions < 0 {
+		return nil, fmt.Errorf("bot.maxConcurrentMissions must not be negative")
+	}
+	for name, server := range cfg.MCPServers {
+		values := append([]string{}, server.Args...)
+		for _, v := range server.Env {
+			values = append(values, v)
+		}
+		for _, v := range values {
+			for _, key := range cfg.undefinedVars(v) {
+				slog.Warn("MCP server setting references an undefined variable", "server", name, "variable", key)
+			}
+		}
+		for _, patterns := range [][]string{server.ToolAllowlist, server.ToolDenylist} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return nil, fmt.Errorf("invalid tool pattern %q for mcpServers.%s: %w", pattern, name, err)
+				}
+			}
+		}
+		switch server.Transport {
+		case "", MCPTransportStreamable, MCPTransportSSE:
+		default:
+			return nil, fmt.Errorf("invalid mcpServers.%s.transport %q: must be %q or %q", name, server.Transport, MCPTransportStreamable, MCPTransportSSE)
+		}
+		if server.MaxConcurrentCalls < 0 {
+			return nil, fmt.Errorf("mcpServers.%s.maxConcurrentCalls must not be negative", name)
+		}
+		if l := server.Limits; l != nil {
+			if server.Remote() {
+				return nil, fmt.Errorf("mcpServers.%s.limits only apply to stdio servers", name)
+			}
+			if l.MemoryMB < 0 || l.CPUSeconds < 0 || l.Nice < 0 || l.Nice > 19 {
+				return nil, fmt.Errorf("invalid mcpServers.%s.limits: memoryMB and cpuSeconds must not be negative, nice must be 0-19", name)
+			}
+		}
+		if server.Agent != "" && !slices.Contains(MCPAgents, server.Agent) {
+			return nil, fmt.Errorf("invalid mcpServers.%s.agent %q: must be one of %s", name, server.Agent, strings.Join(MCPAgents, ", "))
+		}
+		if server.LogLevel != "" && !slices.Contains(MCPLogLevels, server.LogLevel) {
+			return nil, fmt.Errorf("invalid mcpServers.%s.logLevel %q: must be one of %s", name, server.LogLevel, strings.Join(MCPLogLevels, ", "))
+		}
+		if server.Auth != nil {
+			if !server.Remote() {
+				return nil, fmt.Errorf("mcpServers.%s.auth requires an http(s) or ws(s) command", name)
+			}
+			if err := server.Auth.validate(); err != nil {
+				return nil, fmt.Errorf("mcpServers.%s.auth: %w", name, err)
+			}
+		}
+		if server.Sampling == nil {
+			continue
+		}
+		switch server.Sampling.Model {
+		case "", ModelAuto, ModelFlash, ModelPro:
+		default:
+			return nil, fmt.Errorf("invalid mcpServers.%s.sampling.model %q: must be %q, %q or %q", name, server.Sampling.Model, ModelAuto, ModelFlash, ModelPro)
+		}
+		if server.Sampling.MaxTokens < 0 {
+			return nil, fmt.Errorf("mcpServers.%s.sampling.maxTokens must not be negative", name)
+		}
+	}
+
+	for i, p := range cfg.SearchProviders {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("searchProviders[%d]: %w", i, err)
+		}
+		if p.Type == SearchGemini && cfg.GeminiAPIKey == "" {
+			return nil, fmt.Errorf("searchProviders[%d]: gemini requires GEMINI_API_KEY", i)
+		}
+		for _, key := range cfg.undefinedVars(p.APIKey) {
+			slog.Warn("Search provider API key references an undefined variable", "provider", p.Type, "variable", key)
+		}
+	}
+
+	if err := cfg.Reddit.validate(); err != nil {
+		return nil, fmt.Errorf("reddit: %w", err)
+	}
+	if err := cfg.Weather.validate(); err != nil {
+		return nil, fmt.Errorf("weather: %w", err)
+	}
+	if err := cfg.Browser.validate(); err != nil {
+		return nil, fmt.Errorf("browser: %w", err)
+	}
+	if err := cfg.Sandbox.validate(); err != nil {
+		return nil, fmt.Errorf("sandbox: %w", err)
+	}
+	if err := cfg.Politeness.validate(); err != nil {
+		return nil, fmt.Errorf("politeness: %w", err)
+	}
+	if err := cfg.HTTPCache.validate(); err != nil {
+		return nil, fmt.Errorf("httpCache: %w", err)
+	}
+	if err := cfg.HostAccess.validate(); err != nil {
+		return nil, fmt.Errorf("hostAccess: %w", err)
+	}
+	if _, err := parseDuration(cfg.JobCatchUp); err != nil {
+		return nil, fmt.Errorf("jobCatchUp: %w", err)
+	}
+	names := make(map[string]int, len(cfg.Jobs))
+	for i, job := range cfg.Jobs {
+		if err := job.validate(); err != nil {
+			return nil, fmt.Errorf("jobs[%d] (%s): %w", i, job.Name, err)
+		}
+		if j, ok := names[strings.ToLower(job.Name)]; ok {
+			return nil, fmt.Errorf("jobs[%d] (%s): jobs[%d] has the same name; job names must be unique", i, job.Name, j)
+		}
+		names[strings.ToLower(job.Name)] = i
+	}
+	if err := validateRunAfter(cfg.Jobs); err != nil {
+		return nil, err
+	}
+	for _, v := range []string{cfg.Reddit.ClientID, cfg.Reddit.ClientSecret} {
+		for _, key := range cfg.undefinedVars(v) {
+			slog.Warn("Reddit credentials reference an undefined variable", "variable", key)
 		}
-		slog.Info("Loaded configuration from config.json")
-	} else {
-		slog.Warn("No config.json found, relying on environment variables only")
 	}
 
 	// Optional configurations for notifiers
@@ -140,6 +692,205 @@ func LoadConfig() (*Config, error) {
 		}
 		cfg.TelegramChatID = chatID
 	}
+	for i, job := range cfg.Jobs {
+		if err := cfg.checkChannel(job.Params["channel"]); err != nil {
+			return nil, fmt.Errorf("jobs[%d] (%s): params.channel: %w", i, job.Name, err)
+		}
+	}
+	cfg.checkModelPricing()
 
 	return cfg, nil
 }
+
+// checkChannel reports an error if id names a chat on a notifier that
+// isn't configured, so the job's conversation would never be seen.
+func (c *Config) checkChannel(id string) error {
+	switch {
+	case strings.HasPrefix(id, "telegram-"):
+		if c.TelegramBotToken == "" || c.TelegramChatID == 0 {
+			return fmt.Errorf("%q is a Telegram chat, but Telegram is not configured: set TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID", id)
+		}
+	case strings.HasPrefix(id, "discord-"):
+		if c.DiscordBotToken == "" || c.DiscordChannelID == "" {
+			return fmt.Errorf("%q is a Discord channel, but Discord is not configured: set DISCORD_BOT_TOKEN and DISCORD_CHANNEL_ID", id)
+		}
+	}
+	return nil
+}
+
+// checkModelPricing logs the bot.modelPricing entries for models the
+// backend isn't configured to use, which are most likely misspelt.
+func (c *Config) checkModelPricing() {
+	var models []string
+	switch c.AIBackend {
+	case BackendGemini:
+		models = []string{c.GeminiFlashModel, c.GeminiProModel}
+	case BackendOllama:
+		for _, m := range []string{c.OllamaModel, c.OllamaFlashModel, c.OllamaProModel} {
+			if m != "" {
+				models = append(models, m)
+			}
+		}
+	}
+	if len(models) == 0 {
+		return
+	}
+	for name := range c.Bot.ModelPricing {
+		if slices.Contains(models, name) {
+			continue
+		}
+		if known := closest(name, models); known != "" {
+			slog.Warn("bot.modelPricing has prices for a model that isn't configured", "model", name, "didYouMean", known)
+		} else {
+			slog.Warn("bot.modelPricing has prices for a model that isn't configured", "model", name, "models", strings.Join(models, ", "))
+		}
+	}
+}
+
+// Location returns the configured timezone used to interpret user-facing
+// times such as reminders, falling back to the process local time.
+func (c *Config) Location() *time.Location {
+	if c == nil || c.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		slog.Warn("Invalid timezone, using local time", "timezone", c.Timezone, "error", err)
+		return time.Local
+	}
+	return loc
+}
+
+// JobLocation returns the timezone job's schedule and report dates use:
+// its own timezone if it sets one, otherwise Location.
+func (c *Config) JobLocation(job JobConfig) *time.Location {
+	if job.Timezone != "" {
+		if loc, err := time.LoadLocation(job.Timezone); err == nil {
+			return loc
+		}
+		slog.Warn("Invalid job timezone, using the global one", "name", job.Name, "timezone", job.Timezone)
+	}
+	return c.Location()
+}
+
+// JobCatchUpWindow returns how far back a run of job missed while the bot
+// was down is still made up on startup: its own catchUp if set, otherwise
+// jobCatchUp. 0 means missed runs are skipped.
+func (c *Config) JobCatchUpWindow(job JobConfig) time.Duration {
+	window := c.JobCatchUp
+	if job.CatchUp != "" {
+		window = job.CatchUp
+	}
+	d, _ := parseDuration(window)
+	return d
+}
+
+// ExpandEnv replaces $VAR and ${VAR} in s like os.ExpandEnv, with
+// $DATA_DIR and $REPORTS_DIR taken from the configuration as absolute
+// paths, so MCP server settings can point into the data volume. Secrets
+// take precedence over the environment, and "$$" is a literal "$".
+func (c *Config) ExpandEnv(s string) string {
+	return os.Expand(s, func(key string) string {
+		v, _ := c.lookupVar(key)
+		return v
+	})
+}
+
+func (c *Config) lookupVar(key string) (string, bool) {
+	var dir string
+	switch key {
+	case "$":
+		return "$", true
+	case "DATA_DIR":
+		dir = c.DataDir
+	case "REPORTS_DIR":
+		dir = c.ReportsDir
+	default:
+		if v, ok := c.Secrets[key]; ok {
+			return v, true
+		}
+		v, ok, err := lookupSecretEnv(key)
+		if err != nil {
+			slog.Warn("Failed to read a secret file", "variable", key, "error", err)
+		}
+		return v, ok
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs, true
+	}
+	return dir, true
+}
+
+// undefinedVars returns the variables s references that ExpandEnv would
+// replace with nothing.
+func (c *Config) undefinedVars(s string) []string {
+	var missing []string
+	os.Expand(s, func(key string) string {
+		if _, ok := c.lookupVar(key); !ok {
+			missing = append(missing, key)
+		}
+		return ""
+	})
+	return missing
+}
+
+// DefaultMaxConcurrentJobs is how many jobs run at once unless
+// maxConcurrentJobs says otherwise.
+const DefaultMaxConcurrentJobs = 2
+
+// JobConcurrency returns how many jobs may run at once, 0 for no limit.
+func (c *Config) JobConcurrency() int {
+	switch {
+	case c.MaxConcurrentJobs < 0:
+		return 0
+	case c.MaxConcurrentJobs == 0:
+		return DefaultMaxConcurrentJobs
+	}
+	return c.MaxConcurrentJobs
+}
+
+// SlowQuery returns the slow-query log threshold for db.Instrument: zero
+// for the default, negative to disable the log.
+func (c *Config) SlowQuery() time.Duration {
+	return time.Duration(c.SlowQueryMs) * time.Millisecond
+}
+
+// RestartRequired lists the settings that differ between c and next but are
+// only read at startup (AI backend, models, database, MCP servers, search
+// providers, browser), so a reload cannot apply them.
+func (c *Config) RestartRequired(next *Config) []string {
+	var changed []string
+	if c.AIBackend != next.AIBackend {
+		changed = append(changed, "AI_BACKEND")
+	}
+	if c.GeminiFlashModel != next.GeminiFlashModel || c.GeminiProModel != next.GeminiProModel ||
+		c.OllamaModel != next.OllamaModel || c.OllamaFlashModel != next.OllamaFlashModel ||
+		c.OllamaProModel != next.OllamaProModel || c.OllamaBaseURL != next.OllamaBaseURL {
+		changed = append(changed, "models")
+	}
+	if c.DBPath != next.DBPath {
+		changed = append(changed, "dbPath")
+	}
+	if c.DatabaseURL != next.DatabaseURL {
+		changed = append(changed, "DATABASE_URL")
+	}
+	if c.EncryptionKey != next.EncryptionKey || c.EncryptionKeyFile != next.EncryptionKeyFile {
+		changed = append(changed, "RAVENBOT_ENCRYPTION_KEY")
+	}
+	if c.DataDir != next.DataDir {
+		changed = append(changed, "DATA_DIR")
+	}
+	if c.SlowQueryMs != next.SlowQueryMs {
+		changed = append(changed, "slowQueryMs")
+	}
+	if !reflect.DeepEqual(c.MCPServers, next.MCPServers) {
+		changed = append(changed, "mcpServers")
+	}
+	if !reflect.DeepEqual(c.SearchProviders, next.SearchProviders) {
+		changed = append(changed, "searchProviders")
+	}
+	if c.Browser != next.Browser {
+		changed = append(changed, "browser")
+	}
+	return changed
+}
@@ -1,8 +1,15 @@
 package config
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -83,4 +90,514 @@ func TestLoadConfig(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, BackendOllama, cfg.AIBackend)
 	})
+
+	t.Run("timezone from environment", func(t *testing.T) {
+		_ = os.Setenv("GEMINI_API_KEY", "test-key")
+		_ = os.Setenv("TIMEZONE", "America/Chicago")
+		defer func() {
+			_ = os.Unsetenv("GEMINI_API_KEY")
+			_ = os.Unsetenv("TIMEZONE")
+		}()
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "America/Chicago", cfg.Location().String())
+	})
+
+	t.Run("invalid timezone returns error", func(t *testing.T) {
+		_ = os.Setenv("GEMINI_API_KEY", "test-key")
+		_ = os.Setenv("TIMEZONE", "Mars/Olympus")
+		defer func() {
+			_ = os.Unsetenv("GEMINI_API_KEY")
+			_ = os.Unsetenv("TIMEZONE")
+		}()
+
+		cfg, err := LoadConfig()
+		assert.Error(t, err)
+		assert.Nil(t, cfg)
+		assert.Contains(t, err.Error(), "invalid timezone")
+	})
+
+	t.Run("directories default and come from environment", func(t *testing.T) {
+		_ = os.Setenv("GEMINI_API_KEY", "test-key")
+		defer func() { _ = os.Unsetenv("GEMINI_API_KEY") }()
+
+		cfg, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, DefaultDataDir, cfg.DataDir)
+		assert.Equal(t, DefaultReportsDir, cfg.ReportsDir)
+		assert.Equal(t, filepath.Join("data", "ravenbot.db"), cfg.DBPath)
+
+		_ = os.Setenv("DATA_DIR", "/var/lib/ravenbot")
+		_ = os.Setenv("REPORTS_DIR", "/srv/reports")
+		defer func() {
+			_ = os.Unsetenv("DATA_DIR")
+			_ = os.Unsetenv("REPORTS_DIR")
+		}()
+		cfg, err = LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "/srv/reports", cfg.ReportsDir)
+		assert.Equal(t, "/var/lib/ravenbot/ravenbot.db", cfg.DBPath)
+		assert.Equal(t, "/var/lib/ravenbot/memory.jsonl", cfg.ExpandEnv("$DATA_DIR/memory.jsonl"))
+		assert.Equal(t, "/srv/reports", cfg.ExpandEnv("${REPORTS_DIR}"))
+	})
+}
+
+func TestExpandEnv_Secrets(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "from-env")
+	t.Setenv("SLACK_TEAM", "T123")
+	cfg := &Config{DataDir: "/data", Secrets: map[string]string{"GITHUB_TOKEN": "from-secrets"}}
+
+	assert.Equal(t, "Bearer from-secrets", cfg.ExpandEnv("Bearer ${GITHUB_TOKEN}"), "secrets win over the environment")
+	assert.Equal(t, "--team=T123", cfg.ExpandEnv("--team=$SLACK_TEAM"))
+	assert.Equal(t, "cost: $5", cfg.ExpandEnv("cost: $$5"))
+	assert.Equal(t, []string{"MISSING_TOKEN"}, cfg.undefinedVars("${MISSING_TOKEN}/$DATA_DIR/$GITHUB_TOKEN"))
+}
+
+func TestExpandValues(t *testing.T) {
+	t.Setenv("SEARX_HOST", "searx.lan")
+	cfg := &Config{
+		DataDir:          "/data",
+		Secrets:          map[string]string{"HOOK_SECRET": "pa$$word"},
+		TelegramBotToken: "${SEARX_HOST}",
+		Timezone:         "${MISSING_TZ}",
+		SearchProviders:  []SearchProviderConfig{{Type: SearchSearxNG, URL: "https://${SEARX_HOST}/search", APIKey: "${SEARX_HOST}"}},
+		MCPServers: map[string]MCPServerConfig{"memory": {
+			Command: "https://${SEARX_HOST}/mcp",
+			Env:     map[string]string{"MEMORY_FILE_PATH": "$DATA_DIR/memory.jsonl"},
+		}},
+		Jobs: []JobConfig{{Name: "Publish", Params: map[string]string{
+			"url":    "https://example.com/${SEARX_HOST}",
+			"secret": "${HOOK_SECRET}",
+			"prompt": "Costs $5, not $${PRICE}, in ${DATA_DIR}.",
+		}}},
+	}
+	cfg.expandValues()
+
+	assert.Equal(t, "https://searx.lan/search", cfg.SearchProviders[0].URL)
+	assert.Equal(t, "https://searx.lan/mcp", cfg.MCPServers["memory"].Command)
+	assert.Equal(t, "https://example.com/searx.lan", cfg.Jobs[0].Params["url"])
+	assert.Equal(t, "pa$$word", cfg.Jobs[0].Params["secret"], "values aren't expanded again")
+	assert.Equal(t, "Costs $5, not ${PRICE}, in /data.", cfg.Jobs[0].Params["prompt"])
+	assert.Empty(t, cfg.Timezone, "undefined variables expand to nothing")
+	assert.Equal(t, "${SEARX_HOST}", cfg.TelegramBotToken, "settings from the environment are left alone")
+	assert.Equal(t, "${SEARX_HOST}", cfg.SearchProviders[0].APIKey, "expanded where it's used")
+	assert.Equal(t, "$DATA_DIR/memory.jsonl", cfg.MCPServers["memory"].Env["MEMORY_FILE_PATH"])
+}
+
+func TestLoadConfig_SecretsFile(t *testing.T) {
+	dir := t.TempDir()
+	secrets := filepath.Join(dir, "secrets.json")
+	require.NoError(t, os.WriteFile(secrets, []byte(`{"NOTION_TOKEN": "secret_abc"}`), 0o600))
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	t.Setenv("RAVENBOT_SECRETS_FILE", secrets)
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "secret_abc", cfg.ExpandEnv("$NOTION_TOKEN"))
+
+	require.NoError(t, os.WriteFile(secrets, []byte(`not json`), 0o600))
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "secrets file")
+
+	t.Setenv("RAVENBOT_SECRETS_FILE", filepath.Join(dir, "missing.json"))
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "secrets file")
+}
+
+func TestLoadConfig_ConfigFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ravenbot.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"timezone": "Europe/Berlin"}`), 0o600))
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	t.Setenv("RAVENBOT_CONFIG", file)
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", cfg.Timezone)
+	assert.Equal(t, []string{file}, cfg.Files())
+
+	t.Setenv("RAVENBOT_CONFIG", file+".missing")
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "failed to open config file", "a config file named explicitly must exist")
+}
+
+func TestLoadConfig_Profiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	data := []byte(`{
+  "timezone": "UTC",
+  "env": {"AI_BACKEND": "gemini"},
+  "mcpServers": {"memory": {"command": "npx", "args": ["memory"]}, "github": {"command": "npx"}},
+  "jobs": [{"name": "Nightly Backup", "type": "backup", "schedule": "@daily"}],
+  "profiles": {
+    "dev": {
+      "env": {"AI_BACKEND": "ollama", "TELEGRAM_BOT_TOKEN": ""},
+      "timezone": "Europe/Berlin",
+      "mcpServers": {"github": null},
+      "jobs": []
+    },
+    "prod": {"timezon": "America/Chicago"}
+  }
+}`)
+	require.NoError(t, os.WriteFile(file, data, 0o600))
+	t.Setenv("RAVENBOT_CONFIG", file)
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	t.Setenv("AI_BACKEND", "")
+	require.NoError(t, os.Unsetenv("AI_BACKEND"))
+	t.Setenv("TELEGRAM_BOT_TOKEN", "from-env")
+	t.Setenv("RAVENBOT_PROFILE", "")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, BackendGemini, cfg.AIBackend)
+	assert.Equal(t, "from-env", cfg.TelegramBotToken)
+	assert.Len(t, cfg.MCPServers, 2)
+	assert.Len(t, cfg.Jobs, 1)
+	assert.Empty(t, cfg.Profile)
+
+	t.Setenv("RAVENBOT_PROFILE", "dev")
+	cfg, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "dev", cfg.Profile)
+	assert.Equal(t, BackendOllama, cfg.AIBackend, "the profile's env wins over the file's")
+	assert.Equal(t, "from-env", cfg.TelegramBotToken, "the process environment wins over the file")
+	assert.Equal(t, "Europe/Berlin", cfg.Timezone)
+	assert.Equal(t, []string{"memory"}, cfg.MCPServers["memory"].Args, "objects merge")
+	assert.NotContains(t, cfg.MCPServers, "github", "null removes a server")
+	assert.Empty(t, cfg.Jobs, "lists are replaced")
+
+	// A variable set by an earlier load goes away with the file's setting.
+	require.NoError(t, os.WriteFile(file, []byte(`{"timezone": "UTC", "jobs": [{"name": "Nightly Backup", "type": "backup", "schedule": "@daily"}], "profiles": {"dev": {}}}`), 0o600))
+	cfg, err = LoadConfig()
+	require.NoError(t, err)
+	_, set := os.LookupEnv("AI_BACKEND")
+	assert.False(t, set, "AI_BACKEND is no longer set by the file")
+	assert.Equal(t, BackendGemini, cfg.AIBackend)
+	require.NoError(t, os.WriteFile(file, data, 0o600))
+
+	t.Setenv("RAVENBOT_PROFILE", "deb")
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, `unknown profile "deb" (did you mean "dev"?)`)
+	t.Setenv("RAVENBOT_PROFILE", "prod")
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, `config.json:13:14: unknown key "timezon" (did you mean "timezone"?)`)
+}
+
+func TestSchema(t *testing.T) {
+	schema, err := Schema()
+	require.NoError(t, err)
+	committed, err := os.ReadFile(filepath.Join("..", "..", "config.schema.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(committed), string(schema), "config.schema.json is out of date: run go run ./cmd/bot config schema > config.schema.json")
+
+	var doc struct {
+		Defs map[string]struct {
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(schema, &doc))
+	assert.Contains(t, doc.Defs["JobConfig"].Properties["type"].Enum, "rss_digest")
+	assert.Equal(t, []string{"command"}, doc.Defs["MCPServerConfig"].Required)
+}
+
+func TestSecretEnv(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "telegram_token")
+	require.NoError(t, os.WriteFile(file, []byte("123:abc\n"), 0o600))
+	t.Setenv("RAVENBOT_TEST_TOKEN_FILE", file)
+
+	v, err := SecretEnv("RAVENBOT_TEST_TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "123:abc", v, "trailing newlines are trimmed")
+	assert.Equal(t, "--token=123:abc", (&Config{}).ExpandEnv("--token=${RAVENBOT_TEST_TOKEN}"))
+
+	t.Setenv("RAVENBOT_TEST_TOKEN", "from-env")
+	v, err = SecretEnv("RAVENBOT_TEST_TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", v, "the variable itself wins")
+
+	t.Setenv("GEMINI_API_KEY_FILE", filepath.Join(t.TempDir(), "missing"))
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "GEMINI_API_KEY_FILE")
+}
+
+func TestLoadSecrets_SOPS(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as sops")
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "secrets.sops.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"GITHUB_TOKEN": "ENC[AES256_GCM,data:…]", "sops": {"version": "3.9.0"}}`), 0o644))
+
+	t.Setenv("PATH", dir)
+	_, err := loadSecrets(file)
+	assert.ErrorContains(t, err, "sops command is not installed")
+
+	script := "#!/bin/sh\necho '{\"GITHUB_TOKEN\": \"ghp_decrypted\"}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sops"), []byte(script), 0o755))
+	secrets, err := loadSecrets(file)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"GITHUB_TOKEN": "ghp_decrypted"}, secrets)
+}
+
+func TestVaultConfigLoad(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "")
+	t.Setenv("VAULT_TOKEN", "")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.root" || r.URL.Path != "/v1/secret/data/ravenbot" {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"NOTION_TOKEN": "secret_abc", "RETRIES": 3}, "metadata": {"version": 2}}}`))
+	}))
+	defer srv.Close()
+	vault := VaultConfig{Address: srv.URL, Path: "secret/data/ravenbot"}
+
+	_, err := vault.load()
+	assert.ErrorContains(t, err, "VAULT_TOKEN")
+
+	t.Setenv("VAULT_TOKEN", "s.root")
+	secrets, err := vault.load()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"NOTION_TOKEN": "secret_abc"}, secrets)
+
+	_, err = VaultConfig{Address: srv.URL, Path: "secret/data/other"}.load()
+	assert.ErrorContains(t, err, "403")
+	_, err = VaultConfig{Path: "secret/data/ravenbot"}.load()
+	assert.ErrorContains(t, err, "VAULT_ADDR")
+}
+
+func TestRestartRequired(t *testing.T) {
+	base := &Config{
+		AIBackend:  BackendGemini,
+		DBPath:     "data/ravenbot.db",
+		MCPServers: map[string]MCPServerConfig{"fs": {Command: "npx"}},
+		Bot:        BotConfig{SystemPrompt: "old"},
+	}
+
+	next := *base
+	next.Bot.SystemPrompt = "new"
+	next.Jobs = []JobConfig{{Name: "daily"}}
+	assert.Empty(t, base.RestartRequired(&next), "prompts and jobs are reloadable")
+
+	next.GeminiProModel = "gemini-3-pro"
+	next.MCPServers = map[string]MCPServerConfig{"fs": {Command: "uvx"}}
+	assert.Equal(t, []string{"models", "mcpServers"}, base.RestartRequired(&next))
+}
+
+func TestEstimateCost(t *testing.T) {
+	bot := BotConfig{ModelPricing: map[string]ModelPrice{
+		"gemini-2.5-flash": {InputPerMillion: 0.30, OutputPerMillion: 2.50},
+	}}
+
+	cost, ok := bot.EstimateCost("gemini-2.5-flash", 2_000_000, 1_000_000)
+	assert.True(t, ok)
+	assert.InDelta(t, 3.10, cost, 1e-9)
+
+	_, ok = bot.EstimateCost("llama3.2", 100, 100)
+	assert.False(t, ok)
+}
+
+func TestSessionEvents(t *testing.T) {
+	assert.Equal(t, DefaultSessionEvents, BotConfig{}.SessionEvents())
+	assert.Equal(t, 50, BotConfig{MaxSessionEvents: 50}.SessionEvents())
+	assert.Equal(t, 0, BotConfig{MaxSessionEvents: -1}.SessionEvents(), "negative loads everything")
+}
+
+func TestMCPAuthConfigValidate(t *testing.T) {
+	assert.NoError(t, (&MCPAuthConfig{Type: MCPAuthBearer, Token: "$TOKEN"}).validate())
+	assert.NoError(t, (&MCPAuthConfig{Type: MCPAuthOAuth, TokenURL: "https://auth.example.com/token", ClientID: "raven"}).validate())
+	assert.ErrorContains(t, (&MCPAuthConfig{Type: MCPAuthHeader, Value: "key"}).validate(), "requires header")
+	assert.ErrorContains(t, (&MCPAuthConfig{Type: MCPAuthOAuth, ClientID: "raven"}).validate(), "requires tokenUrl")
+	assert.ErrorContains(t, (&MCPAuthConfig{Type: "digest"}).validate(), "invalid type")
+}
+
+func TestSearchProviderConfigValidate(t *testing.T) {
+	assert.NoError(t, SearchProviderConfig{Type: SearchGemini}.validate())
+	assert.NoError(t, SearchProviderConfig{Type: SearchSearxNG, URL: "http://searxng:8080"}.validate())
+	assert.NoError(t, SearchProviderConfig{Type: SearchGoogle, APIKey: "${GOOGLE_CSE_KEY}", CX: "engine", MaxResults: 5}.validate())
+	assert.ErrorContains(t, SearchProviderConfig{Type: SearchSearxNG}.validate(), "requires url")
+	assert.ErrorContains(t, SearchProviderConfig{Type: SearchBrave}.validate(), "requires apiKey")
+	assert.ErrorContains(t, SearchProviderConfig{Type: SearchGoogle, APIKey: "key"}.validate(), "requires cx")
+	assert.ErrorContains(t, SearchProviderConfig{Type: SearchBing, APIKey: "key", MaxResults: -1}.validate(), "must not be negative")
+	assert.ErrorContains(t, SearchProviderConfig{Type: "duckduckgo"}.validate(), "invalid type")
+}
+
+func TestRedditConfigValidate(t *testing.T) {
+	r := RedditConfig{Subreddits: []string{"golang", "r/gis", "/r/MachineLearning"}, Period: "week"}
+	assert.NoError(t, r.validate())
+	assert.Equal(t, []string{"golang", "gis", "MachineLearning"}, r.Names())
+	assert.ErrorContains(t, RedditConfig{Subreddits: []string{"golang/../x"}}.validate(), "invalid subreddit")
+	assert.ErrorContains(t, RedditConfig{Period: "daily"}.validate(), "invalid period")
+	assert.ErrorContains(t, RedditConfig{Posts: -1}.validate(), "must not be negative")
+	assert.ErrorContains(t, RedditConfig{ClientID: "id"}.validate(), "set together")
+}
+
+func TestBrowserConfigValidate(t *testing.T) {
+	assert.NoError(t, BrowserConfig{}.validate())
+	assert.NoError(t, BrowserConfig{RemoteURL: "ws://chrome:9222"}.validate())
+	assert.ErrorContains(t, BrowserConfig{RemoteURL: "chrome:9222"}.validate(), "invalid remoteURL")
+	assert.ErrorContains(t, BrowserConfig{RemoteURL: "ftp://chrome:9222"}.validate(), "must be a ws(s) or http(s) URL")
+}
+
+func TestSandboxConfigValidate(t *testing.T) {
+	assert.NoError(t, SandboxConfig{}.validate())
+	assert.NoError(t, SandboxConfig{MemoryMB: 512, CPUs: 0.5, TimeoutSeconds: 60}.validate())
+	assert.ErrorContains(t, SandboxConfig{TimeoutSeconds: -1}.validate(), "must not be negative")
+}
+
+func TestPolitenessConfigValidate(t *testing.T) {
+	assert.NoError(t, PolitenessConfig{}.validate())
+	assert.NoError(t, PolitenessConfig{DelayMs: -1, Hosts: map[string]HostPolitenessConfig{"example.com": {DelayMs: 5000}}}.validate())
+	assert.ErrorContains(t, PolitenessConfig{DelayMs: -2}.validate(), "delayMs")
+	assert.ErrorContains(t, PolitenessConfig{Hosts: map[string]HostPolitenessConfig{"https://example.com": {}}}.validate(), "not a host name")
+}
+
+func TestHTTPCacheConfigValidate(t *testing.T) {
+	assert.NoError(t, HTTPCacheConfig{}.validate())
+	assert.NoError(t, HTTPCacheConfig{MaxMB: -1}.validate())
+	assert.ErrorContains(t, HTTPCacheConfig{MaxMB: -5}.validate(), "maxMB")
+}
+
+func TestHostAccessConfigValidate(t *testing.T) {
+	assert.NoError(t, HostAccessConfig{}.validate())
+	assert.NoError(t, HostAccessConfig{Allow: []string{"grafana.lan", "*.home.example.com", "192.168.1.20", "fd00::20", "[fd00::21]"}, Deny: []string{"*.doubleclick.net"}}.validate())
+	assert.ErrorContains(t, HostAccessConfig{Allow: []string{"http://grafana.lan/"}}.validate(), "not a URL")
+	assert.ErrorContains(t, HostAccessConfig{Allow: []string{"grafana.lan:3000"}}.validate(), "port")
+	assert.ErrorContains(t, HostAccessConfig{Deny: []string{"ads.*.com"}}.validate(), "deny")
+	assert.ErrorContains(t, HostAccessConfig{Deny: []string{" "}}.validate(), "empty host")
+}
+
+func TestJobConfigValidate(t *testing.T) {
+	assert.NoError(t, JobConfig{Name: "Plain", Type: "backup", Schedule: "@daily"}.validate())
+	tmpl, err := JobConfig{Name: "Plain"}.ParseTemplate()
+	assert.NoError(t, err)
+	assert.Nil(t, tmpl)
+
+	assert.NoError(t, JobConfig{Name: "Digest", Type: "backup", Schedule: "@daily", Template: "# {{.Title}}\n\n{{.Report}}\n\n{{hashtags .Tags}}"}.validate())
+	assert.ErrorContains(t, JobConfig{Name: "Digest", Type: "backup", Schedule: "@daily", Template: "# {{.Title"}.validate(), "invalid template")
+	assert.ErrorContains(t, JobConfig{Name: "Digest", Type: "backup", Schedule: "@daily", Template: "{{nope .Tags}}"}.validate(), "not defined")
+
+	assert.NoError(t, JobConfig{Name: "Morning", Type: "backup", Schedule: "@daily", Timezone: "America/New_York"}.validate())
+	assert.ErrorContains(t, JobConfig{Name: "Morning", Type: "backup", Schedule: "@daily", Timezone: "Mars/Olympus_Mons"}.validate(), "invalid timezone")
+	assert.ErrorContains(t, JobConfig{Name: "Morning", Type: "backup", Schedule: "0 7 * *"}.validate(), "invalid schedule")
+	assert.ErrorContains(t, JobConfig{Type: "backup", Name: "Morning"}.validate(), "needs a schedule or runAfter")
+
+	assert.ErrorContains(t, JobConfig{Name: "Digest", Type: "rss-digest", Schedule: "@daily"}.validate(), `unknown type "rss-digest" (did you mean "rss_digest"?)`)
+	assert.ErrorContains(t, JobConfig{Name: "Digest", Schedule: "@daily"}.validate(), "must be one of backup, chat")
+	assert.ErrorContains(t, JobConfig{Name: "Check-in", Type: "chat", Schedule: "@daily"}.validate(), "chat jobs need params.prompt")
+	assert.ErrorContains(t, JobConfig{Name: "Publish", Type: "webhook", Schedule: "@daily", Params: map[string]string{"prompt": "Roundup"}}.validate(), "params.url")
+}
+
+func TestDecodeConfig(t *testing.T) {
+	var cfg Config
+	require.NoError(t, decodeConfig("config.json", []byte(`{"timezone": "UTC", "jobs": [{"name": "A", "schedule": "@daily"}]}`), &cfg))
+	assert.Equal(t, "@daily", cfg.Jobs[0].Schedule)
+
+	err := decodeConfig("config.json", []byte("{\n  \"jobs\": [\n    {\"name\": \"A\", \"shedule\": \"@daily\"}\n  ]\n}"), &Config{})
+	assert.EqualError(t, err, `config.json:3:19: unknown key "shedule" (did you mean "schedule"?)`)
+	err = decodeConfig("config.json", []byte(`{"maxConcurrentJobs": "two"}`), &Config{})
+	assert.EqualError(t, err, "config.json:1:28: maxConcurrentJobs must be a number, not a string")
+	err = decodeConfig("config.json", []byte("{\n  \"timezone\": \"UTC\",\n}"), &Config{})
+	assert.ErrorContains(t, err, "config.json:3:")
+	assert.ErrorContains(t, decodeConfig("config.json", []byte(`{"frobnicate": true}`), &Config{}), `unknown key "frobnicate"`)
+}
+
+func TestCheckChannel(t *testing.T) {
+	cfg := &Config{TelegramBotToken: "token", TelegramChatID: 123456}
+	assert.NoError(t, cfg.checkChannel(""))
+	assert.NoError(t, cfg.checkChannel("telegram-123456"))
+	assert.ErrorContains(t, cfg.checkChannel("discord-987654"), "set DISCORD_BOT_TOKEN and DISCORD_CHANNEL_ID")
+	assert.ErrorContains(t, (&Config{}).checkChannel("telegram-123456"), "Telegram is not configured")
+}
+
+func TestJobLocation(t *testing.T) {
+	cfg := &Config{Timezone: "Europe/Berlin"}
+	assert.Equal(t, "Europe/Berlin", cfg.JobLocation(JobConfig{}).String())
+	assert.Equal(t, "America/New_York", cfg.JobLocation(JobConfig{Timezone: "America/New_York"}).String())
+	assert.Equal(t, time.Local, (&Config{}).JobLocation(JobConfig{}))
+}
+
+func TestValidateRunAfter(t *testing.T) {
+	chain := []JobConfig{{Name: "Fetch"}, {Name: "Summarize", RunAfter: []string{"fetch"}}, {Name: "Publish", RunAfter: []string{"Summarize", "Fetch"}}}
+	assert.NoError(t, validateRunAfter(chain))
+	assert.ErrorContains(t, validateRunAfter([]JobConfig{{Name: "Publish", RunAfter: []string{"Sumarize"}}}), `no job named "Sumarize"`)
+	cycle := []JobConfig{{Name: "A", RunAfter: []string{"C"}}, {Name: "B", RunAfter: []string{"A"}}, {Name: "C", RunAfter: []string{"B"}}}
+	assert.ErrorContains(t, validateRunAfter(cycle), "A → C → B → A")
+	assert.ErrorContains(t, validateRunAfter([]JobConfig{{Name: "Loop", RunAfter: []string{"loop"}}}), "cycle")
+
+	assert.NoError(t, JobConfig{Name: "Summarize", Type: "backup", RunAfter: []string{"Fetch"}, Params: map[string]string{"prompt": "Summarize {{.Output}}"}}.validate())
+	assert.ErrorContains(t, JobConfig{Name: "Summarize", Type: "backup", RunAfter: []string{"Fetch"}, Params: map[string]string{"prompt": "Summarize {{.Output"}}.validate(), "invalid prompt template")
+	assert.NoError(t, JobConfig{Name: "Plain", Type: "backup", Schedule: "@daily", Params: map[string]string{"prompt": "Literal {{braces"}}.validate(), "only runAfter prompts are templates")
+}
+
+func TestJobCatchUpWindow(t *testing.T) {
+	assert.NoError(t, JobConfig{Type: "backup", Schedule: "@daily", CatchUp: "90m"}.validate())
+	assert.ErrorContains(t, JobConfig{Type: "backup", Schedule: "@daily", CatchUp: "soon"}.validate(), "catchUp")
+	assert.ErrorContains(t, JobConfig{Type: "backup", Schedule: "@daily", CatchUp: "-1h"}.validate(), "catchUp")
+
+	assert.Zero(t, (&Config{}).JobCatchUpWindow(JobConfig{}), "off by default")
+	cfg := &Config{JobCatchUp: "2h"}
+	assert.Equal(t, 2*time.Hour, cfg.JobCatchUpWindow(JobConfig{}))
+	assert.Equal(t, 30*time.Minute, cfg.JobCatchUpWindow(JobConfig{CatchUp: "30m"}))
+	assert.Zero(t, cfg.JobCatchUpWindow(JobConfig{CatchUp: "off"}))
+}
+
+func TestJobJitterAndConcurrency(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, JobConfig{Jitter: "5m"}.JitterDuration())
+	assert.Zero(t, JobConfig{}.JitterDuration())
+	assert.ErrorContains(t, JobConfig{Type: "backup", Schedule: "@daily", Jitter: "a bit"}.validate(), "jitter")
+
+	assert.Equal(t, DefaultMaxConcurrentJobs, (&Config{}).JobConcurrency())
+	assert.Equal(t, 4, (&Config{MaxConcurrentJobs: 4}).JobConcurrency())
+	assert.Zero(t, (&Config{MaxConcurrentJobs: -1}).JobConcurrency(), "no limit")
+}
+
+func TestWeatherConfigValidate(t *testing.T) {
+	assert.NoError(t, WeatherConfig{}.validate())
+	assert.True(t, WeatherConfig{Units: UnitsImperial}.Imperial())
+	assert.ErrorContains(t, WeatherConfig{Units: "fahrenheit"}.validate(), "invalid units")
+}
+
+func TestExposesTool(t *testing.T) {
+	all := MCPServerConfig{}
+	assert.True(t, all.ExposesTool("create_issue"))
+
+	readOnly := MCPServerConfig{ToolAllowlist: []string{"get_*", "list_*", "search_code"}, ToolDenylist: []string{"get_secret*"}}
+	assert.True(t, readOnly.ExposesTool("get_issue"))
+	assert.True(t, readOnly.ExposesTool("search_code"))
+	assert.False(t, readOnly.ExposesTool("create_issue"), "not on the allowlist")
+	assert.False(t, readOnly.ExposesTool("get_secret_scanning_alert"), "the denylist wins")
+
+	noDeletes := MCPServerConfig{ToolDenylist: []string{"delete_*"}}
+	assert.True(t, noDeletes.ExposesTool("create_issue"))
+	assert.False(t, noDeletes.ExposesTool("delete_file"))
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"timezone": "UTC"}`), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan struct{}, 10)
+	go Watch(ctx, func() []string { return []string{path} }, 10*time.Millisecond, func() { changes <- struct{}{} })
+	time.Sleep(30 * time.Millisecond) // let it take the first fingerprint
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"timezone": "Europe/Berlin"}`), 0o600))
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("change not noticed")
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, changes, "one change is reported once")
+
+	require.NoError(t, os.Remove(path))
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("removal not noticed")
+	}
 }
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// decodeConfig decodes the contents of the config file name into cfg.
+// Keys that match no setting are rejected, so a typo such as "shedule"
+// isn't silently ignored. Errors point at the line and column of the
+// problem and suggest the nearest known key.
+func decodeConfig(name string, data []byte, cfg *Config) error {
+	return decodeConfigPart(name, data, 0, len(data), cfg)
+}
+
+// decodeConfigPart decodes data[start:end], such as a profile, into cfg,
+// with errors positioned in data.
+func decodeConfigPart(name string, data []byte, start, end int, cfg *Config) error {
+	dec := json.NewDecoder(bytes.NewReader(data[start:end]))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(cfg)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("%s:%s: %s", name, position(data, int64(start)+syntaxErr.Offset), syntaxErr.Error())
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("%s:%s: %s must be a %s, not a %s", name, position(data, int64(start)+typeErr.Offset), typeErr.Field, jsonKind(typeErr.Type), typeErr.Value)
+	}
+	if quoted, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		key, _ := strconv.Unquote(quoted)
+		msg := fmt.Sprintf("unknown key %q", key)
+		if known := closest(key, configKeys()); known != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", known)
+		}
+		if i := bytes.Index(data[start:end], []byte(strconv.Quote(key))); i >= 0 {
+			return fmt.Errorf("%s:%s: %s", name, position(data, int64(start+i)), msg)
+		}
+		return fmt.Errorf("%s: %s", name, msg)
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
+
+// position returns the "line:column" of offset in data.
+func position(data []byte, offset int64) string {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return fmt.Sprintf("%d:%d", line, col)
+}
+
+// jsonKind names the JSON value t is decoded from.
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Pointer:
+		return jsonKind(t.Elem())
+	}
+	return "number"
+}
+
+// configKeys returns the keys config.json may use at any level.
+func configKeys() []string {
+	var keys []string
+	seen := map[reflect.Type]bool{}
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			walk(t.Elem())
+			return
+		case reflect.Struct:
+		default:
+			return
+		}
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "" || name == "-" {
+				continue
+			}
+			keys = append(keys, name)
+			walk(f.Type)
+		}
+	}
+	walk(reflect.TypeFor[Config]())
+	return keys
+}
+
+// closest returns the candidate nearest to s, ignoring case, if it is
+// close enough to be a likely typo; otherwise "".
+func closest(s string, candidates []string) string {
+	best, bestDist := "", len(s)/3+1
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(s), strings.ToLower(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
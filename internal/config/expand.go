@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// expandValues replaces ${VAR} in every string read from config.json (the
+// fields with a json tag) with the variable's value, looked up like
+// ExpandEnv does, so secrets and endpoints can stay in the environment
+// while the structure stays in the file. "$${" is a literal "${"; $VAR
+// without braces is left alone, since prompts may well mention prices.
+// Fields tagged expand:"-" are expanded where they are used, with
+// ExpandEnv. References to undefined variables are logged and expand to
+// nothing.
+func (c *Config) expandValues() {
+	c.expandValue(reflect.ValueOf(c).Elem(), "")
+}
+
+func (c *Config) expandValue(v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.String:
+		s, missing := c.expandBraced(v.String())
+		for _, key := range missing {
+			slog.Warn("Config value references an undefined variable", "setting", path, "variable", key)
+		}
+		v.SetString(s)
+	case reflect.Pointer:
+		if !v.IsNil() {
+			c.expandValue(v.Elem(), path)
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			c.expandValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		// Map elements aren't addressable: expand a copy and store it back.
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			c.expandValue(elem, fmt.Sprintf("%s.%v", path, iter.Key()))
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "" || name == "-" || f.Tag.Get("expand") == "-" {
+				continue
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			c.expandValue(v.Field(i), name)
+		}
+	}
+}
+
+// expandBraced replaces ${VAR} in s, returning the names it found no
+// value for.
+func (c *Config) expandBraced(s string) (string, []string) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var sb strings.Builder
+	var missing []string
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			break
+		}
+		if i > 0 && s[i-1] == '$' {
+			sb.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 || !isVarName(s[i+2:i+end]) {
+			sb.WriteString(s[:i+2])
+			s = s[i+2:]
+			continue
+		}
+		key := s[i+2 : i+end]
+		value, ok := c.lookupVar(key)
+		if !ok {
+			missing = append(missing, key)
+		}
+		sb.WriteString(s[:i] + value)
+		s = s[i+end+1:]
+	}
+	sb.WriteString(s)
+	return sb.String(), missing
+}
+
+func isVarName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', 'A' <= r && r <= 'Z', 'a' <= r && r <= 'z':
+		case '0' <= r && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HostAccessConfig lists hosts the web tools and HTTP MCP servers may
+// reach although their address or port is one the SSRF protection blocks,
+// and hosts they must never reach.
+type HostAccessConfig struct {
+	// Allow and Deny hold host names or IP addresses, without scheme or
+	// port; "*.example.com" matches the subdomains of example.com. A host
+	// matching both is denied.
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// validate reports an invalid setting.
+func (h HostAccessConfig) validate() error {
+	for _, list := range []struct {
+		name  string
+		hosts []string
+	}{{"allow", h.Allow}, {"deny", h.Deny}} {
+		for _, host := range list.hosts {
+			if err := validHostPattern(host); err != nil {
+				return fmt.Errorf("%s: %q: %w", list.name, host, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validHostPattern(host string) error {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return fmt.Errorf("empty host")
+	}
+	if strings.Contains(host, "/") {
+		return fmt.Errorf("must be a host name, not a URL")
+	}
+	if rest, ok := strings.CutPrefix(host, "*."); ok {
+		host = rest
+	}
+	if strings.Contains(host, "*") {
+		return fmt.Errorf(`"*" may only start a pattern, as in "*.example.com"`)
+	}
+	if !strings.HasPrefix(host, "[") && strings.Count(host, ":") == 1 {
+		return fmt.Errorf("must not include a port")
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package config
+
+import "fmt"
+
+// HTTPCacheConfig sets the disk cache of the web tools' responses, kept in
+// DataDir/httpcache.
+type HTTPCacheConfig struct {
+	// MaxMB bounds the cache's disk use (default 64); -1 turns it off.
+	MaxMB int `json:"maxMB,omitempty"`
+}
+
+// validate reports an invalid setting.
+func (h HTTPCacheConfig) validate() error {
+	if h.MaxMB < -1 {
+		return fmt.Errorf("maxMB must be -1 (off) or more")
+	}
+	return nil
+}
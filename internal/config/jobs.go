@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/schedule"
+)
+
+// JobConfig is a job run on a cron schedule; Type selects what it does
+// and Params configure it.
+type JobConfig struct {
+	Name     string            `json:"name"`
+	Schedule string            `json:"schedule"`
+	Type     string            `json:"type"`
+	Params   map[string]string `json:"params"`
+	// Template is an optional Go text/template that wraps the job's report
+	// before it is saved and sent, so each digest has its own recognizable
+	// layout. It sees .Title (the job name), .Report, .Date, .Tags and the
+	// hashtags function, e.g. "# {{.Title}}\n{{.Report}}\n{{hashtags .Tags}}".
+	Template string `json:"template,omitempty"`
+	// Timezone is the IANA zone the schedule is read in, such as
+	// "America/New_York"; by default the global timezone.
+	Timezone string `json:"timezone,omitempty"`
+	// CatchUp overrides the global jobCatchUp window; "off" disables it.
+	CatchUp string `json:"catchUp,omitempty"`
+	// RunAfter names jobs this one runs after: once each of them has
+	// succeeded since its own last run. Its params.prompt is then a
+	// template that sees their latest reports as .Output (the first
+	// job's) and .Outputs (by name). The schedule may be left empty.
+	RunAfter []string `json:"runAfter,omitempty"`
+	// Jitter delays each scheduled start by a random time up to this
+	// long, e.g. "5m", so jobs sharing a schedule don't start at once.
+	Jitter string `json:"jitter,omitempty"`
+}
+
+// jobTypes maps each job type to the params it can't run without.
+var jobTypes = map[string][]string{
+	"research":         {"prompt"},
+	"rss_digest":       nil,
+	"webhook":          {"prompt", "url"},
+	"chat":             {"prompt"},
+	"feedback_summary": nil,
+	"backup":           nil,
+	"system_report":    nil,
+	"usage_rollup":     nil,
+	"usage_report":     nil,
+}
+
+// jobTemplateFuncs are the functions available to job templates.
+var jobTemplateFuncs = template.FuncMap{
+	// hashtags renders tags as "#go #releases".
+	"hashtags": func(tags []string) string {
+		out := make([]string, 0, len(tags))
+		for _, t := range tags {
+			if t = strings.ReplaceAll(strings.TrimSpace(t), " ", "-"); t != "" {
+				out = append(out, "#"+t)
+			}
+		}
+		return strings.Join(out, " ")
+	},
+}
+
+// JitterDuration returns the job's start jitter, 0 if it has none.
+func (j JobConfig) JitterDuration() time.Duration {
+	d, _ := parseDuration(j.Jitter)
+	return d
+}
+
+// ParsePrompt parses params.prompt as a pipeline template, or returns nil
+// if the job doesn't run after other jobs.
+func (j JobConfig) ParsePrompt() (*template.Template, error) {
+	if len(j.RunAfter) == 0 {
+		return nil, nil
+	}
+	return template.New(j.Name).Parse(j.Params["prompt"])
+}
+
+// ParseTemplate parses the job's report template, or returns nil if it
+// has none.
+func (j JobConfig) ParseTemplate() (*template.Template, error) {
+	if strings.TrimSpace(j.Template) == "" {
+		return nil, nil
+	}
+	return template.New(j.Name).Funcs(jobTemplateFuncs).Option("missingkey=error").Parse(j.Template)
+}
+
+func (j JobConfig) validate() error {
+	required, ok := jobTypes[j.Type]
+	if !ok {
+		types := slices.Sorted(maps.Keys(jobTypes))
+		if known := closest(j.Type, types); known != "" {
+			return fmt.Errorf("unknown type %q (did you mean %q?)", j.Type, known)
+		}
+		return fmt.Errorf("unknown type %q: must be one of %s", j.Type, strings.Join(types, ", "))
+	}
+	for _, param := range required {
+		if strings.TrimSpace(j.Params[param]) == "" {
+			return fmt.Errorf("%s jobs need params.%s", j.Type, param)
+		}
+	}
+	switch {
+	case j.Schedule != "":
+		if _, err := schedule.Parse(j.Schedule, nil); err != nil {
+			return err
+		}
+	case len(j.RunAfter) == 0:
+		return fmt.Errorf("needs a schedule or runAfter")
+	}
+	if j.Timezone != "" {
+		if _, err := time.LoadLocation(j.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", j.Timezone, err)
+		}
+	}
+	if _, err := parseDuration(j.CatchUp); err != nil {
+		return fmt.Errorf("catchUp: %w", err)
+	}
+	if _, err := parseDuration(j.Jitter); err != nil {
+		return fmt.Errorf("jitter: %w", err)
+	}
+	if _, err := j.ParseTemplate(); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	if _, err := j.ParsePrompt(); err != nil {
+		return fmt.Errorf("invalid prompt template: %w", err)
+	}
+	return nil
+}
+
+// validateRunAfter checks that every job named in runAfter exists and that
+// the jobs don't run after each other in a cycle.
+func validateRunAfter(jobs []JobConfig) error {
+	index := make(map[string]int, len(jobs))
+	for i, job := range jobs {
+		index[strings.ToLower(job.Name)] = i
+	}
+	for i, job := range jobs {
+		for _, name := range job.RunAfter {
+			if _, ok := index[strings.ToLower(name)]; !ok {
+				return fmt.Errorf("jobs[%d] (%s): runAfter: no job named %q", i, job.Name, name)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(jobs))
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		path = append(path, jobs[i].Name)
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("jobs run after each other in a cycle: %s", strings.Join(path, " → "))
+		case done:
+			return nil
+		}
+		state[i] = visiting
+		for _, name := range jobs[i].RunAfter {
+			if err := visit(index[strings.ToLower(name)], path); err != nil {
+				return err
+			}
+		}
+		state[i] = done
+		return nil
+	}
+	for i := range jobs {
+		if err := visit(i, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDuration parses an optional duration such as "90m" or "6h". Empty,
+// "off" and "0" mean 0.
+func parseDuration(s string) (time.Duration, error) {
+	switch s = strings.TrimSpace(s); s {
+	case "", "off", "0":
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q: use a value such as \"2h\" or \"off\"", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PolitenessConfig sets how the web tools (fetch_page, crawl_site and feed
+// fetches) pace their requests to each site and whether they obey its
+// robots.txt. Zero values use the defaults in internal/tools.
+type PolitenessConfig struct {
+	// DelayMs is the least time between two requests to one host (default
+	// 1000); -1 turns pacing off. A longer Crawl-delay in the host's
+	// robots.txt is used instead.
+	DelayMs int `json:"delayMs,omitempty"`
+	// IgnoreRobots stops checking robots.txt before fetching.
+	IgnoreRobots bool `json:"ignoreRobots,omitempty"`
+	// Hosts overrides the settings for a host and its subdomains, e.g.
+	// {"example.com": {"delayMs": 5000}}.
+	Hosts map[string]HostPolitenessConfig `json:"hosts,omitempty"`
+}
+
+// HostPolitenessConfig overrides the politeness settings for one host.
+type HostPolitenessConfig struct {
+	DelayMs      int  `json:"delayMs,omitempty"`
+	IgnoreRobots bool `json:"ignoreRobots,omitempty"`
+}
+
+// validate reports an invalid setting.
+func (p PolitenessConfig) validate() error {
+	if p.DelayMs < -1 {
+		return fmt.Errorf("delayMs must be -1 (off) or more")
+	}
+	for host, h := range p.Hosts {
+		if host == "" || strings.ContainsAny(host, "/:") {
+			return fmt.Errorf("hosts: %q is not a host name", host)
+		}
+		if h.DelayMs < -1 {
+			return fmt.Errorf("hosts.%s.delayMs must be -1 (off) or more", host)
+		}
+	}
+	return nil
+}
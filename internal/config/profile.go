@@ -0,0 +1,115 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Profile returns the configuration profile to apply: RAVENBOT_PROFILE,
+// which the --profile flag sets, or "" for the base configuration alone.
+func Profile() string {
+	return os.Getenv("RAVENBOT_PROFILE")
+}
+
+// configEnv is the part of the config file read before the rest, since the
+// variables it sets decide how the rest is read.
+type configEnv struct {
+	Env      map[string]string          `json:"env"`
+	Profiles map[string]json.RawMessage `json:"profiles"`
+}
+
+// fileEnv remembers the variables applyConfigEnv set and their values, so
+// a reload can unset the ones the config file no longer sets.
+var (
+	fileEnvMu sync.Mutex
+	fileEnv   = map[string]string{}
+)
+
+// applyConfigEnv sets the environment variables of the config file's env
+// and then of the profile's, which win, unless the process environment
+// already sets them: the real environment wins over the file. Values
+// expand ${VAR} from the environment. Variables an earlier load set are
+// unset first, so removing one from the file takes effect on reload. A
+// file that doesn't parse is left to decodeConfig to report.
+func applyConfigEnv(data []byte, profile string) {
+	fileEnvMu.Lock()
+	defer fileEnvMu.Unlock()
+	for name, value := range fileEnv {
+		if v, ok := os.LookupEnv(name); ok && v == value {
+			_ = os.Unsetenv(name)
+		}
+	}
+	clear(fileEnv)
+
+	var file configEnv
+	if json.Unmarshal(data, &file) != nil {
+		return
+	}
+	env := file.Env
+	if raw, ok := file.Profiles[profile]; ok && profile != "" {
+		var p configEnv
+		if json.Unmarshal(raw, &p) == nil {
+			if env == nil {
+				env = map[string]string{}
+			}
+			maps.Copy(env, p.Env)
+		}
+	}
+	var none Config
+	for name, value := range env {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		value, _ = none.expandBraced(value)
+		if os.Setenv(name, value) == nil {
+			fileEnv[name] = value
+		}
+	}
+}
+
+// applyProfile decodes profile from cfg.Profiles on top of the base
+// configuration already in cfg: objects merge key by key, while lists and
+// single mcpServers entries are replaced, and a null mcpServers entry
+// removes the server. data is the config file, for error positions.
+func (c *Config) applyProfile(name string, data []byte, profile string) error {
+	raw, ok := c.Profiles[profile]
+	if !ok {
+		names := slices.Sorted(maps.Keys(c.Profiles))
+		if known := closest(profile, names); known != "" {
+			return fmt.Errorf("unknown profile %q (did you mean %q?)", profile, known)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile %q: %s defines no profiles", profile, name)
+		}
+		return fmt.Errorf("unknown profile %q: must be one of %s", profile, strings.Join(names, ", "))
+	}
+	var overlay struct {
+		Profiles   json.RawMessage            `json:"profiles"`
+		MCPServers map[string]json.RawMessage `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(raw, &overlay); err == nil && overlay.Profiles != nil {
+		return fmt.Errorf("profiles.%s: profiles can't define profiles", profile)
+	}
+
+	profiles := c.Profiles
+	if start := bytes.Index(data, raw); start >= 0 {
+		if err := decodeConfigPart(name, data, start, start+len(raw), c); err != nil {
+			return err
+		}
+	} else if err := decodeConfig(name+" profiles."+profile, raw, c); err != nil {
+		return err
+	}
+	c.Profiles = profiles
+	for server, v := range overlay.MCPServers {
+		if string(v) == "null" {
+			delete(c.MCPServers, server)
+		}
+	}
+	return nil
+}
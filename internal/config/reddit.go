@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RedditConfig lists the subreddits whose top posts feed /digest and the
+// research agent's reddit_top tool. With ClientID and ClientSecret (of a
+// Reddit "script" app; both expand ${VAR}) requests go through Reddit's
+// OAuth API, which allows far more requests than the public JSON pages.
+type RedditConfig struct {
+	Subreddits []string `json:"subreddits"`
+	// Period is the window of top posts: hour, day (default), week, month,
+	// year or all.
+	Period string `json:"period,omitempty"`
+	// Posts is how many top posts to read per subreddit (default 10), and
+	// Comments how many top comments of each (default 3, -1 for none).
+	Posts        int    `json:"posts,omitempty"`
+	Comments     int    `json:"comments,omitempty"`
+	ClientID     string `json:"clientId,omitempty" expand:"-"`
+	ClientSecret string `json:"clientSecret,omitempty" expand:"-"`
+}
+
+// RedditPeriods are the windows Reddit ranks top posts over.
+var RedditPeriods = []string{"hour", "day", "week", "month", "year", "all"}
+
+var subredditName = regexp.MustCompile(`^[A-Za-z0-9_]{2,21}$`)
+
+// SubredditName returns the name of a subreddit written as "golang",
+// "r/golang" or "/r/golang", or "" if it is not a valid name.
+func SubredditName(s string) string {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "/"), "r/")
+	if !subredditName.MatchString(s) {
+		return ""
+	}
+	return s
+}
+
+// Names returns the configured subreddits' names.
+func (r RedditConfig) Names() []string {
+	names := make([]string, 0, len(r.Subreddits))
+	for _, s := range r.Subreddits {
+		if name := SubredditName(s); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// validate reports an invalid setting.
+func (r RedditConfig) validate() error {
+	for _, s := range r.Subreddits {
+		if SubredditName(s) == "" {
+			return fmt.Errorf("invalid subreddit %q", s)
+		}
+	}
+	if r.Period != "" && !slices.Contains(RedditPeriods, r.Period) {
+		return fmt.Errorf("invalid period %q: must be one of %s", r.Period, strings.Join(RedditPeriods, ", "))
+	}
+	if r.Posts < 0 || r.Comments < -1 {
+		return fmt.Errorf("posts and comments must not be negative")
+	}
+	if (r.ClientID == "") != (r.ClientSecret == "") {
+		return fmt.Errorf("clientId and clientSecret must be set together")
+	}
+	return nil
+}
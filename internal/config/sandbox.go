@@ -0,0 +1,28 @@
+package config
+
+import "fmt"
+
+// SandboxConfig sets the containers the run_code tool runs snippets in.
+// Zero values use the defaults in internal/tools.
+type SandboxConfig struct {
+	// Docker is the Docker CLI (default "docker").
+	Docker string `json:"docker,omitempty"`
+	// PythonImage and GoImage are the images snippets run in (default
+	// python:3.13-alpine and golang:1.25-alpine). Pull them beforehand, or
+	// the first run spends its time limit downloading.
+	PythonImage string `json:"pythonImage,omitempty"`
+	GoImage     string `json:"goImage,omitempty"`
+	// MemoryMB, CPUs and TimeoutSeconds limit each run (default 256 MB,
+	// one CPU and 30 seconds).
+	MemoryMB       int     `json:"memoryMB,omitempty"`
+	CPUs           float64 `json:"cpus,omitempty"`
+	TimeoutSeconds int     `json:"timeoutSeconds,omitempty"`
+}
+
+// validate reports an invalid setting.
+func (s SandboxConfig) validate() error {
+	if s.MemoryMB < 0 || s.CPUs < 0 || s.TimeoutSeconds < 0 {
+		return fmt.Errorf("memoryMB, cpus and timeoutSeconds must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// schemaEnums lists the values settings accept, keyed by type and key.
+var schemaEnums = map[string][]string{
+	"JobConfig.type":            slices.Sorted(maps.Keys(jobTypes)),
+	"MCPServerConfig.transport": {MCPTransportStreamable, MCPTransportSSE},
+	"MCPServerConfig.logLevel":  MCPLogLevels,
+	"MCPServerConfig.agent":     MCPAgents,
+	"MCPAuthConfig.type":        {MCPAuthBearer, MCPAuthHeader, MCPAuthBasic, MCPAuthOAuth},
+	"MCPSamplingConfig.model":   {ModelAuto, ModelFlash, ModelPro},
+	"BotConfig.defaultModel":    {ModelAuto, ModelFlash, ModelPro},
+	"SearchProviderConfig.type": SearchProviders,
+	"RedditConfig.period":       RedditPeriods,
+}
+
+// schemaRequired lists the keys an object of each type must have.
+var schemaRequired = map[string][]string{
+	"JobConfig":            {"name", "type"},
+	"MCPServerConfig":      {"command"},
+	"MCPAuthConfig":        {"type"},
+	"SearchProviderConfig": {"type"},
+}
+
+// Schema returns a JSON Schema (draft 2020-12) of config.json, derived from
+// Config: the keys LoadConfig accepts, their types, and the values of the
+// settings that only take a few. Like LoadConfig it rejects unknown keys.
+// The repository keeps a copy in config.schema.json, which config.json's
+// "$schema" points editors to.
+func Schema() ([]byte, error) {
+	g := schemaGen{defs: map[string]any{}}
+	root := g.object(reflect.TypeFor[Config]())
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "ravenbot configuration"
+	root["$defs"] = g.defs
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+type schemaGen struct {
+	defs map[string]any
+}
+
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // a placeholder while it is built
+			g.defs[t.Name()] = g.object(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	}
+	return map[string]any{}
+}
+
+// object returns the schema of the JSON object struct type t decodes from.
+func (g *schemaGen) object(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		var s map[string]any
+		switch {
+		case f.Type == reflect.TypeFor[map[string]json.RawMessage]():
+			// Profiles hold partial configurations.
+			s = map[string]any{"type": "object", "additionalProperties": map[string]any{"$ref": "#"}}
+		case t == reflect.TypeFor[Config]() && name == "mcpServers":
+			// A profile removes a server with null.
+			s = g.schema(f.Type)
+			s["additionalProperties"] = map[string]any{"anyOf": []any{s["additionalProperties"], map[string]any{"type": "null"}}}
+		default:
+			s = g.schema(f.Type)
+		}
+		if enum, ok := schemaEnums[t.Name()+"."+name]; ok {
+			s["enum"] = enum
+		}
+		props[name] = s
+	}
+	obj := map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	if required, ok := schemaRequired[t.Name()]; ok {
+		obj["required"] = required
+	}
+	return obj
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SearchProviderConfig is one web search provider of the research agent's
+// web_search tool. APIKey expands ${VAR} (see Config.ExpandEnv), so keys
+// can stay in the environment or the secrets file.
+type SearchProviderConfig struct {
+	Type string `json:"type"` // one of SearchProviders
+	// URL is the SearxNG instance to query; for the other APIs it
+	// replaces their public endpoint, e.g. to go through a proxy.
+	URL    string `json:"url,omitempty"`
+	APIKey string `json:"apiKey,omitempty" expand:"-"`
+	// CX is the Programmable Search Engine ID of Google Custom Search.
+	CX string `json:"cx,omitempty"`
+	// MaxResults is how many results to list (default 8); Gemini answers
+	// in prose and ignores it.
+	MaxResults int `json:"maxResults,omitempty"`
+}
+
+// Web search providers.
+const (
+	SearchGemini  = "gemini" // Google Search grounding through the Gemini API
+	SearchSearxNG = "searxng"
+	SearchBrave   = "brave"
+	SearchGoogle  = "google" // Google Custom Search JSON API
+	SearchBing    = "bing"
+)
+
+// SearchProviders are the supported search provider types.
+var SearchProviders = []string{SearchGemini, SearchSearxNG, SearchBrave, SearchGoogle, SearchBing}
+
+// validate reports a missing or unknown setting.
+func (p SearchProviderConfig) validate() error {
+	var missing string
+	switch p.Type {
+	case SearchGemini:
+	case SearchSearxNG:
+		if p.URL == "" {
+			missing = "url"
+		}
+	case SearchBrave, SearchBing:
+		if p.APIKey == "" {
+			missing = "apiKey"
+		}
+	case SearchGoogle:
+		switch {
+		case p.APIKey == "":
+			missing = "apiKey"
+		case p.CX == "":
+			missing = "cx"
+		}
+	default:
+		return fmt.Errorf("invalid type %q: must be one of %s", p.Type, strings.Join(SearchProviders, ", "))
+	}
+	if missing != "" {
+		return fmt.Errorf("%s requires %s", p.Type, missing)
+	}
+	if p.MaxResults < 0 {
+		return fmt.Errorf("maxResults must not be negative")
+	}
+	return nil
+}
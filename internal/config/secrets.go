@@ -0,0 +1,177 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// SecretEnv returns the environment variable name or, if it is not set,
+// the contents of the file named by name_FILE, the way Docker and
+// Kubernetes mount secrets, with trailing newlines trimmed.
+func SecretEnv(name string) (string, error) {
+	v, _, err := lookupSecretEnv(name)
+	return v, err
+}
+
+func lookupSecretEnv(name string) (string, bool, error) {
+	if v, ok := os.LookupEnv(name); ok {
+		return v, true, nil
+	}
+	file := os.Getenv(name + "_FILE")
+	if file == "" {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s_FILE: %w", name, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
+
+// loadSecrets reads a JSON object of names to values, such as
+// {"GITHUB_TOKEN": "ghp_…"}, kept outside config.json. A file encrypted
+// with SOPS (it has a "sops" key) is decrypted with the sops command.
+func loadSecrets(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file %s: %w", path, err)
+	}
+	if _, ok := raw["sops"]; ok {
+		if data, err = decryptSOPS(path); err != nil {
+			return nil, err
+		}
+		raw = nil
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse decrypted secrets file %s: %w", path, err)
+		}
+		delete(raw, "sops")
+	} else if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0o077 != 0 {
+		slog.Warn("Secrets file is readable by other users", "path", path, "mode", info.Mode().Perm())
+	}
+
+	secrets := make(map[string]string, len(raw))
+	for name, value := range raw {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, fmt.Errorf("failed to parse secrets file %s: %s must be a string", path, name)
+		}
+		secrets[name] = s
+	}
+	return secrets, nil
+}
+
+// sopsTimeout bounds decrypting the secrets file, which may ask a KMS.
+const sopsTimeout = 30 * time.Second
+
+func decryptSOPS(path string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sopsTimeout)
+	defer cancel()
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sops", "--decrypt", "--output-type", "json", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return nil, fmt.Errorf("secrets file %s is encrypted with SOPS, but the sops command is not installed", path)
+	case err != nil:
+		return nil, fmt.Errorf("failed to decrypt secrets file %s with sops: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// VaultConfig reads secrets from a HashiCorp Vault KV secret, as an
+// alternative or addition to the secrets file. The token comes from
+// VAULT_TOKEN (or VAULT_TOKEN_FILE), never from config.json.
+type VaultConfig struct {
+	// Address is the Vault server's URL (default VAULT_ADDR).
+	Address string `json:"address,omitempty"`
+	// Path is the secret to read, such as "secret/data/ravenbot" for a KV
+	// version 2 engine mounted at secret/. Its keys become secrets like
+	// those of the secrets file. Empty turns Vault off.
+	Path string `json:"path,omitempty"`
+	// Namespace is the Vault Enterprise namespace (default VAULT_NAMESPACE).
+	Namespace string `json:"namespace,omitempty"`
+}
+
+const (
+	vaultTimeout  = 10 * time.Second
+	maxVaultBytes = 1 << 20
+)
+
+// load reads the secret at v.Path.
+func (v VaultConfig) load() (map[string]string, error) {
+	addr := v.Address
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		return nil, fmt.Errorf("vault.path is set, but the address %q (vault.address or VAULT_ADDR) is not an http(s) URL", addr)
+	}
+	token, err := SecretEnv("VAULT_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, fmt.Errorf("vault.path is set, but VAULT_TOKEN and VAULT_TOKEN_FILE are not")
+	}
+	namespace := v.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+	defer cancel()
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(v.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault secret %s: %w", v.Path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read vault secret %s: vault returned %s", v.Path, resp.Status)
+	}
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxVaultBytes)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse vault secret %s: %w", v.Path, err)
+	}
+
+	// KV version 2 nests the values under data.data, next to metadata.
+	data := body.Data
+	if inner, ok := data["data"].(map[string]any); ok && data["metadata"] != nil {
+		data = inner
+	}
+	secrets := make(map[string]string, len(data))
+	for name, value := range data {
+		s, ok := value.(string)
+		if !ok {
+			slog.Warn("Skipping a vault secret value that is not a string", "path", v.Path, "key", name)
+			continue
+		}
+		secrets[name] = s
+	}
+	return secrets, nil
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Values of BotConfig.DefaultModel.
+const (
+	ModelAuto  = "auto" // classify each message with the routing prompt
+	ModelFlash = "flash"
+	ModelPro   = "pro"
+)
+
+// Setting is a BotConfig tunable that can be overridden at runtime with /set.
+// Key is its config.json name.
+type Setting struct {
+	Key   string
+	apply func(b *BotConfig, value string) error
+	get   func(b BotConfig) string
+}
+
+// Settings lists the runtime tunables in display order.
+var Settings = []Setting{
+	{
+		Key: "compressionThreshold",
+		apply: func(b *BotConfig, v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || f <= 0 || f > 1 {
+				return fmt.Errorf("must be a number above 0 and at most 1")
+			}
+			b.CompressionThreshold = f
+			return nil
+		},
+		get: func(b BotConfig) string { return strconv.FormatFloat(b.CompressionThreshold, 'g', -1, 64) },
+	},
+	{
+		Key:   "flashTokenLimit",
+		apply: func(b *BotConfig, v string) error { return parseTokens(v, &b.FlashTokenLimit) },
+		get:   func(b BotConfig) string { return strconv.FormatInt(b.FlashTokenLimit, 10) },
+	},
+	{
+		Key:   "proTokenLimit",
+		apply: func(b *BotConfig, v string) error { return parseTokens(v, &b.ProTokenLimit) },
+		get:   func(b BotConfig) string { return strconv.FormatInt(b.ProTokenLimit, 10) },
+	},
+	{
+		Key: "maxConcurrentMissions",
+		apply: func(b *BotConfig, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("must be a whole number, 0 for unlimited")
+			}
+			b.MaxConcurrentMissions = n
+			return nil
+		},
+		get: func(b BotConfig) string { return strconv.Itoa(b.MaxConcurrentMissions) },
+	},
+	{
+		Key: "maxSessionEvents",
+		apply: func(b *BotConfig, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("must be a whole number, 0 for the default or negative for all")
+			}
+			b.MaxSessionEvents = n
+			return nil
+		},
+		get: func(b BotConfig) string { return strconv.Itoa(b.MaxSessionEvents) },
+	},
+	{
+		Key: "defaultModel",
+		apply: func(b *BotConfig, v string) error {
+			switch v = strings.ToLower(v); v {
+			case ModelAuto, ModelFlash, ModelPro:
+				b.DefaultModel = v
+				return nil
+			}
+			return fmt.Errorf("must be %q, %q or %q", ModelAuto, ModelFlash, ModelPro)
+		},
+		get: func(b BotConfig) string {
+			if b.DefaultModel == "" {
+				return ModelAuto
+			}
+			return b.DefaultModel
+		},
+	},
+}
+
+func parseTokens(v string, dst *int64) error {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("must be a whole number of tokens, 0 to disable compression")
+	}
+	*dst = n
+	return nil
+}
+
+// LookupSetting finds a tunable by key, ignoring case.
+func LookupSetting(key string) (Setting, bool) {
+	for _, s := range Settings {
+		if strings.EqualFold(s.Key, key) {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// Validate reports whether value is acceptable for the setting.
+func (s Setting) Validate(value string) error {
+	var b BotConfig
+	return s.apply(&b, strings.TrimSpace(value))
+}
+
+// Value returns the setting's current value in b.
+func (s Setting) Value(b BotConfig) string {
+	return s.get(b)
+}
+
+// WithSettings returns a copy of b with the stored /set overrides applied.
+// Unknown keys and invalid values are logged and skipped.
+func (b BotConfig) WithSettings(values map[string]string) BotConfig {
+	for key, value := range values {
+		s, ok := LookupSetting(key)
+		if !ok {
+			slog.Warn("Ignoring unknown runtime setting", "key", key)
+			continue
+		}
+		if err := s.apply(&b, strings.TrimSpace(value)); err != nil {
+			slog.Warn("Ignoring invalid runtime setting", "key", key, "value", value, "error", err)
+		}
+	}
+	return b
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings(t *testing.T) {
+	t.Parallel()
+	s, ok := LookupSetting("DEFAULTMODEL")
+	assert.True(t, ok)
+	assert.Equal(t, "defaultModel", s.Key)
+	assert.NoError(t, s.Validate("Pro"))
+	assert.Error(t, s.Validate("gpt-4"))
+	assert.Equal(t, ModelAuto, s.Value(BotConfig{}))
+
+	_, ok = LookupSetting("temperature")
+	assert.False(t, ok)
+
+	base := BotConfig{CompressionThreshold: 0.8, FlashTokenLimit: 1000, ProTokenLimit: 2000}
+	got := base.WithSettings(map[string]string{
+		"compressionThreshold":  "0.5",
+		"proTokenLimit":         "-1", // invalid, ignored
+		"maxConcurrentMissions": " 3 ",
+		"defaultModel":          "FLASH",
+		"unknown":               "x",
+	})
+	assert.Equal(t, 0.5, got.CompressionThreshold)
+	assert.Equal(t, int64(1000), got.FlashTokenLimit)
+	assert.Equal(t, int64(2000), got.ProTokenLimit)
+	assert.Equal(t, 3, got.MaxConcurrentMissions)
+	assert.Equal(t, ModelFlash, got.DefaultModel)
+	assert.Equal(t, 0.8, base.CompressionThreshold, "the original is unchanged")
+
+	for _, s := range Settings {
+		assert.Error(t, s.Validate("nope"), s.Key)
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"time"
+)
+
+// DefaultConfigFile is the file LoadConfig reads unless RAVENBOT_CONFIG
+// names another.
+const DefaultConfigFile = "config.json"
+
+// configFile returns the file LoadConfig reads.
+func configFile() string {
+	if f := os.Getenv("RAVENBOT_CONFIG"); f != "" {
+		return f
+	}
+	return DefaultConfigFile
+}
+
+// Files returns the files the configuration is read from: the config file
+// and the secrets file, if any.
+func (c *Config) Files() []string {
+	files := []string{configFile()}
+	if c.SecretsFile != "" {
+		files = append(files, c.SecretsFile)
+	}
+	return files
+}
+
+// Watch checks the files from files every interval and calls onChange once
+// their contents have changed and then stayed the same for another
+// interval, so a file still being written isn't picked up half-way, until
+// ctx is done. A file that is removed or created counts as a change.
+func Watch(ctx context.Context, files func() []string, interval time.Duration, onChange func()) {
+	applied := fingerprint(files())
+	var pending [sha256.Size]byte
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		current := fingerprint(files())
+		switch {
+		case current == applied:
+			pending = [sha256.Size]byte{}
+		case current == pending:
+			applied = current
+			onChange()
+		default:
+			pending = current
+		}
+	}
+}
+
+// fingerprint hashes the names and contents of files.
+func fingerprint(files []string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, f := range files {
+		h.Write([]byte(f))
+		data, err := os.ReadFile(f)
+		if err != nil {
+			h.Write([]byte{0})
+			continue
+		}
+		h.Write([]byte{1})
+		h.Write(data)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
@@ -0,0 +1,31 @@
+package config
+
+import "fmt"
+
+// WeatherConfig sets the defaults of the get_weather tool.
+type WeatherConfig struct {
+	// Location is used when a request names none, e.g. "Dallas, TX" or
+	// "32.78,-96.80".
+	Location string `json:"location,omitempty"`
+	// Units is "metric" (default) or "imperial" (°F, mph, inches).
+	Units string `json:"units,omitempty"`
+}
+
+// Weather units.
+const (
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+)
+
+// Imperial reports whether forecasts use imperial units.
+func (w WeatherConfig) Imperial() bool { return w.Units == UnitsImperial }
+
+// validate reports an invalid setting.
+func (w WeatherConfig) validate() error {
+	switch w.Units {
+	case "", UnitsMetric, UnitsImperial:
+		return nil
+	default:
+		return fmt.Errorf("invalid units %q: must be %q or %q", w.Units, UnitsMetric, UnitsImperial)
+	}
+}
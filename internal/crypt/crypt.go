@@ -0,0 +1,116 @@
+// Package crypt encrypts sensitive values at rest with AES-256-GCM.
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySize is the length of an AES-256 key in bytes.
+const KeySize = 32
+
+// prefix marks encrypted values, so rows written before encryption was
+// enabled are still read as plaintext.
+const prefix = "enc:v1:"
+
+// ErrNoKey is returned when an encrypted value is read without a key.
+var ErrNoKey = errors.New("value is encrypted but no encryption key is configured")
+
+// Cipher encrypts and decrypts values. A nil *Cipher leaves values
+// unencrypted.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New returns a Cipher for a KeySize-byte key.
+func New(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// LoadKey reads a key given as base64 or hex, either directly or from the
+// file at keyFile. It returns nil when neither is set.
+func LoadKey(key, keyFile string) ([]byte, error) {
+	if key == "" && keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+		}
+		key = string(data)
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, nil
+	}
+	if b, err := hex.DecodeString(key); err == nil && len(b) == KeySize {
+		return b, nil
+	}
+	if b, err := base64.StdEncoding.DecodeString(key); err == nil && len(b) == KeySize {
+		return b, nil
+	}
+	return nil, fmt.Errorf("encryption key must be %d bytes encoded as base64 or hex (e.g. openssl rand -base64 32)", KeySize)
+}
+
+// Enabled reports whether c encrypts values.
+func (c *Cipher) Enabled() bool {
+	return c != nil
+}
+
+// EncryptString encrypts s, or returns it unchanged when c is nil.
+func (c *Cipher) EncryptString(s string) (string, error) {
+	if c == nil {
+		return s, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(s), nil)
+	return prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptString decrypts a value from EncryptString. Values that were never
+// encrypted are returned unchanged.
+func (c *Cipher) DecryptString(s string) (string, error) {
+	if !IsEncrypted(s) {
+		return s, nil
+	}
+	if c == nil {
+		return "", ErrNoKey
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, prefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+	n := c.aead.NonceSize()
+	if len(sealed) < n {
+		return "", errors.New("encrypted value is too short")
+	}
+	plain, err := c.aead.Open(nil, sealed[:n], sealed[n:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value (wrong key?): %w", err)
+	}
+	return string(plain), nil
+}
+
+// IsEncrypted reports whether s was produced by EncryptString.
+func IsEncrypted(s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
@@ -0,0 +1,81 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte { return bytes.Repeat([]byte{b}, KeySize) }
+
+func TestCipher_RoundTrip(t *testing.T) {
+	t.Parallel()
+	c, err := New(testKey(1))
+	require.NoError(t, err)
+
+	enc, err := c.EncryptString("my server password is hunter2")
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(enc))
+	assert.NotContains(t, enc, "hunter2")
+
+	again, _ := c.EncryptString("my server password is hunter2")
+	assert.NotEqual(t, enc, again, "each value gets a fresh nonce")
+
+	plain, err := c.DecryptString(enc)
+	require.NoError(t, err)
+	assert.Equal(t, "my server password is hunter2", plain)
+
+	// Rows written before encryption was enabled read as plaintext.
+	plain, err = c.DecryptString("legacy summary")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy summary", plain)
+
+	other, _ := New(testKey(2))
+	_, err = other.DecryptString(enc)
+	assert.Error(t, err)
+
+	var none *Cipher
+	_, err = none.DecryptString(enc)
+	assert.ErrorIs(t, err, ErrNoKey)
+	s, err := none.EncryptString("plain")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", s)
+	assert.False(t, none.Enabled())
+}
+
+func TestLoadKey(t *testing.T) {
+	t.Parallel()
+	key := testKey(7)
+
+	got, err := LoadKey(base64.StdEncoding.EncodeToString(key), "")
+	require.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	got, err = LoadKey(hex.EncodeToString(key), "")
+	require.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	path := filepath.Join(t.TempDir(), "ravenbot.key")
+	require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600))
+	got, err = LoadKey("", path)
+	require.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	got, err = LoadKey("", "")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = LoadKey("too-short", "")
+	assert.Error(t, err)
+	_, err = LoadKey("", filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	_, err = New([]byte("short"))
+	assert.Error(t, err)
+}
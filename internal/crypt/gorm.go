@@ -0,0 +1,105 @@
+package crypt
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// GormOption returns a gorm.Option that encrypts the given byte-slice
+// columns of table on insert and decrypts them on query. This covers models
+// owned by other packages, such as the ADK session events.
+//
+// Encrypted values are stored as JSON strings, so JSON columns still hold
+// valid JSON. Rows written before encryption was enabled are read as is.
+func GormOption(c *Cipher, table string, columns ...string) gorm.Option {
+	return &gormColumns{cipher: c, table: table, columns: columns}
+}
+
+type gormColumns struct {
+	cipher  *Cipher
+	table   string
+	columns []string
+}
+
+func (g *gormColumns) Apply(*gorm.Config) error { return nil }
+
+func (g *gormColumns) AfterInitialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("crypt:encrypt", g.encrypt); err != nil {
+		return err
+	}
+	// Restore the caller's plaintext once the row is written.
+	if err := db.Callback().Create().After("gorm:create").Register("crypt:restore", g.decrypt); err != nil {
+		return err
+	}
+	return db.Callback().Query().After("gorm:query").Register("crypt:decrypt", g.decrypt)
+}
+
+func (g *gormColumns) encrypt(db *gorm.DB) {
+	if g.cipher == nil {
+		return
+	}
+	g.each(db, func(b []byte) ([]byte, error) {
+		if len(b) == 0 {
+			return b, nil
+		}
+		s, err := g.cipher.EncryptString(string(b))
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(s)
+	})
+}
+
+func (g *gormColumns) decrypt(db *gorm.DB) {
+	g.each(db, func(b []byte) ([]byte, error) {
+		var s string
+		if len(b) == 0 || b[0] != '"' || json.Unmarshal(b, &s) != nil || !IsEncrypted(s) {
+			return b, nil
+		}
+		plain, err := g.cipher.DecryptString(s)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(plain), nil
+	})
+}
+
+// each applies fn to the configured columns of every row in the statement.
+func (g *gormColumns) each(db *gorm.DB, fn func([]byte) ([]byte, error)) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.Schema.Table != g.table {
+		return
+	}
+	var fields []*schema.Field
+	for _, col := range g.columns {
+		if f := stmt.Schema.LookUpField(col); f != nil && f.FieldType.Kind() == reflect.Slice && f.FieldType.Elem().Kind() == reflect.Uint8 {
+			fields = append(fields, f)
+		}
+	}
+	apply := func(row reflect.Value) {
+		for _, f := range fields {
+			v := f.ReflectValueOf(stmt.Context, row)
+			out, err := fn(v.Bytes())
+			if err != nil {
+				_ = db.AddError(err)
+				return
+			}
+			v.SetBytes(out)
+		}
+	}
+
+	rv := reflect.Indirect(stmt.ReflectValue)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range rv.Len() {
+			if row := reflect.Indirect(rv.Index(i)); row.Kind() == reflect.Struct {
+				apply(row)
+			}
+		}
+	case reflect.Struct:
+		apply(rv)
+	}
+}
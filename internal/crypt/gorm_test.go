@@ -0,0 +1,53 @@
+package crypt
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	adkdb "google.golang.org/adk/session/database"
+	"google.golang.org/genai"
+)
+
+func TestGormOption_SessionEvents(t *testing.T) {
+	t.Parallel()
+	conn, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	conn.SetMaxOpenConns(1)
+	defer func() { _ = conn.Close() }()
+
+	c, err := New(testKey(3))
+	require.NoError(t, err)
+	svc, err := adkdb.NewSessionService(&sqlite.Dialector{Conn: conn}, GormOption(c, "events", "content", "actions"))
+	require.NoError(t, err)
+	require.NoError(t, adkdb.AutoMigrate(svc))
+	ctx := t.Context()
+
+	created, err := svc.Create(ctx, &session.CreateRequest{AppName: "ravenbot", UserID: "u", SessionID: "s"})
+	require.NoError(t, err)
+	event := session.NewEvent("inv")
+	event.Author = "user"
+	event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("my API key is sk-secret", genai.RoleUser)}
+	require.NoError(t, svc.AppendEvent(ctx, created.Session, event))
+	assert.Equal(t, "my API key is sk-secret", event.Content.Parts[0].Text)
+
+	var raw string
+	require.NoError(t, conn.QueryRowContext(ctx, `SELECT content FROM events`).Scan(&raw))
+	assert.NotContains(t, raw, "sk-secret")
+	assert.Contains(t, raw, prefix)
+
+	got, err := svc.Get(ctx, &session.GetRequest{AppName: "ravenbot", UserID: "u", SessionID: "s"})
+	require.NoError(t, err)
+	require.Equal(t, 1, got.Session.Events().Len())
+	assert.Equal(t, "my API key is sk-secret", got.Session.Events().At(0).Content.Parts[0].Text)
+
+	// Without the key the events can't be read.
+	locked, err := adkdb.NewSessionService(&sqlite.Dialector{Conn: conn}, GormOption(nil, "events", "content", "actions"))
+	require.NoError(t, err)
+	_, err = locked.Get(ctx, &session.GetRequest{AppName: "ravenbot", UserID: "u", SessionID: "s"})
+	assert.ErrorIs(t, err, ErrNoKey)
+}
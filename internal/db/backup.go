@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// Snapshot writes a consistent copy of the database to path, which must not
+// exist yet. It returns ErrSnapshotUnsupported on PostgreSQL.
+func (db *DB) Snapshot(ctx context.Context, path string) error {
+	if err := db.dialect.snapshot(ctx, db.DB, path); err != nil {
+		return fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	return nil
+}
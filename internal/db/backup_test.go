@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if _, err := db.AddTask(ctx, "s1", "Back me up"); err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+	if err := db.Snapshot(ctx, path); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	restored, err := InitDB(path)
+	if err != nil {
+		t.Fatalf("failed to open snapshot: %v", err)
+	}
+	defer func() { _ = restored.Close() }()
+	tasks, err := restored.ListTasks(ctx, "s1", false)
+	if err != nil || len(tasks) != 1 || tasks[0].Title != "Back me up" {
+		t.Errorf("snapshot missing data: %+v err=%v", tasks, err)
+	}
+
+	// The target must not already exist.
+	if err := db.Snapshot(ctx, path); err == nil {
+		t.Error("expected error when the snapshot file exists")
+	}
+}
+
+func TestSnapshot_Postgres(t *testing.T) {
+	t.Parallel()
+	err := (postgresDialect{}).snapshot(context.Background(), nil, "x")
+	if !errors.Is(err, ErrSnapshotUnsupported) {
+		t.Errorf("expected ErrSnapshotUnsupported, got %v", err)
+	}
+}
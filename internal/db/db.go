@@ -1,17 +1,58 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	_ "github.com/glebarez/go-sqlite"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/raythurman2386/ravenbot/internal/crypt"
 )
 
-// DB represents the database connection.
+// DB represents the database connection. Queries are written with "?"
+// placeholders and SQLite syntax; the dialect adapts them to the backend.
 type DB struct {
 	*sql.DB
+	dialect dialect
+
+	// cipher encrypts session summaries, prompts and transcripts at rest;
+	// nil stores them as plaintext. See SetCipher.
+	cipher *crypt.Cipher
+
+	// recorder and slowQuery time queries; see Instrument.
+	recorder  QueryRecorder
+	slowQuery time.Duration
+
+	// settings caches the settings table; nil until first read.
+	settings   map[string]string
+	settingsMu sync.Mutex
+}
+
+// SetCipher enables at-rest encryption of session summaries and transcript
+// messages written from now on. Existing plaintext rows stay readable.
+func (db *DB) SetCipher(c *crypt.Cipher) {
+	db.cipher = c
+}
+
+// Cipher returns the cipher set with SetCipher, or nil.
+func (db *DB) Cipher() *crypt.Cipher {
+	return db.cipher
+}
+
+// Open connects to the database named by target: a postgres:// or
+// postgresql:// URL selects PostgreSQL, anything else is a SQLite file path.
+func Open(target string) (*DB, error) {
+	if strings.HasPrefix(target, "postgres://") || strings.HasPrefix(target, "postgresql://") {
+		return InitPostgres(target)
+	}
+	return InitDB(target)
 }
 
 // InitDB initializes the SQLite database and creates the schema.
@@ -55,15 +96,76 @@ func InitDB(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	instance := &DB{db}
+	instance := &DB{DB: db, dialect: sqliteDialect{}}
+	if err := instance.migrate(); err != nil {
+		return nil, fmt.Errorf("migration failed: %w", err)
+	}
+
+	return instance, nil
+}
+
+// InitPostgres connects to a PostgreSQL database and creates the schema.
+//
+// Sessions use UTC unless the URL sets a timezone, matching SQLite's
+// CURRENT_TIMESTAMP so stored times compare the same way on both backends.
+// The pool is shared with GORM (ADK session service) as with SQLite.
+func InitPostgres(dsn string) (*DB, error) {
+	connCfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+	}
+	if _, ok := connCfg.RuntimeParams["timezone"]; !ok {
+		connCfg.RuntimeParams["timezone"] = "UTC"
+	}
+
+	db := stdlib.OpenDB(*connCfg)
+	db.SetMaxOpenConns(10)
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	instance := &DB{DB: db, dialect: postgresDialect{}}
 	if err := instance.migrate(); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("migration failed: %w", err)
 	}
 
 	return instance, nil
 }
 
+// Driver returns the storage backend in use (DriverSQLite or DriverPostgres).
+func (db *DB) Driver() string {
+	return db.dialect.name()
+}
+
+// ExecContext runs a statement written with "?" placeholders.
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer db.observe(query, time.Now())
+	return db.DB.ExecContext(ctx, db.dialect.rebind(query), args...)
+}
+
+// QueryContext runs a query written with "?" placeholders.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	defer db.observe(query, time.Now())
+	return db.DB.QueryContext(ctx, db.dialect.rebind(query), args...)
+}
+
+// QueryRowContext runs a single-row query written with "?" placeholders.
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer db.observe(query, time.Now())
+	return db.DB.QueryRowContext(ctx, db.dialect.rebind(query), args...)
+}
+
+// insert runs an INSERT into a table with an "id" key and returns the new row's ID.
+func (db *DB) insert(ctx context.Context, query string, args ...any) (int64, error) {
+	defer db.observe(query, time.Now())
+	return db.dialect.insert(ctx, db.DB, query, args...)
+}
+
 func (db *DB) migrate() error {
+	ctx := context.Background()
 	schema := `
 	CREATE TABLE IF NOT EXISTS headlines (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -84,6 +186,15 @@ func (db *DB) migrate() error {
 		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);
 
+	CREATE TABLE IF NOT EXISTS session_summary_versions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		summary TEXT NOT NULL,
+		source TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_summary_versions_session ON session_summary_versions(session_id, id);
+
 	CREATE TABLE IF NOT EXISTS reminders (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		session_id TEXT NOT NULL,
@@ -92,7 +203,238 @@ func (db *DB) migrate() error {
 		delivered INTEGER DEFAULT 0,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);
+
+	CREATE TABLE IF NOT EXISTS tasks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		title TEXT NOT NULL,
+		done INTEGER DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		completed_at TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_tasks_session ON tasks(session_id, done);
+
+	CREATE TABLE IF NOT EXISTS memories (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_memories_session ON memories(session_id);
+
+	CREATE TABLE IF NOT EXISTS subscriptions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		url TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(session_id, url)
+	);
+
+	CREATE TABLE IF NOT EXISTS feeds (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT UNIQUE NOT NULL,
+		title TEXT NOT NULL DEFAULT '',
+		interval_minutes INTEGER NOT NULL DEFAULT 0,
+		etag TEXT NOT NULL DEFAULT '',
+		last_modified TEXT NOT NULL DEFAULT '',
+		last_fetched TIMESTAMP,
+		last_error TEXT NOT NULL DEFAULT '',
+		failures INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS feed_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		feed_id INTEGER NOT NULL,
+		item_key TEXT NOT NULL,
+		title TEXT NOT NULL,
+		link TEXT NOT NULL DEFAULT '',
+		description TEXT NOT NULL DEFAULT '',
+		published TIMESTAMP,
+		first_seen TIMESTAMP NOT NULL,
+		UNIQUE(feed_id, item_key)
+	);
+	CREATE INDEX IF NOT EXISTS idx_feed_items_seen ON feed_items(first_seen);
+
+	CREATE TABLE IF NOT EXISTS resource_watches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		server TEXT NOT NULL,
+		uri TEXT NOT NULL,
+		platform TEXT NOT NULL DEFAULT '',
+		channel_id TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(session_id, server, uri)
+	);
+
+	CREATE TABLE IF NOT EXISTS jules_sessions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		name TEXT UNIQUE NOT NULL,
+		repo TEXT NOT NULL,
+		task TEXT NOT NULL,
+		platform TEXT NOT NULL DEFAULT '',
+		channel_id TEXT NOT NULL DEFAULT '',
+		state TEXT NOT NULL DEFAULT '',
+		url TEXT NOT NULL DEFAULT '',
+		pr_url TEXT NOT NULL DEFAULT '',
+		detail TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_jules_sessions_session ON jules_sessions(session_id, id);
+
+	CREATE TABLE IF NOT EXISTS digest_runs (
+		session_id TEXT PRIMARY KEY,
+		last_run TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS transcripts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		role TEXT NOT NULL,
+		content TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_transcripts_session ON transcripts(session_id, id);
+
+	CREATE TABLE IF NOT EXISTS feedback (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		issue_url TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS chat_sessions (
+		id TEXT PRIMARY KEY,
+		channel_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		last_active TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_chat_sessions_channel ON chat_sessions(channel_id);
+
+	CREATE TABLE IF NOT EXISTS active_sessions (
+		channel_id TEXT PRIMARY KEY,
+		session_id TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS embeddings (
+		namespace TEXT NOT NULL,
+		key TEXT NOT NULL,
+		vector BLOB NOT NULL,
+		dims INTEGER NOT NULL,
+		metadata TEXT NOT NULL DEFAULT '{}',
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (namespace, key)
+	);
+
+	CREATE TABLE IF NOT EXISTS job_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_name TEXT NOT NULL,
+		job_type TEXT NOT NULL,
+		triggered_by TEXT NOT NULL,
+		status TEXT NOT NULL,
+		report_path TEXT NOT NULL DEFAULT '',
+		error TEXT NOT NULL DEFAULT '',
+		tokens INTEGER NOT NULL DEFAULT 0,
+		started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		finished_at TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_job_runs_name ON job_runs(job_name, id);
+
+	CREATE TABLE IF NOT EXISTS paused_jobs (
+		job_name TEXT PRIMARY KEY,
+		paused_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS usage_daily (
+		day TEXT PRIMARY KEY,
+		messages INTEGER NOT NULL DEFAULT 0,
+		missions INTEGER NOT NULL DEFAULT 0,
+		input_tokens INTEGER NOT NULL DEFAULT 0,
+		output_tokens INTEGER NOT NULL DEFAULT 0,
+		cost_usd REAL NOT NULL DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS usage_tools (
+		day TEXT NOT NULL,
+		tool TEXT NOT NULL,
+		calls INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (day, tool)
+	);
+
+	CREATE TABLE IF NOT EXISTS session_locales (
+		session_id TEXT PRIMARY KEY,
+		locale TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS session_prompts (
+		session_id TEXT PRIMARY KEY,
+		prompt TEXT NOT NULL,
+		mode TEXT NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS settings (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS mcp_servers (
+		name TEXT PRIMARY KEY,
+		config TEXT NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS mcp_tool_schemas (
+		server TEXT PRIMARY KEY,
+		tools TEXT NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
 	`
-	_, err := db.Exec(schema)
-	return err
+	if _, err := db.ExecContext(ctx, db.dialect.schema(schema)); err != nil {
+		return err
+	}
+
+	// Columns added after the initial schema. CREATE TABLE IF NOT EXISTS
+	// leaves existing tables untouched, so these are applied explicitly.
+	columns := []struct{ table, column, definition string }{
+		{"reminders", "recurrence", "TEXT NOT NULL DEFAULT ''"},
+		{"reminders", "platform", "TEXT NOT NULL DEFAULT ''"},
+		{"reminders", "channel_id", "TEXT NOT NULL DEFAULT ''"},
+		{"briefings", "topic", "TEXT NOT NULL DEFAULT ''"},
+		{"briefings", "tags", "TEXT NOT NULL DEFAULT ''"},
+		{"briefings", "content_hash", "TEXT NOT NULL DEFAULT ''"},
+		{"briefings", "session_id", "TEXT NOT NULL DEFAULT ''"},
+	}
+	for _, c := range columns {
+		if err := db.ensureColumn(ctx, c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	if _, err := db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_briefings_hash ON briefings(content_hash)`); err != nil {
+		return fmt.Errorf("failed to index briefing hashes: %w", err)
+	}
+	if err := db.backfillBriefingHashes(ctx); err != nil {
+		return err
+	}
+	return db.dialect.ensureFullText(ctx, db.DB)
+}
+
+// ensureColumn adds a column to an existing table if it is not already present.
+func (db *DB) ensureColumn(ctx context.Context, table, column, definition string) error {
+	exists, err := db.dialect.hasColumn(ctx, db.DB, table, column)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	if exists {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+	return nil
 }
@@ -0,0 +1,241 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Supported storage drivers.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// ErrSnapshotUnsupported is returned by Snapshot on backends that are backed
+// up with their own tooling (pg_dump for PostgreSQL).
+var ErrSnapshotUnsupported = errors.New("snapshots are only supported on SQLite; back up PostgreSQL with pg_dump")
+
+// dialect hides the SQL differences between the storage backends. Queries
+// and the schema are written for SQLite; other dialects translate them.
+type dialect interface {
+	name() string
+	// rebind rewrites "?" placeholders into the backend's syntax.
+	rebind(query string) string
+	// schema translates SQLite DDL into the backend's syntax.
+	schema(ddl string) string
+	// hasColumn reports whether table already has column.
+	hasColumn(ctx context.Context, db *sql.DB, table, column string) (bool, error)
+	// insert runs an INSERT on a table with an "id" key and returns the new ID.
+	insert(ctx context.Context, db *sql.DB, query string, args ...any) (int64, error)
+	// ensureFullText creates the full-text indexes over briefings,
+	// headlines and transcripts.
+	ensureFullText(ctx context.Context, db *sql.DB) error
+	// fullTextSearch returns a query over table's text column, best match
+	// first, and its arguments. It selects the row ID, a snippet marking
+	// matched terms with "**", then columns (qualified with "t."). A
+	// non-empty filter is an extra condition on t, with placeholders for
+	// filterArgs.
+	fullTextSearch(table, column, columns, terms, filter string, filterArgs []any, limit int) (string, []any)
+	// snapshot writes a consistent copy of the database to path.
+	snapshot(ctx context.Context, db *sql.DB, path string) error
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) name() string { return DriverSQLite }
+
+func (sqliteDialect) rebind(query string) string { return query }
+
+func (sqliteDialect) schema(ddl string) string { return ddl }
+
+func (sqliteDialect) hasColumn(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+func (sqliteDialect) insert(ctx context.Context, db *sql.DB, query string, args ...any) (int64, error) {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// ensureFullText creates external-content FTS5 tables kept in sync by
+// triggers, and indexes existing rows the first time they are created.
+func (sqliteDialect) ensureFullText(ctx context.Context, db *sql.DB) error {
+	for _, t := range []struct{ table, column string }{{"briefings", "content"}, {"headlines", "title"}, {"transcripts", "content"}} {
+		fts := t.table + "_fts"
+		var exists bool
+		if err := db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE name = ?)`, fts).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", fts, err)
+		}
+		ddl := strings.NewReplacer("{fts}", fts, "{table}", t.table, "{column}", t.column).Replace(`
+		CREATE VIRTUAL TABLE IF NOT EXISTS {fts} USING fts5({column}, content='{table}', content_rowid='id');
+		CREATE TRIGGER IF NOT EXISTS {fts}_ai AFTER INSERT ON {table} BEGIN
+			INSERT INTO {fts}(rowid, {column}) VALUES (new.id, new.{column});
+		END;
+		CREATE TRIGGER IF NOT EXISTS {fts}_ad AFTER DELETE ON {table} BEGIN
+			INSERT INTO {fts}({fts}, rowid, {column}) VALUES ('delete', old.id, old.{column});
+		END;
+		CREATE TRIGGER IF NOT EXISTS {fts}_au AFTER UPDATE ON {table} BEGIN
+			INSERT INTO {fts}({fts}, rowid, {column}) VALUES ('delete', old.id, old.{column});
+			INSERT INTO {fts}(rowid, {column}) VALUES (new.id, new.{column});
+		END;
+		`)
+		if _, err := db.ExecContext(ctx, ddl); err != nil {
+			return fmt.Errorf("failed to create %s: %w", fts, err)
+		}
+		if !exists {
+			if _, err := db.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s(%s) VALUES ('rebuild')`, fts, fts)); err != nil {
+				return fmt.Errorf("failed to index %s: %w", t.table, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (sqliteDialect) fullTextSearch(table, _, columns, terms, filter string, filterArgs []any, limit int) (string, []any) {
+	query := strings.NewReplacer("{fts}", table+"_fts", "{table}", table, "{columns}", columns, "{filter}", andFilter(filter)).Replace(`
+		SELECT t.id, snippet({fts}, 0, '**', '**', '…', 16), {columns}
+		FROM {fts} JOIN {table} t ON t.id = {fts}.rowid
+		WHERE {fts} MATCH ?{filter} ORDER BY {fts}.rank, t.id DESC LIMIT ?
+	`)
+	args := append([]any{ftsQuery(terms)}, filterArgs...)
+	return query, append(args, limit)
+}
+
+// andFilter formats an optional extra WHERE condition.
+func andFilter(filter string) string {
+	if filter == "" {
+		return ""
+	}
+	return " AND (" + filter + ")"
+}
+
+// snapshot uses VACUUM INTO, which copies a consistent, compacted database
+// without blocking readers. path must not exist yet.
+func (sqliteDialect) snapshot(ctx context.Context, db *sql.DB, path string) error {
+	_, err := db.ExecContext(ctx, `VACUUM INTO ?`, path)
+	return err
+}
+
+// ftsQuery turns free text into an FTS5 query that matches rows containing
+// every word (or a word starting with it), treating operators literally.
+func ftsQuery(terms string) string {
+	words := strings.Fields(terms)
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"*`
+	}
+	return strings.Join(words, " ")
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) name() string { return DriverPostgres }
+
+// rebind numbers the placeholders ($1, $2, ...), leaving question marks
+// inside string literals alone.
+func (postgresDialect) rebind(query string) string {
+	var sb strings.Builder
+	sb.Grow(len(query) + 8)
+	n := 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			inString = !inString
+		case c == '?' && !inString:
+			n++
+			sb.WriteByte('$')
+			sb.WriteString(strconv.Itoa(n))
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+func (postgresDialect) schema(ddl string) string {
+	return strings.NewReplacer(
+		"INTEGER PRIMARY KEY AUTOINCREMENT", "BIGSERIAL PRIMARY KEY",
+		" BLOB ", " BYTEA ",
+		" REAL ", " DOUBLE PRECISION ",
+	).Replace(ddl)
+}
+
+func (postgresDialect) hasColumn(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2
+		)
+	`
+	if err := db.QueryRowContext(ctx, query, table, column).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// insert uses RETURNING because the pgx driver does not support
+// LastInsertId.
+func (d postgresDialect) insert(ctx context.Context, db *sql.DB, query string, args ...any) (int64, error) {
+	var id int64
+	if err := db.QueryRowContext(ctx, d.rebind(query)+" RETURNING id", args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (postgresDialect) ensureFullText(ctx context.Context, db *sql.DB) error {
+	ddl := `
+	CREATE INDEX IF NOT EXISTS idx_briefings_fts ON briefings USING GIN (to_tsvector('simple', content));
+	CREATE INDEX IF NOT EXISTS idx_headlines_fts ON headlines USING GIN (to_tsvector('simple', title));
+	CREATE INDEX IF NOT EXISTS idx_transcripts_fts ON transcripts USING GIN (to_tsvector('simple', content));
+	`
+	if _, err := db.ExecContext(ctx, ddl); err != nil {
+		return fmt.Errorf("failed to create full-text indexes: %w", err)
+	}
+	return nil
+}
+
+func (postgresDialect) fullTextSearch(table, column, columns, terms, filter string, filterArgs []any, limit int) (string, []any) {
+	query := strings.NewReplacer("{table}", table, "{column}", column, "{columns}", columns, "{filter}", andFilter(filter)).Replace(`
+		SELECT t.id, ts_headline('simple', t.{column}, plainto_tsquery('simple', ?), 'StartSel=**, StopSel=**, MaxWords=24, MinWords=8'), {columns}
+		FROM {table} t WHERE to_tsvector('simple', t.{column}) @@ plainto_tsquery('simple', ?){filter}
+		ORDER BY ts_rank(to_tsvector('simple', t.{column}), plainto_tsquery('simple', ?)) DESC, t.id DESC LIMIT ?
+	`)
+	args := append([]any{terms, terms}, filterArgs...)
+	return query, append(args, terms, limit)
+}
+
+func (postgresDialect) snapshot(context.Context, *sql.DB, string) error {
+	return ErrSnapshotUnsupported
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AddHeadline records a feed item as seen. It reports false if the URL had
+// already been recorded, which is how digests skip items they have covered.
+func (db *DB) AddHeadline(ctx context.Context, url, title string) (bool, error) {
+	query := `INSERT INTO headlines (url, title) VALUES (?, ?) ON CONFLICT(url) DO NOTHING`
+	res, err := db.ExecContext(ctx, query, url, title)
+	if err != nil {
+		return false, fmt.Errorf("failed to add headline: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add headline: %w", err)
+	}
+	return n > 0, nil
+}
+
+// HasHeadline reports whether a feed item URL has already been recorded.
+func (db *DB) HasHeadline(ctx context.Context, url string) (bool, error) {
+	var exists bool
+	err := db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM headlines WHERE url = ?)`, url).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check headline: %w", err)
+	}
+	return exists, nil
+}
+
+// GetLastDigest returns when the session last ran /digest, or the zero time.
+func (db *DB) GetLastDigest(ctx context.Context, sessionID string) (time.Time, error) {
+	var t time.Time
+	err := db.QueryRowContext(ctx, `SELECT last_run FROM digest_runs WHERE session_id = ?`, sessionID).Scan(&t)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get last digest: %w", err)
+	}
+	return t, nil
+}
+
+// SetLastDigest records when the session last ran /digest.
+func (db *DB) SetLastDigest(ctx context.Context, sessionID string, at time.Time) error {
+	query := `
+		INSERT INTO digest_runs (session_id, last_run) VALUES (?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET last_run = excluded.last_run
+	`
+	if _, err := db.ExecContext(ctx, query, sessionID, at.UTC()); err != nil {
+		return fmt.Errorf("failed to set last digest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHeadlinesAndDigestRuns(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if seen, _ := db.HasHeadline(ctx, "https://go.dev/blog/go1.26"); seen {
+		t.Error("expected unseen headline")
+	}
+	added, err := db.AddHeadline(ctx, "https://go.dev/blog/go1.26", "Go 1.26")
+	if err != nil || !added {
+		t.Fatalf("AddHeadline failed: added=%v err=%v", added, err)
+	}
+	if added, _ := db.AddHeadline(ctx, "https://go.dev/blog/go1.26", "Go 1.26"); added {
+		t.Error("expected duplicate headline to be ignored")
+	}
+	if seen, _ := db.HasHeadline(ctx, "https://go.dev/blog/go1.26"); !seen {
+		t.Error("expected headline to be seen")
+	}
+
+	last, err := db.GetLastDigest(ctx, "session-a")
+	if err != nil || !last.IsZero() {
+		t.Fatalf("expected no last digest, got %v err=%v", last, err)
+	}
+	at := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	if err := db.SetLastDigest(ctx, "session-a", at); err != nil {
+		t.Fatalf("SetLastDigest failed: %v", err)
+	}
+	if err := db.SetLastDigest(ctx, "session-a", at.Add(time.Hour)); err != nil {
+		t.Fatalf("SetLastDigest update failed: %v", err)
+	}
+	last, _ = db.GetLastDigest(ctx, "session-a")
+	if !last.Equal(at.Add(time.Hour)) {
+		t.Errorf("expected last digest %v, got %v", at.Add(time.Hour), last)
+	}
+}
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Embedding is a vector stored under a namespace (e.g. "memories") and a key
+// unique within it, with free-form metadata.
+type Embedding struct {
+	Namespace string
+	Key       string
+	Vector    []float32
+	Metadata  map[string]string
+	UpdatedAt time.Time
+}
+
+// EmbeddingMatch is an embedding returned by similarity search.
+type EmbeddingMatch struct {
+	Embedding
+	Score float64 // cosine similarity, -1 to 1
+}
+
+// UpsertEmbeddings stores a batch of embeddings in one transaction,
+// replacing any with the same namespace and key.
+func (db *DB) UpsertEmbeddings(ctx context.Context, embeddings []Embedding) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin embeddings upsert: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := db.dialect.rebind(`
+		INSERT INTO embeddings (namespace, key, vector, dims, metadata, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(namespace, key) DO UPDATE SET
+			vector = excluded.vector,
+			dims = excluded.dims,
+			metadata = excluded.metadata,
+			updated_at = CURRENT_TIMESTAMP
+	`)
+	for _, e := range embeddings {
+		if e.Namespace == "" || e.Key == "" || len(e.Vector) == 0 {
+			return fmt.Errorf("failed to upsert embedding %q: namespace, key and vector are required", e.Key)
+		}
+		meta, err := json.Marshal(e.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode metadata for %q: %w", e.Key, err)
+		}
+		if _, err := tx.ExecContext(ctx, query, e.Namespace, e.Key, encodeVector(e.Vector), len(e.Vector), string(meta)); err != nil {
+			return fmt.Errorf("failed to upsert embedding %q: %w", e.Key, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit embeddings upsert: %w", err)
+	}
+	return nil
+}
+
+// DeleteEmbeddings removes embeddings by key and reports how many existed.
+func (db *DB) DeleteEmbeddings(ctx context.Context, namespace string, keys ...string) (int64, error) {
+	var deleted int64
+	for _, key := range keys {
+		res, err := db.ExecContext(ctx, `DELETE FROM embeddings WHERE namespace = ? AND key = ?`, namespace, key)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete embedding %q: %w", key, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete embedding %q: %w", key, err)
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// SearchEmbeddings returns the namespace's embeddings most similar to query
+// by cosine similarity, best first. Vectors of a different dimension are
+// skipped. The scan is brute force, which is fine for personal-scale data.
+func (db *DB) SearchEmbeddings(ctx context.Context, namespace string, query []float32, limit int) ([]EmbeddingMatch, error) {
+	if len(query) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 5
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT key, vector, metadata, updated_at FROM embeddings
+		WHERE namespace = ? AND dims = ?
+	`, namespace, len(query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search embeddings: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	queryNorm := norm(query)
+	var matches []EmbeddingMatch
+	for rows.Next() {
+		var (
+			m    EmbeddingMatch
+			blob []byte
+			meta string
+		)
+		if err := rows.Scan(&m.Key, &blob, &meta, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan embedding: %w", err)
+		}
+		m.Namespace = namespace
+		m.Vector = decodeVector(blob)
+		if err := json.Unmarshal([]byte(meta), &m.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode metadata for %q: %w", m.Key, err)
+		}
+		m.Score = cosine(query, queryNorm, m.Vector)
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// encodeVector packs a vector as little-endian float32s.
+func encodeVector(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) []float32 {
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return v
+}
+
+func norm(v []float32) float64 {
+	var sum float64
+	for _, f := range v {
+		sum += float64(f) * float64(f)
+	}
+	return math.Sqrt(sum)
+}
+
+// cosine returns the cosine similarity of a (with precomputed norm) and b,
+// or 0 if either is a zero vector.
+func cosine(a []float32, aNorm float64, b []float32) float64 {
+	bNorm := norm(b)
+	if aNorm == 0 || bNorm == 0 {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot / (aNorm * bNorm)
+}
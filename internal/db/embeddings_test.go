@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestEmbeddings(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	err := db.UpsertEmbeddings(ctx, []Embedding{
+		{Namespace: "memories", Key: "1", Vector: []float32{1, 0, 0}, Metadata: map[string]string{"text": "likes Go"}},
+		{Namespace: "memories", Key: "2", Vector: []float32{0, 1, 0}},
+		{Namespace: "memories", Key: "3", Vector: []float32{0.7, 0.7, 0}},
+		{Namespace: "memories", Key: "short", Vector: []float32{1, 0}},
+		{Namespace: "briefings", Key: "1", Vector: []float32{1, 0, 0}},
+	})
+	if err != nil {
+		t.Fatalf("UpsertEmbeddings failed: %v", err)
+	}
+
+	matches, err := db.SearchEmbeddings(ctx, "memories", []float32{1, 0.1, 0}, 2)
+	if err != nil {
+		t.Fatalf("SearchEmbeddings failed: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Key != "1" || matches[1].Key != "3" {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+	if matches[0].Metadata["text"] != "likes Go" || matches[0].Namespace != "memories" {
+		t.Errorf("unexpected metadata: %+v", matches[0])
+	}
+	if matches[0].Score < 0.99 || matches[0].Score > 1 {
+		t.Errorf("unexpected score %v", matches[0].Score)
+	}
+
+	// Upserting replaces the vector.
+	if err := db.UpsertEmbeddings(ctx, []Embedding{{Namespace: "memories", Key: "2", Vector: []float32{1, 0.1, 0}}}); err != nil {
+		t.Fatalf("UpsertEmbeddings failed: %v", err)
+	}
+	matches, _ = db.SearchEmbeddings(ctx, "memories", []float32{1, 0.1, 0}, 1)
+	if len(matches) != 1 || matches[0].Key != "2" || math.Abs(matches[0].Score-1) > 1e-6 {
+		t.Errorf("expected updated vector to match best, got %+v", matches)
+	}
+
+	if err := db.UpsertEmbeddings(ctx, []Embedding{{Namespace: "memories", Key: "bad"}}); err == nil {
+		t.Error("expected error for an empty vector")
+	}
+
+	n, err := db.DeleteEmbeddings(ctx, "memories", "1", "2", "missing")
+	if err != nil || n != 2 {
+		t.Fatalf("DeleteEmbeddings = %d, %v; want 2", n, err)
+	}
+	matches, _ = db.SearchEmbeddings(ctx, "memories", []float32{1, 0, 0}, 10)
+	if len(matches) != 1 || matches[0].Key != "3" {
+		t.Errorf("unexpected matches after delete: %+v", matches)
+	}
+}
+
+func TestVectorEncoding(t *testing.T) {
+	t.Parallel()
+	v := []float32{0, -1.5, 3.25, float32(math.Pi)}
+	got := decodeVector(encodeVector(v))
+	if len(got) != len(v) {
+		t.Fatalf("decoded %d values, want %d", len(got), len(v))
+	}
+	for i := range v {
+		if got[i] != v[i] {
+			t.Errorf("value %d = %v, want %v", i, got[i], v[i])
+		}
+	}
+	if s := cosine([]float32{0, 0}, 0, []float32{1, 1}); s != 0 {
+		t.Errorf("expected zero similarity for a zero vector, got %v", s)
+	}
+}
@@ -0,0 +1,18 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// IndexSessionEvents adds an index for loading a conversation's recent
+// events to the ADK events table, whose primary key starts with the event
+// ID and so cannot serve lookups by session. Call it after the session
+// service has migrated its tables.
+func (db *DB) IndexSessionEvents(ctx context.Context) error {
+	query := `CREATE INDEX IF NOT EXISTS idx_events_session_time ON events(app_name, user_id, session_id, timestamp)`
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to index session events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Feedback is a message left with /feedback.
+type Feedback struct {
+	ID        int64
+	SessionID string
+	Content   string
+	IssueURL  string // GitHub issue opened for it, if any
+	CreatedAt time.Time
+}
+
+// AddFeedback stores feedback from a session and returns its ID.
+func (db *DB) AddFeedback(ctx context.Context, sessionID, content string) (int64, error) {
+	id, err := db.insert(ctx, `INSERT INTO feedback (session_id, content) VALUES (?, ?)`, sessionID, content)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add feedback: %w", err)
+	}
+	return id, nil
+}
+
+// SetFeedbackIssue records the GitHub issue opened for a piece of feedback.
+func (db *DB) SetFeedbackIssue(ctx context.Context, id int64, issueURL string) error {
+	if _, err := db.ExecContext(ctx, `UPDATE feedback SET issue_url = ? WHERE id = ?`, issueURL, id); err != nil {
+		return fmt.Errorf("failed to set feedback issue: %w", err)
+	}
+	return nil
+}
+
+// ListFeedback returns feedback from all sessions created at or after since,
+// oldest first.
+func (db *DB) ListFeedback(ctx context.Context, since time.Time) ([]Feedback, error) {
+	query := `
+		SELECT id, session_id, content, issue_url, created_at FROM feedback
+		WHERE created_at >= ? ORDER BY id ASC
+	`
+	rows, err := db.QueryContext(ctx, query, since.UTC().Format(time.DateTime))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Feedback
+	for rows.Next() {
+		var f Feedback
+		if err := rows.Scan(&f.ID, &f.SessionID, &f.Content, &f.IssueURL, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan feedback: %w", err)
+		}
+		out = append(out, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFeedback(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	id, err := db.AddFeedback(ctx, "session-a", "The digest is great")
+	if err != nil {
+		t.Fatalf("AddFeedback failed: %v", err)
+	}
+	if _, err := db.AddFeedback(ctx, "session-b", "Reminders fire late"); err != nil {
+		t.Fatalf("AddFeedback failed: %v", err)
+	}
+	if err := db.SetFeedbackIssue(ctx, id, "https://github.com/o/r/issues/1"); err != nil {
+		t.Fatalf("SetFeedbackIssue failed: %v", err)
+	}
+
+	all, err := db.ListFeedback(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListFeedback failed: %v", err)
+	}
+	if len(all) != 2 || all[0].Content != "The digest is great" || all[0].IssueURL != "https://github.com/o/r/issues/1" {
+		t.Fatalf("unexpected feedback: %+v", all)
+	}
+	if all[1].SessionID != "session-b" || all[1].IssueURL != "" {
+		t.Errorf("unexpected second entry: %+v", all[1])
+	}
+
+	if recent, _ := db.ListFeedback(ctx, time.Now().Add(time.Hour)); len(recent) != 0 {
+		t.Errorf("expected no feedback in the future window, got %+v", recent)
+	}
+}
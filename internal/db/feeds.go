@@ -0,0 +1,281 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultFeedInterval is how often a feed is fetched unless it has its
+	// own interval.
+	DefaultFeedInterval = 30 * time.Minute
+	// maxFeedBackoff caps how far consecutive failures push back a feed's
+	// next fetch.
+	maxFeedBackoff = 24 * time.Hour
+	// feedItemRetention is how long fetched items are kept.
+	feedItemRetention = 30 * 24 * time.Hour
+)
+
+// Feed is an RSS/Atom feed in the registry: the fetch state and health
+// shared by every session subscribed to it.
+type Feed struct {
+	ID           int64
+	URL          string
+	Title        string
+	Interval     time.Duration // 0 for DefaultFeedInterval
+	ETag         string
+	LastModified string
+	LastFetched  *time.Time
+	LastError    string // of the last fetch; empty if it succeeded
+	Failures     int    // consecutive failed fetches
+	Subscribers  int
+}
+
+// NextFetch returns when the feed is next due: its interval after the last
+// fetch, doubled for each consecutive failure (up to a day).
+func (f Feed) NextFetch() time.Time {
+	if f.LastFetched == nil {
+		return time.Time{}
+	}
+	interval := f.Interval
+	if interval <= 0 {
+		interval = DefaultFeedInterval
+	}
+	for range min(f.Failures, 10) {
+		interval *= 2
+	}
+	return f.LastFetched.Add(min(interval, max(maxFeedBackoff, f.Interval)))
+}
+
+// Due reports whether the feed should be fetched at now.
+func (f Feed) Due(now time.Time) bool {
+	return !now.Before(f.NextFetch())
+}
+
+// FeedItem is an entry stored from a feed.
+type FeedItem struct {
+	FeedURL     string
+	FeedTitle   string
+	Title       string
+	Link        string
+	Description string
+	Published   time.Time // zero if the feed gave no date
+	FirstSeen   time.Time
+}
+
+// FeedFetch is the outcome of fetching a feed successfully.
+type FeedFetch struct {
+	Title        string
+	ETag         string
+	LastModified string
+	NotModified  bool // the feed answered 304, so Items is empty
+	Items        []FeedItem
+}
+
+const feedColumns = `f.id, f.url, f.title, f.interval_minutes, f.etag, f.last_modified, f.last_fetched, f.last_error, f.failures,
+	(SELECT COUNT(DISTINCT s.session_id) FROM subscriptions s WHERE s.url = f.url)`
+
+// RegisterFeeds adds feeds to the registry, skipping those already in it.
+func (db *DB) RegisterFeeds(ctx context.Context, urls ...string) error {
+	for _, u := range urls {
+		if _, err := db.ExecContext(ctx, `INSERT INTO feeds (url) VALUES (?) ON CONFLICT(url) DO NOTHING`, u); err != nil {
+			return fmt.Errorf("failed to register feed: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetFeeds returns the registry entries for urls, registering any that are
+// missing, in the order given.
+func (db *DB) GetFeeds(ctx context.Context, urls []string) ([]Feed, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+	if err := db.RegisterFeeds(ctx, urls...); err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`SELECT %s FROM feeds f WHERE f.url IN (%s)`, feedColumns, placeholderList(len(urls)))
+	feeds, err := db.queryFeeds(ctx, query, anySlice(urls)...)
+	if err != nil {
+		return nil, err
+	}
+	byURL := make(map[string]Feed, len(feeds))
+	for _, f := range feeds {
+		byURL[f.URL] = f
+	}
+	out := make([]Feed, 0, len(urls))
+	for _, u := range urls {
+		if f, ok := byURL[u]; ok {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// ListFeeds returns the registry entries of every subscribed feed, by URL.
+func (db *DB) ListFeeds(ctx context.Context) ([]Feed, error) {
+	urls, err := db.GetFeedURLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return db.GetFeeds(ctx, urls)
+}
+
+// SetFeedInterval sets how often the feed at url is fetched, 0 restoring
+// the default. It reports whether the feed is in the registry.
+func (db *DB) SetFeedInterval(ctx context.Context, url string, interval time.Duration) (bool, error) {
+	res, err := db.ExecContext(ctx, `UPDATE feeds SET interval_minutes = ? WHERE url = ?`, int(interval/time.Minute), url)
+	if err != nil {
+		return false, fmt.Errorf("failed to set feed interval: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set feed interval: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SetFeedTitle records a title for the feed at url, such as one from an
+// imported subscription list, unless its fetches have already set one.
+func (db *DB) SetFeedTitle(ctx context.Context, url, title string) error {
+	if _, err := db.ExecContext(ctx, `UPDATE feeds SET title = ? WHERE url = ? AND title = ''`, title, url); err != nil {
+		return fmt.Errorf("failed to set feed title: %w", err)
+	}
+	return nil
+}
+
+// RecordFeedFetch stores a successful fetch of the feed id: its validators,
+// and its items, of which those already stored keep their first-seen time.
+// It clears the feed's error and prunes items older than a month.
+func (db *DB) RecordFeedFetch(ctx context.Context, id int64, fetch FeedFetch, at time.Time) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to record feed fetch: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	seen := at.UTC().Format(time.DateTime)
+	update := `UPDATE feeds SET etag = ?, last_modified = ?, last_fetched = ?, last_error = '', failures = 0 WHERE id = ?`
+	args := []any{fetch.ETag, fetch.LastModified, seen, id}
+	if fetch.Title != "" {
+		update = `UPDATE feeds SET title = ?, etag = ?, last_modified = ?, last_fetched = ?, last_error = '', failures = 0 WHERE id = ?`
+		args = append([]any{fetch.Title}, args...)
+	}
+	if _, err := tx.ExecContext(ctx, db.dialect.rebind(update), args...); err != nil {
+		return fmt.Errorf("failed to record feed fetch: %w", err)
+	}
+	insert := db.dialect.rebind(`
+		INSERT INTO feed_items (feed_id, item_key, title, link, description, published, first_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(feed_id, item_key) DO UPDATE SET
+			title = excluded.title, description = excluded.description, published = excluded.published
+	`)
+	for _, it := range fetch.Items {
+		key := it.Link
+		if key == "" {
+			key = "title:" + strings.ToLower(strings.Join(strings.Fields(it.Title), " "))
+		}
+		var published any
+		if !it.Published.IsZero() {
+			published = it.Published.UTC().Format(time.DateTime)
+		}
+		if _, err := tx.ExecContext(ctx, insert, id, key, it.Title, it.Link, it.Description, published, seen); err != nil {
+			return fmt.Errorf("failed to store feed item: %w", err)
+		}
+	}
+	cutoff := at.Add(-feedItemRetention).UTC().Format(time.DateTime)
+	if _, err := tx.ExecContext(ctx, db.dialect.rebind(`DELETE FROM feed_items WHERE feed_id = ? AND first_seen < ?`), id, cutoff); err != nil {
+		return fmt.Errorf("failed to prune feed items: %w", err)
+	}
+	return tx.Commit()
+}
+
+// RecordFeedError records a failed fetch of the feed id, which backs off
+// its next fetch.
+func (db *DB) RecordFeedError(ctx context.Context, id int64, fetchErr error, at time.Time) error {
+	query := `UPDATE feeds SET last_fetched = ?, last_error = ?, failures = failures + 1 WHERE id = ?`
+	if _, err := db.ExecContext(ctx, query, at.UTC().Format(time.DateTime), fetchErr.Error(), id); err != nil {
+		return fmt.Errorf("failed to record feed error: %w", err)
+	}
+	return nil
+}
+
+// FeedItemsSince returns the stored items of the feeds at urls published
+// after since (or, without a date, first seen after it), newest first.
+func (db *DB) FeedItemsSince(ctx context.Context, urls []string, since time.Time, limit int) ([]FeedItem, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+	query := fmt.Sprintf(`
+		SELECT f.url, f.title, i.title, i.link, i.description, i.published, i.first_seen
+		FROM feed_items i JOIN feeds f ON f.id = i.feed_id
+		WHERE f.url IN (%s) AND COALESCE(i.published, i.first_seen) > ?
+		ORDER BY COALESCE(i.published, i.first_seen) DESC, i.id DESC LIMIT ?
+	`, placeholderList(len(urls)))
+	args := append(anySlice(urls), since.UTC().Format(time.DateTime), limit)
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed items: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []FeedItem
+	for rows.Next() {
+		var it FeedItem
+		var published sql.NullTime
+		if err := rows.Scan(&it.FeedURL, &it.FeedTitle, &it.Title, &it.Link, &it.Description, &published, &it.FirstSeen); err != nil {
+			return nil, fmt.Errorf("failed to scan feed item: %w", err)
+		}
+		if published.Valid {
+			it.Published = published.Time
+		}
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return items, nil
+}
+
+func (db *DB) queryFeeds(ctx context.Context, query string, args ...any) ([]Feed, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feeds: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var feeds []Feed
+	for rows.Next() {
+		var f Feed
+		var minutes int
+		var fetched sql.NullTime
+		if err := rows.Scan(&f.ID, &f.URL, &f.Title, &minutes, &f.ETag, &f.LastModified, &fetched, &f.LastError, &f.Failures, &f.Subscribers); err != nil {
+			return nil, fmt.Errorf("failed to scan feed: %w", err)
+		}
+		f.Interval = time.Duration(minutes) * time.Minute
+		if fetched.Valid {
+			f.LastFetched = &fetched.Time
+		}
+		feeds = append(feeds, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return feeds, nil
+}
+
+// placeholderList returns n comma-separated placeholders for an IN list.
+func placeholderList(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func anySlice(ss []string) []any {
+	out := make([]any, len(ss))
+	for i, s := range ss {
+		out[i] = s
+	}
+	return out
+}
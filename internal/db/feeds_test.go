@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFeedRegistry(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	_, _ = db.AddSubscription(ctx, "session-a", "https://go.dev/blog/feed.atom")
+	_, _ = db.AddSubscription(ctx, "session-b", "https://go.dev/blog/feed.atom")
+	feeds, err := db.ListFeeds(ctx)
+	if err != nil || len(feeds) != 1 {
+		t.Fatalf("ListFeeds = %+v, %v", feeds, err)
+	}
+	f := feeds[0]
+	if f.Subscribers != 2 || f.LastFetched != nil || !f.Due(now) {
+		t.Errorf("unexpected new feed %+v", f)
+	}
+
+	// Feeds missing from the registry, like imported subscriptions, are
+	// registered on first use.
+	feeds, err = db.GetFeeds(ctx, []string{"https://blog.rust-lang.org/feed.xml", f.URL})
+	if err != nil || len(feeds) != 2 || feeds[0].URL != "https://blog.rust-lang.org/feed.xml" || feeds[0].Subscribers != 0 {
+		t.Fatalf("GetFeeds = %+v, %v", feeds, err)
+	}
+
+	fetch := FeedFetch{Title: "The Go Blog", ETag: `"v1"`, Items: []FeedItem{
+		{Title: "Go 1.26 released", Link: "https://go.dev/blog/go1.26", Published: now.Add(-time.Hour)},
+		{Title: "Undated", Description: "no link or date"},
+		{Title: "Go 1.0", Link: "https://go.dev/blog/go1", Published: now.Add(-72 * time.Hour)},
+	}}
+	if err := db.RecordFeedFetch(ctx, f.ID, fetch, now); err != nil {
+		t.Fatalf("RecordFeedFetch failed: %v", err)
+	}
+	// A second fetch with the same items keeps them once.
+	if err := db.RecordFeedFetch(ctx, f.ID, fetch, now.Add(time.Minute)); err != nil {
+		t.Fatalf("RecordFeedFetch failed: %v", err)
+	}
+	items, err := db.FeedItemsSince(ctx, []string{f.URL}, now.Add(-24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("FeedItemsSince failed: %v", err)
+	}
+	if len(items) != 2 || items[0].Title != "Undated" || items[1].Title != "Go 1.26 released" || items[1].FeedTitle != "The Go Blog" {
+		t.Fatalf("expected the undated then the recent item, got %+v", items)
+	}
+	if !items[0].Published.IsZero() || !items[0].FirstSeen.Equal(now) {
+		t.Errorf("undated item should keep its first-seen time, got %+v", items[0])
+	}
+
+	feeds, _ = db.GetFeeds(ctx, []string{f.URL})
+	f = feeds[0]
+	if f.Title != "The Go Blog" || f.ETag != `"v1"` || f.LastFetched == nil || f.Due(now.Add(10*time.Minute)) || !f.Due(now.Add(31*time.Minute)) {
+		t.Errorf("unexpected fetched feed %+v", f)
+	}
+
+	// Failures back off the next fetch.
+	for range 2 {
+		if err := db.RecordFeedError(ctx, f.ID, errors.New("feed returned status 503"), now); err != nil {
+			t.Fatalf("RecordFeedError failed: %v", err)
+		}
+	}
+	feeds, _ = db.GetFeeds(ctx, []string{f.URL})
+	f = feeds[0]
+	if f.Failures != 2 || f.LastError != "feed returned status 503" || f.Due(now.Add(time.Hour)) || !f.Due(now.Add(2*time.Hour)) {
+		t.Errorf("unexpected failing feed %+v", f)
+	}
+
+	if ok, err := db.SetFeedInterval(ctx, f.URL, 2*time.Hour); err != nil || !ok {
+		t.Fatalf("SetFeedInterval = %v, %v", ok, err)
+	}
+	if err := db.RecordFeedFetch(ctx, f.ID, FeedFetch{NotModified: true, ETag: `"v1"`}, now); err != nil {
+		t.Fatalf("RecordFeedFetch failed: %v", err)
+	}
+	feeds, _ = db.GetFeeds(ctx, []string{f.URL})
+	if f = feeds[0]; f.Interval != 2*time.Hour || f.Failures != 0 || f.Title != "The Go Blog" || f.Due(now.Add(time.Hour)) {
+		t.Errorf("unexpected feed after interval change %+v", f)
+	}
+	if ok, _ := db.SetFeedInterval(ctx, "https://nowhere.example/feed", time.Hour); ok {
+		t.Error("expected no feed to update")
+	}
+}
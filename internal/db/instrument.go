@@ -0,0 +1,145 @@
+package db
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultSlowQuery is the slow-query threshold used when none is configured.
+const DefaultSlowQuery = 250 * time.Millisecond
+
+// QueryRecorder receives the duration of each database query, grouped by
+// kind (e.g. "SELECT reminders" or "gorm query events"). *stats.Stats
+// implements it.
+type QueryRecorder interface {
+	RecordQuery(name string, d time.Duration, slow bool)
+}
+
+// Instrument times every query issued through db and through the GORM
+// session service (when opened with GormOption), reporting each to rec and
+// logging those that take at least slow. A zero slow uses DefaultSlowQuery;
+// a negative one disables the log. Queries inside transactions are not
+// timed. Call it before the database is used concurrently.
+func (db *DB) Instrument(rec QueryRecorder, slow time.Duration) {
+	if slow == 0 {
+		slow = DefaultSlowQuery
+	}
+	db.recorder = rec
+	db.slowQuery = slow
+}
+
+// observe records a query that started at start.
+func (db *DB) observe(query string, start time.Time) {
+	if db.recorder == nil && db.slowQuery <= 0 {
+		return
+	}
+	db.record(queryName(query), query, time.Since(start))
+}
+
+func (db *DB) record(name, query string, d time.Duration) {
+	slow := db.slowQuery > 0 && d >= db.slowQuery
+	if slow {
+		slog.Warn("Slow database query", "query", name, "duration", d.Round(time.Millisecond), "sql", compactSQL(query, 300))
+	}
+	if db.recorder != nil {
+		db.recorder.RecordQuery(name, d, slow)
+	}
+}
+
+// queryName groups a statement by its verb and main table, such as
+// "SELECT briefings" or "INSERT reminders".
+func queryName(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	verb := strings.ToUpper(fields[0])
+	var after string
+	switch verb {
+	case "SELECT", "DELETE":
+		after = "FROM"
+	case "INSERT":
+		after = "INTO"
+	case "UPDATE":
+		return verb + " " + tableName(fields, 1)
+	default:
+		return verb
+	}
+	for i, f := range fields {
+		if strings.EqualFold(f, after) {
+			return verb + " " + tableName(fields, i+1)
+		}
+	}
+	return verb
+}
+
+func tableName(fields []string, i int) string {
+	if i >= len(fields) {
+		return "?"
+	}
+	name, _, _ := strings.Cut(fields[i], "(")
+	return strings.Trim(name, "\"`);,")
+}
+
+// compactSQL collapses whitespace and truncates a statement for logging.
+func compactSQL(query string, limit int) string {
+	s := strings.Join(strings.Fields(query), " ")
+	if len(s) > limit {
+		s = s[:limit] + "…"
+	}
+	return s
+}
+
+// GormOption returns a gorm.Option that times the queries of a GORM
+// connection (the ADK session service) like db's own; see Instrument.
+func (db *DB) GormOption() gorm.Option {
+	return &gormTimer{db: db}
+}
+
+type gormTimer struct{ db *DB }
+
+const gormStartKey = "ravenbot:query_start"
+
+func (g *gormTimer) Apply(*gorm.Config) error { return nil }
+
+func (g *gormTimer) AfterInitialize(gdb *gorm.DB) error {
+	cb := gdb.Callback()
+	processors := []struct {
+		op            string
+		before, after func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("*").Register, cb.Create().After("*").Register},
+		{"query", cb.Query().Before("*").Register, cb.Query().After("*").Register},
+		{"update", cb.Update().Before("*").Register, cb.Update().After("*").Register},
+		{"delete", cb.Delete().Before("*").Register, cb.Delete().After("*").Register},
+		{"row", cb.Row().Before("*").Register, cb.Row().After("*").Register},
+		{"raw", cb.Raw().Before("*").Register, cb.Raw().After("*").Register},
+	}
+	for _, p := range processors {
+		if err := p.before("ravenbot:timer_start_"+p.op, g.start); err != nil {
+			return err
+		}
+		op := p.op
+		if err := p.after("ravenbot:timer_end_"+op, func(tx *gorm.DB) { g.end(tx, op) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *gormTimer) start(tx *gorm.DB) {
+	tx.InstanceSet(gormStartKey, time.Now())
+}
+
+func (g *gormTimer) end(tx *gorm.DB, op string) {
+	v, _ := tx.InstanceGet(gormStartKey)
+	start, ok := v.(time.Time)
+	if !ok || (g.db.recorder == nil && g.db.slowQuery <= 0) {
+		return
+	}
+	name := strings.TrimSpace("gorm " + op + " " + tx.Statement.Table)
+	g.db.record(name, tx.Statement.SQL.String(), time.Since(start))
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+type recordedQuery struct {
+	name string
+	slow bool
+}
+
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries []recordedQuery
+}
+
+func (r *queryRecorder) RecordQuery(name string, _ time.Duration, slow bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, recordedQuery{name, slow})
+}
+
+func (r *queryRecorder) names() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]int)
+	for _, q := range r.queries {
+		out[q.name]++
+	}
+	return out
+}
+
+func TestQueryName(t *testing.T) {
+	t.Parallel()
+	tests := []struct{ query, want string }{
+		{"SELECT id, content FROM memories WHERE session_id = ?", "SELECT memories"},
+		{"\n\t\tINSERT INTO reminders (session_id) VALUES (?)", "INSERT reminders"},
+		{"UPDATE tasks SET done = 1", "UPDATE tasks"},
+		{"DELETE FROM subscriptions WHERE id = ?", "DELETE subscriptions"},
+		{`SELECT t.id FROM briefings_fts JOIN briefings t ON t.id = briefings_fts.rowid`, "SELECT briefings_fts"},
+		{"select exists(select 1 from sqlite_master)", "SELECT sqlite_master"},
+		{"PRAGMA table_info(tasks)", "PRAGMA"},
+		{"", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := queryName(tt.query); got != tt.want {
+			t.Errorf("queryName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	rec := &queryRecorder{}
+	db.Instrument(rec, time.Nanosecond) // every query counts as slow
+
+	if _, err := db.AddMemory(ctx, "s1", "Prefers tabs"); err != nil {
+		t.Fatalf("AddMemory failed: %v", err)
+	}
+	if _, err := db.SearchMemories(ctx, "s1", "tabs", 5); err != nil {
+		t.Fatalf("SearchMemories failed: %v", err)
+	}
+	if _, err := db.GetSessionSummary(ctx, "s1"); err != nil {
+		t.Fatalf("GetSessionSummary failed: %v", err)
+	}
+
+	gdb, err := gorm.Open(&sqlite.Dialector{Conn: db.DB}, db.GormOption())
+	if err != nil {
+		t.Fatalf("gorm.Open failed: %v", err)
+	}
+	var count int64
+	if err := gdb.Table("memories").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("gorm count = %d, err=%v", count, err)
+	}
+
+	names := rec.names()
+	for _, want := range []string{"INSERT memories", "SELECT memories", "SELECT session_summaries", "gorm query memories"} {
+		if names[want] != 1 {
+			t.Errorf("expected one %q query, got %v", want, names)
+		}
+	}
+	for _, q := range rec.queries {
+		if !q.slow {
+			t.Errorf("expected %q to be flagged slow", q.name)
+		}
+	}
+}
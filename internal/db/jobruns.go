@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Job run triggers.
+const (
+	TriggerScheduled  = "scheduled"
+	TriggerManual     = "manual"
+	TriggerCatchUp    = "catchup"    // a scheduled run made up on startup
+	TriggerDependency = "dependency" // run after the jobs in its runAfter
+)
+
+// Job run statuses.
+const (
+	JobRunning   = "running"
+	JobSucceeded = "success"
+	JobFailed    = "failed"
+)
+
+// JobRun is one execution of a configured job.
+type JobRun struct {
+	ID          int64
+	JobName     string
+	JobType     string
+	TriggeredBy string // TriggerScheduled, TriggerManual, TriggerCatchUp or TriggerDependency
+	Status      string // JobRunning, JobSucceeded or JobFailed
+	ReportPath  string
+	Error       string
+	Tokens      int64
+	StartedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+// Duration returns how long the run took, or 0 while it is running.
+func (r JobRun) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// StartJobRun records that a job started and returns the run ID.
+func (db *DB) StartJobRun(ctx context.Context, name, jobType, triggeredBy string) (int64, error) {
+	query := `INSERT INTO job_runs (job_name, job_type, triggered_by, status, started_at) VALUES (?, ?, ?, ?, ?)`
+	id, err := db.insert(ctx, query, name, jobType, triggeredBy, JobRunning, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to start job run: %w", err)
+	}
+	return id, nil
+}
+
+// FinishJobRun records the outcome of a run.
+func (db *DB) FinishJobRun(ctx context.Context, id int64, status, reportPath, errMsg string, tokens int64) error {
+	query := `
+		UPDATE job_runs SET status = ?, report_path = ?, error = ?, tokens = ?, finished_at = ?
+		WHERE id = ?
+	`
+	if _, err := db.ExecContext(ctx, query, status, reportPath, errMsg, tokens, time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("failed to finish job run: %w", err)
+	}
+	return nil
+}
+
+const jobRunColumns = `id, job_name, job_type, triggered_by, status, report_path, error, tokens, started_at, finished_at`
+
+// ListJobRuns returns a job's most recent runs, newest first. The name is
+// matched case-insensitively.
+func (db *DB) ListJobRuns(ctx context.Context, name string, limit int) ([]JobRun, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	query := `SELECT ` + jobRunColumns + ` FROM job_runs WHERE LOWER(job_name) = LOWER(?) ORDER BY id DESC LIMIT ?`
+	return db.queryJobRuns(ctx, query, name, limit)
+}
+
+// LatestJobRuns returns the most recent run of every job, by job name.
+func (db *DB) LatestJobRuns(ctx context.Context) (map[string]JobRun, error) {
+	query := `SELECT ` + jobRunColumns + ` FROM job_runs WHERE id IN (SELECT MAX(id) FROM job_runs GROUP BY job_name)`
+	runs, err := db.queryJobRuns(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]JobRun, len(runs))
+	for _, r := range runs {
+		latest[r.JobName] = r
+	}
+	return latest, nil
+}
+
+// LastSuccessfulJobRuns returns the most recent successful run of every
+// job, by job name.
+func (db *DB) LastSuccessfulJobRuns(ctx context.Context) (map[string]JobRun, error) {
+	query := `SELECT ` + jobRunColumns + ` FROM job_runs WHERE id IN (SELECT MAX(id) FROM job_runs WHERE status = ? GROUP BY job_name)`
+	runs, err := db.queryJobRuns(ctx, query, JobSucceeded)
+	if err != nil {
+		return nil, err
+	}
+	last := make(map[string]JobRun, len(runs))
+	for _, r := range runs {
+		last[r.JobName] = r
+	}
+	return last, nil
+}
+
+func (db *DB) queryJobRuns(ctx context.Context, query string, args ...any) ([]JobRun, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []JobRun
+	for rows.Next() {
+		var r JobRun
+		var finished sql.NullTime
+		if err := rows.Scan(&r.ID, &r.JobName, &r.JobType, &r.TriggeredBy, &r.Status, &r.ReportPath, &r.Error, &r.Tokens, &r.StartedAt, &finished); err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		if finished.Valid {
+			r.FinishedAt = &finished.Time
+		}
+		runs = append(runs, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return runs, nil
+}
+
+// PauseJob stops a job's scheduled runs until ResumeJob. It reports
+// whether the job was running on schedule before.
+func (db *DB) PauseJob(ctx context.Context, name string) (bool, error) {
+	res, err := db.ExecContext(ctx, `INSERT INTO paused_jobs (job_name, paused_at) VALUES (?, ?) ON CONFLICT(job_name) DO NOTHING`, name, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to pause job: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to pause job: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ResumeJob restarts a paused job's scheduled runs. It reports whether the
+// job was paused.
+func (db *DB) ResumeJob(ctx context.Context, name string) (bool, error) {
+	res, err := db.ExecContext(ctx, `DELETE FROM paused_jobs WHERE job_name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to resume job: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to resume job: %w", err)
+	}
+	return n > 0, nil
+}
+
+// PausedJobs returns when each paused job was paused, by job name.
+func (db *DB) PausedJobs(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := db.QueryContext(ctx, `SELECT job_name, paused_at FROM paused_jobs`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paused jobs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	paused := make(map[string]time.Time)
+	for rows.Next() {
+		var name string
+		var at time.Time
+		if err := rows.Scan(&name, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan paused job: %w", err)
+		}
+		paused[name] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return paused, nil
+}
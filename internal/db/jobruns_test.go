@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJobRuns(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	first, err := db.StartJobRun(ctx, "Daily Briefing", "research", TriggerScheduled)
+	if err != nil {
+		t.Fatalf("StartJobRun failed: %v", err)
+	}
+	if err := db.FinishJobRun(ctx, first, JobFailed, "", "mission timed out", 1200); err != nil {
+		t.Fatalf("FinishJobRun failed: %v", err)
+	}
+	second, _ := db.StartJobRun(ctx, "Daily Briefing", "research", TriggerManual)
+	_ = db.FinishJobRun(ctx, second, JobSucceeded, "daily_logs/report.md", "", 3400)
+	backup, _ := db.StartJobRun(ctx, "Nightly Backup", "backup", TriggerScheduled)
+
+	runs, err := db.ListJobRuns(ctx, "daily briefing", 10)
+	if err != nil {
+		t.Fatalf("ListJobRuns failed: %v", err)
+	}
+	if len(runs) != 2 || runs[0].ID != second || runs[1].ID != first {
+		t.Fatalf("expected both runs newest first, got %+v", runs)
+	}
+	if r := runs[0]; r.Status != JobSucceeded || r.TriggeredBy != TriggerManual || r.ReportPath != "daily_logs/report.md" || r.Tokens != 3400 || r.FinishedAt == nil {
+		t.Errorf("unexpected run: %+v", r)
+	}
+	if r := runs[1]; r.Status != JobFailed || r.Error != "mission timed out" || r.Duration() < 0 {
+		t.Errorf("unexpected run: %+v", r)
+	}
+
+	latest, err := db.LatestJobRuns(ctx)
+	if err != nil {
+		t.Fatalf("LatestJobRuns failed: %v", err)
+	}
+	if len(latest) != 2 || latest["Daily Briefing"].ID != second {
+		t.Fatalf("unexpected latest runs: %+v", latest)
+	}
+	if r := latest["Nightly Backup"]; r.ID != backup || r.Status != JobRunning || r.FinishedAt != nil || r.Duration() != 0 {
+		t.Errorf("expected a running backup, got %+v", r)
+	}
+
+	third, _ := db.StartJobRun(ctx, "Daily Briefing", "research", TriggerCatchUp)
+	_ = db.FinishJobRun(ctx, third, JobFailed, "", "model overloaded", 0)
+	succeeded, err := db.LastSuccessfulJobRuns(ctx)
+	if err != nil {
+		t.Fatalf("LastSuccessfulJobRuns failed: %v", err)
+	}
+	if len(succeeded) != 1 || succeeded["Daily Briefing"].ID != second {
+		t.Errorf("expected only the manual briefing run, got %+v", succeeded)
+	}
+}
+
+func TestPausedJobs(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	paused, err := db.PauseJob(ctx, "Daily Briefing")
+	if err != nil || !paused {
+		t.Fatalf("PauseJob = %v, %v; want true", paused, err)
+	}
+	if paused, _ := db.PauseJob(ctx, "Daily Briefing"); paused {
+		t.Error("expected pausing a paused job to report false")
+	}
+	jobs, err := db.PausedJobs(ctx)
+	if err != nil {
+		t.Fatalf("PausedJobs failed: %v", err)
+	}
+	if at, ok := jobs["Daily Briefing"]; !ok || at.IsZero() || len(jobs) != 1 {
+		t.Errorf("unexpected paused jobs: %v", jobs)
+	}
+
+	resumed, err := db.ResumeJob(ctx, "Daily Briefing")
+	if err != nil || !resumed {
+		t.Fatalf("ResumeJob = %v, %v; want true", resumed, err)
+	}
+	if resumed, _ := db.ResumeJob(ctx, "Daily Briefing"); resumed {
+		t.Error("expected resuming a running job to report false")
+	}
+	if jobs, _ := db.PausedJobs(ctx); len(jobs) != 0 {
+		t.Errorf("expected no paused jobs, got %v", jobs)
+	}
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// JulesSession is a Jules coding session started from a chat, followed
+// until it finishes. Platform and ChannelID are where it was started, as
+// for reminders.
+type JulesSession struct {
+	ID        int64
+	SessionID string
+	Name      string // the Jules API name, "sessions/{id}"
+	Repo      string
+	Task      string
+	Platform  string
+	ChannelID string
+	State     string // as the Jules API last reported it
+	URL       string
+	PRURL     string // the pull request it opened, once it has
+	Detail    string // the latest progress, question or failure reason
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+const julesColumns = `id, session_id, name, repo, task, platform, channel_id, state, url, pr_url, detail, created_at, updated_at`
+
+// AddJulesSession records a started session.
+func (db *DB) AddJulesSession(ctx context.Context, s JulesSession) error {
+	query := `INSERT INTO jules_sessions (session_id, name, repo, task, platform, channel_id, state, url) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO NOTHING`
+	if _, err := db.ExecContext(ctx, query, s.SessionID, s.Name, s.Repo, s.Task, s.Platform, s.ChannelID, s.State, s.URL); err != nil {
+		return fmt.Errorf("failed to add Jules session: %w", err)
+	}
+	return nil
+}
+
+// ListJulesSessions returns the session's latest Jules sessions, newest
+// first.
+func (db *DB) ListJulesSessions(ctx context.Context, sessionID string, limit int) ([]JulesSession, error) {
+	return db.queryJulesSessions(ctx, `SELECT `+julesColumns+` FROM jules_sessions WHERE session_id = ? ORDER BY id DESC LIMIT ?`, sessionID, limit)
+}
+
+// PendingJulesSessions returns the Jules sessions started since since that
+// have not finished, oldest first.
+func (db *DB) PendingJulesSessions(ctx context.Context, since time.Time) ([]JulesSession, error) {
+	return db.queryJulesSessions(ctx, `SELECT `+julesColumns+` FROM jules_sessions
+		WHERE state NOT IN ('COMPLETED', 'FAILED') AND created_at >= ? ORDER BY id ASC`, since.UTC().Format(time.DateTime))
+}
+
+// UpdateJulesSession stores what polling learned about the session id.
+func (db *DB) UpdateJulesSession(ctx context.Context, id int64, state, url, prURL, detail string) error {
+	query := `UPDATE jules_sessions SET state = ?, url = ?, pr_url = ?, detail = ?, updated_at = ? WHERE id = ?`
+	if _, err := db.ExecContext(ctx, query, state, url, prURL, detail, time.Now().UTC().Format(time.DateTime), id); err != nil {
+		return fmt.Errorf("failed to update Jules session: %w", err)
+	}
+	return nil
+}
+
+func (db *DB) queryJulesSessions(ctx context.Context, query string, args ...any) ([]JulesSession, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Jules sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []JulesSession
+	for rows.Next() {
+		var s JulesSession
+		if err := rows.Scan(&s.ID, &s.SessionID, &s.Name, &s.Repo, &s.Task, &s.Platform, &s.ChannelID,
+			&s.State, &s.URL, &s.PRURL, &s.Detail, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan Jules session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return sessions, nil
+}
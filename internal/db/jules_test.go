@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJulesSessions(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	fix := JulesSession{SessionID: "session-a", Name: "sessions/1", Repo: "owner/repo", Task: "fix the build", Platform: "telegram", ChannelID: "42", State: "QUEUED"}
+	if err := db.AddJulesSession(ctx, fix); err != nil {
+		t.Fatalf("AddJulesSession failed: %v", err)
+	}
+	if err := db.AddJulesSession(ctx, fix); err != nil {
+		t.Fatalf("expected a duplicate session to be ignored, got %v", err)
+	}
+	_ = db.AddJulesSession(ctx, JulesSession{SessionID: "session-a", Name: "sessions/2", Repo: "owner/repo", Task: "add docs"})
+	_ = db.AddJulesSession(ctx, JulesSession{SessionID: "session-b", Name: "sessions/3", Repo: "owner/other", Task: "bump deps"})
+
+	sessions, err := db.ListJulesSessions(ctx, "session-a", 10)
+	if err != nil {
+		t.Fatalf("ListJulesSessions failed: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].Name != "sessions/2" || sessions[1].Platform != "telegram" || sessions[1].ChannelID != "42" {
+		t.Fatalf("expected the session's 2 Jules sessions, newest first, got %+v", sessions)
+	}
+
+	since := time.Now().Add(-time.Hour)
+	pending, err := db.PendingJulesSessions(ctx, since)
+	if err != nil || len(pending) != 3 {
+		t.Fatalf("expected 3 pending sessions, got %+v (err=%v)", pending, err)
+	}
+
+	if err := db.UpdateJulesSession(ctx, sessions[1].ID, "COMPLETED", "https://jules.google/session/1", "https://github.com/owner/repo/pull/42", "Done"); err != nil {
+		t.Fatalf("UpdateJulesSession failed: %v", err)
+	}
+	sessions, _ = db.ListJulesSessions(ctx, "session-a", 1)
+	if len(sessions) != 1 || sessions[0].Name != "sessions/2" {
+		t.Fatalf("expected the limit to keep the newest session, got %+v", sessions)
+	}
+	if pending, _ := db.PendingJulesSessions(ctx, since); len(pending) != 2 {
+		t.Errorf("expected the completed session to stop being pending, got %+v", pending)
+	}
+	if pending, _ := db.PendingJulesSessions(ctx, time.Now().Add(time.Hour)); len(pending) != 0 {
+		t.Errorf("expected sessions started before since to be skipped, got %+v", pending)
+	}
+	all, _ := db.ListJulesSessions(ctx, "session-a", 10)
+	if got := all[1]; got.State != "COMPLETED" || got.PRURL != "https://github.com/owner/repo/pull/42" || got.Detail != "Done" {
+		t.Errorf("expected the update to be stored, got %+v", got)
+	}
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// GetSessionLocale returns the language chosen with /language, or "" if the
+// session uses the configured default.
+func (db *DB) GetSessionLocale(ctx context.Context, sessionID string) (string, error) {
+	var locale string
+	err := db.QueryRowContext(ctx, `SELECT locale FROM session_locales WHERE session_id = ?`, sessionID).Scan(&locale)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get session locale: %w", err)
+	}
+	return locale, nil
+}
+
+// SetSessionLocale stores the session's language. An empty locale reverts
+// the session to the configured default.
+func (db *DB) SetSessionLocale(ctx context.Context, sessionID, locale string) error {
+	if locale == "" {
+		if _, err := db.ExecContext(ctx, `DELETE FROM session_locales WHERE session_id = ?`, sessionID); err != nil {
+			return fmt.Errorf("failed to clear session locale: %w", err)
+		}
+		return nil
+	}
+	query := `
+		INSERT INTO session_locales (session_id, locale) VALUES (?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET locale = excluded.locale
+	`
+	if _, err := db.ExecContext(ctx, query, sessionID, locale); err != nil {
+		return fmt.Errorf("failed to set session locale: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSessionLocale(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if l, err := db.GetSessionLocale(ctx, "session-a"); err != nil || l != "" {
+		t.Fatalf("expected no locale, got %q err=%v", l, err)
+	}
+	if err := db.SetSessionLocale(ctx, "session-a", "es"); err != nil {
+		t.Fatalf("SetSessionLocale failed: %v", err)
+	}
+	if err := db.SetSessionLocale(ctx, "session-a", "en"); err != nil {
+		t.Fatalf("SetSessionLocale update failed: %v", err)
+	}
+	if l, _ := db.GetSessionLocale(ctx, "session-a"); l != "en" {
+		t.Errorf("expected updated locale en, got %q", l)
+	}
+	if l, _ := db.GetSessionLocale(ctx, "session-b"); l != "" {
+		t.Errorf("expected other session to be unaffected, got %q", l)
+	}
+	if err := db.SetSessionLocale(ctx, "session-a", ""); err != nil {
+		t.Fatalf("clearing locale failed: %v", err)
+	}
+	if l, _ := db.GetSessionLocale(ctx, "session-a"); l != "" {
+		t.Errorf("expected cleared locale, got %q", l)
+	}
+}
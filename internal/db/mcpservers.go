@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// MCPServerOverrides returns the MCP servers added or removed at runtime
+// (/mcp add and /mcp remove), by name: each added server's configuration
+// as JSON, or "" for a configured server that was removed.
+func (db *DB) MCPServerOverrides(ctx context.Context) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name, config FROM mcp_servers`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load MCP servers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	overrides := make(map[string]string)
+	for rows.Next() {
+		var name, config string
+		if err := rows.Scan(&name, &config); err != nil {
+			return nil, fmt.Errorf("failed to scan MCP server: %w", err)
+		}
+		overrides[name] = config
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return overrides, nil
+}
+
+// SetMCPServerOverride records a server added at runtime with its JSON
+// configuration, or a removed one with "", replacing any earlier change.
+func (db *DB) SetMCPServerOverride(ctx context.Context, name, config string) error {
+	query := `
+		INSERT INTO mcp_servers (name, config, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(name) DO UPDATE SET config = excluded.config, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := db.ExecContext(ctx, query, name, config); err != nil {
+		return fmt.Errorf("failed to save MCP server %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteMCPServerOverride forgets the runtime change to a server, leaving
+// it as configured.
+func (db *DB) DeleteMCPServerOverride(ctx context.Context, name string) error {
+	if _, err := db.ExecContext(ctx, `DELETE FROM mcp_servers WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("failed to delete MCP server %s: %w", name, err)
+	}
+	return nil
+}
+
+// MCPToolSchemas returns the tool definitions last listed by an MCP server
+// as JSON, or "" if none were saved.
+func (db *DB) MCPToolSchemas(ctx context.Context, server string) (string, error) {
+	var tools string
+	err := db.QueryRowContext(ctx, `SELECT tools FROM mcp_tool_schemas WHERE server = ?`, server).Scan(&tools)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get MCP tool schemas: %w", err)
+	}
+	return tools, nil
+}
+
+// SetMCPToolSchemas saves the tool definitions an MCP server listed,
+// replacing the earlier ones.
+func (db *DB) SetMCPToolSchemas(ctx context.Context, server, tools string) error {
+	query := `
+		INSERT INTO mcp_tool_schemas (server, tools, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(server) DO UPDATE SET tools = excluded.tools, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := db.ExecContext(ctx, query, server, tools); err != nil {
+		return fmt.Errorf("failed to save MCP tool schemas for %s: %w", server, err)
+	}
+	return nil
+}
+
+// DeleteMCPToolSchemas forgets a server's saved tool definitions.
+func (db *DB) DeleteMCPToolSchemas(ctx context.Context, server string) error {
+	if _, err := db.ExecContext(ctx, `DELETE FROM mcp_tool_schemas WHERE server = ?`, server); err != nil {
+		return fmt.Errorf("failed to delete MCP tool schemas for %s: %w", server, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"maps"
+	"testing"
+)
+
+func TestMCPServerOverrides(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if got, err := db.MCPServerOverrides(ctx); err != nil || len(got) != 0 {
+		t.Fatalf("expected no overrides, got %v err=%v", got, err)
+	}
+	if err := db.SetMCPServerOverride(ctx, "notes", `{"command":"notes-mcp"}`); err != nil {
+		t.Fatalf("SetMCPServerOverride failed: %v", err)
+	}
+	if err := db.SetMCPServerOverride(ctx, "notes", `{"command":"notes-mcp","args":["--ro"]}`); err != nil {
+		t.Fatalf("SetMCPServerOverride update failed: %v", err)
+	}
+	if err := db.SetMCPServerOverride(ctx, "github", ""); err != nil {
+		t.Fatalf("SetMCPServerOverride removal failed: %v", err)
+	}
+	want := map[string]string{"notes": `{"command":"notes-mcp","args":["--ro"]}`, "github": ""}
+	if got, _ := db.MCPServerOverrides(ctx); !maps.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if err := db.DeleteMCPServerOverride(ctx, "github"); err != nil {
+		t.Fatalf("DeleteMCPServerOverride failed: %v", err)
+	}
+	if got, _ := db.MCPServerOverrides(ctx); len(got) != 1 || got["notes"] == "" {
+		t.Errorf("expected only notes left, got %v", got)
+	}
+}
+
+func TestMCPToolSchemas(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if got, err := db.MCPToolSchemas(ctx, "weather"); err != nil || got != "" {
+		t.Fatalf("expected no schemas, got %q err=%v", got, err)
+	}
+	if err := db.SetMCPToolSchemas(ctx, "weather", `[{"name":"forecast"}]`); err != nil {
+		t.Fatalf("SetMCPToolSchemas failed: %v", err)
+	}
+	if err := db.SetMCPToolSchemas(ctx, "weather", `[{"name":"forecast"},{"name":"alerts"}]`); err != nil {
+		t.Fatalf("SetMCPToolSchemas update failed: %v", err)
+	}
+	if got, _ := db.MCPToolSchemas(ctx, "weather"); got != `[{"name":"forecast"},{"name":"alerts"}]` {
+		t.Errorf("expected the updated schemas, got %q", got)
+	}
+
+	if err := db.DeleteMCPToolSchemas(ctx, "weather"); err != nil {
+		t.Fatalf("DeleteMCPToolSchemas failed: %v", err)
+	}
+	if got, _ := db.MCPToolSchemas(ctx, "weather"); got != "" {
+		t.Errorf("expected schemas to be deleted, got %q", got)
+	}
+}
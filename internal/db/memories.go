@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Memory is an explicit fact the user asked the bot to remember.
+type Memory struct {
+	ID        int64
+	SessionID string
+	Content   string
+	CreatedAt time.Time
+}
+
+// AddMemory stores a fact for the session and returns its ID.
+func (db *DB) AddMemory(ctx context.Context, sessionID, content string) (int64, error) {
+	query := `INSERT INTO memories (session_id, content) VALUES (?, ?)`
+	id, err := db.insert(ctx, query, sessionID, content)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add memory: %w", err)
+	}
+	return id, nil
+}
+
+// SearchMemories returns the session's memories containing every word of
+// query (case-insensitive), newest first. An empty query matches everything.
+func (db *DB) SearchMemories(ctx context.Context, sessionID, query string, limit int) ([]Memory, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	sqlQuery := `SELECT id, session_id, content, created_at FROM memories WHERE session_id = ?`
+	args := []any{sessionID}
+	for _, term := range strings.Fields(query) {
+		sqlQuery += ` AND LOWER(content) LIKE LOWER(?) ESCAPE '\'`
+		args = append(args, "%"+escapeLike(term)+"%")
+	}
+	sqlQuery += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search memories: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var memories []Memory
+	for rows.Next() {
+		var m Memory
+		if err := rows.Scan(&m.ID, &m.SessionID, &m.Content, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan memory: %w", err)
+		}
+		memories = append(memories, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return memories, nil
+}
+
+// DeleteMemory removes one of the session's memories. It reports whether a
+// matching memory was found.
+func (db *DB) DeleteMemory(ctx context.Context, sessionID string, id int64) (bool, error) {
+	query := `DELETE FROM memories WHERE id = ? AND session_id = ?`
+	res, err := db.ExecContext(ctx, query, id, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete memory: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete memory: %w", err)
+	}
+	return n > 0, nil
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally.
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
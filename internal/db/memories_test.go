@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemories(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	first, err := db.AddMemory(ctx, "session-a", "I prefer metric units")
+	if err != nil {
+		t.Fatalf("AddMemory failed: %v", err)
+	}
+	_, _ = db.AddMemory(ctx, "session-a", "My home server runs Debian")
+	_, _ = db.AddMemory(ctx, "session-a", "Discount is 100% off_peak")
+	_, _ = db.AddMemory(ctx, "session-b", "Metric units for session b")
+
+	all, err := db.SearchMemories(ctx, "session-a", "", 0)
+	if err != nil {
+		t.Fatalf("SearchMemories failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 memories, got %d", len(all))
+	}
+
+	matches, _ := db.SearchMemories(ctx, "session-a", "METRIC units", 10)
+	if len(matches) != 1 || matches[0].ID != first {
+		t.Errorf("expected case-insensitive match on first memory, got %+v", matches)
+	}
+
+	// LIKE wildcards in the query are matched literally
+	if matches, _ := db.SearchMemories(ctx, "session-a", "%", 10); len(matches) != 1 {
+		t.Errorf("expected literal %% match only, got %+v", matches)
+	}
+	if matches, _ := db.SearchMemories(ctx, "session-a", "metric debian", 10); len(matches) != 0 {
+		t.Errorf("expected every word to be required, got %+v", matches)
+	}
+
+	if found, _ := db.DeleteMemory(ctx, "session-b", first); found {
+		t.Error("expected delete from another session to find nothing")
+	}
+	found, err := db.DeleteMemory(ctx, "session-a", first)
+	if err != nil || !found {
+		t.Fatalf("DeleteMemory failed: found=%v err=%v", found, err)
+	}
+	if matches, _ := db.SearchMemories(ctx, "session-a", "metric", 10); len(matches) != 0 {
+		t.Errorf("expected deleted memory to be gone, got %+v", matches)
+	}
+}
@@ -2,26 +2,79 @@ package db
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
 )
 
-// SaveSessionSummary persists a conversation summary for a specific session.
+// Sources of a session summary version.
+const (
+	SummaryCompressed = "compression"
+	SummaryRollback   = "rollback"
+	SummaryImported   = "import"
+)
+
+// maxSummaryVersions is how many summary versions are kept per session.
+const maxSummaryVersions = 20
+
+// SummaryVersion is one saved state of a session's summary.
+type SummaryVersion struct {
+	ID        int64
+	SessionID string
+	Summary   string
+	Source    string // SummaryCompressed, SummaryRollback or SummaryImported
+	CreatedAt time.Time
+}
+
+// SaveSessionSummary persists a conversation summary for a specific session
+// and records it as a new version.
 func (db *DB) SaveSessionSummary(ctx context.Context, sessionID, summary string) error {
-	query := `
+	return db.saveSessionSummary(ctx, sessionID, summary, SummaryCompressed)
+}
+
+func (db *DB) saveSessionSummary(ctx context.Context, sessionID, summary, source string) error {
+	stored, err := db.cipher.EncryptString(summary)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt session summary: %w", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save session summary: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	queries := []struct {
+		query string
+		args  []any
+	}{
+		{`
 		INSERT INTO session_summaries (session_id, summary, updated_at)
 		VALUES (?, ?, CURRENT_TIMESTAMP)
 		ON CONFLICT(session_id) DO UPDATE SET
 			summary = excluded.summary,
 			updated_at = CURRENT_TIMESTAMP
-	`
-	_, err := db.ExecContext(ctx, query, sessionID, summary)
-	if err != nil {
-		return fmt.Errorf("failed to save session summary: %w", err)
+		`, []any{sessionID, stored}},
+		{`INSERT INTO session_summary_versions (session_id, summary, source, created_at) VALUES (?, ?, ?, ?)`,
+			[]any{sessionID, stored, source, time.Now().UTC()}},
+		{`
+		DELETE FROM session_summary_versions WHERE session_id = ? AND id NOT IN (
+			SELECT id FROM (
+				SELECT id FROM session_summary_versions WHERE session_id = ? ORDER BY id DESC LIMIT ?
+			) AS kept
+		)
+		`, []any{sessionID, sessionID, maxSummaryVersions}},
 	}
-	return nil
+	for _, q := range queries {
+		if _, err := tx.ExecContext(ctx, db.dialect.rebind(q.query), q.args...); err != nil {
+			return fmt.Errorf("failed to save session summary: %w", err)
+		}
+	}
+	return tx.Commit()
 }
 
 // GetSessionSummary retrieves the persisted summary for a session.
@@ -35,25 +88,168 @@ func (db *DB) GetSessionSummary(ctx context.Context, sessionID string) (string,
 		}
 		return "", fmt.Errorf("failed to get session summary: %w", err)
 	}
+	summary, err = db.cipher.DecryptString(summary)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt session summary: %w", err)
+	}
 	return summary, nil
 }
 
-// DeleteSessionSummary removes a persisted summary.
-func (db *DB) DeleteSessionSummary(ctx context.Context, sessionID string) error {
-	query := `DELETE FROM session_summaries WHERE session_id = ?`
-	_, err := db.ExecContext(ctx, query, sessionID)
+// ListSummaryVersions returns a session's summary versions, newest first.
+func (db *DB) ListSummaryVersions(ctx context.Context, sessionID string, limit int) ([]SummaryVersion, error) {
+	if limit <= 0 {
+		limit = maxSummaryVersions
+	}
+	query := `
+		SELECT id, session_id, summary, source, created_at FROM session_summary_versions
+		WHERE session_id = ? ORDER BY id DESC LIMIT ?
+	`
+	rows, err := db.QueryContext(ctx, query, sessionID, limit)
 	if err != nil {
-		return fmt.Errorf("failed to delete session summary: %w", err)
+		return nil, fmt.Errorf("failed to list summary versions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var versions []SummaryVersion
+	for rows.Next() {
+		var v SummaryVersion
+		if err := rows.Scan(&v.ID, &v.SessionID, &v.Summary, &v.Source, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan summary version: %w", err)
+		}
+		if v.Summary, err = db.cipher.DecryptString(v.Summary); err != nil {
+			return nil, fmt.Errorf("failed to decrypt summary version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return versions, nil
+}
+
+// RollbackSessionSummary makes an earlier version the session's current
+// summary, recording the rollback as a new version. It reports whether the
+// version exists for the session.
+func (db *DB) RollbackSessionSummary(ctx context.Context, sessionID string, versionID int64) (bool, error) {
+	var summary string
+	query := `SELECT summary FROM session_summary_versions WHERE id = ? AND session_id = ?`
+	if err := db.QueryRowContext(ctx, query, versionID, sessionID).Scan(&summary); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to load summary version: %w", err)
+	}
+	summary, err := db.cipher.DecryptString(summary)
+	if err != nil {
+		return false, fmt.Errorf("failed to decrypt summary version: %w", err)
+	}
+	if err := db.saveSessionSummary(ctx, sessionID, summary, SummaryRollback); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteSessionSummary removes a persisted summary and its history.
+func (db *DB) DeleteSessionSummary(ctx context.Context, sessionID string) error {
+	for _, query := range []string{
+		`DELETE FROM session_summaries WHERE session_id = ?`,
+		`DELETE FROM session_summary_versions WHERE session_id = ?`,
+	} {
+		if _, err := db.ExecContext(ctx, query, sessionID); err != nil {
+			return fmt.Errorf("failed to delete session summary: %w", err)
+		}
 	}
 	return nil
 }
 
 // SaveBriefing saves a generated briefing to the database.
 func (db *DB) SaveBriefing(ctx context.Context, content string) error {
-	query := `INSERT INTO briefings (content) VALUES (?)`
-	_, err := db.ExecContext(ctx, query, content)
+	_, err := db.AddBriefing(ctx, content, "", nil)
+	return err
+}
+
+// ErrDuplicateBriefing is returned by AddBriefing when a briefing with the
+// same content is already stored.
+var ErrDuplicateBriefing = errors.New("an identical briefing is already stored")
+
+// AddBriefing saves a briefing with its topic and tags and returns its ID.
+// Tags are normalized with NormalizeTags. If a briefing with the same content
+// (ignoring case, whitespace and punctuation) exists, nothing is saved and its
+// ID is returned with ErrDuplicateBriefing.
+func (db *DB) AddBriefing(ctx context.Context, content, topic string, tags []string) (int64, error) {
+	return db.AddSessionBriefing(ctx, "", content, topic, tags)
+}
+
+// AddSessionBriefing is AddBriefing for a briefing owned by a session, such
+// as the result of its /research request. Briefings without an owner (from
+// scheduled jobs) are shared by all sessions. Duplicates are only looked
+// for among the session's own briefings, so a report another session or
+// a job already has is still stored for this one.
+func (db *DB) AddSessionBriefing(ctx context.Context, sessionID, content, topic string, tags []string) (int64, error) {
+	return db.addBriefing(ctx, sessionID, content, ContentHash(content), topic, tags)
+}
+
+// AddTemplatedBriefing is AddBriefing for a report wrapped in a job's
+// template. Duplicates are found by the hash of source, the report before
+// the template was applied, since a template that adds the run's date
+// makes every wrapped copy differ.
+func (db *DB) AddTemplatedBriefing(ctx context.Context, content, source, topic string, tags []string) (int64, error) {
+	return db.addBriefing(ctx, "", content, ContentHash(source), topic, tags)
+}
+
+func (db *DB) addBriefing(ctx context.Context, sessionID, content, hash, topic string, tags []string) (int64, error) {
+	var existing int64
+	query := `SELECT id FROM briefings WHERE content_hash = ? AND session_id = ? ORDER BY id LIMIT 1`
+	err := db.QueryRowContext(ctx, query, hash, sessionID).Scan(&existing)
+	switch {
+	case err == nil:
+		return existing, ErrDuplicateBriefing
+	case err != sql.ErrNoRows:
+		return 0, fmt.Errorf("failed to check for duplicate briefing: %w", err)
+	}
+
+	query = `INSERT INTO briefings (session_id, content, topic, tags, content_hash) VALUES (?, ?, ?, ?, ?)`
+	id, err := db.insert(ctx, query, sessionID, content, strings.TrimSpace(topic), joinTags(NormalizeTags(tags)), hash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save briefing: %w", err)
+	}
+	return id, nil
+}
+
+// ContentHash returns a hex SHA-256 of content with case, punctuation and
+// whitespace normalized away, so reformatted copies of a report match.
+func ContentHash(content string) string {
+	normalized := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sum := sha256.Sum256([]byte(strings.Join(normalized, " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// backfillBriefingHashes hashes briefings stored before content_hash existed.
+func (db *DB) backfillBriefingHashes(ctx context.Context) error {
+	rows, err := db.QueryContext(ctx, `SELECT id, content FROM briefings WHERE content_hash = ''`)
 	if err != nil {
-		return fmt.Errorf("failed to save briefing: %w", err)
+		return fmt.Errorf("failed to list unhashed briefings: %w", err)
+	}
+	hashes := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan briefing: %w", err)
+		}
+		hashes[id] = ContentHash(content)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows error: %w", err)
+	}
+	for id, hash := range hashes {
+		if _, err := db.ExecContext(ctx, `UPDATE briefings SET content_hash = ? WHERE id = ?`, hash, id); err != nil {
+			return fmt.Errorf("failed to hash briefing %d: %w", id, err)
+		}
 	}
 	return nil
 }
@@ -63,26 +259,134 @@ type Briefing struct {
 	ID        int64
 	Content   string
 	CreatedAt string
+	Topic     string   // job name or research topic, if known
+	Tags      []string // normalized topic tags
 }
 
 // GetRecentBriefings retrieves the most recent N briefings ordered by creation time.
 func (db *DB) GetRecentBriefings(ctx context.Context, limit int) ([]Briefing, error) {
-	if limit <= 0 {
-		limit = 5
+	return db.GetBriefings(ctx, BriefingFilter{Limit: limit})
+}
+
+// NormalizeTags lowercases tags, joins words with hyphens, drops anything
+// but letters, digits and hyphens, and removes empty and duplicate tags.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, t := range tags {
+		var sb strings.Builder
+		for _, r := range strings.Join(strings.Fields(strings.ToLower(t)), "-") {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+				sb.WriteRune(r)
+			}
+		}
+		tag := sb.String()
+		for strings.Contains(tag, "--") {
+			tag = strings.ReplaceAll(tag, "--", "-")
+		}
+		tag = strings.Trim(tag, "-")
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// joinTags stores tags as ",a,b," so a single tag can be matched with LIKE.
+func joinTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
 	}
-	query := `SELECT id, content, created_at FROM briefings ORDER BY created_at DESC LIMIT ?`
-	rows, err := db.QueryContext(ctx, query, limit)
+	return "," + strings.Join(tags, ",") + ","
+}
+
+func splitTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// BriefingFilter narrows a briefing query. Zero values mean "no filter".
+type BriefingFilter struct {
+	Since   time.Time // inclusive
+	Until   time.Time // exclusive
+	Keyword string    // case-insensitive substring of the content
+	// Tag matches briefings carrying the tag. Untagged briefings saved
+	// before tags existed match if their content contains it instead.
+	Tag string
+	// Topic matches briefings of one job or research topic exactly.
+	Topic string
+	// SessionID limits the result to the session's own briefings and the
+	// shared ones.
+	SessionID string
+	// Shared limits the result to the briefings no session owns, the
+	// reports of scheduled jobs.
+	Shared bool
+	Limit  int
+}
+
+// GetBriefings retrieves briefings matching the filter, newest first.
+func (db *DB) GetBriefings(ctx context.Context, f BriefingFilter) ([]Briefing, error) {
+	if f.Limit <= 0 {
+		f.Limit = 5
+	}
+	var where []string
+	var args []interface{}
+	if !f.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, f.Since.UTC().Format(time.DateTime))
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "created_at < ?")
+		args = append(args, f.Until.UTC().Format(time.DateTime))
+	}
+	if f.Keyword != "" {
+		where = append(where, `LOWER(content) LIKE LOWER(?) ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(f.Keyword)+"%")
+	}
+	if tags := NormalizeTags([]string{f.Tag}); len(tags) > 0 {
+		where = append(where, `(tags LIKE ? ESCAPE '\' OR (tags = '' AND LOWER(content) LIKE LOWER(?) ESCAPE '\'))`)
+		args = append(args, "%,"+escapeLike(tags[0])+",%", "%"+escapeLike(f.Tag)+"%")
+	}
+
+	if f.Topic != "" {
+		where = append(where, "topic = ?")
+		args = append(args, f.Topic)
+	}
+	if f.SessionID != "" {
+		where = append(where, "(session_id = ? OR session_id = '')")
+		args = append(args, f.SessionID)
+	}
+	if f.Shared {
+		where = append(where, "session_id = ''")
+	}
+
+	query := `SELECT id, content, created_at, topic, tags FROM briefings`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, f.Limit)
+
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recent briefings: %w", err)
+		return nil, fmt.Errorf("failed to get briefings: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
 	var briefings []Briefing
 	for rows.Next() {
 		var b Briefing
-		if err := rows.Scan(&b.ID, &b.Content, &b.CreatedAt); err != nil {
+		var tags string
+		if err := rows.Scan(&b.ID, &b.Content, &b.CreatedAt, &b.Topic, &tags); err != nil {
 			return nil, fmt.Errorf("failed to scan briefing: %w", err)
 		}
+		b.Tags = splitTags(tags)
 		briefings = append(briefings, b)
 	}
 	if err := rows.Err(); err != nil {
@@ -97,21 +401,50 @@ type Reminder struct {
 	SessionID string
 	Message   string
 	RemindAt  time.Time
+	// Recurrence is an RRULE-style spec (see timeparse.Rule). Empty for
+	// one-shot reminders.
+	Recurrence string
+	// Platform (a notifier name such as "Telegram") and ChannelID record
+	// where the reminder was set, so it is delivered back there. Empty for
+	// reminders from before they were tracked.
+	Platform  string
+	ChannelID string
 }
 
-// AddReminder stores a new reminder in the database.
+// AddReminder stores a new one-shot reminder in the database.
 func (db *DB) AddReminder(ctx context.Context, sessionID, message string, remindAt time.Time) error {
-	query := `INSERT INTO reminders (session_id, message, remind_at) VALUES (?, ?, ?)`
-	_, err := db.ExecContext(ctx, query, sessionID, message, remindAt.UTC())
+	return db.AddRecurringReminder(ctx, sessionID, message, remindAt, "")
+}
+
+// AddRecurringReminder stores a reminder that is rescheduled according to
+// recurrence after each delivery.
+func (db *DB) AddRecurringReminder(ctx context.Context, sessionID, message string, remindAt time.Time, recurrence string) error {
+	return db.SaveReminder(ctx, Reminder{SessionID: sessionID, Message: message, RemindAt: remindAt, Recurrence: recurrence})
+}
+
+// SaveReminder stores a new reminder with its origin. r.ID is ignored.
+func (db *DB) SaveReminder(ctx context.Context, r Reminder) error {
+	query := `INSERT INTO reminders (session_id, message, remind_at, recurrence, platform, channel_id) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := db.ExecContext(ctx, query, r.SessionID, r.Message, r.RemindAt.UTC(), r.Recurrence, r.Platform, r.ChannelID)
 	if err != nil {
 		return fmt.Errorf("failed to add reminder: %w", err)
 	}
 	return nil
 }
 
+// RescheduleReminder moves a recurring reminder to its next occurrence.
+func (db *DB) RescheduleReminder(ctx context.Context, id int64, next time.Time) error {
+	query := `UPDATE reminders SET remind_at = ? WHERE id = ?`
+	_, err := db.ExecContext(ctx, query, next.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule reminder: %w", err)
+	}
+	return nil
+}
+
 // GetPendingReminders returns all undelivered reminders whose remind_at time has passed.
 func (db *DB) GetPendingReminders(ctx context.Context, now time.Time) ([]Reminder, error) {
-	query := `SELECT id, session_id, message, remind_at FROM reminders WHERE delivered = 0 AND remind_at <= ?`
+	query := `SELECT id, session_id, message, remind_at, recurrence, platform, channel_id FROM reminders WHERE delivered = 0 AND remind_at <= ?`
 	rows, err := db.QueryContext(ctx, query, now.UTC())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending reminders: %w", err)
@@ -121,7 +454,7 @@ func (db *DB) GetPendingReminders(ctx context.Context, now time.Time) ([]Reminde
 	var reminders []Reminder
 	for rows.Next() {
 		var r Reminder
-		if err := rows.Scan(&r.ID, &r.SessionID, &r.Message, &r.RemindAt); err != nil {
+		if err := rows.Scan(&r.ID, &r.SessionID, &r.Message, &r.RemindAt, &r.Recurrence, &r.Platform, &r.ChannelID); err != nil {
 			return nil, fmt.Errorf("failed to scan reminder: %w", err)
 		}
 		reminders = append(reminders, r)
@@ -132,6 +465,74 @@ func (db *DB) GetPendingReminders(ctx context.Context, now time.Time) ([]Reminde
 	return reminders, nil
 }
 
+// GetSessionReminders returns all undelivered reminders for a session ordered by remind_at.
+func (db *DB) GetSessionReminders(ctx context.Context, sessionID string) ([]Reminder, error) {
+	query := `SELECT id, session_id, message, remind_at, recurrence, platform, channel_id FROM reminders WHERE session_id = ? AND delivered = 0 ORDER BY remind_at ASC`
+	rows, err := db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session reminders: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var reminders []Reminder
+	for rows.Next() {
+		var r Reminder
+		if err := rows.Scan(&r.ID, &r.SessionID, &r.Message, &r.RemindAt, &r.Recurrence, &r.Platform, &r.ChannelID); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return reminders, nil
+}
+
+// CancelReminder deletes an undelivered reminder owned by the given session.
+// It reports whether a matching reminder was found.
+func (db *DB) CancelReminder(ctx context.Context, sessionID string, id int64) (bool, error) {
+	query := `DELETE FROM reminders WHERE id = ? AND session_id = ? AND delivered = 0`
+	res, err := db.ExecContext(ctx, query, id, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel reminder: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel reminder: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SnoozeReminder re-queues a reminder owned by the session for a later time.
+// One-shot reminders are moved back to pending; recurring reminders get a
+// one-shot copy so their own schedule is unaffected. It reports whether a
+// matching reminder was found.
+func (db *DB) SnoozeReminder(ctx context.Context, sessionID string, id int64, until time.Time) (bool, error) {
+	r := Reminder{SessionID: sessionID, RemindAt: until}
+	query := `SELECT message, recurrence, platform, channel_id FROM reminders WHERE id = ? AND session_id = ?`
+	err := db.QueryRowContext(ctx, query, id, sessionID).Scan(&r.Message, &r.Recurrence, &r.Platform, &r.ChannelID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to snooze reminder: %w", err)
+	}
+
+	if r.Recurrence != "" {
+		r.Recurrence = ""
+		if err := db.SaveReminder(ctx, r); err != nil {
+			return false, fmt.Errorf("failed to snooze reminder: %w", err)
+		}
+		return true, nil
+	}
+
+	query = `UPDATE reminders SET delivered = 0, remind_at = ? WHERE id = ?`
+	if _, err := db.ExecContext(ctx, query, until.UTC(), id); err != nil {
+		return false, fmt.Errorf("failed to snooze reminder: %w", err)
+	}
+	return true, nil
+}
+
 // MarkReminderDelivered marks a reminder as delivered so it won't be returned again.
 func (db *DB) MarkReminderDelivered(ctx context.Context, id int64) error {
 	query := `UPDATE reminders SET delivered = 1 WHERE id = ?`
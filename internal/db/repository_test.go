@@ -2,8 +2,12 @@ package db
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/crypt"
 )
 
 func setupTestDB(t *testing.T) *DB {
@@ -72,6 +76,96 @@ func TestGetRecentBriefings(t *testing.T) {
 	}
 }
 
+func TestGetBriefings(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	for _, b := range []struct{ content, createdAt string }{
+		{"Kubernetes security roundup", "2026-03-01 08:00:00"},
+		{"Go 1.26 release notes", "2026-03-05 08:00:00"},
+		{"Security: 100% of CVEs patched", "2026-03-09 08:00:00"},
+	} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO briefings (content, created_at) VALUES (?, ?)`, b.content, b.createdAt); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	results, err := db.GetBriefings(ctx, BriefingFilter{
+		Since: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("GetBriefings failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Content != "Security: 100% of CVEs patched" {
+		t.Errorf("expected the two briefings in range newest first, got %+v", results)
+	}
+
+	results, _ = db.GetBriefings(ctx, BriefingFilter{Keyword: "SECURITY", Limit: 10})
+	if len(results) != 2 {
+		t.Errorf("expected 2 keyword matches, got %d", len(results))
+	}
+	results, _ = db.GetBriefings(ctx, BriefingFilter{Keyword: "100%", Limit: 10})
+	if len(results) != 1 {
+		t.Errorf("expected literal %% match, got %d", len(results))
+	}
+}
+
+func TestBriefingTags(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	id, err := db.AddBriefing(ctx, "Weekly CVE roundup", "Security Watch", []string{"Security", "supply chain", "security", "  "})
+	if err != nil || id == 0 {
+		t.Fatalf("AddBriefing failed: id=%d err=%v", id, err)
+	}
+	_, _ = db.AddBriefing(ctx, "Go release notes", "Go", []string{"golang"})
+	// Saved before tags existed; matched by content instead.
+	_ = db.SaveBriefing(ctx, "Legacy security notes")
+	_, _ = db.AddBriefing(ctx, "Mentions security in passing", "Misc", []string{"misc"})
+
+	results, err := db.GetBriefings(ctx, BriefingFilter{Tag: "SECURITY", Limit: 10})
+	if err != nil {
+		t.Fatalf("GetBriefings failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Content != "Legacy security notes" || results[1].Content != "Weekly CVE roundup" {
+		t.Fatalf("unexpected tag matches: %+v", results)
+	}
+	got := results[1]
+	if got.Topic != "Security Watch" || strings.Join(got.Tags, ",") != "security,supply-chain" {
+		t.Errorf("unexpected topic/tags: %q %v", got.Topic, got.Tags)
+	}
+
+	results, _ = db.GetBriefings(ctx, BriefingFilter{Tag: "supply chain", Limit: 10})
+	if len(results) != 1 {
+		t.Errorf("expected multi-word tag match, got %+v", results)
+	}
+
+	results, _ = db.GetBriefings(ctx, BriefingFilter{Topic: "Go", Limit: 10})
+	if len(results) != 1 || results[0].Content != "Go release notes" {
+		t.Errorf("unexpected topic matches: %+v", results)
+	}
+
+	recent, _ := db.GetRecentBriefings(ctx, 1)
+	if len(recent) != 1 || recent[0].Topic != "Misc" {
+		t.Errorf("unexpected recent briefings: %+v", recent)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	t.Parallel()
+	got := NormalizeTags([]string{" AI/LLM ", "Go", "go", "k8s & cloud", "---", "Geospatial Engineering"})
+	want := "aillm,go,k8s-cloud,geospatial-engineering"
+	if strings.Join(got, ",") != want {
+		t.Errorf("NormalizeTags = %v, want %s", got, want)
+	}
+}
+
 func TestAddReminder(t *testing.T) {
 	t.Parallel()
 	db := setupTestDB(t)
@@ -145,3 +239,278 @@ func TestMarkReminderDelivered(t *testing.T) {
 		t.Errorf("expected 0 pending after delivery, got %d", len(pending))
 	}
 }
+
+func TestGetSessionReminders(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	_ = db.AddReminder(ctx, "session-a", "Later", time.Now().Add(2*time.Hour))
+	_ = db.AddReminder(ctx, "session-a", "Sooner", time.Now().Add(1*time.Hour))
+	_ = db.AddReminder(ctx, "session-b", "Other session", time.Now().Add(1*time.Hour))
+
+	reminders, err := db.GetSessionReminders(ctx, "session-a")
+	if err != nil {
+		t.Fatalf("GetSessionReminders failed: %v", err)
+	}
+	if len(reminders) != 2 {
+		t.Fatalf("expected 2 reminders, got %d", len(reminders))
+	}
+	if reminders[0].Message != "Sooner" {
+		t.Errorf("expected reminders ordered by remind_at, got %q first", reminders[0].Message)
+	}
+}
+
+func TestCancelReminder(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	_ = db.AddReminder(ctx, "session-a", "Cancel me", time.Now().Add(1*time.Hour))
+	reminders, _ := db.GetSessionReminders(ctx, "session-a")
+	if len(reminders) != 1 {
+		t.Fatalf("expected 1 reminder, got %d", len(reminders))
+	}
+	id := reminders[0].ID
+
+	// Another session cannot cancel it
+	found, err := db.CancelReminder(ctx, "session-b", id)
+	if err != nil {
+		t.Fatalf("CancelReminder failed: %v", err)
+	}
+	if found {
+		t.Error("expected cancel from another session to find nothing")
+	}
+
+	found, err = db.CancelReminder(ctx, "session-a", id)
+	if err != nil {
+		t.Fatalf("CancelReminder failed: %v", err)
+	}
+	if !found {
+		t.Error("expected reminder to be cancelled")
+	}
+
+	reminders, _ = db.GetSessionReminders(ctx, "session-a")
+	if len(reminders) != 0 {
+		t.Errorf("expected 0 reminders after cancel, got %d", len(reminders))
+	}
+}
+
+func TestRecurringReminder(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	rule := "FREQ=DAILY;INTERVAL=1;BYHOUR=9;BYMINUTE=0"
+	if err := db.AddRecurringReminder(ctx, "session-a", "Standup", time.Now().Add(-time.Minute), rule); err != nil {
+		t.Fatalf("AddRecurringReminder failed: %v", err)
+	}
+
+	pending, err := db.GetPendingReminders(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("GetPendingReminders failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Recurrence != rule {
+		t.Fatalf("expected 1 pending reminder with recurrence, got %+v", pending)
+	}
+
+	if err := db.RescheduleReminder(ctx, pending[0].ID, time.Now().Add(24*time.Hour)); err != nil {
+		t.Fatalf("RescheduleReminder failed: %v", err)
+	}
+	pending, _ = db.GetPendingReminders(ctx, time.Now())
+	if len(pending) != 0 {
+		t.Errorf("expected rescheduled reminder to no longer be pending, got %d", len(pending))
+	}
+}
+
+func TestSnoozeReminder(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	_ = db.AddReminder(ctx, "session-a", "One-shot", time.Now().Add(-time.Minute))
+	pending, _ := db.GetPendingReminders(ctx, time.Now())
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending, got %d", len(pending))
+	}
+	id := pending[0].ID
+	_ = db.MarkReminderDelivered(ctx, id)
+
+	found, err := db.SnoozeReminder(ctx, "session-b", id, time.Now().Add(time.Hour))
+	if err != nil || found {
+		t.Fatalf("expected snooze from another session to find nothing, got found=%v err=%v", found, err)
+	}
+
+	found, err = db.SnoozeReminder(ctx, "session-a", id, time.Now().Add(time.Hour))
+	if err != nil || !found {
+		t.Fatalf("SnoozeReminder failed: found=%v err=%v", found, err)
+	}
+	reminders, _ := db.GetSessionReminders(ctx, "session-a")
+	if len(reminders) != 1 || reminders[0].ID != id {
+		t.Fatalf("expected delivered reminder to be re-queued, got %+v", reminders)
+	}
+
+	// Recurring reminders get a one-shot copy
+	_ = db.SaveReminder(ctx, Reminder{SessionID: "session-c", Message: "Standup", RemindAt: time.Now().Add(time.Hour), Recurrence: "FREQ=DAILY;INTERVAL=1", Platform: "Discord", ChannelID: "chan-1"})
+	reminders, _ = db.GetSessionReminders(ctx, "session-c")
+	found, err = db.SnoozeReminder(ctx, "session-c", reminders[0].ID, time.Now().Add(10*time.Minute))
+	if err != nil || !found {
+		t.Fatalf("SnoozeReminder (recurring) failed: found=%v err=%v", found, err)
+	}
+	reminders, _ = db.GetSessionReminders(ctx, "session-c")
+	if len(reminders) != 2 || reminders[0].Recurrence != "" {
+		t.Errorf("expected a one-shot snooze copy before the recurring reminder, got %+v", reminders)
+	}
+	if reminders[0].Platform != "Discord" || reminders[0].ChannelID != "chan-1" {
+		t.Errorf("expected the snooze copy to keep its origin, got %+v", reminders[0])
+	}
+}
+
+func TestEncryptedSessionData(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	// Written before encryption was enabled.
+	if err := db.AddTranscriptMessage(ctx, "s1", RoleUser, "plaintext hello"); err != nil {
+		t.Fatalf("AddTranscriptMessage failed: %v", err)
+	}
+
+	c, err := crypt.New([]byte(strings.Repeat("k", crypt.KeySize)))
+	if err != nil {
+		t.Fatalf("crypt.New failed: %v", err)
+	}
+	db.SetCipher(c)
+	if err := db.SaveSessionSummary(ctx, "s1", "User is planning a move to Berlin"); err != nil {
+		t.Fatalf("SaveSessionSummary failed: %v", err)
+	}
+	if err := db.AddTranscriptMessage(ctx, "s1", RoleAssistant, "Berlin apartments are pricey"); err != nil {
+		t.Fatalf("AddTranscriptMessage failed: %v", err)
+	}
+	if err := db.TouchChatSession(ctx, "chan", "s1", "main"); err != nil {
+		t.Fatalf("TouchChatSession failed: %v", err)
+	}
+
+	var rawSummary, rawMessage string
+	_ = db.QueryRowContext(ctx, `SELECT summary FROM session_summaries`).Scan(&rawSummary)
+	_ = db.QueryRowContext(ctx, `SELECT content FROM transcripts ORDER BY id DESC LIMIT 1`).Scan(&rawMessage)
+	if strings.Contains(rawSummary, "Berlin") || strings.Contains(rawMessage, "Berlin") {
+		t.Errorf("expected ciphertext at rest, got %q and %q", rawSummary, rawMessage)
+	}
+
+	if summary, err := db.GetSessionSummary(ctx, "s1"); err != nil || summary != "User is planning a move to Berlin" {
+		t.Errorf("GetSessionSummary = %q, %v", summary, err)
+	}
+	msgs, err := db.GetTranscript(ctx, "s1", 0)
+	if err != nil || len(msgs) != 2 || msgs[0].Content != "plaintext hello" || msgs[1].Content != "Berlin apartments are pricey" {
+		t.Errorf("GetTranscript = %+v, %v", msgs, err)
+	}
+	sessions, err := db.ListChatSessions(ctx, "chan")
+	if err != nil || len(sessions) != 1 || sessions[0].Summary != "User is planning a move to Berlin" {
+		t.Errorf("ListChatSessions = %+v, %v", sessions, err)
+	}
+
+	db.SetCipher(nil)
+	if _, err := db.GetSessionSummary(ctx, "s1"); !errors.Is(err, crypt.ErrNoKey) {
+		t.Errorf("expected ErrNoKey without a key, got %v", err)
+	}
+}
+
+func TestAddBriefing_Duplicate(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	first, err := db.AddBriefing(ctx, "## Go News\n\nGo 1.26 is out!", "Go", nil)
+	if err != nil {
+		t.Fatalf("AddBriefing failed: %v", err)
+	}
+	id, err := db.AddBriefing(ctx, "## go news\n  go 1.26 is out", "Go retry", nil)
+	if !errors.Is(err, ErrDuplicateBriefing) || id != first {
+		t.Errorf("expected duplicate of %d, got id=%d err=%v", first, id, err)
+	}
+	if _, err := db.AddBriefing(ctx, "## Go News\n\nGo 1.27 is out!", "Go", nil); err != nil {
+		t.Errorf("expected a different report to be saved, got %v", err)
+	}
+
+	// Each session keeps its own copy of a report a job or another session
+	// already has, and is only told about duplicates of its own.
+	own, err := db.AddSessionBriefing(ctx, "telegram-1", "## Go News\n\nGo 1.26 is out!", "Go", nil)
+	if err != nil || own == first {
+		t.Errorf("expected a session copy of a shared report, got id=%d err=%v", own, err)
+	}
+	if id, err := db.AddSessionBriefing(ctx, "telegram-1", "go news: go 1.26 is out", "Go", nil); !errors.Is(err, ErrDuplicateBriefing) || id != own {
+		t.Errorf("expected duplicate of the session's %d, got id=%d err=%v", own, id, err)
+	}
+	if _, err := db.AddSessionBriefing(ctx, "discord-2", "## Go News\n\nGo 1.26 is out!", "Go", nil); err != nil {
+		t.Errorf("expected another session's copy to be saved, got %v", err)
+	}
+
+	// Briefings stored before hashing are backfilled on migrate.
+	if _, err := db.ExecContext(ctx, `UPDATE briefings SET content_hash = ''`); err != nil {
+		t.Fatalf("failed to clear hashes: %v", err)
+	}
+	if err := db.migrate(); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if _, err := db.AddBriefing(ctx, "Go news: Go 1.26 is out.", "", nil); !errors.Is(err, ErrDuplicateBriefing) {
+		t.Errorf("expected backfilled hash to catch the duplicate, got %v", err)
+	}
+}
+
+func TestSummaryVersions(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	for _, s := range []string{"Planning a trip", "Planning a trip to Japan", "User is a cat"} {
+		if err := db.SaveSessionSummary(ctx, "s1", s); err != nil {
+			t.Fatalf("SaveSessionSummary failed: %v", err)
+		}
+	}
+	_ = db.SaveSessionSummary(ctx, "s2", "Other session")
+
+	versions, err := db.ListSummaryVersions(ctx, "s1", 0)
+	if err != nil {
+		t.Fatalf("ListSummaryVersions failed: %v", err)
+	}
+	if len(versions) != 3 || versions[0].Summary != "User is a cat" || versions[2].Summary != "Planning a trip" || versions[0].Source != SummaryCompressed {
+		t.Fatalf("expected three versions newest first, got %+v", versions)
+	}
+
+	if ok, err := db.RollbackSessionSummary(ctx, "s2", versions[1].ID); err != nil || ok {
+		t.Errorf("expected rollback to another session's version to find nothing, got ok=%v err=%v", ok, err)
+	}
+	if ok, err := db.RollbackSessionSummary(ctx, "s1", versions[1].ID); err != nil || !ok {
+		t.Fatalf("RollbackSessionSummary failed: ok=%v err=%v", ok, err)
+	}
+	if summary, _ := db.GetSessionSummary(ctx, "s1"); summary != "Planning a trip to Japan" {
+		t.Errorf("expected the rolled-back summary, got %q", summary)
+	}
+	versions, _ = db.ListSummaryVersions(ctx, "s1", 0)
+	if len(versions) != 4 || versions[0].Source != SummaryRollback || versions[0].Summary != "Planning a trip to Japan" {
+		t.Errorf("expected the rollback to be recorded as a version, got %+v", versions)
+	}
+
+	// Only the newest versions are kept.
+	for i := range maxSummaryVersions + 5 {
+		_ = db.SaveSessionSummary(ctx, "s3", strings.Repeat("x", i+1))
+	}
+	if versions, _ := db.ListSummaryVersions(ctx, "s3", 100); len(versions) != maxSummaryVersions {
+		t.Errorf("expected %d versions after pruning, got %d", maxSummaryVersions, len(versions))
+	}
+
+	if err := db.DeleteSessionSummary(ctx, "s1"); err != nil {
+		t.Fatalf("DeleteSessionSummary failed: %v", err)
+	}
+	if versions, _ := db.ListSummaryVersions(ctx, "s1", 0); len(versions) != 0 {
+		t.Errorf("expected history to be deleted with the summary, got %+v", versions)
+	}
+}
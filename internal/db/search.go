@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BriefingMatch is a briefing found by full-text search.
+type BriefingMatch struct {
+	ID        int64
+	Snippet   string // excerpt with matched terms in **bold**
+	CreatedAt time.Time
+}
+
+// HeadlineMatch is a feed headline found by full-text search.
+type HeadlineMatch struct {
+	ID        int64
+	URL       string
+	Title     string
+	Snippet   string // title with matched terms in **bold**
+	CreatedAt time.Time
+}
+
+// SearchBriefings returns the briefings of sessionID and the shared ones
+// (from scheduled jobs) matching every word of query, best match first. An
+// empty sessionID searches the shared briefings alone. An empty query
+// matches nothing.
+func (db *DB) SearchBriefings(ctx context.Context, sessionID, query string, limit int) ([]BriefingMatch, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 5
+	}
+	filter := `t.session_id = ? OR t.session_id = ''`
+	sqlQuery, args := db.dialect.fullTextSearch("briefings", "content", "t.created_at", query, filter, []any{sessionID}, limit)
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search briefings: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []BriefingMatch
+	for rows.Next() {
+		var m BriefingMatch
+		if err := rows.Scan(&m.ID, &m.Snippet, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan briefing match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return matches, nil
+}
+
+// SearchHeadlines returns the feed headlines whose title matches every word
+// of query, best match first. An empty query matches nothing.
+func (db *DB) SearchHeadlines(ctx context.Context, query string, limit int) ([]HeadlineMatch, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	sqlQuery, args := db.dialect.fullTextSearch("headlines", "title", "t.url, t.title, t.created_at", query, "", nil, limit)
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search headlines: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []HeadlineMatch
+	for rows.Next() {
+		var m HeadlineMatch
+		if err := rows.Scan(&m.ID, &m.Snippet, &m.URL, &m.Title, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan headline match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return matches, nil
+}
+
+// TranscriptMatch is a conversation message found by SearchTranscripts.
+type TranscriptMatch struct {
+	ID        int64
+	SessionID string
+	Role      string
+	Snippet   string // excerpt with matched terms in **bold**
+	CreatedAt time.Time
+}
+
+// transcriptScanLimit caps how many recent messages SearchTranscripts
+// decrypts when the transcripts are encrypted and cannot be indexed.
+const transcriptScanLimit = 5000
+
+// SearchTranscripts returns the chat messages of channelID and its threads
+// ("channelID:name") that contain every word of query, best match first.
+// Encrypted transcripts are decrypted and scanned, most recent first, since
+// the full-text index only sees ciphertext. An empty query matches nothing.
+func (db *DB) SearchTranscripts(ctx context.Context, channelID, query string, limit int) ([]TranscriptMatch, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 5
+	}
+	if db.cipher.Enabled() {
+		return db.scanTranscripts(ctx, channelID, query, limit)
+	}
+	filter := `t.session_id = ? OR t.session_id LIKE ? ESCAPE '\'`
+	filterArgs := []any{channelID, escapeLike(channelID) + ":%"}
+	sqlQuery, args := db.dialect.fullTextSearch("transcripts", "content", "t.session_id, t.role, t.created_at", query, filter, filterArgs, limit)
+	rows, err := db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transcripts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var matches []TranscriptMatch
+	for rows.Next() {
+		var m TranscriptMatch
+		if err := rows.Scan(&m.ID, &m.Snippet, &m.SessionID, &m.Role, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transcript match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return matches, nil
+}
+
+func (db *DB) scanTranscripts(ctx context.Context, channelID, query string, limit int) ([]TranscriptMatch, error) {
+	sqlQuery := `
+		SELECT id, session_id, role, content, created_at FROM transcripts
+		WHERE session_id = ? OR session_id LIKE ? ESCAPE '\'
+		ORDER BY id DESC LIMIT ?
+	`
+	rows, err := db.QueryContext(ctx, sqlQuery, channelID, escapeLike(channelID)+":%", transcriptScanLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transcripts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	words := strings.Fields(strings.ToLower(query))
+	var matches []TranscriptMatch
+	for rows.Next() && len(matches) < limit {
+		var (
+			m       TranscriptMatch
+			content string
+		)
+		if err := rows.Scan(&m.ID, &m.SessionID, &m.Role, &content, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transcript message: %w", err)
+		}
+		if content, err = db.cipher.DecryptString(content); err != nil {
+			return nil, fmt.Errorf("failed to decrypt transcript message: %w", err)
+		}
+		if !containsAll(strings.ToLower(content), words) {
+			continue
+		}
+		m.Snippet = markSnippet(content, words)
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return matches, nil
+}
+
+func containsAll(s string, words []string) bool {
+	for _, w := range words {
+		if !strings.Contains(s, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// markSnippet returns about 30 words of text around the first match of
+// words, each match in **bold**, like the FTS snippets.
+func markSnippet(text string, words []string) string {
+	fields := strings.Fields(text)
+	first := 0
+	for i, f := range fields {
+		if containsAny(strings.ToLower(f), words) {
+			first = i
+			break
+		}
+	}
+	start := max(first-8, 0)
+	end := min(start+30, len(fields))
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i, f := range fields[start:end] {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		if containsAny(strings.ToLower(f), words) {
+			f = "**" + f + "**"
+		}
+		sb.WriteString(f)
+	}
+	if end < len(fields) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+func containsAny(s string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/crypt"
+)
+
+func TestSearchBriefings(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	_ = db.SaveBriefing(ctx, "Go 1.25 ships with a new garbage collector and faster maps.")
+	_ = db.SaveBriefing(ctx, "Python 3.14 adds free-threading improvements.")
+	_ = db.SaveBriefing(ctx, "The Go team published a survey about generics.")
+
+	matches, err := db.SearchBriefings(ctx, "", "go garbage", 5)
+	if err != nil {
+		t.Fatalf("SearchBriefings failed: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %+v", matches)
+	}
+	if !strings.Contains(matches[0].Snippet, "**garbage**") {
+		t.Errorf("expected highlighted snippet, got %q", matches[0].Snippet)
+	}
+	if matches[0].CreatedAt.IsZero() {
+		t.Error("expected created_at to be set")
+	}
+
+	// Prefix matching and operator-like input.
+	if matches, _ := db.SearchBriefings(ctx, "", "gener", 5); len(matches) != 1 {
+		t.Errorf("expected prefix match, got %+v", matches)
+	}
+	if _, err := db.SearchBriefings(ctx, "", `"free-threading" OR (`, 5); err != nil {
+		t.Errorf("expected operators to be treated literally, got %v", err)
+	}
+	if matches, _ := db.SearchBriefings(ctx, "", "  ", 5); matches != nil {
+		t.Errorf("expected no matches for empty query, got %+v", matches)
+	}
+}
+
+func TestSearchBriefings_Sessions(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if _, err := db.AddSessionBriefing(ctx, "telegram-1", "Private notes on quantum error correction for chat one.", "quantum", nil); err != nil {
+		t.Fatalf("AddSessionBriefing failed: %v", err)
+	}
+	if _, err := db.AddSessionBriefing(ctx, "discord-2", "Private notes on quantum annealing for chat two.", "quantum", nil); err != nil {
+		t.Fatalf("AddSessionBriefing failed: %v", err)
+	}
+	if _, err := db.AddBriefing(ctx, "Nightly quantum computing news for everyone.", "nightly", nil); err != nil {
+		t.Fatalf("AddBriefing failed: %v", err)
+	}
+
+	for session, want := range map[string][]string{
+		"telegram-1": {"error correction", "Nightly"},
+		"discord-2":  {"annealing", "Nightly"},
+		"":           {"Nightly"},
+	} {
+		matches, err := db.SearchBriefings(ctx, session, "quantum", 10)
+		if err != nil {
+			t.Fatalf("SearchBriefings(%q) failed: %v", session, err)
+		}
+		if len(matches) != len(want) {
+			t.Errorf("SearchBriefings(%q) = %+v, want %d matches", session, matches, len(want))
+			continue
+		}
+		var all strings.Builder
+		for _, m := range matches {
+			all.WriteString(m.Snippet + "\n")
+		}
+		for _, w := range want {
+			if !strings.Contains(all.String(), w) {
+				t.Errorf("SearchBriefings(%q) misses %q: %s", session, w, all.String())
+			}
+		}
+	}
+}
+
+func TestSearchHeadlines(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	_, _ = db.AddHeadline(ctx, "https://example.com/a", "SQLite adds JSONB support")
+	_, _ = db.AddHeadline(ctx, "https://example.com/b", "PostgreSQL 18 released")
+
+	matches, err := db.SearchHeadlines(ctx, "sqlite", 10)
+	if err != nil {
+		t.Fatalf("SearchHeadlines failed: %v", err)
+	}
+	if len(matches) != 1 || matches[0].URL != "https://example.com/a" || matches[0].Title != "SQLite adds JSONB support" {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+	if !strings.Contains(matches[0].Snippet, "**SQLite**") {
+		t.Errorf("expected highlighted title, got %q", matches[0].Snippet)
+	}
+}
+
+func TestFullTextBackfill(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	// Simulate a database created before the search index existed.
+	for _, stmt := range []string{
+		`DROP TRIGGER briefings_fts_ai`, `DROP TRIGGER briefings_fts_ad`, `DROP TRIGGER briefings_fts_au`, `DROP TABLE briefings_fts`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("failed to drop index: %v", err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO briefings (content) VALUES ('Raspberry Pi 5 thermals')`); err != nil {
+		t.Fatalf("failed to insert briefing: %v", err)
+	}
+	if err := db.migrate(); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if matches, _ := db.SearchBriefings(ctx, "", "raspberry", 5); len(matches) != 1 {
+		t.Errorf("expected existing briefing to be indexed, got %+v", matches)
+	}
+}
+
+func TestSearchTranscripts(t *testing.T) {
+	t.Parallel()
+	for _, encrypted := range []bool{false, true} {
+		db := setupTestDB(t)
+		defer func() { _ = db.Close() }()
+		ctx := context.Background()
+		if encrypted {
+			c, err := crypt.New([]byte(strings.Repeat("k", crypt.KeySize)))
+			if err != nil {
+				t.Fatalf("crypt.New failed: %v", err)
+			}
+			db.SetCipher(c)
+		}
+
+		_ = db.AddTranscriptMessage(ctx, "telegram-1", RoleUser, "How do I upsert in SQLite?")
+		_ = db.AddTranscriptMessage(ctx, "telegram-1", RoleAssistant, "Use INSERT ... ON CONFLICT DO UPDATE, the SQL upsert trick.")
+		_ = db.AddTranscriptMessage(ctx, "telegram-1:work", RoleAssistant, "Another SQL trick: window functions.")
+		_ = db.AddTranscriptMessage(ctx, "telegram-11", RoleAssistant, "A SQL trick for someone else.")
+
+		matches, err := db.SearchTranscripts(ctx, "telegram-1", "sql trick", 5)
+		if err != nil {
+			t.Fatalf("SearchTranscripts(encrypted=%v) failed: %v", encrypted, err)
+		}
+		if len(matches) != 2 {
+			t.Fatalf("encrypted=%v: expected 2 matches in the chat and its threads, got %+v", encrypted, matches)
+		}
+		sessions := map[string]bool{}
+		for _, m := range matches {
+			sessions[m.SessionID] = true
+			if m.Role != RoleAssistant || !strings.Contains(m.Snippet, "**trick") {
+				t.Errorf("encrypted=%v: unexpected match %+v", encrypted, m)
+			}
+		}
+		if !sessions["telegram-1"] || !sessions["telegram-1:work"] {
+			t.Errorf("encrypted=%v: expected matches from both threads, got %+v", encrypted, matches)
+		}
+
+		if matches, _ := db.SearchTranscripts(ctx, "telegram-1", "upsert", 1); len(matches) != 1 {
+			t.Errorf("encrypted=%v: expected limit to apply, got %+v", encrypted, matches)
+		}
+		if matches, _ := db.SearchTranscripts(ctx, "telegram-1", " ", 5); matches != nil {
+			t.Errorf("encrypted=%v: expected no matches for empty query, got %+v", encrypted, matches)
+		}
+	}
+}
+
+func TestMarkSnippet(t *testing.T) {
+	t.Parallel()
+	text := strings.Repeat("filler ", 20) + "the SQL trick\nworks " + strings.Repeat("tail ", 40)
+	got := markSnippet(text, []string{"sql", "trick"})
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("expected a trimmed excerpt, got %q", got)
+	}
+	if !strings.Contains(got, "the **SQL** **trick** works") {
+		t.Errorf("expected highlighted terms, got %q", got)
+	}
+}
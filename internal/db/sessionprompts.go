@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Modes of a session prompt.
+const (
+	// PromptAppend adds the session prompt after the configured system prompt.
+	PromptAppend = "append"
+	// PromptReplace uses the session prompt instead of the configured one.
+	PromptReplace = "replace"
+)
+
+// SessionPrompt is a conversation's own system prompt, set with /prompt set.
+type SessionPrompt struct {
+	Prompt    string
+	Mode      string // PromptAppend or PromptReplace
+	UpdatedAt time.Time
+}
+
+// GetSessionPrompt returns the session's prompt, or nil if it uses the
+// configured system prompt alone.
+func (db *DB) GetSessionPrompt(ctx context.Context, sessionID string) (*SessionPrompt, error) {
+	var p SessionPrompt
+	query := `SELECT prompt, mode, updated_at FROM session_prompts WHERE session_id = ?`
+	err := db.QueryRowContext(ctx, query, sessionID).Scan(&p.Prompt, &p.Mode, &p.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session prompt: %w", err)
+	}
+	if p.Prompt, err = db.cipher.DecryptString(p.Prompt); err != nil {
+		return nil, fmt.Errorf("failed to decrypt session prompt: %w", err)
+	}
+	return &p, nil
+}
+
+// SetSessionPrompt stores the session's prompt, replacing any earlier one.
+// mode is PromptAppend or PromptReplace.
+func (db *DB) SetSessionPrompt(ctx context.Context, sessionID, prompt, mode string) error {
+	if mode != PromptAppend && mode != PromptReplace {
+		return fmt.Errorf("invalid session prompt mode %q", mode)
+	}
+	stored, err := db.cipher.EncryptString(prompt)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt session prompt: %w", err)
+	}
+	query := `
+		INSERT INTO session_prompts (session_id, prompt, mode, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			prompt = excluded.prompt,
+			mode = excluded.mode,
+			updated_at = excluded.updated_at
+	`
+	if _, err := db.ExecContext(ctx, query, sessionID, stored, mode, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to set session prompt: %w", err)
+	}
+	return nil
+}
+
+// DeleteSessionPrompt reverts the session to the configured system prompt.
+// It reports whether the session had a prompt of its own.
+func (db *DB) DeleteSessionPrompt(ctx context.Context, sessionID string) (bool, error) {
+	res, err := db.ExecContext(ctx, `DELETE FROM session_prompts WHERE session_id = ?`, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to clear session prompt: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to clear session prompt: %w", err)
+	}
+	return n > 0, nil
+}
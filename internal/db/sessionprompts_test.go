@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSessionPrompt(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if p, err := db.GetSessionPrompt(ctx, "session-a"); err != nil || p != nil {
+		t.Fatalf("expected no prompt, got %+v err=%v", p, err)
+	}
+	if err := db.SetSessionPrompt(ctx, "session-a", "Answer like a pirate.", PromptAppend); err != nil {
+		t.Fatalf("SetSessionPrompt failed: %v", err)
+	}
+	if err := db.SetSessionPrompt(ctx, "session-a", "You are a terse SRE.", PromptReplace); err != nil {
+		t.Fatalf("SetSessionPrompt update failed: %v", err)
+	}
+	p, err := db.GetSessionPrompt(ctx, "session-a")
+	if err != nil || p == nil {
+		t.Fatalf("GetSessionPrompt failed: %+v err=%v", p, err)
+	}
+	if p.Prompt != "You are a terse SRE." || p.Mode != PromptReplace || p.UpdatedAt.IsZero() {
+		t.Errorf("expected the updated prompt, got %+v", p)
+	}
+	if p, _ := db.GetSessionPrompt(ctx, "session-b"); p != nil {
+		t.Errorf("expected other session to be unaffected, got %+v", p)
+	}
+	if err := db.SetSessionPrompt(ctx, "session-a", "x", "prepend"); err == nil {
+		t.Error("expected an invalid mode to be rejected")
+	}
+
+	if ok, err := db.DeleteSessionPrompt(ctx, "session-a"); err != nil || !ok {
+		t.Fatalf("DeleteSessionPrompt = %v, %v; want true", ok, err)
+	}
+	if ok, _ := db.DeleteSessionPrompt(ctx, "session-a"); ok {
+		t.Error("expected a second delete to report no prompt")
+	}
+	if p, _ := db.GetSessionPrompt(ctx, "session-a"); p != nil {
+		t.Errorf("expected cleared prompt, got %+v", p)
+	}
+}
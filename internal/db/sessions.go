@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ChatSession is one conversation thread of a chat channel. The channel's
+// default thread has the channel ID as its session ID.
+type ChatSession struct {
+	ID         string
+	ChannelID  string
+	Name       string
+	Summary    string // compressed summary, or the latest user message
+	CreatedAt  time.Time
+	LastActive time.Time
+}
+
+// TouchChatSession records activity in a thread, creating it if needed.
+func (db *DB) TouchChatSession(ctx context.Context, channelID, sessionID, name string) error {
+	query := `
+		INSERT INTO chat_sessions (id, channel_id, name) VALUES (?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET last_active = CURRENT_TIMESTAMP
+	`
+	if _, err := db.ExecContext(ctx, query, sessionID, channelID, name); err != nil {
+		return fmt.Errorf("failed to touch chat session: %w", err)
+	}
+	return nil
+}
+
+// GetChatSession returns a channel's thread by name, or nil if it does not
+// exist.
+func (db *DB) GetChatSession(ctx context.Context, channelID, name string) (*ChatSession, error) {
+	query := `SELECT id, channel_id, name, created_at, last_active FROM chat_sessions WHERE channel_id = ? AND name = ?`
+	var s ChatSession
+	err := db.QueryRowContext(ctx, query, channelID, name).Scan(&s.ID, &s.ChannelID, &s.Name, &s.CreatedAt, &s.LastActive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get chat session: %w", err)
+	}
+	return &s, nil
+}
+
+// ListChatSessions returns a channel's threads, most recently active first.
+func (db *DB) ListChatSessions(ctx context.Context, channelID string) ([]ChatSession, error) {
+	query := `
+		SELECT c.id, c.channel_id, c.name, c.created_at, c.last_active,
+			COALESCE(s.summary, (
+				SELECT t.content FROM transcripts t
+				WHERE t.session_id = c.id AND t.role = 'user'
+				ORDER BY t.id DESC LIMIT 1
+			), '')
+		FROM chat_sessions c
+		LEFT JOIN session_summaries s ON s.session_id = c.id
+		WHERE c.channel_id = ?
+		ORDER BY c.last_active DESC, c.created_at DESC
+	`
+	rows, err := db.QueryContext(ctx, query, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []ChatSession
+	for rows.Next() {
+		var s ChatSession
+		if err := rows.Scan(&s.ID, &s.ChannelID, &s.Name, &s.CreatedAt, &s.LastActive, &s.Summary); err != nil {
+			return nil, fmt.Errorf("failed to scan chat session: %w", err)
+		}
+		if s.Summary, err = db.cipher.DecryptString(s.Summary); err != nil {
+			return nil, fmt.Errorf("failed to decrypt chat session summary: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return sessions, nil
+}
+
+// GetActiveSession returns the thread a channel is currently using, or ""
+// for its default thread.
+func (db *DB) GetActiveSession(ctx context.Context, channelID string) (string, error) {
+	var id string
+	err := db.QueryRowContext(ctx, `SELECT session_id FROM active_sessions WHERE channel_id = ?`, channelID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get active session: %w", err)
+	}
+	return id, nil
+}
+
+// SetActiveSession switches the channel to a thread.
+func (db *DB) SetActiveSession(ctx context.Context, channelID, sessionID string) error {
+	query := `
+		INSERT INTO active_sessions (channel_id, session_id) VALUES (?, ?)
+		ON CONFLICT(channel_id) DO UPDATE SET session_id = excluded.session_id
+	`
+	if _, err := db.ExecContext(ctx, query, channelID, sessionID); err != nil {
+		return fmt.Errorf("failed to set active session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChatSessions(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if err := db.TouchChatSession(ctx, "telegram-1", "telegram-1", "main"); err != nil {
+		t.Fatalf("TouchChatSession failed: %v", err)
+	}
+	if err := db.TouchChatSession(ctx, "telegram-1", "telegram-1:research", "research"); err != nil {
+		t.Fatalf("TouchChatSession failed: %v", err)
+	}
+	// Touching again must not duplicate the thread.
+	_ = db.TouchChatSession(ctx, "telegram-1", "telegram-1", "main")
+	_ = db.TouchChatSession(ctx, "telegram-2", "telegram-2", "main")
+
+	_ = db.SaveSessionSummary(ctx, "telegram-1", "Planning the Go upgrade")
+	_ = db.AddTranscriptMessage(ctx, "telegram-1:research", RoleUser, "compare vector databases")
+
+	sessions, err := db.ListChatSessions(ctx, "telegram-1")
+	if err != nil {
+		t.Fatalf("ListChatSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %+v", sessions)
+	}
+	summaries := map[string]string{}
+	for _, s := range sessions {
+		summaries[s.Name] = s.Summary
+	}
+	if summaries["main"] != "Planning the Go upgrade" || summaries["research"] != "compare vector databases" {
+		t.Errorf("unexpected summaries: %+v", summaries)
+	}
+
+	s, err := db.GetChatSession(ctx, "telegram-1", "research")
+	if err != nil || s == nil || s.ID != "telegram-1:research" {
+		t.Fatalf("GetChatSession failed: %+v err=%v", s, err)
+	}
+	if s, _ := db.GetChatSession(ctx, "telegram-2", "research"); s != nil {
+		t.Errorf("expected no research thread in another channel, got %+v", s)
+	}
+
+	if id, err := db.GetActiveSession(ctx, "telegram-1"); err != nil || id != "" {
+		t.Fatalf("expected no active session, got %q err=%v", id, err)
+	}
+	_ = db.SetActiveSession(ctx, "telegram-1", "telegram-1:research")
+	_ = db.SetActiveSession(ctx, "telegram-1", "telegram-1")
+	if id, _ := db.GetActiveSession(ctx, "telegram-1"); id != "telegram-1" {
+		t.Errorf("expected active session to be updated, got %q", id)
+	}
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// Settings returns the runtime settings stored with SetSetting, keyed by
+// name. They are cached in memory, as they are read on every message.
+func (db *DB) Settings(ctx context.Context) (map[string]string, error) {
+	db.settingsMu.Lock()
+	defer db.settingsMu.Unlock()
+	if db.settings == nil {
+		rows, err := db.QueryContext(ctx, `SELECT key, value FROM settings`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load settings: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+		settings := make(map[string]string)
+		for rows.Next() {
+			var key, value string
+			if err := rows.Scan(&key, &value); err != nil {
+				return nil, fmt.Errorf("failed to scan setting: %w", err)
+			}
+			settings[key] = value
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("rows error: %w", err)
+		}
+		db.settings = settings
+	}
+	return maps.Clone(db.settings), nil
+}
+
+// SetSetting stores a runtime setting, replacing any previous value.
+func (db *DB) SetSetting(ctx context.Context, key, value string) error {
+	db.settingsMu.Lock()
+	defer db.settingsMu.Unlock()
+	query := `
+		INSERT INTO settings (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := db.ExecContext(ctx, query, key, value); err != nil {
+		return fmt.Errorf("failed to save setting %s: %w", key, err)
+	}
+	db.settings = nil
+	return nil
+}
+
+// DeleteSetting removes a runtime setting. It reports whether one was set.
+func (db *DB) DeleteSetting(ctx context.Context, key string) (bool, error) {
+	db.settingsMu.Lock()
+	defer db.settingsMu.Unlock()
+	res, err := db.ExecContext(ctx, `DELETE FROM settings WHERE key = ?`, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete setting %s: %w", key, err)
+	}
+	db.settings = nil
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete setting %s: %w", key, err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,334 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StateVersion is the format version of exported state. ImportState rejects
+// archives from newer versions.
+const StateVersion = 1
+
+// State is a portable copy of the bot's user data, independent of the
+// storage backend. Session summaries are stored in plaintext, so the archive
+// can be imported with a different encryption key (or none).
+type State struct {
+	Version       int                 `json:"version"`
+	ExportedAt    time.Time           `json:"exported_at"`
+	Driver        string              `json:"driver"`
+	Briefings     []StateBriefing     `json:"briefings"`
+	Reminders     []StateReminder     `json:"reminders"`
+	Memories      []StateMemory       `json:"memories"`
+	Subscriptions []StateSubscription `json:"subscriptions"`
+	Tasks         []StateTask         `json:"tasks"`
+	Summaries     []StateSummary      `json:"session_summaries"`
+}
+
+// StateBriefing is an exported briefing.
+type StateBriefing struct {
+	SessionID string    `json:"session_id,omitempty"` // empty for shared briefings
+	Content   string    `json:"content"`
+	Topic     string    `json:"topic,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StateReminder is an exported reminder, delivered or not.
+type StateReminder struct {
+	SessionID  string    `json:"session_id"`
+	Message    string    `json:"message"`
+	RemindAt   time.Time `json:"remind_at"`
+	Delivered  bool      `json:"delivered,omitempty"`
+	Recurrence string    `json:"recurrence,omitempty"`
+	Platform   string    `json:"platform,omitempty"`
+	ChannelID  string    `json:"channel_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// StateMemory is an exported remembered fact.
+type StateMemory struct {
+	SessionID string    `json:"session_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StateSubscription is an exported feed subscription.
+type StateSubscription struct {
+	SessionID string    `json:"session_id"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StateTask is an exported todo item.
+type StateTask struct {
+	SessionID   string     `json:"session_id"`
+	Title       string     `json:"title"`
+	Done        bool       `json:"done,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// StateSummary is an exported session summary (its current version only).
+type StateSummary struct {
+	SessionID string    `json:"session_id"`
+	Summary   string    `json:"summary"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ImportResult counts the rows ImportState added per kind, and the rows it
+// skipped because they were already present.
+type ImportResult struct {
+	Briefings     int
+	Reminders     int
+	Memories      int
+	Subscriptions int
+	Tasks         int
+	Summaries     int
+	Skipped       int
+}
+
+// ExportState reads all briefings, reminders, memories, subscriptions, tasks
+// and session summaries, oldest first. Encrypted summaries are decrypted, so
+// the database's key must be set.
+func (db *DB) ExportState(ctx context.Context) (*State, error) {
+	s := &State{Version: StateVersion, ExportedAt: time.Now().UTC(), Driver: db.Driver()}
+
+	err := db.exportRows(ctx, "briefings", `SELECT session_id, content, topic, tags, created_at FROM briefings ORDER BY id`, func(rows *sql.Rows) error {
+		var b StateBriefing
+		var tags string
+		if err := rows.Scan(&b.SessionID, &b.Content, &b.Topic, &tags, &b.CreatedAt); err != nil {
+			return err
+		}
+		b.Tags = splitTags(tags)
+		s.Briefings = append(s.Briefings, b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.exportRows(ctx, "reminders", `SELECT session_id, message, remind_at, delivered, recurrence, platform, channel_id, created_at FROM reminders ORDER BY id`, func(rows *sql.Rows) error {
+		var r StateReminder
+		var delivered int
+		if err := rows.Scan(&r.SessionID, &r.Message, &r.RemindAt, &delivered, &r.Recurrence, &r.Platform, &r.ChannelID, &r.CreatedAt); err != nil {
+			return err
+		}
+		r.Delivered = delivered != 0
+		s.Reminders = append(s.Reminders, r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.exportRows(ctx, "memories", `SELECT session_id, content, created_at FROM memories ORDER BY id`, func(rows *sql.Rows) error {
+		var m StateMemory
+		if err := rows.Scan(&m.SessionID, &m.Content, &m.CreatedAt); err != nil {
+			return err
+		}
+		s.Memories = append(s.Memories, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.exportRows(ctx, "subscriptions", `SELECT session_id, url, created_at FROM subscriptions ORDER BY id`, func(rows *sql.Rows) error {
+		var sub StateSubscription
+		if err := rows.Scan(&sub.SessionID, &sub.URL, &sub.CreatedAt); err != nil {
+			return err
+		}
+		s.Subscriptions = append(s.Subscriptions, sub)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.exportRows(ctx, "tasks", `SELECT session_id, title, done, created_at, completed_at FROM tasks ORDER BY id`, func(rows *sql.Rows) error {
+		var t StateTask
+		var done int
+		var completed sql.NullTime
+		if err := rows.Scan(&t.SessionID, &t.Title, &done, &t.CreatedAt, &completed); err != nil {
+			return err
+		}
+		t.Done = done != 0
+		if completed.Valid {
+			t.CompletedAt = &completed.Time
+		}
+		s.Tasks = append(s.Tasks, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.exportRows(ctx, "session summaries", `SELECT session_id, summary, updated_at FROM session_summaries ORDER BY session_id`, func(rows *sql.Rows) error {
+		var sum StateSummary
+		if err := rows.Scan(&sum.SessionID, &sum.Summary, &sum.UpdatedAt); err != nil {
+			return err
+		}
+		plain, err := db.cipher.DecryptString(sum.Summary)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt summary of %s: %w", sum.SessionID, err)
+		}
+		sum.Summary = plain
+		s.Summaries = append(s.Summaries, sum)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (db *DB) exportRows(ctx context.Context, what, query string, scan func(*sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", what, err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return fmt.Errorf("failed to export %s: %w", what, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export %s: %w", what, err)
+	}
+	return nil
+}
+
+// ImportState adds the rows of s in a single transaction. Rows that already
+// exist are skipped (briefings by content hash, the others by their content
+// within the session), so importing the same archive twice is harmless.
+// Existing session summaries are kept; imported ones are recorded as a
+// SummaryImported version and encrypted with the database's key, if set.
+func (db *DB) ImportState(ctx context.Context, s *State) (ImportResult, error) {
+	var res ImportResult
+	if s.Version > StateVersion {
+		return res, fmt.Errorf("state version %d is newer than supported version %d", s.Version, StateVersion)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, fmt.Errorf("failed to begin import: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// add inserts a row unless the exists query finds a match, and reports
+	// whether it did.
+	add := func(exists string, existsArgs []any, insert string, insertArgs ...any) (bool, error) {
+		var found bool
+		if err := tx.QueryRowContext(ctx, db.dialect.rebind(`SELECT EXISTS(`+exists+`)`), existsArgs...).Scan(&found); err != nil {
+			return false, err
+		}
+		if found {
+			res.Skipped++
+			return false, nil
+		}
+		_, err := tx.ExecContext(ctx, db.dialect.rebind(insert), insertArgs...)
+		return err == nil, err
+	}
+
+	for _, b := range s.Briefings {
+		hash := ContentHash(b.Content)
+		ok, err := add(`SELECT 1 FROM briefings WHERE content_hash = ?`, []any{hash},
+			`INSERT INTO briefings (session_id, content, topic, tags, content_hash, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			b.SessionID, b.Content, b.Topic, joinTags(NormalizeTags(b.Tags)), hash, b.CreatedAt.UTC())
+		if err != nil {
+			return res, fmt.Errorf("failed to import briefing: %w", err)
+		}
+		if ok {
+			res.Briefings++
+		}
+	}
+
+	for _, r := range s.Reminders {
+		delivered := 0
+		if r.Delivered {
+			delivered = 1
+		}
+		ok, err := add(`SELECT 1 FROM reminders WHERE session_id = ? AND message = ? AND remind_at = ?`, []any{r.SessionID, r.Message, r.RemindAt.UTC()},
+			`INSERT INTO reminders (session_id, message, remind_at, delivered, recurrence, platform, channel_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			r.SessionID, r.Message, r.RemindAt.UTC(), delivered, r.Recurrence, r.Platform, r.ChannelID, r.CreatedAt.UTC())
+		if err != nil {
+			return res, fmt.Errorf("failed to import reminder: %w", err)
+		}
+		if ok {
+			res.Reminders++
+		}
+	}
+
+	for _, m := range s.Memories {
+		ok, err := add(`SELECT 1 FROM memories WHERE session_id = ? AND content = ?`, []any{m.SessionID, m.Content},
+			`INSERT INTO memories (session_id, content, created_at) VALUES (?, ?, ?)`,
+			m.SessionID, m.Content, m.CreatedAt.UTC())
+		if err != nil {
+			return res, fmt.Errorf("failed to import memory: %w", err)
+		}
+		if ok {
+			res.Memories++
+		}
+	}
+
+	for _, sub := range s.Subscriptions {
+		ok, err := add(`SELECT 1 FROM subscriptions WHERE session_id = ? AND url = ?`, []any{sub.SessionID, sub.URL},
+			`INSERT INTO subscriptions (session_id, url, created_at) VALUES (?, ?, ?)`,
+			sub.SessionID, sub.URL, sub.CreatedAt.UTC())
+		if err != nil {
+			return res, fmt.Errorf("failed to import subscription: %w", err)
+		}
+		if ok {
+			res.Subscriptions++
+		}
+	}
+
+	for _, t := range s.Tasks {
+		done := 0
+		if t.Done {
+			done = 1
+		}
+		var completed any
+		if t.CompletedAt != nil {
+			completed = t.CompletedAt.UTC()
+		}
+		ok, err := add(`SELECT 1 FROM tasks WHERE session_id = ? AND title = ? AND done = ?`, []any{t.SessionID, t.Title, done},
+			`INSERT INTO tasks (session_id, title, done, created_at, completed_at) VALUES (?, ?, ?, ?, ?)`,
+			t.SessionID, t.Title, done, t.CreatedAt.UTC(), completed)
+		if err != nil {
+			return res, fmt.Errorf("failed to import task: %w", err)
+		}
+		if ok {
+			res.Tasks++
+		}
+	}
+
+	for _, sum := range s.Summaries {
+		stored, err := db.cipher.EncryptString(sum.Summary)
+		if err != nil {
+			return res, fmt.Errorf("failed to encrypt summary of %s: %w", sum.SessionID, err)
+		}
+		ok, err := add(`SELECT 1 FROM session_summaries WHERE session_id = ?`, []any{sum.SessionID},
+			`INSERT INTO session_summaries (session_id, summary, updated_at) VALUES (?, ?, ?)`,
+			sum.SessionID, stored, sum.UpdatedAt.UTC())
+		if err != nil {
+			return res, fmt.Errorf("failed to import summary of %s: %w", sum.SessionID, err)
+		}
+		if !ok {
+			continue
+		}
+		query := db.dialect.rebind(`INSERT INTO session_summary_versions (session_id, summary, source, created_at) VALUES (?, ?, ?, ?)`)
+		if _, err := tx.ExecContext(ctx, query, sum.SessionID, stored, SummaryImported, time.Now().UTC()); err != nil {
+			return res, fmt.Errorf("failed to import summary of %s: %w", sum.SessionID, err)
+		}
+		res.Summaries++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return res, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/crypt"
+)
+
+func TestExportImportState(t *testing.T) {
+	t.Parallel()
+	src := setupTestDB(t)
+	defer func() { _ = src.Close() }()
+	ctx := context.Background()
+	remindAt := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+
+	if _, err := src.AddBriefing(ctx, "Go 1.26 released", "go-news", []string{"Go"}); err != nil {
+		t.Fatalf("AddBriefing failed: %v", err)
+	}
+	if err := src.SaveReminder(ctx, Reminder{SessionID: "s1", Message: "renew cert", RemindAt: remindAt, Recurrence: "FREQ=YEARLY", Platform: "Telegram", ChannelID: "42"}); err != nil {
+		t.Fatalf("SaveReminder failed: %v", err)
+	}
+	if _, err := src.AddMemory(ctx, "s1", "Prefers tabs"); err != nil {
+		t.Fatalf("AddMemory failed: %v", err)
+	}
+	if _, err := src.AddSubscription(ctx, "s1", "https://go.dev/blog/feed.atom"); err != nil {
+		t.Fatalf("AddSubscription failed: %v", err)
+	}
+	id, err := src.AddTask(ctx, "s1", "Upgrade Go")
+	if err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	if _, err := src.CompleteTask(ctx, "s1", id); err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	if err := src.SaveSessionSummary(ctx, "s1", "Discussed the Go upgrade."); err != nil {
+		t.Fatalf("SaveSessionSummary failed: %v", err)
+	}
+
+	state, err := src.ExportState(ctx)
+	if err != nil {
+		t.Fatalf("ExportState failed: %v", err)
+	}
+	if state.Version != StateVersion || len(state.Briefings) != 1 || len(state.Reminders) != 1 || len(state.Memories) != 1 ||
+		len(state.Subscriptions) != 1 || len(state.Tasks) != 1 || len(state.Summaries) != 1 {
+		t.Fatalf("unexpected export: %+v", state)
+	}
+	if got := state.Briefings[0].Tags; len(got) != 1 || got[0] != "go" {
+		t.Errorf("expected briefing tags [go], got %v", got)
+	}
+	if !state.Tasks[0].Done || state.Tasks[0].CompletedAt == nil {
+		t.Errorf("expected completed task, got %+v", state.Tasks[0])
+	}
+
+	dst := setupTestDB(t)
+	defer func() { _ = dst.Close() }()
+	c, err := crypt.New([]byte(strings.Repeat("k", crypt.KeySize)))
+	if err != nil {
+		t.Fatalf("crypt.New failed: %v", err)
+	}
+	dst.SetCipher(c)
+
+	res, err := dst.ImportState(ctx, state)
+	if err != nil {
+		t.Fatalf("ImportState failed: %v", err)
+	}
+	want := ImportResult{Briefings: 1, Reminders: 1, Memories: 1, Subscriptions: 1, Tasks: 1, Summaries: 1}
+	if res != want {
+		t.Errorf("ImportState = %+v, want %+v", res, want)
+	}
+
+	reminders, err := dst.GetSessionReminders(ctx, "s1")
+	if err != nil || len(reminders) != 1 {
+		t.Fatalf("expected 1 reminder, got %+v err=%v", reminders, err)
+	}
+	if r := reminders[0]; !r.RemindAt.Equal(remindAt) || r.Recurrence != "FREQ=YEARLY" || r.Platform != "Telegram" || r.ChannelID != "42" {
+		t.Errorf("reminder not restored: %+v", r)
+	}
+	if summary, err := dst.GetSessionSummary(ctx, "s1"); err != nil || summary != "Discussed the Go upgrade." {
+		t.Errorf("expected restored summary, got %q err=%v", summary, err)
+	}
+	var stored string
+	_ = dst.QueryRow(`SELECT summary FROM session_summaries WHERE session_id = 's1'`).Scan(&stored)
+	if !crypt.IsEncrypted(stored) {
+		t.Errorf("expected imported summary to be encrypted, got %q", stored)
+	}
+	if versions, err := dst.ListSummaryVersions(ctx, "s1", 0); err != nil || len(versions) != 1 || versions[0].Source != SummaryImported {
+		t.Errorf("expected one imported summary version, got %+v err=%v", versions, err)
+	}
+	if matches, err := dst.SearchBriefings(ctx, "", "released", 5); err != nil || len(matches) != 1 {
+		t.Errorf("expected imported briefing to be searchable, got %+v err=%v", matches, err)
+	}
+
+	// Importing the same archive again adds nothing.
+	res, err = dst.ImportState(ctx, state)
+	if err != nil {
+		t.Fatalf("second ImportState failed: %v", err)
+	}
+	if res != (ImportResult{Skipped: 6}) {
+		t.Errorf("expected everything skipped, got %+v", res)
+	}
+
+	state.Version = StateVersion + 1
+	if _, err := dst.ImportState(ctx, state); err == nil {
+		t.Error("expected an error for a newer state version")
+	}
+}
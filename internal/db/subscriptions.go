@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Subscription is an RSS/Atom feed a session follows for digests.
+type Subscription struct {
+	ID        int64
+	SessionID string
+	URL       string
+	CreatedAt time.Time
+}
+
+// AddSubscription subscribes the session to a feed, adding it to the feed
+// registry. It reports false if the session was already subscribed.
+func (db *DB) AddSubscription(ctx context.Context, sessionID, url string) (bool, error) {
+	if err := db.RegisterFeeds(ctx, url); err != nil {
+		return false, err
+	}
+	query := `INSERT INTO subscriptions (session_id, url) VALUES (?, ?) ON CONFLICT(session_id, url) DO NOTHING`
+	res, err := db.ExecContext(ctx, query, sessionID, url)
+	if err != nil {
+		return false, fmt.Errorf("failed to add subscription: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add subscription: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ListSubscriptions returns the session's feeds, oldest first.
+func (db *DB) ListSubscriptions(ctx context.Context, sessionID string) ([]Subscription, error) {
+	query := `SELECT id, session_id, url, created_at FROM subscriptions WHERE session_id = ? ORDER BY id ASC`
+	rows, err := db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var subs []Subscription
+	for rows.Next() {
+		var s Subscription
+		if err := rows.Scan(&s.ID, &s.SessionID, &s.URL, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		subs = append(subs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return subs, nil
+}
+
+// RemoveSubscription unsubscribes the session from a feed, identified either
+// by its ID or its URL. It reports whether a subscription was removed.
+func (db *DB) RemoveSubscription(ctx context.Context, sessionID string, id int64, url string) (bool, error) {
+	query := `DELETE FROM subscriptions WHERE session_id = ? AND (id = ? OR url = ?)`
+	res, err := db.ExecContext(ctx, query, sessionID, id, url)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove subscription: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove subscription: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetFeedURLs returns every subscribed feed URL across all sessions, for
+// scheduled digests.
+func (db *DB) GetFeedURLs(ctx context.Context) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT DISTINCT url FROM subscriptions ORDER BY url ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed urls: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var urls []string
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			return nil, fmt.Errorf("failed to scan feed url: %w", err)
+		}
+		urls = append(urls, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return urls, nil
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSubscriptions(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	added, err := db.AddSubscription(ctx, "session-a", "https://go.dev/blog/feed.atom")
+	if err != nil || !added {
+		t.Fatalf("AddSubscription failed: added=%v err=%v", added, err)
+	}
+	if added, _ := db.AddSubscription(ctx, "session-a", "https://go.dev/blog/feed.atom"); added {
+		t.Error("expected duplicate subscription to be ignored")
+	}
+	_, _ = db.AddSubscription(ctx, "session-a", "https://blog.rust-lang.org/feed.xml")
+	_, _ = db.AddSubscription(ctx, "session-b", "https://go.dev/blog/feed.atom")
+
+	subs, err := db.ListSubscriptions(ctx, "session-a")
+	if err != nil {
+		t.Fatalf("ListSubscriptions failed: %v", err)
+	}
+	if len(subs) != 2 || subs[0].URL != "https://go.dev/blog/feed.atom" {
+		t.Fatalf("expected 2 subscriptions in insertion order, got %+v", subs)
+	}
+
+	urls, err := db.GetFeedURLs(ctx)
+	if err != nil {
+		t.Fatalf("GetFeedURLs failed: %v", err)
+	}
+	if len(urls) != 2 {
+		t.Errorf("expected 2 distinct feed urls, got %v", urls)
+	}
+
+	// Remove by ID, then by URL
+	if removed, _ := db.RemoveSubscription(ctx, "session-b", subs[0].ID, ""); removed {
+		t.Error("expected removal from another session to find nothing")
+	}
+	if removed, err := db.RemoveSubscription(ctx, "session-a", subs[0].ID, ""); err != nil || !removed {
+		t.Fatalf("RemoveSubscription by id failed: removed=%v err=%v", removed, err)
+	}
+	if removed, _ := db.RemoveSubscription(ctx, "session-a", 0, "https://blog.rust-lang.org/feed.xml"); !removed {
+		t.Error("expected removal by url to succeed")
+	}
+	if subs, _ := db.ListSubscriptions(ctx, "session-a"); len(subs) != 0 {
+		t.Errorf("expected no subscriptions left, got %+v", subs)
+	}
+}
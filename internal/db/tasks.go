@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Task represents an item on a session's todo list.
+type Task struct {
+	ID          int64
+	SessionID   string
+	Title       string
+	Done        bool
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// AddTask appends a task to the session's todo list and returns its ID.
+func (db *DB) AddTask(ctx context.Context, sessionID, title string) (int64, error) {
+	query := `INSERT INTO tasks (session_id, title) VALUES (?, ?)`
+	id, err := db.insert(ctx, query, sessionID, title)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add task: %w", err)
+	}
+	return id, nil
+}
+
+// ListTasks returns the session's tasks, oldest first. Completed tasks are
+// included only when includeDone is true.
+func (db *DB) ListTasks(ctx context.Context, sessionID string, includeDone bool) ([]Task, error) {
+	query := `SELECT id, session_id, title, done, created_at, completed_at FROM tasks WHERE session_id = ?`
+	if !includeDone {
+		query += ` AND done = 0`
+	}
+	query += ` ORDER BY done ASC, id ASC`
+
+	rows, err := db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tasks []Task
+	for rows.Next() {
+		var t Task
+		var completedAt sql.NullTime
+		if err := rows.Scan(&t.ID, &t.SessionID, &t.Title, &t.Done, &t.CreatedAt, &completedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		if completedAt.Valid {
+			t.CompletedAt = &completedAt.Time
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return tasks, nil
+}
+
+// CompleteTask marks an open task as done. It reports whether a matching
+// open task was found.
+func (db *DB) CompleteTask(ctx context.Context, sessionID string, id int64) (bool, error) {
+	query := `UPDATE tasks SET done = 1, completed_at = ? WHERE id = ? AND session_id = ? AND done = 0`
+	res, err := db.ExecContext(ctx, query, time.Now().UTC(), id, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to complete task: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to complete task: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ClearTasks deletes the session's completed tasks, or every task when all
+// is true. It returns the number of tasks removed.
+func (db *DB) ClearTasks(ctx context.Context, sessionID string, all bool) (int64, error) {
+	query := `DELETE FROM tasks WHERE session_id = ?`
+	if !all {
+		query += ` AND done = 1`
+	}
+	res, err := db.ExecContext(ctx, query, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear tasks: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear tasks: %w", err)
+	}
+	return n, nil
+}
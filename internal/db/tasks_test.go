@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTasks(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	first, err := db.AddTask(ctx, "session-a", "Write tests")
+	if err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	_, _ = db.AddTask(ctx, "session-a", "Ship it")
+	_, _ = db.AddTask(ctx, "session-b", "Other session")
+
+	tasks, err := db.ListTasks(ctx, "session-a", false)
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Title != "Write tests" {
+		t.Fatalf("expected 2 open tasks in insertion order, got %+v", tasks)
+	}
+
+	// Completing from another session is a no-op
+	if found, _ := db.CompleteTask(ctx, "session-b", first); found {
+		t.Error("expected complete from another session to find nothing")
+	}
+	found, err := db.CompleteTask(ctx, "session-a", first)
+	if err != nil || !found {
+		t.Fatalf("CompleteTask failed: found=%v err=%v", found, err)
+	}
+	if found, _ := db.CompleteTask(ctx, "session-a", first); found {
+		t.Error("expected completing a done task to find nothing")
+	}
+
+	tasks, _ = db.ListTasks(ctx, "session-a", false)
+	if len(tasks) != 1 {
+		t.Errorf("expected 1 open task, got %d", len(tasks))
+	}
+	tasks, _ = db.ListTasks(ctx, "session-a", true)
+	if len(tasks) != 2 || !tasks[1].Done || tasks[1].CompletedAt == nil {
+		t.Errorf("expected completed task last with completion time, got %+v", tasks)
+	}
+
+	n, err := db.ClearTasks(ctx, "session-a", false)
+	if err != nil || n != 1 {
+		t.Fatalf("ClearTasks(completed) = %d, %v; want 1", n, err)
+	}
+	n, err = db.ClearTasks(ctx, "session-a", true)
+	if err != nil || n != 1 {
+		t.Fatalf("ClearTasks(all) = %d, %v; want 1", n, err)
+	}
+	tasks, _ = db.ListTasks(ctx, "session-b", true)
+	if len(tasks) != 1 {
+		t.Errorf("expected other session tasks untouched, got %d", len(tasks))
+	}
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Transcript roles.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// TranscriptMessage is one user or assistant message in a session's clean
+// conversation log, kept separately from the raw ADK events.
+type TranscriptMessage struct {
+	ID        int64
+	SessionID string
+	Role      string
+	Content   string
+	CreatedAt time.Time
+}
+
+// AddTranscriptMessage appends a message to the session's transcript.
+func (db *DB) AddTranscriptMessage(ctx context.Context, sessionID, role, content string) error {
+	stored, err := db.cipher.EncryptString(content)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt transcript message: %w", err)
+	}
+	query := `INSERT INTO transcripts (session_id, role, content) VALUES (?, ?, ?)`
+	if _, err := db.ExecContext(ctx, query, sessionID, role, stored); err != nil {
+		return fmt.Errorf("failed to add transcript message: %w", err)
+	}
+	return nil
+}
+
+// GetTranscript returns the session's most recent messages, oldest first.
+// A limit of zero or less returns the whole transcript.
+func (db *DB) GetTranscript(ctx context.Context, sessionID string, limit int) ([]TranscriptMessage, error) {
+	if limit <= 0 {
+		limit = math.MaxInt32
+	}
+	query := `
+		SELECT id, session_id, role, content, created_at FROM (
+			SELECT id, session_id, role, content, created_at FROM transcripts
+			WHERE session_id = ? ORDER BY id DESC LIMIT ?
+		) AS recent ORDER BY id ASC
+	`
+	rows, err := db.QueryContext(ctx, query, sessionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcript: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var msgs []TranscriptMessage
+	for rows.Next() {
+		var m TranscriptMessage
+		if err := rows.Scan(&m.ID, &m.SessionID, &m.Role, &m.Content, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transcript message: %w", err)
+		}
+		if m.Content, err = db.cipher.DecryptString(m.Content); err != nil {
+			return nil, fmt.Errorf("failed to decrypt transcript message: %w", err)
+		}
+		msgs = append(msgs, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return msgs, nil
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestTranscripts(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		if err := db.AddTranscriptMessage(ctx, "session-a", RoleUser, fmt.Sprintf("question %d", i)); err != nil {
+			t.Fatalf("AddTranscriptMessage failed: %v", err)
+		}
+		if err := db.AddTranscriptMessage(ctx, "session-a", RoleAssistant, fmt.Sprintf("answer %d", i)); err != nil {
+			t.Fatalf("AddTranscriptMessage failed: %v", err)
+		}
+	}
+	_ = db.AddTranscriptMessage(ctx, "session-b", RoleUser, "other session")
+
+	all, err := db.GetTranscript(ctx, "session-a", 0)
+	if err != nil {
+		t.Fatalf("GetTranscript failed: %v", err)
+	}
+	if len(all) != 6 || all[0].Content != "question 1" || all[5].Content != "answer 3" {
+		t.Fatalf("expected full transcript oldest first, got %+v", all)
+	}
+
+	last, err := db.GetTranscript(ctx, "session-a", 2)
+	if err != nil {
+		t.Fatalf("GetTranscript failed: %v", err)
+	}
+	if len(last) != 2 || last[0].Role != RoleUser || last[0].Content != "question 3" || last[1].Content != "answer 3" {
+		t.Errorf("expected the last turn, got %+v", last)
+	}
+
+	if msgs, _ := db.GetTranscript(ctx, "session-c", 10); len(msgs) != 0 {
+		t.Errorf("expected empty transcript, got %+v", msgs)
+	}
+}
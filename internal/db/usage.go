@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// UsageDayFormat is the layout of UsageDay.Day.
+const UsageDayFormat = "2006-01-02"
+
+// UsageDay holds the usage aggregates of one calendar day.
+type UsageDay struct {
+	Day          string // UsageDayFormat, in the bot's timezone
+	Messages     int64
+	Missions     int64
+	InputTokens  int64
+	OutputTokens int64
+	CostUSD      float64
+	Tools        map[string]int64 // calls per tool
+}
+
+// AddUsage adds u's counters to the stored totals for u.Day, so rollups of
+// the same day accumulate.
+func (db *DB) AddUsage(ctx context.Context, u UsageDay) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin usage rollup: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := db.dialect.rebind(`
+		INSERT INTO usage_daily (day, messages, missions, input_tokens, output_tokens, cost_usd)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(day) DO UPDATE SET
+			messages = usage_daily.messages + excluded.messages,
+			missions = usage_daily.missions + excluded.missions,
+			input_tokens = usage_daily.input_tokens + excluded.input_tokens,
+			output_tokens = usage_daily.output_tokens + excluded.output_tokens,
+			cost_usd = usage_daily.cost_usd + excluded.cost_usd
+	`)
+	if _, err := tx.ExecContext(ctx, query, u.Day, u.Messages, u.Missions, u.InputTokens, u.OutputTokens, u.CostUSD); err != nil {
+		return fmt.Errorf("failed to add usage for %s: %w", u.Day, err)
+	}
+
+	toolQuery := db.dialect.rebind(`
+		INSERT INTO usage_tools (day, tool, calls) VALUES (?, ?, ?)
+		ON CONFLICT(day, tool) DO UPDATE SET calls = usage_tools.calls + excluded.calls
+	`)
+	for tool, calls := range u.Tools {
+		if _, err := tx.ExecContext(ctx, toolQuery, u.Day, tool, calls); err != nil {
+			return fmt.Errorf("failed to add tool usage for %s: %w", u.Day, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetUsage returns the stored days from from to to inclusive (both
+// UsageDayFormat), oldest first. Days without activity are omitted.
+func (db *DB) GetUsage(ctx context.Context, from, to string) ([]UsageDay, error) {
+	query := `
+		SELECT day, messages, missions, input_tokens, output_tokens, cost_usd
+		FROM usage_daily WHERE day >= ? AND day <= ? ORDER BY day
+	`
+	rows, err := db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var days []UsageDay
+	index := make(map[string]int)
+	for rows.Next() {
+		u := UsageDay{Tools: make(map[string]int64)}
+		if err := rows.Scan(&u.Day, &u.Messages, &u.Missions, &u.InputTokens, &u.OutputTokens, &u.CostUSD); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		index[u.Day] = len(days)
+		days = append(days, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	_ = rows.Close()
+
+	toolRows, err := db.QueryContext(ctx, `SELECT day, tool, calls FROM usage_tools WHERE day >= ? AND day <= ?`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tool usage: %w", err)
+	}
+	defer func() { _ = toolRows.Close() }()
+	for toolRows.Next() {
+		var day, tool string
+		var calls int64
+		if err := toolRows.Scan(&day, &tool, &calls); err != nil {
+			return nil, fmt.Errorf("failed to scan tool usage: %w", err)
+		}
+		if i, ok := index[day]; ok {
+			days[i].Tools[tool] = calls
+		}
+	}
+	if err := toolRows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return days, nil
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	rollups := []UsageDay{
+		{Day: "2026-10-12", Messages: 4, InputTokens: 1000, OutputTokens: 100, CostUSD: 0.5, Tools: map[string]int64{"web_search": 2}},
+		{Day: "2026-10-13", Messages: 2, Missions: 1, InputTokens: 300, OutputTokens: 30, CostUSD: 0.25},
+		{Day: "2026-10-13", Messages: 1, InputTokens: 200, OutputTokens: 20, CostUSD: 0.25, Tools: map[string]int64{"web_search": 1, "fetch": 3}},
+		{Day: "2026-10-13", Tools: map[string]int64{"fetch": 1}},
+		{Day: "2026-10-14", Messages: 9},
+	}
+	for _, u := range rollups {
+		if err := db.AddUsage(ctx, u); err != nil {
+			t.Fatalf("AddUsage failed: %v", err)
+		}
+	}
+
+	days, err := db.GetUsage(ctx, "2026-10-12", "2026-10-13")
+	if err != nil {
+		t.Fatalf("GetUsage failed: %v", err)
+	}
+	if len(days) != 2 || days[0].Day != "2026-10-12" || days[1].Day != "2026-10-13" {
+		t.Fatalf("expected two days oldest first, got %+v", days)
+	}
+	d := days[1]
+	if d.Messages != 3 || d.Missions != 1 || d.InputTokens != 500 || d.OutputTokens != 50 || d.CostUSD != 0.5 {
+		t.Errorf("expected rollups to accumulate, got %+v", d)
+	}
+	if d.Tools["fetch"] != 4 || d.Tools["web_search"] != 1 || len(d.Tools) != 2 {
+		t.Errorf("unexpected tool calls: %+v", d.Tools)
+	}
+	if days[0].Tools["web_search"] != 2 {
+		t.Errorf("unexpected tool calls: %+v", days[0].Tools)
+	}
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ResourceWatch is an MCP resource a session is notified about when it
+// changes. Platform and ChannelID are where the watch was set, as for
+// reminders.
+type ResourceWatch struct {
+	ID        int64
+	SessionID string
+	Server    string
+	URI       string
+	Platform  string
+	ChannelID string
+	CreatedAt time.Time
+}
+
+const watchColumns = `id, session_id, server, uri, platform, channel_id, created_at`
+
+// AddResourceWatch records a watch. It reports false if the session already
+// watches the resource.
+func (db *DB) AddResourceWatch(ctx context.Context, w ResourceWatch) (bool, error) {
+	query := `INSERT INTO resource_watches (session_id, server, uri, platform, channel_id) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(session_id, server, uri) DO NOTHING`
+	res, err := db.ExecContext(ctx, query, w.SessionID, w.Server, w.URI, w.Platform, w.ChannelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to add resource watch: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add resource watch: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ListResourceWatches returns the session's watches, oldest first.
+func (db *DB) ListResourceWatches(ctx context.Context, sessionID string) ([]ResourceWatch, error) {
+	return db.queryResourceWatches(ctx, `SELECT `+watchColumns+` FROM resource_watches WHERE session_id = ? ORDER BY id ASC`, sessionID)
+}
+
+// AllResourceWatches returns every session's watches, for subscribing at
+// startup.
+func (db *DB) AllResourceWatches(ctx context.Context) ([]ResourceWatch, error) {
+	return db.queryResourceWatches(ctx, `SELECT `+watchColumns+` FROM resource_watches ORDER BY id ASC`)
+}
+
+// ResourceWatchers returns the watches on one resource.
+func (db *DB) ResourceWatchers(ctx context.Context, server, uri string) ([]ResourceWatch, error) {
+	return db.queryResourceWatches(ctx, `SELECT `+watchColumns+` FROM resource_watches WHERE server = ? AND uri = ? ORDER BY id ASC`, server, uri)
+}
+
+// RemoveResourceWatch deletes one of the session's watches and returns it,
+// or nil if the session has no watch with that ID.
+func (db *DB) RemoveResourceWatch(ctx context.Context, sessionID string, id int64) (*ResourceWatch, error) {
+	var w ResourceWatch
+	err := db.QueryRowContext(ctx, `SELECT `+watchColumns+` FROM resource_watches WHERE session_id = ? AND id = ?`, sessionID, id).
+		Scan(&w.ID, &w.SessionID, &w.Server, &w.URI, &w.Platform, &w.ChannelID, &w.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource watch: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM resource_watches WHERE id = ?`, id); err != nil {
+		return nil, fmt.Errorf("failed to remove resource watch: %w", err)
+	}
+	return &w, nil
+}
+
+func (db *DB) queryResourceWatches(ctx context.Context, query string, args ...any) ([]ResourceWatch, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource watches: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var watches []ResourceWatch
+	for rows.Next() {
+		var w ResourceWatch
+		if err := rows.Scan(&w.ID, &w.SessionID, &w.Server, &w.URI, &w.Platform, &w.ChannelID, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan resource watch: %w", err)
+		}
+		watches = append(watches, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return watches, nil
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResourceWatches(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	log := ResourceWatch{SessionID: "session-a", Server: "fs", URI: "file:///var/log/app.log", Platform: "telegram", ChannelID: "42"}
+	added, err := db.AddResourceWatch(ctx, log)
+	if err != nil || !added {
+		t.Fatalf("AddResourceWatch failed: added=%v err=%v", added, err)
+	}
+	if added, _ := db.AddResourceWatch(ctx, log); added {
+		t.Error("expected duplicate watch to be ignored")
+	}
+	_, _ = db.AddResourceWatch(ctx, ResourceWatch{SessionID: "session-a", Server: "grafana", URI: "dashboard://cpu"})
+	_, _ = db.AddResourceWatch(ctx, ResourceWatch{SessionID: "session-b", Server: "fs", URI: "file:///var/log/app.log"})
+
+	watches, err := db.ListResourceWatches(ctx, "session-a")
+	if err != nil {
+		t.Fatalf("ListResourceWatches failed: %v", err)
+	}
+	if len(watches) != 2 || watches[0].URI != log.URI || watches[0].Platform != "telegram" || watches[0].ChannelID != "42" {
+		t.Fatalf("expected 2 watches in insertion order, got %+v", watches)
+	}
+
+	watchers, err := db.ResourceWatchers(ctx, "fs", log.URI)
+	if err != nil || len(watchers) != 2 {
+		t.Fatalf("expected both sessions watching the log, got %+v (err=%v)", watchers, err)
+	}
+	if all, _ := db.AllResourceWatches(ctx); len(all) != 3 {
+		t.Errorf("expected 3 watches in total, got %d", len(all))
+	}
+
+	if w, _ := db.RemoveResourceWatch(ctx, "session-b", watches[0].ID); w != nil {
+		t.Error("expected removal from another session to find nothing")
+	}
+	w, err := db.RemoveResourceWatch(ctx, "session-a", watches[0].ID)
+	if err != nil || w == nil || w.URI != log.URI {
+		t.Fatalf("RemoveResourceWatch failed: watch=%+v err=%v", w, err)
+	}
+	if watchers, _ := db.ResourceWatchers(ctx, "fs", log.URI); len(watchers) != 1 {
+		t.Errorf("expected one watcher left, got %+v", watchers)
+	}
+}
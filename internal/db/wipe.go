@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// sessionTables lists the tables keyed by a session_id column.
+var sessionTables = []string{
+	"briefings", "reminders", "tasks", "memories", "subscriptions",
+	"resource_watches", "jules_sessions", "digest_runs", "transcripts",
+	"session_summaries", "session_summary_versions", "session_locales",
+	"session_prompts", "feedback",
+}
+
+// WipeSession deletes everything stored for a user's channel (sessionID):
+// its own briefings, reminders, todos, memories, feed subscriptions,
+// resource watches, Jules sessions, transcripts, summaries, prompts and
+// settings, the same for each of its chat threads ("sessionID:name"), its
+// thread list, and embeddings in namespaces named after it. Shared
+// briefings are kept. ADK sessions and events are not stored here; the
+// caller clears them through the agent. It returns the number of rows
+// deleted.
+func (db *DB) WipeSession(ctx context.Context, sessionID string) (int64, error) {
+	threads := escapeLike(sessionID) + ":%"
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin wipe: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	type deletion struct {
+		table, column string
+	}
+	deletions := []deletion{{"chat_sessions", "channel_id"}, {"active_sessions", "channel_id"}, {"embeddings", "namespace"}}
+	for _, t := range sessionTables {
+		deletions = append(deletions, deletion{t, "session_id"})
+	}
+
+	var total int64
+	for _, d := range deletions {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE %s = ? OR %s LIKE ? ESCAPE '\'`, d.table, d.column, d.column)
+		res, err := tx.ExecContext(ctx, db.dialect.rebind(query), sessionID, threads)
+		if err != nil {
+			return 0, fmt.Errorf("failed to wipe %s: %w", d.table, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to wipe %s: %w", d.table, err)
+		}
+		total += n
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit wipe: %w", err)
+	}
+	return total, nil
+}
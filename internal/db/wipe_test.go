@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWipeSession(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	for _, session := range []string{"telegram-1", "telegram-1:research", "telegram-10", "discord-2"} {
+		if err := db.AddReminder(ctx, session, "stand up", time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("AddReminder failed: %v", err)
+		}
+		if _, err := db.AddMemory(ctx, session, "Prefers tabs"); err != nil {
+			t.Fatalf("AddMemory failed: %v", err)
+		}
+		if err := db.AddTranscriptMessage(ctx, session, RoleUser, "hello from "+session); err != nil {
+			t.Fatalf("AddTranscriptMessage failed: %v", err)
+		}
+	}
+	if err := db.TouchChatSession(ctx, "telegram-1", "telegram-1:research", "research"); err != nil {
+		t.Fatalf("TouchChatSession failed: %v", err)
+	}
+	if err := db.SaveSessionSummary(ctx, "telegram-1:research", "Compared vector databases."); err != nil {
+		t.Fatalf("SaveSessionSummary failed: %v", err)
+	}
+	if _, err := db.AddSessionBriefing(ctx, "telegram-1", "Private research report", "vector dbs", nil); err != nil {
+		t.Fatalf("AddSessionBriefing failed: %v", err)
+	}
+	if _, err := db.AddBriefing(ctx, "Shared nightly report", "nightly", nil); err != nil {
+		t.Fatalf("AddBriefing failed: %v", err)
+	}
+	if err := db.UpsertEmbeddings(ctx, []Embedding{{Namespace: "telegram-1", Key: "k", Vector: []float32{1}}}); err != nil {
+		t.Fatalf("UpsertEmbeddings failed: %v", err)
+	}
+
+	// Briefings are scoped to their owner plus the shared ones.
+	if got, _ := db.GetBriefings(ctx, BriefingFilter{SessionID: "discord-2"}); len(got) != 1 || got[0].Topic != "nightly" {
+		t.Errorf("expected only the shared briefing for another session, got %+v", got)
+	}
+	if got, _ := db.GetBriefings(ctx, BriefingFilter{SessionID: "telegram-1"}); len(got) != 2 {
+		t.Errorf("expected own and shared briefings, got %+v", got)
+	}
+
+	n, err := db.WipeSession(ctx, "telegram-1")
+	if err != nil {
+		t.Fatalf("WipeSession failed: %v", err)
+	}
+	// 2 reminders, 2 memories, 2 transcripts, 1 thread, 1 summary, 1 summary
+	// version, 1 briefing and 1 embedding.
+	if n != 11 {
+		t.Errorf("expected 11 rows deleted, got %d", n)
+	}
+
+	for _, session := range []string{"telegram-1", "telegram-1:research"} {
+		if memories, _ := db.SearchMemories(ctx, session, "", 10); len(memories) != 0 {
+			t.Errorf("expected no memories left for %s, got %+v", session, memories)
+		}
+		if reminders, _ := db.GetSessionReminders(ctx, session); len(reminders) != 0 {
+			t.Errorf("expected no reminders left for %s, got %+v", session, reminders)
+		}
+	}
+	if summary, _ := db.GetSessionSummary(ctx, "telegram-1:research"); summary != "" {
+		t.Errorf("expected summary to be wiped, got %q", summary)
+	}
+	if got, _ := db.GetBriefings(ctx, BriefingFilter{}); len(got) != 1 || got[0].Topic != "nightly" {
+		t.Errorf("expected only the shared briefing to remain, got %+v", got)
+	}
+
+	// Sessions merely sharing a prefix are untouched.
+	for _, session := range []string{"telegram-10", "discord-2"} {
+		if memories, _ := db.SearchMemories(ctx, session, "", 10); len(memories) != 1 {
+			t.Errorf("expected %s to keep its memory, got %+v", session, memories)
+		}
+	}
+}
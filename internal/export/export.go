@@ -0,0 +1,90 @@
+// Package export renders stored briefings into downloadable files: a single
+// Markdown bundle, a standalone HTML page, or a plain-text PDF.
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/db"
+)
+
+// Format is an export file format.
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+	FormatPDF      Format = "pdf"
+)
+
+// ParseFormat maps user input such as "markdown" or "PDF" to a Format.
+func ParseFormat(s string) (Format, bool) {
+	switch strings.ToLower(s) {
+	case "md", "markdown":
+		return FormatMarkdown, true
+	case "html", "htm":
+		return FormatHTML, true
+	case "pdf":
+		return FormatPDF, true
+	}
+	return "", false
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "text/markdown; charset=utf-8"
+}
+
+// File is a rendered export ready to be sent as an attachment.
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Render bundles the briefings into a single file of the given format.
+func Render(f Format, briefings []db.Briefing, now time.Time) (File, error) {
+	title := fmt.Sprintf("ravenbot Briefings (%s)", now.Format("2006-01-02"))
+	bundle := Markdown(title, briefings)
+
+	var data []byte
+	switch f {
+	case FormatMarkdown:
+		data = []byte(bundle)
+	case FormatHTML:
+		data = []byte(HTML(title, bundle))
+	case FormatPDF:
+		data = PDF(title, bundle)
+	default:
+		return File{}, fmt.Errorf("unsupported export format %q", f)
+	}
+
+	return File{
+		Name:        fmt.Sprintf("ravenbot_briefings_%s.%s", now.Format("2006-01-02"), f),
+		ContentType: f.ContentType(),
+		Data:        data,
+	}, nil
+}
+
+// Markdown concatenates the briefings into one Markdown document.
+func Markdown(title string, briefings []db.Briefing) string {
+	var sb strings.Builder
+	sb.WriteString("# " + title + "\n\n")
+	for i, b := range briefings {
+		sb.WriteString(fmt.Sprintf("---\n\n## Briefing %d (Created: %s)\n\n", i+1, b.CreatedAt))
+		if len(b.Tags) > 0 {
+			sb.WriteString("_Tags: " + strings.Join(b.Tags, ", ") + "_\n\n")
+		}
+		sb.WriteString(strings.TrimSpace(b.Content))
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBriefings = []db.Briefing{
+	{ID: 2, Content: "# Kubernetes 1.31\n\n- **Sidecars** are GA\n- See [notes](https://kubernetes.io/blog)", CreatedAt: "2026-03-02 09:00:00", Tags: []string{"kubernetes", "release"}},
+	{ID: 1, Content: "Plain briefing with `code` and a <script>alert(1)</script> tag.", CreatedAt: "2026-03-01 09:00:00"},
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"md": FormatMarkdown, "Markdown": FormatMarkdown, "HTML": FormatHTML, "pdf": FormatPDF} {
+		got, ok := ParseFormat(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, ok := ParseFormat("docx")
+	assert.False(t, ok)
+}
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+
+	md, err := Render(FormatMarkdown, testBriefings, now)
+	require.NoError(t, err)
+	assert.Equal(t, "ravenbot_briefings_2026-03-03.md", md.Name)
+	assert.Contains(t, string(md.Data), "## Briefing 1 (Created: 2026-03-02 09:00:00)")
+	assert.Contains(t, string(md.Data), "## Briefing 2 (Created: 2026-03-01 09:00:00)")
+	assert.Contains(t, string(md.Data), "_Tags: kubernetes, release_")
+
+	html, err := Render(FormatHTML, testBriefings, now)
+	require.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", html.ContentType)
+	page := string(html.Data)
+	assert.Contains(t, page, "<h1>Kubernetes 1.31</h1>")
+	assert.Contains(t, page, "<li><strong>Sidecars</strong> are GA</li>")
+	assert.Contains(t, page, `<a href="https://kubernetes.io/blog">notes</a>`)
+	assert.Contains(t, page, "<code>code</code>")
+	assert.Contains(t, page, "&lt;script&gt;")
+	assert.NotContains(t, page, "<script>")
+
+	pdf, err := Render(FormatPDF, testBriefings, now)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf.Data, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(pdf.Data, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf.Data), "(Kubernetes 1.31) Tj")
+	assert.Contains(t, string(pdf.Data), "(- Sidecars are GA) Tj")
+
+	_, err = Render("docx", testBriefings, now)
+	assert.Error(t, err)
+}
+
+func TestMarkdownToHTML_UnsafeLinks(t *testing.T) {
+	out := markdownToHTML("[click](javascript:alert(1)) and snake_case_name")
+	assert.NotContains(t, out, "href")
+	assert.Contains(t, out, "snake_case_name")
+}
+
+func TestPDF_Pagination(t *testing.T) {
+	// Title, spacer and 400 body lines at 48 lines per page.
+	out := string(PDF("Title", strings.Repeat("line\n\n", 200)))
+	assert.Contains(t, out, "/Count 9")
+}
+
+func TestPDFEscape(t *testing.T) {
+	assert.Equal(t, `\(parens\) caf\351 - quote' `, pdfEscape("(parens) café — quote’ 🚀"))
+}
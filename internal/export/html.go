@@ -0,0 +1,152 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+const htmlStyle = `body{font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;max-width:860px;margin:2em auto;padding:0 1em;line-height:1.55;color:#222}
+pre{background:#f5f5f5;padding:.8em;overflow-x:auto}code{background:#f5f5f5;padding:0 .2em}
+blockquote{border-left:4px solid #ddd;margin:0;padding-left:1em;color:#555}hr{border:0;border-top:1px solid #ddd;margin:2em 0}`
+
+// HTML renders a Markdown document as a standalone HTML page. It supports
+// the subset of Markdown the models produce: headings, lists, quotes, fenced
+// code, rules, emphasis, inline code and links.
+func HTML(title, markdown string) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	sb.WriteString("<style>" + htmlStyle + "</style>\n</head>\n<body>\n")
+	sb.WriteString(markdownToHTML(markdown))
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+var (
+	orderedItem = regexp.MustCompile(`^\d+[.)]\s+`)
+	codeSpan    = regexp.MustCompile("`([^`]+)`")
+	boldSpan    = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	italicSpan  = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+	linkSpan    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+func markdownToHTML(md string) string {
+	var sb strings.Builder
+	var para []string
+	list := "" // "ul", "ol" or ""
+	inCode := false
+
+	flushPara := func() {
+		if len(para) > 0 {
+			sb.WriteString("<p>" + inline(strings.Join(para, " ")) + "</p>\n")
+			para = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			sb.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+	openList := func(kind string) {
+		if list != kind {
+			closeList()
+			sb.WriteString("<" + kind + ">\n")
+			list = kind
+		}
+	}
+
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				sb.WriteString("</code></pre>\n")
+			} else {
+				flushPara()
+				closeList()
+				sb.WriteString("<pre><code>")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			sb.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flushPara()
+			closeList()
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			flushPara()
+			closeList()
+			sb.WriteString("<hr>\n")
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			if level > 6 || !strings.HasPrefix(trimmed[level:], " ") {
+				para = append(para, trimmed)
+				continue
+			}
+			flushPara()
+			closeList()
+			sb.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", level, inline(strings.TrimSpace(trimmed[level:])), level))
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "+ "):
+			flushPara()
+			openList("ul")
+			sb.WriteString("<li>" + inline(strings.TrimSpace(trimmed[2:])) + "</li>\n")
+		case orderedItem.MatchString(trimmed):
+			flushPara()
+			openList("ol")
+			sb.WriteString("<li>" + inline(orderedItem.ReplaceAllString(trimmed, "")) + "</li>\n")
+		case strings.HasPrefix(trimmed, ">"):
+			flushPara()
+			closeList()
+			sb.WriteString("<blockquote>" + inline(strings.TrimSpace(trimmed[1:])) + "</blockquote>\n")
+		default:
+			closeList()
+			para = append(para, trimmed)
+		}
+	}
+	if inCode {
+		sb.WriteString("</code></pre>\n")
+	}
+	flushPara()
+	closeList()
+	return sb.String()
+}
+
+// inline escapes text and applies inline Markdown. Code spans are rendered
+// first and protected from further formatting.
+func inline(text string) string {
+	var codes []string
+	text = codeSpan.ReplaceAllStringFunc(text, func(m string) string {
+		codes = append(codes, "<code>"+html.EscapeString(m[1:len(m)-1])+"</code>")
+		return fmt.Sprintf("\x00%d\x00", len(codes)-1)
+	})
+
+	text = html.EscapeString(text)
+	text = linkSpan.ReplaceAllStringFunc(text, func(m string) string {
+		parts := linkSpan.FindStringSubmatch(m)
+		href := html.UnescapeString(parts[2])
+		if !safeHref(href) {
+			return parts[1]
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), parts[1])
+	})
+	text = boldSpan.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = italicSpan.ReplaceAllString(text, "<em>$1$2</em>")
+
+	for i, c := range codes {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), c, 1)
+	}
+	return text
+}
+
+func safeHref(href string) bool {
+	lower := strings.ToLower(href)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")
+}
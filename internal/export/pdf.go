@@ -0,0 +1,204 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page layout for PDF exports (US Letter, points).
+const (
+	pdfPageWidth  = 612
+	pdfPageHeight = 792
+	pdfMargin     = 54
+	pdfBodySize   = 10
+	pdfLeading    = 14
+	pdfWrapWidth  = 95 // characters per body line at pdfBodySize
+)
+
+// pdfLine is one laid-out line of text.
+type pdfLine struct {
+	text string
+	bold bool
+	size int
+}
+
+// PDF renders a Markdown document as a plain-text PDF using the built-in
+// Helvetica fonts. Markdown markup is stripped, headings are set in bold,
+// and characters outside Latin-1 are transliterated or dropped.
+func PDF(title, markdown string) []byte {
+	lines := []pdfLine{{text: title, bold: true, size: 16}, {}}
+	inCode := false
+	for _, raw := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			for _, w := range wrap(raw, pdfWrapWidth) {
+				lines = append(lines, pdfLine{text: w, size: pdfBodySize})
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			size := max(16-2*level, pdfBodySize+1)
+			lines = append(lines, pdfLine{}, pdfLine{text: plainText(strings.TrimSpace(trimmed[level:])), bold: true, size: size})
+			continue
+		}
+		if trimmed == "---" || trimmed == "***" {
+			lines = append(lines, pdfLine{text: strings.Repeat("_", 60), size: pdfBodySize})
+			continue
+		}
+		text := plainText(trimmed)
+		if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+			text = "- " + plainText(trimmed[2:])
+		}
+		if text == "" {
+			lines = append(lines, pdfLine{})
+			continue
+		}
+		for _, w := range wrap(text, pdfWrapWidth) {
+			lines = append(lines, pdfLine{text: w, size: pdfBodySize})
+		}
+	}
+	return writePDF(paginate(lines))
+}
+
+// plainText strips inline Markdown markup, keeping link targets.
+func plainText(s string) string {
+	s = linkSpan.ReplaceAllString(s, "$1 ($2)")
+	s = boldSpan.ReplaceAllString(s, "$1$2")
+	s = italicSpan.ReplaceAllString(s, "$1$2")
+	s = strings.ReplaceAll(s, "`", "")
+	return strings.TrimSpace(s)
+}
+
+// wrap breaks s into lines of at most width characters at word boundaries.
+func wrap(s string, width int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var out []string
+	line := ""
+	for _, w := range words {
+		for utf8.RuneCountInString(w) > width {
+			if line != "" {
+				out = append(out, line)
+				line = ""
+			}
+			r := []rune(w)
+			out = append(out, string(r[:width]))
+			w = string(r[width:])
+		}
+		switch {
+		case line == "":
+			line = w
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(w) <= width:
+			line += " " + w
+		default:
+			out = append(out, line)
+			line = w
+		}
+	}
+	if line != "" {
+		out = append(out, line)
+	}
+	return out
+}
+
+func paginate(lines []pdfLine) [][]pdfLine {
+	perPage := (pdfPageHeight - 2*pdfMargin) / pdfLeading
+	var pages [][]pdfLine
+	for len(lines) > perPage {
+		pages = append(pages, lines[:perPage])
+		lines = lines[perPage:]
+	}
+	return append(pages, lines)
+}
+
+// writePDF serializes the pages into a minimal PDF 1.4 document.
+func writePDF(pages [][]pdfLine) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4: catalog, page tree, fonts. Pages start at object 5 and
+	// take two objects each (page + content stream).
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		var content bytes.Buffer
+		content.WriteString("BT\n")
+		fmt.Fprintf(&content, "%d %d Td\n", pdfMargin, pdfPageHeight-pdfMargin)
+		for _, l := range page {
+			font := "F1"
+			if l.bold {
+				font = "F2"
+			}
+			size := l.size
+			if size == 0 {
+				size = pdfBodySize
+			}
+			fmt.Fprintf(&content, "/%s %d Tf\n0 -%d Td\n(%s) Tj\n", font, size, pdfLeading, pdfEscape(l.text))
+		}
+		content.WriteString("ET")
+
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfReplacements maps common typographic characters to Latin-1 friendly
+// equivalents.
+var pdfReplacements = map[rune]string{
+	'‘': "'", '’': "'", '“': "\"", '”': "\"",
+	'–': "-", '—': "-", '•': "-", '…': "...", '→': "->", '✓': "v",
+}
+
+// pdfEscape encodes s as the body of a PDF literal string in WinAnsi.
+func pdfEscape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if rep, ok := pdfReplacements[r]; ok {
+			sb.WriteString(rep)
+			continue
+		}
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '\t':
+			sb.WriteString("    ")
+		case r >= 0x20 && r < 0x7f:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&sb, "\\%03o", r)
+		}
+	}
+	return sb.String()
+}
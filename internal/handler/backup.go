@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/backup"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/i18n"
+)
+
+// runBackupJob snapshots the database on schedule. Failures are broadcast
+// so a broken backup doesn't go unnoticed.
+func (h *Handler) runBackupJob(ctx context.Context, job config.JobConfig) (string, error) {
+	res, err := backup.Run(ctx, h.db, backup.OptionsFromParams(job.Params, h.config().DataDir, h.config().ReportsDir), time.Now())
+	if err != nil {
+		slog.Error("Backup failed", "job", job.Name, "path", res.Path, "error", err)
+		h.broadcast(ctx, job.Name, i18n.T(h.defaultLocale(), "backup.job_failed", job.Name, err))
+		return res.Path, err
+	}
+	slog.Info("Backup completed", "job", job.Name, "path", res.Path, "size", res.Size, "archive", res.Archive, "removed", len(res.Removed), "uploaded", res.Uploaded)
+	return res.Path, nil
+}
+
+func (h *Handler) handleBackup(ctx context.Context, sessionID, text string, reply func(string)) {
+	if !strings.EqualFold(strings.TrimSpace(text[len("/backup"):]), "now") {
+		reply(h.msg(ctx, "backup.usage"))
+		return
+	}
+	if !h.config().Bot.IsAdmin(sessionID) {
+		slog.Warn("Rejected /backup from non-admin", "sessionID", sessionID)
+		reply(h.msg(ctx, "backup.denied"))
+		return
+	}
+	res, err := backup.Run(ctx, h.db, h.backupOptions(), time.Now())
+	switch {
+	case errors.Is(err, db.ErrSnapshotUnsupported):
+		reply(h.msg(ctx, "backup.unsupported"))
+		return
+	case err != nil && res.Path == "":
+		slog.Error("Backup failed", "error", err)
+		reply(h.msg(ctx, "backup.failed"))
+		return
+	}
+
+	msg := h.msg(ctx, "backup.done", res.Path, formatSize(res.Size))
+	if res.Archive != "" {
+		msg += h.msg(ctx, "backup.archived", res.Archive)
+	}
+	if len(res.Removed) > 0 {
+		msg += h.msg(ctx, "backup.rotated", len(res.Removed))
+	}
+	if err != nil {
+		slog.Error("Backup finished with errors", "path", res.Path, "error", err)
+		msg += h.msg(ctx, "backup.partial")
+	} else if res.Uploaded != "" {
+		msg += h.msg(ctx, "backup.uploaded", res.Uploaded)
+	}
+	reply(msg)
+}
+
+// backupOptions uses the params of the first configured backup job, so
+// /backup now writes to the same place as the schedule.
+func (h *Handler) backupOptions() backup.Options {
+	for _, job := range h.config().Jobs {
+		if job.Type == "backup" {
+			return backup.OptionsFromParams(job.Params, h.config().DataDir, h.config().ReportsDir)
+		}
+	}
+	return backup.OptionsFromParams(nil, h.config().DataDir, h.config().ReportsDir)
+}
+
+// formatSize renders a byte count such as "1.5 MB".
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
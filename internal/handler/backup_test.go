@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessage_Backup(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	cfg := *h.config()
+	cfg.ReportsDir = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.ReportsDir, "Daily_Briefing.md"), []byte("# Briefing"), 0644))
+	cfg.Bot.Admins = []string{"admin-session"}
+	cfg.Jobs = []config.JobConfig{{Name: "Nightly Backup", Type: "backup", Params: map[string]string{"dir": dir, "keep": "1", "reports": "true"}}}
+	h.UpdateConfig(&cfg)
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "admin-session", "/backup", nil, reply)
+	assert.Contains(t, got, "Usage")
+	h.HandleMessage(ctx, "test-session", "/backup now", nil, reply)
+	assert.Contains(t, got, "Only admins")
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a non-admin doesn't get a snapshot")
+
+	h.HandleMessage(ctx, "admin-session", "/backup now", nil, reply)
+	assert.Contains(t, got, "💾 Backup saved to `"+dir)
+	assert.Contains(t, got, "Reports archived to `"+filepath.Join(dir, "ravenbot-reports-"))
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// The scheduled job rotates down to params.keep.
+	fn := &fileNotifier{}
+	h.SetNotifiers([]notifier.Notifier{fn})
+	first := entries[0].Name()
+	time.Sleep(2 * time.Millisecond)
+	h.RunJob(ctx, cfg.Jobs[0])
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.NotEqual(t, first, entries[0].Name())
+	assert.Empty(t, fn.sent, "successful backups are not broadcast")
+
+	// Failures are.
+	blocked := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(blocked, nil, 0644))
+	failing := config.JobConfig{Name: "Broken Backup", Type: "backup", Params: map[string]string{"dir": blocked}}
+	h.RunJob(ctx, failing)
+	require.Len(t, fn.sent, 1)
+	assert.Contains(t, fn.sent[0], "Scheduled backup **Broken Backup** failed")
+
+	// The error of a partly failed /backup now stays in the logs.
+	cfg.Jobs[0].Params["upload"] = filepath.Join(blocked, "private-nas")
+	h.UpdateConfig(&cfg)
+	h.HandleMessage(ctx, "admin-session", "/backup now", nil, reply)
+	assert.Contains(t, got, "💾 Backup saved to `"+dir)
+	assert.Contains(t, got, "The local copy was kept")
+	assert.NotContains(t, got, "private-nas")
+}
+
+func TestFormatSize(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "512 B", formatSize(512))
+	assert.Equal(t, "1.5 KB", formatSize(1536))
+	assert.Equal(t, "2.0 MB", formatSize(2<<20))
+}
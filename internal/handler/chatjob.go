@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+)
+
+// chatJobPrefix namespaces the sessions of chat jobs that don't belong to
+// a chat channel.
+const chatJobPrefix = "job:"
+
+// runChatJob sends the job's prompt into a persistent conversation instead
+// of a one-off mission, so a recurring job such as a weekly check-in sees
+// its earlier runs. The reply is published like a research report.
+//
+// Params: prompt, session (the thread's name; default derived from the
+// job name), channel (a chat's session ID such as "telegram-123456", to
+// make the thread one of that chat's /sessions so it can be continued by
+// hand) and tags.
+func (h *Handler) runChatJob(ctx context.Context, job config.JobConfig) (string, error) {
+	prompt := strings.TrimSpace(job.Params["prompt"])
+	if prompt == "" {
+		return "", fmt.Errorf("chat job needs params.prompt")
+	}
+	name := job.Params["session"]
+	if name == "" {
+		name = chatJobSessionName(job.Name)
+	}
+	if !sessionName.MatchString(name) {
+		return "", fmt.Errorf("invalid session name %q: use up to 32 lowercase letters, digits, - and _", name)
+	}
+
+	sessionID := chatJobPrefix + name
+	if channel := job.Params["channel"]; channel != "" {
+		sessionID = sessionIDFor(channel, name)
+		if err := h.db.TouchChatSession(ctx, channel, sessionID, name); err != nil {
+			return "", err
+		}
+	}
+
+	message := fmt.Sprintf("Today is %s. %s", time.Now().In(h.config().JobLocation(job)).Format("Monday, January 2, 2006"), prompt)
+	response, err := h.bot.Chat(ctx, sessionID, message)
+	if err != nil {
+		return "", fmt.Errorf("chat failed: %w", err)
+	}
+	h.recordTurn(ctx, sessionID, message, response)
+	return h.publishJobReport(ctx, job, response, jobTags(job.Name, job.Params["tags"], nil))
+}
+
+// chatJobSessionName turns a job name such as "Weekly Project Check-in"
+// into a session name ("weekly-project-check-in").
+func chatJobSessionName(jobName string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(jobName) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			sb.WriteRune(r)
+			dash = false
+		case sb.Len() > 0 && !dash:
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	name := strings.TrimRight(sb.String(), "-")
+	if len(name) > 32 {
+		name = strings.TrimRight(name[:32], "-")
+	}
+	if name == "" {
+		name = "job"
+	}
+	return name
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatJob(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+	h.cfg.ReportsDir = t.TempDir()
+
+	var sessions, messages []string
+	h.bot = &mockBot{chatFunc: func(_ context.Context, sessionID, message string) (string, error) {
+		sessions, messages = append(sessions, sessionID), append(messages, message)
+		return fmt.Sprintf("Check-in #%d: all on track.", len(messages)), nil
+	}}
+	job := config.JobConfig{Name: "Weekly Project Check-in", Type: "chat", Params: map[string]string{
+		"prompt": "How are my projects doing compared with last week?",
+	}}
+
+	for range 2 {
+		_, err := h.runJob(ctx, job, db.TriggerManual)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"job:weekly-project-check-in", "job:weekly-project-check-in"}, sessions, "every run continues the same session")
+	assert.Contains(t, messages[0], "Today is ")
+	assert.Contains(t, messages[0], "How are my projects doing")
+
+	turns, err := database.GetTranscript(ctx, "job:weekly-project-check-in", 10)
+	require.NoError(t, err)
+	assert.Len(t, turns, 4)
+	briefings, err := database.GetRecentBriefings(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, briefings, 2)
+	assert.Equal(t, "Check-in #2: all on track.", briefings[0].Content)
+
+	// A thread of a chat shows up in its /sessions and can be continued there.
+	job.Params["session"] = "checkin"
+	job.Params["channel"] = "test-session"
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	assert.Equal(t, "test-session:checkin", sessions[2])
+	var got string
+	h.HandleMessage(ctx, "test-session", "/sessions", nil, func(r string) { got = r })
+	assert.Contains(t, got, "`checkin`")
+
+	job.Params["session"] = "Not Valid!"
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	assert.ErrorContains(t, err, "invalid session name")
+
+	job.Params["prompt"] = ""
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	assert.ErrorContains(t, err, "params.prompt")
+}
+
+func TestChatJobSessionName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "weekly-project-check-in", chatJobSessionName("Weekly Project Check-in"))
+	assert.Equal(t, "standup-2026", chatJobSessionName("  Standup — 2026!  "))
+	assert.Equal(t, "job", chatJobSessionName("☕"))
+	assert.Len(t, chatJobSessionName("a very long job name that goes on and on and on"), 32)
+}
+
+func TestJobTemplate(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+	h.cfg.ReportsDir = t.TempDir()
+	n := &fileNotifier{}
+	h.SetNotifiers([]notifier.Notifier{n})
+
+	reply := "Go 1.26 is out."
+	h.bot = &mockBot{chatFunc: func(context.Context, string, string) (string, error) { return reply, nil }}
+	job := config.JobConfig{
+		Name:     "Release Notes",
+		Type:     "chat",
+		Params:   map[string]string{"prompt": "What shipped?", "tags": "go, new releases"},
+		Template: "# {{.Title}} — {{.Date.Format \"2006\"}}\n\n{{.Report}}\n\n{{hashtags .Tags}}",
+	}
+
+	path, err := h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	want := fmt.Sprintf("# Release Notes — %d\n\nGo 1.26 is out.\n\n#release-notes #go #new-releases", time.Now().In(h.cfg.Location()).Year())
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), want)
+	briefings, err := database.GetRecentBriefings(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, briefings, 1)
+	assert.Equal(t, want, briefings[0].Content)
+	require.NotEmpty(t, n.sent)
+	assert.Contains(t, n.sent[len(n.sent)-1], "# Release Notes")
+
+	// A repeat of the same report is a duplicate even though the template
+	// is applied again, with the run's date.
+	sent := len(n.sent)
+	path, err = h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+	assert.Len(t, n.sent, sent, "a duplicate report is not sent again")
+	_, err = database.AddTemplatedBriefing(ctx, "# Release Notes — 2099\n\nGo 1.26 is out.", reply, job.Name, nil)
+	assert.ErrorIs(t, err, db.ErrDuplicateBriefing)
+
+	// A template that fails at run time doesn't lose the report.
+	reply = "Go 1.27 is out."
+	job.Template = "{{.Missing}}"
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	briefings, err = database.GetRecentBriefings(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Go 1.27 is out.", briefings[0].Content)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// confirmationTimeout is how long a pending confirmation stays valid.
+const confirmationTimeout = 60 * time.Second
+
+// pendingConfirmation is an action waiting for the user to reply "yes".
+type pendingConfirmation struct {
+	description string
+	action      func(ctx context.Context, reply func(string))
+	expires     time.Time
+}
+
+// confirm asks the user to approve a destructive or expensive action. The
+// action runs only if the session's next message is "yes" within
+// confirmationTimeout; a newer confirmation replaces an older one.
+func (h *Handler) confirm(ctx context.Context, sessionID, description string, action func(ctx context.Context, reply func(string)), reply func(string)) {
+	h.mu.Lock()
+	h.confirmations[sessionID] = pendingConfirmation{
+		description: description,
+		action:      action,
+		expires:     time.Now().Add(confirmationTimeout),
+	}
+	h.mu.Unlock()
+	reply(h.msg(ctx, "confirm.prompt", description, int(confirmationTimeout.Seconds())))
+}
+
+// resolveConfirmation consumes the session's pending confirmation, if any.
+// It reports true when text was the answer to it. Any other message cancels
+// the pending action and is then handled normally.
+func (h *Handler) resolveConfirmation(ctx context.Context, sessionID, text string, reply func(string)) bool {
+	h.mu.Lock()
+	pending, ok := h.confirmations[sessionID]
+	delete(h.confirmations, sessionID)
+	h.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	answer := strings.ToLower(strings.Trim(text, " .!¡"))
+	expired := time.Now().After(pending.expires)
+	switch answer {
+	case "yes", "y", "confirm", h.msg(ctx, "confirm.yes"):
+		if expired {
+			reply(h.msg(ctx, "confirm.expired"))
+			return true
+		}
+		slog.Info("Action confirmed", "sessionID", sessionID, "action", pending.description)
+		pending.action(ctx, reply)
+		return true
+	case "no", "n", "cancel", h.msg(ctx, "confirm.no"):
+		if !expired {
+			reply(h.msg(ctx, "confirm.cancelled"))
+			return true
+		}
+	}
+	return false
+}
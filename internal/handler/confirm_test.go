@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clearRecordingBot struct {
+	mockBot
+	cleared []string
+}
+
+func (b *clearRecordingBot) ClearSession(sessionID string) {
+	b.cleared = append(b.cleared, sessionID)
+}
+
+func TestHandleMessage_ConfirmReset(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	bot := &clearRecordingBot{}
+	h.bot = bot
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/reset", nil, reply)
+	assert.Contains(t, got, "Reply `yes`")
+	assert.Empty(t, bot.cleared)
+
+	// Another session's answer doesn't count.
+	h.HandleMessage(ctx, "other-session", "yes", nil, reply)
+	assert.Empty(t, bot.cleared)
+
+	h.HandleMessage(ctx, "test-session", "Yes!", nil, reply)
+	assert.Equal(t, []string{"test-session"}, bot.cleared)
+	assert.Contains(t, got, "Conversation cleared")
+
+	h.HandleMessage(ctx, "test-session", "/reset", nil, reply)
+	h.HandleMessage(ctx, "test-session", "no", nil, reply)
+	assert.Equal(t, "🚫 Cancelled.", got)
+	assert.Len(t, bot.cleared, 1)
+}
+
+func TestHandleMessage_ConfirmJules(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var prompts []string
+	h.bot = &mockBot{chatFunc: func(_ context.Context, _, message string) (string, error) {
+		prompts = append(prompts, message)
+		return "ok", nil
+	}}
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/jules owner/repo fix the tests", nil, reply)
+	assert.Contains(t, got, "pull request")
+	assert.Empty(t, prompts)
+
+	// An unrelated message cancels the pending action and is handled normally.
+	h.HandleMessage(ctx, "test-session", "what's the weather?", nil, reply)
+	assert.Equal(t, []string{"what's the weather?"}, prompts)
+	h.HandleMessage(ctx, "test-session", "yes", nil, reply)
+	assert.Equal(t, []string{"what's the weather?", "yes"}, prompts)
+
+	// Expired confirmations are not executed.
+	prompts = nil
+	h.HandleMessage(ctx, "test-session", "/jules owner/repo fix the tests", nil, reply)
+	h.mu.Lock()
+	p := h.confirmations["test-session"]
+	p.expires = time.Now().Add(-time.Second)
+	h.confirmations["test-session"] = p
+	h.mu.Unlock()
+	h.HandleMessage(ctx, "test-session", "yes", nil, reply)
+	assert.Contains(t, got, "expired")
+	assert.Empty(t, prompts)
+
+	h.HandleMessage(ctx, "test-session", "/jules owner/repo fix the tests", nil, reply)
+	h.HandleMessage(ctx, "test-session", "yes", nil, reply)
+	assert.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "owner/repo")
+	assert.Equal(t, "ok", got)
+
+	h.HandleMessage(ctx, "test-session", "/jules owner/repo", nil, reply)
+	assert.Contains(t, got, "Usage")
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/stats"
+)
+
+// maxDBStats is how many query kinds /dbstats lists.
+const maxDBStats = 10
+
+// handleDBStats lists the query kinds that took the most database time since
+// startup, to spot the ones slowing replies down.
+func (h *Handler) handleDBStats(ctx context.Context, reply func(string)) {
+	queries := h.stats.Queries()
+	if len(queries) == 0 {
+		reply(h.msg(ctx, "dbstats.none"))
+		return
+	}
+
+	names := make([]string, 0, len(queries))
+	var total stats.QueryUsage
+	for name, q := range queries {
+		names = append(names, name)
+		total.Count += q.Count
+		total.Slow += q.Slow
+		total.Total += q.Total
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if queries[names[i]].Total != queries[names[j]].Total {
+			return queries[names[i]].Total > queries[names[j]].Total
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > maxDBStats {
+		names = names[:maxDBStats]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "dbstats.header", total.Count, formatQueryTime(total.Total), total.Slow))
+	for _, name := range names {
+		q := queries[name]
+		sb.WriteString(fmt.Sprintf("• `%s` — %d× avg %s, max %s, total %s", name, q.Count, formatQueryTime(q.Avg()), formatQueryTime(q.Max), formatQueryTime(q.Total)))
+		if q.Slow > 0 {
+			sb.WriteString(h.msg(ctx, "dbstats.slow", q.Slow))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(h.msg(ctx, "dbstats.footer"))
+	reply(sb.String())
+}
+
+// formatQueryTime rounds a query duration for display.
+func formatQueryTime(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(100 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleMessage_DBStats(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/dbstats", nil, reply)
+	assert.Contains(t, got, "No database queries")
+
+	h.stats.RecordQuery("SELECT memories", 2*time.Millisecond, false)
+	h.stats.RecordQuery("gorm query events", 300*time.Millisecond, true)
+	h.stats.RecordQuery("gorm query events", 100*time.Millisecond, false)
+
+	h.HandleMessage(ctx, "test-session", "/dbstats", nil, reply)
+	assert.Contains(t, got, "**Database Queries**: 3 in 402ms, 1 slow")
+	assert.Contains(t, got, "• `gorm query events` — 2× avg 200ms, max 300ms, total 400ms ⚠️ 1 slow")
+	assert.Contains(t, got, "• `SELECT memories` — 1× avg 2ms")
+	assert.Less(t, strings.Index(got, "gorm query events"), strings.Index(got, "SELECT memories"), "slowest kinds come first")
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+)
+
+const (
+	// maxDigestItems bounds how many feed items are sent to the model.
+	maxDigestItems = 50
+
+	// defaultDigestWindow is used when the session has never run /digest.
+	defaultDigestWindow = 24 * time.Hour
+)
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// handleDigest reads the session's subscribed feeds from the registry and
+// the configured subreddits, drops items already covered by an earlier
+// digest, and has the model summarize the rest.
+func (h *Handler) handleDigest(ctx context.Context, sessionID, text string, reply func(string)) {
+	arg := strings.TrimSpace(text[len("/digest"):])
+	now := time.Now().In(h.config().Location())
+
+	since, explicit, err := h.digestSince(ctx, sessionID, arg, now)
+	if err != nil {
+		reply(h.msg(ctx, "digest.usage", err))
+		return
+	}
+
+	subs, err := h.db.ListSubscriptions(ctx, sessionID)
+	if err != nil {
+		slog.Error("Failed to list subscriptions", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "feeds.failed"))
+		return
+	}
+	subreddits := h.config().Reddit.Names()
+	if len(subs) == 0 && len(subreddits) == 0 {
+		reply(h.msg(ctx, "digest.no_feeds"))
+		return
+	}
+	if !h.allowMission(ctx, sessionID, reply) {
+		return
+	}
+
+	urls := make([]string, len(subs))
+	for i, s := range subs {
+		urls[i] = s.URL
+	}
+	reply(h.msg(ctx, "digest.building", len(urls)+len(subreddits), since.In(now.Location()).Format("Jan 2 15:04")))
+
+	var feedItems, redditItems []tools.RSSItem
+	if len(urls) > 0 {
+		feedItems = h.feedItems(ctx, urls, since)
+	}
+	if len(subreddits) > 0 {
+		redditItems = h.fetchReddit(ctx, h.config(), since)
+	}
+	var items []tools.RSSItem
+	for _, it := range tools.MergeFeedItems(feedItems, redditItems) {
+		// An explicit window re-includes items seen by earlier digests.
+		if !explicit && it.Link != "" {
+			seen, err := h.db.HasHeadline(ctx, it.Link)
+			if err != nil {
+				slog.Warn("Failed to check headline", "url", it.Link, "error", err)
+			}
+			if seen {
+				continue
+			}
+		}
+		items = append(items, it)
+	}
+	if len(items) == 0 {
+		reply(h.msg(ctx, "digest.no_new"))
+		return
+	}
+	if len(items) > maxDigestItems {
+		items = items[:maxDigestItems]
+	}
+
+	digest, err := h.bot.RunMission(ctx, digestPrompt(items))
+	if err != nil {
+		slog.Error("Digest failed", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "digest.failed"))
+		return
+	}
+
+	for _, it := range items {
+		if it.Link == "" {
+			continue
+		}
+		if _, err := h.db.AddHeadline(ctx, it.Link, it.Title); err != nil {
+			slog.Warn("Failed to record headline", "url", it.Link, "error", err)
+		}
+	}
+	if err := h.db.SetLastDigest(ctx, sessionID, now); err != nil {
+		slog.Warn("Failed to record digest run", "sessionID", sessionID, "error", err)
+	}
+	if _, err := h.db.AddSessionBriefing(ctx, sessionID, digest, "Feed digest", []string{"digest"}); err != nil && !errors.Is(err, db.ErrDuplicateBriefing) {
+		slog.Error("Failed to save digest briefing", "error", err)
+	}
+	h.stats.RecordMission()
+	reply(digest)
+}
+
+// digestSince resolves the start of the digest window. Without an argument
+// it is the session's last digest (or the past day); explicit reports
+// whether the user chose the window.
+func (h *Handler) digestSince(ctx context.Context, sessionID, arg string, now time.Time) (time.Time, bool, error) {
+	if arg != "" {
+		if d, err := time.ParseDuration(arg); err == nil && d > 0 {
+			return now.Add(-d), true, nil
+		}
+		t, err := parseExportDate(arg, now)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return t, true, nil
+	}
+	last, err := h.db.GetLastDigest(ctx, sessionID)
+	if err != nil {
+		slog.Warn("Failed to load last digest time", "sessionID", sessionID, "error", err)
+	}
+	if last.IsZero() {
+		return now.Add(-defaultDigestWindow), false, nil
+	}
+	return last, false, nil
+}
+
+// fetchSubreddits reads the top posts of cfg's subreddits.
+func fetchSubreddits(ctx context.Context, cfg *config.Config, since time.Time) []tools.RSSItem {
+	r := cfg.Reddit
+	client := tools.NewReddit(cfg.ExpandEnv(r.ClientID), cfg.ExpandEnv(r.ClientSecret))
+	return client.FetchSubreddits(ctx, r.Names(), r.Period, r.Posts, r.Comments, since)
+}
+
+func digestPrompt(items []tools.RSSItem) string {
+	var sb strings.Builder
+	sb.WriteString("Summarize the following new feed items into a concise Markdown digest grouped by topic. ")
+	sb.WriteString("Lead with the most important items, keep each to one or two sentences, and include every item's link. ")
+	sb.WriteString("Do not search for additional items.\n\nITEMS:\n")
+	for i, it := range items {
+		sb.WriteString(fmt.Sprintf("%d. [%s] %s", i+1, it.Feed, it.Title))
+		if it.Link != "" {
+			sb.WriteString(" — " + it.Link)
+		}
+		if !it.Published.IsZero() {
+			sb.WriteString(" (" + it.Published.Format("Jan 2") + ")")
+		}
+		sb.WriteString("\n")
+		limit := 300
+		if strings.HasPrefix(it.Feed, "r/") {
+			// Leave room for a post's top comments.
+			limit = 800
+		}
+		if desc := plainSnippet(it.Description, limit); desc != "" {
+			sb.WriteString("   " + desc + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// plainSnippet strips HTML from a feed description and truncates it.
+func plainSnippet(s string, limit int) string {
+	s = strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(s, " "))), " ")
+	if r := []rune(s); len(r) > limit {
+		s = string(r[:limit]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessage_Digest(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var prompts []string
+	h.bot = &mockBot{runMissionFunc: func(_ context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return "## Digest\n- Go 1.26 released", nil
+	}}
+	fetches := 0
+	h.fetchFeed = func(_ context.Context, url, etag, _ string) (tools.FeedResult, error) {
+		fetches++
+		if etag == `"v1"` {
+			return tools.FeedResult{ETag: etag, NotModified: true}, nil
+		}
+		return tools.FeedResult{Title: "Go Blog", ETag: `"v1"`, Items: []tools.RSSItem{
+			{Title: "Go 1.26 released", Link: "https://go.dev/blog/go1.26", Description: "<p>Big &amp; fast</p>", Published: time.Now().Add(-time.Hour)},
+			{Title: "Go 1.0 released", Link: "https://go.dev/blog/go1", Published: time.Now().Add(-48 * time.Hour)},
+		}}, nil
+	}
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/digest", nil, reply)
+	assert.Contains(t, got, "No feed subscriptions")
+
+	_, err := database.AddSubscription(ctx, "test-session", "https://go.dev/blog/feed.atom")
+	require.NoError(t, err)
+
+	h.HandleMessage(ctx, "test-session", "/digest", nil, reply)
+	assert.Equal(t, "## Digest\n- Go 1.26 released", got)
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "[Go Blog] Go 1.26 released — https://go.dev/blog/go1.26")
+	assert.Contains(t, prompts[0], "Big & fast")
+	assert.NotContains(t, prompts[0], "Go 1.0 released", "items before the window are left out")
+	assert.Equal(t, 1, fetches)
+
+	// Items already covered by a digest are skipped
+	h.HandleMessage(ctx, "test-session", "/digest", nil, reply)
+	assert.Contains(t, got, "No new feed items")
+	assert.Len(t, prompts, 1)
+
+	// An explicit window includes them again, read from the registry: the
+	// feed isn't due for another fetch.
+	h.HandleMessage(ctx, "test-session", "/digest 7d", nil, reply)
+	assert.Len(t, prompts, 2)
+	assert.Contains(t, prompts[1], "Go 1.0 released")
+	assert.Equal(t, 1, fetches)
+
+	h.HandleMessage(ctx, "test-session", "/digest someday", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	// Both digests were identical, so only one briefing is stored.
+	briefings, err := database.GetRecentBriefings(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, briefings, 1)
+}
+
+func TestHandleMessage_DigestReddit(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+	h.cfg.Reddit = config.RedditConfig{Subreddits: []string{"r/golang"}}
+
+	var prompt string
+	h.bot = &mockBot{runMissionFunc: func(_ context.Context, p string) (string, error) {
+		prompt = p
+		return "## Digest", nil
+	}}
+	_, err := database.AddSubscription(ctx, "test-session", "https://go.dev/blog/feed.atom")
+	require.NoError(t, err)
+	h.fetchFeed = func(context.Context, string, string, string) (tools.FeedResult, error) {
+		return tools.FeedResult{Title: "Go Blog", Items: []tools.RSSItem{{Title: "Go 1.26 released", Link: "https://go.dev/blog/go1.26"}}}, nil
+	}
+	h.fetchReddit = func(_ context.Context, cfg *config.Config, _ time.Time) []tools.RSSItem {
+		assert.Equal(t, []string{"golang"}, cfg.Reddit.Names())
+		return []tools.RSSItem{
+			{Title: "Go 1.26 is out", Link: "https://go.dev/blog/go1.26/", Feed: "r/golang"},
+			{Title: "Ask: iterators", Link: "https://www.reddit.com/r/golang/comments/b2/ask/", Feed: "r/golang", Description: "40 points, 3 comments\nTop comment (12 points): Use iter.Seq"},
+		}
+	}
+
+	var got []string
+	h.HandleMessage(ctx, "test-session", "/digest", nil, func(r string) { got = append(got, r) })
+	assert.Contains(t, got[0], "2 feed(s) and subreddit(s)")
+	assert.Contains(t, prompt, "[Go Blog] Go 1.26 released")
+	assert.NotContains(t, prompt, "Go 1.26 is out", "a post linking a feed item is deduplicated")
+	assert.Contains(t, prompt, "[r/golang] Ask: iterators")
+	assert.Contains(t, prompt, "Top comment (12 points): Use iter.Seq")
+
+	// Posts recorded by the digest are skipped by the next one.
+	seen, err := database.HasHeadline(ctx, "https://www.reddit.com/r/golang/comments/b2/ask/")
+	require.NoError(t, err)
+	assert.True(t, seen)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+)
+
+// elicitationTimeout is how long an MCP server's question waits for each
+// answer before it is cancelled.
+const elicitationTimeout = 5 * time.Minute
+
+// elicitor returns the agent.Elicitor that asks the session's user an MCP
+// server's questions one field at a time, reading the answers from the
+// session's next messages.
+func (h *Handler) elicitor(sessionID string, reply func(string)) agent.Elicitor {
+	return func(ctx context.Context, e agent.MCPElicitation) (map[string]any, error) {
+		answers := make(chan string, 1)
+		h.mu.Lock()
+		if _, busy := h.elicitations[sessionID]; busy {
+			h.mu.Unlock()
+			return nil, agent.ErrElicitationCancelled
+		}
+		h.elicitations[sessionID] = answers
+		h.mu.Unlock()
+		defer func() {
+			h.mu.Lock()
+			delete(h.elicitations, sessionID)
+			h.mu.Unlock()
+		}()
+
+		// next waits for the user's answer, failing on "cancel",
+		// "decline" or no answer.
+		next := func() (string, error) {
+			select {
+			case answer := <-answers:
+				switch strings.ToLower(strings.TrimSpace(answer)) {
+				case "cancel", "/cancel":
+					return "", agent.ErrElicitationCancelled
+				case "decline", "/decline":
+					return "", agent.ErrElicitationDeclined
+				}
+				return answer, nil
+			case <-time.After(elicitationTimeout):
+				reply(h.msg(ctx, "elicit.timeout"))
+				return "", agent.ErrElicitationCancelled
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+
+		reply(h.msg(ctx, "elicit.header", e.Tool, e.Server, e.Message))
+		if len(e.Fields) == 0 {
+			reply(h.msg(ctx, "elicit.confirm"))
+			answer, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if yes, _ := (agent.ElicitField{Type: "boolean"}).Parse(answer); yes != true {
+				return nil, agent.ErrElicitationDeclined
+			}
+			return map[string]any{}, nil
+		}
+
+		values := make(map[string]any, len(e.Fields))
+		for _, f := range e.Fields {
+			reply(h.elicitQuestion(ctx, f))
+			for {
+				answer, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if !f.Required && strings.EqualFold(strings.TrimSpace(answer), "skip") {
+					break
+				}
+				v, err := f.Parse(answer)
+				if err != nil {
+					reply(h.msg(ctx, "elicit.invalid", err))
+					continue
+				}
+				values[f.Name] = v
+				break
+			}
+		}
+		slog.Info("MCP question answered", "sessionID", sessionID, "server", e.Server, "tool", e.Tool)
+		reply(h.msg(ctx, "elicit.sent", e.Server))
+		return values, nil
+	}
+}
+
+// elicitQuestion asks for one field.
+func (h *Handler) elicitQuestion(ctx context.Context, f agent.ElicitField) string {
+	label := f.Title
+	if label == "" {
+		label = f.Name
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s**", label)
+	if f.Description != "" {
+		sb.WriteString(": " + f.Description)
+	}
+	switch {
+	case len(f.Enum) > 0:
+		sb.WriteString("\n" + h.msg(ctx, "elicit.choices", strings.Join(f.Enum, ", ")))
+	case f.Type == "boolean":
+		sb.WriteString("\n" + h.msg(ctx, "elicit.yes_no"))
+	}
+	if !f.Required {
+		sb.WriteString("\n" + h.msg(ctx, "elicit.optional"))
+	}
+	return sb.String()
+}
+
+// answerElicitation passes text to the session's pending MCP question, if
+// any, reporting whether it did.
+func (h *Handler) answerElicitation(sessionID, text string) bool {
+	h.mu.Lock()
+	answers, ok := h.elicitations[sessionID]
+	h.mu.Unlock()
+	if !ok {
+		return false
+	}
+	select {
+	case answers <- text:
+	default:
+		// An answer is already waiting to be read.
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// askInBackground runs the session's elicitor for e, returning the
+// channels of its replies and its result.
+func askInBackground(h *Handler, sessionID string, e agent.MCPElicitation) (<-chan string, <-chan error, *map[string]any) {
+	replies := make(chan string, 16)
+	done := make(chan error, 1)
+	var values map[string]any
+	go func() {
+		var err error
+		values, err = h.elicitor(sessionID, func(r string) { replies <- r })(context.Background(), e)
+		done <- err
+	}()
+	return replies, done, &values
+}
+
+// nextReply waits for the elicitor's next message.
+func nextReply(t *testing.T, replies <-chan string) string {
+	t.Helper()
+	select {
+	case r := <-replies:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reply")
+		return ""
+	}
+}
+
+func TestElicitor(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+
+	replies, done, values := askInBackground(h, "s1", agent.MCPElicitation{
+		Server:  "github",
+		Tool:    "create_issue",
+		Message: "Describe the issue",
+		Fields: []agent.ElicitField{
+			{Name: "title", Type: "string", Required: true},
+			{Name: "priority", Type: "string", Enum: []string{"low", "high"}},
+			{Name: "count", Type: "integer"},
+		},
+	})
+	assert.Contains(t, nextReply(t, replies), "`create_issue` (github) needs some input:\nDescribe the issue")
+	assert.Equal(t, "**title**", nextReply(t, replies))
+
+	require.True(t, h.answerElicitation("s1", "Flaky test"))
+	assert.Contains(t, nextReply(t, replies), "Choose one of: low, high")
+	require.True(t, h.answerElicitation("s1", "urgent"))
+	assert.Contains(t, nextReply(t, replies), "expected one of: low, high")
+	require.True(t, h.answerElicitation("s1", "HIGH"))
+	assert.Contains(t, nextReply(t, replies), "**count**")
+	require.True(t, h.answerElicitation("s1", "skip"))
+	assert.Contains(t, nextReply(t, replies), "Sent your answers")
+
+	require.NoError(t, <-done)
+	assert.Equal(t, map[string]any{"title": "Flaky test", "priority": "high"}, *values)
+	assert.False(t, h.answerElicitation("s1", "hello"), "messages after the question go to the agent")
+}
+
+func TestElicitor_Refused(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+
+	replies, done, _ := askInBackground(h, "s1", agent.MCPElicitation{Server: "git", Tool: "delete_branch", Message: "Delete main?"})
+	nextReply(t, replies)
+	assert.Contains(t, nextReply(t, replies), "Reply `yes` to go ahead")
+
+	// A second question for the same session is not asked over the first.
+	_, err := h.elicitor("s1", func(string) {})(context.Background(), agent.MCPElicitation{})
+	assert.ErrorIs(t, err, agent.ErrElicitationCancelled)
+
+	require.True(t, h.answerElicitation("s1", "no"))
+	assert.ErrorIs(t, <-done, agent.ErrElicitationDeclined)
+
+	replies, done, _ = askInBackground(h, "s1", agent.MCPElicitation{Server: "git", Tool: "delete_branch", Fields: []agent.ElicitField{{Name: "reason", Type: "string", Required: true}}})
+	nextReply(t, replies)
+	nextReply(t, replies)
+	require.True(t, h.answerElicitation("s1", "cancel"))
+	assert.ErrorIs(t, <-done, agent.ErrElicitationCancelled)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+)
+
+// defaultFeedbackDays is the window of a feedback_summary job without a
+// "days" param.
+const defaultFeedbackDays = 7
+
+var issueURL = regexp.MustCompile(`https://github\.com/[\w.-]+/[\w.-]+/issues/\d+`)
+
+// handleFeedback stores the feedback and, when bot.feedbackRepo is set, asks
+// the agent to file it as a GitHub issue.
+func (h *Handler) handleFeedback(ctx context.Context, sessionID, text string, reply func(string)) {
+	content := strings.TrimSpace(text[len("/feedback"):])
+	if content == "" {
+		reply(h.msg(ctx, "feedback.usage"))
+		return
+	}
+	id, err := h.db.AddFeedback(ctx, sessionID, content)
+	if err != nil {
+		slog.Error("Failed to save feedback", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "feedback.failed"))
+		return
+	}
+	reply(h.msg(ctx, "feedback.saved", id))
+
+	repo := h.config().Bot.FeedbackRepo
+	if repo == "" {
+		return
+	}
+	prompt := fmt.Sprintf("Use the GitHub tools to open an issue in repository %s for this user feedback (ravenbot feedback #%d). "+
+		"Write a short descriptive title, include the feedback verbatim in the body, and reply with the issue URL.\n\nFEEDBACK:\n%s", repo, id, content)
+	response, err := h.bot.Chat(ctx, sessionID, prompt)
+	url := issueURL.FindString(response)
+	if err != nil || url == "" {
+		slog.Error("Failed to open feedback issue", "id", id, "repo", repo, "error", err)
+		reply(h.msg(ctx, "feedback.issue_failed"))
+		return
+	}
+	if err := h.db.SetFeedbackIssue(ctx, id, url); err != nil {
+		slog.Warn("Failed to record feedback issue", "id", id, "error", err)
+	}
+	reply(h.msg(ctx, "feedback.issue_opened", url))
+}
+
+// runFeedbackSummary summarizes the feedback of the past params.days days
+// and sends it to all notifiers. Having no feedback is not an error.
+func (h *Handler) runFeedbackSummary(ctx context.Context, job config.JobConfig) error {
+	days := defaultFeedbackDays
+	if v, err := strconv.Atoi(job.Params["days"]); err == nil && v > 0 {
+		days = v
+	}
+	items, err := h.db.ListFeedback(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		slog.Error("Failed to load feedback", "job", job.Name, "error", err)
+		return err
+	}
+	if len(items) == 0 {
+		slog.Info("No feedback to summarize", "job", job.Name, "days", days)
+		return nil
+	}
+
+	summary, err := h.bot.RunMission(ctx, feedbackPrompt(items, days))
+	if err != nil {
+		slog.Error("Feedback summary failed", "job", job.Name, "error", err)
+		return fmt.Errorf("feedback summary failed: %w", err)
+	}
+	h.stats.RecordMission()
+	h.broadcast(ctx, job.Name, summary)
+	return nil
+}
+
+func feedbackPrompt(items []db.Feedback, days int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Summarize the user feedback ravenbot received over the past %d days as a short Markdown report. ", days))
+	sb.WriteString("Group it into themes (bugs, feature requests, praise), count how often each theme came up, and end with the top three suggested actions. ")
+	sb.WriteString("Do not use any tools.\n\nFEEDBACK:\n")
+	for _, f := range items {
+		sb.WriteString(fmt.Sprintf("- #%d (%s): %s", f.ID, f.CreatedAt.Format("Jan 2"), f.Content))
+		if f.IssueURL != "" {
+			sb.WriteString(" [" + f.IssueURL + "]")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
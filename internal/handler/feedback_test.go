@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessage_Feedback(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var prompts []string
+	issueResponse := "Done! https://github.com/acme/ravenbot/issues/42"
+	h.bot = &mockBot{chatFunc: func(_ context.Context, _, message string) (string, error) {
+		prompts = append(prompts, message)
+		if issueResponse == "" {
+			return "", errors.New("github unavailable")
+		}
+		return issueResponse, nil
+	}}
+
+	var replies []string
+	reply := func(r string) { replies = append(replies, r) }
+
+	h.HandleMessage(ctx, "test-session", "/feedback", nil, reply)
+	assert.Contains(t, replies[0], "Usage")
+
+	// Without a feedback repo the feedback is only stored.
+	replies = nil
+	h.HandleMessage(ctx, "test-session", "/feedback Digests are too long", nil, reply)
+	assert.Equal(t, []string{"🙏 Thanks! Feedback `#1` recorded."}, replies)
+	assert.Empty(t, prompts)
+
+	cfg := *h.config()
+	cfg.Bot.FeedbackRepo = "acme/ravenbot"
+	h.UpdateConfig(&cfg)
+
+	replies = nil
+	h.HandleMessage(ctx, "test-session", "/feedback Add Rust news", nil, reply)
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "acme/ravenbot")
+	assert.Contains(t, prompts[0], "Add Rust news")
+	assert.Equal(t, "🐛 Opened an issue for it: https://github.com/acme/ravenbot/issues/42", replies[len(replies)-1])
+
+	issueResponse = ""
+	replies = nil
+	h.HandleMessage(ctx, "test-session", "/feedback Reminders are late", nil, reply)
+	assert.Contains(t, replies[len(replies)-1], "couldn't open a GitHub issue")
+
+	items, err := database.ListFeedback(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Empty(t, items[0].IssueURL)
+	assert.Equal(t, "https://github.com/acme/ravenbot/issues/42", items[1].IssueURL)
+}
+
+func TestRunJob_FeedbackSummary(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var prompt string
+	h.bot = &mockBot{runMissionFunc: func(_ context.Context, p string) (string, error) {
+		prompt = p
+		return "## Feedback summary", nil
+	}}
+	fn := &fileNotifier{}
+	h.SetNotifiers([]notifier.Notifier{fn})
+	job := config.JobConfig{Name: "Weekly Feedback", Type: "feedback_summary", Params: map[string]string{"days": "7"}}
+
+	// Nothing to summarize yet.
+	h.RunJob(ctx, job)
+	assert.Empty(t, prompt)
+	assert.Empty(t, fn.sent)
+
+	_, err := database.AddFeedback(ctx, "test-session", "Love the digests")
+	require.NoError(t, err)
+	h.RunJob(ctx, job)
+	assert.Contains(t, prompt, "past 7 days")
+	assert.Contains(t, prompt, "Love the digests")
+	assert.Equal(t, []string{"## Feedback summary"}, fn.sent)
+}
@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+)
+
+const (
+	// maxRegistryItems bounds the stored items read for a digest or job,
+	// before deduplication and the digest's own limit.
+	maxRegistryItems = 200
+	// minFeedInterval is the shortest fetch interval /feeds interval
+	// accepts: RefreshFeeds runs every five minutes.
+	minFeedInterval = 5 * time.Minute
+	// maxImportFeeds bounds how many feeds one /feeds import subscribes to.
+	maxImportFeeds = 500
+	// maxImportInvalid bounds the rejected URLs listed after an import.
+	maxImportInvalid = 5
+)
+
+// RefreshFeeds fetches every subscribed feed that is due, storing its new
+// items in the registry. It runs on a schedule, so digests and jobs mostly
+// read items already fetched.
+func (h *Handler) RefreshFeeds(ctx context.Context) {
+	feeds, err := h.db.ListFeeds(ctx)
+	if err != nil {
+		slog.Error("Failed to list feeds", "error", err)
+		return
+	}
+	h.refreshFeeds(ctx, feeds)
+}
+
+// refreshFeeds fetches the feeds that are due, with the validators of their
+// last fetch, and records the outcome.
+func (h *Handler) refreshFeeds(ctx context.Context, feeds []db.Feed) {
+	for _, f := range feeds {
+		now := time.Now()
+		if !f.Due(now) || ctx.Err() != nil {
+			continue
+		}
+		res, err := h.fetchFeed(ctx, f.URL, f.ETag, f.LastModified)
+		if err != nil {
+			slog.Warn("Failed to fetch feed", "url", f.URL, "failures", f.Failures+1, "error", err)
+			if err := h.db.RecordFeedError(ctx, f.ID, err, now); err != nil {
+				slog.Error("Failed to record feed error", "url", f.URL, "error", err)
+			}
+			continue
+		}
+		fetch := db.FeedFetch{Title: res.Title, ETag: res.ETag, LastModified: res.LastModified, NotModified: res.NotModified}
+		for _, it := range res.Items {
+			fetch.Items = append(fetch.Items, db.FeedItem{Title: it.Title, Link: it.Link, Description: it.Description, Published: it.Published})
+		}
+		if err := h.db.RecordFeedFetch(ctx, f.ID, fetch, now); err != nil {
+			slog.Error("Failed to record feed fetch", "url", f.URL, "error", err)
+		}
+	}
+}
+
+// feedItems refreshes the feeds at urls that are due and returns their
+// items since since from the registry, deduplicated and newest first.
+func (h *Handler) feedItems(ctx context.Context, urls []string, since time.Time) []tools.RSSItem {
+	feeds, err := h.db.GetFeeds(ctx, urls)
+	if err != nil {
+		slog.Error("Failed to load feeds", "error", err)
+		return nil
+	}
+	h.refreshFeeds(ctx, feeds)
+	stored, err := h.db.FeedItemsSince(ctx, urls, since, maxRegistryItems)
+	if err != nil {
+		slog.Error("Failed to load feed items", "error", err)
+		return nil
+	}
+	items := make([]tools.RSSItem, len(stored))
+	for i, it := range stored {
+		feed := it.FeedTitle
+		if feed == "" {
+			feed = it.FeedURL
+		}
+		items[i] = tools.RSSItem{Title: it.Title, Link: it.Link, Description: it.Description, Published: it.Published, Feed: feed, FeedURL: it.FeedURL}
+	}
+	return tools.MergeFeedItems(items)
+}
+
+// subscribedFeedsPrompt lists the new items of the feeds managed with
+// /subscribe since since, and the configured subreddits, for jobs that opt
+// in with params.feeds = "subscriptions".
+func (h *Handler) subscribedFeedsPrompt(ctx context.Context, since time.Time) string {
+	var sb strings.Builder
+	urls, err := h.db.GetFeedURLs(ctx)
+	if err != nil {
+		slog.Error("Failed to load feed subscriptions", "error", err)
+	}
+	var items []tools.RSSItem
+	for _, it := range h.feedItems(ctx, urls, since) {
+		if seen, _ := h.db.HasHeadline(ctx, it.Link); it.Link == "" || !seen {
+			items = append(items, it)
+		}
+	}
+	if len(items) > maxDigestItems {
+		items = items[:maxDigestItems]
+	}
+	if len(items) > 0 {
+		sb.WriteString("\n\nAlso review these new items from the subscribed RSS/Atom feeds and include the noteworthy ones:\n")
+		for i, it := range items {
+			sb.WriteString(fmt.Sprintf("%d. [%s] %s", i+1, it.Feed, it.Title))
+			if it.Link != "" {
+				sb.WriteString(" — " + it.Link)
+			}
+			sb.WriteString("\n")
+			if desc := plainSnippet(it.Description, 300); desc != "" {
+				sb.WriteString("   " + desc + "\n")
+			}
+		}
+	}
+	if names := h.config().Reddit.Names(); len(names) > 0 {
+		sb.WriteString("\n\nAlso check the top posts of these subreddits with the reddit_top tool: r/" + strings.Join(names, ", r/") + "\n")
+	}
+	return sb.String()
+}
+
+// lastJobSuccess returns when the job last ran successfully, or a day ago
+// if it never has.
+func (h *Handler) lastJobSuccess(ctx context.Context, name string) time.Time {
+	runs, err := h.db.ListJobRuns(ctx, name, 20)
+	if err != nil {
+		slog.Warn("Failed to load job history", "name", name, "error", err)
+	}
+	for _, r := range runs {
+		if r.Status == db.JobSucceeded {
+			return r.StartedAt
+		}
+	}
+	return time.Now().Add(-defaultDigestWindow)
+}
+
+func (h *Handler) handleFeeds(ctx context.Context, sessionID, text string, n notifier.Notifier, reply func(string)) {
+	args := strings.Fields(text[len("/feeds"):])
+	switch {
+	case len(args) == 0:
+	case len(args) == 3 && strings.EqualFold(args[0], "interval"):
+		h.handleFeedInterval(ctx, sessionID, args[1], args[2], reply)
+		return
+	case len(args) == 1 && strings.EqualFold(args[0], "import"):
+		h.handleFeedImport(ctx, sessionID, reply)
+		return
+	case len(args) == 1 && strings.EqualFold(args[0], "export"):
+		h.handleFeedExport(ctx, sessionID, n, reply)
+		return
+	default:
+		reply(h.msg(ctx, "feeds.usage"))
+		return
+	}
+
+	subs, err := h.db.ListSubscriptions(ctx, sessionID)
+	if err != nil {
+		slog.Error("Failed to list subscriptions", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "feeds.failed"))
+		return
+	}
+	if len(subs) == 0 {
+		reply(h.msg(ctx, "feeds.none"))
+		return
+	}
+	urls := make([]string, len(subs))
+	for i, s := range subs {
+		urls[i] = s.URL
+	}
+	feeds, err := h.db.GetFeeds(ctx, urls)
+	if err != nil {
+		slog.Error("Failed to load feeds", "sessionID", sessionID, "error", err)
+	}
+	byURL := make(map[string]db.Feed, len(feeds))
+	for _, f := range feeds {
+		byURL[f.URL] = f
+	}
+
+	loc := h.config().Location()
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "feeds.header", len(subs)))
+	for _, s := range subs {
+		sb.WriteString(fmt.Sprintf("• `#%d` %s", s.ID, s.URL))
+		if f, ok := byURL[s.URL]; ok {
+			if f.Title != "" {
+				sb.WriteString(" — " + f.Title)
+			}
+			switch {
+			case f.LastFetched == nil:
+				sb.WriteString(" · " + h.msg(ctx, "feeds.never"))
+			case f.Failures > 0:
+				sb.WriteString(" · " + h.msg(ctx, "feeds.failing", f.Failures, f.LastError))
+			default:
+				sb.WriteString(" · " + h.msg(ctx, "feeds.ok", f.LastFetched.In(loc).Format("Jan 2 15:04")))
+			}
+			if f.Interval > 0 {
+				sb.WriteString(" · " + h.msg(ctx, "feeds.every", formatInterval(f.Interval)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(h.msg(ctx, "feeds.footer"))
+	reply(sb.String())
+}
+
+// handleFeedInterval sets how often one of the session's feeds, by ID or
+// URL, is fetched.
+func (h *Handler) handleFeedInterval(ctx context.Context, sessionID, feed, value string, reply func(string)) {
+	var interval time.Duration
+	if !strings.EqualFold(value, "default") {
+		d, err := parseInterval(value)
+		if err != nil || d < minFeedInterval {
+			reply(h.msg(ctx, "feeds.usage"))
+			return
+		}
+		interval = d
+	}
+	subs, err := h.db.ListSubscriptions(ctx, sessionID)
+	if err != nil {
+		slog.Error("Failed to list subscriptions", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "feeds.failed"))
+		return
+	}
+	id, _ := strconv.ParseInt(strings.TrimPrefix(feed, "#"), 10, 64)
+	var feedURL string
+	for _, s := range subs {
+		if s.ID == id || s.URL == feed {
+			feedURL = s.URL
+		}
+	}
+	if feedURL == "" {
+		reply(h.msg(ctx, "unsubscribe.not_found", feed))
+		return
+	}
+	if err := h.db.RegisterFeeds(ctx, feedURL); err != nil {
+		slog.Error("Failed to register feed", "url", feedURL, "error", err)
+	}
+	if _, err := h.db.SetFeedInterval(ctx, feedURL, interval); err != nil {
+		slog.Error("Failed to set feed interval", "url", feedURL, "error", err)
+		reply(h.msg(ctx, "feeds.failed"))
+		return
+	}
+	if interval == 0 {
+		reply(h.msg(ctx, "feeds.interval_default", feedURL, formatInterval(db.DefaultFeedInterval)))
+		return
+	}
+	reply(h.msg(ctx, "feeds.interval_done", feedURL, formatInterval(interval)))
+}
+
+// handleFeedImport subscribes the session to the feeds of the OPML file
+// sent with the command.
+func (h *Handler) handleFeedImport(ctx context.Context, sessionID string, reply func(string)) {
+	attachments := notifier.AttachmentsFromContext(ctx)
+	if len(attachments) == 0 {
+		reply(h.msg(ctx, "feeds.import_no_file"))
+		return
+	}
+	data, err := attachments[0].Download(ctx)
+	if err != nil {
+		slog.Warn("Failed to download OPML file", "sessionID", sessionID, "name", attachments[0].Name, "error", err)
+		reply(h.msg(ctx, "feeds.import_download"))
+		return
+	}
+	feeds, err := tools.ParseOPML(data)
+	if err != nil {
+		reply(h.msg(ctx, "feeds.import_failed", err))
+		return
+	}
+	if len(feeds) == 0 {
+		reply(h.msg(ctx, "feeds.import_empty"))
+		return
+	}
+	if len(feeds) > maxImportFeeds {
+		reply(h.msg(ctx, "feeds.import_too_many", len(feeds), maxImportFeeds))
+		return
+	}
+
+	var added, existing int
+	var invalid []string
+	for _, f := range feeds {
+		if err := tools.ValidateURL(ctx, f.URL); err != nil {
+			slog.Warn("Rejected imported feed URL", "sessionID", sessionID, "url", f.URL, "error", err)
+			invalid = append(invalid, f.URL)
+			continue
+		}
+		ok, err := h.db.AddSubscription(ctx, sessionID, f.URL)
+		if err != nil {
+			slog.Error("Failed to add subscription", "sessionID", sessionID, "url", f.URL, "error", err)
+			reply(h.msg(ctx, "subscribe.failed"))
+			return
+		}
+		if ok {
+			added++
+		} else {
+			existing++
+		}
+		if f.Title != "" {
+			if err := h.db.SetFeedTitle(ctx, f.URL, f.Title); err != nil {
+				slog.Warn("Failed to set feed title", "url", f.URL, "error", err)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "feeds.import_done", added, existing, len(invalid)))
+	for i, u := range invalid {
+		if i == maxImportInvalid {
+			sb.WriteString(h.msg(ctx, "feeds.import_more", len(invalid)-i))
+			break
+		}
+		sb.WriteString("\n• " + u)
+	}
+	reply(sb.String())
+}
+
+// handleFeedExport sends the session's feeds as an OPML file.
+func (h *Handler) handleFeedExport(ctx context.Context, sessionID string, n notifier.Notifier, reply func(string)) {
+	subs, err := h.db.ListSubscriptions(ctx, sessionID)
+	if err != nil {
+		slog.Error("Failed to list subscriptions", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "feeds.failed"))
+		return
+	}
+	if len(subs) == 0 {
+		reply(h.msg(ctx, "feeds.none"))
+		return
+	}
+	sender, ok := n.(notifier.FileSender)
+	if !ok {
+		reply(h.msg(ctx, "feeds.export_no_files"))
+		return
+	}
+	urls := make([]string, len(subs))
+	for i, s := range subs {
+		urls[i] = s.URL
+	}
+	registered, err := h.db.GetFeeds(ctx, urls)
+	if err != nil {
+		slog.Error("Failed to load feeds", "sessionID", sessionID, "error", err)
+	}
+	titles := make(map[string]string, len(registered))
+	for _, f := range registered {
+		titles[f.URL] = f.Title
+	}
+	feeds := make([]tools.OPMLFeed, len(urls))
+	for i, u := range urls {
+		feeds[i] = tools.OPMLFeed{Title: titles[u], URL: u}
+	}
+	data, err := tools.WriteOPML("ravenbot feeds", feeds, time.Now().In(h.config().Location()))
+	if err != nil {
+		slog.Error("Failed to write OPML", "error", err)
+		reply(h.msg(ctx, "feeds.failed"))
+		return
+	}
+	if err := sender.SendFile(ctx, "ravenbot-feeds.opml", "text/x-opml", data, h.msg(ctx, "feeds.export_caption", len(feeds))); err != nil {
+		slog.Error("Failed to send OPML file", "notifier", n.Name(), "error", err)
+		reply(h.msg(ctx, "export.send_failed"))
+	}
+}
+
+// parseInterval parses a Go duration, or a whole number of days ("2d").
+func parseInterval(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid interval %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// formatInterval shows an interval in its largest whole unit: 30m, 2h, 1d.
+func formatInterval(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOPML = `<?xml version="1.0"?>
+<opml version="2.0"><body>
+<outline text="Go Blog" xmlUrl="https://8.8.8.8/feed.xml"/>
+<outline text="Folder">
+  <outline text="Other" title="Other feed" xmlUrl="https://1.1.1.1/rss"/>
+  <outline text="Local" xmlUrl="http://127.0.0.1/rss"/>
+</outline>
+</body></opml>`
+
+func TestHandleMessage_FeedsImportExport(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.opml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testOPML))
+	}))
+	defer server.Close()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/feeds import", nil, reply)
+	assert.Contains(t, got, "Attach an OPML file")
+
+	missing := notifier.WithAttachments(ctx, []notifier.Attachment{notifier.NewAttachment("missing.opml", "", server.URL+"/missing.opml")})
+	h.HandleMessage(missing, "test-session", "/feeds import", nil, reply)
+	assert.Contains(t, got, "Couldn't download the OPML file")
+
+	// The chat never sees a failed download's error, which for Telegram
+	// would quote the bot token in the file URL.
+	unreachable := notifier.NewAttachment("feeds.opml", "", "http://127.0.0.1:1/file/bot123:SECRET/feeds.opml")
+	h.HandleMessage(notifier.WithAttachments(ctx, []notifier.Attachment{unreachable}), "test-session", "/feeds import", nil, reply)
+	assert.Contains(t, got, "Couldn't download the OPML file")
+	assert.NotContains(t, got, "SECRET")
+	_, err := unreachable.Download(ctx)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "SECRET")
+
+	_, err = database.AddSubscription(ctx, "test-session", "https://8.8.8.8/feed.xml")
+	require.NoError(t, err)
+	withFile := notifier.WithAttachments(ctx, []notifier.Attachment{notifier.NewAttachment("feeds.opml", "text/x-opml", server.URL+"/feeds.opml")})
+	h.HandleMessage(withFile, "test-session", "/feeds import", nil, reply)
+	assert.Contains(t, got, "1 added, 1 already subscribed, 1 rejected")
+	assert.Contains(t, got, "http://127.0.0.1/rss")
+
+	subs, err := database.ListSubscriptions(ctx, "test-session")
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	assert.Equal(t, "https://1.1.1.1/rss", subs[1].URL)
+
+	h.HandleMessage(ctx, "test-session", "/feeds export", nil, reply)
+	assert.Contains(t, got, "Files aren't supported")
+
+	fn := &fileNotifier{}
+	h.HandleMessage(ctx, "test-session", "/feeds export", fn, reply)
+	assert.Equal(t, "ravenbot-feeds.opml", fn.name)
+	assert.Equal(t, "text/x-opml", fn.contentType)
+	feeds, err := tools.ParseOPML(fn.data)
+	require.NoError(t, err)
+	assert.Equal(t, []tools.OPMLFeed{
+		{Title: "Go Blog", URL: "https://8.8.8.8/feed.xml"},
+		{Title: "Other feed", URL: "https://1.1.1.1/rss"},
+	}, feeds, "imported titles fill in feeds not fetched yet")
+
+	h.HandleMessage(ctx, "test-session", "/feeds import now", nil, reply)
+	assert.Contains(t, got, "Usage")
+}
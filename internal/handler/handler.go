@@ -2,12 +2,17 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/raythurman2386/ravenbot/internal/agent"
 	"github.com/raythurman2386/ravenbot/internal/config"
 	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/export"
+	"github.com/raythurman2386/ravenbot/internal/i18n"
 	"github.com/raythurman2386/ravenbot/internal/notifier"
 	"github.com/raythurman2386/ravenbot/internal/stats"
+	"github.com/raythurman2386/ravenbot/internal/timeparse"
+	"github.com/raythurman2386/ravenbot/internal/tools"
 	"log/slog"
 	"strconv"
 	"strings"
@@ -38,6 +43,47 @@ type Bot interface {
 	ClearSession(sessionID string)
 }
 
+// ToolLister is implemented by bots that can describe their available tools.
+type ToolLister interface {
+	ToolCatalog(ctx context.Context) []agent.ToolGroup
+}
+
+// PromptProvider is implemented by bots that can run the prompt templates
+// of their MCP servers.
+type PromptProvider interface {
+	MCPPrompts(ctx context.Context) []agent.MCPPrompt
+	RenderMCPPrompt(ctx context.Context, server, name string, args map[string]string) (string, error)
+}
+
+// MCPHealthReporter is implemented by bots that supervise MCP servers.
+type MCPHealthReporter interface {
+	MCPHealth() []agent.MCPHealth
+}
+
+// ResourceWatcher is implemented by bots whose MCP servers can report
+// changes to their resources.
+type ResourceWatcher interface {
+	WatchMCPResource(ctx context.Context, server, uri string) error
+	UnwatchMCPResource(ctx context.Context, server, uri string) error
+	ReadMCPResource(ctx context.Context, server, uri string) (string, error)
+	OnMCPResourceUpdated(fn func(server, uri string))
+}
+
+// MCPLogLeveler is implemented by bots that can change the level of their
+// MCP servers' log messages.
+type MCPLogLeveler interface {
+	SetMCPLogLevel(ctx context.Context, server, level string) error
+}
+
+// MCPServerManager is implemented by bots that can add, remove and
+// refresh MCP servers while running. AddMCPServer returns the agent a new
+// server's tools go to, RefreshMCPServer how many tools it now has.
+type MCPServerManager interface {
+	AddMCPServer(ctx context.Context, name string, serverCfg config.MCPServerConfig) (string, error)
+	RemoveMCPServer(ctx context.Context, name string) error
+	RefreshMCPServer(ctx context.Context, name string) (int, error)
+}
+
 // Handler owns all message routing, command handling, and job execution.
 type Handler struct {
 	bot       Bot
@@ -46,23 +92,163 @@ type Handler struct {
 	stats     *stats.Stats
 	notifiers []notifier.Notifier
 
+	// cfgMu guards cfg and notifiers, which are swapped on /reload.
+	cfgMu sync.RWMutex
+
+	// Per-session limits from cfg.RateLimit; nil means unlimited.
+	messageLimiter *rateLimiter
+	missionLimiter *rateLimiter
+	// jobSlots holds a token per running job, up to cfg.JobConcurrency();
+	// nil means unlimited.
+	jobSlots chan struct{}
+
+	// fetchFeed fetches a feed for the registry; replaced in tests.
+	fetchFeed func(ctx context.Context, url, etag, lastModified string) (tools.FeedResult, error)
+	// fetchReddit loads the configured subreddits' posts for /digest;
+	// replaced in tests.
+	fetchReddit func(ctx context.Context, cfg *config.Config, since time.Time) []tools.RSSItem
+	// julesSession and julesActivities read a Jules session for the
+	// poller; replaced in tests.
+	julesSession    func(ctx context.Context, apiKey, name string) (tools.JulesSession, error)
+	julesActivities func(ctx context.Context, apiKey, name string) ([]tools.JulesActivity, error)
+	// postWebhook sends a webhook job's payload; replaced in tests.
+	postWebhook func(ctx context.Context, url, secret, event string, payload any) error
+
+	// reload re-reads the configuration and applies it; see SetReloader.
+	reload func(ctx context.Context) (string, error)
+
 	// replies maps sessionID → reply function for reminder delivery
 	replies map[string]func(string)
-	mu      sync.Mutex
+	// usageMark is the counters already persisted by FlushUsage.
+	usageMark stats.Totals
+	usageMu   sync.Mutex
+
+	// confirmations holds each session's action awaiting "yes"; see confirm.
+	confirmations map[string]pendingConfirmation
+	// elicitations receives the answers to each session's pending MCP
+	// server question; see elicitor.
+	elicitations map[string]chan string
+	// watchNotified is when each watched resource was last announced.
+	watchNotified map[string]time.Time
+	// systemAlerts is the thresholds each system_report job last found
+	// crossed, so an alert is sent once rather than on every run.
+	systemAlerts map[string]map[string]bool
+	mu           sync.Mutex
 }
 
 // New creates a Handler with all required dependencies.
 func New(bot Bot, database *db.DB, cfg *config.Config, s *stats.Stats, notifiers []notifier.Notifier) *Handler {
-	return &Handler{
+	h := &Handler{
 		bot:       bot,
 		db:        database,
 		cfg:       cfg,
 		stats:     s,
 		notifiers: notifiers,
 		replies:   make(map[string]func(string)),
+
+		confirmations: make(map[string]pendingConfirmation),
+		elicitations:  make(map[string]chan string),
+		watchNotified: make(map[string]time.Time),
+		systemAlerts:  make(map[string]map[string]bool),
+
+		fetchFeed:       tools.FetchFeed,
+		fetchReddit:     fetchSubreddits,
+		julesSession:    tools.GetJulesSession,
+		julesActivities: tools.ListJulesActivities,
+		postWebhook:     tools.PostWebhook,
+	}
+	h.setRateLimits(cfg.RateLimit)
+	h.setJobSlots(cfg.JobConcurrency())
+	return h
+}
+
+// setRateLimits rebuilds the limiters, resetting all buckets. The caller must
+// hold h.cfgMu or have exclusive access to h.
+func (h *Handler) setRateLimits(rl config.RateLimitConfig) {
+	h.messageLimiter = newRateLimiter(rl.MessagesPerMinute, time.Minute)
+	h.missionLimiter = newRateLimiter(rl.MissionsPerHour, time.Hour)
+}
+
+// SetReloader registers the function invoked by /reload. It returns a short
+// summary of what was applied.
+func (h *Handler) SetReloader(fn func(ctx context.Context) (string, error)) {
+	h.reload = fn
+}
+
+// setJobSlots replaces the job concurrency limit. Running jobs free their
+// slot in the old one. The caller must hold h.cfgMu or have exclusive
+// access to h.
+func (h *Handler) setJobSlots(n int) {
+	h.jobSlots = nil
+	if n > 0 {
+		h.jobSlots = make(chan struct{}, n)
 	}
 }
 
+// UpdateConfig swaps in a reloaded configuration.
+func (h *Handler) UpdateConfig(cfg *config.Config) {
+	h.cfgMu.Lock()
+	if h.cfg == nil || h.cfg.RateLimit != cfg.RateLimit {
+		h.setRateLimits(cfg.RateLimit)
+	}
+	if h.cfg == nil || h.cfg.JobConcurrency() != cfg.JobConcurrency() {
+		h.setJobSlots(cfg.JobConcurrency())
+	}
+	h.cfg = cfg
+	h.cfgMu.Unlock()
+}
+
+// SetNotifiers replaces the notifiers used for job reports and reminder
+// fallback. Registered reply functions belong to the old notifiers, so they
+// are dropped; sessions re-register on their next message.
+func (h *Handler) SetNotifiers(notifiers []notifier.Notifier) {
+	h.cfgMu.Lock()
+	h.notifiers = notifiers
+	h.cfgMu.Unlock()
+
+	h.mu.Lock()
+	h.replies = make(map[string]func(string))
+	h.mu.Unlock()
+}
+
+func (h *Handler) config() *config.Config {
+	h.cfgMu.RLock()
+	defer h.cfgMu.RUnlock()
+	return h.cfg
+}
+
+// allowMessage and allowMission apply the per-session rate limits, replying
+// with a friendly notice when the session has to wait.
+func (h *Handler) allowMessage(ctx context.Context, sessionID string, reply func(string)) bool {
+	h.cfgMu.RLock()
+	limiter, limit := h.messageLimiter, h.cfg.RateLimit.MessagesPerMinute
+	h.cfgMu.RUnlock()
+	if ok, wait := limiter.allow(sessionID); !ok {
+		slog.Warn("Message rate limited", "sessionID", sessionID, "retryAfter", wait)
+		reply(h.msg(ctx, "ratelimit.messages", limit, wait))
+		return false
+	}
+	return true
+}
+
+func (h *Handler) allowMission(ctx context.Context, sessionID string, reply func(string)) bool {
+	h.cfgMu.RLock()
+	limiter, limit := h.missionLimiter, h.cfg.RateLimit.MissionsPerHour
+	h.cfgMu.RUnlock()
+	if ok, wait := limiter.allow(sessionID); !ok {
+		slog.Warn("Mission rate limited", "sessionID", sessionID, "retryAfter", wait)
+		reply(h.msg(ctx, "ratelimit.missions", limit, wait))
+		return false
+	}
+	return true
+}
+
+func (h *Handler) currentNotifiers() []notifier.Notifier {
+	h.cfgMu.RLock()
+	defer h.cfgMu.RUnlock()
+	return h.notifiers
+}
+
 // HandleMessage is the unified entry point for all incoming messages.
 // It routes commands and general conversation to the appropriate handler.
 func (h *Handler) HandleMessage(ctx context.Context, sessionID, text string, n notifier.Notifier, reply func(string)) {
@@ -70,240 +256,950 @@ func (h *Handler) HandleMessage(ctx context.Context, sessionID, text string, n n
 	if text == "" {
 		return
 	}
+	ctx = i18n.WithLocale(ctx, h.sessionLocale(ctx, sessionID))
+	if n != nil {
+		ctx = context.WithValue(ctx, platformKey{}, n.Name())
+	}
 
 	// Security: Prevent DoS by limiting input length
 	if len(text) > MaxInputLength {
 		slog.Warn("Message rejected: too long", "sessionID", sessionID, "length", len(text))
-		reply(fmt.Sprintf("⚠️ Message too long (max %d characters). Please shorten your request.", MaxInputLength))
+		reply(h.msg(ctx, "input.too_long", MaxInputLength))
+		return
+	}
+
+	// Answers to an MCP server's question go to the waiting tool call.
+	if h.answerElicitation(sessionID, text) {
+		return
+	}
+
+	if !h.allowMessage(ctx, sessionID, reply) {
 		return
 	}
 
-	h.stats.RecordMessage()
+	// Conversation state lives in the channel's active thread; reminders,
+	// todos and other personal data stay keyed by the channel.
+	chatID := h.activeSession(ctx, sessionID)
+	h.stats.RecordSessionMessage(chatID)
 
 	// Register reply function for reminder delivery
 	h.mu.Lock()
 	h.replies[sessionID] = reply
 	h.mu.Unlock()
 
+	// Long MCP tool calls report their progress to the chat, their servers
+	// can ask the user questions, and the files tools return are attached.
+	// Jules sessions started on the user's behalf are followed up on,
+	// and searches of past research see the user's own briefings.
+	ctx = agent.WithMCPProgress(ctx, func(p agent.MCPProgress) {
+		reply(h.progressMessage(ctx, p))
+	})
+	ctx = agent.WithMCPElicitor(ctx, h.elicitor(sessionID, reply))
+	ctx = agent.WithJulesSessions(ctx, func(s agent.JulesStart) {
+		h.trackJulesSession(ctx, sessionID, s)
+	})
+	ctx = agent.WithHistorySession(ctx, sessionID)
+	if sender, ok := n.(notifier.FileSender); ok {
+		ctx = agent.WithMCPAttachments(ctx, func(a agent.MCPAttachment) {
+			if err := sender.SendFile(ctx, a.Name, a.MIMEType, a.Data, h.msg(ctx, "attachment.caption", a.Tool, a.Server)); err != nil {
+				slog.Error("Failed to send MCP tool attachment", "server", a.Server, "tool", a.Tool, "error", err)
+			}
+		})
+	}
+
 	// Start typing indicator if notifier is provided
 	if n != nil {
 		stopTyping := n.StartTyping(ctx)
 		defer stopTyping()
 	}
 
+	if h.resolveConfirmation(ctx, sessionID, text, reply) {
+		return
+	}
+
+	if expanded, ok := h.expandShortcut(text, time.Now().In(h.config().Location())); ok {
+		slog.Info("Expanded shortcut", "sessionID", sessionID, "shortcut", strings.Fields(text)[0])
+		text = expanded
+	}
+
 	lowerText := strings.ToLower(text)
 	switch {
 	case lowerText == "/help" || strings.HasPrefix(lowerText, "/help "):
-		reply(h.cfg.Bot.HelpMessage)
+		reply(h.config().Bot.Help(i18n.FromContext(ctx)) + h.shortcutHelp(ctx))
 
 	case lowerText == "/status" || strings.HasPrefix(lowerText, "/status "):
-		h.handleStatus(ctx, sessionID, reply)
+		h.handleStatus(ctx, chatID, reply)
 
 	case lowerText == "/reset" || strings.HasPrefix(lowerText, "/reset "):
-		h.bot.ClearSession(sessionID)
-		reply("🔄 Conversation cleared! Let's start fresh.")
+		h.confirm(ctx, sessionID, h.msg(ctx, "reset.confirm"), func(ctx context.Context, reply func(string)) {
+			h.bot.ClearSession(chatID)
+			reply(h.msg(ctx, "reset.done"))
+		}, reply)
 
 	case lowerText == "/uptime" || strings.HasPrefix(lowerText, "/uptime "):
 		reply(h.stats.Summary())
 
+	case lowerText == "/dbstats":
+		h.handleDBStats(ctx, reply)
+	case lowerText == "/mcpstats":
+		h.handleMCPStats(ctx, reply)
+	case lowerText == "/usage" || strings.HasPrefix(lowerText, "/usage "):
+		h.handleUsage(ctx, chatID, text, reply)
+
+	case lowerText == "/summary" || strings.HasPrefix(lowerText, "/summary "):
+		h.handleSummary(ctx, sessionID, chatID, text, reply)
+
+	case lowerText == "/sessions":
+		h.handleSessions(ctx, sessionID, chatID, reply)
+
+	case lowerText == "/session" || strings.HasPrefix(lowerText, "/session "):
+		h.handleSession(ctx, sessionID, chatID, text, reply)
+
+	case lowerText == "/language" || strings.HasPrefix(lowerText, "/language "):
+		h.handleLanguage(ctx, sessionID, text, reply)
+
+	case lowerText == "/tools":
+		h.handleTools(ctx, reply)
+
+	case lowerText == "/prompts":
+		h.handlePrompts(ctx, reply)
+
+	case lowerText == "/prompt" || strings.HasPrefix(lowerText, "/prompt "):
+		h.handlePrompt(ctx, chatID, text, reply)
+
+	case lowerText == "/jobs" || strings.HasPrefix(lowerText, "/jobs "):
+		h.handleJobs(ctx, sessionID, text, reply)
+
+	case lowerText == "/job" || strings.HasPrefix(lowerText, "/job "):
+		h.handleJob(ctx, sessionID, text, reply)
+
+	case lowerText == "/jobstatus" || strings.HasPrefix(lowerText, "/jobstatus "):
+		h.handleJobStatus(ctx, text, reply)
+
+	case lowerText == "/backup" || strings.HasPrefix(lowerText, "/backup "):
+		h.handleBackup(ctx, sessionID, text, reply)
+
+	case lowerText == "/set" || strings.HasPrefix(lowerText, "/set "):
+		h.handleSet(ctx, sessionID, text, reply)
+	case lowerText == "/wipe-user" || strings.HasPrefix(lowerText, "/wipe-user "):
+		h.handleWipeUser(ctx, sessionID, text, reply)
+	case lowerText == "/mcplog" || strings.HasPrefix(lowerText, "/mcplog "):
+		h.handleMCPLog(ctx, sessionID, text, reply)
+	case lowerText == "/mcp" || strings.HasPrefix(lowerText, "/mcp "):
+		h.handleMCP(ctx, sessionID, text, reply)
+	case lowerText == "/reload":
+		h.handleReload(ctx, sessionID, reply)
+
 	case strings.HasPrefix(lowerText, "/remind "):
 		h.handleRemind(ctx, sessionID, text, reply)
 
+	case lowerText == "/snooze" || strings.HasPrefix(lowerText, "/snooze "):
+		h.handleSnooze(ctx, sessionID, text, reply)
+
+	case lowerText == "/todo" || strings.HasPrefix(lowerText, "/todo "):
+		h.handleTodo(ctx, sessionID, text, reply)
+
+	case lowerText == "/remember" || strings.HasPrefix(lowerText, "/remember "):
+		h.handleRemember(ctx, sessionID, text, reply)
+
+	case lowerText == "/recall" || strings.HasPrefix(lowerText, "/recall "):
+		h.handleRecall(ctx, sessionID, text, reply)
+
+	case lowerText == "/forget" || strings.HasPrefix(lowerText, "/forget "):
+		h.handleForget(ctx, sessionID, text, reply)
+
+	case lowerText == "/subscribe" || strings.HasPrefix(lowerText, "/subscribe "):
+		h.handleSubscribe(ctx, sessionID, text, reply)
+
+	case lowerText == "/unsubscribe" || strings.HasPrefix(lowerText, "/unsubscribe "):
+		h.handleUnsubscribe(ctx, sessionID, text, reply)
+
+	case lowerText == "/feeds" || strings.HasPrefix(lowerText, "/feeds "):
+		h.handleFeeds(ctx, sessionID, text, n, reply)
+
+	case lowerText == "/watch" || strings.HasPrefix(lowerText, "/watch "):
+		h.handleWatch(ctx, sessionID, text, reply)
+
+	case lowerText == "/unwatch" || strings.HasPrefix(lowerText, "/unwatch "):
+		h.handleUnwatch(ctx, sessionID, text, reply)
+
+	case lowerText == "/digest" || strings.HasPrefix(lowerText, "/digest "):
+		h.handleDigest(ctx, sessionID, text, reply)
+
+	case lowerText == "/transcript" || strings.HasPrefix(lowerText, "/transcript "):
+		h.handleTranscript(ctx, chatID, text, n, reply)
+
 	case strings.HasPrefix(lowerText, "/export"):
-		h.handleExport(ctx, text, reply)
+		h.handleExport(ctx, sessionID, text, n, reply)
+
+	case lowerText == "/history" || strings.HasPrefix(lowerText, "/history "):
+		h.handleHistory(ctx, sessionID, text, reply)
+
+	case lowerText == "/feedback" || strings.HasPrefix(lowerText, "/feedback "):
+		h.handleFeedback(ctx, sessionID, text, reply)
 
 	case strings.HasPrefix(lowerText, "/research "):
-		h.handleResearch(ctx, text, reply)
+		h.handleResearch(ctx, sessionID, text, reply)
 
 	case strings.HasPrefix(lowerText, "/jules "):
-		h.handleJules(ctx, sessionID, text, reply)
+		h.handleJules(ctx, sessionID, chatID, text, reply)
 
 	default:
-		h.handleChat(ctx, sessionID, text, reply)
+		h.handleChat(ctx, chatID, text, reply)
 	}
 }
 
 func (h *Handler) handleStatus(ctx context.Context, sessionID string, reply func(string)) {
-	reply("🔍 Checking server health...")
-	response, err := h.bot.Chat(ctx, sessionID, h.cfg.Bot.StatusPrompt)
+	reply(h.msg(ctx, "status.checking"))
+	response, err := h.bot.Chat(ctx, sessionID, h.config().Bot.StatusPrompt)
 	if err != nil {
 		slog.Error("Status check failed", "sessionID", sessionID, "error", err)
-		reply("❌ Status check failed. I couldn't retrieve the system health metrics.")
+		response = h.msg(ctx, "status.failed")
+	}
+	reply(response + h.mcpHealth(ctx))
+}
+
+// mcpHealth lists the MCP servers' connection state and tool calls for
+// /status, or is empty if the bot has none.
+func (h *Handler) mcpHealth(ctx context.Context) string {
+	reporter, ok := h.bot.(MCPHealthReporter)
+	if !ok {
+		return ""
+	}
+	servers := reporter.MCPHealth()
+	if len(servers) == 0 {
+		return ""
+	}
+
+	loc := h.config().Location()
+	calls := h.stats.MCPServerCalls()
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "status.mcp_header"))
+	for _, s := range servers {
+		since := s.Since.In(loc).Format("Jan 2 15:04")
+		switch {
+		case s.Healthy:
+			sb.WriteString(h.msg(ctx, "status.mcp_up", s.Server, since))
+		case s.Since.IsZero() && s.Err != "":
+			sb.WriteString(h.msg(ctx, "status.mcp_failed", s.Server, s.Err))
+		case s.Since.IsZero():
+			sb.WriteString(h.msg(ctx, "status.mcp_pending", s.Server))
+		default:
+			sb.WriteString(h.msg(ctx, "status.mcp_down", s.Server, since, s.Err))
+		}
+		if s.Restarts > 0 {
+			sb.WriteString(h.msg(ctx, "status.mcp_restarts", s.Restarts))
+		}
+		if s.Healthy && s.Protocol != "" && s.Protocol < agent.LatestMCPProtocol {
+			sb.WriteString(h.msg(ctx, "status.mcp_protocol", s.Protocol))
+		}
+		if s.LastCrash != "" {
+			sb.WriteString(h.msg(ctx, "status.mcp_crash", s.CrashedAt.In(loc).Format("Jan 2 15:04"), s.LastCrash))
+		}
+		if u := calls[s.Server]; u.Calls > 0 {
+			sb.WriteString(h.msg(ctx, "status.mcp_calls", u.Calls, formatQueryTime(u.Avg()), u.ErrorPercent()))
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func (h *Handler) handleTools(ctx context.Context, reply func(string)) {
+	lister, ok := h.bot.(ToolLister)
+	if !ok {
+		reply(h.msg(ctx, "tools.unavailable"))
 		return
 	}
-	reply(response)
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "tools.header"))
+	for _, g := range lister.ToolCatalog(ctx) {
+		status := "✅"
+		if !g.Available {
+			status = "❌"
+		}
+		title := g.Name
+		if g.MCP {
+			title = "MCP: " + g.Name
+		}
+		sb.WriteString(fmt.Sprintf("\n%s **%s**", status, title))
+		switch {
+		case g.Owner != "":
+			sb.WriteString(h.msg(ctx, "tools.via", g.Owner))
+		case g.MCP:
+			sb.WriteString(h.msg(ctx, "tools.unassigned"))
+		}
+		if g.Status != "" {
+			sb.WriteString(" — " + g.Status)
+		}
+		sb.WriteString("\n")
+		for _, t := range g.Tools {
+			sb.WriteString(fmt.Sprintf("• `%s` %s\n", t.Name, firstLine(t.Description)))
+		}
+	}
+	reply(sb.String())
+}
+
+// firstLine returns the first line of s, which keeps long MCP tool
+// descriptions readable in chat.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+func (h *Handler) handleReload(ctx context.Context, sessionID string, reply func(string)) {
+	if h.reload == nil {
+		reply(h.msg(ctx, "reload.unavailable"))
+		return
+	}
+	if !h.config().Bot.IsAdmin(sessionID) {
+		slog.Warn("Rejected /reload from non-admin", "sessionID", sessionID)
+		reply(h.msg(ctx, "reload.denied"))
+		return
+	}
+	summary, err := h.reload(ctx)
+	if err != nil {
+		slog.Error("Config reload failed", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "reload.failed"))
+		return
+	}
+	reply(h.msg(ctx, "reload.done", summary))
 }
 
 func (h *Handler) handleRemind(ctx context.Context, sessionID, text string, reply func(string)) {
 	args := strings.TrimSpace(text[len("/remind"):])
 	parts := strings.SplitN(args, " ", 2)
+	switch strings.ToLower(parts[0]) {
+	case "list":
+		h.handleRemindList(ctx, sessionID, reply)
+		return
+	case "cancel":
+		h.handleRemindCancel(ctx, sessionID, parts, reply)
+		return
+	case "every":
+		h.handleRemindEvery(ctx, sessionID, parts, reply)
+		return
+	}
+	usage := h.msg(ctx, "remind.usage")
 	if len(parts) < 2 {
-		reply("Usage: `/remind <duration> <message>`\nExamples: `/remind 30m Check Docker`, `/remind 2h Review PR`")
+		reply(usage)
+		return
+	}
+
+	// Go durations keep their original, compact confirmation.
+	if duration, err := time.ParseDuration(parts[0]); err == nil {
+		remindAt := time.Now().Add(duration)
+		if err := h.db.SaveReminder(ctx, newReminder(ctx, sessionID, parts[1], remindAt, "")); err != nil {
+			slog.Error("Failed to add reminder", "error", err)
+			reply(h.msg(ctx, "remind.save_failed"))
+			return
+		}
+		reply(h.msg(ctx, "remind.set_in", parts[0], parts[1]))
 		return
 	}
-	duration, err := time.ParseDuration(parts[0])
+
+	now := time.Now().In(h.config().Location())
+	remindAt, message, err := timeparse.Parse(args, now)
 	if err != nil {
-		reply(fmt.Sprintf("❌ Invalid duration `%s`. Use Go duration format: `30s`, `5m`, `2h`, `1h30m`", parts[0]))
+		reply(h.msg(ctx, "remind.invalid_time", err))
+		return
+	}
+	if message == "" {
+		reply(usage)
 		return
 	}
-	remindAt := time.Now().Add(duration)
-	if err := h.db.AddReminder(ctx, sessionID, parts[1], remindAt); err != nil {
+	if !remindAt.After(now) {
+		reply(h.msg(ctx, "remind.past", remindAt.Format("Mon Jan 2 15:04")))
+		return
+	}
+	if err := h.db.SaveReminder(ctx, newReminder(ctx, sessionID, message, remindAt, "")); err != nil {
 		slog.Error("Failed to add reminder", "error", err)
-		reply("❌ Failed to save reminder.")
+		reply(h.msg(ctx, "remind.save_failed"))
 		return
 	}
-	reply(fmt.Sprintf("⏰ Reminder set! I'll remind you in **%s**: %s", parts[0], parts[1]))
+	reply(h.msg(ctx, "remind.set_at", remindAt.Format("Mon Jan 2 15:04 MST"), message))
 }
 
-func (h *Handler) handleExport(ctx context.Context, text string, reply func(string)) {
-	limitStr := strings.TrimSpace(text[len("/export"):])
-	limit := 5
-	if limitStr != "" {
-		if n, err := strconv.Atoi(limitStr); err == nil && n > 0 {
-			limit = n
-			if limit > 20 {
-				limit = 20
+func (h *Handler) handleRemindEvery(ctx context.Context, sessionID string, parts []string, reply func(string)) {
+	usage := h.msg(ctx, "remind.every_usage")
+	if len(parts) < 2 {
+		reply(usage)
+		return
+	}
+	now := time.Now().In(h.config().Location())
+	rule, message, err := timeparse.ParseEvery(parts[1], now)
+	if err != nil {
+		reply(h.msg(ctx, "error.with_usage", err, usage))
+		return
+	}
+	if message == "" {
+		reply(usage)
+		return
+	}
+	remindAt := rule.Next(now)
+	if err := h.db.SaveReminder(ctx, newReminder(ctx, sessionID, message, remindAt, rule.String())); err != nil {
+		slog.Error("Failed to add recurring reminder", "error", err)
+		reply(h.msg(ctx, "remind.save_failed"))
+		return
+	}
+	reply(h.msg(ctx, "remind.recurring_set", rule.Describe(), remindAt.Format("Mon Jan 2 15:04"), message))
+}
+
+func (h *Handler) handleRemindList(ctx context.Context, sessionID string, reply func(string)) {
+	reminders, err := h.db.GetSessionReminders(ctx, sessionID)
+	if err != nil {
+		slog.Error("Failed to list reminders", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "remind.list_failed"))
+		return
+	}
+	if len(reminders) == 0 {
+		reply(h.msg(ctx, "remind.none"))
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "remind.list_header", len(reminders)))
+	for _, r := range reminders {
+		sb.WriteString(fmt.Sprintf("• `#%d` %s — %s", r.ID, r.RemindAt.In(h.config().Location()).Format("Jan 2 15:04"), r.Message))
+		if rule, err := timeparse.ParseRule(r.Recurrence); r.Recurrence != "" && err == nil {
+			sb.WriteString(fmt.Sprintf(" (🔁 %s)", rule.Describe()))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(h.msg(ctx, "remind.list_footer"))
+	reply(sb.String())
+}
+
+func (h *Handler) handleRemindCancel(ctx context.Context, sessionID string, parts []string, reply func(string)) {
+	if len(parts) < 2 {
+		reply(h.msg(ctx, "remind.cancel_usage"))
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(parts[1]), "#"), 10, 64)
+	if err != nil {
+		reply(h.msg(ctx, "remind.invalid_id", parts[1]))
+		return
+	}
+	found, err := h.db.CancelReminder(ctx, sessionID, id)
+	if err != nil {
+		slog.Error("Failed to cancel reminder", "sessionID", sessionID, "id", id, "error", err)
+		reply(h.msg(ctx, "remind.cancel_failed"))
+		return
+	}
+	if !found {
+		reply(h.msg(ctx, "remind.not_found", id))
+		return
+	}
+	reply(h.msg(ctx, "remind.cancelled", id))
+}
+
+func (h *Handler) handleSnooze(ctx context.Context, sessionID, text string, reply func(string)) {
+	parts := strings.SplitN(strings.TrimSpace(text[len("/snooze"):]), " ", 2)
+	if len(parts) < 2 {
+		reply(h.msg(ctx, "snooze.usage"))
+		return
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(parts[0], "#"), 10, 64)
+	if err != nil {
+		reply(h.msg(ctx, "snooze.invalid_id", parts[0]))
+		return
+	}
+	now := time.Now().In(h.config().Location())
+	until, _, err := timeparse.Parse(parts[1], now)
+	if err != nil || !until.After(now) {
+		reply(h.msg(ctx, "snooze.invalid_time", parts[1]))
+		return
+	}
+	found, err := h.db.SnoozeReminder(ctx, sessionID, id, until)
+	if err != nil {
+		slog.Error("Failed to snooze reminder", "sessionID", sessionID, "id", id, "error", err)
+		reply(h.msg(ctx, "snooze.failed"))
+		return
+	}
+	if !found {
+		reply(h.msg(ctx, "snooze.not_found", id))
+		return
+	}
+	reply(h.msg(ctx, "snooze.done", id, until.Format("Mon Jan 2 15:04")))
+}
+
+func (h *Handler) handleTodo(ctx context.Context, sessionID, text string, reply func(string)) {
+	parts := strings.SplitN(strings.TrimSpace(text[len("/todo"):]), " ", 2)
+	sub := strings.ToLower(parts[0])
+	arg := ""
+	if len(parts) > 1 {
+		arg = strings.TrimSpace(parts[1])
+	}
+
+	switch sub {
+	case "add":
+		if arg == "" {
+			reply(h.msg(ctx, "todo.add_usage"))
+			return
+		}
+		id, err := h.db.AddTask(ctx, sessionID, arg)
+		if err != nil {
+			slog.Error("Failed to add task", "sessionID", sessionID, "error", err)
+			reply(h.msg(ctx, "todo.save_failed"))
+			return
+		}
+		reply(h.msg(ctx, "todo.added", id, arg))
+
+	case "", "list":
+		tasks, err := h.db.ListTasks(ctx, sessionID, strings.EqualFold(arg, "all"))
+		if err != nil {
+			slog.Error("Failed to list tasks", "sessionID", sessionID, "error", err)
+			reply(h.msg(ctx, "todo.list_failed"))
+			return
+		}
+		if len(tasks) == 0 {
+			reply(h.msg(ctx, "todo.empty"))
+			return
+		}
+		var sb strings.Builder
+		sb.WriteString(h.msg(ctx, "todo.list_header", len(tasks)))
+		for _, t := range tasks {
+			box := "☐"
+			if t.Done {
+				box = "☑"
 			}
+			sb.WriteString(fmt.Sprintf("%s `#%d` %s\n", box, t.ID, t.Title))
+		}
+		reply(sb.String())
+
+	case "done":
+		id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+		if err != nil {
+			reply(h.msg(ctx, "todo.done_usage"))
+			return
 		}
+		found, err := h.db.CompleteTask(ctx, sessionID, id)
+		if err != nil {
+			slog.Error("Failed to complete task", "sessionID", sessionID, "id", id, "error", err)
+			reply(h.msg(ctx, "todo.update_failed"))
+			return
+		}
+		if !found {
+			reply(h.msg(ctx, "todo.not_found", id))
+			return
+		}
+		reply(h.msg(ctx, "todo.completed", id))
+
+	case "clear":
+		all := strings.EqualFold(arg, "all")
+		n, err := h.db.ClearTasks(ctx, sessionID, all)
+		if err != nil {
+			slog.Error("Failed to clear tasks", "sessionID", sessionID, "error", err)
+			reply(h.msg(ctx, "todo.clear_failed"))
+			return
+		}
+		if all {
+			reply(h.msg(ctx, "todo.cleared_all", n))
+			return
+		}
+		reply(h.msg(ctx, "todo.cleared_done", n))
+
+	default:
+		reply(h.msg(ctx, "todo.usage"))
 	}
-	briefings, err := h.db.GetRecentBriefings(ctx, limit)
+}
+
+func (h *Handler) handleRemember(ctx context.Context, sessionID, text string, reply func(string)) {
+	fact := strings.TrimSpace(text[len("/remember"):])
+	if fact == "" {
+		reply(h.msg(ctx, "remember.usage"))
+		return
+	}
+	id, err := h.db.AddMemory(ctx, sessionID, fact)
 	if err != nil {
-		slog.Error("Export failed", "error", err)
-		reply("❌ Failed to retrieve briefings.")
+		slog.Error("Failed to save memory", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "remember.failed"))
 		return
 	}
-	if len(briefings) == 0 {
-		reply("📭 No briefings found. Run `/research <topic>` to generate one!")
+	reply(h.msg(ctx, "remember.saved", id))
+}
+
+func (h *Handler) handleRecall(ctx context.Context, sessionID, text string, reply func(string)) {
+	query := strings.TrimSpace(text[len("/recall"):])
+	memories, err := h.db.SearchMemories(ctx, sessionID, query, 20)
+	if err != nil {
+		slog.Error("Failed to search memories", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "recall.failed"))
+		return
+	}
+	if len(memories) == 0 {
+		if query == "" {
+			reply(h.msg(ctx, "recall.none"))
+		} else {
+			reply(h.msg(ctx, "recall.no_match", query))
+		}
 		return
 	}
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("📋 **Exported %d Briefing(s)**\n\n", len(briefings)))
-	for i, b := range briefings {
-		sb.WriteString(fmt.Sprintf("---\n### Briefing %d (Created: %s)\n\n", i+1, b.CreatedAt))
-		sb.WriteString(b.Content)
-		sb.WriteString("\n\n")
+	sb.WriteString(h.msg(ctx, "recall.header", len(memories)))
+	for _, m := range memories {
+		sb.WriteString(fmt.Sprintf("• `#%d` %s\n", m.ID, m.Content))
 	}
+	sb.WriteString(h.msg(ctx, "recall.footer"))
 	reply(sb.String())
 }
 
-func (h *Handler) handleResearch(ctx context.Context, text string, reply func(string)) {
-	topic := strings.TrimSpace(text[len("/research"):])
-	if topic == "" {
-		reply("Please provide a topic. Usage: `/research <topic>`")
+func (h *Handler) handleForget(ctx context.Context, sessionID, text string, reply func(string)) {
+	arg := strings.TrimPrefix(strings.TrimSpace(text[len("/forget"):]), "#")
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		reply(h.msg(ctx, "forget.usage"))
 		return
 	}
-	reply(fmt.Sprintf("🔬 Starting research on: **%s**...", topic))
-	prompt := fmt.Sprintf("Research the following topic in depth and provide a technical report: %s", topic)
-	report, err := h.bot.RunMission(ctx, prompt)
+	found, err := h.db.DeleteMemory(ctx, sessionID, id)
 	if err != nil {
-		slog.Error("Research failed", "topic", topic, "error", err)
-		reply("❌ Research failed. I couldn't complete the research mission.")
+		slog.Error("Failed to delete memory", "sessionID", sessionID, "id", id, "error", err)
+		reply(h.msg(ctx, "forget.failed"))
 		return
 	}
-	h.stats.RecordMission()
-	if err := h.db.SaveBriefing(ctx, report); err != nil {
-		slog.Error("Failed to save briefing", "error", err)
+	if !found {
+		reply(h.msg(ctx, "forget.not_found", id))
+		return
 	}
-	reply(report)
+	reply(h.msg(ctx, "forget.done", id))
 }
 
-func (h *Handler) handleJules(ctx context.Context, sessionID, text string, reply func(string)) {
-	parts := strings.Fields(text[len("/jules"):])
-	if len(parts) < 2 {
-		reply("Usage: `/jules <owner/repo> <task description>`")
+func (h *Handler) handleSubscribe(ctx context.Context, sessionID, text string, reply func(string)) {
+	feedURL := strings.TrimSpace(text[len("/subscribe"):])
+	if feedURL == "" {
+		reply(h.msg(ctx, "subscribe.usage"))
 		return
 	}
-	repo := parts[0]
-	task := strings.Join(parts[1:], " ")
-	reply(fmt.Sprintf("🤖 Delegating to Jules for **%s**: %s", repo, task))
-	prompt := fmt.Sprintf("Ask the Jules agent to delegate this coding task to the external Jules service for repository %s: %s", repo, task)
-	response, err := h.bot.Chat(ctx, sessionID, prompt)
+	if err := tools.ValidateURL(ctx, feedURL); err != nil {
+		slog.Warn("Rejected feed URL", "sessionID", sessionID, "url", feedURL, "error", err)
+		reply(h.msg(ctx, "subscribe.invalid_url"))
+		return
+	}
+	added, err := h.db.AddSubscription(ctx, sessionID, feedURL)
 	if err != nil {
-		slog.Error("Jules delegation failed", "repo", repo, "task", task, "error", err)
-		reply("❌ Jules delegation failed. I couldn't hand off the task to Jules.")
+		slog.Error("Failed to add subscription", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "subscribe.failed"))
 		return
 	}
-	reply(response)
+	if !added {
+		reply(h.msg(ctx, "subscribe.exists", feedURL))
+		return
+	}
+	reply(h.msg(ctx, "subscribe.done", feedURL))
+}
+
+func (h *Handler) handleUnsubscribe(ctx context.Context, sessionID, text string, reply func(string)) {
+	arg := strings.TrimSpace(text[len("/unsubscribe"):])
+	if arg == "" {
+		reply(h.msg(ctx, "unsubscribe.usage"))
+		return
+	}
+	id, _ := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	removed, err := h.db.RemoveSubscription(ctx, sessionID, id, arg)
+	if err != nil {
+		slog.Error("Failed to remove subscription", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "unsubscribe.failed"))
+		return
+	}
+	if !removed {
+		reply(h.msg(ctx, "unsubscribe.not_found", arg))
+		return
+	}
+	reply(h.msg(ctx, "unsubscribe.done", arg))
+}
+
+func (h *Handler) handleExport(ctx context.Context, sessionID, text string, n notifier.Notifier, reply func(string)) {
+	now := time.Now().In(h.config().Location())
+	format, filter, err := parseExportArgs(strings.Fields(text[len("/export"):]), now)
+	if err != nil {
+		reply(h.msg(ctx, "error.with_usage", err, h.msg(ctx, "export.usage")))
+		return
+	}
+	filter.SessionID = sessionID
+
+	briefings, err := h.db.GetBriefings(ctx, filter)
+	if err != nil {
+		slog.Error("Export failed", "error", err)
+		reply(h.msg(ctx, "export.failed"))
+		return
+	}
+	if len(briefings) == 0 {
+		reply(h.msg(ctx, "export.none"))
+		return
+	}
+
+	if format == "" {
+		var sb strings.Builder
+		sb.WriteString(h.msg(ctx, "export.inline_header", len(briefings)))
+		for i, b := range briefings {
+			sb.WriteString(h.msg(ctx, "export.inline_item", i+1, b.CreatedAt))
+			sb.WriteString(b.Content)
+			sb.WriteString("\n\n")
+		}
+		reply(sb.String())
+		return
+	}
+
+	sender, ok := n.(notifier.FileSender)
+	if !ok {
+		reply(h.msg(ctx, "export.no_files"))
+		return
+	}
+	file, err := export.Render(format, briefings, now)
+	if err != nil {
+		slog.Error("Failed to render export", "format", format, "error", err)
+		reply(h.msg(ctx, "export.render_failed"))
+		return
+	}
+	caption := h.msg(ctx, "export.caption", len(briefings), strings.ToUpper(string(format)))
+	if err := sender.SendFile(ctx, file.Name, file.ContentType, file.Data, caption); err != nil {
+		slog.Error("Failed to send export file", "notifier", n.Name(), "error", err)
+		reply(h.msg(ctx, "export.send_failed"))
+	}
+}
+
+// parseExportArgs parses the /export arguments into an optional file format
+// (empty for inline text) and a briefing filter.
+func parseExportArgs(args []string, now time.Time) (export.Format, db.BriefingFilter, error) {
+	var format export.Format
+	filter := db.BriefingFilter{Limit: 5}
+	maxLimit := 20
+	limitSet := false
+
+	for _, arg := range args {
+		key, value, hasValue := strings.Cut(arg, ":")
+		switch {
+		case hasValue && (strings.EqualFold(key, "since") || strings.EqualFold(key, "from")):
+			t, err := parseExportDate(value, now)
+			if err != nil {
+				return "", filter, err
+			}
+			filter.Since = t
+		case hasValue && (strings.EqualFold(key, "until") || strings.EqualFold(key, "to")):
+			t, err := parseExportDate(value, now)
+			if err != nil {
+				return "", filter, err
+			}
+			filter.Until = t.AddDate(0, 0, 1) // inclusive of the whole day
+		case hasValue && strings.EqualFold(key, "tag"):
+			filter.Tag = value
+		default:
+			if n, err := strconv.Atoi(arg); err == nil && n > 0 {
+				filter.Limit = n
+				limitSet = true
+				continue
+			}
+			f, ok := export.ParseFormat(arg)
+			if !ok {
+				return "", filter, fmt.Errorf("unknown option %q", arg)
+			}
+			format = f
+		}
+	}
+
+	// Files can hold more than a chat message.
+	if format != "" {
+		maxLimit = 100
+		if !limitSet {
+			filter.Limit = maxLimit
+		}
+	}
+	filter.Limit = min(filter.Limit, maxLimit)
+	return format, filter, nil
+}
+
+// parseExportDate accepts an ISO date or a relative "<N>d" day count.
+func parseExportDate(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(strings.ToLower(value), "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			y, m, d := now.AddDate(0, 0, -n).Date()
+			return time.Date(y, m, d, 0, 0, 0, 0, now.Location()), nil
+		}
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return t, nil
+}
+
+func (h *Handler) handleResearch(ctx context.Context, sessionID, text string, reply func(string)) {
+	topic := strings.TrimSpace(text[len("/research"):])
+	if topic == "" {
+		reply(h.msg(ctx, "research.usage"))
+		return
+	}
+	if !h.allowMission(ctx, sessionID, reply) {
+		return
+	}
+	reply(h.msg(ctx, "research.starting", topic))
+	prompt := fmt.Sprintf("Research the following topic in depth and provide a technical report: %s", topic) + tagInstruction
+	report, err := h.bot.RunMission(ctx, prompt)
+	if err != nil {
+		slog.Error("Research failed", "topic", topic, "error", err)
+		reply(h.msg(ctx, "research.failed"))
+		return
+	}
+	h.stats.RecordMission()
+	report, tags := splitReportTags(report)
+	if id, err := h.db.AddSessionBriefing(ctx, sessionID, report, topic, tags); errors.Is(err, db.ErrDuplicateBriefing) {
+		slog.Info("Research matched a stored briefing, not saving it again", "topic", topic, "briefing", id)
+	} else if err != nil {
+		slog.Error("Failed to save briefing", "error", err)
+	}
+	reply(report)
 }
 
 func (h *Handler) handleChat(ctx context.Context, sessionID, text string, reply func(string)) {
 	response, err := h.bot.Chat(ctx, sessionID, text)
 	if err != nil {
 		slog.Error("Chat failed", "sessionID", sessionID, "error", err)
-		reply("Sorry, I encountered an error while processing your request.")
+		reply(h.msg(ctx, "chat.failed"))
 		return
 	}
+	h.recordTurn(ctx, sessionID, text, response)
 	reply(response)
 }
 
-// RunJob executes a scheduled job (e.g., daily research briefing).
+// RunJob executes a scheduled job (e.g., daily research briefing) and
+// records the run in the job history. Paused jobs are skipped.
 func (h *Handler) RunJob(ctx context.Context, job config.JobConfig) {
-	slog.Info("Running scheduled job", "name", job.Name, "type", job.Type)
+	if h.jobPaused(ctx, job.Name) {
+		slog.Info("Skipping paused job", "name", job.Name)
+		return
+	}
+	_, _ = h.runJob(ctx, job, db.TriggerScheduled)
+}
+
+// execJob runs a job and returns the path of the report it saved, if any.
+func (h *Handler) execJob(ctx context.Context, job config.JobConfig) (string, error) {
 	switch job.Type {
 	case "research":
-		prompt := job.Params["prompt"]
-		today := time.Now().Format("Monday, January 2, 2006")
-		fullPrompt := fmt.Sprintf("Today is %s. %s", today, prompt)
-
-		var report string
-		var err error
+		return h.runResearchJob(ctx, job)
+	case "rss_digest":
+		return h.runRSSDigestJob(ctx, job)
+	case "webhook":
+		return h.runWebhookJob(ctx, job)
+	case "chat":
+		return h.runChatJob(ctx, job)
+	case "feedback_summary":
+		return "", h.runFeedbackSummary(ctx, job)
+	case "backup":
+		return h.runBackupJob(ctx, job)
+	case "system_report":
+		return "", h.runSystemReportJob(ctx, job)
+	case "usage_rollup":
+		return "", h.runUsageRollupJob(ctx, job)
+	case "usage_report":
+		return "", h.runUsageReportJob(ctx, job)
+	default:
+		slog.Warn("Unknown job type", "type", job.Type, "name", job.Name)
+		return "", fmt.Errorf("unknown job type %q", job.Type)
+	}
+}
 
-		for attempt := range maxJobRetries + 1 {
-			if attempt > 0 {
-				slog.Warn("Retrying job after inadequate report", "name", job.Name, "attempt", attempt+1, "delay", jobRetryDelay)
-				time.Sleep(jobRetryDelay)
-			}
+func (h *Handler) runResearchJob(ctx context.Context, job config.JobConfig) (string, error) {
+	report, tags, err := h.researchReport(ctx, job)
+	if err != nil {
+		return "", err
+	}
+	return h.publishJobReport(ctx, job, report, tags)
+}
 
-			report, err = h.bot.RunMission(ctx, fullPrompt)
-			if err != nil {
-				slog.Error("Job mission failed", "name", job.Name, "attempt", attempt+1, "error", err)
-				continue
-			}
+// researchReport runs a job's research prompt, retrying inadequate
+// reports, and returns the report and its tags.
+func (h *Handler) researchReport(ctx context.Context, job config.JobConfig) (string, []string, error) {
+	prompt := job.Params["prompt"]
+	if job.Params["feeds"] == "subscriptions" {
+		prompt += h.subscribedFeedsPrompt(ctx, h.lastJobSuccess(ctx, job.Name))
+	}
+	today := time.Now().Format("Monday, January 2, 2006")
+	fullPrompt := fmt.Sprintf("Today is %s. %s", today, prompt) + tagInstruction
 
-			if isAdequateReport(report) {
-				break
-			}
+	var report string
+	var err error
 
-			slog.Warn("Job produced inadequate report", "name", job.Name, "attempt", attempt+1, "length", len(report))
-			// Treat as failure for retry purposes but keep report in case
-			// all retries produce the same result — saving a bad report is
-			// better than saving nothing.
+	for attempt := range maxJobRetries + 1 {
+		if attempt > 0 {
+			slog.Warn("Retrying job after inadequate report", "name", job.Name, "attempt", attempt+1, "delay", jobRetryDelay)
+			time.Sleep(jobRetryDelay)
 		}
 
+		report, err = h.bot.RunMission(ctx, fullPrompt)
 		if err != nil {
-			slog.Error("Job failed after retries", "name", job.Name, "error", err)
-			return
+			slog.Error("Job mission failed", "name", job.Name, "attempt", attempt+1, "error", err)
+			continue
 		}
 
-		if !isAdequateReport(report) {
-			slog.Warn("Job completed with inadequate report after retries, saving anyway", "name", job.Name, "length", len(report))
+		if isAdequateReport(report) {
+			break
 		}
 
-		path, err := agent.SaveReport("daily_logs", report)
-		if err != nil {
-			slog.Error("Failed to save report", "name", job.Name, "error", err)
-			return
-		}
+		slog.Warn("Job produced inadequate report", "name", job.Name, "attempt", attempt+1, "length", len(report))
+		// Treat as failure for retry purposes but keep report in case
+		// all retries produce the same result — saving a bad report is
+		// better than saving nothing.
+	}
+
+	if err != nil {
+		slog.Error("Job failed after retries", "name", job.Name, "error", err)
+		return "", nil, fmt.Errorf("mission failed after retries: %w", err)
+	}
 
-		slog.Info("Job completed", "name", job.Name, "path", path)
+	if !isAdequateReport(report) {
+		slog.Warn("Job completed with inadequate report after retries, saving anyway", "name", job.Name, "length", len(report))
+	}
+
+	report, tags := splitReportTags(report)
+	return report, jobTags(job.Name, job.Params["tags"], tags), nil
+}
+
+// publishJobReport stores a job's report as a briefing and a report file and
+// sends it to every notifier. A report identical to an earlier briefing was
+// already delivered, so it is neither saved nor sent again and the path
+// returned is empty.
+func (h *Handler) publishJobReport(ctx context.Context, job config.JobConfig, report string, tags []string) (string, error) {
+	raw := report
+	report = h.applyJobTemplate(job, report, tags)
+	id, err := h.db.AddTemplatedBriefing(ctx, report, raw, job.Name, tags)
+	if errors.Is(err, db.ErrDuplicateBriefing) {
+		// A retried or re-run job produced a report that was already
+		// delivered; don't notify twice.
+		slog.Info("Job produced a duplicate report, skipping notification", "name", job.Name, "briefing", id)
 		h.stats.RecordMission()
+		return "", nil
+	} else if err != nil {
+		slog.Error("Failed to save briefing", "name", job.Name, "error", err)
+	}
 
-		var wg sync.WaitGroup
-		for _, n := range h.notifiers {
-			wg.Add(1)
-			go func(n notifier.Notifier) {
-				defer wg.Done()
-				if err := n.Send(ctx, report); err != nil {
-					slog.Error("Failed to send report", "job", job.Name, "notifier", n.Name(), "error", err)
-				} else {
-					slog.Info("Report sent", "job", job.Name, "notifier", n.Name())
-				}
-			}(n)
-		}
-		wg.Wait()
-	default:
-		slog.Warn("Unknown job type", "type", job.Type, "name", job.Name)
+	path, err := agent.SaveReport(h.config().ReportsDir, report)
+	if err != nil {
+		slog.Error("Failed to save report", "name", job.Name, "error", err)
+		return "", err
 	}
+
+	slog.Info("Job completed", "name", job.Name, "path", path)
+	h.stats.RecordMission()
+	h.broadcast(ctx, job.Name, report)
+	return path, nil
+}
+
+// broadcast sends a job's output to every notifier concurrently.
+func (h *Handler) broadcast(ctx context.Context, jobName, report string) {
+	var wg sync.WaitGroup
+	for _, n := range h.currentNotifiers() {
+		wg.Add(1)
+		go func(n notifier.Notifier) {
+			defer wg.Done()
+			if err := n.Send(ctx, report); err != nil {
+				slog.Error("Failed to send report", "job", jobName, "notifier", n.Name(), "error", err)
+			} else {
+				slog.Info("Report sent", "job", jobName, "notifier", n.Name())
+			}
+		}(n)
+	}
+	wg.Wait()
 }
 
 // isAdequateReport checks whether a report looks like a real result
@@ -338,31 +1234,23 @@ func (h *Handler) DeliverReminders(ctx context.Context) {
 		return
 	}
 
+	// Recurrences are evaluated in the configured timezone so "9am" stays
+	// 9am local across DST changes.
+	now := time.Now().In(h.config().Location())
 	deliveredIDs := make([]int64, 0, len(pending))
 
 	for _, r := range pending {
-		msg := fmt.Sprintf("⏰ **Reminder**: %s", r.Message)
-		delivered := false
-
-		// Try session-specific reply function first
-		h.mu.Lock()
-		if replyFn, ok := h.replies[r.SessionID]; ok {
-			replyFn(msg)
-			delivered = true
-		}
-		h.mu.Unlock()
-
-		// Fallback to notifiers if no direct reply function
-		if !delivered {
-			for _, n := range h.notifiers {
-				if err := n.Send(ctx, msg); err != nil {
-					slog.Error("Failed to deliver reminder", "error", err)
-				}
-			}
+		msg := i18n.T(h.sessionLocale(ctx, r.SessionID), "remind.delivery", r.ID, r.Message, r.ID, r.ID, r.ID)
+		h.deliverReminder(ctx, r, msg)
+		slog.Info("Reminder delivered", "id", r.ID, "session", r.SessionID, "platform", r.Platform)
+
+		// Recurring reminders move to their next occurrence instead of
+		// being marked delivered.
+		if r.Recurrence != "" && h.rescheduleReminder(ctx, r, now) {
+			continue
 		}
 
 		deliveredIDs = append(deliveredIDs, r.ID)
-		slog.Info("Reminder delivered", "id", r.ID, "session", r.SessionID)
 	}
 
 	if len(deliveredIDs) > 0 {
@@ -371,3 +1259,92 @@ func (h *Handler) DeliverReminders(ctx context.Context) {
 		}
 	}
 }
+
+// deliverReminder sends msg to where the reminder was set.
+func (h *Handler) deliverReminder(ctx context.Context, r db.Reminder, msg string) {
+	h.deliver(ctx, r.SessionID, r.Platform, r.ChannelID, msg)
+}
+
+// deliver sends an unprompted message to where it was asked for: the
+// notifier for platform and channelID, so delivery survives restarts.
+// Messages without a reachable origin go to the session's reply function,
+// then to every notifier.
+func (h *Handler) deliver(ctx context.Context, sessionID, platform, channelID, msg string) {
+	if platform != "" {
+		for _, n := range h.currentNotifiers() {
+			if !strings.EqualFold(n.Name(), platform) {
+				continue
+			}
+			var err error
+			if cs, ok := n.(notifier.ChannelSender); ok && channelID != "" {
+				err = cs.SendTo(ctx, channelID, msg)
+			} else {
+				err = n.Send(ctx, msg)
+			}
+			if err == nil {
+				return
+			}
+			slog.Error("Failed to deliver message to its origin", "session", sessionID, "platform", platform, "error", err)
+		}
+		slog.Warn("Message origin unavailable, falling back", "session", sessionID, "platform", platform)
+	}
+
+	h.mu.Lock()
+	replyFn, ok := h.replies[sessionID]
+	h.mu.Unlock()
+	if ok {
+		replyFn(msg)
+		return
+	}
+	for _, n := range h.currentNotifiers() {
+		if err := n.Send(ctx, msg); err != nil {
+			slog.Error("Failed to deliver message", "error", err)
+		}
+	}
+}
+
+// progressMessage describes an MCP tool call's progress.
+func (h *Handler) progressMessage(ctx context.Context, p agent.MCPProgress) string {
+	var detail string
+	if p.Message != "" {
+		detail = ": " + p.Message
+	}
+	if p.Total > 0 {
+		detail += fmt.Sprintf(" (%d%%)", int(100*p.Progress/p.Total))
+	}
+	return h.msg(ctx, "progress.update", p.Tool, p.Server, detail)
+}
+
+// platformKey carries the name of the notifier a message arrived on.
+type platformKey struct{}
+
+// newReminder builds a reminder that remembers the platform and channel of
+// the message that set it.
+func newReminder(ctx context.Context, sessionID, message string, remindAt time.Time, recurrence string) db.Reminder {
+	platform, _ := ctx.Value(platformKey{}).(string)
+	return db.Reminder{
+		SessionID:  sessionID,
+		Message:    message,
+		RemindAt:   remindAt,
+		Recurrence: recurrence,
+		Platform:   platform,
+		ChannelID:  notifier.ChannelFromContext(ctx),
+	}
+}
+
+// rescheduleReminder moves a recurring reminder to its next occurrence after
+// now. It returns false if the reminder should be treated as one-shot.
+func (h *Handler) rescheduleReminder(ctx context.Context, r db.Reminder, now time.Time) bool {
+	rule, err := timeparse.ParseRule(r.Recurrence)
+	if err != nil {
+		slog.Error("Invalid reminder recurrence, delivering as one-shot", "id", r.ID, "recurrence", r.Recurrence, "error", err)
+		return false
+	}
+	next := rule.NextAfter(r.RemindAt.In(now.Location()), now)
+	if err := h.db.RescheduleReminder(ctx, r.ID, next); err != nil {
+		slog.Error("Failed to reschedule recurring reminder", "id", r.ID, "error", err)
+		return false
+	}
+	slog.Info("Recurring reminder rescheduled", "id", r.ID, "next", next)
+	return true
+}
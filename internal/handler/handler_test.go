@@ -2,13 +2,18 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/raythurman2386/ravenbot/internal/agent"
 	"github.com/raythurman2386/ravenbot/internal/config"
 	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
 	"github.com/raythurman2386/ravenbot/internal/stats"
+	"github.com/raythurman2386/ravenbot/internal/tools"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -95,6 +100,22 @@ func TestHandleMessage_Remind(t *testing.T) {
 		assert.Contains(t, got, "Usage")
 	})
 
+	t.Run("natural language time", func(t *testing.T) {
+		var got string
+		h.HandleMessage(ctx, "nl-session", "/remind tomorrow at 3pm Review PR", nil, func(reply string) {
+			got = reply
+		})
+		assert.Contains(t, got, "Reminder set for")
+
+		reminders, err := database.GetSessionReminders(ctx, "nl-session")
+		require.NoError(t, err)
+		require.Len(t, reminders, 1)
+		assert.Equal(t, "Review PR", reminders[0].Message)
+		local := reminders[0].RemindAt.Local()
+		assert.Equal(t, 15, local.Hour())
+		assert.Equal(t, time.Now().AddDate(0, 0, 1).Day(), local.Day())
+	})
+
 	t.Run("invalid duration", func(t *testing.T) {
 		var got string
 		h.HandleMessage(ctx, "test-session", "/remind xyz Check Docker", nil, func(reply string) {
@@ -104,6 +125,42 @@ func TestHandleMessage_Remind(t *testing.T) {
 	})
 }
 
+func TestHandleMessage_RemindListCancel(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/remind list", nil, reply)
+	assert.Contains(t, got, "No pending reminders")
+
+	require.NoError(t, database.AddReminder(ctx, "test-session", "Check Docker", time.Now().Add(time.Hour)))
+	reminders, err := database.GetSessionReminders(ctx, "test-session")
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	id := reminders[0].ID
+
+	h.HandleMessage(ctx, "test-session", "/remind list", nil, reply)
+	assert.Contains(t, got, "Check Docker")
+	assert.Contains(t, got, fmt.Sprintf("#%d", id))
+
+	h.HandleMessage(ctx, "test-session", "/remind cancel abc", nil, reply)
+	assert.Contains(t, got, "Invalid reminder ID")
+
+	h.HandleMessage(ctx, "test-session", "/remind cancel 9999", nil, reply)
+	assert.Contains(t, got, "No pending reminder")
+
+	h.HandleMessage(ctx, "test-session", fmt.Sprintf("/remind cancel %d", id), nil, reply)
+	assert.Contains(t, got, "cancelled")
+
+	reminders, err = database.GetSessionReminders(ctx, "test-session")
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+}
+
 func TestHandleMessage_Export_Empty(t *testing.T) {
 	t.Parallel()
 	h, database := newTestHandler(t)
@@ -134,6 +191,55 @@ func TestHandleMessage_Export_WithData(t *testing.T) {
 	assert.Contains(t, got, "Briefing content here")
 }
 
+type fileNotifier struct {
+	name, contentType string
+	data              []byte
+	sent              []string
+}
+
+func (f *fileNotifier) Send(_ context.Context, msg string) error {
+	f.sent = append(f.sent, msg)
+	return nil
+}
+func (f *fileNotifier) Name() string                       { return "file" }
+func (f *fileNotifier) StartTyping(context.Context) func() { return func() {} }
+func (f *fileNotifier) SendFile(_ context.Context, name, contentType string, data []byte, _ string) error {
+	f.name, f.contentType, f.data = name, contentType, data
+	return nil
+}
+
+func TestHandleMessage_ExportFile(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	_ = database.SaveBriefing(ctx, "# Security\n\nPatch **now**")
+	_ = database.SaveBriefing(ctx, "Unrelated update")
+
+	var got string
+	reply := func(r string) { got = r }
+
+	fn := &fileNotifier{}
+	h.HandleMessage(ctx, "test-session", "/export html tag:security", fn, reply)
+	assert.Empty(t, got)
+	assert.True(t, strings.HasSuffix(fn.name, ".html"))
+	assert.Contains(t, string(fn.data), "<strong>now</strong>")
+	assert.NotContains(t, string(fn.data), "Unrelated update")
+
+	h.HandleMessage(ctx, "test-session", "/export pdf since:1d", fn, reply)
+	assert.Equal(t, "application/pdf", fn.contentType)
+
+	h.HandleMessage(ctx, "test-session", "/export pdf", nil, reply)
+	assert.Contains(t, got, "aren't supported")
+
+	h.HandleMessage(ctx, "test-session", "/export docx", fn, reply)
+	assert.Contains(t, got, "unknown option")
+
+	h.HandleMessage(ctx, "test-session", "/export until:2000-01-01", fn, reply)
+	assert.Contains(t, got, "No briefings found")
+}
+
 func TestHandleMessage_EmptyText(t *testing.T) {
 	t.Parallel()
 	h, database := newTestHandler(t)
@@ -278,3 +384,431 @@ func TestDeliverReminders(t *testing.T) {
 	pending, _ := database.GetPendingReminders(ctx, time.Now())
 	assert.Len(t, pending, 0)
 }
+
+// channelNotifier records messages sent to specific channels.
+type channelNotifier struct {
+	fileNotifier
+	platform string
+	sentTo   map[string][]string
+}
+
+func (c *channelNotifier) Name() string { return c.platform }
+func (c *channelNotifier) SendTo(_ context.Context, channelID, msg string) error {
+	if c.sentTo == nil {
+		c.sentTo = make(map[string][]string)
+	}
+	c.sentTo[channelID] = append(c.sentTo[channelID], msg)
+	return nil
+}
+
+func TestDeliverReminders_RoutesToOrigin(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	telegram := &channelNotifier{platform: "Telegram"}
+	discord := &channelNotifier{platform: "Discord"}
+	h.SetNotifiers([]notifier.Notifier{discord, telegram})
+
+	msgCtx := notifier.WithChannel(ctx, "12345")
+	var got string
+	h.HandleMessage(msgCtx, "telegram-12345", "/remind 1ms Rotate the keys", telegram, func(r string) { got = r })
+	assert.Contains(t, got, "Rotate the keys")
+
+	reminders, err := database.GetSessionReminders(ctx, "telegram-12345")
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, "Telegram", reminders[0].Platform)
+	assert.Equal(t, "12345", reminders[0].ChannelID)
+
+	// After a restart the reply map is empty; delivery uses the stored origin.
+	h.mu.Lock()
+	h.replies = make(map[string]func(string))
+	h.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	h.DeliverReminders(ctx)
+
+	require.Len(t, telegram.sentTo["12345"], 1)
+	assert.Contains(t, telegram.sentTo["12345"][0], "Rotate the keys")
+	assert.Empty(t, telegram.sent)
+	assert.Empty(t, discord.sent, "other platforms don't get the reminder")
+	assert.Empty(t, discord.sentTo)
+
+	// Reminders from before origins were stored still broadcast.
+	_ = database.AddReminder(ctx, "legacy-session", "Old reminder", time.Now().Add(-time.Minute))
+	h.DeliverReminders(ctx)
+	assert.Len(t, telegram.sent, 1)
+	assert.Len(t, discord.sent, 1)
+}
+
+func TestHandleMessage_RemindEvery(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	h.HandleMessage(ctx, "test-session", "/remind every day 9am Standup notes", nil, func(reply string) {
+		got = reply
+	})
+	assert.Contains(t, got, "Recurring reminder set")
+	assert.Contains(t, got, "every day at 09:00")
+
+	reminders, err := database.GetSessionReminders(ctx, "test-session")
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, "Standup notes", reminders[0].Message)
+	assert.Equal(t, "FREQ=DAILY;INTERVAL=1;BYHOUR=9;BYMINUTE=0", reminders[0].Recurrence)
+
+	h.HandleMessage(ctx, "test-session", "/remind every fortnight nope", nil, func(reply string) {
+		got = reply
+	})
+	assert.Contains(t, got, "unrecognized recurrence")
+}
+
+func TestDeliverReminders_Recurring(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	_ = database.AddRecurringReminder(ctx, "test-session", "Drink water", time.Now().Add(-90*time.Minute), "FREQ=HOURLY;INTERVAL=1")
+
+	var delivered []string
+	h.mu.Lock()
+	h.replies["test-session"] = func(msg string) { delivered = append(delivered, msg) }
+	h.mu.Unlock()
+
+	h.DeliverReminders(ctx)
+	require.Len(t, delivered, 1)
+	assert.Contains(t, delivered[0], "Drink water")
+
+	// Rescheduled into the future rather than marked delivered
+	reminders, err := database.GetSessionReminders(ctx, "test-session")
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.True(t, reminders[0].RemindAt.After(time.Now()))
+}
+
+func TestHandleMessage_Snooze(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	_ = database.AddReminder(ctx, "test-session", "Stretch", time.Now().Add(-time.Minute))
+
+	var delivered string
+	h.mu.Lock()
+	h.replies["test-session"] = func(msg string) { delivered = msg }
+	h.mu.Unlock()
+	h.DeliverReminders(ctx)
+
+	pending, err := database.GetPendingReminders(ctx, time.Now().Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	// The delivered message carries the ID and snooze hints
+	assert.Contains(t, delivered, "/snooze")
+	var id int64
+	_, err = fmt.Sscanf(delivered[strings.Index(delivered, "#"):], "#%d", &id)
+	require.NoError(t, err)
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/snooze", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	h.HandleMessage(ctx, "test-session", fmt.Sprintf("/snooze %d whenever", id), nil, reply)
+	assert.Contains(t, got, "Invalid snooze time")
+
+	h.HandleMessage(ctx, "test-session", "/snooze 9999 10m", nil, reply)
+	assert.Contains(t, got, "No reminder")
+
+	h.HandleMessage(ctx, "test-session", fmt.Sprintf("/snooze %d 10m", id), nil, reply)
+	assert.Contains(t, got, "Snoozed")
+
+	reminders, err := database.GetSessionReminders(ctx, "test-session")
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.True(t, reminders[0].RemindAt.After(time.Now().Add(9*time.Minute)))
+}
+
+func TestHandleMessage_Todo(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/todo", nil, reply)
+	assert.Contains(t, got, "todo list is empty")
+
+	h.HandleMessage(ctx, "test-session", "/todo add Renew TLS cert", nil, reply)
+	assert.Contains(t, got, "Added")
+
+	tasks, err := database.ListTasks(ctx, "test-session", false)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	h.HandleMessage(ctx, "test-session", "/todo list", nil, reply)
+	assert.Contains(t, got, "Renew TLS cert")
+
+	h.HandleMessage(ctx, "test-session", fmt.Sprintf("/todo done %d", tasks[0].ID), nil, reply)
+	assert.Contains(t, got, "Completed")
+
+	h.HandleMessage(ctx, "test-session", "/todo done 9999", nil, reply)
+	assert.Contains(t, got, "No open task")
+
+	h.HandleMessage(ctx, "test-session", "/todo clear", nil, reply)
+	assert.Contains(t, got, "Cleared 1 completed")
+
+	h.HandleMessage(ctx, "test-session", "/todo frobnicate", nil, reply)
+	assert.Contains(t, got, "Usage")
+}
+
+func TestHandleMessage_Memory(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/recall", nil, reply)
+	assert.Contains(t, got, "don't have any saved memories")
+
+	h.HandleMessage(ctx, "test-session", "/remember", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	h.HandleMessage(ctx, "test-session", "/remember My cat is called Pixel", nil, reply)
+	assert.Contains(t, got, "remember that")
+
+	memories, err := database.SearchMemories(ctx, "test-session", "", 10)
+	require.NoError(t, err)
+	require.Len(t, memories, 1)
+
+	h.HandleMessage(ctx, "test-session", "/recall cat", nil, reply)
+	assert.Contains(t, got, "My cat is called Pixel")
+
+	h.HandleMessage(ctx, "test-session", "/recall dog", nil, reply)
+	assert.Contains(t, got, "No memories matching")
+
+	h.HandleMessage(ctx, "test-session", fmt.Sprintf("/forget #%d", memories[0].ID), nil, reply)
+	assert.Contains(t, got, "Forgotten")
+
+	h.HandleMessage(ctx, "test-session", fmt.Sprintf("/forget %d", memories[0].ID), nil, reply)
+	assert.Contains(t, got, "No memory with ID")
+}
+
+func TestHandleMessage_Reload(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/reload", nil, reply)
+	assert.Contains(t, got, "not available")
+
+	h.SetReloader(func(context.Context) (string, error) {
+		h.UpdateConfig(&config.Config{Bot: config.BotConfig{HelpMessage: "New help"}})
+		return "2 job(s) scheduled.", nil
+	})
+	h.HandleMessage(ctx, "test-session", "/reload", nil, reply)
+	assert.Contains(t, got, "2 job(s) scheduled.")
+
+	h.HandleMessage(ctx, "test-session", "/help", nil, reply)
+	assert.Equal(t, "New help", got)
+
+	h.SetReloader(func(context.Context) (string, error) {
+		return "", fmt.Errorf("failed to decode config file: unexpected EOF")
+	})
+	h.HandleMessage(ctx, "test-session", "/reload", nil, reply)
+	assert.Contains(t, got, "Reload failed")
+	assert.NotContains(t, got, "EOF")
+
+	// With admins configured, only they may reload.
+	cfg := *h.config()
+	cfg.Bot.Admins = []string{"admin-session"}
+	h.UpdateConfig(&cfg)
+	reloads := 0
+	h.SetReloader(func(context.Context) (string, error) {
+		reloads++
+		return "1 job(s) scheduled.", nil
+	})
+	h.HandleMessage(ctx, "test-session", "/reload", nil, reply)
+	assert.Contains(t, got, "Only admins")
+	assert.Zero(t, reloads)
+	h.HandleMessage(ctx, "admin-session", "/reload", nil, reply)
+	assert.Contains(t, got, "1 job(s) scheduled.")
+	assert.Equal(t, 1, reloads)
+}
+
+type toolListingBot struct {
+	mockBot
+	groups []agent.ToolGroup
+}
+
+func (b *toolListingBot) ToolCatalog(context.Context) []agent.ToolGroup { return b.groups }
+
+func TestHandleMessage_Tools(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.bot = &mockBot{}
+	h.HandleMessage(ctx, "test-session", "/tools", nil, reply)
+	assert.Contains(t, got, "not available")
+
+	h.bot = &toolListingBot{groups: []agent.ToolGroup{
+		{Name: "Assistant", Owner: "ravenbot", Available: true, Tools: []agent.ToolInfo{{Name: "todo_add", Description: "Adds a task.\nMore detail."}}},
+		{Name: "github", MCP: true, Owner: "Jules", Status: "failed to start"},
+		{Name: "extra", MCP: true, Available: true},
+	}}
+	h.HandleMessage(ctx, "test-session", "/tools", nil, reply)
+	assert.Contains(t, got, "✅ **Assistant** _(via ravenbot)_")
+	assert.Contains(t, got, "• `todo_add` Adds a task.\n")
+	assert.NotContains(t, got, "More detail")
+	assert.Contains(t, got, "❌ **MCP: github** _(via Jules)_ — failed to start")
+	assert.Contains(t, got, "not assigned to an agent")
+}
+
+func TestHandleMessage_Usage(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.stats.RecordSessionTokens("test-session", "gemini-2.5-flash", 1_000_000, 0)
+	h.stats.RecordSessionTokens("other-session", "gemini-2.5-flash", 5, 5)
+	h.UpdateConfig(&config.Config{Bot: config.BotConfig{ModelPricing: map[string]config.ModelPrice{
+		"gemini-2.5-flash": {InputPerMillion: 0.30},
+	}}})
+
+	h.HandleMessage(ctx, "test-session", "/usage", nil, reply)
+	assert.Contains(t, got, "**Messages**: 1")
+	assert.Contains(t, got, "1,000,000 in / 0 out")
+	assert.Contains(t, got, "~$0.3000")
+}
+
+func TestHandleMessage_Subscriptions(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/feeds", nil, reply)
+	assert.Contains(t, got, "No feed subscriptions")
+
+	h.HandleMessage(ctx, "test-session", "/subscribe http://127.0.0.1/feed", nil, reply)
+	assert.Contains(t, got, "public http(s) URL")
+
+	// IP literals avoid DNS lookups in tests
+	h.HandleMessage(ctx, "test-session", "/subscribe https://8.8.8.8/feed.xml", nil, reply)
+	assert.Contains(t, got, "Subscribed")
+	h.HandleMessage(ctx, "test-session", "/subscribe https://8.8.8.8/feed.xml", nil, reply)
+	assert.Contains(t, got, "already subscribed")
+
+	h.HandleMessage(ctx, "test-session", "/feeds", nil, reply)
+	assert.Contains(t, got, "https://8.8.8.8/feed.xml")
+	assert.Contains(t, got, "not fetched yet")
+
+	since := time.Now().Add(-time.Hour)
+	h.fetchFeed = func(context.Context, string, string, string) (tools.FeedResult, error) {
+		return tools.FeedResult{}, errors.New("feed returned status 502")
+	}
+	assert.Empty(t, h.subscribedFeedsPrompt(ctx, since))
+	h.HandleMessage(ctx, "test-session", "/feeds", nil, reply)
+	assert.Contains(t, got, "failing (1 in a row): feed returned status 502")
+
+	h.HandleMessage(ctx, "test-session", "/feeds interval #9999 2h", nil, reply)
+	assert.Contains(t, got, "No subscription")
+	h.HandleMessage(ctx, "test-session", "/feeds interval https://8.8.8.8/feed.xml 1m", nil, reply)
+	assert.Contains(t, got, "Usage")
+	h.HandleMessage(ctx, "test-session", "/feeds interval https://8.8.8.8/feed.xml 2d", nil, reply)
+	assert.Contains(t, got, "every 2d")
+	h.HandleMessage(ctx, "test-session", "/feeds", nil, reply)
+	assert.Contains(t, got, "every 2d")
+
+	// The failure pushed the next fetch back, so reset the feed's interval
+	// and move its last fetch into the past to retry it.
+	_, err := database.SetFeedInterval(ctx, "https://8.8.8.8/feed.xml", 0)
+	require.NoError(t, err)
+	_, err = database.ExecContext(ctx, `UPDATE feeds SET last_fetched = ?`, time.Now().Add(-2*time.Hour).UTC().Format(time.DateTime))
+	require.NoError(t, err)
+	h.fetchFeed = func(context.Context, string, string, string) (tools.FeedResult, error) {
+		return tools.FeedResult{Title: "Example", Items: []tools.RSSItem{{Title: "News", Link: "https://8.8.8.8/news", Published: time.Now()}}}, nil
+	}
+	assert.Contains(t, h.subscribedFeedsPrompt(ctx, since), "1. [Example] News — https://8.8.8.8/news")
+	h.HandleMessage(ctx, "test-session", "/feeds", nil, reply)
+	assert.Contains(t, got, "— Example · ✅ fetched")
+
+	h.HandleMessage(ctx, "test-session", "/unsubscribe 9999", nil, reply)
+	assert.Contains(t, got, "No subscription")
+	h.HandleMessage(ctx, "test-session", "/unsubscribe https://8.8.8.8/feed.xml", nil, reply)
+	assert.Contains(t, got, "Unsubscribed")
+	assert.Empty(t, h.subscribedFeedsPrompt(ctx, since))
+}
+
+func TestRunJob_DuplicateReportNotBroadcast(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	report := "# Daily Briefing\n\n" + strings.Repeat("Go 1.26 ships faster maps and a new GC. ", 40)
+	h.bot = &mockBot{runMissionFunc: func(context.Context, string) (string, error) {
+		return report, nil
+	}}
+	fn := &fileNotifier{}
+	h.SetNotifiers([]notifier.Notifier{fn})
+
+	// The same report, reformatted, was already delivered by an earlier run.
+	_, err := database.AddBriefing(ctx, strings.ToUpper(strings.ReplaceAll(report, " ", "  ")), "Daily Briefing", nil)
+	require.NoError(t, err)
+
+	job := config.JobConfig{Name: "Daily Briefing", Type: "research", Params: map[string]string{"prompt": "news"}}
+	h.RunJob(ctx, job)
+	assert.Empty(t, fn.sent)
+
+	runs, err := database.ListJobRuns(ctx, job.Name, 1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, db.JobSucceeded, runs[0].Status)
+	assert.Empty(t, runs[0].ReportPath)
+
+	briefings, err := database.GetRecentBriefings(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, briefings, 1)
+}
+
+func TestProgressMessage(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	msg := h.progressMessage(ctx, agent.MCPProgress{Server: "git", Tool: "clone", Message: "Receiving objects", Progress: 3, Total: 4})
+	assert.Equal(t, "⏳ `clone` (git) is still running: Receiving objects (75%)", msg)
+
+	msg = h.progressMessage(ctx, agent.MCPProgress{Server: "db", Tool: "query", Progress: 1200})
+	assert.Equal(t, "⏳ `query` (db) is still running", msg, "progress without a total is not shown")
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/db"
+)
+
+func (h *Handler) handleHistory(ctx context.Context, sessionID, text string, reply func(string)) {
+	query := strings.TrimSpace(text[len("/history"):])
+	if rest, ok := cutFlag(query, "--chat"); ok {
+		h.handleChatHistory(ctx, sessionID, rest, reply)
+		return
+	}
+	if query == "" {
+		reply(h.msg(ctx, "history.usage"))
+		return
+	}
+	briefings, err := h.db.SearchBriefings(ctx, sessionID, query, 5)
+	if err != nil {
+		slog.Error("Failed to search briefings", "query", query, "error", err)
+		reply(h.msg(ctx, "history.failed"))
+		return
+	}
+	headlines, err := h.db.SearchHeadlines(ctx, query, 10)
+	if err != nil {
+		slog.Error("Failed to search headlines", "query", query, "error", err)
+		reply(h.msg(ctx, "history.failed"))
+		return
+	}
+	if len(briefings) == 0 && len(headlines) == 0 {
+		reply(h.msg(ctx, "history.no_match", query))
+		return
+	}
+	loc := h.config().Location()
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "history.header", query))
+	if len(briefings) > 0 {
+		sb.WriteString(h.msg(ctx, "history.briefings", len(briefings)))
+		for _, b := range briefings {
+			sb.WriteString(fmt.Sprintf("• `#%d` %s — %s\n", b.ID, b.CreatedAt.In(loc).Format("Jan 2"), strings.Join(strings.Fields(b.Snippet), " ")))
+		}
+	}
+	if len(headlines) > 0 {
+		sb.WriteString(h.msg(ctx, "history.headlines", len(headlines)))
+		for _, hl := range headlines {
+			sb.WriteString(fmt.Sprintf("• %s — %s\n", hl.Snippet, hl.URL))
+		}
+	}
+	reply(sb.String())
+}
+
+// handleChatHistory searches the chat's own conversations (every thread)
+// for query.
+func (h *Handler) handleChatHistory(ctx context.Context, sessionID, query string, reply func(string)) {
+	if query == "" {
+		reply(h.msg(ctx, "history.usage"))
+		return
+	}
+	matches, err := h.db.SearchTranscripts(ctx, sessionID, query, 8)
+	if err != nil {
+		slog.Error("Failed to search transcripts", "sessionID", sessionID, "query", query, "error", err)
+		reply(h.msg(ctx, "history.failed"))
+		return
+	}
+	if len(matches) == 0 {
+		reply(h.msg(ctx, "history.chat_no_match", query))
+		return
+	}
+	loc := h.config().Location()
+	you := h.msg(ctx, "transcript.you")
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "history.header", query))
+	sb.WriteString(h.msg(ctx, "history.chat", len(matches)))
+	for _, m := range matches {
+		thread := defaultSessionName
+		if m.SessionID != sessionID {
+			thread = strings.TrimPrefix(m.SessionID, sessionID+":")
+		}
+		speaker := you
+		if m.Role == db.RoleAssistant {
+			speaker = "ravenbot"
+		}
+		sb.WriteString(fmt.Sprintf("• `%s` %s · %s: %s\n", thread, m.CreatedAt.In(loc).Format("Jan 2 15:04"), speaker, strings.Join(strings.Fields(m.Snippet), " ")))
+	}
+	reply(sb.String())
+}
+
+// cutFlag reports whether args starts with flag (ignoring case) and returns
+// the rest.
+func cutFlag(args, flag string) (string, bool) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], flag) {
+		return args, false
+	}
+	return strings.TrimSpace(strings.TrimSpace(args)[len(fields[0]):]), true
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessage_History(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/history", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	h.HandleMessage(ctx, "test-session", "/history kubernetes", nil, reply)
+	assert.Contains(t, got, "Nothing in past briefings")
+
+	require.NoError(t, database.SaveBriefing(ctx, "## Kubernetes 1.34\n\nThe release adds\nDRA improvements."))
+	_, err := database.AddHeadline(ctx, "https://example.com/k8s", "Kubernetes 1.34 released")
+	require.NoError(t, err)
+
+	h.HandleMessage(ctx, "test-session", "/history kubernetes", nil, reply)
+	assert.Contains(t, got, "**Briefings (1)**")
+	assert.Contains(t, got, "**Kubernetes** 1.34 The release adds")
+	assert.Contains(t, got, "**Headlines (1)**")
+	assert.Contains(t, got, "• **Kubernetes** 1.34 released — https://example.com/k8s")
+}
+
+func TestHandleMessage_HistoryChat(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/history --chat", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	h.HandleMessage(ctx, "test-session", "/history --chat sql trick", nil, reply)
+	assert.Contains(t, got, "Nothing in our past conversations")
+
+	require.NoError(t, database.AddTranscriptMessage(ctx, "test-session:work", db.RoleAssistant, "Here is the SQL trick:\nuse a CTE."))
+	h.HandleMessage(ctx, "test-session", "/history --CHAT sql trick", nil, reply)
+	assert.Contains(t, got, "**Conversations (1)**")
+	assert.Contains(t, got, "`work`")
+	assert.Contains(t, got, "ravenbot: Here is the **SQL** **trick")
+	assert.NotContains(t, got, "Briefings")
+}
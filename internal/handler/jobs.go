@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/schedule"
+	"github.com/raythurman2386/ravenbot/internal/stats"
+)
+
+// maxJobStatusRuns is how many runs /jobstatus shows.
+const maxJobStatusRuns = 10
+
+// runJob executes a job and records it in the job history with the tokens
+// its model calls used, then runs the jobs waiting on it if it succeeded.
+// It returns the report path, if any.
+func (h *Handler) runJob(ctx context.Context, job config.JobConfig, trigger string) (string, error) {
+	release, err := h.acquireJobSlot(ctx, job.Name)
+	if err != nil {
+		return "", err
+	}
+	// Freed before the jobs waiting on this one start, or on a panic.
+	release = sync.OnceFunc(release)
+	defer release()
+
+	slog.Info("Running job", "name", job.Name, "type", job.Type, "trigger", trigger)
+	runID, err := h.db.StartJobRun(ctx, job.Name, job.Type, trigger)
+	if err != nil {
+		slog.Warn("Failed to record job start", "name", job.Name, "error", err)
+	}
+
+	parent := ctx
+	ctx, tokens := stats.WithTokenCounter(ctx)
+	var path string
+	job, runErr := h.withPipelineInputs(ctx, job)
+	if runErr == nil {
+		path, runErr = h.execJob(ctx, job)
+	}
+
+	status, errMsg := db.JobSucceeded, ""
+	if runErr != nil {
+		status, errMsg = db.JobFailed, runErr.Error()
+	}
+	if runID != 0 {
+		if err := h.db.FinishJobRun(context.WithoutCancel(ctx), runID, status, path, errMsg, tokens.Total()); err != nil {
+			slog.Warn("Failed to record job result", "name", job.Name, "error", err)
+		}
+	}
+	release()
+	if runErr == nil {
+		h.runDependents(parent, job)
+	}
+	return path, runErr
+}
+
+// acquireJobSlot waits until fewer than maxConcurrentJobs jobs are running,
+// so jobs scheduled at the same time don't all call the model at once. It
+// returns the function that frees the slot.
+func (h *Handler) acquireJobSlot(ctx context.Context, name string) (func(), error) {
+	h.cfgMu.RLock()
+	slots := h.jobSlots
+	h.cfgMu.RUnlock()
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+	default:
+		slog.Info("Waiting for a free job slot", "name", name, "limit", cap(slots))
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return func() { <-slots }, nil
+}
+
+// findJob returns the configured job with the given name, ignoring case.
+func (h *Handler) findJob(name string) (config.JobConfig, bool) {
+	for _, job := range h.config().Jobs {
+		if strings.EqualFold(job.Name, name) {
+			return job, true
+		}
+	}
+	return config.JobConfig{}, false
+}
+
+func (h *Handler) handleJobs(ctx context.Context, sessionID, text string, reply func(string)) {
+	args := strings.TrimSpace(text[len("/jobs"):])
+	if args != "" {
+		sub, name, _ := strings.Cut(args, " ")
+		name = strings.TrimSpace(name)
+		if !strings.EqualFold(sub, "run") || name == "" {
+			reply(h.msg(ctx, "jobs.usage"))
+			return
+		}
+		h.handleJobRun(ctx, sessionID, name, reply)
+		return
+	}
+
+	jobs := h.config().Jobs
+	if len(jobs) == 0 {
+		reply(h.msg(ctx, "jobs.none"))
+		return
+	}
+	latest, err := h.db.LatestJobRuns(ctx)
+	if err != nil {
+		slog.Error("Failed to load job runs", "error", err)
+		reply(h.msg(ctx, "jobs.failed"))
+		return
+	}
+	paused, err := h.db.PausedJobs(ctx)
+	if err != nil {
+		slog.Warn("Failed to load paused jobs", "error", err)
+	}
+	loc := h.config().Location()
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "jobs.header", len(jobs)))
+	for _, job := range jobs {
+		last := h.msg(ctx, "jobs.never")
+		if r, ok := latest[job.Name]; ok {
+			last = h.msg(ctx, "jobs.last_run", r.StartedAt.In(loc).Format("Jan 2 15:04"), h.jobStatus(ctx, r.Status))
+		}
+		if _, ok := paused[job.Name]; ok {
+			last += h.msg(ctx, "jobs.paused")
+		}
+		schedule := job.Schedule
+		if job.Timezone != "" {
+			schedule += " " + job.Timezone
+		}
+		if len(job.RunAfter) > 0 {
+			schedule = strings.TrimLeft(schedule+", after "+strings.Join(job.RunAfter, ", "), ", ")
+		}
+		sb.WriteString(fmt.Sprintf("• **%s** (`%s`, `%s`) — %s\n", job.Name, job.Type, schedule, last))
+	}
+	sb.WriteString(h.msg(ctx, "jobs.footer"))
+	reply(sb.String())
+}
+
+// handleJob pauses or resumes a job's scheduled runs. The state is kept in
+// the database, so a paused job stays paused across restarts and reloads.
+func (h *Handler) handleJob(ctx context.Context, sessionID, text string, reply func(string)) {
+	sub, name, _ := strings.Cut(strings.TrimSpace(text[len("/job"):]), " ")
+	sub, name = strings.ToLower(sub), strings.TrimSpace(name)
+	if (sub != "pause" && sub != "resume") || name == "" {
+		reply(h.msg(ctx, "job.usage"))
+		return
+	}
+	if !h.config().Bot.IsAdmin(sessionID) {
+		slog.Warn("Rejected /job from non-admin", "sessionID", sessionID)
+		reply(h.msg(ctx, "job.denied"))
+		return
+	}
+	job, ok := h.findJob(name)
+	if !ok {
+		reply(h.msg(ctx, "jobs.not_found", name))
+		return
+	}
+
+	if sub == "pause" {
+		changed, err := h.db.PauseJob(ctx, job.Name)
+		switch {
+		case err != nil:
+			slog.Error("Failed to pause job", "name", job.Name, "error", err)
+			reply(h.msg(ctx, "job.failed"))
+		case changed:
+			slog.Info("Job paused", "name", job.Name, "sessionID", sessionID)
+			reply(h.msg(ctx, "job.paused", job.Name))
+		default:
+			reply(h.msg(ctx, "job.already_paused", job.Name))
+		}
+		return
+	}
+	changed, err := h.db.ResumeJob(ctx, job.Name)
+	switch {
+	case err != nil:
+		slog.Error("Failed to resume job", "name", job.Name, "error", err)
+		reply(h.msg(ctx, "job.failed"))
+	case changed:
+		slog.Info("Job resumed", "name", job.Name, "sessionID", sessionID)
+		reply(h.msg(ctx, "job.resumed", job.Name))
+	default:
+		reply(h.msg(ctx, "job.not_paused", job.Name))
+	}
+}
+
+// jobPaused reports whether a job's scheduled runs are paused. If the
+// state can't be read, the job runs.
+func (h *Handler) jobPaused(ctx context.Context, name string) bool {
+	paused, err := h.db.PausedJobs(ctx)
+	if err != nil {
+		slog.Warn("Failed to load paused jobs", "error", err)
+		return false
+	}
+	_, ok := paused[name]
+	return ok
+}
+
+// CatchUpJobs makes up, once each, the scheduled runs missed while the bot
+// was down: a job with a catch-up window runs if a time it was scheduled
+// for within that window before startup has passed since its last
+// successful run. Jobs that never succeeded and paused jobs are left alone.
+func (h *Handler) CatchUpJobs(ctx context.Context, startup time.Time) {
+	cfg := h.config()
+	var due []config.JobConfig
+	for _, job := range cfg.Jobs {
+		if cfg.JobCatchUpWindow(job) > 0 {
+			due = append(due, job)
+		}
+	}
+	if len(due) == 0 {
+		return
+	}
+	last, err := h.db.LastSuccessfulJobRuns(ctx)
+	if err != nil {
+		slog.Error("Failed to load job history for catch-up", "error", err)
+		return
+	}
+	for _, job := range due {
+		r, ok := last[job.Name]
+		if !ok {
+			continue
+		}
+		s, err := schedule.Parse(job.Schedule, cfg.JobLocation(job))
+		if err != nil {
+			continue
+		}
+		since := r.StartedAt
+		if start := startup.Add(-cfg.JobCatchUpWindow(job)); start.After(since) {
+			since = start
+		}
+		missed := s.Next(since)
+		if missed.IsZero() || missed.After(startup) || h.jobPaused(ctx, job.Name) {
+			continue
+		}
+		slog.Info("Catching up on a missed job run", "name", job.Name, "missed", missed)
+		_, _ = h.runJob(ctx, job, db.TriggerCatchUp)
+	}
+}
+
+func (h *Handler) handleJobRun(ctx context.Context, sessionID, name string, reply func(string)) {
+	job, ok := h.findJob(name)
+	if !ok {
+		reply(h.msg(ctx, "jobs.not_found", name))
+		return
+	}
+	if !h.allowMission(ctx, sessionID, reply) {
+		return
+	}
+	reply(h.msg(ctx, "jobs.running", job.Name))
+	path, err := h.runJob(ctx, job, db.TriggerManual)
+	if err != nil {
+		reply(h.msg(ctx, "jobs.run_failed", job.Name, err))
+		return
+	}
+	msg := h.msg(ctx, "jobs.done", job.Name)
+	if path != "" {
+		msg += h.msg(ctx, "jobs.done_report", path)
+	}
+	reply(msg)
+}
+
+func (h *Handler) handleJobStatus(ctx context.Context, text string, reply func(string)) {
+	name := strings.TrimSpace(text[len("/jobstatus"):])
+	if name == "" {
+		reply(h.msg(ctx, "jobstatus.usage"))
+		return
+	}
+	if job, ok := h.findJob(name); ok {
+		name = job.Name
+	}
+	runs, err := h.db.ListJobRuns(ctx, name, maxJobStatusRuns)
+	if err != nil {
+		slog.Error("Failed to load job runs", "name", name, "error", err)
+		reply(h.msg(ctx, "jobs.failed"))
+		return
+	}
+	if len(runs) == 0 {
+		reply(h.msg(ctx, "jobstatus.none", name))
+		return
+	}
+	loc := h.config().Location()
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "jobstatus.header", runs[0].JobName, len(runs)))
+	for _, r := range runs {
+		sb.WriteString(fmt.Sprintf("• %s (%s) %s", r.StartedAt.In(loc).Format("Jan 2 15:04"), r.TriggeredBy, h.jobStatus(ctx, r.Status)))
+		if r.FinishedAt != nil {
+			sb.WriteString(" · " + r.Duration().Round(time.Second).String())
+		}
+		if r.Tokens > 0 {
+			sb.WriteString(h.msg(ctx, "jobstatus.tokens", r.Tokens))
+		}
+		sb.WriteString("\n")
+		if r.ReportPath != "" {
+			sb.WriteString("  `" + r.ReportPath + "`\n")
+		}
+		if r.Error != "" {
+			sb.WriteString("  _" + plainSnippet(r.Error, 200) + "_\n")
+		}
+	}
+	reply(sb.String())
+}
+
+func (h *Handler) jobStatus(ctx context.Context, status string) string {
+	switch status {
+	case db.JobSucceeded:
+		return h.msg(ctx, "jobs.status_success")
+	case db.JobFailed:
+		return h.msg(ctx, "jobs.status_failed")
+	}
+	return h.msg(ctx, "jobs.status_running")
+}
+
+// jobTemplateData is what a job's report template sees.
+type jobTemplateData struct {
+	Title  string // the job name
+	Type   string
+	Report string
+	Date   time.Time // in the job's timezone
+	Tags   []string
+}
+
+// applyJobTemplate wraps a report in the job's template. If the template
+// fails, the report is used as it is rather than lost.
+func (h *Handler) applyJobTemplate(job config.JobConfig, report string, tags []string) string {
+	tmpl, err := job.ParseTemplate()
+	if tmpl == nil || err != nil {
+		if err != nil {
+			slog.Error("Invalid job template, using the plain report", "name", job.Name, "error", err)
+		}
+		return report
+	}
+	var sb strings.Builder
+	data := jobTemplateData{Title: job.Name, Type: job.Type, Report: report, Date: time.Now().In(h.config().JobLocation(job)), Tags: tags}
+	if err := tmpl.Execute(&sb, data); err != nil {
+		slog.Error("Job template failed, using the plain report", "name", job.Name, "error", err)
+		return report
+	}
+	return sb.String()
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessage_Jobs(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	summaryErr := errors.New("model overloaded")
+	h.bot = &mockBot{runMissionFunc: func(ctx context.Context, _ string) (string, error) {
+		stats.CountTokens(ctx, 1000, 200)
+		return "", summaryErr
+	}}
+	cfg := *h.config()
+	cfg.Jobs = []config.JobConfig{
+		{Name: "Weekly Feedback", Schedule: "0 0 9 * * 1", Type: "feedback_summary"},
+		{Name: "Daily Briefing", Schedule: "0 0 7 * * *", Type: "research", Timezone: "America/Chicago"},
+	}
+	h.UpdateConfig(&cfg)
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/jobs", nil, reply)
+	assert.Contains(t, got, "**Jobs (2)**")
+	assert.Contains(t, got, "• **Daily Briefing** (`research`, `0 0 7 * * * America/Chicago`) — never run")
+
+	// Nothing to summarize: a successful scheduled run without tokens.
+	h.RunJob(ctx, cfg.Jobs[0])
+
+	_, err := database.AddFeedback(ctx, "test-session", "More Go news")
+	require.NoError(t, err)
+	h.HandleMessage(ctx, "test-session", "/jobs run weekly feedback", nil, reply)
+	assert.Equal(t, "❌ **Weekly Feedback** failed: feedback summary failed: model overloaded", got)
+
+	h.HandleMessage(ctx, "test-session", "/jobs", nil, reply)
+	assert.Contains(t, got, "• **Weekly Feedback** (`feedback_summary`, `0 0 9 * * 1`) — last run")
+	assert.Contains(t, got, "❌ failed")
+
+	h.HandleMessage(ctx, "test-session", "/jobstatus WEEKLY FEEDBACK", nil, reply)
+	assert.Contains(t, got, "**Weekly Feedback**: last 2 run(s)")
+	assert.Contains(t, got, "(manual) ❌ failed")
+	assert.Contains(t, got, "1200 tokens")
+	assert.Contains(t, got, "_feedback summary failed: model overloaded_")
+	assert.Contains(t, got, "(scheduled) ✅ success")
+
+	runs, err := database.ListJobRuns(ctx, "Weekly Feedback", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, db.JobFailed, runs[0].Status)
+	assert.Equal(t, int64(1200), runs[0].Tokens)
+	assert.Equal(t, int64(0), runs[1].Tokens)
+
+	h.HandleMessage(ctx, "test-session", "/jobstatus Daily Briefing", nil, reply)
+	assert.Contains(t, got, "hasn't run yet")
+	h.HandleMessage(ctx, "test-session", "/jobs run nope", nil, reply)
+	assert.Contains(t, got, "No job named **nope**")
+	h.HandleMessage(ctx, "test-session", "/jobs delete x", nil, reply)
+	assert.Contains(t, got, "Usage")
+	h.HandleMessage(ctx, "test-session", "/jobstatus", nil, reply)
+	assert.Contains(t, got, "Usage")
+}
+
+func TestHandleMessage_JobPause(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	h.cfg.ReportsDir = t.TempDir()
+	runs := 0
+	h.bot = &mockBot{chatFunc: func(context.Context, string, string) (string, error) {
+		runs++
+		return "Nothing new.", nil
+	}}
+	cfg := *h.config()
+	cfg.Bot.Admins = []string{"admin-session"}
+	cfg.Jobs = []config.JobConfig{{Name: "Noisy Digest", Schedule: "0 */10 * * * *", Type: "chat", Params: map[string]string{"prompt": "Anything new?"}}}
+	h.UpdateConfig(&cfg)
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "admin-session", "/job pause", nil, reply)
+	assert.Contains(t, got, "Usage")
+	h.HandleMessage(ctx, "test-session", "/job pause noisy digest", nil, reply)
+	assert.Contains(t, got, "Only admins")
+	h.HandleMessage(ctx, "admin-session", "/job pause nope", nil, reply)
+	assert.Contains(t, got, "No job named **nope**")
+
+	h.HandleMessage(ctx, "admin-session", "/job pause noisy digest", nil, reply)
+	assert.Contains(t, got, "**Noisy Digest** is paused")
+	h.HandleMessage(ctx, "admin-session", "/job pause Noisy Digest", nil, reply)
+	assert.Contains(t, got, "already paused")
+	h.HandleMessage(ctx, "admin-session", "/jobs", nil, reply)
+	assert.Contains(t, got, "never run · ⏸ paused")
+
+	// Scheduled runs are skipped, manual ones are not.
+	h.RunJob(ctx, cfg.Jobs[0])
+	assert.Zero(t, runs)
+	h.HandleMessage(ctx, "admin-session", "/jobs run noisy digest", nil, reply)
+	assert.Equal(t, 1, runs, "a manual run still runs")
+
+	// The pause is stored, so a restarted handler keeps it.
+	restarted := New(h.bot, database, h.config(), h.stats, nil)
+	assert.True(t, restarted.jobPaused(ctx, "Noisy Digest"))
+
+	h.HandleMessage(ctx, "admin-session", "/job resume noisy digest", nil, reply)
+	assert.Contains(t, got, "running on schedule again")
+	h.HandleMessage(ctx, "admin-session", "/job resume noisy digest", nil, reply)
+	assert.Contains(t, got, "isn't paused")
+	assert.False(t, restarted.jobPaused(ctx, "Noisy Digest"))
+}
+
+func TestCatchUpJobs(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+	h.cfg.ReportsDir = t.TempDir()
+
+	var ran []string
+	h.bot = &mockBot{chatFunc: func(_ context.Context, sessionID, _ string) (string, error) {
+		ran = append(ran, sessionID)
+		return "Run for " + sessionID, nil
+	}}
+	job := func(name, catchUp string) config.JobConfig {
+		return config.JobConfig{Name: name, Schedule: "@every 1h", Type: "chat", CatchUp: catchUp, Params: map[string]string{"prompt": "Status?"}}
+	}
+	cfg := *h.config()
+	cfg.JobCatchUp = "3h"
+	cfg.Jobs = []config.JobConfig{
+		job("Missed", ""),
+		job("Recent", ""),
+		job("Short Window", "20m"),
+		job("Never Ran", ""),
+		job("Opted Out", "off"),
+		job("Paused", ""),
+	}
+	h.UpdateConfig(&cfg)
+
+	startup := time.Now()
+	succeeded := func(name string, ago time.Duration) {
+		id, err := database.StartJobRun(ctx, name, "chat", db.TriggerScheduled)
+		require.NoError(t, err)
+		require.NoError(t, database.FinishJobRun(ctx, id, db.JobSucceeded, "", "", 0))
+		_, err = database.ExecContext(ctx, "UPDATE job_runs SET started_at = ? WHERE id = ?", startup.Add(-ago).UTC(), id)
+		require.NoError(t, err)
+	}
+	succeeded("Missed", 90*time.Minute)
+	succeeded("Recent", 10*time.Minute)
+	succeeded("Short Window", 90*time.Minute)
+	succeeded("Opted Out", 90*time.Minute)
+	succeeded("Paused", 90*time.Minute)
+	_, err := database.PauseJob(ctx, "Paused")
+	require.NoError(t, err)
+
+	h.CatchUpJobs(ctx, startup)
+	assert.Equal(t, []string{"job:missed"}, ran)
+	runs, err := database.ListJobRuns(ctx, "Missed", 1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, db.TriggerCatchUp, runs[0].TriggeredBy)
+
+	// The catch-up run counts as the missed one.
+	h.CatchUpJobs(ctx, startup.Add(time.Minute))
+	assert.Len(t, ran, 1)
+}
+
+func TestJobConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+	h.cfg.ReportsDir = t.TempDir()
+
+	var running, peak atomic.Int32
+	h.bot = &mockBot{chatFunc: func(_ context.Context, sessionID, _ string) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(20 * time.Millisecond)
+		return "Report from " + sessionID, nil
+	}}
+	cfg := *h.config()
+	cfg.MaxConcurrentJobs = 2
+	h.UpdateConfig(&cfg)
+
+	var wg sync.WaitGroup
+	for i := range 5 {
+		job := config.JobConfig{Name: fmt.Sprintf("Midnight %d", i), Type: "chat", Params: map[string]string{"prompt": "Go."}}
+		wg.Go(func() {
+			_, err := h.runJob(ctx, job, db.TriggerScheduled)
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), peak.Load())
+
+	// A job waiting for a slot gives up with its context.
+	release, err := h.acquireJobSlot(ctx, "first")
+	require.NoError(t, err)
+	defer release()
+	release2, err := h.acquireJobSlot(ctx, "second")
+	require.NoError(t, err)
+	defer release2()
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = h.runJob(waitCtx, config.JobConfig{Name: "Late", Type: "chat"}, db.TriggerScheduled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlimited := cfg
+	unlimited.MaxConcurrentJobs = -1
+	h.UpdateConfig(&unlimited)
+	_, err = h.acquireJobSlot(waitCtx, "unlimited")
+	assert.NoError(t, err)
+}
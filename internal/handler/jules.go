@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/i18n"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+)
+
+const (
+	// julesTrackingWindow is how long a Jules session is polled; one that
+	// hasn't finished by then is left alone.
+	julesTrackingWindow = 7 * 24 * time.Hour
+	// julesStatusLimit caps the sessions /jules status lists.
+	julesStatusLimit = 10
+	// julesDetailLen caps how much of Jules's latest message is quoted.
+	julesDetailLen = 500
+)
+
+func (h *Handler) handleJules(ctx context.Context, sessionID, chatID, text string, reply func(string)) {
+	parts := strings.Fields(text[len("/jules"):])
+	if len(parts) == 1 && strings.EqualFold(parts[0], "status") {
+		h.handleJulesStatus(ctx, sessionID, reply)
+		return
+	}
+	if len(parts) < 2 {
+		reply(h.msg(ctx, "jules.usage"))
+		return
+	}
+	repo := parts[0]
+	task := strings.Join(parts[1:], " ")
+	h.confirm(ctx, sessionID, h.msg(ctx, "jules.confirm", task, repo),
+		func(ctx context.Context, reply func(string)) {
+			h.runJules(ctx, chatID, repo, task, reply)
+		}, reply)
+}
+
+func (h *Handler) runJules(ctx context.Context, sessionID, repo, task string, reply func(string)) {
+	reply(h.msg(ctx, "jules.delegating", repo, task))
+	prompt := fmt.Sprintf("Ask the Jules agent to delegate this coding task to the external Jules service for repository %s: %s", repo, task)
+	response, err := h.bot.Chat(ctx, sessionID, prompt)
+	if err != nil {
+		slog.Error("Jules delegation failed", "repo", repo, "task", task, "error", err)
+		reply(h.msg(ctx, "jules.failed"))
+		return
+	}
+	reply(response)
+}
+
+// trackJulesSession records a Jules session the bot started for sessionID,
+// with where the request came from, so PollJulesSessions reports back there.
+func (h *Handler) trackJulesSession(ctx context.Context, sessionID string, s agent.JulesStart) {
+	platform, _ := ctx.Value(platformKey{}).(string)
+	err := h.db.AddJulesSession(ctx, db.JulesSession{
+		SessionID: sessionID,
+		Name:      s.Session.Name,
+		Repo:      s.Repo,
+		Task:      s.Task,
+		Platform:  platform,
+		ChannelID: notifier.ChannelFromContext(ctx),
+		State:     s.Session.State,
+		URL:       s.Session.URL,
+	})
+	if err != nil {
+		slog.Error("Failed to record Jules session", "sessionID", sessionID, "session", s.Session.Name, "error", err)
+		return
+	}
+	slog.Info("Tracking Jules session", "sessionID", sessionID, "session", s.Session.Name, "repo", s.Repo)
+}
+
+// PollJulesSessions checks every Jules session started in the last week
+// that hasn't finished, and tells its chat when Jules opens a pull request,
+// needs an answer, or finishes. It does nothing without a Jules API key.
+func (h *Handler) PollJulesSessions(ctx context.Context) {
+	apiKey := h.config().JulesAPIKey
+	if apiKey == "" {
+		return
+	}
+	pending, err := h.db.PendingJulesSessions(ctx, time.Now().Add(-julesTrackingWindow))
+	if err != nil {
+		slog.Error("Failed to list pending Jules sessions", "error", err)
+		return
+	}
+	for _, s := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		h.pollJulesSession(ctx, apiKey, s)
+	}
+}
+
+// pollJulesSession reads one session's state and latest activities, stores
+// what changed and announces it.
+func (h *Handler) pollJulesSession(ctx context.Context, apiKey string, s db.JulesSession) db.JulesSession {
+	cur, err := h.julesSession(ctx, apiKey, s.Name)
+	if err != nil {
+		slog.Warn("Failed to poll Jules session", "session", s.Name, "error", err)
+		return s
+	}
+	activities, err := h.julesActivities(ctx, apiKey, s.Name)
+	if err != nil {
+		slog.Warn("Failed to read Jules activities", "session", s.Name, "error", err)
+	}
+	updated := s
+	updated.State = cur.State
+	if cur.URL != "" {
+		updated.URL = cur.URL
+	}
+	if detail := julesDetail(activities, cur.State); detail != "" {
+		updated.Detail = detail
+	}
+
+	locale := h.sessionLocale(ctx, s.SessionID)
+	var notes []string
+	if n := len(cur.PullRequests); n > 0 && cur.PullRequests[n-1].URL != s.PRURL {
+		pr := cur.PullRequests[n-1]
+		updated.PRURL = pr.URL
+		notes = append(notes, i18n.T(locale, "jules.pr_opened", pullRequestLabel(pr.URL), s.Repo, pr.Title, pr.URL))
+	}
+	if cur.State != s.State {
+		quote := ""
+		if updated.Detail != "" {
+			quote = "\n\n> " + strings.ReplaceAll(updated.Detail, "\n", "\n> ")
+		}
+		switch cur.State {
+		case "AWAITING_USER_FEEDBACK", "AWAITING_PLAN_APPROVAL":
+			notes = append(notes, i18n.T(locale, "jules.needs_input", s.Repo, updated.URL)+quote)
+		case "FAILED":
+			notes = append(notes, i18n.T(locale, "jules.session_failed", s.Repo, updated.URL)+quote)
+		case "COMPLETED":
+			// A pull request announced just now says as much.
+			if len(notes) == 0 {
+				notes = append(notes, i18n.T(locale, "jules.completed", s.Repo, updated.URL))
+			}
+		}
+	}
+
+	if updated.State != s.State || updated.URL != s.URL || updated.PRURL != s.PRURL || updated.Detail != s.Detail {
+		if err := h.db.UpdateJulesSession(ctx, s.ID, updated.State, updated.URL, updated.PRURL, updated.Detail); err != nil {
+			slog.Error("Failed to update Jules session", "session", s.Name, "error", err)
+			return s
+		}
+	}
+	for _, note := range notes {
+		h.deliver(ctx, s.SessionID, s.Platform, s.ChannelID, note)
+	}
+	if len(notes) > 0 {
+		slog.Info("Jules session update announced", "session", s.Name, "state", cur.State, "pr", updated.PRURL)
+	}
+	return updated
+}
+
+// julesDetail picks what to show of a session's activities: the question
+// or plan awaiting an answer, the failure reason, or the latest progress.
+func julesDetail(activities []tools.JulesActivity, state string) string {
+	want := "progress"
+	switch state {
+	case "AWAITING_USER_FEEDBACK":
+		want = "message"
+	case "AWAITING_PLAN_APPROVAL":
+		want = "plan"
+	case "FAILED":
+		want = "failed"
+	}
+	var fallback string
+	for i := len(activities) - 1; i >= 0; i-- {
+		a := activities[i]
+		text := strings.TrimSpace(a.Text)
+		if text == "" {
+			continue
+		}
+		if a.Kind == want {
+			return truncateRunes(text, julesDetailLen)
+		}
+		if fallback == "" {
+			fallback = text
+		}
+	}
+	return truncateRunes(fallback, julesDetailLen)
+}
+
+// pullRequestLabel returns "#42" for a GitHub pull request URL, or the URL.
+func pullRequestLabel(url string) string {
+	if _, num, ok := strings.Cut(url, "/pull/"); ok {
+		num, _, _ = strings.Cut(num, "/")
+		if num != "" {
+			return "#" + num
+		}
+	}
+	return url
+}
+
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
+
+// handleJulesStatus lists the session's latest Jules sessions, refreshing
+// those still running first.
+func (h *Handler) handleJulesStatus(ctx context.Context, sessionID string, reply func(string)) {
+	sessions, err := h.db.ListJulesSessions(ctx, sessionID, julesStatusLimit)
+	if err != nil {
+		slog.Error("Failed to list Jules sessions", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "jules.status_failed"))
+		return
+	}
+	if len(sessions) == 0 {
+		reply(h.msg(ctx, "jules.status_none"))
+		return
+	}
+	if apiKey := h.config().JulesAPIKey; apiKey != "" {
+		for i, s := range sessions {
+			if s.State != "COMPLETED" && s.State != "FAILED" {
+				sessions[i] = h.pollJulesSession(ctx, apiKey, s)
+			}
+		}
+	}
+
+	loc := h.config().Location()
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "jules.status_header", len(sessions)))
+	for _, s := range sessions {
+		state := s.State
+		if state == "" {
+			state = "UNKNOWN"
+		}
+		sb.WriteString(fmt.Sprintf("• **%s** — %s\n  `%s` · %s", s.Repo, truncateRunes(s.Task, 80), state, s.CreatedAt.In(loc).Format("Jan 2 15:04")))
+		switch {
+		case s.PRURL != "":
+			sb.WriteString(" · " + h.msg(ctx, "jules.status_pr", pullRequestLabel(s.PRURL), s.PRURL))
+		case s.URL != "":
+			sb.WriteString(" · " + s.URL)
+		}
+		sb.WriteString("\n")
+		if s.Detail != "" && s.State != "COMPLETED" {
+			line, _, _ := strings.Cut(s.Detail, "\n")
+			sb.WriteString("  _" + truncateRunes(line, 120) + "_\n")
+		}
+	}
+	reply(sb.String())
+}
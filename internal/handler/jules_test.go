@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJulesSessionTracking(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+	h.bot = &mockBot{}
+
+	var got []string
+	reply := func(r string) { got = append(got, r) }
+
+	h.HandleMessage(ctx, "test-session", "/jules status", nil, reply)
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "haven't started any Jules sessions")
+
+	h.trackJulesSession(ctx, "test-session", agent.JulesStart{
+		Repo:    "owner/repo",
+		Task:    "fix the flaky test",
+		Session: tools.JulesSession{Name: "sessions/1", State: "QUEUED", URL: "https://jules.google/session/1"},
+	})
+
+	current := tools.JulesSession{Name: "sessions/1", State: "IN_PROGRESS", URL: "https://jules.google/session/1"}
+	activities := []tools.JulesActivity{{Kind: "progress", Text: "Running the test suite"}}
+	h.julesSession = func(_ context.Context, apiKey, name string) (tools.JulesSession, error) {
+		assert.Equal(t, "jules-key", apiKey)
+		assert.Equal(t, "sessions/1", name)
+		return current, nil
+	}
+	h.julesActivities = func(context.Context, string, string) ([]tools.JulesActivity, error) {
+		return activities, nil
+	}
+
+	// Without an API key there is nothing to poll.
+	h.PollJulesSessions(ctx)
+	assert.Len(t, got, 1)
+
+	h.cfg.JulesAPIKey = "jules-key"
+	got = nil
+	h.PollJulesSessions(ctx)
+	assert.Empty(t, got, "progress alone is not announced")
+
+	h.HandleMessage(ctx, "test-session", "/jules status", nil, reply)
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "Jules sessions** (1)")
+	assert.Contains(t, got[0], "owner/repo")
+	assert.Contains(t, got[0], "`IN_PROGRESS`")
+	assert.Contains(t, got[0], "Running the test suite")
+
+	// Jules asks a question.
+	current.State = "AWAITING_USER_FEEDBACK"
+	activities = append(activities, tools.JulesActivity{Kind: "message", Text: "Should I also update the CI config?"})
+	got = nil
+	h.PollJulesSessions(ctx)
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "needs your input")
+	assert.Contains(t, got[0], "> Should I also update the CI config?")
+
+	// The pull request is announced once, and completion with it.
+	current.State = "COMPLETED"
+	current.PullRequests = []tools.JulesPullRequest{{URL: "https://github.com/owner/repo/pull/42", Title: "Fix the flaky test"}}
+	got = nil
+	h.PollJulesSessions(ctx)
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "Jules opened PR #42 on **owner/repo**: Fix the flaky test")
+	got = nil
+	h.PollJulesSessions(ctx)
+	assert.Empty(t, got, "finished sessions are no longer polled")
+
+	h.HandleMessage(ctx, "test-session", "/jules status", nil, reply)
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], "`COMPLETED`")
+	assert.Contains(t, got[0], "PR [#42](https://github.com/owner/repo/pull/42)")
+}
+
+func TestJulesDetail(t *testing.T) {
+	t.Parallel()
+	activities := []tools.JulesActivity{
+		{Kind: "plan", Text: "1. Update the tests"},
+		{Kind: "message", Text: "Which branch should I use?"},
+		{Kind: "progress", Text: "Editing handler.go"},
+		{Kind: "failed", Text: "Tests did not pass"},
+		{Kind: "", Text: "  "},
+	}
+	assert.Equal(t, "Which branch should I use?", julesDetail(activities, "AWAITING_USER_FEEDBACK"))
+	assert.Equal(t, "1. Update the tests", julesDetail(activities, "AWAITING_PLAN_APPROVAL"))
+	assert.Equal(t, "Tests did not pass", julesDetail(activities, "FAILED"))
+	assert.Equal(t, "Editing handler.go", julesDetail(activities, "IN_PROGRESS"))
+	assert.Equal(t, "Tests did not pass", julesDetail(activities[3:], "IN_PROGRESS"), "falls back to the latest text")
+	assert.Empty(t, julesDetail(nil, "IN_PROGRESS"))
+
+	assert.Equal(t, "#42", pullRequestLabel("https://github.com/owner/repo/pull/42"))
+	assert.Equal(t, "#7", pullRequestLabel("https://github.com/owner/repo/pull/7/files"))
+	assert.Equal(t, "https://example.com/pr", pullRequestLabel("https://example.com/pr"))
+}
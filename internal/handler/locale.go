@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/i18n"
+)
+
+// msg returns a catalog message in the locale of the request being handled.
+func (h *Handler) msg(ctx context.Context, key string, args ...any) string {
+	return i18n.T(i18n.FromContext(ctx), key, args...)
+}
+
+// sessionLocale resolves the session's language: its /language choice, then
+// bot.locale from the config, then English.
+func (h *Handler) sessionLocale(ctx context.Context, sessionID string) string {
+	locale, err := h.db.GetSessionLocale(ctx, sessionID)
+	if err != nil {
+		slog.Warn("Failed to load session locale", "sessionID", sessionID, "error", err)
+	}
+	if locale != "" {
+		return locale
+	}
+	return h.defaultLocale()
+}
+
+func (h *Handler) defaultLocale() string {
+	if l, ok := i18n.Normalize(h.config().Bot.Locale); ok {
+		return l
+	}
+	return i18n.DefaultLocale
+}
+
+func (h *Handler) handleLanguage(ctx context.Context, sessionID, text string, reply func(string)) {
+	arg := strings.TrimSpace(text[len("/language"):])
+	available := "`" + strings.Join(i18n.Supported(), "`, `") + "`"
+
+	if arg == "" {
+		locale := i18n.FromContext(ctx)
+		reply(h.msg(ctx, "language.current", i18n.Name(locale), locale, available))
+		return
+	}
+
+	if strings.EqualFold(arg, "default") {
+		if err := h.db.SetSessionLocale(ctx, sessionID, ""); err != nil {
+			slog.Error("Failed to reset session locale", "sessionID", sessionID, "error", err)
+			reply(h.msg(ctx, "language.failed"))
+			return
+		}
+		locale := h.defaultLocale()
+		reply(i18n.T(locale, "language.reset", i18n.Name(locale)))
+		return
+	}
+
+	locale, ok := i18n.Normalize(arg)
+	if !ok {
+		reply(h.msg(ctx, "language.invalid", arg, available))
+		return
+	}
+	if err := h.db.SetSessionLocale(ctx, sessionID, locale); err != nil {
+		slog.Error("Failed to set session locale", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "language.failed"))
+		return
+	}
+	// Confirm in the newly selected language.
+	reply(i18n.T(locale, "language.set", i18n.Name(locale)))
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleMessage_Language(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/language", nil, reply)
+	assert.Contains(t, got, "**English** (`en`)")
+
+	h.HandleMessage(ctx, "test-session", "/language klingon", nil, reply)
+	assert.Contains(t, got, "Unsupported language")
+
+	h.HandleMessage(ctx, "test-session", "/language es-MX", nil, reply)
+	assert.Equal(t, "🌐 Idioma cambiado a **Español**.", got)
+
+	h.HandleMessage(ctx, "test-session", "/forget 42", nil, reply)
+	assert.Equal(t, "❌ No hay un recuerdo con ID `#42`.", got)
+
+	// Other sessions keep the configured default.
+	h.HandleMessage(ctx, "other-session", "/forget 42", nil, reply)
+	assert.Equal(t, "❌ No memory with ID `#42`.", got)
+
+	cfg := *h.config()
+	cfg.Bot.Locale = "es"
+	cfg.Bot.HelpMessages = map[string]string{"es": "mensaje de ayuda"}
+	h.UpdateConfig(&cfg)
+	h.HandleMessage(ctx, "other-session", "/help", nil, reply)
+	assert.Equal(t, "mensaje de ayuda", got)
+
+	cfg.Bot.Locale = ""
+	h.UpdateConfig(&cfg)
+	h.HandleMessage(ctx, "test-session", "/language default", nil, reply)
+	assert.Contains(t, got, "English")
+	h.HandleMessage(ctx, "test-session", "/help", nil, reply)
+	assert.Equal(t, "test help message", got)
+}
+
+func TestConfirm_Localized(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	bot := &clearRecordingBot{}
+	h.bot = bot
+	_ = database.SetSessionLocale(ctx, "test-session", "es")
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/reset", nil, reply)
+	assert.Contains(t, got, "Responde `sí`")
+	h.HandleMessage(ctx, "test-session", "Sí", nil, reply)
+	assert.Equal(t, []string{"test-session"}, bot.cleared)
+	assert.Contains(t, got, "Conversación borrada")
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/config"
+)
+
+// handleMCP adds, removes or refreshes an MCP server while the bot runs:
+// /mcp add <name> <command|url> [args...], /mcp remove <name> and
+// /mcp refresh <name>. Admin only, as a server runs commands on the host.
+func (h *Handler) handleMCP(ctx context.Context, sessionID, text string, reply func(string)) {
+	manager, ok := h.bot.(MCPServerManager)
+	if !ok {
+		reply(h.msg(ctx, "mcp.unavailable"))
+		return
+	}
+	args := strings.Fields(text[len("/mcp"):])
+	if len(args) < 2 || !(strings.EqualFold(args[0], "add") && len(args) >= 3 || (strings.EqualFold(args[0], "remove") || strings.EqualFold(args[0], "refresh")) && len(args) == 2) {
+		reply(h.msg(ctx, "mcp.usage"))
+		return
+	}
+	if !h.config().Bot.IsAdmin(sessionID) {
+		slog.Warn("Rejected /mcp from non-admin", "sessionID", sessionID)
+		reply(h.msg(ctx, "mcp.denied"))
+		return
+	}
+	name := args[1]
+
+	if strings.EqualFold(args[0], "refresh") {
+		n, err := manager.RefreshMCPServer(ctx, name)
+		switch {
+		case errors.Is(err, agent.ErrNoMCPServer):
+			reply(h.msg(ctx, "mcp.unknown", name))
+		case err != nil:
+			slog.Warn("Failed to refresh MCP server", "sessionID", sessionID, "server", name, "error", err)
+			reply(h.msg(ctx, "mcp.refresh_failed", name, err))
+		default:
+			reply(h.msg(ctx, "mcp.refreshed", name, n))
+		}
+		return
+	}
+
+	if strings.EqualFold(args[0], "remove") {
+		if err := manager.RemoveMCPServer(ctx, name); err != nil {
+			slog.Warn("Failed to remove MCP server", "sessionID", sessionID, "server", name, "error", err)
+			if errors.Is(err, agent.ErrNoMCPServer) {
+				reply(h.msg(ctx, "mcp.unknown", name))
+				return
+			}
+			reply(h.msg(ctx, "mcp.remove_failed", name))
+			return
+		}
+		slog.Info("MCP server removed", "server", name, "by", sessionID)
+		reply(h.msg(ctx, "mcp.removed", name))
+		return
+	}
+
+	owner, err := manager.AddMCPServer(ctx, name, config.MCPServerConfig{Command: args[2], Args: args[3:]})
+	switch {
+	case errors.Is(err, agent.ErrInvalidMCPName):
+		reply(h.msg(ctx, "mcp.invalid_name", name))
+	case errors.Is(err, agent.ErrMCPServerExists):
+		reply(h.msg(ctx, "mcp.exists", name))
+	case err != nil:
+		slog.Warn("Failed to add MCP server", "sessionID", sessionID, "server", name, "error", err)
+		reply(h.msg(ctx, "mcp.add_failed", name, err))
+	default:
+		slog.Info("MCP server added", "server", name, "command", args[2], "by", sessionID)
+		reply(h.msg(ctx, "mcp.added", name, owner))
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type serverManagerBot struct {
+	mockBot
+	servers map[string]config.MCPServerConfig
+}
+
+func (b *serverManagerBot) AddMCPServer(_ context.Context, name string, serverCfg config.MCPServerConfig) (string, error) {
+	switch {
+	case name == "bad/name":
+		return "", fmt.Errorf("%w: %q", agent.ErrInvalidMCPName, name)
+	case serverCfg.Command == "missing-mcp":
+		return "", errors.New("exec: not found")
+	}
+	if _, ok := b.servers[name]; ok {
+		return "", fmt.Errorf("%w: %q", agent.ErrMCPServerExists, name)
+	}
+	b.servers[name] = serverCfg
+	return "ResearchAssistant", nil
+}
+
+func (b *serverManagerBot) RemoveMCPServer(_ context.Context, name string) error {
+	if _, ok := b.servers[name]; !ok {
+		return fmt.Errorf("%w: %q", agent.ErrNoMCPServer, name)
+	}
+	delete(b.servers, name)
+	return nil
+}
+
+func (b *serverManagerBot) RefreshMCPServer(_ context.Context, name string) (int, error) {
+	if name == "flaky" {
+		return 0, errors.New("context deadline exceeded")
+	}
+	if _, ok := b.servers[name]; !ok {
+		return 0, fmt.Errorf("%w: %q", agent.ErrNoMCPServer, name)
+	}
+	return 3, nil
+}
+
+func TestHandleMessage_MCP(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/mcp add notes notes-mcp", nil, reply)
+	assert.Contains(t, got, "not available")
+
+	bot := &serverManagerBot{servers: make(map[string]config.MCPServerConfig)}
+	h.bot = bot
+	h.HandleMessage(ctx, "test-session", "/mcp add notes npx -y @acme/notes-mcp", nil, reply)
+	assert.Contains(t, got, "`notes` is connected; its tools are available to ResearchAssistant")
+	assert.Equal(t, config.MCPServerConfig{Command: "npx", Args: []string{"-y", "@acme/notes-mcp"}}, bot.servers["notes"])
+
+	h.HandleMessage(ctx, "test-session", "/mcp add notes notes-mcp", nil, reply)
+	assert.Contains(t, got, "already an MCP server called `notes`")
+	h.HandleMessage(ctx, "test-session", "/mcp add bad/name notes-mcp", nil, reply)
+	assert.Contains(t, got, "isn't a valid server name")
+	h.HandleMessage(ctx, "test-session", "/mcp add gone missing-mcp", nil, reply)
+	assert.Contains(t, got, "Couldn't start `gone`: exec: not found")
+	h.HandleMessage(ctx, "test-session", "/mcp add notes", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	h.HandleMessage(ctx, "test-session", "/mcp refresh notes", nil, reply)
+	assert.Contains(t, got, "`notes` has 3 tools")
+	h.HandleMessage(ctx, "test-session", "/mcp refresh flaky", nil, reply)
+	assert.Contains(t, got, "Couldn't refresh `flaky`: context deadline exceeded")
+
+	h.HandleMessage(ctx, "test-session", "/mcp remove notes", nil, reply)
+	assert.Contains(t, got, "`notes` is stopped")
+	assert.Empty(t, bot.servers)
+	h.HandleMessage(ctx, "test-session", "/mcp remove notes", nil, reply)
+	assert.Contains(t, got, "no MCP server called `notes`")
+
+	cfg := *h.config()
+	cfg.Bot.Admins = []string{"admin-session"}
+	h.UpdateConfig(&cfg)
+	h.HandleMessage(ctx, "test-session", "/mcp add notes notes-mcp", nil, reply)
+	assert.Contains(t, got, "Only admins")
+	assert.Empty(t, bot.servers)
+}
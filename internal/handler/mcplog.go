@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/config"
+)
+
+// handleMCPLog changes the level of the log messages an MCP server sends,
+// for debugging it. Admin only.
+func (h *Handler) handleMCPLog(ctx context.Context, sessionID, text string, reply func(string)) {
+	leveler, ok := h.bot.(MCPLogLeveler)
+	if !ok {
+		reply(h.msg(ctx, "mcplog.unavailable"))
+		return
+	}
+	args := strings.Fields(text[len("/mcplog"):])
+	if len(args) != 2 || !slices.Contains(config.MCPLogLevels, strings.ToLower(args[1])) {
+		reply(h.msg(ctx, "mcplog.usage", strings.Join(config.MCPLogLevels, ", ")))
+		return
+	}
+	if !h.config().Bot.IsAdmin(sessionID) {
+		slog.Warn("Rejected /mcplog from non-admin", "sessionID", sessionID)
+		reply(h.msg(ctx, "mcplog.denied"))
+		return
+	}
+	server, level := args[0], strings.ToLower(args[1])
+
+	if err := leveler.SetMCPLogLevel(ctx, server, level); err != nil {
+		slog.Warn("Failed to set MCP server log level", "sessionID", sessionID, "server", server, "level", level, "error", err)
+		if errors.Is(err, agent.ErrNoLogging) {
+			reply(h.msg(ctx, "mcplog.unsupported", server))
+			return
+		}
+		reply(h.msg(ctx, "mcplog.failed", server))
+		return
+	}
+	slog.Info("MCP server log level set", "server", server, "level", level, "by", sessionID)
+	reply(h.msg(ctx, "mcplog.done", server, level))
+}
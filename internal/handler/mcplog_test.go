@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+type logLevelBot struct {
+	mockBot
+	levels map[string]string
+}
+
+func (b *logLevelBot) SetMCPLogLevel(_ context.Context, server, level string) error {
+	switch server {
+	case "github":
+		b.levels[server] = level
+		return nil
+	case "weather":
+		return agent.ErrNoLogging
+	}
+	return fmt.Errorf("no MCP server %q", server)
+}
+
+func TestHandleMessage_MCPLog(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/mcplog github debug", nil, reply)
+	assert.Contains(t, got, "not available")
+
+	bot := &logLevelBot{levels: make(map[string]string)}
+	h.bot = bot
+	h.HandleMessage(ctx, "test-session", "/mcplog github DEBUG", nil, reply)
+	assert.Contains(t, got, "`github` now logs from `debug` up")
+	assert.Equal(t, "debug", bot.levels["github"])
+
+	h.HandleMessage(ctx, "test-session", "/mcplog github verbose", nil, reply)
+	assert.Contains(t, got, "Usage")
+	h.HandleMessage(ctx, "test-session", "/mcplog weather info", nil, reply)
+	assert.Contains(t, got, "doesn't support logging")
+	h.HandleMessage(ctx, "test-session", "/mcplog gitlab info", nil, reply)
+	assert.Contains(t, got, "Couldn't set the log level of `gitlab`")
+
+	cfg := *h.config()
+	cfg.Bot.Admins = []string{"admin-session"}
+	h.UpdateConfig(&cfg)
+	h.HandleMessage(ctx, "test-session", "/mcplog github error", nil, reply)
+	assert.Contains(t, got, "Only admins")
+	assert.Equal(t, "debug", bot.levels["github"])
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/stats"
+)
+
+// handleMCPStats lists the calls made to each MCP server's tools since
+// startup, to spot the servers that are slow or often fail.
+func (h *Handler) handleMCPStats(ctx context.Context, reply func(string)) {
+	calls := h.stats.MCPCalls()
+	if len(calls) == 0 {
+		reply(h.msg(ctx, "mcpstats.none"))
+		return
+	}
+
+	byServer := make(map[string][]stats.MCPTool)
+	var total stats.MCPCallUsage
+	for t, u := range calls {
+		byServer[t.Server] = append(byServer[t.Server], t)
+		total = total.Add(u)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "mcpstats.header", total.Calls, total.Errors))
+	for _, server := range slices.Sorted(maps.Keys(byServer)) {
+		tools := byServer[server]
+		sort.Slice(tools, func(i, j int) bool {
+			if calls[tools[i]].Total != calls[tools[j]].Total {
+				return calls[tools[i]].Total > calls[tools[j]].Total
+			}
+			return tools[i].Tool < tools[j].Tool
+		})
+		var su stats.MCPCallUsage
+		for _, t := range tools {
+			su = su.Add(calls[t])
+		}
+		sb.WriteString(h.msg(ctx, "mcpstats.server", server, su.Calls, formatQueryTime(su.Avg()), su.ErrorPercent()))
+		for _, t := range tools {
+			u := calls[t]
+			sb.WriteString(fmt.Sprintf("• `%s` — %d× avg %s, max %s", t.Tool, u.Calls, formatQueryTime(u.Avg()), formatQueryTime(u.Max)))
+			if u.Errors > 0 {
+				sb.WriteString(h.msg(ctx, "mcpstats.failed", u.Errors, u.ErrorPercent()))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString(h.msg(ctx, "mcpstats.footer"))
+	reply(sb.String())
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleMessage_MCPStats(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/mcpstats", nil, reply)
+	assert.Contains(t, got, "No MCP tool calls")
+
+	h.stats.RecordMCPCall("weather", "forecast", 40*time.Millisecond, false)
+	h.stats.RecordMCPCall("github", "search", 2*time.Second, true)
+	h.stats.RecordMCPCall("github", "search", time.Second, false)
+	h.stats.RecordMCPCall("github", "create_issue", 300*time.Millisecond, false)
+
+	h.HandleMessage(ctx, "test-session", "/mcpstats", nil, reply)
+	assert.Contains(t, got, "**MCP Tool Calls**: 4, 1 failed")
+	assert.Contains(t, got, "**github** — 3 calls, avg 1.1s, 33% failed\n• `search` — 2× avg 1.5s, max 2s ⚠️ 1 failed (50%)\n• `create_issue` — 1× avg 300ms")
+	assert.Contains(t, got, "**weather** — 1 calls, avg 40ms, 0% failed")
+	assert.Less(t, strings.Index(got, "github"), strings.Index(got, "weather"), "servers are listed by name")
+}
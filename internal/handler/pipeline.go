@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+)
+
+// pipelineData is what the prompt of a job with runAfter sees.
+type pipelineData struct {
+	Output  string            // the latest report of the first job in runAfter
+	Outputs map[string]string // the latest report of each job in runAfter
+}
+
+// withPipelineInputs renders the prompt of a job that runs after other
+// jobs with their latest reports. Other jobs are returned as they are.
+func (h *Handler) withPipelineInputs(ctx context.Context, job config.JobConfig) (config.JobConfig, error) {
+	tmpl, err := job.ParsePrompt()
+	if tmpl == nil || err != nil {
+		return job, err
+	}
+	data := pipelineData{Outputs: make(map[string]string, len(job.RunAfter))}
+	for i, name := range job.RunAfter {
+		if upstream, ok := h.findJob(name); ok {
+			name = upstream.Name
+		}
+		// Only job reports: a /research briefing can share the job's name
+		// as its topic, and must not feed a user's text into the prompt.
+		briefings, err := h.db.GetBriefings(ctx, db.BriefingFilter{Topic: name, Shared: true, Limit: 1})
+		if err != nil {
+			return job, err
+		}
+		var output string
+		if len(briefings) > 0 {
+			output = briefings[0].Content
+		}
+		data.Outputs[name] = output
+		if i == 0 {
+			data.Output = output
+		}
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return job, fmt.Errorf("invalid prompt template: %w", err)
+	}
+	params := make(map[string]string, len(job.Params))
+	for k, v := range job.Params {
+		params[k] = v
+	}
+	params["prompt"] = sb.String()
+	job.Params = params
+	return job, nil
+}
+
+// runDependents runs the jobs that list job in runAfter, after it has
+// succeeded. A job that runs after several jobs waits until each of them
+// has succeeded since its own last successful run; paused jobs are left
+// out.
+func (h *Handler) runDependents(ctx context.Context, job config.JobConfig) {
+	var dependents []config.JobConfig
+	for _, next := range h.config().Jobs {
+		for _, name := range next.RunAfter {
+			if strings.EqualFold(name, job.Name) {
+				dependents = append(dependents, next)
+				break
+			}
+		}
+	}
+	if len(dependents) == 0 {
+		return
+	}
+	last, err := h.db.LastSuccessfulJobRuns(ctx)
+	if err != nil {
+		slog.Error("Failed to load job history for dependent jobs", "name", job.Name, "error", err)
+		return
+	}
+	for _, next := range dependents {
+		if !h.dependenciesReady(next, last) || h.jobPaused(ctx, next.Name) {
+			continue
+		}
+		slog.Info("Running dependent job", "name", next.Name, "after", job.Name)
+		_, _ = h.runJob(ctx, next, db.TriggerDependency)
+	}
+}
+
+// dependenciesReady reports whether every job in job.RunAfter has
+// succeeded since job last did.
+func (h *Handler) dependenciesReady(job config.JobConfig, last map[string]db.JobRun) bool {
+	own, ran := last[job.Name]
+	for _, name := range job.RunAfter {
+		if upstream, ok := h.findJob(name); ok {
+			name = upstream.Name
+		}
+		r, ok := last[name]
+		if !ok || (ran && r.ID < own.ID) {
+			return false
+		}
+	}
+	return true
+}
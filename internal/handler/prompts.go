@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/db"
+)
+
+// handlePrompts lists the prompt templates offered by the MCP servers.
+func (h *Handler) handlePrompts(ctx context.Context, reply func(string)) {
+	provider, ok := h.bot.(PromptProvider)
+	if !ok {
+		reply(h.msg(ctx, "prompts.unavailable"))
+		return
+	}
+	prompts := provider.MCPPrompts(ctx)
+	if len(prompts) == 0 {
+		reply(h.msg(ctx, "prompts.none"))
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "prompts.header", len(prompts)))
+	for _, p := range prompts {
+		sb.WriteString(fmt.Sprintf("• `%s/%s`", p.Server, p.Name))
+		if p.Description != "" {
+			sb.WriteString(" " + firstLine(p.Description))
+		}
+		if len(p.Arguments) > 0 {
+			args := make([]string, 0, len(p.Arguments))
+			for _, arg := range p.Arguments {
+				name := "`" + arg.Name + "`"
+				if arg.Required {
+					name += "*"
+				}
+				args = append(args, name)
+			}
+			sb.WriteString(h.msg(ctx, "prompts.args", strings.Join(args, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(h.msg(ctx, "prompts.footer"))
+	reply(sb.String())
+}
+
+// handlePrompt fills in an MCP prompt template and sends the result to the
+// conversation as if the user had typed it. "/prompt set|show|clear"
+// manage the conversation's own system prompt instead.
+func (h *Handler) handlePrompt(ctx context.Context, chatID, text string, reply func(string)) {
+	fields := strings.Fields(text[len("/prompt"):])
+	if len(fields) == 0 {
+		reply(h.msg(ctx, "prompt.usage"))
+		return
+	}
+	switch strings.ToLower(fields[0]) {
+	case "set":
+		rest := strings.TrimSpace(text[len("/prompt"):])
+		h.handlePromptSet(ctx, chatID, strings.TrimSpace(rest[len(fields[0]):]), reply)
+		return
+	case "show":
+		h.handlePromptShow(ctx, chatID, reply)
+		return
+	case "clear":
+		h.handlePromptClear(ctx, chatID, reply)
+		return
+	}
+
+	provider, ok := h.bot.(PromptProvider)
+	if !ok {
+		reply(h.msg(ctx, "prompts.unavailable"))
+		return
+	}
+	server, name, ok := strings.Cut(fields[0], "/")
+	if !ok || server == "" || name == "" {
+		reply(h.msg(ctx, "prompt.usage"))
+		return
+	}
+
+	var prompt *agent.MCPPrompt
+	for _, p := range provider.MCPPrompts(ctx) {
+		if p.Server == server && p.Name == name {
+			prompt = &p
+			break
+		}
+	}
+	if prompt == nil {
+		reply(h.msg(ctx, "prompt.unknown", fields[0]))
+		return
+	}
+	args := promptArgs(*prompt, fields[1:])
+	for _, arg := range prompt.Arguments {
+		if _, ok := args[arg.Name]; arg.Required && !ok {
+			reply(h.msg(ctx, "prompt.missing", arg.Name, fields[0]))
+			return
+		}
+	}
+
+	rendered, err := provider.RenderMCPPrompt(ctx, server, name, args)
+	if err != nil {
+		slog.Error("Failed to render MCP prompt", "server", server, "prompt", name, "error", err)
+		if errors.Is(err, agent.ErrUnknownPrompt) {
+			reply(h.msg(ctx, "prompt.unknown", fields[0]))
+			return
+		}
+		reply(h.msg(ctx, "prompt.failed", fields[0]))
+		return
+	}
+	slog.Info("Running MCP prompt", "server", server, "prompt", name, "sessionID", chatID)
+	h.handleChat(ctx, chatID, rendered, reply)
+}
+
+// handlePromptSet stores instructions the conversation's agent follows on
+// top of the configured system prompt or, with --replace, instead of it,
+// so one channel can run a different persona.
+func (h *Handler) handlePromptSet(ctx context.Context, chatID, args string, reply func(string)) {
+	mode := db.PromptAppend
+	if rest, ok := cutFlag(args, "--replace"); ok {
+		args, mode = rest, db.PromptReplace
+	}
+	if args == "" {
+		reply(h.msg(ctx, "prompt.set_usage"))
+		return
+	}
+	if err := h.db.SetSessionPrompt(ctx, chatID, args, mode); err != nil {
+		slog.Error("Failed to save session prompt", "sessionID", chatID, "error", err)
+		reply(h.msg(ctx, "prompt.save_failed"))
+		return
+	}
+	slog.Info("Session prompt set", "sessionID", chatID, "mode", mode)
+	if mode == db.PromptReplace {
+		reply(h.msg(ctx, "prompt.replaced"))
+		return
+	}
+	reply(h.msg(ctx, "prompt.set"))
+}
+
+func (h *Handler) handlePromptShow(ctx context.Context, chatID string, reply func(string)) {
+	p, err := h.db.GetSessionPrompt(ctx, chatID)
+	if err != nil {
+		slog.Error("Failed to load session prompt", "sessionID", chatID, "error", err)
+		reply(h.msg(ctx, "prompt.load_failed"))
+		return
+	}
+	switch {
+	case p == nil:
+		reply(h.msg(ctx, "prompt.none"))
+	case p.Mode == db.PromptReplace:
+		reply(h.msg(ctx, "prompt.show_replace", p.UpdatedAt.Format("Jan 2"), p.Prompt))
+	default:
+		reply(h.msg(ctx, "prompt.show_append", p.UpdatedAt.Format("Jan 2"), p.Prompt))
+	}
+}
+
+func (h *Handler) handlePromptClear(ctx context.Context, chatID string, reply func(string)) {
+	ok, err := h.db.DeleteSessionPrompt(ctx, chatID)
+	if err != nil {
+		slog.Error("Failed to clear session prompt", "sessionID", chatID, "error", err)
+		reply(h.msg(ctx, "prompt.save_failed"))
+		return
+	}
+	if !ok {
+		reply(h.msg(ctx, "prompt.none"))
+		return
+	}
+	slog.Info("Session prompt cleared", "sessionID", chatID)
+	reply(h.msg(ctx, "prompt.cleared"))
+}
+
+// promptArgs parses "key=value" words, where a value runs until the next
+// key. Words before the first key fill the prompt's only argument, if it
+// has exactly one.
+func promptArgs(p agent.MCPPrompt, words []string) map[string]string {
+	args := make(map[string]string)
+	var free []string
+	key := ""
+	for _, w := range words {
+		if k, v, ok := strings.Cut(w, "="); ok && k != "" {
+			key = k
+			args[key] = v
+			continue
+		}
+		if key == "" {
+			free = append(free, w)
+			continue
+		}
+		args[key] = strings.TrimSpace(args[key] + " " + w)
+	}
+	if len(free) > 0 && len(p.Arguments) == 1 {
+		if _, ok := args[p.Arguments[0].Name]; !ok {
+			args[p.Arguments[0].Name] = strings.Join(free, " ")
+		}
+	}
+	return args
+}
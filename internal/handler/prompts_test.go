@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type promptBot struct {
+	mockBot
+	prompts  []agent.MCPPrompt
+	rendered map[string]string // args of the last render
+}
+
+func (b *promptBot) MCPPrompts(context.Context) []agent.MCPPrompt { return b.prompts }
+
+func (b *promptBot) RenderMCPPrompt(_ context.Context, server, name string, args map[string]string) (string, error) {
+	b.rendered = args
+	return "Rendered " + server + "/" + name, nil
+}
+
+func TestHandleMessage_Prompts(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.bot = &mockBot{}
+	h.HandleMessage(ctx, "test-session", "/prompts", nil, reply)
+	assert.Contains(t, got, "not available")
+
+	var chatted string
+	bot := &promptBot{
+		mockBot: mockBot{chatFunc: func(_ context.Context, _, message string) (string, error) {
+			chatted = message
+			return "Here is the review.", nil
+		}},
+		prompts: []agent.MCPPrompt{
+			{Server: "github", Name: "review-pr", Description: "Review a pull request.\nDetails.", Arguments: []agent.MCPPromptArgument{{Name: "number", Required: true}, {Name: "focus"}}},
+			{Server: "notes", Name: "summarize", Arguments: []agent.MCPPromptArgument{{Name: "topic"}}},
+		},
+	}
+	h.bot = bot
+	h.HandleMessage(ctx, "test-session", "/prompts", nil, reply)
+	assert.Contains(t, got, "**MCP Prompts (2)**")
+	assert.Contains(t, got, "• `github/review-pr` Review a pull request. — `number`*, `focus`\n")
+	assert.NotContains(t, got, "Details")
+
+	h.HandleMessage(ctx, "test-session", "/prompt review-pr", nil, reply)
+	assert.Contains(t, got, "Usage")
+	h.HandleMessage(ctx, "test-session", "/prompt github/merge", nil, reply)
+	assert.Contains(t, got, "No MCP prompt named `github/merge`")
+	h.HandleMessage(ctx, "test-session", "/prompt github/review-pr focus=tests", nil, reply)
+	assert.Contains(t, got, "Missing required argument `number`")
+
+	h.HandleMessage(ctx, "test-session", "/prompt github/review-pr number=42 focus=error handling", nil, reply)
+	assert.Equal(t, "Here is the review.", got)
+	assert.Equal(t, "Rendered github/review-pr", chatted)
+	assert.Equal(t, map[string]string{"number": "42", "focus": "error handling"}, bot.rendered)
+
+	// Free text fills a prompt's only argument.
+	h.HandleMessage(ctx, "test-session", "/prompt notes/summarize rust async traits", nil, reply)
+	assert.Equal(t, map[string]string{"topic": "rust async traits"}, bot.rendered)
+}
+
+func TestHandleMessage_SessionPrompt(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+	h.bot = &mockBot{}
+
+	// The session prompt doesn't need MCP prompts.
+	h.HandleMessage(ctx, "test-session", "/prompt show", nil, reply)
+	assert.Contains(t, got, "system prompt alone")
+	h.HandleMessage(ctx, "test-session", "/prompt set", nil, reply)
+	assert.Contains(t, got, "Usage: `/prompt set")
+
+	h.HandleMessage(ctx, "test-session", "/prompt set Answer like a pirate.\nKeep it short.", nil, reply)
+	assert.Contains(t, got, "on top of the system prompt")
+	p, err := database.GetSessionPrompt(ctx, "test-session")
+	assert.NoError(t, err)
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "Answer like a pirate.\nKeep it short.", p.Prompt)
+		assert.Equal(t, db.PromptAppend, p.Mode)
+	}
+	h.HandleMessage(ctx, "test-session", "/prompt show", nil, reply)
+	assert.Contains(t, got, "added to the system prompt")
+	assert.Contains(t, got, "Answer like a pirate.\nKeep it short.")
+
+	h.HandleMessage(ctx, "test-session", "/prompt SET --replace You are a terse SRE.", nil, reply)
+	assert.Contains(t, got, "instead of the system prompt")
+	p, _ = database.GetSessionPrompt(ctx, "test-session")
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "You are a terse SRE.", p.Prompt)
+		assert.Equal(t, db.PromptReplace, p.Mode)
+	}
+
+	// Other channels keep the configured prompt.
+	other, _ := database.GetSessionPrompt(ctx, "other-session")
+	assert.Nil(t, other)
+
+	h.HandleMessage(ctx, "test-session", "/prompt clear", nil, reply)
+	assert.Contains(t, got, "Prompt cleared")
+	h.HandleMessage(ctx, "test-session", "/prompt clear", nil, reply)
+	assert.Contains(t, got, "system prompt alone")
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter is a per-key token bucket. Each key may spend up to limit
+// tokens at once, and tokens refill continuously at limit per period.
+type rateLimiter struct {
+	limit  int
+	period time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time // last sweep of idle buckets
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a limiter allowing limit events per period per key,
+// or nil if limit is not positive. A nil limiter allows everything.
+func newRateLimiter(limit int, period time.Duration) *rateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		limit:   limit,
+		period:  period,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow consumes a token for key. When the bucket is empty it returns false
+// and how long until the next token is available.
+func (rl *rateLimiter) allow(key string) (bool, time.Duration) {
+	if rl == nil {
+		return true, 0
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+	perToken := rl.period / time.Duration(rl.limit)
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rl.limit), last: now}
+		rl.buckets[key] = b
+	} else {
+		b.tokens += float64(now.Sub(b.last)) / float64(perToken)
+		b.tokens = min(b.tokens, float64(rl.limit))
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) * float64(perToken))
+		return false, max(wait.Round(time.Second), time.Second)
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops the buckets of keys idle for a whole period, at most once a
+// period. They have refilled to limit, so dropping them changes nothing,
+// and the map only holds the keys seen recently instead of every key ever.
+func (rl *rateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.swept) < rl.period {
+		return
+	}
+	rl.swept = now
+	for key, b := range rl.buckets {
+		if now.Sub(b.last) >= rl.period {
+			delete(rl.buckets, key)
+		}
+	}
+}
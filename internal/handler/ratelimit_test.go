@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rl := newRateLimiter(3, time.Minute)
+	rl.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		ok, _ := rl.allow("a")
+		assert.True(t, ok, "burst of %d should be allowed", i+1)
+	}
+	ok, wait := rl.allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, 20*time.Second, wait)
+
+	// Other keys have their own bucket
+	ok, _ = rl.allow("b")
+	assert.True(t, ok)
+
+	// One token refills every 20s
+	now = now.Add(20 * time.Second)
+	ok, _ = rl.allow("a")
+	assert.True(t, ok)
+	ok, _ = rl.allow("a")
+	assert.False(t, ok)
+
+	// Keys idle for a period are dropped, since their buckets are full again.
+	for _, key := range []string{"c", "d", "e"} {
+		rl.allow(key)
+	}
+	now = now.Add(30 * time.Second)
+	rl.allow("a")
+	now = now.Add(40 * time.Second)
+	rl.allow("f")
+	assert.Len(t, rl.buckets, 2, "only the buckets used within the last period are kept")
+	assert.Contains(t, rl.buckets, "a")
+	assert.Contains(t, rl.buckets, "f")
+	for i := 0; i < 3; i++ {
+		ok, _ = rl.allow("c")
+		assert.True(t, ok, "an evicted key starts with a full bucket")
+	}
+
+	// A nil limiter (limit disabled) allows everything
+	var disabled *rateLimiter
+	ok, _ = disabled.allow("a")
+	assert.True(t, ok)
+	assert.Nil(t, newRateLimiter(0, time.Minute))
+}
+
+func TestHandleMessage_RateLimited(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	h.UpdateConfig(&config.Config{
+		Bot:       config.BotConfig{HelpMessage: "help"},
+		RateLimit: config.RateLimitConfig{MessagesPerMinute: 2},
+	})
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/help", nil, reply)
+	h.HandleMessage(ctx, "test-session", "/help", nil, reply)
+	assert.Equal(t, "help", got)
+
+	h.HandleMessage(ctx, "test-session", "/help", nil, reply)
+	assert.Contains(t, got, "Slow down")
+	assert.Contains(t, got, "2 messages per minute")
+	assert.Equal(t, int64(2), h.stats.MessagesProcessed(), "limited messages are not counted")
+
+	h.HandleMessage(ctx, "other-session", "/help", nil, reply)
+	assert.Equal(t, "help", got)
+}
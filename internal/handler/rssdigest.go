@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+)
+
+const (
+	// maxDigestCategories caps the sections of an rss_digest report, each
+	// of which costs a model call; the smallest past it go to "Other".
+	maxDigestCategories = 8
+	otherCategory       = "Other"
+)
+
+// digestCategory is a section of an rss_digest report.
+type digestCategory struct {
+	Name  string
+	Items []tools.RSSItem
+}
+
+// runRSSDigestJob builds a digest from the feed registry itself rather
+// than asking the model to fetch feeds: it refreshes the job's feeds,
+// drops headlines already sent, groups the new items by category and has
+// the model summarize each group. A group the model fails on is listed as
+// plain headlines, so the digest is never empty while there is news.
+//
+// Params: feeds ("subscriptions", the default, or feed URLs separated by
+// spaces or commas), categories ("Name=url url; Name2=url", default one
+// per feed), since (a duration; default since the last successful run),
+// reddit ("true" adds the configured subreddits) and tags.
+func (h *Handler) runRSSDigestJob(ctx context.Context, job config.JobConfig) (string, error) {
+	urls, err := h.digestFeedURLs(ctx, job.Params["feeds"])
+	if err != nil {
+		return "", err
+	}
+	reddit := job.Params["reddit"] == "true" && len(h.config().Reddit.Names()) > 0
+	if len(urls) == 0 && !reddit {
+		return "", fmt.Errorf("no feeds to digest: subscribe to feeds or set params.feeds")
+	}
+	since := h.lastJobSuccess(ctx, job.Name)
+	if v := job.Params["since"]; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return "", fmt.Errorf("invalid since %q: want a duration such as 24h", v)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	var feedItems, redditItems []tools.RSSItem
+	if len(urls) > 0 {
+		feedItems = h.feedItems(ctx, urls, since)
+	}
+	if reddit {
+		redditItems = h.fetchReddit(ctx, h.config(), since)
+	}
+	items, repeats := h.newHeadlines(ctx, tools.MergeFeedItems(feedItems, redditItems))
+	if len(items) == 0 {
+		slog.Info("RSS digest has no new items", "name", job.Name, "since", since)
+		return "", nil
+	}
+	if len(items) > maxDigestItems {
+		items = items[:maxDigestItems]
+	}
+
+	categories := groupDigestItems(items, parseDigestCategories(job.Params["categories"]))
+	now := time.Now().In(h.config().Location())
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# 📰 %s — %s\n\n", job.Name, now.Format("Monday, January 2")))
+	sb.WriteString(fmt.Sprintf("_%d new items since %s_\n", len(items), since.In(now.Location()).Format("Jan 2 15:04")))
+	for _, c := range categories {
+		sb.WriteString(fmt.Sprintf("\n## %s (%d)\n\n", c.Name, len(c.Items)))
+		summary, err := h.bot.RunMission(ctx, categoryDigestPrompt(c))
+		if summary = strings.TrimSpace(summary); err != nil || summary == "" {
+			slog.Warn("RSS digest summary failed, listing headlines", "name", job.Name, "category", c.Name, "error", err)
+			summary = headlineList(c.Items)
+		}
+		sb.WriteString(summary + "\n")
+	}
+
+	path, err := h.publishJobReport(ctx, job, sb.String(), jobTags(job.Name, job.Params["tags"], []string{"digest"}))
+	if err != nil {
+		return "", err
+	}
+	// Repeated stories are recorded too, so they aren't sent next time.
+	for _, it := range append(items, repeats...) {
+		if it.Link == "" {
+			continue
+		}
+		if _, err := h.db.AddHeadline(ctx, it.Link, it.Title); err != nil {
+			slog.Warn("Failed to record headline", "url", it.Link, "error", err)
+		}
+	}
+	return path, nil
+}
+
+// digestFeedURLs returns the feeds an rss_digest job reads: every
+// subscribed feed, or the listed ones, which are added to the registry.
+func (h *Handler) digestFeedURLs(ctx context.Context, param string) ([]string, error) {
+	if param == "" || param == "subscriptions" {
+		urls, err := h.db.GetFeedURLs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load feed subscriptions: %w", err)
+		}
+		return urls, nil
+	}
+	urls := strings.FieldsFunc(param, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' })
+	if err := h.db.RegisterFeeds(ctx, urls...); err != nil {
+		return nil, fmt.Errorf("failed to register feeds: %w", err)
+	}
+	return urls, nil
+}
+
+// newHeadlines drops items sent by an earlier digest and returns stories
+// already listed under the same headline by another feed separately.
+func (h *Handler) newHeadlines(ctx context.Context, items []tools.RSSItem) (fresh, repeats []tools.RSSItem) {
+	titles := make(map[string]bool, len(items))
+	for _, it := range items {
+		if it.Link != "" {
+			seen, err := h.db.HasHeadline(ctx, it.Link)
+			if err != nil {
+				slog.Warn("Failed to check headline", "url", it.Link, "error", err)
+			}
+			if seen {
+				continue
+			}
+		}
+		key := strings.ToLower(strings.Join(strings.Fields(it.Title), " "))
+		if key != "" && titles[key] {
+			repeats = append(repeats, it)
+			continue
+		}
+		titles[key] = true
+		fresh = append(fresh, it)
+	}
+	return fresh, repeats
+}
+
+// parseDigestCategories reads "Name=url url; Name2=url" into a map from
+// feed URL to category.
+func parseDigestCategories(param string) map[string]string {
+	byURL := make(map[string]string)
+	for _, entry := range strings.Split(param, ";") {
+		name, urls, ok := strings.Cut(entry, "=")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			continue
+		}
+		for _, u := range strings.FieldsFunc(urls, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' }) {
+			byURL[u] = name
+		}
+	}
+	return byURL
+}
+
+// groupDigestItems sorts items into categories: the configured one of
+// their feed, or the feed itself. Categories keep the order of their
+// newest item. Past maxDigestCategories, the smallest are merged into
+// "Other".
+func groupDigestItems(items []tools.RSSItem, byURL map[string]string) []digestCategory {
+	var cats []digestCategory
+	index := make(map[string]int)
+	for _, it := range items {
+		name := it.Feed
+		if c, ok := byURL[it.FeedURL]; ok {
+			name = c
+		}
+		if name == "" {
+			name = otherCategory
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(cats)
+			index[name] = i
+			cats = append(cats, digestCategory{Name: name})
+		}
+		cats[i].Items = append(cats[i].Items, it)
+	}
+	if len(cats) <= maxDigestCategories {
+		return cats
+	}
+
+	bySize := make([]int, len(cats))
+	for i := range cats {
+		bySize[i] = i
+	}
+	sort.SliceStable(bySize, func(a, b int) bool { return len(cats[bySize[a]].Items) > len(cats[bySize[b]].Items) })
+	keep := make(map[int]bool, maxDigestCategories-1)
+	for _, i := range bySize[:maxDigestCategories-1] {
+		keep[i] = true
+	}
+	var out []digestCategory
+	other := digestCategory{Name: otherCategory}
+	for i, c := range cats {
+		if keep[i] && c.Name != otherCategory {
+			out = append(out, c)
+		} else {
+			other.Items = append(other.Items, c.Items...)
+		}
+	}
+	return append(out, other)
+}
+
+func categoryDigestPrompt(c digestCategory) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Summarize these new items from the %q feeds as Markdown bullet points, ", c.Name))
+	sb.WriteString("most important first, one or two sentences each, merging items about the same story. ")
+	sb.WriteString("Include every item's link. Reply with the bullets only, without a heading, and do not search for additional items.\n\nITEMS:\n")
+	for i, it := range c.Items {
+		sb.WriteString(fmt.Sprintf("%d. [%s] %s", i+1, it.Feed, it.Title))
+		if it.Link != "" {
+			sb.WriteString(" — " + it.Link)
+		}
+		sb.WriteString("\n")
+		if desc := plainSnippet(it.Description, 300); desc != "" {
+			sb.WriteString("   " + desc + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// headlineList lists items as linked headlines.
+func headlineList(items []tools.RSSItem) string {
+	var sb strings.Builder
+	for _, it := range items {
+		if it.Link != "" {
+			sb.WriteString(fmt.Sprintf("- [%s](%s)\n", it.Title, it.Link))
+		} else {
+			sb.WriteString("- " + it.Title + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
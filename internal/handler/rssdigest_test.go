@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSDigestJob(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+	h.cfg.ReportsDir = t.TempDir()
+
+	var prompts []string
+	h.bot = &mockBot{runMissionFunc: func(_ context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		if strings.Contains(prompt, `"Security"`) {
+			return "", errors.New("model overloaded")
+		}
+		return "- Go 1.26 is out", nil
+	}}
+	h.fetchFeed = func(_ context.Context, url, _, _ string) (tools.FeedResult, error) {
+		switch url {
+		case "https://go.dev/blog/feed.atom":
+			return tools.FeedResult{Title: "Go Blog", Items: []tools.RSSItem{
+				{Title: "Go 1.26 released", Link: "https://go.dev/blog/go1.26", Published: time.Now().Add(-time.Hour)},
+			}}, nil
+		case "https://example.com/golang.xml":
+			return tools.FeedResult{Title: "Golang Weekly", Items: []tools.RSSItem{
+				{Title: "Go 1.26  Released", Link: "https://example.com/go126", Published: time.Now().Add(-2 * time.Hour)},
+			}}, nil
+		default:
+			return tools.FeedResult{Title: "Security News", Items: []tools.RSSItem{
+				{Title: "CVE in libfoo", Link: "https://sec.example.com/cve", Published: time.Now().Add(-3 * time.Hour)},
+			}}, nil
+		}
+	}
+	job := config.JobConfig{Name: "Morning Digest", Type: "rss_digest", Params: map[string]string{
+		"categories": "Go=https://go.dev/blog/feed.atom https://example.com/golang.xml; Security=https://sec.example.com/feed",
+	}}
+
+	_, err := h.execJob(ctx, job)
+	assert.ErrorContains(t, err, "no feeds to digest")
+
+	for _, u := range []string{"https://go.dev/blog/feed.atom", "https://example.com/golang.xml", "https://sec.example.com/feed"} {
+		_, err := database.AddSubscription(ctx, "test-session", u)
+		require.NoError(t, err)
+	}
+
+	path, err := h.execJob(ctx, job)
+	require.NoError(t, err)
+	require.NotEmpty(t, path)
+	report, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "# 📰 Morning Digest")
+	assert.Contains(t, string(report), "_2 new items since")
+	assert.Contains(t, string(report), "## Go (1)\n\n- Go 1.26 is out")
+	assert.Contains(t, string(report), "## Security (1)\n\n- [CVE in libfoo](https://sec.example.com/cve)", "a failed summary lists the headlines")
+	require.Len(t, prompts, 2)
+	assert.Contains(t, prompts[0], "[Go Blog] Go 1.26 released — https://go.dev/blog/go1.26")
+	assert.NotContains(t, prompts[0], "Golang Weekly", "the same headline from another feed is dropped")
+
+	briefings, err := database.GetRecentBriefings(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, briefings, 1)
+	assert.Equal(t, "Morning Digest", briefings[0].Topic)
+	assert.Contains(t, briefings[0].Tags, "digest")
+
+	// Headlines sent by the digest are not sent again.
+	prompts = nil
+	path, err = h.execJob(ctx, job)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+	assert.Empty(t, prompts)
+
+	job.Params["since"] = "soon"
+	_, err = h.execJob(ctx, job)
+	assert.ErrorContains(t, err, "invalid since")
+}
+
+func TestGroupDigestItems(t *testing.T) {
+	t.Parallel()
+	var items []tools.RSSItem
+	for i := range maxDigestCategories + 2 {
+		// Feed i has i+1 items.
+		for j := 0; j <= i; j++ {
+			items = append(items, tools.RSSItem{Title: fmt.Sprintf("%d-%d", i, j), Feed: fmt.Sprintf("Feed %d", i), FeedURL: fmt.Sprintf("https://example.com/%d", i)})
+		}
+	}
+	items = append(items, tools.RSSItem{Title: "untitled feed"})
+
+	cats := groupDigestItems(items, parseDigestCategories("Big = https://example.com/8, https://example.com/9; =https://example.com/0; broken"))
+	require.Len(t, cats, maxDigestCategories)
+	assert.Equal(t, "Feed 2", cats[0].Name)
+	assert.Equal(t, "Big", cats[len(cats)-2].Name)
+	assert.Len(t, cats[len(cats)-2].Items, 19)
+	other := cats[len(cats)-1]
+	assert.Equal(t, otherCategory, other.Name)
+	// Feeds 0 and 1 are the smallest, plus the item without a feed.
+	assert.Len(t, other.Items, 1+2+1)
+}
@@ -71,7 +71,7 @@ func TestErrorLeakage(t *testing.T) {
 
 	t.Run("handleResearch error leakage", func(t *testing.T) {
 		var got string
-		h.handleResearch(context.Background(), "/research topic", func(reply string) {
+		h.handleResearch(context.Background(), "test-session", "/research topic", func(reply string) {
 			if !assert.ObjectsAreEqual(reply, "🔬 Starting research on: **topic**...") {
 				got = reply
 			}
@@ -81,7 +81,7 @@ func TestErrorLeakage(t *testing.T) {
 
 	t.Run("handleJules error leakage", func(t *testing.T) {
 		var got string
-		h.handleJules(context.Background(), "test", "/jules owner/repo task", func(reply string) {
+		h.runJules(context.Background(), "test", "owner/repo", "task", func(reply string) {
 			if !assert.ObjectsAreEqual(reply, "🤖 Delegating to Jules for **owner/repo**: task") {
 				got = reply
 			}
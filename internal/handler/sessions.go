@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// defaultSessionName is the thread a channel uses until it switches. Its
+// session ID is the channel ID itself, so existing history carries over.
+const defaultSessionName = "main"
+
+var sessionName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// sessionIDFor returns the conversation session ID of a channel's thread.
+func sessionIDFor(channelID, name string) string {
+	if name == defaultSessionName {
+		return channelID
+	}
+	return channelID + ":" + name
+}
+
+// activeSession returns the conversation session the channel is using and
+// records activity on it.
+func (h *Handler) activeSession(ctx context.Context, channelID string) string {
+	id, err := h.db.GetActiveSession(ctx, channelID)
+	if err != nil {
+		slog.Warn("Failed to load active session", "channelID", channelID, "error", err)
+	}
+	if id == "" {
+		id = channelID
+	}
+	name := defaultSessionName
+	if id != channelID {
+		name = strings.TrimPrefix(id, channelID+":")
+	}
+	if err := h.db.TouchChatSession(ctx, channelID, id, name); err != nil {
+		slog.Warn("Failed to record session activity", "sessionID", id, "error", err)
+	}
+	return id
+}
+
+func (h *Handler) handleSessions(ctx context.Context, channelID, activeID string, reply func(string)) {
+	sessions, err := h.db.ListChatSessions(ctx, channelID)
+	if err != nil {
+		slog.Error("Failed to list sessions", "channelID", channelID, "error", err)
+		reply(h.msg(ctx, "sessions.failed"))
+		return
+	}
+	loc := h.config().Location()
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "sessions.header", len(sessions)))
+	for _, s := range sessions {
+		marker := ""
+		if s.ID == activeID {
+			marker = " ✅"
+		}
+		sb.WriteString(fmt.Sprintf("• `%s`%s — %s\n", s.Name, marker, h.msg(ctx, "sessions.last_active", s.LastActive.In(loc).Format("Jan 2 15:04"))))
+		if summary := plainSnippet(firstLine(s.Summary), 100); summary != "" {
+			sb.WriteString("  _" + summary + "_\n")
+		}
+	}
+	sb.WriteString(h.msg(ctx, "sessions.footer"))
+	reply(sb.String())
+}
+
+func (h *Handler) handleSession(ctx context.Context, channelID, activeID, text string, reply func(string)) {
+	parts := strings.Fields(text[len("/session"):])
+	if len(parts) == 0 {
+		name := defaultSessionName
+		if activeID != channelID {
+			name = strings.TrimPrefix(activeID, channelID+":")
+		}
+		reply(h.msg(ctx, "session.current", name))
+		return
+	}
+	if len(parts) != 2 {
+		reply(h.msg(ctx, "session.usage"))
+		return
+	}
+	name := strings.ToLower(parts[1])
+
+	switch strings.ToLower(parts[0]) {
+	case "new":
+		if !sessionName.MatchString(name) {
+			reply(h.msg(ctx, "session.invalid_name"))
+			return
+		}
+		existing, err := h.db.GetChatSession(ctx, channelID, name)
+		if err != nil {
+			slog.Error("Failed to look up session", "channelID", channelID, "name", name, "error", err)
+			reply(h.msg(ctx, "sessions.failed"))
+			return
+		}
+		if existing != nil || name == defaultSessionName {
+			reply(h.msg(ctx, "session.exists", name, name))
+			return
+		}
+		id := sessionIDFor(channelID, name)
+		if err := h.db.TouchChatSession(ctx, channelID, id, name); err != nil {
+			slog.Error("Failed to create session", "sessionID", id, "error", err)
+			reply(h.msg(ctx, "sessions.failed"))
+			return
+		}
+		if !h.switchSession(ctx, channelID, id, reply) {
+			return
+		}
+		reply(h.msg(ctx, "session.created", name))
+
+	case "switch":
+		existing, err := h.db.GetChatSession(ctx, channelID, name)
+		if err != nil {
+			slog.Error("Failed to look up session", "channelID", channelID, "name", name, "error", err)
+			reply(h.msg(ctx, "sessions.failed"))
+			return
+		}
+		if existing == nil && name != defaultSessionName {
+			reply(h.msg(ctx, "session.not_found", name))
+			return
+		}
+		if !h.switchSession(ctx, channelID, sessionIDFor(channelID, name), reply) {
+			return
+		}
+		reply(h.msg(ctx, "session.switched", name))
+
+	default:
+		reply(h.msg(ctx, "session.usage"))
+	}
+}
+
+func (h *Handler) switchSession(ctx context.Context, channelID, sessionID string, reply func(string)) bool {
+	if err := h.db.SetActiveSession(ctx, channelID, sessionID); err != nil {
+		slog.Error("Failed to switch session", "channelID", channelID, "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "sessions.failed"))
+		return false
+	}
+	slog.Info("Switched session", "channelID", channelID, "sessionID", sessionID)
+	return true
+}
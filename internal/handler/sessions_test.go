@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessage_Sessions(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var chatSessions []string
+	bot := &clearRecordingBot{mockBot: mockBot{chatFunc: func(_ context.Context, sessionID, _ string) (string, error) {
+		chatSessions = append(chatSessions, sessionID)
+		return "ok", nil
+	}}}
+	h.bot = bot
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "plan the Go upgrade", nil, reply)
+	h.HandleMessage(ctx, "test-session", "/session", nil, reply)
+	assert.Contains(t, got, "`main`")
+
+	h.HandleMessage(ctx, "test-session", "/session new Research", nil, reply)
+	assert.Contains(t, got, "Started session `research`")
+	h.HandleMessage(ctx, "test-session", "compare vector databases", nil, reply)
+
+	h.HandleMessage(ctx, "test-session", "/sessions", nil, reply)
+	assert.Contains(t, got, "**Sessions (2)**")
+	assert.Contains(t, got, "`research` ✅")
+	assert.Contains(t, got, "_compare vector databases_")
+	assert.Contains(t, got, "_plan the Go upgrade_")
+
+	// /reset only clears the active thread.
+	h.HandleMessage(ctx, "test-session", "/reset", nil, reply)
+	h.HandleMessage(ctx, "test-session", "yes", nil, reply)
+	assert.Equal(t, []string{"test-session:research"}, bot.cleared)
+
+	h.HandleMessage(ctx, "test-session", "/session switch main", nil, reply)
+	assert.Contains(t, got, "Switched to session `main`")
+	h.HandleMessage(ctx, "test-session", "back to the upgrade", nil, reply)
+
+	require.Equal(t, []string{"test-session", "test-session:research", "test-session"}, chatSessions)
+
+	h.HandleMessage(ctx, "test-session", "/session switch nope", nil, reply)
+	assert.Contains(t, got, "No session named `nope`")
+	h.HandleMessage(ctx, "test-session", "/session new research", nil, reply)
+	assert.Contains(t, got, "already exists")
+	h.HandleMessage(ctx, "test-session", "/session new bad/name", nil, reply)
+	assert.Contains(t, got, "Session names are")
+	h.HandleMessage(ctx, "test-session", "/session delete research", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	// Other channels don't see these threads.
+	h.HandleMessage(ctx, "other-session", "/sessions", nil, reply)
+	assert.Contains(t, got, "**Sessions (1)**")
+	assert.NotContains(t, got, "research")
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+)
+
+// handleSet lists the runtime tunables, or (for admins) overrides one or
+// resets it to the config.json value. Overrides are stored in the database
+// and read by the agent on the next message, without a restart.
+func (h *Handler) handleSet(ctx context.Context, sessionID, text string, reply func(string)) {
+	args := strings.Fields(text[len("/set"):])
+	if len(args) == 0 {
+		h.listSettings(ctx, reply)
+		return
+	}
+	if len(args) < 2 {
+		reply(h.msg(ctx, "set.usage"))
+		return
+	}
+	setting, ok := config.LookupSetting(args[0])
+	if !ok {
+		reply(h.msg(ctx, "set.unknown", args[0]))
+		return
+	}
+	if !h.config().Bot.IsAdmin(sessionID) {
+		slog.Warn("Rejected /set from non-admin", "sessionID", sessionID)
+		reply(h.msg(ctx, "set.denied"))
+		return
+	}
+
+	value := strings.Join(args[1:], " ")
+	if strings.EqualFold(value, "reset") {
+		if _, err := h.db.DeleteSetting(ctx, setting.Key); err != nil {
+			slog.Error("Failed to reset setting", "key", setting.Key, "error", err)
+			reply(h.msg(ctx, "set.failed"))
+			return
+		}
+		slog.Info("Runtime setting reset", "key", setting.Key, "by", sessionID)
+		reply(h.msg(ctx, "set.reset", setting.Key, setting.Value(h.config().Bot)))
+		return
+	}
+	if err := setting.Validate(value); err != nil {
+		reply(h.msg(ctx, "set.invalid", setting.Key, err))
+		return
+	}
+	if err := h.db.SetSetting(ctx, setting.Key, value); err != nil {
+		slog.Error("Failed to save setting", "key", setting.Key, "error", err)
+		reply(h.msg(ctx, "set.failed"))
+		return
+	}
+	slog.Info("Runtime setting changed", "key", setting.Key, "value", value, "by", sessionID)
+	reply(h.msg(ctx, "set.done", setting.Key, value))
+}
+
+func (h *Handler) listSettings(ctx context.Context, reply func(string)) {
+	values, err := h.db.Settings(ctx)
+	if err != nil {
+		slog.Error("Failed to load settings", "error", err)
+		reply(h.msg(ctx, "set.failed"))
+		return
+	}
+	effective := h.config().Bot.WithSettings(values)
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "set.header"))
+	for _, s := range config.Settings {
+		marker := ""
+		if _, ok := values[s.Key]; ok {
+			marker = h.msg(ctx, "set.overridden")
+		}
+		sb.WriteString(fmt.Sprintf("• `%s` = `%s`%s — %s\n", s.Key, s.Value(effective), marker, h.msg(ctx, "set.desc_"+s.Key)))
+	}
+	sb.WriteString(h.msg(ctx, "set.footer"))
+	reply(sb.String())
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessage_Set(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	cfg := *h.config()
+	cfg.Bot.CompressionThreshold = 0.8
+	h.UpdateConfig(&cfg)
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/set", nil, reply)
+	assert.Contains(t, got, "**Runtime Settings**")
+	assert.Contains(t, got, "• `compressionThreshold` = `0.8` —")
+	assert.Contains(t, got, "• `defaultModel` = `auto` —")
+
+	h.HandleMessage(ctx, "test-session", "/set compressionthreshold 0.6", nil, reply)
+	assert.Contains(t, got, "`compressionThreshold` set to `0.6`")
+	h.HandleMessage(ctx, "test-session", "/set", nil, reply)
+	assert.Contains(t, got, "• `compressionThreshold` = `0.6` (set) —")
+
+	h.HandleMessage(ctx, "test-session", "/set defaultModel gpt", nil, reply)
+	assert.Contains(t, got, "Invalid value for `defaultModel`")
+	h.HandleMessage(ctx, "test-session", "/set temperature 1", nil, reply)
+	assert.Contains(t, got, "Unknown setting `temperature`")
+	h.HandleMessage(ctx, "test-session", "/set defaultModel", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	h.HandleMessage(ctx, "test-session", "/set compressionThreshold reset", nil, reply)
+	assert.Contains(t, got, "reset to the config.json value (`0.8`)")
+	values, err := database.Settings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	cfg.Bot.Admins = []string{"admin-session"}
+	h.UpdateConfig(&cfg)
+	h.HandleMessage(ctx, "test-session", "/set maxConcurrentMissions 2", nil, reply)
+	assert.Contains(t, got, "Only admins")
+	h.HandleMessage(ctx, "test-session", "/set", nil, reply)
+	assert.Contains(t, got, "• `maxConcurrentMissions` = `0` —", "anyone can list settings")
+}
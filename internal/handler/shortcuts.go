@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// expandShortcut rewrites a message whose first word is a configured
+// shortcut. "{{args}}" is replaced with the rest of the message (or the rest
+// is appended when the template has no placeholder) and "{{date}}" with
+// today's date. Expansion happens once, so shortcuts cannot recurse.
+func (h *Handler) expandShortcut(text string, now time.Time) (string, bool) {
+	shortcuts := h.config().Shortcuts
+	if len(shortcuts) == 0 || !strings.HasPrefix(text, "/") {
+		return text, false
+	}
+
+	name, args, _ := strings.Cut(text, " ")
+	args = strings.TrimSpace(args)
+	var template string
+	for k, v := range shortcuts {
+		if strings.EqualFold(k, name) {
+			template = v
+			break
+		}
+	}
+	if template == "" {
+		return text, false
+	}
+
+	expanded := strings.ReplaceAll(template, "{{date}}", now.Format("2006-01-02"))
+	if strings.Contains(expanded, "{{args}}") {
+		expanded = strings.ReplaceAll(expanded, "{{args}}", args)
+	} else if args != "" {
+		expanded += " " + args
+	}
+	return strings.TrimSpace(expanded), true
+}
+
+// shortcutHelp lists the configured shortcuts for /help.
+func (h *Handler) shortcutHelp(ctx context.Context) string {
+	shortcuts := h.config().Shortcuts
+	if len(shortcuts) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(shortcuts))
+	for name := range shortcuts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "shortcuts.header"))
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("\n• `%s` → %s", name, firstLine(shortcuts[name])))
+	}
+	return sb.String()
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandShortcut(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+
+	cfg := *h.config()
+	cfg.Shortcuts = map[string]string{
+		"/r":       "/research",
+		"/standup": "Write my standup for {{date}} from my open todos.",
+		"/compare": "Compare {{args}} and list the trade-offs.",
+	}
+	h.UpdateConfig(&cfg)
+	now := time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"/r ai news", "/research ai news", true},
+		{"/R ai news", "/research ai news", true},
+		{"/standup", "Write my standup for 2026-03-05 from my open todos.", true},
+		{"/standup focus on infra", "Write my standup for 2026-03-05 from my open todos. focus on infra", true},
+		{"/compare Go vs Rust", "Compare Go vs Rust and list the trade-offs.", true},
+		{"/rr ai news", "/rr ai news", false},
+		{"hello /r", "hello /r", false},
+	}
+	for _, tt := range tests {
+		got, ok := h.expandShortcut(tt.in, now)
+		assert.Equal(t, tt.ok, ok, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestHandleMessage_Shortcut(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+
+	cfg := *h.config()
+	cfg.Shortcuts = map[string]string{
+		"/standup": "Summarize my open todos as a standup.",
+		"/h":       "/help",
+	}
+	h.UpdateConfig(&cfg)
+
+	var gotMessage string
+	h.bot = &mockBot{chatFunc: func(_ context.Context, _, message string) (string, error) {
+		gotMessage = message
+		return "standup", nil
+	}}
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(context.Background(), "test-session", "/standup", nil, reply)
+	assert.Equal(t, "Summarize my open todos as a standup.", gotMessage)
+	assert.Equal(t, "standup", got)
+
+	h.HandleMessage(context.Background(), "test-session", "/h", nil, reply)
+	assert.Contains(t, got, "test help message")
+	assert.Contains(t, got, "`/standup` → Summarize my open todos as a standup.")
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+type healthBot struct {
+	mockBot
+	health []agent.MCPHealth
+}
+
+func (b *healthBot) MCPHealth() []agent.MCPHealth { return b.health }
+
+func TestHandleStatus_MCPHealth(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+
+	since := time.Date(2026, 3, 4, 9, 30, 0, 0, h.config().Location())
+	bot := &healthBot{
+		mockBot: mockBot{chatFunc: func(context.Context, string, string) (string, error) {
+			return "CPU 12%", nil
+		}},
+		health: []agent.MCPHealth{
+			{Server: "filesystem", Healthy: true, Since: since, Restarts: 2, LastCrash: "signal: killed", CrashedAt: since},
+			{Server: "github", Err: "connection refused", Since: since},
+			{Server: "memory", Err: "failed to start"},
+			{Server: "time", Healthy: true, Since: since, Protocol: "2025-03-26"},
+			{Server: "weather"},
+		},
+	}
+	h.bot = bot
+	h.stats.RecordMCPCall("github", "search", 3*time.Second, true)
+	h.stats.RecordMCPCall("github", "search", time.Second, false)
+
+	var got string
+	h.handleStatus(context.Background(), "test", func(r string) { got = r })
+	assert.Contains(t, got, "CPU 12%")
+	assert.Contains(t, got, "✅ `filesystem` — up since Mar 4 09:30 (restarted 2×)\n    💥 crashed Mar 4 09:30: signal: killed")
+	assert.Contains(t, got, "❌ `github` — down since Mar 4 09:30: connection refused\n    📈 2 calls, avg 2s, 50% failed")
+	assert.Contains(t, got, "❌ `memory` — failed to start")
+	assert.Contains(t, got, "⏳ `weather` — starting")
+	assert.Contains(t, got, "✅ `time` — up since Mar 4 09:30 (MCP 2025-03-26, older features only)")
+
+	// The MCP section is still shown when the status prompt fails.
+	bot.chatFunc = func(context.Context, string, string) (string, error) { return "", errors.New("model down") }
+	h.handleStatus(context.Background(), "test", func(r string) { got = r })
+	assert.Contains(t, got, "Status check failed")
+	assert.Contains(t, got, "`github`")
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/db"
+)
+
+// maxSummaryHistory is how many versions /summary history lists.
+const maxSummaryHistory = 10
+
+// handleSummary shows the active thread's summary, its history, or rolls
+// it back to an earlier version.
+func (h *Handler) handleSummary(ctx context.Context, sessionID, chatID, text string, reply func(string)) {
+	parts := strings.Fields(text[len("/summary"):])
+	switch {
+	case len(parts) == 0:
+		summary, err := h.db.GetSessionSummary(ctx, chatID)
+		if err != nil {
+			slog.Error("Failed to load session summary", "sessionID", chatID, "error", err)
+			reply(h.msg(ctx, "summary.failed"))
+			return
+		}
+		if summary == "" {
+			reply(h.msg(ctx, "summary.none"))
+			return
+		}
+		reply(h.msg(ctx, "summary.current", summary))
+
+	case len(parts) == 1 && strings.EqualFold(parts[0], "history"):
+		h.handleSummaryHistory(ctx, chatID, reply)
+
+	case len(parts) == 2 && strings.EqualFold(parts[0], "rollback"):
+		id, err := strconv.ParseInt(strings.TrimPrefix(parts[1], "#"), 10, 64)
+		if err != nil {
+			reply(h.msg(ctx, "summary.usage"))
+			return
+		}
+		h.confirm(ctx, sessionID, h.msg(ctx, "summary.rollback_confirm", id), func(ctx context.Context, reply func(string)) {
+			ok, err := h.db.RollbackSessionSummary(ctx, chatID, id)
+			switch {
+			case err != nil:
+				slog.Error("Failed to roll back session summary", "sessionID", chatID, "version", id, "error", err)
+				reply(h.msg(ctx, "summary.failed"))
+			case !ok:
+				reply(h.msg(ctx, "summary.not_found", id))
+			default:
+				reply(h.msg(ctx, "summary.rolled_back", id))
+			}
+		}, reply)
+
+	default:
+		reply(h.msg(ctx, "summary.usage"))
+	}
+}
+
+func (h *Handler) handleSummaryHistory(ctx context.Context, chatID string, reply func(string)) {
+	versions, err := h.db.ListSummaryVersions(ctx, chatID, maxSummaryHistory)
+	if err != nil {
+		slog.Error("Failed to list summary versions", "sessionID", chatID, "error", err)
+		reply(h.msg(ctx, "summary.failed"))
+		return
+	}
+	if len(versions) == 0 {
+		reply(h.msg(ctx, "summary.none"))
+		return
+	}
+	loc := h.config().Location()
+
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "summary.history_header", len(versions)))
+	for i, v := range versions {
+		source := h.msg(ctx, "summary.source_compression")
+		switch v.Source {
+		case db.SummaryRollback:
+			source = h.msg(ctx, "summary.source_rollback")
+		case db.SummaryImported:
+			source = h.msg(ctx, "summary.source_import")
+		}
+		marker := ""
+		if i == 0 {
+			marker = " ✅"
+		}
+		sb.WriteString(fmt.Sprintf("• `#%d`%s %s (%s)\n", v.ID, marker, v.CreatedAt.In(loc).Format("Jan 2 15:04"), source))
+		sb.WriteString("  _" + plainSnippet(firstLine(v.Summary), 120) + "_\n")
+	}
+	sb.WriteString(h.msg(ctx, "summary.history_footer"))
+	reply(sb.String())
+}
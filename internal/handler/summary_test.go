@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessage_Summary(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/summary", nil, reply)
+	assert.Contains(t, got, "No summary yet")
+
+	require.NoError(t, database.SaveSessionSummary(ctx, "test-session", "Migrating the homelab to k3s"))
+	require.NoError(t, database.SaveSessionSummary(ctx, "test-session", "asdf corrupted"))
+	versions, err := database.ListSummaryVersions(ctx, "test-session", 0)
+	require.NoError(t, err)
+	good := versions[1].ID
+
+	h.HandleMessage(ctx, "test-session", "/summary", nil, reply)
+	assert.Contains(t, got, "asdf corrupted")
+
+	h.HandleMessage(ctx, "test-session", "/summary history", nil, reply)
+	assert.Contains(t, got, "**Summary History (2)**")
+	assert.Contains(t, got, fmt.Sprintf("`#%d`", good))
+	assert.Contains(t, got, "_Migrating the homelab to k3s_")
+
+	h.HandleMessage(ctx, "test-session", fmt.Sprintf("/summary rollback #%d", good), nil, reply)
+	assert.Contains(t, got, fmt.Sprintf("version #%d", good))
+	h.HandleMessage(ctx, "test-session", "yes", nil, reply)
+	assert.Contains(t, got, "Restored summary version")
+
+	summary, err := database.GetSessionSummary(ctx, "test-session")
+	require.NoError(t, err)
+	assert.Equal(t, "Migrating the homelab to k3s", summary)
+
+	h.HandleMessage(ctx, "test-session", "/summary rollback 9999", nil, reply)
+	h.HandleMessage(ctx, "test-session", "yes", nil, reply)
+	assert.Contains(t, got, "No summary version #9999")
+
+	h.HandleMessage(ctx, "test-session", "/summary rollback latest", nil, reply)
+	assert.Contains(t, got, "Usage")
+}
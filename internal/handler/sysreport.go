@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/i18n"
+)
+
+// Default system_report thresholds, in percent used.
+const (
+	defaultDiskThreshold   = 90
+	defaultMemoryThreshold = 90
+)
+
+const systemReportPrompt = `Use the SystemManager's system metrics tools to measure this host right now: the usage of every mounted filesystem, memory, swap and the load average.
+Do not explain or summarize. Reply with only a JSON object of this form, using null for anything the tools could not measure:
+{"disks": [{"mount": "/", "used_percent": 42.5}], "memory_used_percent": 61.2, "swap_used_percent": 0, "load1": 0.52, "cpus": 4}`
+
+// systemSnapshot is the metrics the SystemManager reports for a
+// system_report job.
+type systemSnapshot struct {
+	Disks []struct {
+		Mount       string   `json:"mount"`
+		UsedPercent *float64 `json:"used_percent"`
+	} `json:"disks"`
+	MemoryUsedPercent *float64 `json:"memory_used_percent"`
+	SwapUsedPercent   *float64 `json:"swap_used_percent"`
+	Load1             *float64 `json:"load1"`
+	CPUs              int      `json:"cpus"`
+}
+
+// systemThresholds are the limits of a system_report job; zero disables
+// a check.
+type systemThresholds struct {
+	Disk, Memory, Swap, Load float64
+}
+
+// systemAlert is a threshold a snapshot crosses.
+type systemAlert struct {
+	Key  string // identifies the check across runs, e.g. "disk:/"
+	Text string
+}
+
+// runSystemReportJob has the SystemManager measure the host and alerts
+// the notifiers only when a threshold is newly crossed, and once more
+// when everything is back within limits, instead of sending a report on
+// every run.
+//
+// Params: disk and memory (percent used; default 90), swap (percent) and
+// load (1-minute load per CPU), which are off unless set. "0" turns a
+// check off.
+func (h *Handler) runSystemReportJob(ctx context.Context, job config.JobConfig) error {
+	limits, err := parseSystemThresholds(job.Params)
+	if err != nil {
+		return err
+	}
+	reply, err := h.bot.RunMission(ctx, systemReportPrompt)
+	if err != nil {
+		return fmt.Errorf("failed to read system metrics: %w", err)
+	}
+	snap, err := parseSystemSnapshot(reply)
+	if err != nil {
+		return err
+	}
+
+	loc := h.defaultLocale()
+	alerts := systemAlerts(loc, snap, limits)
+	active := make(map[string]bool, len(alerts))
+	for _, a := range alerts {
+		active[a.Key] = true
+	}
+
+	h.mu.Lock()
+	prev := h.systemAlerts[job.Name]
+	h.systemAlerts[job.Name] = active
+	h.mu.Unlock()
+
+	crossed := false
+	for key := range active {
+		if !prev[key] {
+			crossed = true
+		}
+	}
+	switch {
+	case crossed:
+		lines := make([]string, len(alerts))
+		for i, a := range alerts {
+			lines[i] = a.Text
+		}
+		slog.Warn("System thresholds crossed", "job", job.Name, "alerts", len(alerts))
+		h.broadcast(ctx, job.Name, i18n.T(loc, "sysreport.alert", job.Name, strings.Join(lines, "\n")))
+	case len(active) == 0 && len(prev) > 0:
+		slog.Info("System metrics back within thresholds", "job", job.Name)
+		h.broadcast(ctx, job.Name, i18n.T(loc, "sysreport.resolved", job.Name))
+	default:
+		slog.Info("System report checked", "job", job.Name, "alerts", len(alerts))
+	}
+	return nil
+}
+
+func parseSystemThresholds(params map[string]string) (systemThresholds, error) {
+	t := systemThresholds{Disk: defaultDiskThreshold, Memory: defaultMemoryThreshold}
+	for name, dst := range map[string]*float64{"disk": &t.Disk, "memory": &t.Memory, "swap": &t.Swap, "load": &t.Load} {
+		v := strings.TrimSuffix(strings.TrimSpace(params[name]), "%")
+		if v == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return t, fmt.Errorf("invalid %s threshold %q", name, params[name])
+		}
+		*dst = f
+	}
+	return t, nil
+}
+
+// parseSystemSnapshot reads the JSON object in the model's reply, which
+// may be wrapped in a code fence or prose.
+func parseSystemSnapshot(reply string) (systemSnapshot, error) {
+	var snap systemSnapshot
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return snap, fmt.Errorf("system metrics reply has no JSON object: %s", truncateRunes(reply, 200))
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &snap); err != nil {
+		return snap, fmt.Errorf("failed to parse system metrics: %w", err)
+	}
+	if len(snap.Disks) == 0 && snap.MemoryUsedPercent == nil && snap.SwapUsedPercent == nil && snap.Load1 == nil {
+		return snap, fmt.Errorf("system metrics reply has no measurements")
+	}
+	return snap, nil
+}
+
+// systemAlerts lists the thresholds snap crosses. Metrics the tools could
+// not measure are skipped.
+func systemAlerts(locale string, snap systemSnapshot, t systemThresholds) []systemAlert {
+	var alerts []systemAlert
+	if t.Disk > 0 {
+		for _, d := range snap.Disks {
+			if d.UsedPercent != nil && *d.UsedPercent >= t.Disk {
+				alerts = append(alerts, systemAlert{"disk:" + d.Mount, i18n.T(locale, "sysreport.disk", d.Mount, *d.UsedPercent, t.Disk)})
+			}
+		}
+	}
+	if v := snap.MemoryUsedPercent; t.Memory > 0 && v != nil && *v >= t.Memory {
+		alerts = append(alerts, systemAlert{"memory", i18n.T(locale, "sysreport.memory", *v, t.Memory)})
+	}
+	if v := snap.SwapUsedPercent; t.Swap > 0 && v != nil && *v >= t.Swap {
+		alerts = append(alerts, systemAlert{"swap", i18n.T(locale, "sysreport.swap", *v, t.Swap)})
+	}
+	if v := snap.Load1; t.Load > 0 && v != nil && snap.CPUs > 0 {
+		if perCPU := *v / float64(snap.CPUs); perCPU >= t.Load {
+			alerts = append(alerts, systemAlert{"load", i18n.T(locale, "sysreport.load", *v, snap.CPUs, t.Load)})
+		}
+	}
+	return alerts
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemReportJob(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	reply := `{"disks": [{"mount": "/", "used_percent": 40}], "memory_used_percent": 50, "swap_used_percent": null, "load1": 0.5, "cpus": 4}`
+	var prompt string
+	h.bot = &mockBot{runMissionFunc: func(_ context.Context, p string) (string, error) {
+		prompt = p
+		return reply, nil
+	}}
+	fn := &fileNotifier{}
+	h.SetNotifiers([]notifier.Notifier{fn})
+	job := config.JobConfig{Name: "Host Watch", Type: "system_report", Params: map[string]string{"load": "2"}}
+
+	// Healthy: nothing is sent.
+	_, err := h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "SystemManager")
+	assert.Empty(t, fn.sent)
+
+	// The disk fills up: one alert, not repeated while it stays full.
+	reply = "Here you go:\n```json\n" + `{"disks": [{"mount": "/", "used_percent": 93.4}, {"mount": "/boot", "used_percent": 20}], "memory_used_percent": 50, "load1": 9, "cpus": 4}` + "\n```"
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	require.Len(t, fn.sent, 1)
+	assert.Contains(t, fn.sent[0], "**Host Watch**: system thresholds crossed")
+	assert.Contains(t, fn.sent[0], "Disk `/` is 93.4% full (threshold 90%)")
+	assert.Contains(t, fn.sent[0], "Load is 9.00 on 4 CPU(s)")
+	assert.NotContains(t, fn.sent[0], "/boot")
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	assert.Len(t, fn.sent, 1)
+
+	// Memory pressure is a new crossing.
+	reply = `{"disks": [{"mount": "/", "used_percent": 93.4}], "memory_used_percent": 97}`
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	require.Len(t, fn.sent, 2)
+	assert.Contains(t, fn.sent[1], "Memory is 97.0% used")
+
+	// All clear is announced once.
+	reply = `{"disks": [{"mount": "/", "used_percent": 60}], "memory_used_percent": 50}`
+	for range 2 {
+		_, err = h.runJob(ctx, job, db.TriggerManual)
+		require.NoError(t, err)
+	}
+	require.Len(t, fn.sent, 3)
+	assert.Contains(t, fn.sent[2], "back within thresholds")
+
+	reply = "I couldn't reach the metrics tools."
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	assert.ErrorContains(t, err, "no JSON object")
+
+	job.Params["disk"] = "lots"
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	assert.ErrorContains(t, err, "invalid disk threshold")
+}
+
+func TestParseSystemThresholds(t *testing.T) {
+	t.Parallel()
+	limits, err := parseSystemThresholds(nil)
+	require.NoError(t, err)
+	assert.Equal(t, systemThresholds{Disk: 90, Memory: 90}, limits)
+
+	limits, err = parseSystemThresholds(map[string]string{"disk": "80%", "memory": "0", "swap": "50"})
+	require.NoError(t, err)
+	assert.Equal(t, systemThresholds{Disk: 80, Swap: 50}, limits)
+
+	_, err = parseSystemThresholds(map[string]string{"load": "-1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/raythurman2386/ravenbot/internal/db"
+)
+
+// tagInstruction asks the model to finish a report with topic tags, which
+// splitReportTags removes before the report is shown or stored.
+const tagInstruction = "\n\nEnd the report with a final line of the form \"Tags: tag1, tag2\" listing 1-5 short lowercase topic tags."
+
+// maxReportTags caps how many model-extracted tags a briefing keeps.
+const maxReportTags = 5
+
+var tagLine = regexp.MustCompile(`(?i)^[*_\s]*tags[*_\s]*:[*_\s]*(.+?)[*_\s]*$`)
+
+// splitReportTags removes a trailing "Tags: a, b" line from a report and
+// returns the report and the normalized tags.
+func splitReportTags(report string) (string, []string) {
+	trimmed := strings.TrimRight(report, " \t\r\n")
+	last := trimmed[strings.LastIndex(trimmed, "\n")+1:]
+	m := tagLine.FindStringSubmatch(last)
+	if m == nil {
+		return report, nil
+	}
+	tags := db.NormalizeTags(strings.Split(m[1], ","))
+	if len(tags) > maxReportTags {
+		tags = tags[:maxReportTags]
+	}
+	return strings.TrimRight(strings.TrimSuffix(trimmed, last), " \t\r\n"), tags
+}
+
+// jobTags returns the tags of a scheduled job's briefing: the job name, any
+// params.tags (comma-separated), then those the model extracted.
+func jobTags(jobName, params string, extracted []string) []string {
+	tags := []string{jobName}
+	if params != "" {
+		tags = append(tags, strings.Split(params, ",")...)
+	}
+	return db.NormalizeTags(append(tags, extracted...))
+}
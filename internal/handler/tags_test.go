@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitReportTags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, in, report string
+		tags             []string
+	}{
+		{"no tags", "# Report\n\nBody", "# Report\n\nBody", nil},
+		{"plain", "# Report\n\nBody\n\nTags: Go, security, supply chain\n", "# Report\n\nBody", []string{"go", "security", "supply-chain"}},
+		{"bold", "Body\n**Tags:** ai, llm", "Body", []string{"ai", "llm"}},
+		{"capped", "Body\nTags: a, b, c, d, e, f", "Body", []string{"a", "b", "c", "d", "e"}},
+		{"not last line", "Tags: go\n\nBody", "Tags: go\n\nBody", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, tags := splitReportTags(tt.in)
+			assert.Equal(t, tt.report, report)
+			assert.Equal(t, tt.tags, tags)
+		})
+	}
+}
+
+func TestHandleMessage_ResearchTags(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var prompt string
+	h.bot = &mockBot{runMissionFunc: func(_ context.Context, p string) (string, error) {
+		prompt = p
+		return "# Rust in 2026\n\nAsync traits landed.\n\nTags: rust, async", nil
+	}}
+
+	var replies []string
+	h.HandleMessage(ctx, "test-session", "/research rust async", nil, func(r string) { replies = append(replies, r) })
+	assert.Contains(t, prompt, "Tags: tag1, tag2")
+	assert.Equal(t, "# Rust in 2026\n\nAsync traits landed.", replies[len(replies)-1])
+
+	briefings, err := database.GetBriefings(ctx, db.BriefingFilter{Tag: "async"})
+	require.NoError(t, err)
+	require.Len(t, briefings, 1)
+	assert.Equal(t, "rust async", briefings[0].Topic)
+	assert.Equal(t, []string{"rust", "async"}, briefings[0].Tags)
+}
+
+func TestJobTags(t *testing.T) {
+	t.Parallel()
+	job := config.JobConfig{Name: "Security Watch", Params: map[string]string{"tags": "security, CVE"}}
+	assert.Equal(t, []string{"security-watch", "security", "cve", "linux"}, jobTags(job.Name, job.Params["tags"], []string{"linux", "security"}))
+}
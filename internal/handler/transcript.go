@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+)
+
+const (
+	defaultTranscriptTurns = 10
+	maxTranscriptTurns     = 100
+)
+
+// recordTurn appends a completed chat exchange to the session transcript.
+// Failures are logged but never interrupt the reply.
+func (h *Handler) recordTurn(ctx context.Context, sessionID, message, response string) {
+	if err := h.db.AddTranscriptMessage(ctx, sessionID, db.RoleUser, message); err != nil {
+		slog.Warn("Failed to record transcript", "sessionID", sessionID, "error", err)
+		return
+	}
+	if err := h.db.AddTranscriptMessage(ctx, sessionID, db.RoleAssistant, response); err != nil {
+		slog.Warn("Failed to record transcript", "sessionID", sessionID, "error", err)
+	}
+}
+
+// handleTranscript sends the last n chat turns of the session as a Markdown
+// file, or inline when the channel cannot receive files.
+func (h *Handler) handleTranscript(ctx context.Context, sessionID, text string, n notifier.Notifier, reply func(string)) {
+	turns := defaultTranscriptTurns
+	if arg := strings.TrimSpace(text[len("/transcript"):]); arg != "" {
+		v, err := strconv.Atoi(arg)
+		if err != nil || v <= 0 {
+			reply(h.msg(ctx, "transcript.usage", defaultTranscriptTurns, maxTranscriptTurns))
+			return
+		}
+		turns = min(v, maxTranscriptTurns)
+	}
+
+	msgs, err := h.db.GetTranscript(ctx, sessionID, 2*turns)
+	if err != nil {
+		slog.Error("Failed to get transcript", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "transcript.failed"))
+		return
+	}
+	// Don't start the transcript with a dangling reply.
+	if len(msgs) > 0 && msgs[0].Role == db.RoleAssistant {
+		msgs = msgs[1:]
+	}
+	if len(msgs) == 0 {
+		reply(h.msg(ctx, "transcript.none"))
+		return
+	}
+
+	now := time.Now().In(h.config().Location())
+	md := transcriptMarkdown(sessionID, msgs, now.Location(), h.msg(ctx, "transcript.you"))
+
+	sender, ok := n.(notifier.FileSender)
+	if !ok {
+		reply(md)
+		return
+	}
+	name := fmt.Sprintf("ravenbot_transcript_%s.md", now.Format("2006-01-02_1504"))
+	caption := h.msg(ctx, "transcript.caption", len(msgs))
+	if err := sender.SendFile(ctx, name, "text/markdown; charset=utf-8", []byte(md), caption); err != nil {
+		slog.Error("Failed to send transcript file", "notifier", n.Name(), "error", err)
+		reply(h.msg(ctx, "transcript.send_fail"))
+	}
+}
+
+// transcriptMarkdown renders the messages, labelling the user's turns with
+// you in the session's language.
+func transcriptMarkdown(sessionID string, msgs []db.TranscriptMessage, loc *time.Location, you string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# ravenbot Transcript (%s)\n\n", sessionID))
+	for _, m := range msgs {
+		speaker := you
+		if m.Role == db.RoleAssistant {
+			speaker = "ravenbot"
+		}
+		sb.WriteString(fmt.Sprintf("**%s** · %s\n\n", speaker, m.CreatedAt.In(loc).Format("Jan 2 15:04")))
+		sb.WriteString(strings.TrimSpace(m.Content))
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
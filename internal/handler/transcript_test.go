@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleMessage_Transcript(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	h.bot = &mockBot{chatFunc: func(_ context.Context, _, message string) (string, error) {
+		return "echo: " + message, nil
+	}}
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/transcript", nil, reply)
+	assert.Contains(t, got, "No conversation recorded")
+
+	h.HandleMessage(ctx, "test-session", "first question", nil, reply)
+	h.HandleMessage(ctx, "test-session", "second question", nil, reply)
+	h.HandleMessage(ctx, "other-session", "unrelated", nil, reply)
+
+	fn := &fileNotifier{}
+	h.HandleMessage(ctx, "test-session", "/transcript", fn, reply)
+	assert.True(t, strings.HasSuffix(fn.name, ".md"))
+	md := string(fn.data)
+	assert.Contains(t, md, "**You**")
+	assert.Contains(t, md, "echo: first question")
+	assert.Contains(t, md, "second question")
+	assert.NotContains(t, md, "unrelated")
+	assert.NotContains(t, md, "/transcript")
+
+	h.HandleMessage(ctx, "test-session", "/transcript 1", nil, reply)
+	assert.Contains(t, got, "echo: second question")
+	assert.NotContains(t, got, "first question")
+
+	h.HandleMessage(ctx, "test-session", "/transcript lots", nil, reply)
+	assert.Contains(t, got, "Usage")
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/i18n"
+)
+
+const (
+	// defaultUsageReportDays is the period of a usage report unless
+	// params.days is set.
+	defaultUsageReportDays = 7
+	// maxUsageReportTools is how many of the busiest tools a report lists.
+	maxUsageReportTools = 5
+)
+
+// FlushUsage adds the activity counted since the last flush to today's
+// usage rollup. It runs on the usage jobs and at shutdown, so only counters
+// since the last flush are lost if the process dies.
+func (h *Handler) FlushUsage(ctx context.Context) error {
+	h.usageMu.Lock()
+	defer h.usageMu.Unlock()
+
+	now := h.stats.Totals()
+	delta := now.Sub(h.usageMark)
+	if delta.Messages == 0 && delta.Missions == 0 && delta.InputTokens == 0 && delta.OutputTokens == 0 && len(delta.Tools) == 0 {
+		return nil
+	}
+	day := db.UsageDay{
+		Day:          time.Now().In(h.config().Location()).Format(db.UsageDayFormat),
+		Messages:     delta.Messages,
+		Missions:     delta.Missions,
+		InputTokens:  delta.InputTokens,
+		OutputTokens: delta.OutputTokens,
+		CostUSD:      delta.Cost(h.config().Bot.EstimateCost),
+		Tools:        delta.Tools,
+	}
+	if err := h.db.AddUsage(ctx, day); err != nil {
+		return err
+	}
+	h.usageMark = now
+	return nil
+}
+
+// runUsageRollupJob persists the day's usage counters.
+func (h *Handler) runUsageRollupJob(ctx context.Context, job config.JobConfig) error {
+	if err := h.FlushUsage(ctx); err != nil {
+		slog.Error("Usage rollup failed", "job", job.Name, "error", err)
+		return err
+	}
+	return nil
+}
+
+// runUsageReportJob broadcasts the usage trend over params.days.
+func (h *Handler) runUsageReportJob(ctx context.Context, job config.JobConfig) error {
+	days := defaultUsageReportDays
+	if v, err := strconv.Atoi(job.Params["days"]); err == nil && v > 0 {
+		days = v
+	}
+	report, err := h.usageReport(ctx, h.defaultLocale(), days)
+	if err != nil {
+		slog.Error("Usage report failed", "job", job.Name, "error", err)
+		return err
+	}
+	h.broadcast(ctx, job.Name, report)
+	return nil
+}
+
+func (h *Handler) handleUsage(ctx context.Context, chatID, text string, reply func(string)) {
+	switch arg := strings.ToLower(strings.TrimSpace(text[len("/usage"):])); arg {
+	case "":
+		reply(h.stats.SessionSummary(chatID, h.config().Bot.EstimateCost))
+	case "week", "month":
+		days := defaultUsageReportDays
+		if arg == "month" {
+			days = 30
+		}
+		report, err := h.usageReport(ctx, i18n.FromContext(ctx), days)
+		if err != nil {
+			slog.Error("Failed to build usage report", "error", err)
+			reply(h.msg(ctx, "usage.failed"))
+			return
+		}
+		reply(report)
+	default:
+		reply(h.msg(ctx, "usage.usage"))
+	}
+}
+
+// usageReport flushes pending counters and summarizes the last days days,
+// comparing each total with the period before.
+func (h *Handler) usageReport(ctx context.Context, locale string, days int) (string, error) {
+	if err := h.FlushUsage(ctx); err != nil {
+		return "", err
+	}
+	today := time.Now().In(h.config().Location())
+	start := today.AddDate(0, 0, -(days - 1))
+	prevStart := start.AddDate(0, 0, -days)
+	rows, err := h.db.GetUsage(ctx, prevStart.Format(db.UsageDayFormat), today.Format(db.UsageDayFormat))
+	if err != nil {
+		return "", err
+	}
+
+	cutoff := start.Format(db.UsageDayFormat)
+	var cur, prev db.UsageDay
+	var daily []db.UsageDay
+	for _, r := range rows {
+		if r.Day < cutoff {
+			addUsage(&prev, r)
+			continue
+		}
+		addUsage(&cur, r)
+		daily = append(daily, r)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T(locale, "usage.report_header", start.Format("Jan 2"), today.Format("Jan 2")))
+	if len(daily) == 0 {
+		sb.WriteString(i18n.T(locale, "usage.report_empty"))
+		return sb.String(), nil
+	}
+	sb.WriteString(i18n.T(locale, "usage.report_messages", cur.Messages) + usageTrend(cur.Messages, prev.Messages) + "\n")
+	sb.WriteString(i18n.T(locale, "usage.report_missions", cur.Missions) + usageTrend(cur.Missions, prev.Missions) + "\n")
+	curTokens, prevTokens := cur.InputTokens+cur.OutputTokens, prev.InputTokens+prev.OutputTokens
+	sb.WriteString(i18n.T(locale, "usage.report_tokens", curTokens, cur.InputTokens, cur.OutputTokens) + usageTrend(curTokens, prevTokens) + "\n")
+	sb.WriteString(i18n.T(locale, "usage.report_cost", cur.CostUSD) + "\n")
+
+	if len(cur.Tools) > 0 {
+		tools := make([]string, 0, len(cur.Tools))
+		for name := range cur.Tools {
+			tools = append(tools, name)
+		}
+		sort.Slice(tools, func(i, j int) bool {
+			if cur.Tools[tools[i]] != cur.Tools[tools[j]] {
+				return cur.Tools[tools[i]] > cur.Tools[tools[j]]
+			}
+			return tools[i] < tools[j]
+		})
+		if len(tools) > maxUsageReportTools {
+			tools = tools[:maxUsageReportTools]
+		}
+		parts := make([]string, len(tools))
+		for i, name := range tools {
+			parts[i] = fmt.Sprintf("`%s` ×%d", name, cur.Tools[name])
+		}
+		sb.WriteString(i18n.T(locale, "usage.report_tools", strings.Join(parts, ", ")) + "\n")
+	}
+
+	sb.WriteString(i18n.T(locale, "usage.report_daily"))
+	for _, d := range daily {
+		label := d.Day
+		if t, err := time.Parse(db.UsageDayFormat, d.Day); err == nil {
+			label = t.Format("Mon Jan 2")
+		}
+		sb.WriteString(i18n.T(locale, "usage.report_day", label, d.Messages, d.InputTokens+d.OutputTokens))
+	}
+	return sb.String(), nil
+}
+
+// addUsage adds the counters of r to total.
+func addUsage(total *db.UsageDay, r db.UsageDay) {
+	total.Messages += r.Messages
+	total.Missions += r.Missions
+	total.InputTokens += r.InputTokens
+	total.OutputTokens += r.OutputTokens
+	total.CostUSD += r.CostUSD
+	for name, n := range r.Tools {
+		if total.Tools == nil {
+			total.Tools = make(map[string]int64)
+		}
+		total.Tools[name] += n
+	}
+}
+
+// usageTrend renders the change from prev to cur, e.g. " (▲ 20%)". It is
+// empty when there is nothing to compare with.
+func usageTrend(cur, prev int64) string {
+	if prev == 0 {
+		return ""
+	}
+	pct := float64(cur-prev) / float64(prev) * 100
+	switch {
+	case pct >= 0.5:
+		return fmt.Sprintf(" (▲ %.0f%%)", pct)
+	case pct <= -0.5:
+		return fmt.Sprintf(" (▼ %.0f%%)", -pct)
+	}
+	return " (=)"
+}
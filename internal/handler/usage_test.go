@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageRollupAndReport(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	cfg := *h.config()
+	cfg.Timezone = "UTC"
+	cfg.Bot.ModelPricing = map[string]config.ModelPrice{"pro": {InputPerMillion: 1e6, OutputPerMillion: 2e6}}
+	rollup := config.JobConfig{Name: "Usage Rollup", Schedule: "0 55 23 * * *", Type: "usage_rollup"}
+	cfg.Jobs = []config.JobConfig{rollup}
+	h.UpdateConfig(&cfg)
+
+	now := time.Now().UTC()
+	today := now.Format(db.UsageDayFormat)
+	lastWeek := now.AddDate(0, 0, -7).Format(db.UsageDayFormat)
+	require.NoError(t, database.AddUsage(ctx, db.UsageDay{Day: lastWeek, Messages: 2, InputTokens: 10}))
+
+	h.stats.RecordSessionMessage("test-session")
+	h.stats.RecordSessionMessage("test-session")
+	h.stats.RecordMission()
+	h.stats.RecordModelTokens("pro", 3, 1)
+	h.stats.RecordModelTokens("flash", 100, 10)
+	h.stats.RecordToolCall("web_search")
+	h.stats.RecordToolCall("web_search")
+	h.stats.RecordToolCall("fetch")
+
+	h.RunJob(ctx, rollup)
+	// A second rollup with no new activity adds nothing.
+	h.RunJob(ctx, rollup)
+
+	days, err := database.GetUsage(ctx, today, today)
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.Equal(t, db.UsageDay{
+		Day: today, Messages: 2, Missions: 1, InputTokens: 103, OutputTokens: 11, CostUSD: 5,
+		Tools: map[string]int64{"web_search": 2, "fetch": 1},
+	}, days[0])
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "test-session", "/usage week", nil, reply)
+	assert.Contains(t, got, "📈 **Usage: "+now.AddDate(0, 0, -6).Format("Jan 2")+" – "+now.Format("Jan 2")+"**")
+	// The /usage message itself was flushed before the report was built.
+	assert.Contains(t, got, "💬 **Messages**: 3 (▲ 50%)")
+	assert.Contains(t, got, "🔬 **Missions**: 1\n")
+	assert.Contains(t, got, "🔤 **Tokens**: 114 (103 in / 11 out) (▲ 1040%)")
+	assert.Contains(t, got, "~$5.0000")
+	assert.Contains(t, got, "🛠 **Top Tools**: `web_search` ×2, `fetch` ×1")
+	assert.Contains(t, got, "• "+now.Format("Mon Jan 2")+": 3 messages, 114 tokens")
+
+	h.HandleMessage(ctx, "test-session", "/usage", nil, reply)
+	assert.Contains(t, got, "**Session Usage**")
+	h.HandleMessage(ctx, "test-session", "/usage year", nil, reply)
+	assert.Contains(t, got, "Usage: `/usage`")
+}
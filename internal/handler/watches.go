@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/i18n"
+	"github.com/raythurman2386/ravenbot/internal/notifier"
+)
+
+// watchNotifyInterval is the minimum time between two announcements of the
+// same resource, so a busy log file does not flood the chat.
+const watchNotifyInterval = time.Minute
+
+// watchExcerptLen caps how much of the changed resource is quoted.
+const watchExcerptLen = 500
+
+// StartResourceWatches subscribes to the resources watched with /watch and
+// announces their changes until ctx ends. It does nothing if the bot cannot
+// watch resources.
+func (h *Handler) StartResourceWatches(ctx context.Context) {
+	watcher, ok := h.bot.(ResourceWatcher)
+	if !ok {
+		return
+	}
+	watcher.OnMCPResourceUpdated(func(server, uri string) {
+		if ctx.Err() == nil {
+			h.resourceUpdated(ctx, watcher, server, uri)
+		}
+	})
+
+	watches, err := h.db.AllResourceWatches(ctx)
+	if err != nil {
+		slog.Error("Failed to load resource watches", "error", err)
+		return
+	}
+	subscribed := make(map[string]bool)
+	for _, w := range watches {
+		key := w.Server + " " + w.URI
+		if subscribed[key] {
+			continue
+		}
+		subscribed[key] = true
+		if err := watcher.WatchMCPResource(ctx, w.Server, w.URI); err != nil {
+			slog.Warn("Failed to resubscribe to MCP resource", "server", w.Server, "uri", w.URI, "error", err)
+		}
+	}
+	slog.Info("Resource watches started", "watches", len(watches), "resources", len(subscribed))
+}
+
+// handleWatch lists the session's watches, or watches a resource.
+func (h *Handler) handleWatch(ctx context.Context, sessionID, text string, reply func(string)) {
+	watcher, ok := h.bot.(ResourceWatcher)
+	if !ok {
+		reply(h.msg(ctx, "watch.unavailable"))
+		return
+	}
+	args := strings.Fields(text[len("/watch"):])
+	if len(args) == 0 {
+		h.listWatches(ctx, sessionID, reply)
+		return
+	}
+	if len(args) != 2 {
+		reply(h.msg(ctx, "watch.usage"))
+		return
+	}
+	server, uri := args[0], args[1]
+
+	if err := watcher.WatchMCPResource(ctx, server, uri); err != nil {
+		slog.Warn("Failed to watch MCP resource", "sessionID", sessionID, "server", server, "uri", uri, "error", err)
+		if errors.Is(err, agent.ErrNoSubscriptions) {
+			reply(h.msg(ctx, "watch.unsupported", server))
+			return
+		}
+		reply(h.msg(ctx, "watch.failed", uri))
+		return
+	}
+	platform, _ := ctx.Value(platformKey{}).(string)
+	added, err := h.db.AddResourceWatch(ctx, db.ResourceWatch{
+		SessionID: sessionID,
+		Server:    server,
+		URI:       uri,
+		Platform:  platform,
+		ChannelID: notifier.ChannelFromContext(ctx),
+	})
+	if err != nil {
+		slog.Error("Failed to save resource watch", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "watch.failed", uri))
+		return
+	}
+	if !added {
+		reply(h.msg(ctx, "watch.exists", uri))
+		return
+	}
+	slog.Info("Watching MCP resource", "sessionID", sessionID, "server", server, "uri", uri)
+	reply(h.msg(ctx, "watch.done", uri, server))
+}
+
+func (h *Handler) listWatches(ctx context.Context, sessionID string, reply func(string)) {
+	watches, err := h.db.ListResourceWatches(ctx, sessionID)
+	if err != nil {
+		slog.Error("Failed to list resource watches", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "watch.list_failed"))
+		return
+	}
+	if len(watches) == 0 {
+		reply(h.msg(ctx, "watch.none"))
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(h.msg(ctx, "watch.header", len(watches)))
+	for _, w := range watches {
+		sb.WriteString(fmt.Sprintf("• `#%d` `%s` %s\n", w.ID, w.Server, w.URI))
+	}
+	sb.WriteString(h.msg(ctx, "watch.footer"))
+	reply(sb.String())
+}
+
+// handleUnwatch removes a watch, unsubscribing once no session watches the
+// resource.
+func (h *Handler) handleUnwatch(ctx context.Context, sessionID, text string, reply func(string)) {
+	arg := strings.TrimSpace(text[len("/unwatch"):])
+	id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	if err != nil {
+		reply(h.msg(ctx, "unwatch.usage"))
+		return
+	}
+	w, err := h.db.RemoveResourceWatch(ctx, sessionID, id)
+	if err != nil {
+		slog.Error("Failed to remove resource watch", "sessionID", sessionID, "error", err)
+		reply(h.msg(ctx, "unwatch.failed"))
+		return
+	}
+	if w == nil {
+		reply(h.msg(ctx, "unwatch.not_found", arg))
+		return
+	}
+	if watcher, ok := h.bot.(ResourceWatcher); ok {
+		if others, err := h.db.ResourceWatchers(ctx, w.Server, w.URI); err == nil && len(others) == 0 {
+			if err := watcher.UnwatchMCPResource(ctx, w.Server, w.URI); err != nil {
+				slog.Warn("Failed to unsubscribe from MCP resource", "server", w.Server, "uri", w.URI, "error", err)
+			}
+		}
+	}
+	reply(h.msg(ctx, "unwatch.done", w.URI))
+}
+
+// resourceUpdated tells every session watching the resource that it
+// changed, quoting the start of its new content.
+func (h *Handler) resourceUpdated(ctx context.Context, watcher ResourceWatcher, server, uri string) {
+	key := server + " " + uri
+	now := time.Now()
+	h.mu.Lock()
+	if last, ok := h.watchNotified[key]; ok && now.Sub(last) < watchNotifyInterval {
+		h.mu.Unlock()
+		slog.Debug("Skipping resource update announcement", "server", server, "uri", uri)
+		return
+	}
+	h.watchNotified[key] = now
+	h.mu.Unlock()
+
+	watches, err := h.db.ResourceWatchers(ctx, server, uri)
+	if err != nil {
+		slog.Error("Failed to load resource watchers", "server", server, "uri", uri, "error", err)
+		return
+	}
+	if len(watches) == 0 {
+		return
+	}
+	excerpt, err := watcher.ReadMCPResource(ctx, server, uri)
+	if err != nil {
+		slog.Warn("Failed to read updated MCP resource", "server", server, "uri", uri, "error", err)
+	}
+	excerpt = strings.TrimSpace(excerpt)
+	if r := []rune(excerpt); len(r) > watchExcerptLen {
+		excerpt = string(r[:watchExcerptLen]) + "…"
+	}
+
+	for _, w := range watches {
+		locale := h.sessionLocale(ctx, w.SessionID)
+		msg := i18n.T(locale, "watch.updated", uri, server)
+		if excerpt != "" {
+			msg += "\n\n```\n" + excerpt + "\n```"
+		}
+		h.deliver(ctx, w.SessionID, w.Platform, w.ChannelID, msg)
+	}
+	slog.Info("Resource update announced", "server", server, "uri", uri, "sessions", len(watches))
+}
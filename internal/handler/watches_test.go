@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchBot struct {
+	mockBot
+	watched  map[string]bool
+	onUpdate func(server, uri string)
+}
+
+func (b *watchBot) WatchMCPResource(_ context.Context, server, uri string) error {
+	if server == "weather" {
+		return agent.ErrNoSubscriptions
+	}
+	b.watched[server+" "+uri] = true
+	return nil
+}
+
+func (b *watchBot) UnwatchMCPResource(_ context.Context, server, uri string) error {
+	delete(b.watched, server+" "+uri)
+	return nil
+}
+
+func (b *watchBot) ReadMCPResource(context.Context, string, string) (string, error) {
+	return "ERROR disk full", nil
+}
+
+func (b *watchBot) OnMCPResourceUpdated(fn func(server, uri string)) { b.onUpdate = fn }
+
+func TestHandleMessage_Watch(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.bot = &mockBot{}
+	h.HandleMessage(ctx, "test-session", "/watch", nil, reply)
+	assert.Contains(t, got, "not available")
+
+	bot := &watchBot{watched: make(map[string]bool)}
+	h.bot = bot
+	h.HandleMessage(ctx, "test-session", "/watch", nil, reply)
+	assert.Contains(t, got, "No watched resources")
+
+	h.HandleMessage(ctx, "test-session", "/watch fs", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	const uri = "file:///var/log/app.log"
+	h.HandleMessage(ctx, "test-session", "/watch fs "+uri, nil, reply)
+	assert.Contains(t, got, "Watching "+uri)
+	assert.True(t, bot.watched["fs "+uri])
+	h.HandleMessage(ctx, "test-session", "/watch fs "+uri, nil, reply)
+	assert.Contains(t, got, "already watching")
+	h.HandleMessage(ctx, "test-session", "/watch weather weather://today", nil, reply)
+	assert.Contains(t, got, "doesn't support")
+
+	h.HandleMessage(ctx, "test-session", "/watch", nil, reply)
+	assert.Contains(t, got, "Watched Resources (1)")
+	assert.Contains(t, got, uri)
+
+	// A restart resubscribes, and updates reach the chat once a minute.
+	bot.watched = make(map[string]bool)
+	h.StartResourceWatches(ctx)
+	assert.True(t, bot.watched["fs "+uri])
+	require.NotNil(t, bot.onUpdate)
+	got = ""
+	bot.onUpdate("fs", uri)
+	assert.Contains(t, got, "changed")
+	assert.Contains(t, got, "ERROR disk full")
+	got = ""
+	bot.onUpdate("fs", uri)
+	assert.Empty(t, got, "repeat updates within a minute are not announced")
+
+	watches, err := database.ListResourceWatches(ctx, "test-session")
+	require.NoError(t, err)
+	require.Len(t, watches, 1)
+	h.HandleMessage(ctx, "test-session", "/unwatch 999", nil, reply)
+	assert.Contains(t, got, "No watch matching")
+	h.HandleMessage(ctx, "test-session", fmt.Sprintf("/unwatch #%d", watches[0].ID), nil, reply)
+	assert.Contains(t, got, "Stopped watching")
+	assert.False(t, bot.watched["fs "+uri], "the last watcher unsubscribes")
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/stats"
+)
+
+// webhookEvent is the X-Ravenbot-Event of a webhook job's request.
+const webhookEvent = "job.report"
+
+// webhookPayload is the JSON body a webhook job POSTs.
+type webhookPayload struct {
+	Event       string        `json:"event"`
+	Job         string        `json:"job"`
+	Markdown    string        `json:"markdown"`
+	Tags        []string      `json:"tags"`
+	Report      string        `json:"report"` // file name in the reports directory
+	GeneratedAt time.Time     `json:"generated_at"`
+	Tokens      webhookTokens `json:"tokens"`
+}
+
+type webhookTokens struct {
+	Input  int64 `json:"input"`
+	Output int64 `json:"output"`
+	Total  int64 `json:"total"`
+}
+
+// runWebhookJob runs a research prompt like a research job, publishes the
+// report as usual, then POSTs it with its metadata to params.url so other
+// systems, such as a static site, can pick it up. With params.secret the
+// request carries an HMAC-SHA256 signature. A report that was already delivered isn't posted again.
+func (h *Handler) runWebhookJob(ctx context.Context, job config.JobConfig) (string, error) {
+	url := job.Params["url"]
+	if url == "" {
+		return "", fmt.Errorf("webhook job needs params.url")
+	}
+	report, tags, err := h.researchReport(ctx, job)
+	if err != nil {
+		return "", err
+	}
+	path, err := h.publishJobReport(ctx, job, report, tags)
+	if err != nil || path == "" {
+		return path, err
+	}
+
+	payload := webhookPayload{
+		Event:       webhookEvent,
+		Job:         job.Name,
+		Markdown:    h.applyJobTemplate(job, report, tags),
+		Tags:        tags,
+		Report:      filepath.Base(path),
+		GeneratedAt: time.Now().UTC(),
+	}
+	if c := stats.CounterFromContext(ctx); c != nil {
+		payload.Tokens = webhookTokens{Input: c.Input(), Output: c.Output(), Total: c.Total()}
+	}
+	if err := h.postWebhook(ctx, url, job.Params["secret"], webhookEvent, payload); err != nil {
+		return path, err
+	}
+	slog.Info("Webhook delivered", "job", job.Name, "path", path)
+	return path, nil
+}
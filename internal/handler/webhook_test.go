@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/config"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookJob(t *testing.T) {
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+	h.cfg.ReportsDir = t.TempDir()
+
+	report := "# Weekly Roundup\n\n" + strings.Repeat("Go 1.26 shipped with faster builds. ", 40) + "\nTAGS: go, releases"
+	h.bot = &mockBot{runMissionFunc: func(ctx context.Context, _ string) (string, error) {
+		stats.CountTokens(ctx, 1500, 300)
+		return report, nil
+	}}
+	var urls, secrets []string
+	var payloads []webhookPayload
+	var postErr error
+	h.postWebhook = func(_ context.Context, url, secret, event string, payload any) error {
+		assert.Equal(t, "job.report", event)
+		urls, secrets = append(urls, url), append(secrets, secret)
+		payloads = append(payloads, payload.(webhookPayload))
+		return postErr
+	}
+	job := config.JobConfig{Name: "Site Publish", Type: "webhook", Params: map[string]string{
+		"prompt": "Write the weekly roundup.",
+		"url":    "https://example.com/hooks/ravenbot",
+		"secret": "s3cret",
+		"tags":   "weekly",
+	}}
+
+	path, err := h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+	assert.Equal(t, []string{"https://example.com/hooks/ravenbot"}, urls)
+	assert.Equal(t, []string{"s3cret"}, secrets)
+	p := payloads[0]
+	assert.Equal(t, "Site Publish", p.Job)
+	assert.True(t, strings.HasPrefix(p.Markdown, "# Weekly Roundup"))
+	assert.NotContains(t, p.Markdown, "TAGS:")
+	assert.Subset(t, p.Tags, []string{"go", "releases", "weekly"})
+	assert.Equal(t, filepath.Base(path), p.Report)
+	assert.Equal(t, webhookTokens{Input: 1500, Output: 300, Total: 1800}, p.Tokens)
+	assert.False(t, p.GeneratedAt.IsZero())
+
+	// The same report was already delivered.
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	require.NoError(t, err)
+	assert.Len(t, payloads, 1)
+
+	report = strings.Replace(report, "faster", "smaller", 1)
+	postErr = errors.New("webhook returned status 500")
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	assert.ErrorContains(t, err, "status 500")
+	runs, err := database.ListJobRuns(ctx, "Site Publish", 1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, db.JobFailed, runs[0].Status)
+
+	delete(job.Params, "url")
+	_, err = h.runJob(ctx, job, db.TriggerManual)
+	assert.ErrorContains(t, err, "params.url")
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// handleWipeUser deletes everything stored for a chat (its session ID, as
+// used in reminders and logs), after confirmation. Admin only.
+func (h *Handler) handleWipeUser(ctx context.Context, sessionID, text string, reply func(string)) {
+	parts := strings.Fields(text[len("/wipe-user"):])
+	if len(parts) != 1 {
+		reply(h.msg(ctx, "wipe.usage"))
+		return
+	}
+	if !h.config().Bot.IsAdmin(sessionID) {
+		slog.Warn("Rejected /wipe-user from non-admin", "sessionID", sessionID)
+		reply(h.msg(ctx, "wipe.denied"))
+		return
+	}
+	target := parts[0]
+
+	h.confirm(ctx, sessionID, h.msg(ctx, "wipe.confirm", target), func(ctx context.Context, reply func(string)) {
+		threads, err := h.db.ListChatSessions(ctx, target)
+		if err != nil {
+			slog.Error("Failed to list sessions to wipe", "target", target, "error", err)
+			reply(h.msg(ctx, "wipe.failed", target))
+			return
+		}
+		// The default thread uses the channel's own ID and may not be listed.
+		ids := []string{target}
+		for _, t := range threads {
+			if t.ID != target {
+				ids = append(ids, t.ID)
+			}
+		}
+		for _, id := range ids {
+			h.bot.ClearSession(id)
+		}
+
+		n, err := h.db.WipeSession(ctx, target)
+		if err != nil {
+			slog.Error("Failed to wipe user data", "target", target, "error", err)
+			reply(h.msg(ctx, "wipe.failed", target))
+			return
+		}
+		h.mu.Lock()
+		delete(h.replies, target)
+		delete(h.confirmations, target)
+		h.mu.Unlock()
+
+		slog.Info("Wiped user data", "target", target, "by", sessionID, "conversations", len(ids), "rows", n)
+		reply(h.msg(ctx, "wipe.done", target, len(ids), n))
+	}, reply)
+}
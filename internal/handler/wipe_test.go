@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMessage_WipeUser(t *testing.T) {
+	t.Parallel()
+	h, database := newTestHandler(t)
+	defer func() { _ = database.Close() }()
+	ctx := context.Background()
+
+	bot := &clearRecordingBot{}
+	h.bot = bot
+	require.NoError(t, database.TouchChatSession(ctx, "telegram-42", "telegram-42:trip", "trip"))
+	require.NoError(t, database.AddReminder(ctx, "telegram-42", "pack", time.Now().Add(time.Hour)))
+
+	var got string
+	reply := func(r string) { got = r }
+
+	h.HandleMessage(ctx, "admin-session", "/wipe-user", nil, reply)
+	assert.Contains(t, got, "Usage")
+
+	h.HandleMessage(ctx, "admin-session", "/wipe-user telegram-42", nil, reply)
+	assert.Contains(t, got, "permanently delete all data for `telegram-42`")
+	h.HandleMessage(ctx, "admin-session", "yes", nil, reply)
+	assert.Contains(t, got, "Deleted all data for `telegram-42`: 2 conversations and 2 records")
+	assert.Equal(t, []string{"telegram-42", "telegram-42:trip"}, bot.cleared)
+
+	reminders, err := database.GetSessionReminders(ctx, "telegram-42")
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+
+	// With admins configured, other chats are refused.
+	cfg := *h.config()
+	cfg.Bot.Admins = []string{"admin-session"}
+	h.UpdateConfig(&cfg)
+	h.HandleMessage(ctx, "other-session", "/wipe-user telegram-42", nil, reply)
+	assert.Contains(t, got, "Only admins")
+	h.HandleMessage(ctx, "admin-session", "/wipe-user telegram-42", nil, reply)
+	assert.Contains(t, got, "permanently delete")
+}
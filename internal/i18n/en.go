@@ -0,0 +1,391 @@
+package i18n
+
+var en = map[string]string{
+	// General
+	"input.too_long":     "⚠️ Message too long (max %d characters). Please shorten your request.",
+	"ratelimit.messages": "🐢 Slow down! You've reached the limit of %d messages per minute. Try again in %s.",
+	"ratelimit.missions": "🐢 Slow down! You've reached the limit of %d research missions per hour. Try again in %s.",
+	"error.with_usage":   "❌ %s.\n%s",
+	"chat.failed":        "Sorry, I encountered an error while processing your request.",
+	"shortcuts.header":   "\n\n⚡ *Shortcuts:*",
+
+	// Confirmations
+	"confirm.prompt":    "⚠️ %s\nReply `yes` within %ds to confirm, or `no` to cancel.",
+	"confirm.yes":       "yes",
+	"confirm.no":        "no",
+	"confirm.expired":   "⌛ That confirmation expired. Please run the command again.",
+	"confirm.cancelled": "🚫 Cancelled.",
+
+	// /language
+	"language.current": "🌐 Language: **%s** (`%s`)\nAvailable: %s\nChange with `/language <code>` or `/language default`.",
+	"language.invalid": "❌ Unsupported language `%s`. Available: %s",
+	"language.set":     "🌐 Language set to **%s**.",
+	"language.reset":   "🌐 Language reset to the default (**%s**).",
+	"language.failed":  "❌ Failed to save language.",
+
+	// /sessions, /session
+	"sessions.header":      "🧵 **Sessions (%d)**\n\n",
+	"sessions.last_active": "last active %s",
+	"sessions.footer":      "\nSwitch with `/session switch <name>` or start one with `/session new <name>`.",
+	"sessions.failed":      "❌ Failed to update sessions.",
+	"session.current":      "🧵 Current session: `%s`",
+	"session.usage":        "Usage: `/session new <name>`, `/session switch <name>` (see `/sessions`)",
+	"session.invalid_name": "❌ Session names are 1-32 lowercase letters, digits, `-` or `_`.",
+	"session.exists":       "❌ A session named `%s` already exists. Use `/session switch %s`.",
+	"session.created":      "🧵 Started session `%s`. New messages go to this thread.",
+	"session.not_found":    "❌ No session named `%s`. See `/sessions`.",
+	"session.switched":     "🧵 Switched to session `%s`.",
+
+	// /status, /reset, /tools, /reload
+	"status.checking":     "🔍 Checking server health...",
+	"status.failed":       "❌ Status check failed. I couldn't retrieve the system health metrics.",
+	"status.mcp_header":   "\n\n🔌 **MCP servers**\n",
+	"status.mcp_up":       "✅ `%s` — up since %s",
+	"status.mcp_down":     "❌ `%s` — down since %s: %s",
+	"status.mcp_failed":   "❌ `%s` — %s",
+	"status.mcp_pending":  "⏳ `%s` — starting",
+	"status.mcp_restarts": " (restarted %d×)",
+	"status.mcp_protocol": " (MCP %s, older features only)",
+	"status.mcp_crash":    "\n    💥 crashed %s: %s",
+	"status.mcp_calls":    "\n    📈 %d calls, avg %s, %d%% failed",
+	"reset.confirm":       "This will clear our conversation history.",
+	"reset.done":          "🔄 Conversation cleared! Let's start fresh.",
+	"tools.unavailable":   "⚠️ Tool listing is not available for this bot.",
+	"tools.header":        "🧰 **Available Tools**\n",
+	"tools.via":           " _(via %s)_",
+	"tools.unassigned":    " _(not assigned to an agent)_",
+	"reload.unavailable":  "⚠️ Reloading is not available in this process.",
+	"reload.failed":       "❌ Reload failed; the current configuration is still active. Check the logs for details.",
+	"reload.done":         "🔄 Configuration reloaded. %s",
+	"reload.denied":       "⛔ Only admins can reload the configuration.",
+
+	// Reminders
+	"remind.usage":         "Usage: `/remind <when> <message>`\nExamples: `/remind 30m Check Docker`, `/remind tomorrow at 3pm Review PR`, `/remind next friday Deploy`, `/remind in 2 weeks Renew cert`, `/remind every day 9am Standup notes`",
+	"remind.every_usage":   "Usage: `/remind every <interval> [at <time>] <message>`\nExamples: `/remind every day 9am Standup notes`, `/remind every weekday at 17:30 Log hours`, `/remind every 2h Stretch`",
+	"remind.save_failed":   "❌ Failed to save reminder.",
+	"remind.set_in":        "⏰ Reminder set! I'll remind you in **%s**: %s",
+	"remind.set_at":        "⏰ Reminder set for **%s**: %s",
+	"remind.invalid_time":  "❌ Invalid duration or time: %s. Try `30m`, `2h`, `tomorrow at 3pm`, `next friday`, or `in 2 weeks`.",
+	"remind.past":          "❌ %s has already passed.",
+	"remind.recurring_set": "🔁 Recurring reminder set **%s** (next: %s): %s",
+	"remind.list_failed":   "❌ Failed to retrieve reminders.",
+	"remind.none":          "📭 No pending reminders.",
+	"remind.list_header":   "⏰ **Pending Reminders (%d)**\n\n",
+	"remind.list_footer":   "\nCancel with `/remind cancel <id>`.",
+	"remind.cancel_usage":  "Usage: `/remind cancel <id>`",
+	"remind.invalid_id":    "❌ Invalid reminder ID `%s`. Use `/remind list` to see IDs.",
+	"remind.cancel_failed": "❌ Failed to cancel reminder.",
+	"remind.not_found":     "❌ No pending reminder with ID `#%d`.",
+	"remind.cancelled":     "🗑 Reminder `#%d` cancelled.",
+	"remind.delivery":      "⏰ **Reminder** `#%d`: %s\n_Snooze: `/snooze %d 10m` · `/snooze %d 1h` · `/snooze %d tomorrow`_",
+	"snooze.usage":         "Usage: `/snooze <id> <when>`\nExamples: `/snooze 12 10m`, `/snooze 12 1h`, `/snooze 12 tomorrow`",
+	"snooze.invalid_id":    "❌ Invalid reminder ID `%s`.",
+	"snooze.invalid_time":  "❌ Invalid snooze time `%s`. Try `10m`, `1h`, or `tomorrow`.",
+	"snooze.failed":        "❌ Failed to snooze reminder.",
+	"snooze.not_found":     "❌ No reminder with ID `#%d`.",
+	"snooze.done":          "😴 Snoozed `#%d` until **%s**.",
+
+	// Todos and memories
+	"todo.usage":         "Usage: `/todo add <task>`, `/todo list [all]`, `/todo done <id>`, `/todo clear [all]`",
+	"todo.add_usage":     "Usage: `/todo add <task>`",
+	"todo.save_failed":   "❌ Failed to save task.",
+	"todo.added":         "📝 Added `#%d`: %s",
+	"todo.list_failed":   "❌ Failed to retrieve tasks.",
+	"todo.empty":         "📭 Your todo list is empty. Add one with `/todo add <task>`.",
+	"todo.list_header":   "📝 **Todo List (%d)**\n\n",
+	"todo.done_usage":    "Usage: `/todo done <id>`",
+	"todo.update_failed": "❌ Failed to update task.",
+	"todo.not_found":     "❌ No open task with ID `#%d`.",
+	"todo.completed":     "✅ Completed `#%d`.",
+	"todo.clear_failed":  "❌ Failed to clear tasks.",
+	"todo.cleared_all":   "🗑 Cleared %d task(s).",
+	"todo.cleared_done":  "🗑 Cleared %d completed task(s).",
+	"remember.usage":     "Usage: `/remember <fact>`\nExample: `/remember I prefer metric units`",
+	"remember.failed":    "❌ Failed to save memory.",
+	"remember.saved":     "🧠 Got it, I'll remember that (`#%d`).",
+	"recall.failed":      "❌ Failed to search memories.",
+	"recall.none":        "📭 I don't have any saved memories yet. Use `/remember <fact>` to add one.",
+	"recall.no_match":    "📭 No memories matching **%s**.",
+	"recall.header":      "🧠 **Memories (%d)**\n\n",
+	"recall.footer":      "\nRemove one with `/forget <id>`.",
+	"forget.usage":       "Usage: `/forget <id>` (see `/recall` for IDs)",
+	"forget.failed":      "❌ Failed to delete memory.",
+	"forget.not_found":   "❌ No memory with ID `#%d`.",
+	"forget.done":        "🗑 Forgotten `#%d`.",
+
+	// Feed subscriptions
+	"subscribe.usage":        "Usage: `/subscribe <feed-url>`\nExample: `/subscribe https://go.dev/blog/feed.atom`",
+	"subscribe.invalid_url":  "❌ That doesn't look like a reachable public http(s) URL.",
+	"subscribe.failed":       "❌ Failed to save subscription.",
+	"subscribe.exists":       "ℹ️ You're already subscribed to %s",
+	"subscribe.done":         "📡 Subscribed to %s. It will be included in feed digests.",
+	"unsubscribe.usage":      "Usage: `/unsubscribe <id|feed-url>` (see `/feeds` for IDs)",
+	"unsubscribe.failed":     "❌ Failed to remove subscription.",
+	"unsubscribe.not_found":  "❌ No subscription matching `%s`.",
+	"unsubscribe.done":       "🗑 Unsubscribed from `%s`.",
+	"feeds.failed":           "❌ Failed to retrieve subscriptions.",
+	"feeds.none":             "📭 No feed subscriptions. Add one with `/subscribe <feed-url>`.",
+	"feeds.header":           "📡 **Feed Subscriptions (%d)**\n\n",
+	"feeds.footer":           "\nRemove one with `/unsubscribe <id>`; change how often one is fetched with `/feeds interval <id> <30m|2h|1d|default>`; `/feeds export` saves them as OPML.",
+	"feeds.usage":            "Usage: `/feeds` to list your feeds, `/feeds interval <id|url> <interval|default>` to set how often one is fetched (at least 5m), e.g. `/feeds interval 3 2h`, `/feeds import` with an OPML file attached (or in reply to one) to subscribe to its feeds, `/feeds export` to get them as OPML",
+	"feeds.never":            "not fetched yet",
+	"feeds.ok":               "✅ fetched %s",
+	"feeds.failing":          "⚠️ failing (%d in a row): %s",
+	"feeds.every":            "every %s",
+	"feeds.interval_done":    "⏱ %s will be fetched every %s.",
+	"feeds.interval_default": "⏱ %s will be fetched at the default interval (%s).",
+	"feeds.import_no_file":   "📎 Attach an OPML file to `/feeds import`, or send the command in reply to one.",
+	"feeds.import_failed":    "❌ Couldn't read the OPML file: %v",
+	"feeds.import_download":  "❌ Couldn't download the OPML file. Try sending it again.",
+	"feeds.import_empty":     "📭 The OPML file lists no feeds.",
+	"feeds.import_too_many":  "❌ The OPML file lists %d feeds; at most %d can be imported at once.",
+	"feeds.import_done":      "📥 Imported feeds: %d added, %d already subscribed, %d rejected.",
+	"feeds.import_more":      "\n…and %d more",
+	"feeds.export_no_files":  "⚠️ Files aren't supported on this channel. Use `/feeds` to list your feeds.",
+	"feeds.export_caption":   "📤 %d feed subscription(s), as OPML",
+
+	// /digest
+	"digest.usage":    "❌ %s. Usage: `/digest [since]`, e.g. `/digest`, `/digest 12h`, `/digest 7d`, `/digest 2026-03-01`",
+	"digest.no_feeds": "📭 No feed subscriptions. Add one with `/subscribe <feed-url>` first.",
+	"digest.building": "📰 Building digest from %d feed(s) and subreddit(s) since %s...",
+	"digest.no_new":   "📭 No new feed items since the last digest.",
+	"digest.failed":   "❌ Digest failed. I couldn't summarize the feed items.",
+
+	// /export, /transcript
+	"export.usage":         "Usage: `/export [N] [md|html|pdf] [since:YYYY-MM-DD|7d] [until:YYYY-MM-DD] [tag:<word>]`\nExamples: `/export 10`, `/export pdf since:7d`, `/export html tag:kubernetes`",
+	"export.failed":        "❌ Failed to retrieve briefings.",
+	"export.none":          "📭 No briefings found. Run `/research <topic>` to generate one!",
+	"export.inline_header": "📋 **Exported %d Briefing(s)**\n\n",
+	"export.inline_item":   "---\n### Briefing %d (Created: %s)\n\n",
+	"export.no_files":      "⚠️ File exports aren't supported on this channel. Use `/export` without a format for inline text.",
+	"export.render_failed": "❌ Failed to render export.",
+	"export.caption":       "📋 %d briefing(s) exported as %s",
+	"export.send_failed":   "❌ Failed to send the export file.",
+	"transcript.usage":     "Usage: `/transcript [n]` — the last n turns (default %d, max %d)",
+	"transcript.failed":    "❌ Failed to retrieve the transcript.",
+	"transcript.none":      "📭 No conversation recorded for this session yet.",
+	"transcript.caption":   "💬 Transcript: %d message(s)",
+	"transcript.you":       "You",
+	"transcript.send_fail": "❌ Failed to send the transcript file.",
+
+	// /jobs, /jobstatus
+	"jobs.usage":          "Usage: `/jobs` to list jobs, `/jobs run <name>` to run one now",
+	"jobs.failed":         "❌ Failed to load the job history.",
+	"jobs.none":           "📭 No jobs are configured. Add them under `jobs` in config.json.",
+	"jobs.header":         "🗓 **Jobs (%d)**\n\n",
+	"jobs.never":          "never run",
+	"jobs.last_run":       "last run %s: %s",
+	"jobs.footer":         "\nHistory: `/jobstatus <name>` · Run now: `/jobs run <name>` · Pause: `/job pause <name>`",
+	"jobs.not_found":      "❓ No job named **%s**. See `/jobs`.",
+	"jobs.running":        "▶️ Running **%s**...",
+	"jobs.done":           "✅ **%s** finished.",
+	"jobs.done_report":    " Report: `%s`",
+	"jobs.run_failed":     "❌ **%s** failed: %v",
+	"jobs.status_success": "✅ success",
+	"jobs.status_failed":  "❌ failed",
+	"jobs.status_running": "⏳ running",
+	"jobs.paused":         " · ⏸ paused",
+	"job.usage":           "Usage: `/job pause <name>` to stop a job's scheduled runs, `/job resume <name>` to restart them",
+	"job.denied":          "⛔ Only admins can pause or resume jobs.",
+	"job.failed":          "❌ Failed to update the job.",
+	"job.paused":          "⏸ **%s** is paused. It won't run on schedule until `/job resume`; `/jobs run` still runs it.",
+	"job.already_paused":  "⏸ **%s** is already paused.",
+	"job.resumed":         "▶️ **%s** is running on schedule again.",
+	"job.not_paused":      "▶️ **%s** isn't paused.",
+	"jobstatus.usage":     "Usage: `/jobstatus <name>` (see `/jobs` for names)",
+	"jobstatus.none":      "📭 **%s** hasn't run yet.",
+	"jobstatus.header":    "📜 **%s**: last %d run(s)\n\n",
+	"jobstatus.tokens":    " · %d tokens",
+
+	// /backup
+	"backup.usage":       "Usage: `/backup now` — snapshot the database (scheduled backups use a `backup` job)",
+	"backup.failed":      "❌ Backup failed. Check the logs for details.",
+	"backup.unsupported": "⚠️ `/backup` only snapshots SQLite. Back up PostgreSQL with `pg_dump`.",
+	"backup.done":        "💾 Backup saved to `%s` (%s).",
+	"backup.archived":    " Reports archived to `%s`.",
+	"backup.rotated":     " Removed %d old backup(s).",
+	"backup.uploaded":    " Copied to `%s`.",
+	"backup.partial":     "\n⚠️ The local copy was kept, but archiving, rotation or the upload failed. Check the logs for details.",
+	"backup.denied":      "⛔ Only admins can run backups.",
+	"backup.job_failed":  "⚠️ Scheduled backup **%s** failed: %v",
+
+	// system_report jobs
+	"sysreport.alert":    "🚨 **%s**: system thresholds crossed\n%s",
+	"sysreport.resolved": "✅ **%s**: system metrics are back within thresholds.",
+	"sysreport.disk":     "• Disk `%s` is %.1f%% full (threshold %.0f%%)",
+	"sysreport.memory":   "• Memory is %.1f%% used (threshold %.0f%%)",
+	"sysreport.swap":     "• Swap is %.1f%% used (threshold %.0f%%)",
+	"sysreport.load":     "• Load is %.2f on %d CPU(s) (threshold %.2f per CPU)",
+
+	// /usage
+	"usage.usage":           "Usage: `/usage` for this chat, `/usage week` or `/usage month` for bot-wide trends",
+	"usage.failed":          "❌ Failed to load the usage history.",
+	"usage.report_header":   "📈 **Usage: %s – %s**\n\n",
+	"usage.report_empty":    "📭 No activity recorded in this period yet.",
+	"usage.report_messages": "💬 **Messages**: %d",
+	"usage.report_missions": "🔬 **Missions**: %d",
+	"usage.report_tokens":   "🔤 **Tokens**: %d (%d in / %d out)",
+	"usage.report_cost":     "💵 **Estimated Cost**: ~$%.4f",
+	"usage.report_tools":    "🛠 **Top Tools**: %s",
+	"usage.report_daily":    "\n📅 **Daily**:\n",
+	"usage.report_day":      "• %s: %d messages, %d tokens\n",
+
+	// /summary
+	"summary.usage":              "Usage: `/summary`, `/summary history`, `/summary rollback <id>`",
+	"summary.failed":             "❌ Failed to load the conversation summary.",
+	"summary.none":               "📭 No summary yet. One is written when this conversation gets long.",
+	"summary.current":            "🧾 **Conversation Summary**\n\n%s\n\nHistory: `/summary history`",
+	"summary.history_header":     "🕰 **Summary History (%d)**\n\n",
+	"summary.history_footer":     "\nRoll back: `/summary rollback <id>`",
+	"summary.source_compression": "compressed",
+	"summary.source_rollback":    "rollback",
+	"summary.source_import":      "imported",
+	"summary.rollback_confirm":   "This will replace the current summary with version #%d.",
+	"summary.not_found":          "❓ No summary version #%d in this conversation. See `/summary history`.",
+	"summary.rolled_back":        "⏪ Restored summary version #%d. It applies from your next message.",
+
+	// /wipe-user
+	"wipe.usage":   "Usage: `/wipe-user <id>`, e.g. `/wipe-user telegram-123456`. Deletes all data stored for that chat.",
+	"wipe.denied":  "⛔ Only admins can wipe user data.",
+	"wipe.confirm": "This will permanently delete all data for `%s`: conversations, summaries, transcripts, reminders, todos, memories, subscriptions and its own briefings.",
+	"wipe.failed":  "❌ Failed to wipe data for `%s`.",
+	"wipe.done":    "🧹 Deleted all data for `%s`: %d conversations and %d records.",
+
+	// /mcplog
+	"mcplog.unavailable": "⚠️ MCP server logging is not available for this bot.",
+	"mcplog.usage":       "Usage: `/mcplog <server> <level>`, e.g. `/mcplog github debug`. Levels: %s.",
+	"mcplog.denied":      "⛔ Only admins can change MCP server log levels.",
+	"mcplog.unsupported": "❌ MCP server `%s` doesn't support logging.",
+	"mcplog.failed":      "❌ Couldn't set the log level of `%s`. Check the server name.",
+	"mcplog.done":        "🪵 `%s` now logs from `%s` up, until it is restarted.",
+
+	// /mcp
+	"mcp.unavailable":    "⚠️ Adding and removing MCP servers is not available for this bot.",
+	"mcp.usage":          "Usage: `/mcp add <name> <command|url> [args...]`, `/mcp remove <name>` or `/mcp refresh <name>`, e.g. `/mcp add notes npx -y @acme/notes-mcp`.",
+	"mcp.denied":         "⛔ Only admins can add or remove MCP servers.",
+	"mcp.invalid_name":   "❌ `%s` isn't a valid server name. Use letters, digits, `.`, `-` and `_`.",
+	"mcp.exists":         "❌ There is already an MCP server called `%s`. Remove it first to replace it.",
+	"mcp.add_failed":     "❌ Couldn't start `%s`: %v",
+	"mcp.added":          "🔌 `%s` is connected; its tools are available to %s from the next message.",
+	"mcp.unknown":        "❌ There is no MCP server called `%s`.",
+	"mcp.remove_failed":  "❌ Couldn't remove `%s`. Check the logs.",
+	"mcp.removed":        "🔌 `%s` is stopped and its tools removed.",
+	"mcp.refresh_failed": "❌ Couldn't refresh `%s`: %v",
+	"mcp.refreshed":      "🔄 `%s` has %d tools; agents use the new definitions from the next message.",
+
+	// /dbstats
+	"dbstats.none":   "📭 No database queries recorded yet.",
+	"dbstats.header": "🗄 **Database Queries**: %d in %s, %d slow\n\n",
+	"dbstats.slow":   " ⚠️ %d slow",
+	"dbstats.footer": "\nSlowest kinds first, since startup. Slow queries are logged with their SQL (threshold: `slowQueryMs`).",
+
+	// /mcpstats
+	"mcpstats.none":   "📭 No MCP tool calls recorded yet.",
+	"mcpstats.header": "🔌 **MCP Tool Calls**: %d, %d failed\n",
+	"mcpstats.server": "\n**%s** — %d calls, avg %s, %d%% failed\n",
+	"mcpstats.failed": " ⚠️ %d failed (%d%%)",
+	"mcpstats.footer": "\nSince startup, slowest tools first. A server whose calls are slow or often fail can be stopped with `/mcp remove <name>`.",
+
+	// /set
+	"set.usage":                      "Usage: `/set` to list settings, `/set <key> <value>` to change one, `/set <key> reset` to go back to config.json.",
+	"set.unknown":                    "❓ Unknown setting `%s`. Send `/set` for the list.",
+	"set.denied":                     "⛔ Only admins can change settings.",
+	"set.invalid":                    "❌ Invalid value for `%s`: %v",
+	"set.failed":                     "❌ Failed to update settings.",
+	"set.done":                       "✅ `%s` set to `%s`. It applies from the next message.",
+	"set.reset":                      "↩️ `%s` reset to the config.json value (`%s`).",
+	"set.header":                     "⚙️ **Runtime Settings**\n\n",
+	"set.overridden":                 " (set)",
+	"set.footer":                     "\nChange: `/set <key> <value>` · Undo: `/set <key> reset`",
+	"set.desc_compressionThreshold":  "share of the token limit that triggers summarizing the conversation",
+	"set.desc_flashTokenLimit":       "context size of the fast model, in tokens",
+	"set.desc_proTokenLimit":         "context size of the advanced model, in tokens",
+	"set.desc_maxConcurrentMissions": "research missions running at once (0 = no limit)",
+	"set.desc_maxSessionEvents":      "recent conversation events loaded per message (0 = default, negative = all)",
+	"set.desc_defaultModel":          "`auto` routes each message, `flash` or `pro` always use that model",
+
+	// /prompts, /prompt
+	"prompts.unavailable": "⚠️ MCP prompts are not available for this bot.",
+	"prompts.none":        "📭 None of the MCP servers offer prompts.",
+	"prompts.header":      "📝 **MCP Prompts (%d)**\n",
+	"prompts.args":        " — %s",
+	"prompts.footer":      "\nRun one with `/prompt <server>/<name> [arg=value ...]` (* = required).",
+	"prompt.usage":        "Usage: `/prompt <server>/<name> [arg=value ...]`\nExample: `/prompt github/review-pr repo=owner/repo number=42`\nSee `/prompts` for the list.\n\n`/prompt set [--replace] <instructions>`, `/prompt show` and `/prompt clear` give this conversation its own system prompt.",
+	"prompt.set_usage":    "Usage: `/prompt set [--replace] <instructions>`\nExample: `/prompt set Answer as a terse SRE and prefer shell one-liners.`\nThe instructions are added to the system prompt; with `--replace` they are used instead of it.",
+	"prompt.set":          "📝 Prompt saved: this conversation follows it on top of the system prompt. See it with `/prompt show`, remove it with `/prompt clear`.",
+	"prompt.replaced":     "📝 Prompt saved: this conversation uses it instead of the system prompt. See it with `/prompt show`, remove it with `/prompt clear`.",
+	"prompt.show_append":  "📝 **Prompt for this conversation** (added to the system prompt, set %s):\n%s",
+	"prompt.show_replace": "📝 **Prompt for this conversation** (replaces the system prompt, set %s):\n%s",
+	"prompt.none":         "This conversation uses the system prompt alone. Give it its own with `/prompt set <instructions>`.",
+	"prompt.cleared":      "🧹 Prompt cleared: this conversation uses the system prompt again.",
+	"prompt.save_failed":  "❌ Failed to save the prompt.",
+	"prompt.load_failed":  "❌ Failed to load the prompt.",
+	"prompt.unknown":      "❌ No MCP prompt named `%s`. See `/prompts`.",
+	"prompt.missing":      "❌ Missing required argument `%s` for `%s`.",
+	"prompt.failed":       "❌ Failed to load the prompt `%s` from its MCP server.",
+
+	// /watch, /unwatch
+	"watch.unavailable": "⚠️ Resource watching is not available for this bot.",
+	"watch.usage":       "Usage: `/watch <server> <resource-uri>`\nExample: `/watch filesystem file:///app/logs/combined.log`",
+	"watch.unsupported": "❌ MCP server `%s` doesn't support resource subscriptions.",
+	"watch.failed":      "❌ Couldn't watch %s. Check the server name and URI.",
+	"watch.exists":      "ℹ️ You're already watching %s",
+	"watch.done":        "👀 Watching %s on `%s`. I'll tell you when it changes.",
+	"watch.list_failed": "❌ Failed to retrieve watched resources.",
+	"watch.none":        "📭 No watched resources. Add one with `/watch <server> <uri>`.",
+	"watch.header":      "👀 **Watched Resources (%d)**\n\n",
+	"watch.footer":      "\nStop watching one with `/unwatch <id>`.",
+	"watch.updated":     "🔔 **%s** changed (`%s`).",
+	"unwatch.usage":     "Usage: `/unwatch <id>` (see `/watch` for IDs)",
+	"unwatch.failed":    "❌ Failed to remove the watch.",
+	"unwatch.not_found": "❌ No watch matching `%s`.",
+	"unwatch.done":      "🗑 Stopped watching %s.",
+
+	// /history
+	"history.usage":         "Usage: `/history <words>` — search past briefings and feed headlines\n`/history --chat <words>` — search our past conversations\nExample: `/history kubernetes release`",
+	"history.failed":        "❌ Failed to search history.",
+	"history.no_match":      "📭 Nothing in past briefings or headlines matches **%s**.",
+	"history.header":        "🔎 **History: %s**\n",
+	"history.briefings":     "\n**Briefings (%d)**\n",
+	"history.headlines":     "\n**Headlines (%d)**\n",
+	"history.chat":          "\n**Conversations (%d)**\n",
+	"history.chat_no_match": "📭 Nothing in our past conversations matches **%s**.",
+
+	// /feedback
+	"feedback.usage":        "Usage: `/feedback <text>`\nExample: `/feedback The daily briefing should include Rust news`",
+	"feedback.failed":       "❌ Failed to save feedback.",
+	"feedback.saved":        "🙏 Thanks! Feedback `#%d` recorded.",
+	"feedback.issue_opened": "🐛 Opened an issue for it: %s",
+	"feedback.issue_failed": "⚠️ Your feedback was saved, but I couldn't open a GitHub issue for it.",
+
+	// MCP server questions (elicitation)
+	"elicit.header":   "❓ `%s` (%s) needs some input:\n%s\n\nReply `cancel` to stop or `decline` to refuse.",
+	"elicit.confirm":  "Reply `yes` to go ahead or `no` to decline.",
+	"elicit.choices":  "Choose one of: %s",
+	"elicit.yes_no":   "Reply `yes` or `no`.",
+	"elicit.optional": "_Optional: reply `skip` to leave it out._",
+	"elicit.invalid":  "⚠️ %s. Please try again.",
+	"elicit.sent":     "✅ Sent your answers to `%s`.",
+	"elicit.timeout":  "⌛ No answer in time; the question was cancelled.",
+
+	// MCP tool progress and attachments
+	"progress.update":    "⏳ `%s` (%s) is still running%s",
+	"attachment.caption": "📎 From `%s` (%s)",
+
+	// /research, /jules
+	"research.usage":       "Please provide a topic. Usage: `/research <topic>`",
+	"research.starting":    "🔬 Starting research on: **%s**...",
+	"research.failed":      "❌ Research failed. I couldn't complete the research mission.",
+	"jules.usage":          "Usage: `/jules <owner/repo> <task description>`, or `/jules status` to see your Jules sessions",
+	"jules.confirm":        "This will delegate **%s** to Jules, which works on **%s** and may open a pull request.",
+	"jules.delegating":     "🤖 Delegating to Jules for **%s**: %s",
+	"jules.failed":         "❌ Jules delegation failed. I couldn't hand off the task to Jules.",
+	"jules.status_header":  "🤖 **Jules sessions** (%d):\n",
+	"jules.status_none":    "🤖 You haven't started any Jules sessions yet. Use `/jules <owner/repo> <task>`.",
+	"jules.status_failed":  "❌ I couldn't load your Jules sessions.",
+	"jules.status_pr":      "PR [%s](%s)",
+	"jules.pr_opened":      "🔀 Jules opened PR %s on **%s**: %s\n%s",
+	"jules.needs_input":    "🙋 Jules needs your input on **%s**: %s",
+	"jules.session_failed": "❌ Jules couldn't finish the task on **%s**: %s",
+	"jules.completed":      "✅ Jules finished the task on **%s**: %s",
+}
@@ -0,0 +1,393 @@
+package i18n
+
+// es is the Spanish catalog. Command names and their arguments stay in
+// English because the parsers only understand English keywords.
+var es = map[string]string{
+	// General
+	"input.too_long":     "⚠️ Mensaje demasiado largo (máximo %d caracteres). Acorta tu solicitud.",
+	"ratelimit.messages": "🐢 ¡Más despacio! Alcanzaste el límite de %d mensajes por minuto. Inténtalo de nuevo en %s.",
+	"ratelimit.missions": "🐢 ¡Más despacio! Alcanzaste el límite de %d investigaciones por hora. Inténtalo de nuevo en %s.",
+	"error.with_usage":   "❌ %s.\n%s",
+	"chat.failed":        "Lo siento, ocurrió un error al procesar tu solicitud.",
+	"shortcuts.header":   "\n\n⚡ *Atajos:*",
+
+	// Confirmations
+	"confirm.prompt":    "⚠️ %s\nResponde `sí` en %ds para confirmar, o `no` para cancelar.",
+	"confirm.yes":       "sí",
+	"confirm.no":        "no",
+	"confirm.expired":   "⌛ La confirmación expiró. Vuelve a ejecutar el comando.",
+	"confirm.cancelled": "🚫 Cancelado.",
+
+	// /language
+	"language.current": "🌐 Idioma: **%s** (`%s`)\nDisponibles: %s\nCámbialo con `/language <código>` o `/language default`.",
+	"language.invalid": "❌ Idioma no compatible `%s`. Disponibles: %s",
+	"language.set":     "🌐 Idioma cambiado a **%s**.",
+	"language.reset":   "🌐 Idioma restablecido al predeterminado (**%s**).",
+	"language.failed":  "❌ No se pudo guardar el idioma.",
+
+	// /sessions, /session
+	"sessions.header":      "🧵 **Sesiones (%d)**\n\n",
+	"sessions.last_active": "última actividad %s",
+	"sessions.footer":      "\nCambia con `/session switch <nombre>` o crea una con `/session new <nombre>`.",
+	"sessions.failed":      "❌ No se pudieron actualizar las sesiones.",
+	"session.current":      "🧵 Sesión actual: `%s`",
+	"session.usage":        "Uso: `/session new <nombre>`, `/session switch <nombre>` (consulta `/sessions`)",
+	"session.invalid_name": "❌ Los nombres de sesión tienen de 1 a 32 letras minúsculas, dígitos, `-` o `_`.",
+	"session.exists":       "❌ Ya existe una sesión llamada `%s`. Usa `/session switch %s`.",
+	"session.created":      "🧵 Sesión `%s` iniciada. Los mensajes nuevos irán a este hilo.",
+	"session.not_found":    "❌ No hay una sesión llamada `%s`. Consulta `/sessions`.",
+	"session.switched":     "🧵 Cambiaste a la sesión `%s`.",
+
+	// /status, /reset, /tools, /reload
+	"status.checking":     "🔍 Revisando el estado del servidor...",
+	"status.failed":       "❌ Falló la revisión de estado. No pude obtener las métricas del sistema.",
+	"status.mcp_header":   "\n\n🔌 **Servidores MCP**\n",
+	"status.mcp_up":       "✅ `%s` — activo desde %s",
+	"status.mcp_down":     "❌ `%s` — caído desde %s: %s",
+	"status.mcp_failed":   "❌ `%s` — %s",
+	"status.mcp_pending":  "⏳ `%s` — iniciando",
+	"status.mcp_restarts": " (reiniciado %d×)",
+	"status.mcp_protocol": " (MCP %s, solo funciones antiguas)",
+	"status.mcp_crash":    "\n    💥 se cayó el %s: %s",
+	"status.mcp_calls":    "\n    📈 %d llamadas, promedio %s, %d%% fallidas",
+	"reset.confirm":       "Esto borrará el historial de nuestra conversación.",
+	"reset.done":          "🔄 ¡Conversación borrada! Empecemos de nuevo.",
+	"tools.unavailable":   "⚠️ La lista de herramientas no está disponible para este bot.",
+	"tools.header":        "🧰 **Herramientas disponibles**\n",
+	"tools.via":           " _(vía %s)_",
+	"tools.unassigned":    " _(sin agente asignado)_",
+	"reload.unavailable":  "⚠️ La recarga no está disponible en este proceso.",
+	"reload.failed":       "❌ Falló la recarga; la configuración actual sigue activa. Revisa los registros.",
+	"reload.done":         "🔄 Configuración recargada. %s",
+	"reload.denied":       "⛔ Solo los administradores pueden recargar la configuración.",
+
+	// Reminders
+	"remind.usage":         "Uso: `/remind <cuándo> <mensaje>`\nEjemplos: `/remind 30m Revisar Docker`, `/remind tomorrow at 3pm Revisar PR`, `/remind next friday Desplegar`, `/remind in 2 weeks Renovar certificado`, `/remind every day 9am Notas del standup`",
+	"remind.every_usage":   "Uso: `/remind every <intervalo> [at <hora>] <mensaje>`\nEjemplos: `/remind every day 9am Notas del standup`, `/remind every weekday at 17:30 Registrar horas`, `/remind every 2h Estirarse`",
+	"remind.save_failed":   "❌ No se pudo guardar el recordatorio.",
+	"remind.set_in":        "⏰ ¡Recordatorio creado! Te lo recordaré en **%s**: %s",
+	"remind.set_at":        "⏰ Recordatorio para el **%s**: %s",
+	"remind.invalid_time":  "❌ Duración u hora no válida: %s. Prueba `30m`, `2h`, `tomorrow at 3pm`, `next friday` o `in 2 weeks`.",
+	"remind.past":          "❌ %s ya pasó.",
+	"remind.recurring_set": "🔁 Recordatorio recurrente **%s** (próximo: %s): %s",
+	"remind.list_failed":   "❌ No se pudieron obtener los recordatorios.",
+	"remind.none":          "📭 No hay recordatorios pendientes.",
+	"remind.list_header":   "⏰ **Recordatorios pendientes (%d)**\n\n",
+	"remind.list_footer":   "\nCancela con `/remind cancel <id>`.",
+	"remind.cancel_usage":  "Uso: `/remind cancel <id>`",
+	"remind.invalid_id":    "❌ ID de recordatorio no válido `%s`. Usa `/remind list` para ver los IDs.",
+	"remind.cancel_failed": "❌ No se pudo cancelar el recordatorio.",
+	"remind.not_found":     "❌ No hay un recordatorio pendiente con ID `#%d`.",
+	"remind.cancelled":     "🗑 Recordatorio `#%d` cancelado.",
+	"remind.delivery":      "⏰ **Recordatorio** `#%d`: %s\n_Posponer: `/snooze %d 10m` · `/snooze %d 1h` · `/snooze %d tomorrow`_",
+	"snooze.usage":         "Uso: `/snooze <id> <cuándo>`\nEjemplos: `/snooze 12 10m`, `/snooze 12 1h`, `/snooze 12 tomorrow`",
+	"snooze.invalid_id":    "❌ ID de recordatorio no válido `%s`.",
+	"snooze.invalid_time":  "❌ Hora de aplazamiento no válida `%s`. Prueba `10m`, `1h` o `tomorrow`.",
+	"snooze.failed":        "❌ No se pudo posponer el recordatorio.",
+	"snooze.not_found":     "❌ No hay un recordatorio con ID `#%d`.",
+	"snooze.done":          "😴 `#%d` pospuesto hasta el **%s**.",
+
+	// Todos and memories
+	"todo.usage":         "Uso: `/todo add <tarea>`, `/todo list [all]`, `/todo done <id>`, `/todo clear [all]`",
+	"todo.add_usage":     "Uso: `/todo add <tarea>`",
+	"todo.save_failed":   "❌ No se pudo guardar la tarea.",
+	"todo.added":         "📝 Agregada `#%d`: %s",
+	"todo.list_failed":   "❌ No se pudieron obtener las tareas.",
+	"todo.empty":         "📭 Tu lista de tareas está vacía. Agrega una con `/todo add <tarea>`.",
+	"todo.list_header":   "📝 **Lista de tareas (%d)**\n\n",
+	"todo.done_usage":    "Uso: `/todo done <id>`",
+	"todo.update_failed": "❌ No se pudo actualizar la tarea.",
+	"todo.not_found":     "❌ No hay una tarea abierta con ID `#%d`.",
+	"todo.completed":     "✅ Completada `#%d`.",
+	"todo.clear_failed":  "❌ No se pudieron borrar las tareas.",
+	"todo.cleared_all":   "🗑 %d tarea(s) borradas.",
+	"todo.cleared_done":  "🗑 %d tarea(s) completadas borradas.",
+	"remember.usage":     "Uso: `/remember <dato>`\nEjemplo: `/remember Prefiero unidades métricas`",
+	"remember.failed":    "❌ No se pudo guardar el recuerdo.",
+	"remember.saved":     "🧠 Entendido, lo recordaré (`#%d`).",
+	"recall.failed":      "❌ No se pudieron buscar los recuerdos.",
+	"recall.none":        "📭 Aún no tengo recuerdos guardados. Usa `/remember <dato>` para agregar uno.",
+	"recall.no_match":    "📭 No hay recuerdos que coincidan con **%s**.",
+	"recall.header":      "🧠 **Recuerdos (%d)**\n\n",
+	"recall.footer":      "\nElimina uno con `/forget <id>`.",
+	"forget.usage":       "Uso: `/forget <id>` (consulta `/recall` para ver los IDs)",
+	"forget.failed":      "❌ No se pudo eliminar el recuerdo.",
+	"forget.not_found":   "❌ No hay un recuerdo con ID `#%d`.",
+	"forget.done":        "🗑 Olvidado `#%d`.",
+
+	// Feed subscriptions
+	"subscribe.usage":        "Uso: `/subscribe <url-del-feed>`\nEjemplo: `/subscribe https://go.dev/blog/feed.atom`",
+	"subscribe.invalid_url":  "❌ Eso no parece una URL http(s) pública y accesible.",
+	"subscribe.failed":       "❌ No se pudo guardar la suscripción.",
+	"subscribe.exists":       "ℹ️ Ya estás suscrito a %s",
+	"subscribe.done":         "📡 Suscrito a %s. Se incluirá en los resúmenes de feeds.",
+	"unsubscribe.usage":      "Uso: `/unsubscribe <id|url-del-feed>` (consulta `/feeds` para ver los IDs)",
+	"unsubscribe.failed":     "❌ No se pudo eliminar la suscripción.",
+	"unsubscribe.not_found":  "❌ No hay una suscripción que coincida con `%s`.",
+	"unsubscribe.done":       "🗑 Suscripción a `%s` cancelada.",
+	"feeds.failed":           "❌ No se pudieron obtener las suscripciones.",
+	"feeds.none":             "📭 No hay suscripciones a feeds. Agrega una con `/subscribe <url-del-feed>`.",
+	"feeds.header":           "📡 **Suscripciones a feeds (%d)**\n\n",
+	"feeds.footer":           "\nElimina una con `/unsubscribe <id>`; cambia con qué frecuencia se consulta con `/feeds interval <id> <30m|2h|1d|default>`; `/feeds export` los guarda en OPML.",
+	"feeds.usage":            "Uso: `/feeds` para ver tus feeds, `/feeds interval <id|url> <intervalo|default>` para fijar con qué frecuencia se consulta uno (mínimo 5m), p. ej. `/feeds interval 3 2h`, `/feeds import` con un archivo OPML adjunto (o respondiendo a uno) para suscribirte a sus feeds, `/feeds export` para obtenerlos en OPML",
+	"feeds.never":            "aún sin consultar",
+	"feeds.ok":               "✅ consultado %s",
+	"feeds.failing":          "⚠️ fallando (%d seguidas): %s",
+	"feeds.every":            "cada %s",
+	"feeds.interval_done":    "⏱ %s se consultará cada %s.",
+	"feeds.interval_default": "⏱ %s se consultará con el intervalo predeterminado (%s).",
+	"feeds.import_no_file":   "📎 Adjunta un archivo OPML a `/feeds import`, o envía el comando respondiendo a uno.",
+	"feeds.import_failed":    "❌ No se pudo leer el archivo OPML: %v",
+	"feeds.import_download":  "❌ No se pudo descargar el archivo OPML. Prueba a enviarlo de nuevo.",
+	"feeds.import_empty":     "📭 El archivo OPML no contiene feeds.",
+	"feeds.import_too_many":  "❌ El archivo OPML contiene %d feeds; se pueden importar como máximo %d a la vez.",
+	"feeds.import_done":      "📥 Feeds importados: %d agregados, %d ya suscritos, %d rechazados.",
+	"feeds.import_more":      "\n…y %d más",
+	"feeds.export_no_files":  "⚠️ Este canal no admite archivos. Usa `/feeds` para ver tus feeds.",
+	"feeds.export_caption":   "📤 %d suscripción(es) a feeds, en OPML",
+
+	// /digest
+	"digest.usage":    "❌ %s. Uso: `/digest [desde]`, p. ej. `/digest`, `/digest 12h`, `/digest 7d`, `/digest 2026-03-01`",
+	"digest.no_feeds": "📭 No hay suscripciones a feeds. Primero agrega una con `/subscribe <url-del-feed>`.",
+	"digest.building": "📰 Preparando el resumen de %d feed(s) y subreddit(s) desde %s...",
+	"digest.no_new":   "📭 No hay elementos nuevos desde el último resumen.",
+	"digest.failed":   "❌ Falló el resumen. No pude resumir los elementos de los feeds.",
+
+	// /export, /transcript
+	"export.usage":         "Uso: `/export [N] [md|html|pdf] [since:AAAA-MM-DD|7d] [until:AAAA-MM-DD] [tag:<palabra>]`\nEjemplos: `/export 10`, `/export pdf since:7d`, `/export html tag:kubernetes`",
+	"export.failed":        "❌ No se pudieron obtener los informes.",
+	"export.none":          "📭 No se encontraron informes. ¡Ejecuta `/research <tema>` para generar uno!",
+	"export.inline_header": "📋 **%d informe(s) exportados**\n\n",
+	"export.inline_item":   "---\n### Informe %d (Creado: %s)\n\n",
+	"export.no_files":      "⚠️ Este canal no admite archivos. Usa `/export` sin formato para obtener el texto.",
+	"export.render_failed": "❌ No se pudo generar la exportación.",
+	"export.caption":       "📋 %d informe(s) exportados como %s",
+	"export.send_failed":   "❌ No se pudo enviar el archivo exportado.",
+	"transcript.usage":     "Uso: `/transcript [n]`: los últimos n turnos (predeterminado %d, máximo %d)",
+	"transcript.failed":    "❌ No se pudo obtener la transcripción.",
+	"transcript.none":      "📭 Todavía no hay conversación registrada en esta sesión.",
+	"transcript.caption":   "💬 Transcripción: %d mensaje(s)",
+	"transcript.you":       "Tú",
+	"transcript.send_fail": "❌ No se pudo enviar el archivo de la transcripción.",
+
+	// /jobs, /jobstatus
+	"jobs.usage":          "Uso: `/jobs` para ver los jobs, `/jobs run <nombre>` para ejecutar uno ahora",
+	"jobs.failed":         "❌ No se pudo cargar el historial de jobs.",
+	"jobs.none":           "📭 No hay jobs configurados. Añádelos en `jobs` de config.json.",
+	"jobs.header":         "🗓 **Jobs (%d)**\n\n",
+	"jobs.never":          "nunca ejecutado",
+	"jobs.last_run":       "última ejecución %s: %s",
+	"jobs.footer":         "\nHistorial: `/jobstatus <nombre>` · Ejecutar ahora: `/jobs run <nombre>` · Pausar: `/job pause <nombre>`",
+	"jobs.not_found":      "❓ No hay ningún job llamado **%s**. Consulta `/jobs`.",
+	"jobs.running":        "▶️ Ejecutando **%s**...",
+	"jobs.done":           "✅ **%s** terminó.",
+	"jobs.done_report":    " Informe: `%s`",
+	"jobs.run_failed":     "❌ **%s** falló: %v",
+	"jobs.status_success": "✅ correcto",
+	"jobs.status_failed":  "❌ fallido",
+	"jobs.status_running": "⏳ en curso",
+	"jobs.paused":         " · ⏸ en pausa",
+	"job.usage":           "Uso: `/job pause <nombre>` para detener las ejecuciones programadas de un job, `/job resume <nombre>` para reanudarlas",
+	"job.denied":          "⛔ Solo los administradores pueden pausar o reanudar jobs.",
+	"job.failed":          "❌ No se pudo actualizar el job.",
+	"job.paused":          "⏸ **%s** está en pausa. No se ejecutará según su programación hasta `/job resume`; `/jobs run` sigue ejecutándolo.",
+	"job.already_paused":  "⏸ **%s** ya está en pausa.",
+	"job.resumed":         "▶️ **%s** vuelve a ejecutarse según su programación.",
+	"job.not_paused":      "▶️ **%s** no está en pausa.",
+	"jobstatus.usage":     "Uso: `/jobstatus <nombre>` (consulta `/jobs` para ver los nombres)",
+	"jobstatus.none":      "📭 **%s** todavía no se ha ejecutado.",
+	"jobstatus.header":    "📜 **%s**: últimas %d ejecución(es)\n\n",
+	"jobstatus.tokens":    " · %d tokens",
+
+	// /backup
+	"backup.usage":       "Uso: `/backup now`: crea una copia de la base de datos (las copias programadas usan un job `backup`)",
+	"backup.failed":      "❌ La copia de seguridad falló. Revisa los registros.",
+	"backup.unsupported": "⚠️ `/backup` solo copia SQLite. Respalda PostgreSQL con `pg_dump`.",
+	"backup.done":        "💾 Copia guardada en `%s` (%s).",
+	"backup.archived":    " Informes archivados en `%s`.",
+	"backup.rotated":     " Se eliminaron %d copia(s) antigua(s).",
+	"backup.uploaded":    " Copiada a `%s`.",
+	"backup.partial":     "\n⚠️ Se conservó la copia local, pero falló el archivado, la rotación o la subida. Revisa los registros.",
+	"backup.denied":      "⛔ Solo los administradores pueden crear copias de seguridad.",
+	"backup.job_failed":  "⚠️ La copia programada **%s** falló: %v",
+
+	// jobs system_report
+	"sysreport.alert":    "🚨 **%s**: se superaron umbrales del sistema\n%s",
+	"sysreport.resolved": "✅ **%s**: las métricas del sistema vuelven a estar dentro de los umbrales.",
+	"sysreport.disk":     "• El disco `%s` está al %.1f%% (umbral %.0f%%)",
+	"sysreport.memory":   "• La memoria está al %.1f%% (umbral %.0f%%)",
+	"sysreport.swap":     "• El swap está al %.1f%% (umbral %.0f%%)",
+	"sysreport.load":     "• La carga es %.2f en %d CPU(s) (umbral %.2f por CPU)",
+
+	// /usage
+	"usage.usage":           "Uso: `/usage` para este chat, `/usage week` o `/usage month` para las tendencias del bot",
+	"usage.failed":          "❌ No se pudo cargar el historial de uso.",
+	"usage.report_header":   "📈 **Uso: %s – %s**\n\n",
+	"usage.report_empty":    "📭 Aún no hay actividad registrada en este periodo.",
+	"usage.report_messages": "💬 **Mensajes**: %d",
+	"usage.report_missions": "🔬 **Misiones**: %d",
+	"usage.report_tokens":   "🔤 **Tokens**: %d (%d de entrada / %d de salida)",
+	"usage.report_cost":     "💵 **Coste estimado**: ~$%.4f",
+	"usage.report_tools":    "🛠 **Herramientas más usadas**: %s",
+	"usage.report_daily":    "\n📅 **Por día**:\n",
+	"usage.report_day":      "• %s: %d mensajes, %d tokens\n",
+
+	// /summary
+	"summary.usage":              "Uso: `/summary`, `/summary history`, `/summary rollback <id>`",
+	"summary.failed":             "❌ No se pudo cargar el resumen de la conversación.",
+	"summary.none":               "📭 Aún no hay resumen. Se escribe cuando la conversación se alarga.",
+	"summary.current":            "🧾 **Resumen de la conversación**\n\n%s\n\nHistorial: `/summary history`",
+	"summary.history_header":     "🕰 **Historial del resumen (%d)**\n\n",
+	"summary.history_footer":     "\nRestaurar: `/summary rollback <id>`",
+	"summary.source_compression": "compresión",
+	"summary.source_rollback":    "restauración",
+	"summary.source_import":      "importación",
+	"summary.rollback_confirm":   "Esto reemplazará el resumen actual por la versión #%d.",
+	"summary.not_found":          "❓ No hay una versión #%d del resumen en esta conversación. Consulta `/summary history`.",
+	"summary.rolled_back":        "⏪ Versión #%d del resumen restaurada. Se aplica desde tu próximo mensaje.",
+
+	// /wipe-user
+	"wipe.usage":   "Uso: `/wipe-user <id>`, p. ej. `/wipe-user telegram-123456`. Borra todos los datos guardados de ese chat.",
+	"wipe.denied":  "⛔ Solo los administradores pueden borrar datos de usuarios.",
+	"wipe.confirm": "Esto borrará para siempre todos los datos de `%s`: conversaciones, resúmenes, transcripciones, recordatorios, tareas, memorias, suscripciones y sus propios informes.",
+	"wipe.failed":  "❌ No se pudieron borrar los datos de `%s`.",
+	"wipe.done":    "🧹 Borrados todos los datos de `%s`: %d conversaciones y %d registros.",
+
+	// /mcplog
+	"mcplog.unavailable": "⚠️ El registro de los servidores MCP no está disponible en este bot.",
+	"mcplog.usage":       "Uso: `/mcplog <servidor> <nivel>`, p. ej. `/mcplog github debug`. Niveles: %s.",
+	"mcplog.denied":      "⛔ Solo los administradores pueden cambiar el nivel de registro de los servidores MCP.",
+	"mcplog.unsupported": "❌ El servidor MCP `%s` no admite registro.",
+	"mcplog.failed":      "❌ No se pudo cambiar el nivel de registro de `%s`. Revisa el nombre del servidor.",
+	"mcplog.done":        "🪵 `%s` ahora registra desde `%s`, hasta que se reinicie.",
+
+	// /mcp
+	"mcp.unavailable":    "⚠️ Agregar y quitar servidores MCP no está disponible para este bot.",
+	"mcp.usage":          "Uso: `/mcp add <nombre> <comando|url> [args...]`, `/mcp remove <nombre>` o `/mcp refresh <nombre>`, p. ej. `/mcp add notes npx -y @acme/notes-mcp`.",
+	"mcp.denied":         "⛔ Solo los administradores pueden agregar o quitar servidores MCP.",
+	"mcp.invalid_name":   "❌ `%s` no es un nombre de servidor válido. Usa letras, dígitos, `.`, `-` y `_`.",
+	"mcp.exists":         "❌ Ya hay un servidor MCP llamado `%s`. Quítalo primero para reemplazarlo.",
+	"mcp.add_failed":     "❌ No pude iniciar `%s`: %v",
+	"mcp.added":          "🔌 `%s` está conectado; sus herramientas están disponibles para %s desde el próximo mensaje.",
+	"mcp.unknown":        "❌ No hay ningún servidor MCP llamado `%s`.",
+	"mcp.remove_failed":  "❌ No pude quitar `%s`. Revisa los registros.",
+	"mcp.removed":        "🔌 `%s` se detuvo y sus herramientas se quitaron.",
+	"mcp.refresh_failed": "❌ No pude actualizar `%s`: %v",
+	"mcp.refreshed":      "🔄 `%s` tiene %d herramientas; los agentes usan las nuevas definiciones desde el próximo mensaje.",
+
+	// /dbstats
+	"dbstats.none":   "📭 Aún no se han registrado consultas a la base de datos.",
+	"dbstats.header": "🗄 **Consultas a la base de datos**: %d en %s, %d lentas\n\n",
+	"dbstats.slow":   " ⚠️ %d lentas",
+	"dbstats.footer": "\nLos tipos más lentos primero, desde el arranque. Las consultas lentas se registran con su SQL (umbral: `slowQueryMs`).",
+
+	// /mcpstats
+	"mcpstats.none":   "📭 Aún no se han registrado llamadas a herramientas MCP.",
+	"mcpstats.header": "🔌 **Llamadas a herramientas MCP**: %d, %d fallidas\n",
+	"mcpstats.server": "\n**%s** — %d llamadas, promedio %s, %d%% fallidas\n",
+	"mcpstats.failed": " ⚠️ %d fallidas (%d%%)",
+	"mcpstats.footer": "\nDesde el arranque, las herramientas más lentas primero. Un servidor cuyas llamadas son lentas o fallan a menudo se puede detener con `/mcp remove <nombre>`.",
+
+	// /set
+	"set.usage":                      "Uso: `/set` para listar los ajustes, `/set <clave> <valor>` para cambiar uno, `/set <clave> reset` para volver a config.json.",
+	"set.unknown":                    "❓ Ajuste desconocido `%s`. Envía `/set` para ver la lista.",
+	"set.denied":                     "⛔ Solo los administradores pueden cambiar los ajustes.",
+	"set.invalid":                    "❌ Valor no válido para `%s`: %v",
+	"set.failed":                     "❌ No se pudieron actualizar los ajustes.",
+	"set.done":                       "✅ `%s` cambiado a `%s`. Se aplica desde el próximo mensaje.",
+	"set.reset":                      "↩️ `%s` vuelve al valor de config.json (`%s`).",
+	"set.header":                     "⚙️ **Ajustes en tiempo de ejecución**\n\n",
+	"set.overridden":                 " (cambiado)",
+	"set.footer":                     "\nCambiar: `/set <clave> <valor>` · Deshacer: `/set <clave> reset`",
+	"set.desc_compressionThreshold":  "parte del límite de tokens que activa el resumen de la conversación",
+	"set.desc_flashTokenLimit":       "tamaño de contexto del modelo rápido, en tokens",
+	"set.desc_proTokenLimit":         "tamaño de contexto del modelo avanzado, en tokens",
+	"set.desc_maxConcurrentMissions": "misiones de investigación simultáneas (0 = sin límite)",
+	"set.desc_maxSessionEvents":      "eventos recientes de la conversación cargados por mensaje (0 = predeterminado, negativo = todos)",
+	"set.desc_defaultModel":          "`auto` enruta cada mensaje, `flash` o `pro` usan siempre ese modelo",
+
+	// /prompts, /prompt
+	"prompts.unavailable": "⚠️ Los prompts MCP no están disponibles para este bot.",
+	"prompts.none":        "📭 Ningún servidor MCP ofrece prompts.",
+	"prompts.header":      "📝 **Prompts MCP (%d)**\n",
+	"prompts.args":        " — %s",
+	"prompts.footer":      "\nEjecuta uno con `/prompt <servidor>/<nombre> [arg=valor ...]` (* = obligatorio).",
+	"prompt.usage":        "Uso: `/prompt <servidor>/<nombre> [arg=valor ...]`\nEjemplo: `/prompt github/review-pr repo=owner/repo number=42`\nConsulta la lista con `/prompts`.\n\n`/prompt set [--replace] <instrucciones>`, `/prompt show` y `/prompt clear` dan a esta conversación su propio prompt de sistema.",
+	"prompt.set_usage":    "Uso: `/prompt set [--replace] <instrucciones>`\nEjemplo: `/prompt set Responde como un SRE escueto y prefiere comandos de una línea.`\nLas instrucciones se añaden al prompt de sistema; con `--replace` lo sustituyen.",
+	"prompt.set":          "📝 Prompt guardado: esta conversación lo sigue además del prompt de sistema. Míralo con `/prompt show` y quítalo con `/prompt clear`.",
+	"prompt.replaced":     "📝 Prompt guardado: esta conversación lo usa en lugar del prompt de sistema. Míralo con `/prompt show` y quítalo con `/prompt clear`.",
+	"prompt.show_append":  "📝 **Prompt de esta conversación** (se añade al prompt de sistema, guardado el %s):\n%s",
+	"prompt.show_replace": "📝 **Prompt de esta conversación** (sustituye al prompt de sistema, guardado el %s):\n%s",
+	"prompt.none":         "Esta conversación usa solo el prompt de sistema. Dale uno propio con `/prompt set <instrucciones>`.",
+	"prompt.cleared":      "🧹 Prompt borrado: esta conversación vuelve a usar el prompt de sistema.",
+	"prompt.save_failed":  "❌ No se pudo guardar el prompt.",
+	"prompt.load_failed":  "❌ No se pudo cargar el prompt.",
+	"prompt.unknown":      "❌ No hay ningún prompt MCP llamado `%s`. Consulta `/prompts`.",
+	"prompt.missing":      "❌ Falta el argumento obligatorio `%s` para `%s`.",
+	"prompt.failed":       "❌ No se pudo cargar el prompt `%s` desde su servidor MCP.",
+
+	// /watch, /unwatch
+	"watch.unavailable": "⚠️ La vigilancia de recursos no está disponible para este bot.",
+	"watch.usage":       "Uso: `/watch <servidor> <uri-del-recurso>`\nEjemplo: `/watch filesystem file:///app/logs/combined.log`",
+	"watch.unsupported": "❌ El servidor MCP `%s` no admite suscripciones a recursos.",
+	"watch.failed":      "❌ No pude vigilar %s. Revisa el nombre del servidor y la URI.",
+	"watch.exists":      "ℹ️ Ya estás vigilando %s",
+	"watch.done":        "👀 Vigilando %s en `%s`. Te avisaré cuando cambie.",
+	"watch.list_failed": "❌ No pude obtener los recursos vigilados.",
+	"watch.none":        "📭 No hay recursos vigilados. Agrega uno con `/watch <servidor> <uri>`.",
+	"watch.header":      "👀 **Recursos vigilados (%d)**\n\n",
+	"watch.footer":      "\nDeja de vigilar uno con `/unwatch <id>`.",
+	"watch.updated":     "🔔 **%s** cambió (`%s`).",
+	"unwatch.usage":     "Uso: `/unwatch <id>` (consulta `/watch` para ver los IDs)",
+	"unwatch.failed":    "❌ No pude eliminar la vigilancia.",
+	"unwatch.not_found": "❌ No hay ninguna vigilancia que coincida con `%s`.",
+	"unwatch.done":      "🗑 Dejé de vigilar %s.",
+
+	// /history
+	"history.usage":         "Uso: `/history <palabras>`: busca en informes y titulares anteriores\n`/history --chat <palabras>`: busca en nuestras conversaciones anteriores\nEjemplo: `/history kubernetes versión`",
+	"history.failed":        "❌ No se pudo buscar en el historial.",
+	"history.no_match":      "📭 Nada en los informes o titulares anteriores coincide con **%s**.",
+	"history.header":        "🔎 **Historial: %s**\n",
+	"history.briefings":     "\n**Informes (%d)**\n",
+	"history.headlines":     "\n**Titulares (%d)**\n",
+	"history.chat":          "\n**Conversaciones (%d)**\n",
+	"history.chat_no_match": "📭 Nada en nuestras conversaciones anteriores coincide con **%s**.",
+
+	// /feedback
+	"feedback.usage":        "Uso: `/feedback <texto>`\nEjemplo: `/feedback El informe diario debería incluir noticias de Rust`",
+	"feedback.failed":       "❌ No se pudo guardar el comentario.",
+	"feedback.saved":        "🙏 ¡Gracias! Comentario `#%d` registrado.",
+	"feedback.issue_opened": "🐛 Abrí un issue para él: %s",
+	"feedback.issue_failed": "⚠️ Tu comentario se guardó, pero no pude abrir un issue en GitHub.",
+
+	// MCP server questions (elicitation)
+	"elicit.header":   "❓ `%s` (%s) necesita información:\n%s\n\nResponde `cancel` para detenerlo o `decline` para rechazarlo.",
+	"elicit.confirm":  "Responde `sí` para continuar o `no` para rechazarlo.",
+	"elicit.choices":  "Elige una opción: %s",
+	"elicit.yes_no":   "Responde `sí` o `no`.",
+	"elicit.optional": "_Opcional: responde `skip` para omitirlo._",
+	"elicit.invalid":  "⚠️ %s. Inténtalo de nuevo.",
+	"elicit.sent":     "✅ Respuestas enviadas a `%s`.",
+	"elicit.timeout":  "⌛ Sin respuesta a tiempo; la pregunta se canceló.",
+
+	// MCP tool progress and attachments
+	"progress.update":    "⏳ `%s` (%s) sigue en curso%s",
+	"attachment.caption": "📎 De `%s` (%s)",
+
+	// /research, /jules
+	"research.usage":       "Indica un tema. Uso: `/research <tema>`",
+	"research.starting":    "🔬 Iniciando investigación sobre: **%s**...",
+	"research.failed":      "❌ Falló la investigación. No pude completar la misión.",
+	"jules.usage":          "Uso: `/jules <owner/repo> <descripción de la tarea>`, o `/jules status` para ver tus sesiones de Jules",
+	"jules.confirm":        "Esto delegará **%s** a Jules, que trabajará en **%s** y podría abrir un pull request.",
+	"jules.delegating":     "🤖 Delegando a Jules para **%s**: %s",
+	"jules.failed":         "❌ Falló la delegación. No pude pasarle la tarea a Jules.",
+	"jules.status_header":  "🤖 **Sesiones de Jules** (%d):\n",
+	"jules.status_none":    "🤖 Aún no has iniciado ninguna sesión de Jules. Usa `/jules <owner/repo> <tarea>`.",
+	"jules.status_failed":  "❌ No pude cargar tus sesiones de Jules.",
+	"jules.status_pr":      "PR [%s](%s)",
+	"jules.pr_opened":      "🔀 Jules abrió el PR %s en **%s**: %s\n%s",
+	"jules.needs_input":    "🙋 Jules necesita tu respuesta en **%s**: %s",
+	"jules.session_failed": "❌ Jules no pudo terminar la tarea en **%s**: %s",
+	"jules.completed":      "✅ Jules terminó la tarea en **%s**: %s",
+}
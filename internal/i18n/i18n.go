@@ -0,0 +1,88 @@
+// Package i18n holds the catalog of user-facing bot messages and resolves
+// them for a locale, falling back to English for anything untranslated.
+// Prompts sent to the model are not part of the catalog; they stay
+// configurable in config.json.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is used when neither the chat nor the config selects one.
+const DefaultLocale = "en"
+
+// catalogs maps a locale to its messages. English must contain every key.
+var catalogs = map[string]map[string]string{
+	"en": en,
+	"es": es,
+}
+
+// names are the native display names of the supported locales.
+var names = map[string]string{
+	"en": "English",
+	"es": "Español",
+}
+
+// Normalize maps user input such as "ES", "es-MX" or "es_MX" to a supported
+// locale. It reports false if the language is not supported.
+func Normalize(locale string) (string, bool) {
+	l := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(l, "-_"); i >= 0 {
+		l = l[:i]
+	}
+	_, ok := catalogs[l]
+	return l, ok
+}
+
+// Supported returns the supported locales, sorted.
+func Supported() []string {
+	out := make([]string, 0, len(catalogs))
+	for l := range catalogs {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Name returns the display name of a locale.
+func Name(locale string) string {
+	if n, ok := names[locale]; ok {
+		return n
+	}
+	return locale
+}
+
+// T returns the message for key in the given locale, formatted with args.
+// Missing translations fall back to English, and unknown keys to the key
+// itself so a gap is visible rather than silent.
+func T(locale, key string, args ...any) string {
+	msg, ok := catalogs[locale][key]
+	if !ok {
+		if msg, ok = en[key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+type localeKey struct{}
+
+// WithLocale returns a context carrying the locale for messages produced
+// while handling a request.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale stored by WithLocale, or DefaultLocale.
+func FromContext(ctx context.Context) string {
+	if l, ok := ctx.Value(localeKey{}).(string); ok && l != "" {
+		return l
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,52 @@
+package i18n
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var verb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for key, msg := range catalog {
+			base, ok := en[key]
+			if !assert.True(t, ok, "%s: key %q is not in the English catalog", locale, key) {
+				continue
+			}
+			assert.Equal(t, verb.FindAllString(base, -1), verb.FindAllString(msg, -1),
+				"%s: %q must use the same format verbs as English", locale, key)
+		}
+		if locale != DefaultLocale {
+			assert.NotEmpty(t, names[locale], "%s has no display name", locale)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{"es": "es", "ES": "es", "es-MX": "es", "en_GB": "en"} {
+		got, ok := Normalize(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, ok := Normalize("tlh")
+	assert.False(t, ok)
+	assert.True(t, slices.Contains(Supported(), "es"))
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "🗑 Forgotten `#3`.", T("en", "forget.done", 3))
+	assert.Equal(t, "🗑 Olvidado `#3`.", T("es", "forget.done", 3))
+	// Unknown locales and untranslated keys fall back to English.
+	assert.Equal(t, "🗑 Forgotten `#3`.", T("fr", "forget.done", 3))
+	assert.Equal(t, "missing.key", T("es", "missing.key"))
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, DefaultLocale, FromContext(context.Background()))
+	assert.Equal(t, "es", FromContext(WithLocale(context.Background(), "es")))
+}
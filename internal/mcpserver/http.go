@@ -0,0 +1,80 @@
+package mcpserver
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"net/url"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// HTTPHandler returns a Streamable HTTP handler for server. With a token,
+// requests must carry it as a bearer token. Without one, requests must
+// name a loopback Host and come from no Origin or a loopback one, so a web
+// page can't reach a server on 127.0.0.1 through DNS rebinding.
+func HTTPHandler(server *officialmcp.Server, token string) http.Handler {
+	h := officialmcp.NewStreamableHTTPHandler(func(*http.Request) *officialmcp.Server { return server }, nil)
+	if token != "" {
+		return requireToken(token, h)
+	}
+	return requireLoopback(h)
+}
+
+// requireToken rejects requests without "Authorization: Bearer <token>".
+func requireToken(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireLoopback rejects requests whose Host, or Origin if set, is not a
+// loopback name.
+func requireLoopback(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLoopbackHost(r.Host) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || !isLoopbackHost(u.Host) {
+				http.Error(w, "forbidden origin", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IsLoopback reports whether addr ("host:port") listens only on a loopback
+// interface.
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	return err == nil && isLoopbackName(host)
+}
+
+// isLoopbackHost reports whether a Host header value, with or without a
+// port, names the local machine.
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return host != "" && isLoopbackName(host)
+}
+
+func isLoopbackName(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	if len(host) > 1 && host[0] == '[' && host[len(host)-1] == ']' {
+		host = host[1 : len(host)-1]
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
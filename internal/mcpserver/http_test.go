@@ -0,0 +1,65 @@
+package mcpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	t.Parallel()
+	database, err := db.InitDB(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+	server := New(&fakeMissioner{}, database, Options{Location: time.UTC})
+
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+	do := func(h http.Handler, host, origin, auth string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(initialize))
+		req.Host = host
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	open := HTTPHandler(server, "")
+	assert.Equal(t, http.StatusForbidden, do(open, "evil.example", "", ""))
+	assert.Equal(t, http.StatusForbidden, do(open, "evil.example:8765", "", ""))
+	assert.Equal(t, http.StatusForbidden, do(open, "127.0.0.1:8765", "http://evil.example", ""))
+	assert.Equal(t, http.StatusOK, do(open, "127.0.0.1:8765", "", ""))
+	assert.Equal(t, http.StatusOK, do(open, "localhost:8765", "http://localhost:3000", ""))
+	assert.Equal(t, http.StatusOK, do(open, "[::1]:8765", "", ""))
+
+	authed := HTTPHandler(server, "s3cret")
+	assert.Equal(t, http.StatusUnauthorized, do(authed, "mcp.example", "", ""))
+	assert.Equal(t, http.StatusOK, do(authed, "mcp.example", "", "Bearer s3cret"))
+}
+
+func TestIsLoopback(t *testing.T) {
+	t.Parallel()
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8765": true,
+		"localhost:8765": true,
+		"[::1]:8765":     true,
+		"0.0.0.0:8765":   false,
+		":8765":          false,
+		"10.0.0.5:8765":  false,
+		"127.0.0.1":      false,
+	} {
+		assert.Equal(t, want, IsLoopback(addr), addr)
+	}
+}
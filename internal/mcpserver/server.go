@@ -0,0 +1,282 @@
+// Package mcpserver exposes ravenbot's own capabilities (research missions,
+// briefing search, reminders and memory) as an MCP server, so other agents
+// such as Claude Desktop can drive it.
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/raythurman2386/ravenbot/internal/timeparse"
+)
+
+// DefaultSession is the chat session whose reminders and memories the
+// server works with unless told otherwise.
+const DefaultSession = "mcp"
+
+// recentBriefings is how many briefings the briefings resource lists.
+const recentBriefings = 5
+
+// Missioner runs research missions; *agent.Agent implements it.
+type Missioner interface {
+	RunMission(ctx context.Context, prompt string) (string, error)
+}
+
+// Options configures the server.
+type Options struct {
+	// SessionID is the session reminders and memories belong to. Reminders
+	// have no origin chat, so the running bot delivers them to every
+	// notifier.
+	SessionID string
+	// Location resolves reminder times such as "tomorrow 9am".
+	Location *time.Location
+	now      func() time.Time
+}
+
+type server struct {
+	bot  Missioner
+	db   *db.DB
+	opts Options
+}
+
+// New returns an MCP server backed by bot and database.
+func New(bot Missioner, database *db.DB, opts Options) *officialmcp.Server {
+	if opts.SessionID == "" {
+		opts.SessionID = DefaultSession
+	}
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.now == nil {
+		opts.now = time.Now
+	}
+	s := &server{bot: bot, db: database, opts: opts}
+
+	srv := officialmcp.NewServer(&officialmcp.Implementation{Name: "ravenbot"}, nil)
+	officialmcp.AddTool(srv, &officialmcp.Tool{
+		Name:        "research",
+		Description: "Research a topic in depth with ravenbot's research assistant and return a technical report. The report is saved as a briefing. Takes minutes.",
+	}, s.research)
+	officialmcp.AddTool(srv, &officialmcp.Tool{
+		Name:        "search_briefings",
+		Description: "Full-text search of ravenbot's saved briefings and research reports, best match first.",
+	}, s.searchBriefings)
+	officialmcp.AddTool(srv, &officialmcp.Tool{
+		Name:        "set_reminder",
+		Description: `Set a reminder that ravenbot delivers to its chats. "when" is a time such as "in 2 hours", "tomorrow 9am" or "2026-03-10 8am".`,
+	}, s.setReminder)
+	officialmcp.AddTool(srv, &officialmcp.Tool{
+		Name:        "list_reminders",
+		Description: "List the pending reminders.",
+	}, s.listReminders)
+	officialmcp.AddTool(srv, &officialmcp.Tool{
+		Name:        "remember",
+		Description: "Store a fact in ravenbot's long-term memory.",
+	}, s.remember)
+	officialmcp.AddTool(srv, &officialmcp.Tool{
+		Name:        "recall",
+		Description: "Search ravenbot's long-term memory. Every word of the query must match; an empty query lists the newest memories.",
+	}, s.recall)
+
+	srv.AddResource(&officialmcp.Resource{
+		URI:         "ravenbot://briefings/recent",
+		Name:        "recent-briefings",
+		Description: "The most recent briefings.",
+		MIMEType:    "text/markdown",
+	}, s.readBriefings)
+	srv.AddResource(&officialmcp.Resource{
+		URI:         "ravenbot://memories",
+		Name:        "memories",
+		Description: "The newest facts in ravenbot's long-term memory.",
+		MIMEType:    "text/markdown",
+	}, s.readMemories)
+	return srv
+}
+
+type researchInput struct {
+	Topic string `json:"topic" jsonschema:"the topic to research"`
+}
+
+type researchOutput struct {
+	Report string `json:"report"`
+}
+
+func (s *server) research(ctx context.Context, _ *officialmcp.CallToolRequest, in researchInput) (*officialmcp.CallToolResult, researchOutput, error) {
+	topic := strings.TrimSpace(in.Topic)
+	if topic == "" {
+		return nil, researchOutput{}, errors.New("topic is required")
+	}
+	slog.Info("MCP research requested", "topic", topic)
+	prompt := fmt.Sprintf("Research the following topic in depth and provide a technical report: %s", topic)
+	report, err := s.bot.RunMission(ctx, prompt)
+	if err != nil {
+		return nil, researchOutput{}, fmt.Errorf("research failed: %w", err)
+	}
+	if _, err := s.db.AddSessionBriefing(ctx, s.opts.SessionID, report, topic, nil); err != nil && !errors.Is(err, db.ErrDuplicateBriefing) {
+		slog.Error("Failed to save briefing", "error", err)
+	}
+	return nil, researchOutput{Report: report}, nil
+}
+
+type searchInput struct {
+	Query string `json:"query" jsonschema:"words that must all appear"`
+	Limit int    `json:"limit,omitempty" jsonschema:"maximum number of matches (default 5)"`
+}
+
+type briefingMatch struct {
+	ID        int64     `json:"id"`
+	Snippet   string    `json:"snippet"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type searchOutput struct {
+	Matches []briefingMatch `json:"matches"`
+}
+
+func (s *server) searchBriefings(ctx context.Context, _ *officialmcp.CallToolRequest, in searchInput) (*officialmcp.CallToolResult, searchOutput, error) {
+	matches, err := s.db.SearchBriefings(ctx, s.opts.SessionID, in.Query, in.Limit)
+	if err != nil {
+		return nil, searchOutput{}, err
+	}
+	out := searchOutput{Matches: make([]briefingMatch, 0, len(matches))}
+	for _, m := range matches {
+		out.Matches = append(out.Matches, briefingMatch{ID: m.ID, Snippet: m.Snippet, CreatedAt: m.CreatedAt})
+	}
+	return nil, out, nil
+}
+
+type reminderInput struct {
+	Message string `json:"message" jsonschema:"what to remind about"`
+	When    string `json:"when" jsonschema:"when to deliver it, e.g. \"in 30m\" or \"tomorrow 9am\""`
+}
+
+type reminder struct {
+	ID       int64     `json:"id,omitempty"`
+	Message  string    `json:"message"`
+	RemindAt time.Time `json:"remind_at"`
+}
+
+func (s *server) setReminder(ctx context.Context, _ *officialmcp.CallToolRequest, in reminderInput) (*officialmcp.CallToolResult, reminder, error) {
+	message := strings.TrimSpace(in.Message)
+	if message == "" {
+		return nil, reminder{}, errors.New("message is required")
+	}
+	now := s.opts.now().In(s.opts.Location)
+	remindAt, rest, err := timeparse.Parse(in.When, now)
+	if err != nil {
+		return nil, reminder{}, fmt.Errorf("invalid time %q: %w", in.When, err)
+	}
+	if rest != "" {
+		return nil, reminder{}, fmt.Errorf("invalid time %q: unexpected %q", in.When, rest)
+	}
+	if !remindAt.After(now) {
+		return nil, reminder{}, fmt.Errorf("%s is in the past", remindAt.Format(time.RFC3339))
+	}
+	if err := s.db.AddReminder(ctx, s.opts.SessionID, message, remindAt); err != nil {
+		return nil, reminder{}, err
+	}
+	slog.Info("MCP reminder set", "session", s.opts.SessionID, "remindAt", remindAt)
+	return nil, reminder{Message: message, RemindAt: remindAt}, nil
+}
+
+type remindersOutput struct {
+	Reminders []reminder `json:"reminders"`
+}
+
+func (s *server) listReminders(ctx context.Context, _ *officialmcp.CallToolRequest, _ struct{}) (*officialmcp.CallToolResult, remindersOutput, error) {
+	pending, err := s.db.GetSessionReminders(ctx, s.opts.SessionID)
+	if err != nil {
+		return nil, remindersOutput{}, err
+	}
+	out := remindersOutput{Reminders: make([]reminder, 0, len(pending))}
+	for _, r := range pending {
+		out.Reminders = append(out.Reminders, reminder{ID: r.ID, Message: r.Message, RemindAt: r.RemindAt})
+	}
+	return nil, out, nil
+}
+
+type rememberInput struct {
+	Content string `json:"content" jsonschema:"the fact to remember"`
+}
+
+type memory struct {
+	ID        int64     `json:"id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+func (s *server) remember(ctx context.Context, _ *officialmcp.CallToolRequest, in rememberInput) (*officialmcp.CallToolResult, memory, error) {
+	content := strings.TrimSpace(in.Content)
+	if content == "" {
+		return nil, memory{}, errors.New("content is required")
+	}
+	id, err := s.db.AddMemory(ctx, s.opts.SessionID, content)
+	if err != nil {
+		return nil, memory{}, err
+	}
+	return nil, memory{ID: id, Content: content}, nil
+}
+
+type recallInput struct {
+	Query string `json:"query,omitempty" jsonschema:"words that must all appear"`
+	Limit int    `json:"limit,omitempty" jsonschema:"maximum number of memories (default 20)"`
+}
+
+type recallOutput struct {
+	Memories []memory `json:"memories"`
+}
+
+func (s *server) recall(ctx context.Context, _ *officialmcp.CallToolRequest, in recallInput) (*officialmcp.CallToolResult, recallOutput, error) {
+	found, err := s.db.SearchMemories(ctx, s.opts.SessionID, in.Query, in.Limit)
+	if err != nil {
+		return nil, recallOutput{}, err
+	}
+	out := recallOutput{Memories: make([]memory, 0, len(found))}
+	for _, m := range found {
+		out.Memories = append(out.Memories, memory{ID: m.ID, Content: m.Content, CreatedAt: m.CreatedAt})
+	}
+	return nil, out, nil
+}
+
+func (s *server) readBriefings(ctx context.Context, req *officialmcp.ReadResourceRequest) (*officialmcp.ReadResourceResult, error) {
+	briefings, err := s.db.GetRecentBriefings(ctx, recentBriefings)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	for i, b := range briefings {
+		if i > 0 {
+			sb.WriteString("\n\n---\n\n")
+		}
+		title := b.Topic
+		if title == "" {
+			title = "Briefing"
+		}
+		fmt.Fprintf(&sb, "## %s (#%d, %s)\n\n%s", title, b.ID, b.CreatedAt, b.Content)
+	}
+	return markdownResult(req.Params.URI, sb.String()), nil
+}
+
+func (s *server) readMemories(ctx context.Context, req *officialmcp.ReadResourceRequest) (*officialmcp.ReadResourceResult, error) {
+	memories, err := s.db.SearchMemories(ctx, s.opts.SessionID, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	for _, m := range memories {
+		fmt.Fprintf(&sb, "- %s\n", m.Content)
+	}
+	return markdownResult(req.Params.URI, sb.String()), nil
+}
+
+func markdownResult(uri, text string) *officialmcp.ReadResourceResult {
+	return &officialmcp.ReadResourceResult{Contents: []*officialmcp.ResourceContents{
+		{URI: uri, MIMEType: "text/markdown", Text: text},
+	}}
+}
@@ -0,0 +1,149 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	officialmcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/raythurman2386/ravenbot/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMissioner struct {
+	report string
+	err    error
+	prompt string
+}
+
+func (m *fakeMissioner) RunMission(_ context.Context, prompt string) (string, error) {
+	m.prompt = prompt
+	return m.report, m.err
+}
+
+var testNow = time.Date(2026, 3, 9, 12, 0, 0, 0, time.UTC)
+
+func connect(t *testing.T, bot Missioner) (*officialmcp.ClientSession, *db.DB) {
+	t.Helper()
+	database, err := db.InitDB(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+
+	server := New(bot, database, Options{Location: time.UTC, now: func() time.Time { return testNow }})
+	clientTransport, serverTransport := officialmcp.NewInMemoryTransports()
+	ctx := context.Background()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ss.Close() })
+	cs, err := officialmcp.NewClient(&officialmcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cs.Close() })
+	return cs, database
+}
+
+// call runs a tool and decodes its structured result into out.
+func call(t *testing.T, cs *officialmcp.ClientSession, name string, args map[string]any, out any) *officialmcp.CallToolResult {
+	t.Helper()
+	res, err := cs.CallTool(context.Background(), &officialmcp.CallToolParams{Name: name, Arguments: args})
+	require.NoError(t, err)
+	if out != nil && !res.IsError {
+		raw, err := json.Marshal(res.StructuredContent)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(raw, out))
+	}
+	return res
+}
+
+func TestServer_ListsTools(t *testing.T) {
+	cs, _ := connect(t, &fakeMissioner{})
+	res, err := cs.ListTools(context.Background(), nil)
+	require.NoError(t, err)
+	var names []string
+	for _, tool := range res.Tools {
+		names = append(names, tool.Name)
+	}
+	assert.ElementsMatch(t, []string{"research", "search_briefings", "set_reminder", "list_reminders", "remember", "recall"}, names)
+}
+
+func TestServer_Research(t *testing.T) {
+	bot := &fakeMissioner{report: "# Go generics\n\nThey work."}
+	cs, database := connect(t, bot)
+
+	var out researchOutput
+	res := call(t, cs, "research", map[string]any{"topic": "Go generics"}, &out)
+	require.False(t, res.IsError)
+	assert.Equal(t, bot.report, out.Report)
+	assert.Contains(t, bot.prompt, "Go generics")
+
+	briefings, err := database.GetRecentBriefings(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, briefings, 1)
+	assert.Equal(t, "Go generics", briefings[0].Topic, "the report is saved as a briefing")
+
+	var found searchOutput
+	call(t, cs, "search_briefings", map[string]any{"query": "generics"}, &found)
+	require.Len(t, found.Matches, 1)
+	assert.Equal(t, briefings[0].ID, found.Matches[0].ID)
+
+	bot.err = errors.New("model unavailable")
+	res = call(t, cs, "research", map[string]any{"topic": "Rust"}, nil)
+	assert.True(t, res.IsError)
+}
+
+func TestServer_Reminders(t *testing.T) {
+	cs, database := connect(t, &fakeMissioner{})
+
+	var set reminder
+	res := call(t, cs, "set_reminder", map[string]any{"message": "stand up", "when": "in 2 hours"}, &set)
+	require.False(t, res.IsError)
+	assert.True(t, set.RemindAt.Equal(testNow.Add(2*time.Hour)))
+
+	pending, err := database.GetSessionReminders(context.Background(), DefaultSession)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "stand up", pending[0].Message)
+
+	var list remindersOutput
+	call(t, cs, "list_reminders", nil, &list)
+	require.Len(t, list.Reminders, 1)
+	assert.Equal(t, pending[0].ID, list.Reminders[0].ID)
+
+	for _, when := range []string{"whenever", "in 2 hours please", "2026-03-01 8am"} {
+		res = call(t, cs, "set_reminder", map[string]any{"message": "x", "when": when}, nil)
+		assert.True(t, res.IsError, when)
+	}
+}
+
+func TestServer_Memory(t *testing.T) {
+	cs, _ := connect(t, &fakeMissioner{})
+
+	call(t, cs, "remember", map[string]any{"content": "The staging DB is on port 5433"}, nil)
+	call(t, cs, "remember", map[string]any{"content": "Deploys happen on Tuesdays"}, nil)
+
+	var out recallOutput
+	call(t, cs, "recall", map[string]any{"query": "staging port"}, &out)
+	require.Len(t, out.Memories, 1)
+	assert.Equal(t, "The staging DB is on port 5433", out.Memories[0].Content)
+
+	res, err := cs.ReadResource(context.Background(), &officialmcp.ReadResourceParams{URI: "ravenbot://memories"})
+	require.NoError(t, err)
+	require.Len(t, res.Contents, 1)
+	assert.Contains(t, res.Contents[0].Text, "Deploys happen on Tuesdays")
+	assert.Contains(t, res.Contents[0].Text, "port 5433")
+}
+
+func TestServer_BriefingsResource(t *testing.T) {
+	cs, database := connect(t, &fakeMissioner{})
+	_, err := database.AddBriefing(context.Background(), "Kernel 7.0 released.", "linux", nil)
+	require.NoError(t, err)
+
+	res, err := cs.ReadResource(context.Background(), &officialmcp.ReadResourceParams{URI: "ravenbot://briefings/recent"})
+	require.NoError(t, err)
+	require.Len(t, res.Contents, 1)
+	assert.Equal(t, "text/markdown", res.Contents[0].MIMEType)
+	assert.Contains(t, res.Contents[0].Text, "## linux")
+	assert.Contains(t, res.Contents[0].Text, "Kernel 7.0 released.")
+}
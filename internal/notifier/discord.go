@@ -1,6 +1,7 @@
 package notifier
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log/slog"
@@ -28,19 +29,40 @@ func NewDiscordNotifier(token string, channelID string) (*DiscordNotifier, error
 }
 
 func (d *DiscordNotifier) Send(ctx context.Context, message string) error {
+	return d.SendTo(ctx, d.channelID, message)
+}
+
+// SendTo sends a message to the given channel.
+func (d *DiscordNotifier) SendTo(ctx context.Context, channelID, message string) error {
 	// Discord has a 2000 character limit
 	const limit = 1900
 
 	chunks := splitMessage(message, limit)
 	for i, chunk := range chunks {
-		if _, err := d.session.ChannelMessageSend(d.channelID, chunk); err != nil {
-			return fmt.Errorf("failed to send discord message chunk %d/%d to channel %s: %w", i+1, len(chunks), d.channelID, err)
+		if _, err := d.session.ChannelMessageSend(channelID, chunk); err != nil {
+			return fmt.Errorf("failed to send discord message chunk %d/%d to channel %s: %w", i+1, len(chunks), channelID, err)
 		}
 	}
 
 	return nil
 }
 
+// SendFile uploads data as an attachment to the configured channel.
+func (d *DiscordNotifier) SendFile(ctx context.Context, name, contentType string, data []byte, caption string) error {
+	_, err := d.session.ChannelMessageSendComplex(d.channelID, &discordgo.MessageSend{
+		Content: caption,
+		Files: []*discordgo.File{{
+			Name:        name,
+			ContentType: contentType,
+			Reader:      bytes.NewReader(data),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send discord file: %w", err)
+	}
+	return nil
+}
+
 func (d *DiscordNotifier) Name() string {
 	return "Discord"
 }
@@ -71,8 +93,9 @@ func (d *DiscordNotifier) StartTyping(ctx context.Context) func() {
 	return cancel
 }
 
-// StartListener begins listening for messages on Discord.
-func (d *DiscordNotifier) StartListener(ctx context.Context, handler func(channelID string, text string)) {
+// StartListener begins listening for messages on Discord, passing the
+// files sent with a message as attachments.
+func (d *DiscordNotifier) StartListener(ctx context.Context, handler func(channelID string, text string, attachments []Attachment)) {
 	d.session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
 		// Ignore all messages created by the bot itself
 		if m.Author.ID == s.State.User.ID {
@@ -92,8 +115,17 @@ func (d *DiscordNotifier) StartListener(ctx context.Context, handler func(channe
 		content = strings.ReplaceAll(content, botMentionNick, "")
 		content = strings.TrimSpace(content)
 
+		var attachments []Attachment
+		for _, a := range m.Attachments {
+			attachments = append(attachments, Attachment{
+				Name:        a.Filename,
+				ContentType: a.ContentType,
+				Size:        int64(a.Size),
+				url:         func(context.Context) (string, error) { return a.URL, nil },
+			})
+		}
 		if content != "" {
-			handler(m.ChannelID, content)
+			handler(m.ChannelID, content, attachments)
 		}
 	})
 
@@ -2,9 +2,18 @@ package notifier
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"strings"
+	"time"
 )
 
+// maxAttachmentBytes caps the size of a file downloaded from a message.
+const maxAttachmentBytes = 20 << 20
+
 // Notifier defines the interface for sending reports to various channels.
 type Notifier interface {
 	Send(ctx context.Context, message string) error
@@ -12,6 +21,104 @@ type Notifier interface {
 	StartTyping(ctx context.Context) func()
 }
 
+// FileSender is implemented by notifiers that can deliver file attachments.
+type FileSender interface {
+	SendFile(ctx context.Context, name, contentType string, data []byte, caption string) error
+}
+
+// ChannelSender is implemented by notifiers that can message a specific
+// chat or channel rather than only their configured one.
+type ChannelSender interface {
+	SendTo(ctx context.Context, channelID, message string) error
+}
+
+type channelKey struct{}
+
+// WithChannel returns a context recording the chat or channel a message
+// came from, so replies sent later can be routed back to it.
+func WithChannel(ctx context.Context, channelID string) context.Context {
+	return context.WithValue(ctx, channelKey{}, channelID)
+}
+
+// ChannelFromContext returns the channel set with WithChannel, or "".
+func ChannelFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(channelKey{}).(string)
+	return id
+}
+
+// Attachment is a file sent with a message. Its content is downloaded only
+// when a command reads it.
+type Attachment struct {
+	Name        string
+	ContentType string // may be empty
+	Size        int64  // 0 if unknown
+	url         func(ctx context.Context) (string, error)
+}
+
+// Download returns the attachment's content. Its errors leave out the
+// file's URL, which for Telegram contains the bot token.
+func (a Attachment) Download(ctx context.Context) ([]byte, error) {
+	if a.Size > maxAttachmentBytes {
+		return nil, fmt.Errorf("%s is larger than %d MB", a.Name, maxAttachmentBytes>>20)
+	}
+	u, err := a.url(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate %s: %w", a.Name, withoutURL(err))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", withoutURL(err))
+	}
+	resp, err := (&http.Client{Timeout: time.Minute}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", a.Name, withoutURL(err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download of %s returned status %d", a.Name, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAttachmentBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", a.Name, err)
+	}
+	if len(data) > maxAttachmentBytes {
+		return nil, fmt.Errorf("%s is larger than %d MB", a.Name, maxAttachmentBytes>>20)
+	}
+	return data, nil
+}
+
+// withoutURL returns the cause of a *url.Error, which quotes the URL.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// NewAttachment returns an attachment whose content is at rawURL; for
+// tests and other sources of files.
+func NewAttachment(name, contentType, rawURL string) Attachment {
+	return Attachment{Name: name, ContentType: contentType, url: func(context.Context) (string, error) { return rawURL, nil }}
+}
+
+type attachmentsKey struct{}
+
+// WithAttachments returns a context carrying the files sent with the
+// message being handled.
+func WithAttachments(ctx context.Context, attachments []Attachment) context.Context {
+	if len(attachments) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, attachmentsKey{}, attachments)
+}
+
+// AttachmentsFromContext returns the files set with WithAttachments.
+func AttachmentsFromContext(ctx context.Context) []Attachment {
+	a, _ := ctx.Value(attachmentsKey{}).([]Attachment)
+	return a
+}
+
 func splitMessage(message string, limit int) []string {
 	var chunks []string
 	for len(message) > limit {
@@ -3,6 +3,7 @@ package notifier
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,12 +29,25 @@ func NewTelegramNotifier(token string, chatID int64) (*TelegramNotifier, error)
 }
 
 func (t *TelegramNotifier) Send(ctx context.Context, message string) error {
+	return t.sendChat(t.chatID, message)
+}
+
+// SendTo sends a message to the chat with the given numeric ID.
+func (t *TelegramNotifier) SendTo(ctx context.Context, channelID, message string) error {
+	chatID, err := strconv.ParseInt(channelID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid telegram chat ID %q: %w", channelID, err)
+	}
+	return t.sendChat(chatID, message)
+}
+
+func (t *TelegramNotifier) sendChat(chatID int64, message string) error {
 	// Telegram has a 4096 character limit
 	const limit = 4000
 
 	chunks := splitMessage(message, limit)
 	for _, chunk := range chunks {
-		msg := tgbotapi.NewMessage(t.chatID, chunk)
+		msg := tgbotapi.NewMessage(chatID, chunk)
 		msg.ParseMode = tgbotapi.ModeMarkdown
 
 		if _, err := t.bot.Send(msg); err != nil {
@@ -48,6 +62,16 @@ func (t *TelegramNotifier) Send(ctx context.Context, message string) error {
 	return nil
 }
 
+// SendFile uploads data as a document to the configured chat.
+func (t *TelegramNotifier) SendFile(ctx context.Context, name, contentType string, data []byte, caption string) error {
+	doc := tgbotapi.NewDocument(t.chatID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	doc.Caption = caption
+	if _, err := t.bot.Send(doc); err != nil {
+		return fmt.Errorf("failed to send telegram document: %w", err)
+	}
+	return nil
+}
+
 func (t *TelegramNotifier) Name() string {
 	return "Telegram"
 }
@@ -76,8 +100,10 @@ func (t *TelegramNotifier) StartTyping(ctx context.Context) func() {
 	return cancel
 }
 
-// StartListener begins listening for messages on Telegram.
-func (t *TelegramNotifier) StartListener(ctx context.Context, handler func(chatID int64, text string)) {
+// StartListener begins listening for messages on Telegram. A document sent
+// with a message, or with the message it replies to, is passed as an
+// attachment; a document's caption is its text.
+func (t *TelegramNotifier) StartListener(ctx context.Context, handler func(chatID int64, text string, attachments []Attachment)) {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
@@ -98,7 +124,10 @@ func (t *TelegramNotifier) StartListener(ctx context.Context, handler func(chatI
 			}
 
 			text := update.Message.Text
-			if update.Message.IsCommand() {
+			if text == "" {
+				text = update.Message.Caption
+			}
+			if strings.HasPrefix(text, "/") {
 				// Strip bot username from command (e.g., /status@botname -> /status)
 				if i := strings.Index(text, "@"); i != -1 {
 					spaceIdx := strings.Index(text, " ")
@@ -120,7 +149,29 @@ func (t *TelegramNotifier) StartListener(ctx context.Context, handler func(chatI
 				}
 			}
 
-			handler(update.Message.Chat.ID, text)
+			var attachments []Attachment
+			for _, m := range []*tgbotapi.Message{update.Message, update.Message.ReplyToMessage} {
+				if m != nil && m.Document != nil {
+					attachments = append(attachments, t.attachment(m.Document))
+				}
+			}
+			if text != "" {
+				handler(update.Message.Chat.ID, text, attachments)
+			}
 		}
 	}
 }
+
+// attachment returns a document as an attachment, resolving its download
+// URL when it is read.
+func (t *TelegramNotifier) attachment(doc *tgbotapi.Document) Attachment {
+	fileID := doc.FileID
+	return Attachment{
+		Name:        doc.FileName,
+		ContentType: doc.MimeType,
+		Size:        int64(doc.FileSize),
+		url: func(context.Context) (string, error) {
+			return t.bot.GetFileDirectURL(fileID)
+		},
+	}
+}
@@ -0,0 +1,138 @@
+// Package schedule evaluates cron expressions on the wall clock of a
+// timezone and runs functions at the times they fire, so "0 0 7 * * *"
+// means 7:00 local time on both sides of a daylight saving change.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/raythurman2386/cronlib"
+)
+
+// Schedule is a parsed cron expression bound to a timezone.
+type Schedule struct {
+	spec     string
+	expr     cronlib.Expression
+	loc      *time.Location
+	interval bool // @every: fixed elapsed time, not wall-clock based
+}
+
+// Parse parses a cron spec for loc (time.Local if nil). It accepts the
+// six-field form with seconds ("0 0 7 * * *"), the classic five-field
+// crontab form ("0 7 * * *"), the @daily style macros and "@every 1h".
+func Parse(spec string, loc *time.Location) (Schedule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	spec = strings.TrimSpace(spec)
+	full := spec
+	if len(strings.Fields(spec)) == 5 {
+		full = "0 " + spec
+	}
+	expr, err := cronlib.Parse(full)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid schedule %q: %w", spec, err)
+	}
+	return Schedule{spec: spec, expr: expr, loc: loc, interval: strings.HasPrefix(spec, "@every ")}, nil
+}
+
+// String returns the spec the schedule was parsed from.
+func (s Schedule) String() string { return s.spec }
+
+// Location returns the timezone the schedule is evaluated in.
+func (s Schedule) Location() *time.Location { return s.loc }
+
+// Next returns the first time after t that the schedule fires, or the
+// zero time if it never does. A wall-clock time skipped when the clocks
+// go forward fires as they jump; one repeated when they go back fires
+// only the first time.
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.interval {
+		return s.expr.Next(t)
+	}
+	// cronlib steps through fields with time.Date, which misbehaves in
+	// zones with DST transitions, so it only ever sees UTC wall clocks.
+	from := wallClock(t.In(s.loc))
+	for range 2 {
+		w := s.expr.Next(from)
+		if w.IsZero() {
+			return w
+		}
+		if next := s.instant(w); next.After(t) {
+			return next
+		}
+		// t is in the hour repeated after the clocks went back, whose
+		// first pass is over: continue from the end of that hour.
+		start, _ := t.In(s.loc).ZoneBounds()
+		from = wallClock(start.Add(-time.Second).In(s.loc))
+	}
+	return time.Time{}
+}
+
+// instant returns the first moment the wall clock in s.loc reads w (a
+// UTC-encoded wall time), or, if the clocks skip it, the moment they jump.
+func (s Schedule) instant(w time.Time) time.Time {
+	near := time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), 0, s.loc)
+	// The offsets in effect half a day before and after cover any single
+	// transition; trying the earlier one first picks the first occurrence.
+	for _, probe := range []time.Time{near.Add(-12 * time.Hour), near.Add(12 * time.Hour)} {
+		_, offset := probe.Zone()
+		if t := w.Add(-time.Duration(offset) * time.Second); wallClock(t.In(s.loc)).Equal(w) {
+			return t
+		}
+	}
+	_, before := near.Add(-12 * time.Hour).Zone()
+	start, _ := w.Add(-time.Duration(before) * time.Second).In(s.loc).ZoneBounds()
+	return start
+}
+
+// wallClock re-encodes t's local date and time in UTC, where there are no
+// transitions to trip over.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// Loop calls run each time s fires, after a random delay of up to jitter,
+// until ctx is done. Runs never overlap: fire times that pass while run is
+// busy are skipped. run gets a context that isn't cancelled with ctx, so
+// stopping the loop lets a run in progress finish; Loop returns once it
+// has. A panicking run is logged and the loop goes on.
+func Loop(ctx context.Context, s Schedule, jitter time.Duration, run func(context.Context)) {
+	last := time.Now()
+	for {
+		next := s.Next(last)
+		if next.IsZero() {
+			return
+		}
+		delay := time.Until(next)
+		if jitter > 0 {
+			delay += rand.N(jitter)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		safeRun(context.WithoutCancel(ctx), s, run)
+		last = next
+		if now := time.Now(); now.After(last) {
+			last = now
+		}
+	}
+}
+
+func safeRun(ctx context.Context, s Schedule, run func(context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Scheduled run panicked", "schedule", s.spec, "panic", r)
+		}
+	}()
+	run(ctx)
+}
@@ -0,0 +1,96 @@
+package schedule
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		name, spec, from string
+		want             []string
+	}{
+		{"local morning all year", "0 7 * * *", "2026-03-07T12:00:00-05:00",
+			[]string{"2026-03-08T07:00:00-04:00", "2026-03-09T07:00:00-04:00"}},
+		{"after falling back", "0 0 7 * * *", "2026-10-31T12:00:00-04:00",
+			[]string{"2026-11-01T07:00:00-05:00", "2026-11-02T07:00:00-05:00"}},
+		{"skipped time runs as the clocks jump", "0 30 2 * * *", "2026-03-07T12:00:00-05:00",
+			[]string{"2026-03-08T03:00:00-04:00", "2026-03-09T02:30:00-04:00"}},
+		{"just before the jump", "0 30 1 * * *", "2026-03-07T12:00:00-05:00",
+			[]string{"2026-03-08T01:30:00-05:00", "2026-03-09T01:30:00-04:00"}},
+		{"repeated time runs once", "0 30 1 * * *", "2026-10-31T12:00:00-04:00",
+			[]string{"2026-11-01T01:30:00-04:00", "2026-11-02T01:30:00-05:00"}},
+		{"started during the repeated hour", "0 */15 * * * *", "2026-11-01T01:10:00-05:00",
+			[]string{"2026-11-01T02:00:00-05:00", "2026-11-01T02:15:00-05:00"}},
+		{"fixed interval", "@every 90m", "2026-03-08T01:00:00-05:00",
+			[]string{"2026-03-08T03:30:00-04:00", "2026-03-08T05:00:00-04:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec, ny)
+			require.NoError(t, err)
+			from := at(tt.from)
+			for _, want := range tt.want {
+				from = s.Next(from)
+				assert.True(t, at(want).Equal(from), "want %s, got %s", want, from.In(ny).Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	s, err := Parse("0 7 * * 1-5", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "0 7 * * 1-5", s.String())
+	assert.Equal(t, time.Local, s.Location())
+
+	_, err = Parse("0 7 * *", time.UTC)
+	assert.ErrorContains(t, err, "invalid schedule")
+	_, err = Parse("@every soon", time.UTC)
+	assert.Error(t, err)
+}
+
+func TestLoop(t *testing.T) {
+	t.Parallel()
+	s, err := Parse("@every 10ms", time.UTC)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+	done := make(chan struct{})
+	go func() {
+		Loop(ctx, s, 5*time.Millisecond, func(ctx context.Context) {
+			if runs.Add(1) == 1 {
+				panic("first run fails")
+			}
+			if runs.Load() == 3 {
+				cancel()
+				time.Sleep(20 * time.Millisecond)
+				assert.NoError(t, ctx.Err(), "a run in progress isn't cancelled")
+			}
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("loop did not stop")
+	}
+	assert.Equal(t, int32(3), runs.Load(), "the loop survives a panic and stops after cancel")
+}
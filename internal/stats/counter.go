@@ -0,0 +1,45 @@
+package stats
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// TokenCounter accumulates the tokens used by one unit of work, such as a
+// scheduled job, across every model call made with its context.
+type TokenCounter struct {
+	input  atomic.Int64
+	output atomic.Int64
+}
+
+type counterKey struct{}
+
+// WithTokenCounter returns a context whose model calls are counted by the
+// returned counter.
+func WithTokenCounter(ctx context.Context) (context.Context, *TokenCounter) {
+	c := &TokenCounter{}
+	return context.WithValue(ctx, counterKey{}, c), c
+}
+
+// CounterFromContext returns the context's token counter, or nil.
+func CounterFromContext(ctx context.Context) *TokenCounter {
+	c, _ := ctx.Value(counterKey{}).(*TokenCounter)
+	return c
+}
+
+// CountTokens adds token usage to the context's counter, if any.
+func CountTokens(ctx context.Context, input, output int64) {
+	if c, ok := ctx.Value(counterKey{}).(*TokenCounter); ok {
+		c.input.Add(input)
+		c.output.Add(output)
+	}
+}
+
+// Input returns the counted input tokens.
+func (c *TokenCounter) Input() int64 { return c.input.Load() }
+
+// Output returns the counted output tokens.
+func (c *TokenCounter) Output() int64 { return c.output.Load() }
+
+// Total returns the counted input and output tokens.
+func (c *TokenCounter) Total() int64 { return c.Input() + c.Output() }
@@ -0,0 +1,21 @@
+package stats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenCounter(t *testing.T) {
+	t.Parallel()
+	// Without a counter, counting is a no-op.
+	CountTokens(context.Background(), 10, 10)
+
+	ctx, c := WithTokenCounter(context.Background())
+	CountTokens(ctx, 100, 20)
+	CountTokens(ctx, 50, 5)
+	assert.Equal(t, int64(150), c.Input())
+	assert.Equal(t, int64(25), c.Output())
+	assert.Equal(t, int64(175), c.Total())
+}
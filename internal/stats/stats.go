@@ -2,6 +2,9 @@ package stats
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -13,11 +16,96 @@ type Stats struct {
 	missionsRun       atomic.Int64
 	inputTokens       atomic.Int64
 	outputTokens      atomic.Int64
+
+	mu       sync.Mutex
+	sessions map[string]*SessionUsage
+	models   map[string]ModelUsage
+	tools    map[string]int64
+	queries  map[string]QueryUsage
+	mcpCalls map[MCPTool]MCPCallUsage
+}
+
+// QueryUsage holds the timings of one kind of database query.
+type QueryUsage struct {
+	Count int64
+	Slow  int64 // queries at or above the slow-query threshold
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Avg returns the mean query duration.
+func (q QueryUsage) Avg() time.Duration {
+	if q.Count == 0 {
+		return 0
+	}
+	return q.Total / time.Duration(q.Count)
 }
 
+// MCPTool names a tool of an MCP server.
+type MCPTool struct {
+	Server string
+	Tool   string
+}
+
+// MCPCallUsage holds the calls made to MCP tools: their count, how many
+// failed and how long they took.
+type MCPCallUsage struct {
+	Calls  int64
+	Errors int64
+	Total  time.Duration
+	Max    time.Duration
+}
+
+// Avg returns the mean call duration.
+func (u MCPCallUsage) Avg() time.Duration {
+	if u.Calls == 0 {
+		return 0
+	}
+	return u.Total / time.Duration(u.Calls)
+}
+
+// ErrorPercent returns the share of calls that failed, in percent.
+func (u MCPCallUsage) ErrorPercent() int {
+	if u.Calls == 0 {
+		return 0
+	}
+	return int((u.Errors*100 + u.Calls/2) / u.Calls)
+}
+
+// Add returns the combined usage of u and v.
+func (u MCPCallUsage) Add(v MCPCallUsage) MCPCallUsage {
+	return MCPCallUsage{Calls: u.Calls + v.Calls, Errors: u.Errors + v.Errors, Total: u.Total + v.Total, Max: max(u.Max, v.Max)}
+}
+
+// SessionUsage holds the counters for a single chat session.
+type SessionUsage struct {
+	Messages     int64
+	InputTokens  int64
+	OutputTokens int64
+	Models       map[string]ModelUsage
+}
+
+// ModelUsage holds per-model token counters within a session.
+type ModelUsage struct {
+	Calls        int64
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// CostFunc estimates the cost in USD of the given token counts for a model.
+// It reports false when no pricing is known for the model.
+type CostFunc func(model string, input, output int64) (float64, bool)
+
 // New creates a new Stats tracker pinned to the current time.
 func New() *Stats {
-	return &Stats{startTime: time.Now()}
+	return &Stats{
+		startTime: time.Now(),
+		sessions:  make(map[string]*SessionUsage),
+		models:    make(map[string]ModelUsage),
+		tools:     make(map[string]int64),
+		queries:   make(map[string]QueryUsage),
+		mcpCalls:  make(map[MCPTool]MCPCallUsage),
+	}
 }
 
 // RecordMessage increments the messages-processed counter.
@@ -25,6 +113,61 @@ func (s *Stats) RecordMessage() {
 	s.messagesProcessed.Add(1)
 }
 
+// RecordSessionMessage increments the messages-processed counter and the
+// message count of the given session.
+func (s *Stats) RecordSessionMessage(sessionID string) {
+	s.RecordMessage()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session(sessionID).Messages++
+}
+
+// RecordSessionTokens adds one model call's token usage to a session. The
+// global counters are updated separately through RecordTokens.
+func (s *Stats) RecordSessionTokens(sessionID, model string, input, output int64) {
+	input, output = max(input, 0), max(output, 0)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.session(sessionID)
+	u.InputTokens += input
+	u.OutputTokens += output
+	m := u.Models[model]
+	m.Calls++
+	m.InputTokens += input
+	m.OutputTokens += output
+	u.Models[model] = m
+}
+
+// session returns the usage record for sessionID, creating it if needed.
+// The caller must hold s.mu.
+func (s *Stats) session(sessionID string) *SessionUsage {
+	if s.sessions == nil {
+		s.sessions = make(map[string]*SessionUsage)
+	}
+	u, ok := s.sessions[sessionID]
+	if !ok {
+		u = &SessionUsage{Models: make(map[string]ModelUsage)}
+		s.sessions[sessionID] = u
+	}
+	return u
+}
+
+// Session returns a copy of the usage counters for a session.
+func (s *Stats) Session(sessionID string) SessionUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.sessions[sessionID]
+	if !ok {
+		return SessionUsage{Models: map[string]ModelUsage{}}
+	}
+	c := *u
+	c.Models = make(map[string]ModelUsage, len(u.Models))
+	for k, v := range u.Models {
+		c.Models[k] = v
+	}
+	return c
+}
+
 // RecordMission increments the missions-run counter.
 func (s *Stats) RecordMission() {
 	s.missionsRun.Add(1)
@@ -40,6 +183,173 @@ func (s *Stats) RecordTokens(input, output int64) {
 	}
 }
 
+// RecordModelTokens adds one model call's token usage to the cumulative
+// counters and to the model's totals.
+func (s *Stats) RecordModelTokens(model string, input, output int64) {
+	input, output = max(input, 0), max(output, 0)
+	s.RecordTokens(input, output)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.models == nil {
+		s.models = make(map[string]ModelUsage)
+	}
+	m := s.models[model]
+	m.Calls++
+	m.InputTokens += input
+	m.OutputTokens += output
+	s.models[model] = m
+}
+
+// RecordToolCall increments the call count of a tool.
+func (s *Stats) RecordToolCall(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tools == nil {
+		s.tools = make(map[string]int64)
+	}
+	s.tools[name]++
+}
+
+// RecordQuery adds one database query's duration to the totals of its kind
+// (such as "SELECT reminders"). It implements db.QueryRecorder.
+func (s *Stats) RecordQuery(name string, d time.Duration, slow bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queries == nil {
+		s.queries = make(map[string]QueryUsage)
+	}
+	q := s.queries[name]
+	q.Count++
+	q.Total += d
+	q.Max = max(q.Max, d)
+	if slow {
+		q.Slow++
+	}
+	s.queries[name] = q
+}
+
+// Queries returns a copy of the query timings by kind.
+func (s *Stats) Queries() map[string]QueryUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]QueryUsage, len(s.queries))
+	for k, v := range s.queries {
+		out[k] = v
+	}
+	return out
+}
+
+// RecordMCPCall adds one MCP tool call, and whether it failed, to the
+// tool's totals.
+func (s *Stats) RecordMCPCall(server, tool string, d time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mcpCalls == nil {
+		s.mcpCalls = make(map[MCPTool]MCPCallUsage)
+	}
+	key := MCPTool{Server: server, Tool: tool}
+	u := s.mcpCalls[key]
+	u.Calls++
+	u.Total += d
+	u.Max = max(u.Max, d)
+	if failed {
+		u.Errors++
+	}
+	s.mcpCalls[key] = u
+}
+
+// MCPCalls returns a copy of the MCP tool call totals by tool.
+func (s *Stats) MCPCalls() map[MCPTool]MCPCallUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[MCPTool]MCPCallUsage, len(s.mcpCalls))
+	for k, v := range s.mcpCalls {
+		out[k] = v
+	}
+	return out
+}
+
+// MCPServerCalls returns the MCP tool call totals by server.
+func (s *Stats) MCPServerCalls() map[string]MCPCallUsage {
+	out := make(map[string]MCPCallUsage)
+	for k, v := range s.MCPCalls() {
+		out[k.Server] = out[k.Server].Add(v)
+	}
+	return out
+}
+
+// Totals is a point-in-time copy of the cumulative counters.
+type Totals struct {
+	Messages     int64
+	Missions     int64
+	InputTokens  int64
+	OutputTokens int64
+	Models       map[string]ModelUsage
+	Tools        map[string]int64
+}
+
+// Totals returns a copy of the cumulative counters.
+func (s *Stats) Totals() Totals {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := Totals{
+		Messages:     s.messagesProcessed.Load(),
+		Missions:     s.missionsRun.Load(),
+		InputTokens:  s.inputTokens.Load(),
+		OutputTokens: s.outputTokens.Load(),
+		Models:       make(map[string]ModelUsage, len(s.models)),
+		Tools:        make(map[string]int64, len(s.tools)),
+	}
+	for k, v := range s.models {
+		t.Models[k] = v
+	}
+	for k, v := range s.tools {
+		t.Tools[k] = v
+	}
+	return t
+}
+
+// Sub returns the counters accumulated since prev, omitting models and
+// tools with no new calls.
+func (t Totals) Sub(prev Totals) Totals {
+	d := Totals{
+		Messages:     t.Messages - prev.Messages,
+		Missions:     t.Missions - prev.Missions,
+		InputTokens:  t.InputTokens - prev.InputTokens,
+		OutputTokens: t.OutputTokens - prev.OutputTokens,
+		Models:       make(map[string]ModelUsage),
+		Tools:        make(map[string]int64),
+	}
+	for name, m := range t.Models {
+		p := prev.Models[name]
+		if m.Calls > p.Calls {
+			d.Models[name] = ModelUsage{
+				Calls:        m.Calls - p.Calls,
+				InputTokens:  m.InputTokens - p.InputTokens,
+				OutputTokens: m.OutputTokens - p.OutputTokens,
+			}
+		}
+	}
+	for name, n := range t.Tools {
+		if n > prev.Tools[name] {
+			d.Tools[name] = n - prev.Tools[name]
+		}
+	}
+	return d
+}
+
+// Cost estimates the USD cost of the model usage in t. Models without
+// pricing are skipped.
+func (t Totals) Cost(cost CostFunc) float64 {
+	var total float64
+	for name, m := range t.Models {
+		if c, ok := cost(name, m.InputTokens, m.OutputTokens); ok {
+			total += c
+		}
+	}
+	return total
+}
+
 // Uptime returns the duration since the bot started.
 func (s *Stats) Uptime() time.Duration {
 	return time.Since(s.startTime)
@@ -118,3 +428,50 @@ func (s *Stats) Summary() string {
 		formatNumber(input+output),
 	)
 }
+
+// SessionSummary returns a Markdown summary of a session's usage with a
+// per-model breakdown. Costs are estimated with cost; models without pricing
+// are excluded from the total.
+func (s *Stats) SessionSummary(sessionID string, cost CostFunc) string {
+	u := s.Session(sessionID)
+
+	models := make([]string, 0, len(u.Models))
+	for name := range u.Models {
+		models = append(models, name)
+	}
+	sort.Strings(models)
+
+	var total float64
+	var priced bool
+	var breakdown strings.Builder
+	for _, name := range models {
+		m := u.Models[name]
+		breakdown.WriteString(fmt.Sprintf("\n• `%s` — %d calls, %s in / %s out", name, m.Calls, formatNumber(m.InputTokens), formatNumber(m.OutputTokens)))
+		if c, ok := cost(name, m.InputTokens, m.OutputTokens); ok {
+			breakdown.WriteString(fmt.Sprintf(" (~$%.4f)", c))
+			total += c
+			priced = true
+		}
+	}
+
+	costLine := "n/a (no pricing configured)"
+	if priced {
+		costLine = fmt.Sprintf("~$%.4f", total)
+	}
+
+	summary := fmt.Sprintf(
+		"📊 **Session Usage**\n\n"+
+			"💬 **Messages**: %d\n"+
+			"🔤 **Tokens**: %s in / %s out (%s total)\n"+
+			"💵 **Estimated Cost**: %s",
+		u.Messages,
+		formatNumber(u.InputTokens),
+		formatNumber(u.OutputTokens),
+		formatNumber(u.InputTokens+u.OutputTokens),
+		costLine,
+	)
+	if len(models) > 0 {
+		summary += "\n\n🤖 **Models**:" + breakdown.String()
+	}
+	return summary
+}
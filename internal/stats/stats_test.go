@@ -116,3 +116,127 @@ func TestFormatDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionUsage(t *testing.T) {
+	t.Parallel()
+	s := New()
+
+	s.RecordSessionMessage("a")
+	s.RecordSessionMessage("a")
+	s.RecordSessionMessage("b")
+	s.RecordSessionTokens("a", "flash", 1000, 200)
+	s.RecordSessionTokens("a", "flash", 500, 100)
+	s.RecordSessionTokens("a", "pro", 2000, -5)
+
+	assert.Equal(t, int64(3), s.MessagesProcessed())
+
+	u := s.Session("a")
+	assert.Equal(t, int64(2), u.Messages)
+	assert.Equal(t, int64(3500), u.InputTokens)
+	assert.Equal(t, int64(300), u.OutputTokens)
+	assert.Equal(t, ModelUsage{Calls: 2, InputTokens: 1500, OutputTokens: 300}, u.Models["flash"])
+
+	// Returned usage is a copy
+	u.Models["flash"] = ModelUsage{}
+	assert.Equal(t, int64(2), s.Session("a").Models["flash"].Calls)
+
+	assert.Equal(t, int64(1), s.Session("b").Messages)
+	assert.Empty(t, s.Session("missing").Models)
+}
+
+func TestSessionSummary(t *testing.T) {
+	t.Parallel()
+	s := New()
+	s.RecordSessionMessage("a")
+	s.RecordSessionTokens("a", "flash", 1_000_000, 0)
+	s.RecordSessionTokens("a", "local", 10, 10)
+
+	cost := func(model string, input, output int64) (float64, bool) {
+		if model != "flash" {
+			return 0, false
+		}
+		return float64(input) / 1e6 * 0.5, true
+	}
+	summary := s.SessionSummary("a", cost)
+	assert.Contains(t, summary, "**Messages**: 1")
+	assert.Contains(t, summary, "1,000,020 total")
+	assert.Contains(t, summary, "**Estimated Cost**: ~$0.5000")
+	assert.Contains(t, summary, "`flash` — 1 calls, 1,000,000 in / 0 out (~$0.5000)")
+	assert.Contains(t, summary, "`local` — 1 calls, 10 in / 10 out")
+	assert.NotContains(t, summary, "10 out (~", "unpriced models have no cost")
+
+	empty := s.SessionSummary("b", cost)
+	assert.Contains(t, empty, "n/a")
+	assert.NotContains(t, empty, "Models")
+}
+
+func TestTotals_Sub(t *testing.T) {
+	t.Parallel()
+	s := New()
+	s.RecordMessage()
+	s.RecordModelTokens("flash", 100, 10)
+	s.RecordToolCall("web_search")
+	before := s.Totals()
+
+	s.RecordMessage()
+	s.RecordMission()
+	s.RecordModelTokens("flash", 50, 5)
+	s.RecordModelTokens("pro", 1000, 100)
+	s.RecordToolCall("web_search")
+	s.RecordToolCall("fetch")
+
+	d := s.Totals().Sub(before)
+	assert.Equal(t, int64(1), d.Messages)
+	assert.Equal(t, int64(1), d.Missions)
+	assert.Equal(t, int64(1050), d.InputTokens)
+	assert.Equal(t, int64(105), d.OutputTokens)
+	assert.Equal(t, map[string]ModelUsage{
+		"flash": {Calls: 1, InputTokens: 50, OutputTokens: 5},
+		"pro":   {Calls: 1, InputTokens: 1000, OutputTokens: 100},
+	}, d.Models)
+	assert.Equal(t, map[string]int64{"web_search": 1, "fetch": 1}, d.Tools)
+
+	cost := func(model string, in, out int64) (float64, bool) {
+		if model != "pro" {
+			return 0, false
+		}
+		return float64(in+out) / 1000, true
+	}
+	assert.InDelta(t, 1.1, d.Cost(cost), 1e-9)
+}
+
+func TestRecordQuery(t *testing.T) {
+	t.Parallel()
+	s := New()
+	s.RecordQuery("SELECT memories", 2*time.Millisecond, false)
+	s.RecordQuery("SELECT memories", 6*time.Millisecond, true)
+	s.RecordQuery("INSERT memories", time.Millisecond, false)
+
+	q := s.Queries()
+	assert.Equal(t, QueryUsage{Count: 2, Slow: 1, Total: 8 * time.Millisecond, Max: 6 * time.Millisecond}, q["SELECT memories"])
+	assert.Equal(t, 4*time.Millisecond, q["SELECT memories"].Avg())
+	assert.Equal(t, int64(1), q["INSERT memories"].Count)
+	assert.Zero(t, QueryUsage{}.Avg())
+}
+
+func TestRecordMCPCall(t *testing.T) {
+	t.Parallel()
+	s := New()
+	s.RecordMCPCall("github", "create_issue", 300*time.Millisecond, false)
+	s.RecordMCPCall("github", "create_issue", 100*time.Millisecond, true)
+	s.RecordMCPCall("github", "search", time.Second, true)
+	s.RecordMCPCall("weather", "forecast", 50*time.Millisecond, false)
+
+	calls := s.MCPCalls()
+	issue := calls[MCPTool{Server: "github", Tool: "create_issue"}]
+	assert.Equal(t, MCPCallUsage{Calls: 2, Errors: 1, Total: 400 * time.Millisecond, Max: 300 * time.Millisecond}, issue)
+	assert.Equal(t, 200*time.Millisecond, issue.Avg())
+	assert.Equal(t, 50, issue.ErrorPercent())
+
+	servers := s.MCPServerCalls()
+	assert.Equal(t, MCPCallUsage{Calls: 3, Errors: 2, Total: 1400 * time.Millisecond, Max: time.Second}, servers["github"])
+	assert.Equal(t, 67, servers["github"].ErrorPercent())
+	assert.Equal(t, int64(1), servers["weather"].Calls)
+	assert.Zero(t, MCPCallUsage{}.Avg())
+	assert.Zero(t, MCPCallUsage{}.ErrorPercent())
+}
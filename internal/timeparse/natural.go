@@ -0,0 +1,233 @@
+package timeparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultHour is the time of day used when a phrase names a day but no time
+// ("tomorrow", "next friday").
+const defaultHour = 9
+
+// maxAhead is how far past now a parsed time may be, about ten years.
+const maxAhead = 10 * 366 * 24 * time.Hour
+
+// Parse resolves the time expression at the start of phrase relative to now
+// and returns it along with the remaining text. Supported forms include Go
+// durations ("30m", "1h30m"), "in 2 weeks", "in an hour", "today at 5pm",
+// "tomorrow 9:30", "tonight", "next friday", "on monday at noon",
+// "at 15:00", and ISO dates ("2026-03-10 8am"). Results are in now's location;
+// a time of day the clocks skip moves forward to the moment they jump, and
+// times more than about ten years ahead are rejected.
+func Parse(phrase string, now time.Time) (time.Time, string, error) {
+	t, rest, err := parse(phrase, now)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if t.Sub(now) > maxAhead {
+		return time.Time{}, "", fmt.Errorf("%s is too far ahead", t.Format("Jan 2 2006"))
+	}
+	return t, rest, nil
+}
+
+func parse(phrase string, now time.Time) (time.Time, string, error) {
+	fields := strings.Fields(phrase)
+	if len(fields) == 0 {
+		return time.Time{}, "", fmt.Errorf("missing time")
+	}
+	rest := func(i int) string { return strings.Join(fields[i:], " ") }
+	first := strings.ToLower(fields[0])
+
+	// Plain Go duration: "30m", "2h".
+	if d, err := time.ParseDuration(first); err == nil && d > 0 {
+		return now.Add(d), rest(1), nil
+	}
+
+	switch first {
+	case "in":
+		t, n, err := parseRelative(fields[1:], now)
+		if err != nil {
+			return time.Time{}, "", err
+		}
+		return t, rest(1 + n), nil
+
+	case "today", "tomorrow", "tonight":
+		day := now
+		if first == "tomorrow" {
+			day = now.AddDate(0, 0, 1)
+		}
+		hour, minute := defaultHour, 0
+		if first == "tonight" {
+			hour = 20
+		}
+		i := 1
+		if h, m, n, ok := parseAtClock(fields[1:]); ok {
+			hour, minute = h, m
+			i += n
+		} else if first == "today" {
+			return time.Time{}, "", fmt.Errorf("\"today\" needs a time, e.g. \"today at 5pm\"")
+		}
+		return atClock(day, hour, minute), rest(i), nil
+
+	case "at":
+		if h, m, n, ok := parseAtClock(fields); ok {
+			return nextClock(now, h, m), rest(n), nil
+		}
+		return time.Time{}, "", fmt.Errorf("unrecognized time %q", strings.Join(fields[:min(2, len(fields))], " "))
+	}
+
+	// Weekdays: "friday", "next friday", "on friday", optionally with a time.
+	i := 0
+	if first == "on" || first == "next" || first == "this" {
+		i++
+	}
+	if i < len(fields) {
+		if wd, ok := weekdayNames[strings.ToLower(fields[i])]; ok {
+			days := (int(wd) - int(now.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			day := now.AddDate(0, 0, days)
+			i++
+			hour, minute := defaultHour, 0
+			if h, m, n, ok := parseAtClock(fields[i:]); ok {
+				hour, minute = h, m
+				i += n
+			}
+			return atClock(day, hour, minute), rest(i), nil
+		}
+	}
+
+	// ISO date: "2026-03-10", optionally with a time.
+	if d, err := time.ParseInLocation("2006-01-02", first, now.Location()); err == nil {
+		hour, minute := defaultHour, 0
+		i := 1
+		if h, m, n, ok := parseAtClock(fields[1:]); ok {
+			hour, minute = h, m
+			i += n
+		}
+		t := atClock(d, hour, minute)
+		if !t.After(now) {
+			return time.Time{}, "", fmt.Errorf("%s is in the past", t.Format("Jan 2 15:04"))
+		}
+		return t, rest(i), nil
+	}
+
+	// Bare time of day: "3pm", "15:30", "noon".
+	if h, m, n, ok := parseAtClock(fields); ok {
+		return nextClock(now, h, m), rest(n), nil
+	}
+
+	return time.Time{}, "", fmt.Errorf("unrecognized time %q", fields[0])
+}
+
+// parseRelative parses the part after "in": "2 weeks", "an hour", "90 minutes",
+// or a Go duration like "1h30m". It returns the number of fields consumed.
+func parseRelative(fields []string, now time.Time) (time.Time, int, error) {
+	if len(fields) == 0 {
+		return time.Time{}, 0, fmt.Errorf("missing amount after \"in\"")
+	}
+	if d, err := time.ParseDuration(strings.ToLower(fields[0])); err == nil && d > 0 {
+		return now.Add(d), 1, nil
+	}
+	if len(fields) < 2 {
+		return time.Time{}, 0, fmt.Errorf("missing unit after \"in %s\"", fields[0])
+	}
+
+	amount := strings.ToLower(fields[0])
+	var n int
+	switch amount {
+	case "a", "an", "one":
+		n = 1
+	default:
+		v, err := strconv.Atoi(amount)
+		if err != nil || v < 1 {
+			return time.Time{}, 0, fmt.Errorf("invalid amount %q", fields[0])
+		}
+		n = v
+	}
+	// No unit is shorter than a second, so a larger count is too far ahead
+	// whatever follows; capping it here keeps the arithmetic from overflowing.
+	if time.Duration(n) > maxAhead/time.Second {
+		return time.Time{}, 0, fmt.Errorf("%q is too far ahead", strings.Join(fields[:2], " "))
+	}
+	add := func(unit time.Duration) (time.Time, int, error) {
+		if time.Duration(n) > maxAhead/unit {
+			return time.Time{}, 0, fmt.Errorf("%q is too far ahead", strings.Join(fields[:2], " "))
+		}
+		return now.Add(time.Duration(n) * unit), 2, nil
+	}
+
+	switch strings.TrimSuffix(strings.ToLower(fields[1]), "s") {
+	case "second", "sec":
+		return add(time.Second)
+	case "minute", "min":
+		return add(time.Minute)
+	case "hour", "hr":
+		return add(time.Hour)
+	case "day":
+		return now.AddDate(0, 0, n), 2, nil
+	case "week":
+		return now.AddDate(0, 0, 7*n), 2, nil
+	case "month":
+		return now.AddDate(0, n, 0), 2, nil
+	case "year":
+		return now.AddDate(n, 0, 0), 2, nil
+	}
+	return time.Time{}, 0, fmt.Errorf("unrecognized unit %q", fields[1])
+}
+
+// parseAtClock parses an optional "at" followed by a time of day, including
+// the words "noon" and "midnight".
+func parseAtClock(fields []string) (hour, minute, consumed int, ok bool) {
+	i := 0
+	if len(fields) > 1 && strings.EqualFold(fields[0], "at") {
+		i = 1
+	}
+	if i >= len(fields) {
+		return 0, 0, 0, false
+	}
+	switch strings.ToLower(fields[i]) {
+	case "noon":
+		return 12, 0, i + 1, true
+	case "midnight":
+		return 0, 0, i + 1, true
+	}
+	h, m, n, ok := parseClock(fields[i:])
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return h, m, i + n, true
+}
+
+// nextClock returns the first time after now at hour:minute, today or
+// tomorrow.
+func nextClock(now time.Time, hour, minute int) time.Time {
+	t := atClock(now, hour, minute)
+	if !t.After(now) {
+		t = atClock(now.AddDate(0, 0, 1), hour, minute)
+	}
+	return t
+}
+
+// atClock returns hour:minute on day's date in day's location. A time the
+// clocks skip on that date resolves to the moment they jump, as schedule.Next
+// does, rather than to wherever time.Date normalizes it.
+func atClock(day time.Time, hour, minute int) time.Time {
+	t := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+	if t.Hour() == hour && t.Minute() == minute {
+		return t
+	}
+	start, end := t.ZoneBounds()
+	want := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.UTC)
+	got := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if got.Before(want) && !end.IsZero() {
+		return end
+	}
+	if !start.IsZero() {
+		return start
+	}
+	return t
+}
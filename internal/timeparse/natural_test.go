@@ -0,0 +1,81 @@
+package timeparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	loc, err := time.LoadLocation("America/Chicago")
+	require.NoError(t, err)
+	// Wednesday, 10:15 local
+	now := time.Date(2026, 3, 4, 10, 15, 0, 0, loc)
+
+	tests := []struct {
+		phrase  string
+		want    time.Time
+		wantMsg string
+	}{
+		{"30m check oven", now.Add(30 * time.Minute), "check oven"},
+		{"in 2 weeks renew cert", now.AddDate(0, 0, 14), "renew cert"},
+		{"in an hour stretch", now.Add(time.Hour), "stretch"},
+		{"in 90 minutes call mom", now.Add(90 * time.Minute), "call mom"},
+		{"in 1h30m deploy", now.Add(90 * time.Minute), "deploy"},
+		{"tomorrow at 3pm review PR", time.Date(2026, 3, 5, 15, 0, 0, 0, loc), "review PR"},
+		{"tomorrow standup", time.Date(2026, 3, 5, 9, 0, 0, 0, loc), "standup"},
+		{"today at 5:30 pm leave", time.Date(2026, 3, 4, 17, 30, 0, 0, loc), "leave"},
+		{"tonight trash", time.Date(2026, 3, 4, 20, 0, 0, 0, loc), "trash"},
+		{"next friday deploy", time.Date(2026, 3, 6, 9, 0, 0, 0, loc), "deploy"},
+		{"on monday at noon lunch", time.Date(2026, 3, 9, 12, 0, 0, 0, loc), "lunch"},
+		{"wednesday retro", time.Date(2026, 3, 11, 9, 0, 0, 0, loc), "retro"},
+		{"at 9am coffee", time.Date(2026, 3, 5, 9, 0, 0, 0, loc), "coffee"},
+		{"15:00 sync", time.Date(2026, 3, 4, 15, 0, 0, 0, loc), "sync"},
+		{"2026-03-10 8am dentist", time.Date(2026, 3, 10, 8, 0, 0, 0, loc), "dentist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			t.Parallel()
+			got, msg, err := Parse(tt.phrase, now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+			assert.Equal(t, tt.wantMsg, msg)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 4, 10, 15, 0, 0, time.UTC)
+	for _, phrase := range []string{"", "xyz do it", "in soon", "in 3 fortnights", "today lunch", "2020-01-01 old",
+		"in 99999999999 years x", "in 99999999999 hours x", "in 200 years x", "2040-01-01 x", "100000h x"} {
+		_, _, err := Parse(phrase, now)
+		assert.Error(t, err, "phrase %q", phrase)
+	}
+}
+
+func TestParse_SpringForward(t *testing.T) {
+	t.Parallel()
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// Clocks jump from 02:00 EST to 03:00 EDT on 2026-03-08.
+	jump := time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		phrase string
+		now    time.Time
+	}{
+		{"at 2:30am x", time.Date(2026, 3, 8, 0, 30, 0, 0, loc)},
+		{"2:30am x", time.Date(2026, 3, 7, 23, 0, 0, 0, loc)},
+		{"tomorrow at 2:30am x", time.Date(2026, 3, 7, 12, 0, 0, 0, loc)},
+		{"2026-03-08 2:30am x", time.Date(2026, 3, 1, 12, 0, 0, 0, loc)},
+	} {
+		got, _, err := Parse(tt.phrase, tt.now)
+		require.NoError(t, err, tt.phrase)
+		assert.True(t, jump.Equal(got), "%s: want %s, got %s", tt.phrase, jump.In(loc), got)
+	}
+}
@@ -0,0 +1,382 @@
+// Package timeparse turns the human-friendly time expressions used by chat
+// commands (durations, times of day, recurrence phrases) into concrete times.
+package timeparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported recurrence frequencies. The names follow RFC 5545 RRULE FREQ values.
+const (
+	FreqMinutely = "MINUTELY"
+	FreqHourly   = "HOURLY"
+	FreqDaily    = "DAILY"
+	FreqWeekly   = "WEEKLY"
+	FreqMonthly  = "MONTHLY"
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// Rule is a small subset of an RFC 5545 recurrence rule: a frequency with an
+// interval, optionally pinned to a time of day and a set of weekdays.
+type Rule struct {
+	Freq     string
+	Interval int
+	// ByDay restricts WEEKLY rules to specific weekdays. BYDAY rules always
+	// repeat every week; Interval is ignored for them.
+	ByDay []time.Weekday
+	// Hour and Minute pin DAILY/WEEKLY/MONTHLY rules to a time of day.
+	// Hour is -1 when the rule has no fixed time.
+	Hour   int
+	Minute int
+}
+
+// String encodes the rule in RRULE form, e.g. "FREQ=DAILY;INTERVAL=1;BYHOUR=9;BYMINUTE=0".
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq, fmt.Sprintf("INTERVAL=%d", r.interval())}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			codes[i] = strings.ToUpper(d.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Hour >= 0 {
+		parts = append(parts, fmt.Sprintf("BYHOUR=%d", r.Hour), fmt.Sprintf("BYMINUTE=%d", r.Minute))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseRule decodes a rule previously produced by Rule.String.
+func ParseRule(spec string) (Rule, error) {
+	r := Rule{Interval: 1, Hour: -1}
+	for _, field := range strings.Split(spec, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("invalid rule field %q", field)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				d, ok := weekdayCodes[strings.ToUpper(code)]
+				if !ok {
+					return Rule{}, fmt.Errorf("invalid BYDAY value %q", code)
+				}
+				r.ByDay = append(r.ByDay, d)
+			}
+		case "BYHOUR":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || n > 23 {
+				return Rule{}, fmt.Errorf("invalid BYHOUR %q", value)
+			}
+			r.Hour = n
+		case "BYMINUTE":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || n > 59 {
+				return Rule{}, fmt.Errorf("invalid BYMINUTE %q", value)
+			}
+			r.Minute = n
+		default:
+			return Rule{}, fmt.Errorf("unsupported rule field %q", key)
+		}
+	}
+	switch r.Freq {
+	case FreqMinutely, FreqHourly, FreqDaily, FreqWeekly, FreqMonthly:
+	default:
+		return Rule{}, fmt.Errorf("unsupported FREQ %q", r.Freq)
+	}
+	return r, nil
+}
+
+func (r Rule) interval() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+// Next returns the first occurrence of the rule strictly after t, evaluated
+// in t's location.
+func (r Rule) Next(t time.Time) time.Time {
+	n := r.interval()
+	switch r.Freq {
+	case FreqMinutely:
+		return t.Add(time.Duration(n) * time.Minute)
+	case FreqHourly:
+		return t.Add(time.Duration(n) * time.Hour)
+	}
+
+	hour, minute := r.Hour, r.Minute
+	if hour < 0 {
+		hour, minute = t.Hour(), t.Minute()
+	}
+	candidate := time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, t.Location())
+
+	switch r.Freq {
+	case FreqWeekly:
+		if len(r.ByDay) > 0 {
+			for i := 0; i <= 7; i++ {
+				c := candidate.AddDate(0, 0, i)
+				if c.After(t) && containsWeekday(r.ByDay, c.Weekday()) {
+					return c
+				}
+			}
+		}
+		if !candidate.After(t) {
+			candidate = candidate.AddDate(0, 0, 7*n)
+		}
+	case FreqMonthly:
+		if !candidate.After(t) {
+			candidate = candidate.AddDate(0, n, 0)
+		}
+	default: // FreqDaily
+		if !candidate.After(t) {
+			candidate = candidate.AddDate(0, 0, n)
+		}
+	}
+	return candidate
+}
+
+// NextAfter advances from the previous occurrence until it finds one after
+// now, so that occurrences missed during downtime are skipped rather than
+// delivered in a burst.
+func (r Rule) NextAfter(prev, now time.Time) time.Time {
+	next := r.Next(prev)
+	for !next.After(now) {
+		next = r.Next(next)
+	}
+	return next
+}
+
+// Describe renders the rule as a short human-readable phrase such as
+// "every day at 09:00" or "every Mon, Wed at 08:30".
+func (r Rule) Describe() string {
+	n := r.interval()
+	var base string
+	switch r.Freq {
+	case FreqMinutely:
+		base = plural(n, "minute")
+	case FreqHourly:
+		base = plural(n, "hour")
+	case FreqDaily:
+		base = plural(n, "day")
+	case FreqWeekly:
+		if len(r.ByDay) > 0 {
+			names := make([]string, len(r.ByDay))
+			for i, d := range r.ByDay {
+				names[i] = d.String()[:3]
+			}
+			base = "every " + strings.Join(names, ", ")
+		} else {
+			base = plural(n, "week")
+		}
+	case FreqMonthly:
+		base = plural(n, "month")
+	default:
+		base = "every " + strings.ToLower(r.Freq)
+	}
+	if r.Hour >= 0 && r.Freq != FreqMinutely && r.Freq != FreqHourly {
+		base += fmt.Sprintf(" at %02d:%02d", r.Hour, r.Minute)
+	}
+	return base
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "every " + unit
+	}
+	return fmt.Sprintf("every %d %ss", n, unit)
+}
+
+func containsWeekday(days []time.Weekday, d time.Weekday) bool {
+	for _, day := range days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEvery parses the phrase that follows "every" in a reminder command,
+// e.g. "day 9am standup notes", "weekday at 8:30 check CI", "2h stretch",
+// or "mon,wed 18:00 gym". It returns the rule and the remaining message.
+// Day-level rules without an explicit time repeat at now's time of day.
+func ParseEvery(phrase string, now time.Time) (Rule, string, error) {
+	fields := strings.Fields(phrase)
+	if len(fields) == 0 {
+		return Rule{}, "", fmt.Errorf("missing recurrence")
+	}
+
+	r := Rule{Interval: 1, Hour: -1}
+	i := 0
+
+	// Optional leading count: "every 2 days", "every 3 weeks".
+	if n, err := strconv.Atoi(fields[0]); err == nil && len(fields) > 1 {
+		if n < 1 {
+			return Rule{}, "", fmt.Errorf("interval must be positive")
+		}
+		r.Interval = n
+		i++
+	}
+
+	unit := strings.ToLower(fields[i])
+	switch {
+	case unit == "minute" || unit == "minutes" || unit == "min" || unit == "mins":
+		r.Freq = FreqMinutely
+	case unit == "hour" || unit == "hours":
+		r.Freq = FreqHourly
+	case unit == "day" || unit == "days" || unit == "daily":
+		r.Freq = FreqDaily
+	case unit == "week" || unit == "weeks" || unit == "weekly":
+		r.Freq = FreqWeekly
+	case unit == "month" || unit == "months" || unit == "monthly":
+		r.Freq = FreqMonthly
+	case unit == "weekday" || unit == "weekdays":
+		r.Freq = FreqWeekly
+		r.ByDay = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	case unit == "weekend" || unit == "weekends":
+		r.Freq = FreqWeekly
+		r.ByDay = []time.Weekday{time.Saturday, time.Sunday}
+	default:
+		if days, ok := parseWeekdayList(unit); ok {
+			r.Freq = FreqWeekly
+			r.ByDay = days
+			break
+		}
+		// Compact Go duration: "every 30m", "every 2h".
+		d, err := time.ParseDuration(unit)
+		if err != nil || d < time.Minute || r.Interval != 1 {
+			return Rule{}, "", fmt.Errorf("unrecognized recurrence %q", fields[i])
+		}
+		if d%time.Hour == 0 {
+			r.Freq, r.Interval = FreqHourly, int(d/time.Hour)
+		} else {
+			r.Freq, r.Interval = FreqMinutely, int(d/time.Minute)
+		}
+	}
+	i++
+
+	if r.Freq != FreqMinutely && r.Freq != FreqHourly && i < len(fields) {
+		j := i
+		if strings.EqualFold(fields[j], "at") && j+1 < len(fields) {
+			j++
+		}
+		if hour, minute, consumed, ok := parseClock(fields[j:]); ok {
+			r.Hour, r.Minute = hour, minute
+			i = j + consumed
+		}
+	}
+	if r.Hour < 0 && r.Freq != FreqMinutely && r.Freq != FreqHourly {
+		r.Hour, r.Minute = now.Hour(), now.Minute()
+	}
+
+	return r, strings.Join(fields[i:], " "), nil
+}
+
+// parseWeekdayList parses "monday" or a comma-separated list like "mon,wed,fri".
+func parseWeekdayList(s string) ([]time.Weekday, bool) {
+	var days []time.Weekday
+	for _, name := range strings.Split(s, ",") {
+		d, ok := weekdayNames[name]
+		if !ok {
+			// Plural forms: "mondays".
+			d, ok = weekdayNames[strings.TrimSuffix(name, "s")]
+		}
+		if !ok {
+			return nil, false
+		}
+		days = append(days, d)
+	}
+	return days, len(days) > 0
+}
+
+// parseClock parses a time of day from the start of fields, accepting "9am",
+// "9:30pm", "21:00", and "9 am". It reports how many fields were consumed.
+func parseClock(fields []string) (hour, minute, consumed int, ok bool) {
+	if len(fields) == 0 {
+		return 0, 0, 0, false
+	}
+	s := strings.ToLower(fields[0])
+	consumed = 1
+	if len(fields) > 1 {
+		next := strings.ToLower(fields[1])
+		if next == "am" || next == "pm" {
+			s += next
+			consumed = 2
+		}
+	}
+
+	meridiem := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		meridiem = s[len(s)-2:]
+		s = s[:len(s)-2]
+	}
+
+	h, m := s, "0"
+	if before, after, found := strings.Cut(s, ":"); found {
+		h, m = before, after
+	} else if meridiem == "" {
+		// A bare number is not a time ("every day 5 things").
+		return 0, 0, 0, false
+	}
+
+	hour, err := strconv.Atoi(h)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	minute, err = strconv.Atoi(m)
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, 0, 0, false
+	}
+
+	switch meridiem {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0, false
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	default:
+		if hour < 0 || hour > 23 {
+			return 0, 0, 0, false
+		}
+	}
+	return hour, minute, consumed, true
+}
@@ -0,0 +1,116 @@
+package timeparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// base is a Wednesday.
+var base = time.Date(2026, 3, 4, 10, 15, 0, 0, time.UTC)
+
+func TestParseEvery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		phrase   string
+		wantRule string
+		wantMsg  string
+		wantNext time.Time
+	}{
+		{
+			name:     "daily with am time",
+			phrase:   "day 9am standup notes",
+			wantRule: "FREQ=DAILY;INTERVAL=1;BYHOUR=9;BYMINUTE=0",
+			wantMsg:  "standup notes",
+			wantNext: time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily later today",
+			phrase:   "day at 5:30 pm go home",
+			wantRule: "FREQ=DAILY;INTERVAL=1;BYHOUR=17;BYMINUTE=30",
+			wantMsg:  "go home",
+			wantNext: time.Date(2026, 3, 4, 17, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekdays",
+			phrase:   "weekday 08:00 check CI",
+			wantRule: "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,TU,WE,TH,FR;BYHOUR=8;BYMINUTE=0",
+			wantMsg:  "check CI",
+			wantNext: time.Date(2026, 3, 5, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "named weekdays",
+			phrase:   "mon,fri 18:00 gym",
+			wantRule: "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,FR;BYHOUR=18;BYMINUTE=0",
+			wantMsg:  "gym",
+			wantNext: time.Date(2026, 3, 6, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "compact duration",
+			phrase:   "2h stretch",
+			wantRule: "FREQ=HOURLY;INTERVAL=2",
+			wantMsg:  "stretch",
+			wantNext: base.Add(2 * time.Hour),
+		},
+		{
+			name:     "counted days without time keep time of day",
+			phrase:   "3 days water plants",
+			wantRule: "FREQ=DAILY;INTERVAL=3;BYHOUR=10;BYMINUTE=15",
+			wantMsg:  "water plants",
+			wantNext: time.Date(2026, 3, 7, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:     "bare number is not a time",
+			phrase:   "day 5 pushups",
+			wantRule: "FREQ=DAILY;INTERVAL=1;BYHOUR=10;BYMINUTE=15",
+			wantMsg:  "5 pushups",
+			wantNext: time.Date(2026, 3, 5, 10, 15, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rule, msg, err := ParseEvery(tt.phrase, base)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRule, rule.String())
+			assert.Equal(t, tt.wantMsg, msg)
+			assert.Equal(t, tt.wantNext, rule.Next(base))
+		})
+	}
+}
+
+func TestParseEvery_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, phrase := range []string{"", "fortnight do things", "0 days nothing"} {
+		_, _, err := ParseEvery(phrase, base)
+		assert.Error(t, err, "phrase %q", phrase)
+	}
+}
+
+func TestParseRule_RoundTrip(t *testing.T) {
+	t.Parallel()
+	rule, _, err := ParseEvery("mon,wed 9:45am review", base)
+	require.NoError(t, err)
+
+	parsed, err := ParseRule(rule.String())
+	require.NoError(t, err)
+	assert.Equal(t, rule, parsed)
+	assert.Equal(t, "every Mon, Wed at 09:45", parsed.Describe())
+
+	_, err = ParseRule("FREQ=YEARLY")
+	assert.Error(t, err)
+}
+
+func TestNextAfter_SkipsMissedOccurrences(t *testing.T) {
+	t.Parallel()
+	rule, _, err := ParseEvery("day 9am standup", base)
+	require.NoError(t, err)
+
+	prev := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), rule.NextAfter(prev, base))
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	archiveTimeout = 45 * time.Second
+	// maxArchiveAPIBytes caps the availability API response read.
+	maxArchiveAPIBytes = 64 << 10
+	waybackTimestamp   = "20060102150405"
+)
+
+// The Wayback Machine's availability API and snapshot prefix; tests point
+// them at a local server.
+var (
+	waybackAvailableURL = "https://archive.org/wayback/available"
+	waybackWebURL       = "https://web.archive.org/web/"
+)
+
+// ArchivedPage is a page read from a Wayback Machine snapshot.
+type ArchivedPage struct {
+	Article
+	// SnapshotURL is the snapshot read, on web.archive.org.
+	SnapshotURL string
+	Captured    time.Time
+}
+
+// ArchiveFetch reads the most recent Wayback Machine snapshot of rawURL, for
+// pages that have gone missing or sit behind a paywall. Article.URL is the
+// original URL.
+func ArchiveFetch(ctx context.Context, rawURL string) (ArchivedPage, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ArchivedPage{}, fmt.Errorf("invalid URL %q", rawURL)
+	}
+	timestamp, err := latestSnapshot(ctx, rawURL)
+	if err != nil {
+		return ArchivedPage{}, err
+	}
+	// "id_" asks for the page as captured, without the Wayback toolbar and
+	// rewritten links.
+	snapshot := waybackWebURL + timestamp + "id_/" + rawURL
+	doc, base, err := getHTML(ctx, newCachedClient(archiveTimeout), snapshot)
+	if err != nil {
+		return ArchivedPage{}, fmt.Errorf("failed to read the snapshot of %s: %w", rawURL, err)
+	}
+	a := ExtractArticle(doc, base)
+	a.URL = rawURL
+	if r := []rune(a.Text); len(r) > maxArticleChars {
+		a.Text = string(r[:maxArticleChars]) + "…"
+	}
+	captured, _ := time.Parse(waybackTimestamp, timestamp)
+	return ArchivedPage{Article: a, SnapshotURL: waybackWebURL + timestamp + "/" + rawURL, Captured: captured}, nil
+}
+
+// latestSnapshot returns the timestamp of the newest successful capture of
+// rawURL.
+func latestSnapshot(ctx context.Context, rawURL string) (string, error) {
+	endpoint := waybackAvailableURL + "?url=" + url.QueryEscape(rawURL)
+	if err := ValidateURL(ctx, endpoint); err != nil {
+		return "", fmt.Errorf("invalid Wayback Machine URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Wayback Machine request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := NewSafeClient(archiveTimeout).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Wayback Machine request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Wayback Machine returned status %d", resp.StatusCode)
+	}
+	var out struct {
+		ArchivedSnapshots struct {
+			Closest struct {
+				Available bool   `json:"available"`
+				Timestamp string `json:"timestamp"`
+				Status    string `json:"status"`
+			} `json:"closest"`
+		} `json:"archived_snapshots"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxArchiveAPIBytes)).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode Wayback Machine response: %w", err)
+	}
+	c := out.ArchivedSnapshots.Closest
+	if !c.Available || c.Timestamp == "" || (c.Status != "" && c.Status != "200") {
+		return "", fmt.Errorf("the Wayback Machine has no snapshot of %s", rawURL)
+	}
+	return c.Timestamp, nil
+}
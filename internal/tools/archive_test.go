@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchiveFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/wayback/available":
+			if r.URL.Query().Get("url") == "https://news.example/gone" {
+				_, _ = w.Write([]byte(`{"url":"https://news.example/gone","archived_snapshots":{}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"archived_snapshots":{"closest":{"available":true,"url":"http://web.archive.org/web/20260301120000/https://news.example/post","timestamp":"20260301120000","status":"200"}}}`))
+		case r.URL.Path == "/web/20260301120000id_/https://news.example/post":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><head><title>Archived story</title></head><body><article><p>` + strings.Repeat("The archived text of the story. ", 10) + `</p></article></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	oldAPI, oldWeb := waybackAvailableURL, waybackWebURL
+	waybackAvailableURL, waybackWebURL = srv.URL+"/wayback/available", srv.URL+"/web/"
+	defer func() { waybackAvailableURL, waybackWebURL = oldAPI, oldWeb }()
+	ctx := context.Background()
+
+	page, err := ArchiveFetch(ctx, "https://news.example/post")
+	if err != nil {
+		t.Fatalf("ArchiveFetch failed: %v", err)
+	}
+	if page.URL != "https://news.example/post" || page.Title != "Archived story" || !strings.HasPrefix(page.Text, "The archived text") {
+		t.Errorf("unexpected page: %+v", page)
+	}
+	if page.SnapshotURL != srv.URL+"/web/20260301120000/https://news.example/post" || !page.Captured.Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected snapshot: %s at %v", page.SnapshotURL, page.Captured)
+	}
+
+	if _, err := ArchiveFetch(ctx, "https://news.example/gone"); err == nil || !strings.Contains(err.Error(), "no snapshot") {
+		t.Errorf("expected a no-snapshot error, got %v", err)
+	}
+	if _, err := ArchiveFetch(ctx, "news.example/post"); err == nil {
+		t.Error("expected an error for a URL without a scheme")
+	}
+}
@@ -0,0 +1,242 @@
+package tools
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	arxivTimeout = 30 * time.Second
+	// DefaultArxivResults is how many papers a search lists by default, and
+	// maxArxivResults the most it lists.
+	DefaultArxivResults = 5
+	maxArxivResults     = 20
+)
+
+var (
+	// arxivAPI and arxivPDF are the arXiv endpoints; tests point them at a
+	// local server.
+	arxivAPI = "https://export.arxiv.org/api/query"
+	arxivPDF = "https://arxiv.org/pdf/"
+
+	// arXiv asks API clients to wait three seconds between requests.
+	arxivInterval = 3 * time.Second
+	arxivMu       sync.Mutex
+	arxivLast     time.Time
+)
+
+// ArxivPaper is a paper's metadata and abstract from the arXiv API.
+type ArxivPaper struct {
+	ID         string // e.g. 2401.01234v2
+	Title      string
+	Authors    []string
+	Abstract   string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// URL is the paper's abstract page.
+func (p ArxivPaper) URL() string { return "https://arxiv.org/abs/" + p.ID }
+
+// Markdown formats the paper for the model, with its abstract.
+func (p ArxivPaper) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s** (arXiv:%s, %s)\n", p.Title, p.ID, p.Published.Format("2006-01-02")))
+	authors := p.Authors
+	if len(authors) > 6 {
+		authors = append(authors[:6:6], "et al.")
+	}
+	if len(authors) > 0 {
+		sb.WriteString("Authors: " + strings.Join(authors, ", ") + "\n")
+	}
+	if len(p.Categories) > 0 {
+		sb.WriteString("Categories: " + strings.Join(p.Categories, ", ") + "\n")
+	}
+	sb.WriteString(p.URL() + "\n")
+	if p.Abstract != "" {
+		sb.WriteString("\n" + p.Abstract + "\n")
+	}
+	return sb.String()
+}
+
+type arxivFeed struct {
+	Entries []struct {
+		ID        string `xml:"id"`
+		Title     string `xml:"title"`
+		Summary   string `xml:"summary"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+		Authors   []struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+// SearchArxiv returns the papers matching query, most relevant first. The
+// query uses the arXiv API syntax, e.g. "ti:transformer AND cat:cs.CV";
+// plain words search all fields.
+func SearchArxiv(ctx context.Context, query string, maxResults int) ([]ArxivPaper, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if maxResults <= 0 {
+		maxResults = DefaultArxivResults
+	}
+	if !strings.Contains(query, ":") {
+		query = "all:" + query
+	}
+	q := url.Values{
+		"search_query": {query},
+		"max_results":  {strconv.Itoa(min(maxResults, maxArxivResults))},
+		"sortBy":       {"relevance"},
+	}
+	return queryArxiv(ctx, q)
+}
+
+// ArxivPaperByID returns the paper with the given ID, which may also be
+// written as "arXiv:ID" or as its abs or pdf URL.
+func ArxivPaperByID(ctx context.Context, id string) (ArxivPaper, error) {
+	id, err := ParseArxivID(id)
+	if err != nil {
+		return ArxivPaper{}, err
+	}
+	papers, err := queryArxiv(ctx, url.Values{"id_list": {id}})
+	if err != nil {
+		return ArxivPaper{}, err
+	}
+	if len(papers) == 0 || papers[0].Title == "" {
+		return ArxivPaper{}, fmt.Errorf("arXiv has no paper %s", id)
+	}
+	return papers[0], nil
+}
+
+var arxivIDPattern = regexp.MustCompile(`^(\d{4}\.\d{4,5}|[a-z-]+(\.[A-Z]{2})?/\d{7})(v\d+)?$`)
+
+// ParseArxivID returns the arXiv identifier in id: a new-style ID such as
+// 2401.01234v2, an old-style one such as hep-th/9901001, or either prefixed
+// with "arXiv:" or as an arxiv.org URL.
+func ParseArxivID(id string) (string, error) {
+	s := strings.TrimSpace(id)
+	for _, prefix := range []string{"https://", "http://", "www.", "export.", "arxiv.org/abs/", "arxiv.org/pdf/", "arXiv:", "arxiv:"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".pdf")
+	if !arxivIDPattern.MatchString(s) {
+		return "", fmt.Errorf("invalid arXiv ID %q", id)
+	}
+	return s, nil
+}
+
+// ArxivPaperText downloads a paper's PDF and returns its text, cut to
+// maxChars (if positive).
+func ArxivPaperText(ctx context.Context, id string, maxChars int) (string, error) {
+	id, err := ParseArxivID(id)
+	if err != nil {
+		return "", err
+	}
+	resp, err := getArxiv(ctx, arxivPDF+id)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPDFBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read arXiv PDF: %w", err)
+	}
+	text, err := PDFText(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract the text of arXiv:%s: %w", id, err)
+	}
+	if maxChars > 0 && len(text) > maxChars {
+		cut := strings.LastIndexAny(text[:maxChars], " \n")
+		if cut <= 0 {
+			cut = maxChars
+		}
+		text = text[:cut] + "\n\n[… truncated]"
+	}
+	return text, nil
+}
+
+func queryArxiv(ctx context.Context, q url.Values) ([]ArxivPaper, error) {
+	resp, err := getArxiv(ctx, arxivAPI+"?"+q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var feed arxivFeed
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxFeedBytes)).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("failed to decode arXiv response: %w", err)
+	}
+	papers := make([]ArxivPaper, 0, len(feed.Entries))
+	for _, e := range feed.Entries {
+		p := ArxivPaper{
+			ID:       strings.TrimPrefix(strings.TrimPrefix(e.ID, "http://arxiv.org/abs/"), "https://arxiv.org/abs/"),
+			Title:    strings.Join(strings.Fields(e.Title), " "),
+			Abstract: strings.Join(strings.Fields(e.Summary), " "),
+		}
+		p.Published, _ = time.Parse(time.RFC3339, e.Published)
+		p.Updated, _ = time.Parse(time.RFC3339, e.Updated)
+		for _, a := range e.Authors {
+			p.Authors = append(p.Authors, strings.TrimSpace(a.Name))
+		}
+		for _, c := range e.Categories {
+			p.Categories = append(p.Categories, c.Term)
+		}
+		papers = append(papers, p)
+	}
+	return papers, nil
+}
+
+// getArxiv fetches an arXiv URL, keeping to arxivInterval between requests.
+func getArxiv(ctx context.Context, rawURL string) (*http.Response, error) {
+	if err := ValidateURL(ctx, rawURL); err != nil {
+		return nil, fmt.Errorf("invalid arXiv URL: %w", err)
+	}
+	if err := waitArxiv(ctx); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create arXiv request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	slog.Info("arXiv: fetching", "url", rawURL)
+	resp, err := newCachedClient(arxivTimeout).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("arXiv request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("arXiv returned status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func waitArxiv(ctx context.Context) error {
+	arxivMu.Lock()
+	defer arxivMu.Unlock()
+	if wait := time.Until(arxivLast.Add(arxivInterval)); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	arxivLast = time.Now()
+	return nil
+}
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/raythurman2386/ravenbot/internal/export"
+)
+
+const testArxivFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+<entry>
+<id>http://arxiv.org/abs/2401.01234v2</id>
+<updated>2024-02-01T10:00:00Z</updated>
+<published>2024-01-03T18:00:00Z</published>
+<title>Segment Anything
+  in Satellite Imagery</title>
+<summary>  We adapt segmentation
+  models to remote sensing.</summary>
+<author><name>Ada Lovelace</name></author>
+<author><name>Alan Turing</name></author>
+<category term="cs.CV" scheme="http://arxiv.org/schemas/atom"/>
+<category term="eess.IV" scheme="http://arxiv.org/schemas/atom"/>
+</entry>
+</feed>`
+
+// testPDF returns a PDF whose content stream is Flate-compressed, next to
+// a font program that must be skipped.
+func testPDF(content string) []byte {
+	var z bytes.Buffer
+	w := zlib.NewWriter(&z)
+	_, _ = w.Write([]byte(content))
+	_ = w.Close()
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.5\n")
+	fmt.Fprintf(&pdf, "4 0 obj\n<< /Length1 10 /Length %d >>\nstream\nBT (font) Tj ET\nendstream\nendobj\n", 16)
+	fmt.Fprintf(&pdf, "5 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", z.Len())
+	pdf.Write(z.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestParseArxivID(t *testing.T) {
+	for in, want := range map[string]string{
+		"2401.01234":                           "2401.01234",
+		"arXiv:2401.01234v2":                   "2401.01234v2",
+		"https://arxiv.org/abs/2401.01234":     "2401.01234",
+		"https://arxiv.org/pdf/2401.01234.pdf": "2401.01234",
+		"hep-th/9901001":                       "hep-th/9901001",
+		"math.GT/0309136v1":                    "math.GT/0309136v1",
+	} {
+		if got, err := ParseArxivID(in); err != nil || got != want {
+			t.Errorf("ParseArxivID(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseArxivID("../etc/passwd"); err == nil {
+		t.Error("expected error for invalid ID")
+	}
+}
+
+func TestSearchArxiv(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/pdf/") {
+			_, _ = w.Write(testPDF("BT /F1 10 Tf 72 700 Td [(Seg)-20(men)40(tation)-333(of)]TJ 0 -12 Td (sa\\014elds vi-)Tj T* (sion.)Tj ET"))
+			return
+		}
+		queries = append(queries, r.URL.RawQuery)
+		_, _ = w.Write([]byte(testArxivFeed))
+	}))
+	defer srv.Close()
+	oldAPI, oldPDF, oldInterval := arxivAPI, arxivPDF, arxivInterval
+	arxivAPI, arxivPDF, arxivInterval = srv.URL+"/api/query", srv.URL+"/pdf/", 0
+	defer func() { arxivAPI, arxivPDF, arxivInterval = oldAPI, oldPDF, oldInterval }()
+	ctx := context.Background()
+
+	papers, err := SearchArxiv(ctx, "segment anything", 0)
+	if err != nil {
+		t.Fatalf("SearchArxiv failed: %v", err)
+	}
+	if len(papers) != 1 || papers[0].ID != "2401.01234v2" || papers[0].Title != "Segment Anything in Satellite Imagery" || len(papers[0].Authors) != 2 || papers[0].Published.Day() != 3 {
+		t.Errorf("unexpected papers: %+v", papers)
+	}
+	if !strings.Contains(queries[0], "search_query=all%3Asegment+anything") || !strings.Contains(queries[0], "max_results=5") {
+		t.Errorf("unexpected query %q", queries[0])
+	}
+	md := papers[0].Markdown()
+	if !strings.Contains(md, "https://arxiv.org/abs/2401.01234v2") || !strings.Contains(md, "We adapt segmentation models to remote sensing.") {
+		t.Errorf("unexpected Markdown: %s", md)
+	}
+
+	if _, err := SearchArxiv(ctx, "cat:cs.CV", 50); err != nil || !strings.Contains(queries[1], "search_query=cat%3Acs.CV") || !strings.Contains(queries[1], "max_results=20") {
+		t.Errorf("unexpected query %q, %v", queries[1], err)
+	}
+	if _, err := ArxivPaperByID(ctx, "arXiv:2401.01234"); err != nil || queries[2] != "id_list=2401.01234" {
+		t.Errorf("unexpected query %q, %v", queries[2], err)
+	}
+
+	text, err := ArxivPaperText(ctx, "2401.01234", 0)
+	if err != nil {
+		t.Fatalf("ArxivPaperText failed: %v", err)
+	}
+	if text != "Segmentation of\nsafields vision." {
+		t.Errorf("unexpected text %q", text)
+	}
+	if text, _ := ArxivPaperText(ctx, "2401.01234", 20); text != "Segmentation of\n\n[… truncated]" {
+		t.Errorf("unexpected truncated text %q", text)
+	}
+}
+
+func TestPDFText(t *testing.T) {
+	text, err := PDFText(export.PDF("Briefing", "Go 1.26 (released) ships\n\nnew features"))
+	if err != nil {
+		t.Fatalf("PDFText failed: %v", err)
+	}
+	if !strings.Contains(text, "Go 1.26 (released) ships") || !strings.Contains(text, "new features") {
+		t.Errorf("unexpected text %q", text)
+	}
+	if _, err := PDFText([]byte("<html></html>")); err == nil {
+		t.Error("expected error for non-PDF")
+	}
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	browserTimeout = 45 * time.Second
+	// A screenshot waits this long after the page loads, for scripts to
+	// draw charts and the like, unless asked to wait longer.
+	defaultSettle = time.Second
+	maxSettle     = 10 * time.Second
+
+	screenshotWidth  = 1280
+	screenshotHeight = 800
+)
+
+// BrowserManager runs the headless Chrome the browser tools share. Chrome
+// is started (or connected to) on first use, and every request a page
+// makes is checked with ValidateURL, so pages can't reach internal
+// addresses through redirects or sub-resources.
+type BrowserManager struct {
+	remoteURL string
+	execPath  string
+	noSandbox bool
+
+	mu      sync.Mutex
+	browser context.Context // nil until started
+	cancel  context.CancelFunc
+}
+
+// NewBrowserManager returns a manager that connects to the Chrome DevTools
+// endpoint at remoteURL or, if it is empty, starts Chrome from execPath
+// (found on the PATH if empty).
+func NewBrowserManager(remoteURL, execPath string, noSandbox bool) *BrowserManager {
+	return &BrowserManager{remoteURL: remoteURL, execPath: execPath, noSandbox: noSandbox}
+}
+
+// start returns the browser context, starting Chrome if need be.
+func (b *BrowserManager) start() (context.Context, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.browser != nil && b.browser.Err() == nil {
+		return b.browser, nil
+	}
+	var alloc context.Context
+	var cancelAlloc context.CancelFunc
+	if b.remoteURL != "" {
+		alloc, cancelAlloc = chromedp.NewRemoteAllocator(context.Background(), b.remoteURL)
+	} else {
+		opts := append(chromedp.DefaultExecAllocatorOptions[:], chromedp.WindowSize(screenshotWidth, screenshotHeight))
+		if b.execPath != "" {
+			opts = append(opts, chromedp.ExecPath(b.execPath))
+		}
+		if b.noSandbox {
+			opts = append(opts, chromedp.NoSandbox)
+		}
+		alloc, cancelAlloc = chromedp.NewExecAllocator(context.Background(), opts...)
+	}
+	browser, cancelBrowser := chromedp.NewContext(alloc)
+	// Running no actions starts the browser.
+	if err := chromedp.Run(browser); err != nil {
+		cancelBrowser()
+		cancelAlloc()
+		return nil, fmt.Errorf("failed to start browser: %w", err)
+	}
+	slog.Info("Browser started", "remote", b.remoteURL != "")
+	b.browser = browser
+	b.cancel = func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+	return browser, nil
+}
+
+// Close stops Chrome, or disconnects from it. The manager starts it
+// again if used afterwards.
+func (b *BrowserManager) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		b.cancel()
+		b.browser, b.cancel = nil, nil
+	}
+}
+
+// Screenshot loads rawURL in a new tab and returns a PNG of the viewport,
+// or of the whole page if fullPage, taken settle after the page loads
+// (a second if zero, at most ten).
+func (b *BrowserManager) Screenshot(ctx context.Context, rawURL string, fullPage bool, settle time.Duration) ([]byte, error) {
+	if err := ValidateURL(ctx, rawURL); err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if settle <= 0 {
+		settle = defaultSettle
+	}
+	settle = min(settle, maxSettle)
+	browser, err := b.start()
+	if err != nil {
+		return nil, err
+	}
+	tab, cancel := chromedp.NewContext(browser)
+	defer cancel()
+	tab, cancelTimeout := context.WithTimeout(tab, browserTimeout)
+	defer cancelTimeout()
+	// The tab belongs to the browser, so stop it when the caller gives up.
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	guardRequests(ctx, tab)
+	var buf []byte
+	capture := chromedp.CaptureScreenshot(&buf)
+	if fullPage {
+		capture = chromedp.FullScreenshot(&buf, 100)
+	}
+	if err := chromedp.Run(tab,
+		fetch.Enable(),
+		chromedp.EmulateViewport(screenshotWidth, screenshotHeight),
+		chromedp.Navigate(rawURL),
+		chromedp.Sleep(settle),
+		capture,
+	); err != nil {
+		return nil, fmt.Errorf("failed to screenshot %s: %w", rawURL, err)
+	}
+	return buf, nil
+}
+
+// guardRequests checks every request the tab makes, intercepted with the
+// Fetch domain, failing those ValidateURL rejects.
+func guardRequests(ctx, tab context.Context) {
+	chromedp.ListenTarget(tab, func(ev any) {
+		e, ok := ev.(*fetch.EventRequestPaused)
+		if !ok {
+			return
+		}
+		// ValidateURL resolves the host, which mustn't hold up the tab's
+		// event loop.
+		go func() {
+			c := chromedp.FromContext(tab)
+			if c == nil || c.Target == nil {
+				return
+			}
+			exec := cdp.WithExecutor(tab, c.Target)
+			u := e.Request.URL
+			if !strings.HasPrefix(u, "data:") && !strings.HasPrefix(u, "blob:") {
+				if err := ValidateURL(ctx, u); err != nil {
+					slog.Warn("Browser: blocked request", "url", u, "error", err)
+					_ = fetch.FailRequest(e.RequestID, network.ErrorReasonBlockedByClient).Do(exec)
+					return
+				}
+			}
+			_ = fetch.ContinueRequest(e.RequestID).Do(exec)
+		}()
+	})
+}
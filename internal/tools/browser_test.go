@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+)
+
+func TestBrowserScreenshot(t *testing.T) {
+	var chrome string
+	for _, name := range []string{"chromium", "chromium-browser", "google-chrome", "headless-shell"} {
+		if p, err := exec.LookPath(name); err == nil {
+			chrome = p
+			break
+		}
+	}
+	if chrome == "" {
+		t.Skip("no Chrome found")
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body style="height:3000px"><h1>Dashboard</h1></body></html>`))
+	}))
+	defer srv.Close()
+	b := NewBrowserManager("", chrome, true)
+	defer b.Close()
+	ctx := context.Background()
+
+	png, err := b.Screenshot(ctx, srv.URL, true, 0)
+	if err != nil {
+		t.Fatalf("Screenshot failed: %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG")) {
+		t.Errorf("not a PNG: %q", png[:min(len(png), 8)])
+	}
+	if _, err := b.Screenshot(ctx, "file:///etc/passwd", false, 0); err == nil {
+		t.Error("expected error for a file URL")
+	}
+}
@@ -0,0 +1,657 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Calculate evaluates expr deterministically, in one of three forms:
+//
+//   - arithmetic, exact on arbitrarily large rationals: + - * / ^ mod,
+//     n% and n!, parentheses, pi and e, and functions such as sqrt, ln,
+//     log, exp, sin, round and max (those with irrational results are
+//     computed in floating point);
+//   - a unit conversion, "<expression> <unit> to <unit>", e.g.
+//     "26.2 mi to km" or "98.6 F in C";
+//   - date arithmetic on YYYY-MM-DD dates (optionally with HH:MM), today,
+//     now, tomorrow and yesterday: "2026-03-01 + 90 days", "2026-12-25 -
+//     today", "days between 2026-01-01 and 2026-07-04", "days until
+//     2026-12-25".
+//
+// now is the current time for the date keywords, in the user's location.
+func Calculate(expr string, now time.Time) (string, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return "", fmt.Errorf("expression is required")
+	}
+	if res, ok, err := convertUnits(expr); ok {
+		return res, err
+	}
+	if res, ok, err := dateArithmetic(expr, now); ok {
+		return res, err
+	}
+	v, err := evalArithmetic(expr)
+	if err != nil {
+		return "", err
+	}
+	return v.String(), nil
+}
+
+// calcValue is a number: an exact rational, or a float once an operation
+// has no rational result.
+type calcValue struct {
+	r *big.Rat // nil for a float
+	f float64
+}
+
+// maxExactExponent bounds the exponents and factorials computed exactly,
+// so a request can't exhaust memory.
+const maxExactExponent = 10000
+
+func exact(r *big.Rat) calcValue  { return calcValue{r: r} }
+func inexact(f float64) calcValue { return calcValue{f: f} }
+func exactInt(n int64) calcValue  { return exact(new(big.Rat).SetInt64(n)) }
+func (v calcValue) isExact() bool { return v.r != nil }
+func (v calcValue) isInt() bool   { return v.r != nil && v.r.IsInt() }
+func (v calcValue) float() float64 {
+	if v.r == nil {
+		return v.f
+	}
+	f, _ := v.r.Float64()
+	return f
+}
+
+// String formats v exactly when it is an integer or a terminating decimal,
+// and to 15 significant digits otherwise.
+func (v calcValue) String() string {
+	if v.r == nil {
+		if math.IsInf(v.f, 0) || math.IsNaN(v.f) {
+			return strconv.FormatFloat(v.f, 'g', -1, 64)
+		}
+		return strconv.FormatFloat(v.f, 'g', 15, 64)
+	}
+	if v.r.IsInt() {
+		return v.r.Num().String()
+	}
+	// A decimal terminates when the denominator has no factors but 2 and 5.
+	d := new(big.Int).Set(v.r.Denom())
+	digits := 0
+	for _, p := range []int64{2, 5} {
+		bp := big.NewInt(p)
+		for n := 0; new(big.Int).Mod(d, bp).Sign() == 0; n++ {
+			d.Div(d, bp)
+			if pn := n + 1; pn > digits {
+				digits = pn
+			}
+		}
+	}
+	if d.Cmp(big.NewInt(1)) == 0 && digits <= 50 {
+		return v.r.FloatString(digits)
+	}
+	return strings.TrimRight(strings.TrimRight(v.r.FloatString(15), "0"), ".") + " (" + v.r.String() + ")"
+}
+
+func (v calcValue) op(w calcValue, exactOp func(z, x, y *big.Rat) *big.Rat, floatOp func(x, y float64) float64) calcValue {
+	if v.isExact() && w.isExact() {
+		return exact(exactOp(new(big.Rat), v.r, w.r))
+	}
+	return inexact(floatOp(v.float(), w.float()))
+}
+
+func (v calcValue) pow(w calcValue) (calcValue, error) {
+	if v.isExact() && w.isInt() && w.r.Num().IsInt64() && abs64(w.r.Num().Int64()) <= maxExactExponent {
+		n := w.r.Num().Int64()
+		if n < 0 && v.r.Sign() == 0 {
+			return calcValue{}, fmt.Errorf("division by zero")
+		}
+		num := new(big.Int).Exp(v.r.Num(), big.NewInt(abs64(n)), nil)
+		den := new(big.Int).Exp(v.r.Denom(), big.NewInt(abs64(n)), nil)
+		if n < 0 {
+			num, den = den, num
+		}
+		return exact(new(big.Rat).SetFrac(num, den)), nil
+	}
+	return inexact(math.Pow(v.float(), w.float())), nil
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// calcFuncs are the functions of one argument.
+var calcFuncs = map[string]func(float64) float64{
+	"sqrt": math.Sqrt, "cbrt": math.Cbrt, "ln": math.Log, "log": math.Log10, "log10": math.Log10, "log2": math.Log2,
+	"exp": math.Exp, "sin": math.Sin, "cos": math.Cos, "tan": math.Tan, "asin": math.Asin, "acos": math.Acos,
+	"atan": math.Atan, "sinh": math.Sinh, "cosh": math.Cosh, "tanh": math.Tanh,
+}
+
+// calcParser evaluates arithmetic by recursive descent:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/" | "mod" | "%") unary }
+//	unary   = ("-" | "+") unary | power
+//	power   = postfix [ "^" unary ]
+//	postfix = primary { "!" | "%" }
+//	primary = number | name | name "(" expr { "," expr } ")" | "(" expr ")"
+type calcParser struct {
+	toks []string
+	pos  int
+}
+
+var calcToken = regexp.MustCompile(`^(\d+(\.\d*)?([eE][-+]?\d+)?|\.\d+([eE][-+]?\d+)?|[a-zA-Z_][a-zA-Z_0-9]*|\*\*|[-+*/^%!(),×÷])`)
+
+func evalArithmetic(expr string) (calcValue, error) {
+	p := &calcParser{}
+	for s := strings.TrimSpace(expr); s != ""; s = strings.TrimLeftFunc(s, unicode.IsSpace) {
+		tok := calcToken.FindString(s)
+		if tok == "" {
+			return calcValue{}, fmt.Errorf("unexpected %q", firstRune(s))
+		}
+		switch tok {
+		case "**":
+			p.toks = append(p.toks, "^")
+		case "×":
+			p.toks = append(p.toks, "*")
+		case "÷":
+			p.toks = append(p.toks, "/")
+		default:
+			p.toks = append(p.toks, tok)
+		}
+		s = s[len(tok):]
+	}
+	v, err := p.expr()
+	if err != nil {
+		return calcValue{}, err
+	}
+	if p.pos < len(p.toks) {
+		return calcValue{}, fmt.Errorf("unexpected %q", p.toks[p.pos])
+	}
+	return v, nil
+}
+
+func firstRune(s string) string {
+	for _, r := range s {
+		return string(r)
+	}
+	return ""
+}
+
+func (p *calcParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *calcParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *calcParser) expr() (calcValue, error) {
+	v, err := p.term()
+	for err == nil && (p.peek() == "+" || p.peek() == "-") {
+		op := p.next()
+		var w calcValue
+		if w, err = p.term(); err != nil {
+			break
+		}
+		if op == "+" {
+			v = v.op(w, (*big.Rat).Add, func(x, y float64) float64 { return x + y })
+		} else {
+			v = v.op(w, (*big.Rat).Sub, func(x, y float64) float64 { return x - y })
+		}
+	}
+	return v, err
+}
+
+func (p *calcParser) term() (calcValue, error) {
+	v, err := p.unary()
+	for err == nil {
+		op := p.peek()
+		if op != "*" && op != "/" && op != "mod" && op != "%" {
+			break
+		}
+		p.next()
+		var w calcValue
+		if w, err = p.unary(); err != nil {
+			break
+		}
+		switch op {
+		case "*":
+			v = v.op(w, (*big.Rat).Mul, func(x, y float64) float64 { return x * y })
+		case "/":
+			if w.float() == 0 {
+				return calcValue{}, fmt.Errorf("division by zero")
+			}
+			v = v.op(w, (*big.Rat).Quo, func(x, y float64) float64 { return x / y })
+		default:
+			if w.float() == 0 {
+				return calcValue{}, fmt.Errorf("division by zero")
+			}
+			if v.isInt() && w.isInt() {
+				v = exact(new(big.Rat).SetInt(new(big.Int).Rem(v.r.Num(), w.r.Num())))
+			} else {
+				v = inexact(math.Mod(v.float(), w.float()))
+			}
+		}
+	}
+	return v, err
+}
+
+func (p *calcParser) unary() (calcValue, error) {
+	switch p.peek() {
+	case "-":
+		p.next()
+		v, err := p.unary()
+		if err != nil {
+			return v, err
+		}
+		if v.isExact() {
+			return exact(new(big.Rat).Neg(v.r)), nil
+		}
+		return inexact(-v.f), nil
+	case "+":
+		p.next()
+		return p.unary()
+	}
+	return p.power()
+}
+
+func (p *calcParser) power() (calcValue, error) {
+	v, err := p.postfix()
+	if err != nil || p.peek() != "^" {
+		return v, err
+	}
+	p.next()
+	w, err := p.unary()
+	if err != nil {
+		return v, err
+	}
+	return v.pow(w)
+}
+
+func (p *calcParser) postfix() (calcValue, error) {
+	v, err := p.primary()
+	for err == nil {
+		switch p.peek() {
+		case "!":
+			p.next()
+			if !v.isInt() || v.r.Sign() < 0 || v.r.Num().Cmp(big.NewInt(maxExactExponent)) > 0 {
+				return v, fmt.Errorf("factorial needs a whole number from 0 to %d", maxExactExponent)
+			}
+			f := new(big.Int).MulRange(1, v.r.Num().Int64())
+			v = exact(new(big.Rat).SetInt(f))
+		case "%":
+			// A percentage, unless an operand follows (then it is mod).
+			if p.pos+1 < len(p.toks) && startsOperand(p.toks[p.pos+1]) {
+				return v, nil
+			}
+			p.next()
+			v = v.op(exactInt(100), (*big.Rat).Quo, func(x, y float64) float64 { return x / y })
+		default:
+			return v, nil
+		}
+	}
+	return v, err
+}
+
+func startsOperand(tok string) bool {
+	c := tok[0]
+	return c == '(' || c == '.' || unicode.IsDigit(rune(c)) || unicode.IsLetter(rune(c)) && tok != "mod"
+}
+
+func (p *calcParser) primary() (calcValue, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return calcValue{}, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		v, err := p.expr()
+		if err != nil {
+			return v, err
+		}
+		if p.next() != ")" {
+			return v, fmt.Errorf("missing )")
+		}
+		return v, nil
+	case tok[0] == '.' || unicode.IsDigit(rune(tok[0])):
+		r, ok := new(big.Rat).SetString(tok)
+		if !ok {
+			return calcValue{}, fmt.Errorf("invalid number %q", tok)
+		}
+		return exact(r), nil
+	case unicode.IsLetter(rune(tok[0])) || tok[0] == '_':
+		name := strings.ToLower(tok)
+		if p.peek() != "(" {
+			switch name {
+			case "pi":
+				return inexact(math.Pi), nil
+			case "e":
+				return inexact(math.E), nil
+			}
+			return calcValue{}, fmt.Errorf("unknown name %q", tok)
+		}
+		p.next()
+		var args []calcValue
+		for {
+			v, err := p.expr()
+			if err != nil {
+				return v, err
+			}
+			args = append(args, v)
+			if sep := p.next(); sep == ")" {
+				break
+			} else if sep != "," {
+				return v, fmt.Errorf("missing ) after the arguments of %s", tok)
+			}
+		}
+		return callCalcFunc(name, args)
+	}
+	return calcValue{}, fmt.Errorf("unexpected %q", tok)
+}
+
+func callCalcFunc(name string, args []calcValue) (calcValue, error) {
+	switch name {
+	case "min", "max":
+		best := args[0]
+		for _, a := range args[1:] {
+			if c := a.op(best, (*big.Rat).Sub, func(x, y float64) float64 { return x - y }).float(); (name == "min") == (c < 0) && c != 0 {
+				best = a
+			}
+		}
+		return best, nil
+	}
+	if len(args) != 1 {
+		return calcValue{}, fmt.Errorf("%s takes one argument", name)
+	}
+	a := args[0]
+	switch name {
+	case "abs":
+		if a.isExact() {
+			return exact(new(big.Rat).Abs(a.r)), nil
+		}
+		return inexact(math.Abs(a.f)), nil
+	case "round", "floor", "ceil", "trunc":
+		if !a.isExact() {
+			f := map[string]func(float64) float64{"round": math.Round, "floor": math.Floor, "ceil": math.Ceil, "trunc": math.Trunc}[name]
+			return inexact(f(a.f)), nil
+		}
+		return exact(new(big.Rat).SetInt(roundRat(a.r, name))), nil
+	case "sqrt":
+		// Perfect squares stay exact.
+		if a.isExact() && a.r.Sign() >= 0 {
+			n, d := new(big.Int).Sqrt(a.r.Num()), new(big.Int).Sqrt(a.r.Denom())
+			if r := new(big.Rat).SetFrac(n, d); new(big.Rat).Mul(r, r).Cmp(a.r) == 0 {
+				return exact(r), nil
+			}
+		}
+	}
+	f, ok := calcFuncs[name]
+	if !ok {
+		return calcValue{}, fmt.Errorf("unknown function %q", name)
+	}
+	return inexact(f(a.float())), nil
+}
+
+// roundRat rounds r to an integer: half away from zero for round.
+func roundRat(r *big.Rat, mode string) *big.Int {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() == 0 {
+		return q
+	}
+	neg := r.Sign() < 0
+	switch mode {
+	case "floor":
+		if neg {
+			q.Sub(q, big.NewInt(1))
+		}
+	case "ceil":
+		if !neg {
+			q.Add(q, big.NewInt(1))
+		}
+	case "round":
+		twice := new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2))
+		if twice.Cmp(r.Denom()) >= 0 {
+			if neg {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+	return q
+}
+
+// calcUnit is a unit: its dimension and its size in the dimension's base
+// unit. Temperatures convert with offsets instead.
+type calcUnit struct {
+	dim    string
+	factor string
+}
+
+var calcUnits = map[string]calcUnit{
+	// Length, in metres.
+	"mm": {"length", "0.001"}, "cm": {"length", "0.01"}, "m": {"length", "1"}, "km": {"length", "1000"},
+	"in": {"length", "0.0254"}, "inch": {"length", "0.0254"}, "inches": {"length", "0.0254"},
+	"ft": {"length", "0.3048"}, "foot": {"length", "0.3048"}, "feet": {"length", "0.3048"},
+	"yd": {"length", "0.9144"}, "yard": {"length", "0.9144"}, "yards": {"length", "0.9144"},
+	"mi": {"length", "1609.344"}, "mile": {"length", "1609.344"}, "miles": {"length", "1609.344"},
+	"nmi": {"length", "1852"}, "meter": {"length", "1"}, "meters": {"length", "1"}, "metre": {"length", "1"}, "metres": {"length", "1"},
+	"kilometer": {"length", "1000"}, "kilometers": {"length", "1000"},
+	// Mass, in grams.
+	"mg": {"mass", "0.001"}, "g": {"mass", "1"}, "kg": {"mass", "1000"}, "t": {"mass", "1000000"}, "tonne": {"mass", "1000000"},
+	"oz": {"mass", "28.349523125"}, "lb": {"mass", "453.59237"}, "lbs": {"mass", "453.59237"}, "pound": {"mass", "453.59237"}, "pounds": {"mass", "453.59237"},
+	"st": {"mass", "6350.29318"}, "ton": {"mass", "907184.74"},
+	// Time, in seconds.
+	"ms": {"time", "0.001"}, "s": {"time", "1"}, "sec": {"time", "1"}, "second": {"time", "1"}, "seconds": {"time", "1"},
+	"min": {"time", "60"}, "minute": {"time", "60"}, "minutes": {"time", "60"},
+	"h": {"time", "3600"}, "hr": {"time", "3600"}, "hour": {"time", "3600"}, "hours": {"time", "3600"},
+	"day": {"time", "86400"}, "days": {"time", "86400"}, "week": {"time", "604800"}, "weeks": {"time", "604800"},
+	"year": {"time", "31557600"}, "years": {"time", "31557600"},
+	// Data, in bytes.
+	"b": {"data", "1"}, "byte": {"data", "1"}, "bytes": {"data", "1"}, "bit": {"data", "0.125"}, "bits": {"data", "0.125"},
+	"kb": {"data", "1000"}, "mb": {"data", "1000000"}, "gb": {"data", "1000000000"}, "tb": {"data", "1000000000000"}, "pb": {"data", "1000000000000000"},
+	"kib": {"data", "1024"}, "mib": {"data", "1048576"}, "gib": {"data", "1073741824"}, "tib": {"data", "1099511627776"},
+	// Volume, in litres.
+	"ml": {"volume", "0.001"}, "l": {"volume", "1"}, "liter": {"volume", "1"}, "liters": {"volume", "1"}, "litre": {"volume", "1"}, "litres": {"volume", "1"},
+	"gal": {"volume", "3.785411784"}, "gallon": {"volume", "3.785411784"}, "gallons": {"volume", "3.785411784"},
+	"qt": {"volume", "0.946352946"}, "pt": {"volume", "0.473176473"}, "cup": {"volume", "0.2365882365"}, "cups": {"volume", "0.2365882365"},
+	"floz": {"volume", "0.0295735295625"},
+	// Speed, in metres per second.
+	"m/s": {"speed", "1"}, "km/h": {"speed", "5/18"}, "kph": {"speed", "5/18"}, "mph": {"speed", "0.44704"},
+	"knot": {"speed", "463/900"}, "knots": {"speed", "463/900"}, "kn": {"speed", "463/900"}, "ft/s": {"speed", "0.3048"},
+	// Temperature.
+	"c": {"temperature", ""}, "°c": {"temperature", ""}, "celsius": {"temperature", ""},
+	"f": {"temperature", ""}, "°f": {"temperature", ""}, "fahrenheit": {"temperature", ""},
+	"k": {"temperature", ""}, "kelvin": {"temperature", ""},
+}
+
+var conversionPattern = regexp.MustCompile(`^(.*?)\s*([a-zA-Z°/]+)\s+(?:to|in|as)\s+([a-zA-Z°/]+)$`)
+
+// convertUnits handles "<expression> <unit> to <unit>"; ok is false if
+// expr isn't a conversion.
+func convertUnits(expr string) (string, bool, error) {
+	m := conversionPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return "", false, nil
+	}
+	from, ok1 := calcUnits[strings.ToLower(m[2])]
+	to, ok2 := calcUnits[strings.ToLower(m[3])]
+	if !ok1 || !ok2 {
+		return "", false, nil
+	}
+	if from.dim != to.dim {
+		return "", true, fmt.Errorf("can't convert %s (%s) to %s (%s)", m[2], from.dim, m[3], to.dim)
+	}
+	v, err := evalArithmetic(m[1])
+	if err != nil {
+		return "", true, err
+	}
+	var out calcValue
+	if from.dim == "temperature" {
+		out = convertTemperature(v, tempScale(m[2]), tempScale(m[3]))
+	} else {
+		f, _ := new(big.Rat).SetString(from.factor)
+		t, _ := new(big.Rat).SetString(to.factor)
+		out = v.op(exact(f), (*big.Rat).Mul, func(x, y float64) float64 { return x * y })
+		out = out.op(exact(t), (*big.Rat).Quo, func(x, y float64) float64 { return x / y })
+	}
+	return fmt.Sprintf("%s %s = %s %s", v, m[2], out, m[3]), true, nil
+}
+
+func tempScale(unit string) byte {
+	return strings.TrimPrefix(strings.ToLower(unit), "°")[0]
+}
+
+func convertTemperature(v calcValue, from, to byte) calcValue {
+	rat := func(s string) calcValue { r, _ := new(big.Rat).SetString(s); return exact(r) }
+	add := func(a, b calcValue) calcValue {
+		return a.op(b, (*big.Rat).Add, func(x, y float64) float64 { return x + y })
+	}
+	mul := func(a, b calcValue) calcValue {
+		return a.op(b, (*big.Rat).Mul, func(x, y float64) float64 { return x * y })
+	}
+	// Through Celsius.
+	c := v
+	switch from {
+	case 'f':
+		c = mul(add(v, rat("-32")), rat("5/9"))
+	case 'k':
+		c = add(v, rat("-273.15"))
+	}
+	switch to {
+	case 'f':
+		return add(mul(c, rat("9/5")), rat("32"))
+	case 'k':
+		return add(c, rat("273.15"))
+	}
+	return c
+}
+
+var (
+	calcDate       = `(\d{4}-\d{2}-\d{2}(?:[ T]\d{1,2}:\d{2})?|today|now|tomorrow|yesterday)`
+	dateTerm       = regexp.MustCompile(`(?i)^` + calcDate)
+	dateOffset     = regexp.MustCompile(`(?i)^\s*([-+])\s*(\d+)\s*(minutes?|mins?|hours?|hrs?|h|days?|d|weeks?|w|months?|years?|y)\b`)
+	dateDifference = regexp.MustCompile(`(?i)^` + calcDate + `\s*-\s*` + calcDate + `$`)
+	daysBetween    = regexp.MustCompile(`(?i)^(?:days|time)\s+between\s+` + calcDate + `\s+and\s+` + calcDate + `$`)
+	daysUntil      = regexp.MustCompile(`(?i)^(?:days|time)\s+(until|since)\s+` + calcDate + `$`)
+)
+
+// dateArithmetic handles date expressions; ok is false if expr isn't one.
+func dateArithmetic(expr string, now time.Time) (string, bool, error) {
+	if m := dateDifference.FindStringSubmatch(expr); m != nil {
+		return dateDiff(m[2], m[1], now)
+	}
+	if m := daysBetween.FindStringSubmatch(expr); m != nil {
+		return dateDiff(m[1], m[2], now)
+	}
+	if m := daysUntil.FindStringSubmatch(expr); m != nil {
+		if strings.EqualFold(m[1], "until") {
+			return dateDiff("today", m[2], now)
+		}
+		return dateDiff(m[2], "today", now)
+	}
+	m := dateTerm.FindString(expr)
+	if m == "" {
+		return "", false, nil
+	}
+	t, withTime, err := parseCalcDate(m, now)
+	if err != nil {
+		return "", true, err
+	}
+	for rest := expr[len(m):]; strings.TrimSpace(rest) != ""; {
+		o := dateOffset.FindStringSubmatch(rest)
+		if o == nil {
+			return "", true, fmt.Errorf("expected + or - a number of days, weeks, months or years, got %q", strings.TrimSpace(rest))
+		}
+		rest = rest[len(o[0]):]
+		n, err := strconv.Atoi(o[2])
+		if err != nil || n > 100000 {
+			return "", true, fmt.Errorf("invalid offset %q", o[2])
+		}
+		if o[1] == "-" {
+			n = -n
+		}
+		switch unit := strings.ToLower(o[3]); {
+		case strings.HasPrefix(unit, "min"):
+			t, withTime = t.Add(time.Duration(n)*time.Minute), true
+		case strings.HasPrefix(unit, "h"):
+			t, withTime = t.Add(time.Duration(n)*time.Hour), true
+		case strings.HasPrefix(unit, "d"):
+			t = t.AddDate(0, 0, n)
+		case strings.HasPrefix(unit, "w"):
+			t = t.AddDate(0, 0, 7*n)
+		case strings.HasPrefix(unit, "mo"):
+			t = t.AddDate(0, n, 0)
+		default:
+			t = t.AddDate(n, 0, 0)
+		}
+	}
+	if withTime {
+		return t.Format("Monday, 2006-01-02 15:04 MST"), true, nil
+	}
+	return t.Format("Monday, 2006-01-02"), true, nil
+}
+
+// dateDiff describes the time from a to b.
+func dateDiff(a, b string, now time.Time) (string, bool, error) {
+	from, timeA, err := parseCalcDate(a, now)
+	if err != nil {
+		return "", true, err
+	}
+	to, timeB, err := parseCalcDate(b, now)
+	if err != nil {
+		return "", true, err
+	}
+	if timeA || timeB {
+		d := to.Sub(from)
+		return fmt.Sprintf("%s (%.2f days)", d.Round(time.Minute), d.Hours()/24), true, nil
+	}
+	// Calendar days, unaffected by DST changes in between.
+	days := int(math.Round(time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC).Sub(time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24))
+	res := plural(days, "day")
+	if w := days / 7; w != 0 {
+		res += fmt.Sprintf(" (%s and %s)", plural(w, "week"), plural(days%7, "day"))
+	}
+	return res, true, nil
+}
+
+func plural(n int, unit string) string {
+	if n == 1 || n == -1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// parseCalcDate reads a date or date keyword, reporting whether it has a
+// time of day.
+func parseCalcDate(s string, now time.Time) (time.Time, bool, error) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(s) {
+	case "now":
+		return now, true, nil
+	case "today":
+		return day, false, nil
+	case "tomorrow":
+		return day.AddDate(0, 0, 1), false, nil
+	case "yesterday":
+		return day.AddDate(0, 0, -1), false, nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, layout != "2006-01-02", nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid date %q", s)
+}
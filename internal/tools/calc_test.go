@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalculate(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		expr, want string
+	}{
+		{"1 + 2 * 3", "7"},
+		{"(1 + 2) * 3", "9"},
+		{"-2^2", "-4"},
+		{"2^3^2", "512"},
+		{"0.1 + 0.2", "0.3"},
+		{"1/3", "0.333333333333333 (1/3)"},
+		{"7 / 4", "1.75"},
+		{"2^100", "1267650600228229401496703205376"},
+		{"25!", "15511210043330985984000000"},
+		{"17 mod 5", "2"},
+		{"17 % 5", "2"},
+		{"15% * 200", "30"},
+		{"1200 * (1 + 5%)^2", "1323"},
+		{"sqrt(144)", "12"},
+		{"sqrt(2)", "1.4142135623731"},
+		{"round(2.5) + floor(-1.5) + ceil(1.2)", "3"},
+		{"max(3, 9, 4) - min(3, 9, 4)", "6"},
+		{"abs(-3.5)", "3.5"},
+		{"log(1000)", "3"},
+		{"2 × 3 ÷ 4", "1.5"},
+		{"26.2 mi to km", "26.2 mi = 42.1648128 km"},
+		{"100 F to C", "100 F = 37.777777777777778 (340/9) C"},
+		{"0 C in K", "0 C = 273.15 K"},
+		{"1 GiB in MB", "1 GiB = 1073.741824 MB"},
+		{"90 minutes to hours", "90 minutes = 1.5 hours"},
+		{"60 mph to km/h", "60 mph = 96.56064 km/h"},
+		{"2026-03-01 + 90 days", "Saturday, 2026-05-30"},
+		{"2026-01-31 + 1 month", "Tuesday, 2026-03-03"},
+		{"today + 2 weeks - 1 day", "Tuesday, 2026-10-27"},
+		{"2026-12-25 - today", "72 days (10 weeks and 2 days)"},
+		{"days between 2026-01-01 and 2026-07-04", "184 days (26 weeks and 2 days)"},
+		{"days until 2026-10-15", "1 day"},
+		{"now + 90 minutes", "Wednesday, 2026-10-14 11:00 UTC"},
+	}
+	for _, tt := range tests {
+		got, err := Calculate(tt.expr, now)
+		if err != nil {
+			t.Errorf("Calculate(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Calculate(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+
+	for expr, want := range map[string]string{
+		"":              "expression is required",
+		"1 / 0":         "division by zero",
+		"2 +":           "unexpected end",
+		"(1 + 2":        "missing )",
+		"foo(2)":        "unknown function",
+		"x + 1":         "unknown name",
+		"5 kg to km":    "can't convert",
+		"1.5!":          "factorial",
+		"today + a day": "expected + or -",
+	} {
+		if _, err := Calculate(expr, now); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Calculate(%q) error = %v, want %q", expr, err, want)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	crawlTimeout = 20 * time.Second
+	// maxPageBytes caps the HTML read per page.
+	maxPageBytes = 2 << 20
+	// maxPageChars caps the text kept per page.
+	maxPageChars = 8000
+
+	DefaultCrawlDepth = 2
+	DefaultCrawlPages = 10
+	maxCrawlDepth     = 3
+	maxCrawlPages     = 30
+)
+
+// CrawledPage is the readable text of a crawled page.
+type CrawledPage struct {
+	URL   string
+	Title string
+	Text  string
+	Depth int
+}
+
+// CrawlSite reads the pages linked from start, breadth first, following
+// links on the same host up to depth links away (2 by default, at most 3)
+// and reading at most maxPages pages (10 by default, at most 30). Pages
+// that fail or aren't HTML are skipped; it fails only if start can't be
+// read.
+func CrawlSite(ctx context.Context, start string, depth, maxPages int) ([]CrawledPage, error) {
+	if depth <= 0 {
+		depth = DefaultCrawlDepth
+	}
+	if maxPages <= 0 {
+		maxPages = DefaultCrawlPages
+	}
+	depth, maxPages = min(depth, maxCrawlDepth), min(maxPages, maxCrawlPages)
+	root, err := url.Parse(strings.TrimSpace(start))
+	if err != nil || (root.Scheme != "http" && root.Scheme != "https") || root.Host == "" {
+		return nil, fmt.Errorf("invalid start URL %q", start)
+	}
+	root.Fragment = ""
+
+	type queued struct {
+		u     *url.URL
+		depth int
+	}
+	queue := []queued{{root, 0}}
+	seen := map[string]bool{crawlKey(root): true}
+	var pages []CrawledPage
+	for len(queue) > 0 && len(pages) < maxPages {
+		q := queue[0]
+		queue = queue[1:]
+		if ctx.Err() != nil {
+			return pages, ctx.Err()
+		}
+		page, links, err := fetchPage(ctx, q.u)
+		if err != nil {
+			if q.depth == 0 {
+				return nil, err
+			}
+			slog.Warn("Crawl: skipping page", "url", q.u.String(), "error", err)
+			continue
+		}
+		page.Depth = q.depth
+		pages = append(pages, page)
+		if q.depth == depth {
+			continue
+		}
+		for _, l := range links {
+			if !sameSite(root, l) || seen[crawlKey(l)] || !crawlable(l) {
+				continue
+			}
+			seen[crawlKey(l)] = true
+			queue = append(queue, queued{l, q.depth + 1})
+		}
+	}
+	return pages, nil
+}
+
+// fetchPage reads an HTML page, returning its text and the links on it.
+func fetchPage(ctx context.Context, u *url.URL) (CrawledPage, []*url.URL, error) {
+	doc, base, err := getHTML(ctx, newWebClient(crawlTimeout), u.String())
+	if err != nil {
+		return CrawledPage{}, nil, err
+	}
+	var t pageText
+	t.walk(doc)
+	page := CrawledPage{URL: base.String(), Title: strings.Join(strings.Fields(t.title), " "), Text: cleanPageText(t.sb.String())}
+	if r := []rune(page.Text); len(r) > maxPageChars {
+		page.Text = string(r[:maxPageChars]) + "…"
+	}
+	var links []*url.URL
+	for _, href := range t.links {
+		l, err := base.Parse(href)
+		if err != nil {
+			continue
+		}
+		l.Fragment = ""
+		links = append(links, l)
+	}
+	return page, links, nil
+}
+
+// PageStatusError is returned for a page that answers with a status other
+// than 200.
+type PageStatusError struct{ StatusCode int }
+
+func (e PageStatusError) Error() string {
+	return fmt.Sprintf("page returned status %d", e.StatusCode)
+}
+
+// getHTML fetches and parses an HTML page with client, returning it with the
+// URL it was redirected to, which its links resolve against.
+func getHTML(ctx context.Context, client *http.Client, rawURL string) (*html.Node, *url.URL, error) {
+	if err := ValidateURL(ctx, rawURL); err != nil {
+		return nil, nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html, application/xhtml+xml;q=0.9")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, PageStatusError{StatusCode: resp.StatusCode}
+	}
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt != "" && mt != "text/html" && mt != "application/xhtml+xml" {
+		return nil, nil, fmt.Errorf("page is %s, not HTML", mt)
+	}
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxPageBytes))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+	return doc, resp.Request.URL, nil
+}
+
+// pageText collects the readable text, title and links of an HTML page.
+type pageText struct {
+	sb    strings.Builder
+	title string
+	links []string
+}
+
+func (t *pageText) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		t.sb.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		switch n.DataAtom {
+		case atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Svg, atom.Iframe, atom.Nav, atom.Footer, atom.Form, atom.Button:
+			return
+		case atom.Title:
+			if n.FirstChild != nil && t.title == "" {
+				t.title = n.FirstChild.Data
+			}
+			return
+		case atom.A:
+			for _, a := range n.Attr {
+				if a.Key == "href" {
+					t.links = append(t.links, a.Val)
+				}
+			}
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			t.sb.WriteString("\n\n" + strings.Repeat("#", int(n.Data[1]-'0')) + " ")
+		case atom.Li:
+			t.sb.WriteString("\n- ")
+		case atom.Br:
+			t.sb.WriteString("\n")
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		t.walk(c)
+	}
+	if n.Type == html.ElementNode && isBlock(n.DataAtom) {
+		t.sb.WriteString("\n\n")
+	}
+}
+
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Main, atom.Header, atom.Aside,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Pre, atom.Blockquote, atom.Table, atom.Tr, atom.Dl, atom.Dt, atom.Dd:
+		return true
+	}
+	return false
+}
+
+// cleanPageText collapses spacing within lines and runs of blank lines.
+func cleanPageText(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		// Drop the markers of empty list items and headings.
+		if strings.Trim(line, "-# ") == "" {
+			line = ""
+		}
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// sameSite reports whether u is on root's host, ignoring a "www." prefix.
+func sameSite(root, u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.") == strings.TrimPrefix(root.Hostname(), "www.")
+}
+
+// crawlKey identifies a page regardless of scheme and trailing slash.
+func crawlKey(u *url.URL) string {
+	k := strings.TrimPrefix(u.Hostname(), "www.") + strings.TrimSuffix(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		k += "?" + u.RawQuery
+	}
+	return k
+}
+
+// skipExtensions are the files a crawl doesn't read as pages.
+var skipExtensions = map[string]bool{
+	".pdf": true, ".zip": true, ".gz": true, ".tar": true, ".png": true, ".jpg": true, ".jpeg": true,
+	".gif": true, ".svg": true, ".webp": true, ".ico": true, ".css": true, ".js": true, ".json": true,
+	".xml": true, ".mp4": true, ".mp3": true, ".woff": true, ".woff2": true, ".exe": true, ".dmg": true,
+}
+
+func crawlable(u *url.URL) bool {
+	return !skipExtensions[strings.ToLower(path.Ext(u.Path))]
+}
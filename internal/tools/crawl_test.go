@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCrawlSite(t *testing.T) {
+	pages := map[string]string{
+		"/docs/": `<html><head><title>Docs</title><script>var x = 1;</script></head><body>
+<nav><a href="/nav-only">Menu</a></nav>
+<h1>Getting started</h1><p>Install the   tool.</p>
+<ul><li><a href="guide">Guide</a></li><li><a href="/docs/api#auth">API</a></li><li><a href="https://example.com/">Elsewhere</a></li><li><a href="/file.zip">Download</a></li></ul>
+</body></html>`,
+		"/docs/guide": `<html><head><title>Guide</title></head><body><p>Read this.</p><a href="/docs/deep">Deeper</a><a href="/docs/">Home</a></body></html>`,
+		"/docs/api":   `<html><head><title>API</title></head><body><h2>Auth</h2><p>Use a token.</p></body></html>`,
+		"/docs/deep":  `<html><body><p>Too deep.</p></body></html>`,
+	}
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			requested = append(requested, r.URL.Path)
+		}
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	got, err := CrawlSite(ctx, srv.URL+"/docs/", 1, 0)
+	if err != nil {
+		t.Fatalf("CrawlSite failed: %v", err)
+	}
+	if len(got) != 3 || got[0].Title != "Docs" || got[1].Title != "Guide" || got[2].Title != "API" || got[2].Depth != 1 {
+		t.Fatalf("unexpected pages: %+v", got)
+	}
+	if want := "# Getting started\n\nInstall the tool.\n\n- Guide\n- API\n- Elsewhere\n- Download"; got[0].Text != want {
+		t.Errorf("text = %q, want %q", got[0].Text, want)
+	}
+	if strings.Join(requested, ",") != "/docs/,/docs/guide,/docs/api" {
+		t.Errorf("unexpected requests %v", requested)
+	}
+
+	requested = nil
+	if got, err := CrawlSite(ctx, srv.URL+"/docs/", 2, 0); err != nil || len(got) != 4 || got[3].Text != "Too deep." {
+		t.Errorf("unexpected pages at depth 2: %+v, %v", got, err)
+	}
+	if got, err := CrawlSite(ctx, srv.URL+"/docs/", 2, 2); err != nil || len(got) != 2 {
+		t.Errorf("expected the page budget to stop the crawl: %+v, %v", got, err)
+	}
+	if _, err := CrawlSite(ctx, srv.URL+"/missing", 1, 0); err == nil {
+		t.Error("expected error for a missing start page")
+	}
+	if _, err := CrawlSite(ctx, "ftp://example.com", 1, 0); err == nil {
+		t.Error("expected error for a non-HTTP URL")
+	}
+}
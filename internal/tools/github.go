@@ -0,0 +1,376 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	githubTimeout = 20 * time.Second
+	// maxGitHubBytes caps the API responses read.
+	maxGitHubBytes = 5 << 20
+	// maxGitHubText caps the issue bodies and comments returned, and
+	// maxGitHubDiff a commit's or pull request's patches.
+	maxGitHubText = 2000
+	maxGitHubDiff = 30000
+	// githubPageSize is how many items a list returns.
+	githubPageSize = 20
+)
+
+// githubAPI is the GitHub REST API; tests point it at a local server.
+var githubAPI = "https://api.github.com"
+
+var githubRepo = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// GitHub looks things up with the GitHub REST API, authenticated with a
+// personal access token. Its methods return Markdown for the model.
+type GitHub struct {
+	token  string
+	client *http.Client
+}
+
+// NewGitHub returns a GitHub client using token.
+func NewGitHub(token string) *GitHub {
+	return &GitHub{token: token, client: NewSafeClient(githubTimeout)}
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubIssue struct {
+	Number      int          `json:"number"`
+	Title       string       `json:"title"`
+	State       string       `json:"state"`
+	HTMLURL     string       `json:"html_url"`
+	Body        string       `json:"body"`
+	User        githubUser   `json:"user"`
+	Comments    int          `json:"comments"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	PullRequest *struct{}    `json:"pull_request"`
+	Labels      []githubName `json:"labels"`
+}
+
+type githubName struct {
+	Name string `json:"name"`
+}
+
+type githubFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Patch     string `json:"patch"`
+}
+
+// Issues lists a repository's issues, or pull requests if pulls, in state
+// (open, closed or all), most recently updated first. With a query it
+// searches them instead.
+func (g *GitHub) Issues(ctx context.Context, repo, state, query string, pulls bool) (string, error) {
+	if !githubRepo.MatchString(repo) {
+		return "", fmt.Errorf("invalid repository %q: use owner/name", repo)
+	}
+	if state == "" {
+		state = "open"
+	}
+	var issues []githubIssue
+	if query != "" {
+		kind := "is:issue"
+		if pulls {
+			kind = "is:pr"
+		}
+		q := "repo:" + repo + " " + kind + " " + query
+		if state != "all" {
+			q += " state:" + state
+		}
+		var resp struct {
+			Items []githubIssue `json:"items"`
+		}
+		if err := g.get(ctx, "/search/issues", url.Values{"q": {q}, "sort": {"updated"}, "per_page": {strconv.Itoa(githubPageSize)}}, &resp); err != nil {
+			return "", err
+		}
+		issues = resp.Items
+	} else {
+		// The issues list includes pull requests; asking for more leaves
+		// enough of the requested kind.
+		q := url.Values{"state": {state}, "sort": {"updated"}, "per_page": {"100"}}
+		if err := g.get(ctx, "/repos/"+repo+"/issues", q, &issues); err != nil {
+			return "", err
+		}
+	}
+	var sb strings.Builder
+	n := 0
+	for _, is := range issues {
+		if (is.PullRequest != nil) != pulls || n == githubPageSize {
+			continue
+		}
+		n++
+		sb.WriteString(fmt.Sprintf("- #%d %s (%s, @%s, %d comments, updated %s)%s %s\n", is.Number, is.Title, is.State, is.User.Login, is.Comments, is.UpdatedAt.Format("2006-01-02"), labels(is.Labels), is.HTMLURL))
+	}
+	if n == 0 {
+		return fmt.Sprintf("No %s %s match in %s.", state, kindName(pulls), repo), nil
+	}
+	return sb.String(), nil
+}
+
+// Issue returns an issue or pull request with its latest comments; for a
+// pull request also its changed files.
+func (g *GitHub) Issue(ctx context.Context, repo string, number int) (string, error) {
+	if !githubRepo.MatchString(repo) {
+		return "", fmt.Errorf("invalid repository %q: use owner/name", repo)
+	}
+	var is githubIssue
+	path := fmt.Sprintf("/repos/%s/issues/%d", repo, number)
+	if err := g.get(ctx, path, nil, &is); err != nil {
+		return "", err
+	}
+	kind := "issue"
+	if is.PullRequest != nil {
+		kind = "pull request"
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**#%d %s** (%s %s by @%s, opened %s)%s\n%s\n", is.Number, is.Title, is.State, kind, is.User.Login, is.CreatedAt.Format("2006-01-02"), labels(is.Labels), is.HTMLURL))
+	if body := truncateText(is.Body, maxGitHubText); body != "" {
+		sb.WriteString("\n" + body + "\n")
+	}
+
+	if is.PullRequest != nil {
+		var pr struct {
+			Merged       bool `json:"merged"`
+			Draft        bool `json:"draft"`
+			Additions    int  `json:"additions"`
+			Deletions    int  `json:"deletions"`
+			ChangedFiles int  `json:"changed_files"`
+			Base         struct {
+				Ref string `json:"ref"`
+			} `json:"base"`
+			Head struct {
+				Ref string `json:"ref"`
+			} `json:"head"`
+		}
+		if err := g.get(ctx, fmt.Sprintf("/repos/%s/pulls/%d", repo, number), nil, &pr); err != nil {
+			return "", err
+		}
+		sb.WriteString(fmt.Sprintf("\n%s → %s, %d files changed (+%d −%d), merged: %t, draft: %t\n", pr.Head.Ref, pr.Base.Ref, pr.ChangedFiles, pr.Additions, pr.Deletions, pr.Merged, pr.Draft))
+		var files []githubFile
+		if err := g.get(ctx, fmt.Sprintf("/repos/%s/pulls/%d/files", repo, number), url.Values{"per_page": {"100"}}, &files); err != nil {
+			return "", err
+		}
+		writeFiles(&sb, files)
+	}
+
+	if is.Comments > 0 {
+		// The latest comments matter most; the list is oldest first.
+		page := (is.Comments + 9) / 10
+		var comments []struct {
+			User      githubUser `json:"user"`
+			Body      string     `json:"body"`
+			CreatedAt time.Time  `json:"created_at"`
+		}
+		if err := g.get(ctx, path+"/comments", url.Values{"per_page": {"10"}, "page": {strconv.Itoa(page)}}, &comments); err != nil {
+			return "", err
+		}
+		sb.WriteString(fmt.Sprintf("\n**Comments** (%d, latest shown)\n", is.Comments))
+		for _, c := range comments {
+			sb.WriteString(fmt.Sprintf("- @%s (%s): %s\n", c.User.Login, c.CreatedAt.Format("2006-01-02"), strings.Join(strings.Fields(truncateText(c.Body, maxGitHubText/4)), " ")))
+		}
+	}
+	return sb.String(), nil
+}
+
+// SearchRepos searches repositories with GitHub's search syntax, e.g.
+// "language:go stars:>1000 mcp".
+func (g *GitHub) SearchRepos(ctx context.Context, query string) (string, error) {
+	var resp struct {
+		TotalCount int `json:"total_count"`
+		Items      []struct {
+			FullName    string    `json:"full_name"`
+			Description string    `json:"description"`
+			HTMLURL     string    `json:"html_url"`
+			Stars       int       `json:"stargazers_count"`
+			Language    string    `json:"language"`
+			PushedAt    time.Time `json:"pushed_at"`
+		} `json:"items"`
+	}
+	if err := g.get(ctx, "/search/repositories", url.Values{"q": {query}, "per_page": {"10"}}, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Items) == 0 {
+		return fmt.Sprintf("No repositories match %q.", query), nil
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d repositories match; the top %d:\n", resp.TotalCount, len(resp.Items)))
+	for _, r := range resp.Items {
+		sb.WriteString(fmt.Sprintf("- [%s](%s) ★%d %s, pushed %s", r.FullName, r.HTMLURL, r.Stars, r.Language, r.PushedAt.Format("2006-01-02")))
+		if r.Description != "" {
+			sb.WriteString(" — " + r.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// Notifications lists the token owner's unread notifications, or all
+// recent ones if all.
+func (g *GitHub) Notifications(ctx context.Context, all bool) (string, error) {
+	var notes []struct {
+		Reason     string    `json:"reason"`
+		Unread     bool      `json:"unread"`
+		UpdatedAt  time.Time `json:"updated_at"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Subject struct {
+			Title string `json:"title"`
+			Type  string `json:"type"`
+			URL   string `json:"url"`
+		} `json:"subject"`
+	}
+	if err := g.get(ctx, "/notifications", url.Values{"all": {strconv.FormatBool(all)}, "per_page": {strconv.Itoa(githubPageSize)}}, &notes); err != nil {
+		return "", err
+	}
+	if len(notes) == 0 {
+		return "No unread GitHub notifications.", nil
+	}
+	var sb strings.Builder
+	for _, n := range notes {
+		unread := ""
+		if n.Unread {
+			unread = " (unread)"
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s %q, %s, %s%s %s\n", n.Repository.FullName, n.Subject.Type, n.Subject.Title, n.Reason, n.UpdatedAt.Format("2006-01-02 15:04"), unread, webURL(n.Subject.URL)))
+	}
+	return sb.String(), nil
+}
+
+// Commit returns a commit's message, stats and diff.
+func (g *GitHub) Commit(ctx context.Context, repo, ref string) (string, error) {
+	if !githubRepo.MatchString(repo) {
+		return "", fmt.Errorf("invalid repository %q: use owner/name", repo)
+	}
+	var c struct {
+		SHA     string `json:"sha"`
+		HTMLURL string `json:"html_url"`
+		Commit  struct {
+			Message string `json:"message"`
+			Author  struct {
+				Name string    `json:"name"`
+				Date time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+		Stats struct {
+			Additions int `json:"additions"`
+			Deletions int `json:"deletions"`
+		} `json:"stats"`
+		Files []githubFile `json:"files"`
+	}
+	if err := g.get(ctx, "/repos/"+repo+"/commits/"+url.PathEscape(ref), nil, &c); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s** by %s on %s (+%d −%d)\n%s\n\n%s\n", c.SHA[:min(len(c.SHA), 12)], c.Commit.Author.Name, c.Commit.Author.Date.Format("2006-01-02"), c.Stats.Additions, c.Stats.Deletions, c.HTMLURL, strings.TrimSpace(c.Commit.Message)))
+	writeFiles(&sb, c.Files)
+	return sb.String(), nil
+}
+
+// writeFiles lists changed files with their patches, until maxGitHubDiff.
+func writeFiles(sb *strings.Builder, files []githubFile) {
+	sb.WriteString("\n**Files**\n")
+	budget := maxGitHubDiff
+	for _, f := range files {
+		sb.WriteString(fmt.Sprintf("- %s (%s, +%d −%d)\n", f.Filename, f.Status, f.Additions, f.Deletions))
+		if f.Patch == "" {
+			continue
+		}
+		if len(f.Patch) > budget {
+			sb.WriteString("  (diff omitted: too long)\n")
+			budget = 0
+			continue
+		}
+		budget -= len(f.Patch)
+		sb.WriteString("```diff\n" + f.Patch + "\n```\n")
+	}
+}
+
+func (g *GitHub) get(ctx context.Context, path string, q url.Values, v any) error {
+	endpoint := githubAPI + path
+	if len(q) > 0 {
+		endpoint += "?" + q.Encode()
+	}
+	if err := ValidateURL(ctx, endpoint); err != nil {
+		return fmt.Errorf("invalid GitHub URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", userAgent)
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body := io.LimitReader(resp.Body, maxGitHubBytes)
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(body).Decode(&e)
+		if e.Message != "" {
+			return fmt.Errorf("GitHub returned status %d: %s", resp.StatusCode, e.Message)
+		}
+		return fmt.Errorf("GitHub returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode GitHub response: %w", err)
+	}
+	return nil
+}
+
+func kindName(pulls bool) string {
+	if pulls {
+		return "pull requests"
+	}
+	return "issues"
+}
+
+func labels(ls []githubName) string {
+	if len(ls) == 0 {
+		return ""
+	}
+	names := make([]string, len(ls))
+	for i, l := range ls {
+		names[i] = l.Name
+	}
+	return " [" + strings.Join(names, ", ") + "]"
+}
+
+// webURL turns an API URL of an issue, pull request or commit into the
+// page people open.
+func webURL(apiURL string) string {
+	u := strings.Replace(apiURL, "https://api.github.com/repos/", "https://github.com/", 1)
+	u = strings.Replace(u, "/pulls/", "/pull/", 1)
+	return strings.Replace(u, "/commits/", "/commit/", 1)
+}
+
+func truncateText(s string, limit int) string {
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) > limit {
+		s = string(r[:limit]) + "…"
+	}
+	return s
+}
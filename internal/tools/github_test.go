@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitHub(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghp_test" || r.Header.Get("Accept") != "application/vnd.github+json" {
+			t.Errorf("missing headers on %s", r.URL.Path)
+		}
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/repos/golang/go/issues":
+			_, _ = w.Write([]byte(`[
+				{"number":2,"title":"cmd/go: faster builds","state":"open","html_url":"https://github.com/golang/go/pull/2","user":{"login":"rsc"},"pull_request":{}},
+				{"number":1,"title":"spec: iterators","state":"open","html_url":"https://github.com/golang/go/issues/1","user":{"login":"gri"},"comments":3,"labels":[{"name":"Proposal"}],"updated_at":"2026-03-10T12:00:00Z"}]`))
+		case "/search/issues":
+			_, _ = w.Write([]byte(`{"items":[]}`))
+		case "/repos/golang/go/issues/2":
+			_, _ = w.Write([]byte(`{"number":2,"title":"cmd/go: faster builds","state":"open","html_url":"https://github.com/golang/go/pull/2","body":"Caches more.","user":{"login":"rsc"},"comments":11,"pull_request":{}}`))
+		case "/repos/golang/go/pulls/2":
+			_, _ = w.Write([]byte(`{"additions":10,"deletions":2,"changed_files":1,"base":{"ref":"master"},"head":{"ref":"cache"}}`))
+		case "/repos/golang/go/pulls/2/files":
+			_, _ = w.Write([]byte(`[{"filename":"src/cmd/go/build.go","status":"modified","additions":10,"deletions":2,"patch":"@@ -1 +1 @@\n-old\n+new"}]`))
+		case "/repos/golang/go/issues/2/comments":
+			_, _ = w.Write([]byte(`[{"user":{"login":"gri"},"body":"LGTM\n\nthanks"}]`))
+		case "/repos/golang/go/commits/abc":
+			_, _ = w.Write([]byte(`{"sha":"abcdef0123456789","html_url":"https://github.com/golang/go/commit/abcdef0123456789","commit":{"message":"runtime: fix\n\nDetails.","author":{"name":"Rob"}},"stats":{"additions":1,"deletions":1},"files":[{"filename":"x.go","status":"modified","patch":"` + strings.Repeat("x", maxGitHubDiff+1) + `"}]}`))
+		case "/search/repositories":
+			_, _ = w.Write([]byte(`{"total_count":1,"items":[{"full_name":"golang/go","html_url":"https://github.com/golang/go","stargazers_count":120000,"language":"Go","description":"The Go programming language"}]}`))
+		case "/notifications":
+			_, _ = w.Write([]byte(`[{"reason":"review_requested","unread":true,"repository":{"full_name":"golang/go"},"subject":{"title":"faster builds","type":"PullRequest","url":"https://api.github.com/repos/golang/go/pulls/2"}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer srv.Close()
+	old := githubAPI
+	githubAPI = srv.URL
+	defer func() { githubAPI = old }()
+	gh := NewGitHub("ghp_test")
+	ctx := context.Background()
+
+	got, err := gh.Issues(ctx, "golang/go", "", "", false)
+	if err != nil || got != "- #1 spec: iterators (open, @gri, 3 comments, updated 2026-03-10) [Proposal] https://github.com/golang/go/issues/1\n" {
+		t.Errorf("unexpected issues %q, %v", got, err)
+	}
+	if got, _ := gh.Issues(ctx, "golang/go", "closed", "iterators", true); !strings.Contains(got, "No closed pull requests") || !strings.Contains(queries[1], "q=repo%3Agolang%2Fgo+is%3Apr+iterators+state%3Aclosed") {
+		t.Errorf("unexpected search %q, %q", got, queries[1])
+	}
+	if _, err := gh.Issues(ctx, "golang", "", "", false); err == nil {
+		t.Error("expected error for invalid repository")
+	}
+
+	got, err = gh.Issue(ctx, "golang/go", 2)
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	for _, want := range []string{"(open pull request by @rsc", "cache → master, 1 files changed (+10 −2)", "```diff\n@@ -1 +1 @@\n-old\n+new\n```", "- @gri (0001-01-01): LGTM thanks"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s", want, got)
+		}
+	}
+	if !strings.HasSuffix(queries[len(queries)-1], "page=2&per_page=10") {
+		t.Errorf("expected the last page of comments, got %q", queries[len(queries)-1])
+	}
+
+	got, err = gh.Commit(ctx, "golang/go", "abc")
+	if err != nil || !strings.Contains(got, "**abcdef012345** by Rob") || !strings.Contains(got, "(diff omitted: too long)") {
+		t.Errorf("unexpected commit %q, %v", got, err)
+	}
+
+	got, err = gh.Notifications(ctx, false)
+	if err != nil || !strings.Contains(got, `golang/go: PullRequest "faster builds", review_requested`) || !strings.Contains(got, "https://github.com/golang/go/pull/2") {
+		t.Errorf("unexpected notifications %q, %v", got, err)
+	}
+
+	got, err = gh.SearchRepos(ctx, "language:go")
+	if err != nil || !strings.Contains(got, "- [golang/go](https://github.com/golang/go) ★120000 Go") || !strings.Contains(got, "— The Go programming language") {
+		t.Errorf("unexpected repositories %q, %v", got, err)
+	}
+	if _, err := gh.Issue(ctx, "golang/missing", 1); err == nil || !strings.Contains(err.Error(), "404: Not Found") {
+		t.Errorf("expected a 404 error, got %v", err)
+	}
+}
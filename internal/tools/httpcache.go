@@ -0,0 +1,363 @@
+package tools
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultHTTPCacheBytes bounds the disk space of the HTTP cache.
+	DefaultHTTPCacheBytes = 64 << 20
+	// maxCacheEntryBytes is the largest response the cache stores.
+	maxCacheEntryBytes = 8 << 20
+	// maxCacheFreshness caps how long a response is reused without asking
+	// the server, whatever its Cache-Control says.
+	maxCacheFreshness = 24 * time.Hour
+	cacheFileExt      = ".cache"
+)
+
+// webCache is the HTTP cache of the web tools; nil disables it.
+var webCache atomic.Pointer[httpCache]
+
+// SetHTTPCache stores the web tools' responses in dir, using at most
+// maxBytes of disk; an empty dir or maxBytes <= 0 turns the cache off.
+// Responses cached by an earlier run are kept.
+func SetHTTPCache(dir string, maxBytes int64) error {
+	if dir == "" || maxBytes <= 0 {
+		webCache.Store(nil)
+		return nil
+	}
+	if c := webCache.Load(); c != nil && c.dir == dir {
+		c.mu.Lock()
+		c.maxBytes = maxBytes
+		c.evict()
+		c.mu.Unlock()
+		return nil
+	}
+	c, err := openHTTPCache(dir, maxBytes)
+	if err != nil {
+		return err
+	}
+	webCache.Store(c)
+	return nil
+}
+
+// newWebClient returns the client of the tools that read web pages and
+// feeds: SSRF-safe, polite (see SetPolitenessPolicy) and cached.
+func newWebClient(timeout time.Duration) *http.Client {
+	c := NewSafeClient(timeout)
+	c.Transport = cacheTransport{politeTransport{c.Transport}}
+	return c
+}
+
+// newCachedClient returns an SSRF-safe client whose responses are cached,
+// for APIs with pacing of their own.
+func newCachedClient(timeout time.Duration) *http.Client {
+	c := NewSafeClient(timeout)
+	c.Transport = cacheTransport{c.Transport}
+	return c
+}
+
+// httpCache is a disk cache of GET responses. It reuses a response while
+// its Cache-Control or Expires says it is fresh, then revalidates it with
+// its ETag or Last-Modified, so an unchanged page costs a 304. The files
+// least recently used are removed when it grows past maxBytes.
+type httpCache struct {
+	dir string
+
+	mu       sync.Mutex
+	maxBytes int64
+	total    int64
+	files    map[string]*cacheFile // by key
+}
+
+type cacheFile struct {
+	size     int64
+	lastUsed time.Time
+}
+
+// cachedResponse is a stored response.
+type cachedResponse struct {
+	URL    string
+	Header http.Header
+	Body   []byte
+	Stored time.Time
+}
+
+func openHTTPCache(dir string, maxBytes int64) (*httpCache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create HTTP cache directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTTP cache directory: %w", err)
+	}
+	c := &httpCache{dir: dir, maxBytes: maxBytes, files: make(map[string]*cacheFile)}
+	for _, e := range entries {
+		key, ok := strings.CutSuffix(e.Name(), cacheFileExt)
+		if !ok || e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		c.files[key] = &cacheFile{size: info.Size(), lastUsed: info.ModTime()}
+		c.total += info.Size()
+	}
+	c.evict()
+	return c, nil
+}
+
+func cacheKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *httpCache) path(key string) string {
+	return filepath.Join(c.dir, key+cacheFileExt)
+}
+
+// get returns the stored response for key, or nil.
+func (c *httpCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	f, ok := c.files[key]
+	if ok {
+		f.lastUsed = time.Now()
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		c.remove(key)
+		return nil
+	}
+	var r cachedResponse
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&r); err != nil {
+		slog.Warn("Discarding unreadable HTTP cache entry", "key", key, "error", err)
+		c.remove(key)
+		return nil
+	}
+	// Keep the least-recently-used order across restarts.
+	now := time.Now()
+	_ = os.Chtimes(c.path(key), now, now)
+	return &r
+}
+
+// put stores r under key, evicting old files to stay within maxBytes.
+func (c *httpCache) put(key string, r *cachedResponse) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(r); err != nil {
+		slog.Warn("Failed to encode HTTP cache entry", "url", r.URL, "error", err)
+		return
+	}
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		slog.Warn("Failed to write HTTP cache entry", "url", r.URL, "error", err)
+		return
+	}
+	_, err = tmp.Write(buf.Bytes())
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		slog.Warn("Failed to write HTTP cache entry", "url", r.URL, "error", err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.files[key]; ok {
+		c.total -= old.size
+	}
+	c.files[key] = &cacheFile{size: int64(buf.Len()), lastUsed: time.Now()}
+	c.total += int64(buf.Len())
+	c.evict()
+}
+
+func (c *httpCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f, ok := c.files[key]; ok {
+		c.total -= f.size
+		delete(c.files, key)
+	}
+	_ = os.Remove(c.path(key))
+}
+
+// evict removes the least recently used files until the cache fits in
+// maxBytes. c.mu must be held.
+func (c *httpCache) evict() {
+	for c.total > c.maxBytes && len(c.files) > 0 {
+		var oldest string
+		for k, f := range c.files {
+			if oldest == "" || f.lastUsed.Before(c.files[oldest].lastUsed) {
+				oldest = k
+			}
+		}
+		c.total -= c.files[oldest].size
+		delete(c.files, oldest)
+		_ = os.Remove(c.path(oldest))
+	}
+}
+
+// cacheTransport answers GET requests from webCache when it can, and
+// stores the responses that may be reused.
+type cacheTransport struct{ next http.RoundTripper }
+
+func (t cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c := webCache.Load()
+	// Requests that carry their own validators (feeds track theirs) or
+	// credentials are passed through.
+	if c == nil || req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" ||
+		req.Header.Get("If-Modified-Since") != "" || req.Header.Get("Authorization") != "" {
+		return t.next.RoundTrip(req)
+	}
+	key := cacheKey(req.URL.String())
+	stored := c.get(key)
+	now := time.Now()
+	if stored != nil && stored.fresh(now) {
+		return stored.response(req), nil
+	}
+
+	out := req
+	if stored != nil {
+		out = req.Clone(req.Context())
+		if etag := stored.Header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if lm := stored.Header.Get("Last-Modified"); lm != "" {
+			out.Header.Set("If-Modified-Since", lm)
+		}
+	}
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		for _, h := range []string{"Cache-Control", "Expires", "Date", "ETag", "Last-Modified"} {
+			if v := resp.Header.Get(h); v != "" {
+				stored.Header.Set(h, v)
+			}
+		}
+		stored.Stored = now
+		c.put(key, stored)
+		return stored.response(req), nil
+	}
+	if !storable(resp) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCacheEntryBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCacheEntryBytes {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	c.put(key, &cachedResponse{URL: req.URL.String(), Header: resp.Header.Clone(), Body: body, Stored: now})
+	return resp, nil
+}
+
+// storable reports whether resp may be cached: a 200 that the server
+// doesn't forbid storing, that varies only by encoding, and that is either
+// fresh for a while or can be revalidated.
+func storable(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	cc := parseCacheControl(resp.Header.Get("Cache-Control"))
+	if _, ok := cc["no-store"]; ok {
+		return false
+	}
+	if v := resp.Header.Get("Vary"); v != "" && !strings.EqualFold(strings.TrimSpace(v), "Accept-Encoding") {
+		return false
+	}
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "" || freshFor(resp.Header) > 0
+}
+
+// fresh reports whether r may be used without asking the server.
+func (r *cachedResponse) fresh(now time.Time) bool {
+	return now.Before(r.Stored.Add(freshFor(r.Header)))
+}
+
+// freshFor is how long a response with header h may be reused, from its
+// Cache-Control max-age or its Expires, at most a day.
+func freshFor(h http.Header) time.Duration {
+	cc := parseCacheControl(h.Get("Cache-Control"))
+	if _, ok := cc["no-cache"]; ok {
+		return 0
+	}
+	if v, ok := cc["max-age"]; ok {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs <= 0 {
+			return 0
+		}
+		return min(time.Duration(secs)*time.Second, maxCacheFreshness)
+	}
+	if v := h.Get("Expires"); v != "" {
+		expires, err := http.ParseTime(v)
+		if err != nil {
+			return 0
+		}
+		date, err := http.ParseTime(h.Get("Date"))
+		if err != nil {
+			date = time.Now()
+		}
+		return min(max(expires.Sub(date), 0), maxCacheFreshness)
+	}
+	return 0
+}
+
+func parseCacheControl(v string) map[string]string {
+	cc := make(map[string]string)
+	for _, part := range strings.Split(v, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name != "" {
+			cc[strings.ToLower(name)] = strings.Trim(value, `"`)
+		}
+	}
+	return cc
+}
+
+// response returns r as the response to req.
+func (r *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
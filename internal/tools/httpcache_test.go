@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHTTPCache(t *testing.T) {
+	var requests, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "public, max-age=600")
+		case "/etag":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/nostore":
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("ETag", `"v1"`)
+		}
+		_, _ = w.Write([]byte(`<html><head><title>` + r.URL.Path + `</title></head><body><p>Cached text.</p></body></html>`))
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	if err := SetHTTPCache(dir, 1<<20); err != nil {
+		t.Fatalf("SetHTTPCache failed: %v", err)
+	}
+	defer func() { _ = SetHTTPCache("", 0) }()
+	ctx := context.Background()
+
+	fetch := func(path string) {
+		t.Helper()
+		a, err := ScrapePage(ctx, srv.URL+path)
+		if err != nil || a.Text != "Cached text." || a.Title != path {
+			t.Fatalf("ScrapePage(%s) = %+v, %v", path, a, err)
+		}
+	}
+	count := func(path string, want int32) {
+		t.Helper()
+		requests.Store(0)
+		fetch(path)
+		fetch(path)
+		if got := requests.Load(); got != want {
+			t.Errorf("%s: %d requests, want %d", path, got, want)
+		}
+	}
+	count("/fresh", 1)
+	count("/fresh", 0)
+	count("/etag", 2)
+	if notModified.Load() != 1 {
+		t.Errorf("expected the ETag to be revalidated, got %d 304s", notModified.Load())
+	}
+	count("/nostore", 2)
+	count("/plain", 2)
+
+	// The cache survives a restart.
+	_ = SetHTTPCache("", 0)
+	if err := SetHTTPCache(dir, 1<<20); err != nil {
+		t.Fatalf("SetHTTPCache failed: %v", err)
+	}
+	count("/fresh", 0)
+
+	// Requests with their own validators pass through.
+	requests.Store(0)
+	if _, err := FetchFeed(ctx, srv.URL+"/etag", `"v1"`, ""); err != nil {
+		t.Fatalf("FetchFeed failed: %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected a conditional feed request to reach the server")
+	}
+
+	// Shrinking the cache evicts the least recently used entries.
+	if err := SetHTTPCache(dir, 1); err != nil {
+		t.Fatalf("SetHTTPCache failed: %v", err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected an empty cache, found %d files", len(files))
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 )
@@ -30,23 +32,76 @@ type JulesSessionRequest struct {
 	AutomationMode      string        `json:"automationMode,omitempty"`
 }
 
+// julesAPIURL is the Jules API root; tests point it at a local server.
+var julesAPIURL = "https://jules.googleapis.com/v1alpha/"
+
+// JulesSession is a Jules session as the API reports it.
+type JulesSession struct {
+	Name         string // "sessions/{id}"
+	Title        string
+	State        string // e.g. "IN_PROGRESS", "AWAITING_USER_FEEDBACK", "COMPLETED"
+	URL          string // the session's page on jules.google
+	PullRequests []JulesPullRequest
+}
+
+// JulesPullRequest is a pull request a session opened.
+type JulesPullRequest struct {
+	URL   string
+	Title string
+}
+
+// JulesActivity is one step of a session: a plan, a progress update, a
+// message from Jules, or its completion or failure.
+type JulesActivity struct {
+	Kind       string // "progress", "message", "plan", "completed", "failed" or ""
+	Text       string
+	CreateTime time.Time
+}
+
+// Done reports whether the session has finished, so there is nothing left
+// to watch.
+func (s JulesSession) Done() bool {
+	return s.State == "COMPLETED" || s.State == "FAILED"
+}
+
+type julesSessionJSON struct {
+	Name    string `json:"name"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	URL     string `json:"url"`
+	Outputs []struct {
+		PullRequest *struct {
+			URL   string `json:"url"`
+			Title string `json:"title"`
+		} `json:"pullRequest"`
+	} `json:"outputs"`
+}
+
+func (j julesSessionJSON) session() JulesSession {
+	s := JulesSession{Name: j.Name, Title: j.Title, State: j.State, URL: j.URL}
+	for _, o := range j.Outputs {
+		if o.PullRequest != nil && o.PullRequest.URL != "" {
+			s.PullRequests = append(s.PullRequests, JulesPullRequest{URL: o.PullRequest.URL, Title: o.PullRequest.Title})
+		}
+	}
+	return s
+}
+
 // DelegateToJules calls the alpha Jules Agent API to perform a repository task.
 // The repo should be in the format "owner/repo" (e.g., "raythurman2386/ravenbot").
 // Note: The repository must be connected to Jules via https://jules.google first.
-func DelegateToJules(ctx context.Context, apiKey, repo, task string) (string, error) {
+func DelegateToJules(ctx context.Context, apiKey, repo, task string) (JulesSession, error) {
 	if apiKey == "" {
-		return "", fmt.Errorf("JULES_API_KEY is not set")
+		return JulesSession{}, fmt.Errorf("JULES_API_KEY is not set")
 	}
 
 	// Parse owner/repo format
 	parts := strings.Split(repo, "/")
 	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid repo format, expected 'owner/repo', got: %s", repo)
+		return JulesSession{}, fmt.Errorf("invalid repo format, expected 'owner/repo', got: %s", repo)
 	}
 	owner, repoName := parts[0], parts[1]
 
-	url := "https://jules.googleapis.com/v1alpha/sessions"
-
 	// Build the proper request payload
 	// Source format: sources/github/{owner}/{repo}
 	payload := JulesSessionRequest{
@@ -62,12 +117,96 @@ func DelegateToJules(ctx context.Context, apiKey, repo, task string) (string, er
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal jules payload: %w", err)
+		return JulesSession{}, fmt.Errorf("failed to marshal jules payload: %w", err)
+	}
+
+	var result julesSessionJSON
+	if err := julesCall(ctx, apiKey, http.MethodPost, "sessions", bytes.NewBuffer(jsonData), &result); err != nil {
+		return JulesSession{}, err
 	}
+	if result.Name == "" {
+		return JulesSession{}, fmt.Errorf("jules response missing session name")
+	}
+	return result.session(), nil
+}
+
+// GetJulesSession returns the current state of a session by its name
+// ("sessions/{id}").
+func GetJulesSession(ctx context.Context, apiKey, name string) (JulesSession, error) {
+	var result julesSessionJSON
+	if err := julesCall(ctx, apiKey, http.MethodGet, name, nil, &result); err != nil {
+		return JulesSession{}, err
+	}
+	return result.session(), nil
+}
+
+// ListJulesActivities returns a session's most recent activities, oldest
+// first.
+func ListJulesActivities(ctx context.Context, apiKey, name string) ([]JulesActivity, error) {
+	var result struct {
+		Activities []struct {
+			Description   string    `json:"description"`
+			CreateTime    time.Time `json:"createTime"`
+			AgentMessaged *struct {
+				AgentMessage string `json:"agentMessage"`
+			} `json:"agentMessaged"`
+			PlanGenerated *struct {
+				Plan struct {
+					Steps []struct {
+						Title string `json:"title"`
+					} `json:"steps"`
+				} `json:"plan"`
+			} `json:"planGenerated"`
+			ProgressUpdated *struct {
+				Title       string `json:"title"`
+				Description string `json:"description"`
+			} `json:"progressUpdated"`
+			SessionCompleted *struct{} `json:"sessionCompleted"`
+			SessionFailed    *struct {
+				Reason string `json:"reason"`
+			} `json:"sessionFailed"`
+		} `json:"activities"`
+	}
+	if err := julesCall(ctx, apiKey, http.MethodGet, name+"/activities?pageSize=50", nil, &result); err != nil {
+		return nil, err
+	}
+
+	activities := make([]JulesActivity, 0, len(result.Activities))
+	for _, a := range result.Activities {
+		act := JulesActivity{Text: a.Description, CreateTime: a.CreateTime}
+		switch {
+		case a.AgentMessaged != nil:
+			act.Kind, act.Text = "message", a.AgentMessaged.AgentMessage
+		case a.PlanGenerated != nil:
+			var steps []string
+			for i, st := range a.PlanGenerated.Plan.Steps {
+				steps = append(steps, fmt.Sprintf("%d. %s", i+1, st.Title))
+			}
+			act.Kind, act.Text = "plan", strings.Join(steps, "\n")
+		case a.ProgressUpdated != nil:
+			act.Kind, act.Text = "progress", strings.TrimSpace(a.ProgressUpdated.Title+"\n"+a.ProgressUpdated.Description)
+		case a.SessionCompleted != nil:
+			act.Kind = "completed"
+		case a.SessionFailed != nil:
+			act.Kind, act.Text = "failed", a.SessionFailed.Reason
+		}
+		activities = append(activities, act)
+	}
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].CreateTime.Before(activities[j].CreateTime)
+	})
+	return activities, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+// julesCall sends a request to the Jules API and decodes its JSON answer
+// into out.
+func julesCall(ctx context.Context, apiKey, method, path string, body io.Reader, out any) error {
+	if apiKey == "" {
+		return fmt.Errorf("JULES_API_KEY is not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, julesAPIURL+path, body)
 	if err != nil {
-		return "", fmt.Errorf("failed to create jules request: %w", err)
+		return fmt.Errorf("failed to create jules request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -76,36 +215,38 @@ func DelegateToJules(ctx context.Context, apiKey, repo, task string) (string, er
 	client := NewSafeClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("jules api call failed: %w", err)
+		return fmt.Errorf("jules api call failed: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	// Read response body for error details
-	var result map[string]any
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode jules response: %w", err)
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read jules response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
 		// Try to extract error message from response
-		if errMsg, ok := result["error"].(map[string]any); ok {
-			if msg, ok := errMsg["message"].(string); ok {
-				// Provide helpful context for common errors
-				if strings.Contains(msg, "not found") {
-					return "", fmt.Errorf("jules api error: %s (Hint: Make sure the repo is connected at https://jules.google)", msg)
-				}
-				return "", fmt.Errorf("jules api error: %s", msg)
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			msg := apiErr.Error.Message
+			// Provide helpful context for common errors
+			if method == http.MethodPost && strings.Contains(msg, "not found") {
+				return fmt.Errorf("jules api error: %s (Hint: Make sure the repo is connected at https://jules.google)", msg)
 			}
+			return fmt.Errorf("jules api error: %s", msg)
 		}
-		return "", fmt.Errorf("jules api returned status: %s", resp.Status)
+		return fmt.Errorf("jules api returned status: %s", resp.Status)
 	}
 
-	sessionName, ok := result["name"].(string)
-	if !ok {
-		return "", fmt.Errorf("jules response missing session name")
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode jules response: %w", err)
 	}
-
-	return fmt.Sprintf("Jules task initiated successfully. Session: %s", sessionName), nil
+	return nil
 }
 
 // truncateString truncates a string to the specified rune length,
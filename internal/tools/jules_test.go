@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJulesAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Goog-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"API key not valid"}}`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/sessions":
+			var req JulesSessionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("bad session request: %v", err)
+			}
+			if req.SourceContext.Source == "sources/github/owner/missing" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"message":"source not found"}}`))
+				return
+			}
+			if req.SourceContext.Source != "sources/github/owner/repo" || req.Prompt != "fix it" {
+				t.Errorf("unexpected session request: %+v", req)
+			}
+			_, _ = w.Write([]byte(`{"name":"sessions/123","state":"QUEUED","url":"https://jules.google/session/123"}`))
+		case r.URL.Path == "/sessions/123":
+			_, _ = w.Write([]byte(`{"name":"sessions/123","title":"Fix it","state":"COMPLETED","url":"https://jules.google/session/123",
+				"outputs":[{"changeSet":{}},{"pullRequest":{"url":"https://github.com/owner/repo/pull/42","title":"Fix it"}}]}`))
+		case r.URL.Path == "/sessions/123/activities":
+			_, _ = w.Write([]byte(`{"activities":[
+				{"createTime":"2026-01-02T10:05:00Z","sessionCompleted":{}},
+				{"createTime":"2026-01-02T10:00:00Z","planGenerated":{"plan":{"steps":[{"title":"Read the code"},{"title":"Fix the bug"}]}}},
+				{"createTime":"2026-01-02T10:02:00Z","progressUpdated":{"title":"Ran the tests","description":"All green"}},
+				{"createTime":"2026-01-02T10:01:00Z","agentMessaged":{"agentMessage":"Which branch?"}},
+				{"createTime":"2026-01-02T10:03:00Z","sessionFailed":{"reason":"boom"}},
+				{"createTime":"2026-01-02T10:04:00Z","description":"Something else"}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	old := julesAPIURL
+	julesAPIURL = srv.URL + "/"
+	defer func() { julesAPIURL = old }()
+	ctx := context.Background()
+
+	s, err := DelegateToJules(ctx, "key", "owner/repo", "fix it")
+	if err != nil {
+		t.Fatalf("DelegateToJules failed: %v", err)
+	}
+	if s.Name != "sessions/123" || s.State != "QUEUED" || s.URL != "https://jules.google/session/123" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+	if _, err := DelegateToJules(ctx, "key", "owner/missing", "fix it"); err == nil || !strings.Contains(err.Error(), "jules.google") {
+		t.Errorf("expected a hint to connect the repo, got %v", err)
+	}
+	if _, err := DelegateToJules(ctx, "key", "not-a-repo", "fix it"); err == nil {
+		t.Error("expected an invalid repo to be rejected")
+	}
+	if _, err := DelegateToJules(ctx, "", "owner/repo", "fix it"); err == nil {
+		t.Error("expected a missing API key to be rejected")
+	}
+	if _, err := GetJulesSession(ctx, "wrong", "sessions/123"); err == nil || !strings.Contains(err.Error(), "API key not valid") {
+		t.Errorf("expected the API's error message, got %v", err)
+	}
+
+	s, err = GetJulesSession(ctx, "key", "sessions/123")
+	if err != nil {
+		t.Fatalf("GetJulesSession failed: %v", err)
+	}
+	if !s.Done() || len(s.PullRequests) != 1 || s.PullRequests[0].URL != "https://github.com/owner/repo/pull/42" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+
+	activities, err := ListJulesActivities(ctx, "key", "sessions/123")
+	if err != nil {
+		t.Fatalf("ListJulesActivities failed: %v", err)
+	}
+	want := []JulesActivity{
+		{Kind: "plan", Text: "1. Read the code\n2. Fix the bug"},
+		{Kind: "message", Text: "Which branch?"},
+		{Kind: "progress", Text: "Ran the tests\nAll green"},
+		{Kind: "failed", Text: "boom"},
+		{Kind: "", Text: "Something else"},
+		{Kind: "completed"},
+	}
+	if len(activities) != len(want) {
+		t.Fatalf("expected %d activities, got %+v", len(want), activities)
+	}
+	for i, w := range want {
+		if activities[i].Kind != w.Kind || activities[i].Text != w.Text {
+			t.Errorf("activity %d = %+v, want %+v", i, activities[i], w)
+		}
+	}
+}
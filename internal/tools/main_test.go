@@ -8,6 +8,8 @@ import (
 func TestMain(m *testing.M) {
 	// Set ALLOW_LOCAL_URLS=true to allow httptest servers during tests
 	_ = os.Setenv("ALLOW_LOCAL_URLS", "true")
+	// Don't pace requests to the test servers
+	SetPolitenessPolicy(PolitenessPolicy{Delay: -1})
 	code := m.Run()
 	os.Exit(code)
 }
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OPMLFeed is a feed listed in an OPML subscription list.
+type OPMLFeed struct {
+	Title   string
+	URL     string // the feed itself (xmlUrl)
+	HTMLURL string // the site it belongs to, if given
+}
+
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    struct {
+		Title       string `xml:"title,omitempty"`
+		DateCreated string `xml:"dateCreated,omitempty"`
+	} `xml:"head"`
+	Body struct {
+		Outlines []opmlOutline `xml:"outline"`
+	} `xml:"body"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ParseOPML returns the feeds of an OPML subscription list, including those
+// nested in folders, once each in document order.
+func ParseOPML(data []byte) ([]OPMLFeed, error) {
+	var doc opmlDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OPML: %w", err)
+	}
+
+	var feeds []OPMLFeed
+	seen := make(map[string]bool)
+	var walk func([]opmlOutline)
+	walk = func(outlines []opmlOutline) {
+		for _, o := range outlines {
+			if u := strings.TrimSpace(o.XMLURL); u != "" && !seen[u] {
+				seen[u] = true
+				title := strings.TrimSpace(o.Title)
+				if title == "" {
+					title = strings.TrimSpace(o.Text)
+				}
+				feeds = append(feeds, OPMLFeed{Title: title, URL: u, HTMLURL: strings.TrimSpace(o.HTMLURL)})
+			}
+			walk(o.Outlines)
+		}
+	}
+	walk(doc.Body.Outlines)
+	return feeds, nil
+}
+
+// WriteOPML renders feeds as an OPML 2.0 subscription list.
+func WriteOPML(title string, feeds []OPMLFeed, now time.Time) ([]byte, error) {
+	doc := opmlDocument{Version: "2.0"}
+	doc.Head.Title = title
+	doc.Head.DateCreated = now.Format(time.RFC1123Z)
+	for _, f := range feeds {
+		text := f.Title
+		if text == "" {
+			text = f.URL
+		}
+		doc.Body.Outlines = append(doc.Body.Outlines, opmlOutline{
+			Text: text, Title: f.Title, Type: "rss", XMLURL: f.URL, HTMLURL: f.HTMLURL,
+		})
+	}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write OPML: %w", err)
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
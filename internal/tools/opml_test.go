@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"testing"
+	"time"
+)
+
+const testOPML = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0"><head><title>My feeds</title></head><body>
+<outline text="Go Blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog"/>
+<outline text="Tech" title="Tech">
+  <outline text="LWN" title="LWN.net" type="rss" xmlUrl=" https://lwn.net/headlines/rss "/>
+  <outline text="Go Blog again" xmlUrl="https://go.dev/blog/feed.atom"/>
+  <outline text="Just a link" htmlUrl="https://example.com"/>
+</outline>
+</body></opml>`
+
+func TestParseOPML(t *testing.T) {
+	feeds, err := ParseOPML([]byte(testOPML))
+	if err != nil {
+		t.Fatalf("ParseOPML failed: %v", err)
+	}
+	want := []OPMLFeed{
+		{Title: "Go Blog", URL: "https://go.dev/blog/feed.atom", HTMLURL: "https://go.dev/blog"},
+		{Title: "LWN.net", URL: "https://lwn.net/headlines/rss"},
+	}
+	if len(feeds) != len(want) {
+		t.Fatalf("expected %d feeds, got %+v", len(want), feeds)
+	}
+	for i := range want {
+		if feeds[i] != want[i] {
+			t.Errorf("feed %d: expected %+v, got %+v", i, want[i], feeds[i])
+		}
+	}
+
+	if _, err := ParseOPML([]byte("not xml")); err == nil {
+		t.Error("expected an error for invalid OPML")
+	}
+}
+
+func TestWriteOPML(t *testing.T) {
+	feeds := []OPMLFeed{
+		{Title: "Go Blog & friends", URL: "https://go.dev/blog/feed.atom"},
+		{URL: "https://lwn.net/headlines/rss"},
+	}
+	data, err := WriteOPML("ravenbot feeds", feeds, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("WriteOPML failed: %v", err)
+	}
+	got, err := ParseOPML(data)
+	if err != nil {
+		t.Fatalf("ParseOPML of written OPML failed: %v\n%s", err, data)
+	}
+	if len(got) != 2 || got[0] != feeds[0] || got[1].URL != feeds[1].URL || got[1].Title != feeds[1].URL {
+		t.Errorf("round trip mismatch: %+v\n%s", got, data)
+	}
+}
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	pdfTimeout = 60 * time.Second
+	// maxPDFBytes caps the PDFs downloaded.
+	maxPDFBytes = 25 << 20
+	// DefaultChunkChars is the size of the chunks FetchPDF text is read in.
+	DefaultChunkChars = 12000
+)
+
+// FetchPDF downloads the PDF at rawURL and returns its text, laid out in
+// lines and paragraphs as PDFText does.
+func FetchPDF(ctx context.Context, rawURL string) (string, error) {
+	if err := ValidateURL(ctx, rawURL); err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/pdf")
+	resp, err := newCachedClient(pdfTimeout).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch PDF: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("PDF returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPDFBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read PDF: %w", err)
+	}
+	if len(data) > maxPDFBytes {
+		return "", fmt.Errorf("PDF is larger than %d MB", maxPDFBytes>>20)
+	}
+	text, err := PDFText(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract the text of %s: %w", rawURL, err)
+	}
+	return text, nil
+}
+
+// ChunkText splits text into chunks of at most size bytes, breaking between
+// paragraphs where it can, else between lines or words.
+func ChunkText(text string, size int) []string {
+	if size <= 0 {
+		size = DefaultChunkChars
+	}
+	var chunks []string
+	for len(text) > size {
+		cut := -1
+		for _, sep := range []string{"\n\n", "\n", " "} {
+			// A break in the first half would leave the chunk too short.
+			if i := strings.LastIndex(text[:size], sep); i > size/2 {
+				cut = i
+				break
+			}
+		}
+		if cut < 0 {
+			cut = size
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		chunks = append(chunks, strings.TrimSpace(text[:cut]))
+		text = strings.TrimSpace(text[cut:])
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
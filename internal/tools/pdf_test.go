@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPDFTextLayout(t *testing.T) {
+	// Words placed by Tm join as a line where they sit together, break
+	// where the text moves down, and a wider gap starts a paragraph.
+	content := "BT /F1 10 Tf 1 0 0 1 72 700 Tm (Raven)Tj 1 0 0 1 100 700 Tm (bot)Tj 1 0 0 1 130 700 Tm (reads)Tj " +
+		"1 0 0 1 72 688 Tm (PDFs.)Tj 1 0 0 1 72 660 Tm (New)Tj 14 TL T* (para-)Tj (graph.)' ET"
+	text, err := PDFText(testPDF(content))
+	if err != nil {
+		t.Fatalf("PDFText failed: %v", err)
+	}
+	if want := "Ravenbot reads\nPDFs.\n\nNew\nparagraph."; text != want {
+		t.Errorf("PDFText = %q, want %q", text, want)
+	}
+}
+
+func TestFetchPDF(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/report.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write(testPDF("BT /F1 10 Tf 72 700 Td (Quarterly report)Tj ET"))
+		case "/page.html":
+			_, _ = w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if text, err := FetchPDF(ctx, srv.URL+"/report.pdf"); err != nil || text != "Quarterly report" {
+		t.Errorf("FetchPDF = %q, %v", text, err)
+	}
+	if _, err := FetchPDF(ctx, srv.URL+"/page.html"); err == nil || !strings.Contains(err.Error(), "not a PDF") {
+		t.Errorf("expected not a PDF error, got %v", err)
+	}
+	if _, err := FetchPDF(ctx, srv.URL+"/missing.pdf"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestChunkText(t *testing.T) {
+	text := strings.Repeat("a", 30) + "\n\n" + strings.Repeat("b", 20) + "\n" + strings.Repeat("c", 20)
+	chunks := ChunkText(text, 60)
+	if len(chunks) != 2 || chunks[0] != strings.Repeat("a", 30)+"\n\n"+strings.Repeat("b", 20) || chunks[1] != strings.Repeat("c", 20) {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+	// Without breaks, chunks are cut between runes.
+	chunks = ChunkText(strings.Repeat("é", 10), 5)
+	if len(chunks) != 5 || chunks[0] != "éé" {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+	if chunks := ChunkText("short", 0); len(chunks) != 1 || chunks[0] != "short" {
+		t.Errorf("unexpected chunks %q", chunks)
+	}
+}
@@ -0,0 +1,444 @@
+package tools
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PDFText extracts the text of a PDF, best effort: it reads the text
+// operators of the page content streams that are uncompressed or
+// Flate-compressed, in file order, and lays the text out in lines and
+// paragraphs by its position. That suits papers and reports; text drawn
+// with CID fonts or as images is lost.
+func PDFText(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return "", fmt.Errorf("not a PDF")
+	}
+	var sb strings.Builder
+	for pos := 0; ; {
+		dict, body, next, ok := nextPDFStream(data, pos)
+		if !ok {
+			break
+		}
+		pos = next
+		if !isContentStream(dict) {
+			continue
+		}
+		if bytes.Contains(dict, []byte("/FlateDecode")) {
+			// A truncated stream still yields what was decompressed.
+			r, err := zlib.NewReader(bytes.NewReader(body))
+			if err != nil {
+				continue
+			}
+			body, _ = io.ReadAll(r)
+		}
+		if !bytes.Contains(body, []byte("BT")) {
+			continue
+		}
+		contentText(body, &sb)
+		sb.WriteString("\n\n")
+	}
+	text := cleanPDFText(sb.String())
+	if text == "" {
+		return "", fmt.Errorf("no text found")
+	}
+	return text, nil
+}
+
+// nextPDFStream finds the first stream at or after pos, returning its
+// dictionary and raw data and where to continue.
+func nextPDFStream(data []byte, pos int) (dict, body []byte, next int, ok bool) {
+	for {
+		i := bytes.Index(data[pos:], []byte("stream"))
+		if i < 0 {
+			return nil, nil, 0, false
+		}
+		i += pos
+		pos = i + len("stream")
+		if i >= 3 && string(data[i-3:i]) == "end" {
+			continue
+		}
+		start := pos
+		if start < len(data) && data[start] == '\r' {
+			start++
+		}
+		if start < len(data) && data[start] == '\n' {
+			start++
+		}
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			return nil, nil, 0, false
+		}
+		end += start
+		dictStart := bytes.LastIndex(data[:i], []byte("obj"))
+		if dictStart < 0 {
+			dictStart = 0
+		}
+		return data[dictStart:i], bytes.TrimRight(data[start:end], "\r\n"), end + len("endstream"), true
+	}
+}
+
+// isContentStream reports whether a stream may hold page content: not an
+// image, font program, object or cross-reference stream, and compressed
+// with Flate if at all.
+func isContentStream(dict []byte) bool {
+	for _, key := range []string{"/Subtype", "/Length1", "/Length2", "/Length3", "/ObjStm", "/XRef", "/Metadata", "/DCTDecode", "/LZWDecode", "/ASCII85Decode", "/ASCIIHexDecode", "/CCITTFaxDecode", "/JBIG2Decode", "/JPXDecode", "/RunLengthDecode"} {
+		if bytes.Contains(dict, []byte(key)) {
+			return false
+		}
+	}
+	return true
+}
+
+// contentText writes the text shown by a content stream's operators.
+func contentText(b []byte, sb *strings.Builder) {
+	var operands []any
+	var array []any
+	inArray := false
+	push := func(v any) {
+		if inArray {
+			array = append(array, v)
+		} else {
+			operands = append(operands, v)
+		}
+	}
+	lastString := func() (string, bool) {
+		if len(operands) == 0 {
+			return "", false
+		}
+		s, ok := operands[len(operands)-1].(string)
+		return s, ok
+	}
+	// num returns the operand n places before the operator, or 0.
+	num := func(n int) float64 {
+		if len(operands) < n {
+			return 0
+		}
+		f, _ := operands[len(operands)-n].(float64)
+		return f
+	}
+	l := newTextLayout(sb)
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case isPDFSpace(c):
+			i++
+		case c == '%':
+			for i < len(b) && b[i] != '\n' && b[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			s, n := pdfLiteral(b[i:])
+			push(s)
+			i += n
+		case c == '<' && i+1 < len(b) && b[i+1] == '<', c == '>' && i+1 < len(b) && b[i+1] == '>':
+			i += 2
+		case c == '<':
+			end := bytes.IndexByte(b[i:], '>')
+			if end < 0 {
+				return
+			}
+			push(pdfHex(b[i+1 : i+end]))
+			i += end + 1
+		case c == '[':
+			inArray, array = true, nil
+			i++
+		case c == ']':
+			inArray = false
+			operands = append(operands, array)
+			i++
+		case c == '/':
+			j := i + 1
+			for j < len(b) && !isPDFSpace(b[j]) && !isPDFDelimiter(b[j]) {
+				j++
+			}
+			push(string(b[i:j]))
+			i = j
+		case isPDFDelimiter(c):
+			i++
+		default:
+			j := i
+			for j < len(b) && !isPDFSpace(b[j]) && !isPDFDelimiter(b[j]) {
+				j++
+			}
+			tok := string(b[i:j])
+			i = j
+			if f, err := strconv.ParseFloat(tok, 64); err == nil {
+				push(f)
+				continue
+			}
+			switch tok {
+			case "BT":
+				l.beginText()
+			case "Tf":
+				l.size = math.Abs(num(1))
+			case "TL":
+				l.leading = num(1)
+			case "Tm":
+				l.setMatrix(num(6), num(3), num(2), num(1))
+			case "Td":
+				l.move(num(2), num(1))
+			case "TD":
+				l.leading = -num(1)
+				l.move(num(2), num(1))
+			case "T*":
+				l.nextLine()
+			case "Tj":
+				if s, ok := lastString(); ok {
+					l.show(s)
+				}
+			case "'", `"`:
+				l.nextLine()
+				if s, ok := lastString(); ok {
+					l.show(s)
+				}
+			case "TJ":
+				if len(operands) > 0 {
+					parts, _ := operands[len(operands)-1].([]any)
+					for _, p := range parts {
+						switch v := p.(type) {
+						case string:
+							l.show(v)
+						case float64:
+							// A wide negative adjustment is a word space.
+							if v < -200 {
+								l.space()
+							}
+						}
+					}
+				}
+			case "BI":
+				// Skip the inline image data up to EI.
+				if end := bytes.Index(b[i:], []byte("EI")); end >= 0 {
+					i += end + 2
+				} else {
+					return
+				}
+			}
+			operands = operands[:0]
+		}
+	}
+}
+
+// textLayout follows the text position to break lines where the text moves
+// down, paragraphs where it skips more than a line, and words where it
+// moves right. It ignores the graphics transformation, and estimates the
+// width of text from its length, so it suits ordinary running text.
+type textLayout struct {
+	sb      *strings.Builder
+	x, y    float64 // start of the current line, in text space
+	sx, sy  float64 // scale of the text matrix
+	leading float64
+	size    float64
+	moved   bool    // the position changed since text was last shown
+	shown   bool    // text was shown in this stream
+	lastX   float64 // where the last text was shown, and its estimated end
+	lastEnd float64
+	lastY   float64
+}
+
+func newTextLayout(sb *strings.Builder) *textLayout {
+	return &textLayout{sb: sb, sx: 1, sy: 1, size: 10}
+}
+
+func (l *textLayout) beginText() {
+	l.x, l.y, l.sx, l.sy = 0, 0, 1, 1
+	l.moved = true
+}
+
+func (l *textLayout) setMatrix(a, d, e, f float64) {
+	if a == 0 || d == 0 {
+		// Rotated text: keep the scale, and take its position as is.
+		a, d = l.sx, l.sy
+	}
+	l.sx, l.sy, l.x, l.y = a, d, e, f
+	l.moved = true
+}
+
+func (l *textLayout) move(tx, ty float64) {
+	l.x += tx * l.sx
+	l.y += ty * l.sy
+	l.moved = true
+}
+
+func (l *textLayout) nextLine() {
+	l.move(0, -l.leading)
+	if l.leading == 0 {
+		// Without a leading the move is still a new line.
+		l.lastY = l.y + l.height()
+	}
+}
+
+// height is the font size in text space.
+func (l *textLayout) height() float64 {
+	return math.Abs(l.size * l.sy)
+}
+
+// show writes s, after the break or space the move to it implies.
+func (l *textLayout) show(s string) {
+	if s == "" {
+		return
+	}
+	h := l.height()
+	width := float64(utf8.RuneCountInString(s)) * 0.5 * h
+	if !l.moved {
+		l.sb.WriteString(s)
+		l.lastEnd += width
+		return
+	}
+	if l.shown {
+		switch dy := math.Abs(l.lastY - l.y); {
+		case dy > 1.6*h:
+			l.sb.WriteString("\n\n")
+		case dy > 0.5*h:
+			l.sb.WriteString("\n")
+		case l.x > l.lastEnd+0.15*h+0.1*(l.lastEnd-l.lastX) || l.x < l.lastX:
+			// The estimated width errs by a share of the text's length.
+			l.space()
+		}
+	}
+	l.sb.WriteString(s)
+	l.lastX, l.lastEnd, l.lastY = l.x, l.x+width, l.y
+	l.moved, l.shown = false, true
+}
+
+func (l *textLayout) space() {
+	l.sb.WriteString(" ")
+}
+
+// pdfLiteral decodes the literal string at the start of b, returning it
+// and its length in b.
+func pdfLiteral(b []byte) (string, int) {
+	var out []byte
+	depth := 0
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch c {
+		case '(':
+			if depth > 0 {
+				out = append(out, c)
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfChars(out), i + 1
+			}
+			out = append(out, c)
+		case '\\':
+			i++
+			if i >= len(b) {
+				break
+			}
+			switch e := b[i]; e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r', '\n':
+				// A line continuation.
+			default:
+				if e >= '0' && e <= '7' {
+					v, n := 0, 0
+					for n < 3 && i+n < len(b) && b[i+n] >= '0' && b[i+n] <= '7' {
+						v = v*8 + int(b[i+n]-'0')
+						n++
+					}
+					out = append(out, byte(v))
+					i += n - 1
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return pdfChars(out), len(b)
+}
+
+// pdfHex decodes a hex string.
+func pdfHex(b []byte) string {
+	var digits []byte
+	for _, c := range b {
+		if !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return ""
+		}
+		out = append(out, byte(v))
+	}
+	return pdfChars(out)
+}
+
+// texLigatures are the ligatures at codes 11 to 15 in TeX font encodings.
+var texLigatures = []string{"ff", "fi", "fl", "ffi", "ffl"}
+
+// pdfChars maps the bytes of a shown string to text, reading them as
+// Latin-1 with TeX's ligatures, and dropping other control codes.
+func pdfChars(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch {
+		case c >= 11 && c <= 15:
+			sb.WriteString(texLigatures[c-11])
+		case c == '\n' || c == '\t':
+			sb.WriteByte(' ')
+		case c < 32 || c == 127 || (c >= 128 && c < 160):
+		default:
+			sb.WriteRune(rune(c))
+		}
+	}
+	return sb.String()
+}
+
+// cleanPDFText collapses spacing and blank lines, keeping one between
+// paragraphs, and joins words hyphenated across lines.
+func cleanPDFText(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		n := len(lines)
+		if line == "" {
+			if n > 0 && lines[n-1] != "" {
+				lines = append(lines, "")
+			}
+			continue
+		}
+		if first, _ := utf8.DecodeRuneInString(line); n > 0 && strings.HasSuffix(lines[n-1], "-") && unicode.IsLower(first) {
+			lines[n-1] = strings.TrimSuffix(lines[n-1], "-") + line
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
@@ -0,0 +1,323 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHostDelay is the least time between two requests to one host.
+	DefaultHostDelay = time.Second
+	// maxCrawlDelay caps the Crawl-delay a robots.txt can ask for.
+	maxCrawlDelay = 30 * time.Second
+	// robotsTTL is how long a host's robots.txt is used before it is read
+	// again; robotsErrorTTL is how long a failure to read it is.
+	robotsTTL      = 24 * time.Hour
+	robotsErrorTTL = time.Hour
+	// maxRobotsBytes caps the robots.txt read; Google reads 500 KiB.
+	maxRobotsBytes = 512 << 10
+)
+
+// userAgent is the User-Agent the tools' HTTP requests send. robots.txt
+// rules are matched against its product token, robotsAgent, so the rules
+// obeyed are the ones written for the agent sites see.
+const (
+	robotsAgent = "ravenbot"
+	userAgent   = robotsAgent + "/1.0 (+https://github.com/raythurman2386/ravenbot)"
+)
+
+// HostPolicy overrides the politeness settings for one host.
+type HostPolicy struct {
+	// Delay is the least time between requests to the host: 0 uses the
+	// default, a negative delay none.
+	Delay        time.Duration
+	IgnoreRobots bool
+}
+
+// PolitenessPolicy sets how ScrapePage, CrawlSite and FetchFeed pace their
+// requests to each host and whether they obey its robots.txt.
+type PolitenessPolicy struct {
+	// Delay is the least time between requests to a host (DefaultHostDelay
+	// if 0, none if negative). A longer Crawl-delay in the host's
+	// robots.txt, up to 30 seconds, is used instead.
+	Delay        time.Duration
+	IgnoreRobots bool
+	// Hosts overrides the policy for hosts and their subdomains, keyed by
+	// host name ("example.com").
+	Hosts map[string]HostPolicy
+}
+
+// forHost returns the delay and robots setting for host.
+func (p PolitenessPolicy) forHost(host string) (time.Duration, bool) {
+	delay, ignore := p.Delay, p.IgnoreRobots
+	for h := host; h != ""; {
+		if hp, ok := p.Hosts[h]; ok {
+			if hp.Delay != 0 {
+				delay = hp.Delay
+			}
+			ignore = ignore || hp.IgnoreRobots
+			break
+		}
+		_, rest, ok := strings.Cut(h, ".")
+		if !ok {
+			break
+		}
+		h = rest
+	}
+	if delay == 0 {
+		delay = DefaultHostDelay
+	}
+	return max(delay, 0), ignore
+}
+
+// politeness is the state shared by the web tools: the next time each host
+// may be requested and the robots.txt rules read.
+type politeness struct {
+	mu     sync.Mutex
+	policy PolitenessPolicy
+	next   map[string]time.Time
+	robots map[string]*robotsEntry
+}
+
+type robotsEntry struct {
+	ready   chan struct{} // closed once rules is set
+	rules   robotsRules
+	expires time.Time
+}
+
+var polite = &politeness{next: make(map[string]time.Time), robots: make(map[string]*robotsEntry)}
+
+// SetPolitenessPolicy changes the politeness policy of the web tools. The
+// hosts' pacing and robots.txt rules read so far are kept.
+func SetPolitenessPolicy(p PolitenessPolicy) {
+	polite.mu.Lock()
+	defer polite.mu.Unlock()
+	polite.policy = p
+}
+
+// DisallowedError is returned for a URL the host's robots.txt disallows.
+type DisallowedError struct{ URL string }
+
+func (e DisallowedError) Error() string {
+	return fmt.Sprintf("robots.txt disallows fetching %s", e.URL)
+}
+
+// politeTransport sends each request once the host's robots.txt allows it
+// and the host's delay since the last request has passed.
+type politeTransport struct{ next http.RoundTripper }
+
+func (t politeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := polite.wait(req.Context(), req.URL); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+func (p *politeness) wait(ctx context.Context, u *url.URL) error {
+	host := strings.ToLower(u.Hostname())
+	p.mu.Lock()
+	delay, ignoreRobots := p.policy.forHost(host)
+	p.mu.Unlock()
+
+	if !ignoreRobots {
+		rules := p.rules(ctx, u)
+		if !rules.allowed(u) {
+			return DisallowedError{URL: u.String()}
+		}
+		delay = max(delay, rules.crawlDelay)
+	}
+
+	p.mu.Lock()
+	now := time.Now()
+	at := now
+	if next := p.next[host]; next.After(now) {
+		at = next
+	}
+	p.next[host] = at.Add(delay)
+	p.mu.Unlock()
+	if wait := at.Sub(now); wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return nil
+}
+
+// rules returns the robots.txt rules for u's origin, reading them if they
+// haven't been or have expired. Concurrent callers share one read.
+func (p *politeness) rules(ctx context.Context, u *url.URL) robotsRules {
+	origin := u.Scheme + "://" + u.Host
+	p.mu.Lock()
+	e, ok := p.robots[origin]
+	if !ok || (time.Now().After(e.expires) && isClosed(e.ready)) {
+		e = &robotsEntry{ready: make(chan struct{})}
+		p.robots[origin] = e
+		p.mu.Unlock()
+		rules, ttl := fetchRobots(ctx, origin)
+		p.mu.Lock()
+		e.rules, e.expires = rules, time.Now().Add(ttl)
+		close(e.ready)
+	}
+	p.mu.Unlock()
+	select {
+	case <-e.ready:
+		return e.rules
+	case <-ctx.Done():
+		return robotsRules{}
+	}
+}
+
+func isClosed(c chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
+// fetchRobots reads origin's robots.txt and how long to keep it. A missing
+// robots.txt allows everything; so does one that can't be read, for an
+// hour, as it is more likely a broken server than a ban.
+func fetchRobots(ctx context.Context, origin string) (robotsRules, time.Duration) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return robotsRules{}, robotsErrorTTL
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := NewSafeClient(10 * time.Second).Do(req)
+	if err != nil {
+		return robotsRules{}, robotsErrorTTL
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return parseRobots(io.LimitReader(resp.Body, maxRobotsBytes), robotsAgent), robotsTTL
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return robotsRules{}, robotsTTL
+	}
+	return robotsRules{}, robotsErrorTTL
+}
+
+// robotsRules are the rules of the robots.txt group that applies to us.
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// parseRobots returns the rules of the group naming agent, or of the "*"
+// group if none does, as RFC 9309 describes.
+func parseRobots(r io.Reader, agent string) robotsRules {
+	var own, star robotsRules
+	var foundOwn bool
+	var agents []string // the user-agent lines of the current group
+	inRules := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if key == "user-agent" {
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+			continue
+		}
+		if key != "allow" && key != "disallow" && key != "crawl-delay" {
+			continue // e.g. sitemap, which belongs to no group
+		}
+		inRules = true
+		for _, a := range agents {
+			var g *robotsRules
+			switch {
+			case a != "*" && a != "" && strings.Contains(agent, a):
+				g, foundOwn = &own, true
+			case a == "*":
+				g = &star
+			default:
+				continue
+			}
+			switch key {
+			case "allow", "disallow":
+				if value != "" {
+					g.rules = append(g.rules, robotsRule{allow: key == "allow", pattern: value})
+				}
+			case "crawl-delay":
+				if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+					g.crawlDelay = min(time.Duration(secs*float64(time.Second)), maxCrawlDelay)
+				}
+			}
+		}
+	}
+	if foundOwn {
+		return own
+	}
+	return star
+}
+
+// allowed reports whether u may be fetched: the longest matching rule
+// decides, allow winning ties, and no match allows.
+func (r robotsRules) allowed(u *url.URL) bool {
+	target := u.EscapedPath()
+	if target == "" {
+		target = "/"
+	}
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+	allow, best := true, -1
+	for _, rule := range r.rules {
+		if len(rule.pattern) < best || !robotsMatch(rule.pattern, target) {
+			continue
+		}
+		if len(rule.pattern) > best || rule.allow {
+			allow, best = rule.allow, len(rule.pattern)
+		}
+	}
+	return allow
+}
+
+// robotsMatch matches a robots.txt path pattern, in which "*" matches any
+// characters and a trailing "$" anchors the end, against a path.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	for _, part := range parts[1:] {
+		i := strings.Index(path[pos:], part)
+		if i < 0 {
+			return false
+		}
+		pos += i + len(part)
+	}
+	if !anchored {
+		return true
+	}
+	last := parts[len(parts)-1]
+	return pos == len(path) || (len(parts) > 1 && strings.HasSuffix(path, last))
+}
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRobots = `# comments are ignored
+User-agent: Googlebot
+Disallow: /
+
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.json$
+Crawl-delay: 2
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+func TestParseRobots(t *testing.T) {
+	rules := parseRobots(strings.NewReader(testRobots), robotsAgent)
+	if rules.crawlDelay != 2*time.Second {
+		t.Errorf("crawl delay = %v, want 2s", rules.crawlDelay)
+	}
+	cases := map[string]bool{
+		"/":                      true,
+		"/private":               false,
+		"/private/notes":         false,
+		"/private/public/page":   true,
+		"/data.json":             false,
+		"/data.json?x=1":         true,
+		"/docs/index.html":       true,
+		"/privateer":             false,
+		"/a/b/report.json":       false,
+		"/a/b/report.json/extra": true,
+	}
+	for path, want := range cases {
+		u, _ := url.Parse("https://example.com" + path)
+		if got := rules.allowed(u); got != want {
+			t.Errorf("allowed(%s) = %v, want %v", path, got, want)
+		}
+	}
+
+	own := parseRobots(strings.NewReader("User-agent: *\nDisallow: /\n\nUser-agent: RavenBot\nUser-agent: other\nDisallow:\n"), robotsAgent)
+	if u, _ := url.Parse("https://example.com/page"); !own.allowed(u) {
+		t.Error("expected the group naming ravenbot to replace the * group")
+	}
+}
+
+func TestPolitenessPolicyForHost(t *testing.T) {
+	p := PolitenessPolicy{Hosts: map[string]HostPolicy{
+		"example.com":      {Delay: 5 * time.Second},
+		"docs.example.com": {IgnoreRobots: true},
+		"fast.test":        {Delay: -1},
+	}}
+	check := func(host string, wantDelay time.Duration, wantIgnore bool) {
+		t.Helper()
+		if d, ignore := p.forHost(host); d != wantDelay || ignore != wantIgnore {
+			t.Errorf("forHost(%s) = %v, %v; want %v, %v", host, d, ignore, wantDelay, wantIgnore)
+		}
+	}
+	check("other.org", DefaultHostDelay, false)
+	check("www.example.com", 5*time.Second, false)
+	check("docs.example.com", DefaultHostDelay, true)
+	check("fast.test", 0, false)
+}
+
+func TestPoliteFetch(t *testing.T) {
+	var times []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			if got := r.UserAgent(); got != userAgent || !strings.HasPrefix(got, robotsAgent+"/") {
+				t.Errorf("robots.txt requested as %q, want %q", got, userAgent)
+			}
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+		default:
+			times = append(times, time.Now())
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><body><p>Hello</p></body></html>`))
+		}
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	SetPolitenessPolicy(PolitenessPolicy{Delay: -1, Hosts: map[string]HostPolicy{u.Hostname(): {Delay: 100 * time.Millisecond}}})
+	defer SetPolitenessPolicy(PolitenessPolicy{Delay: -1})
+	ctx := context.Background()
+
+	var disallowed DisallowedError
+	if _, err := ScrapePage(ctx, srv.URL+"/private/page"); !errors.As(err, &disallowed) {
+		t.Errorf("expected a robots.txt error, got %v", err)
+	}
+	for range 2 {
+		if _, err := ScrapePage(ctx, srv.URL+"/page"); err != nil {
+			t.Fatalf("ScrapePage failed: %v", err)
+		}
+	}
+	if len(times) != 2 || times[1].Sub(times[0]) < 90*time.Millisecond {
+		t.Errorf("expected requests 100ms apart, got %v", times)
+	}
+
+	SetPolitenessPolicy(PolitenessPolicy{Delay: -1, IgnoreRobots: true})
+	if _, err := ScrapePage(ctx, srv.URL+"/private/page"); err != nil {
+		t.Errorf("expected robots.txt to be ignored, got %v", err)
+	}
+}
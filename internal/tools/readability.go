@@ -0,0 +1,589 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// maxArticleChars caps the text ScrapePage keeps, about 5k tokens.
+	maxArticleChars = 20000
+	// minArticleChars is the text a content element needs to be taken as
+	// the article rather than a teaser or caption.
+	minArticleChars = 250
+)
+
+// Article is the main content of a web page, without its navigation,
+// sidebars, cookie banners and other boilerplate, with the metadata the
+// page declares.
+type Article struct {
+	URL         string
+	Title       string
+	Byline      string
+	SiteName    string
+	Description string
+	Published   time.Time // zero if the page doesn't say
+	// Paywalled is set when the page declares its content isn't free to
+	// read, so Text may be only a teaser.
+	Paywalled bool
+	Text      string
+}
+
+// ScrapePage reads a web page and returns its main content as text, in the
+// manner of Mozilla's Readability.
+func ScrapePage(ctx context.Context, rawURL string) (Article, error) {
+	doc, base, err := getHTML(ctx, newWebClient(crawlTimeout), strings.TrimSpace(rawURL))
+	if err != nil {
+		return Article{}, err
+	}
+	a := ExtractArticle(doc, base)
+	if r := []rune(a.Text); len(r) > maxArticleChars {
+		a.Text = string(r[:maxArticleChars]) + "…"
+	}
+	return a, nil
+}
+
+var (
+	// unlikelyCandidate and maybeCandidate match the class and id of
+	// elements that are boilerplate, unless they also look like content.
+	unlikelyCandidate = regexp.MustCompile(`(?i)-ad-|^ad-|advert|banner|breadcrumb|combx|comment|community|consent|cookie|disqus|extra|footer|gdpr|header|menu|modal|navbar|newsletter|pager|pagination|popup|promo|related|remark|replies|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|toolbar|widget`)
+	maybeCandidate    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow|post|entry`)
+	positiveClass     = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeClass     = regexp.MustCompile(`(?i)-ad-|hidden|banner|combx|comment|com-|contact|foot|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|widget`)
+	bylineClass       = regexp.MustCompile(`(?i)byline|author|dateline|writtenby|p-author`)
+)
+
+// ExtractArticle finds the main content of a parsed page and its metadata.
+// It removes the boilerplate from doc as it goes.
+func ExtractArticle(doc *html.Node, pageURL *url.URL) Article {
+	a := Article{URL: pageURL.String()}
+	meta := readMetadata(doc)
+	a.SiteName = meta.first("og:site_name", "application-name")
+	a.Description = meta.first("og:description", "description", "twitter:description")
+	a.Byline = meta.first("author", "article:author", "dc.creator", "parsely-author", "sailthru.author")
+	if strings.Contains(a.Byline, "://") {
+		a.Byline = ""
+	}
+	a.Published = parseFeedDate(meta.first("article:published_time", "datepublished", "og:published_time", "parsely-pub-date", "sailthru.date", "dc.date", "date", "pubdate"))
+	a.Title = meta.first("og:title", "twitter:title")
+	if a.Title == "" {
+		a.Title = trimSiteName(meta.title, a.SiteName)
+	}
+	if ld := meta.article; ld != nil {
+		a.Title = orDefault(ld.title, a.Title)
+		a.Byline = orDefault(ld.author, a.Byline)
+		a.SiteName = orDefault(ld.publisher, a.SiteName)
+		a.Description = orDefault(ld.description, a.Description)
+		if t := parseFeedDate(ld.published); !t.IsZero() {
+			a.Published = t
+		}
+		a.Paywalled = ld.paywalled
+	}
+
+	byline := pruneBoilerplate(doc)
+	if a.Byline == "" {
+		a.Byline = byline
+	}
+	a.Byline = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(a.Byline, "By "), "by "))
+
+	nodes := articleContent(doc)
+	var t pageText
+	for _, n := range nodes {
+		if a.Published.IsZero() {
+			a.Published = firstTime(n)
+		}
+		cleanConditionally(n)
+		t.walk(n)
+		t.sb.WriteString("\n\n")
+	}
+	if a.Title == "" {
+		a.Title = t.title
+	}
+	a.Title = strings.Join(strings.Fields(a.Title), " ")
+	text := cleanPageText(t.sb.String())
+	// The headline usually opens the content too.
+	if rest, ok := strings.CutPrefix(text, "# "+a.Title); ok && (rest == "" || rest[0] == '\n') {
+		text = strings.TrimSpace(rest)
+	}
+	a.Text = text
+	return a
+}
+
+// pageMetadata is what a page declares about itself in its head.
+type pageMetadata struct {
+	title   string
+	values  map[string]string // meta name, property or itemprop → content
+	article *ldArticle
+}
+
+func (m pageMetadata) first(keys ...string) string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(m.values[k]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ldArticle is the part of a schema.org Article given as JSON-LD that
+// ExtractArticle uses.
+type ldArticle struct {
+	title, author, publisher, description, published string
+	paywalled                                        bool
+}
+
+func readMetadata(doc *html.Node) pageMetadata {
+	m := pageMetadata{values: make(map[string]string)}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Title:
+				if m.title == "" {
+					m.title = textContent(n)
+				}
+			case atom.Meta:
+				key := strings.ToLower(attr(n, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(n, "name"))
+				}
+				if key == "" {
+					key = strings.ToLower(attr(n, "itemprop"))
+				}
+				if key != "" && m.values[key] == "" {
+					m.values[key] = attr(n, "content")
+				}
+			case atom.Script:
+				if strings.EqualFold(attr(n, "type"), "application/ld+json") && m.article == nil {
+					m.article = parseLDArticle(textContent(n))
+				}
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return m
+}
+
+// parseLDArticle returns the first Article-like object in a JSON-LD block,
+// looking through arrays and @graph.
+func parseLDArticle(data string) *ldArticle {
+	var v any
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return nil
+	}
+	var find func(any) *ldArticle
+	find = func(v any) *ldArticle {
+		switch v := v.(type) {
+		case []any:
+			for _, e := range v {
+				if a := find(e); a != nil {
+					return a
+				}
+			}
+		case map[string]any:
+			if graph, ok := v["@graph"]; ok {
+				return find(graph)
+			}
+			if !isArticleType(v["@type"]) {
+				return nil
+			}
+			title, _ := v["headline"].(string)
+			if title == "" {
+				title, _ = v["name"].(string)
+			}
+			description, _ := v["description"].(string)
+			published, _ := v["datePublished"].(string)
+			return &ldArticle{title: title, author: ldName(v["author"]), publisher: ldName(v["publisher"]), description: description, published: published, paywalled: ldPaywalled(v)}
+		}
+		return nil
+	}
+	return find(v)
+}
+
+func isArticleType(t any) bool {
+	switch t := t.(type) {
+	case string:
+		return strings.HasSuffix(t, "Article") || t == "BlogPosting" || t == "Report" || t == "LiveBlogPosting"
+	case []any:
+		for _, e := range t {
+			if isArticleType(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ldPaywalled reports whether a JSON-LD article, or a part of it, says
+// isAccessibleForFree: false, as news sites mark paywalled content.
+func ldPaywalled(v map[string]any) bool {
+	switch free := v["isAccessibleForFree"].(type) {
+	case bool:
+		if !free {
+			return true
+		}
+	case string:
+		if strings.EqualFold(free, "false") {
+			return true
+		}
+	}
+	parts, ok := v["hasPart"].([]any)
+	if !ok {
+		parts = []any{v["hasPart"]}
+	}
+	for _, p := range parts {
+		if m, ok := p.(map[string]any); ok && ldPaywalled(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// ldName returns the name of a JSON-LD person or organisation, or the
+// names of a list of them.
+func ldName(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any:
+		name, _ := v["name"].(string)
+		return name
+	case []any:
+		var names []string
+		for _, e := range v {
+			if n := ldName(e); n != "" {
+				names = append(names, n)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+// trimSiteName drops the site's name from a <title> such as
+// "Article — Site".
+func trimSiteName(title, site string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	for _, sep := range []string{" | ", " - ", " — ", " – ", " · ", " :: "} {
+		i := strings.LastIndex(title, sep)
+		if i <= 0 {
+			continue
+		}
+		if suffix := title[i+len(sep):]; site == "" || strings.EqualFold(suffix, site) {
+			if site != "" || len(strings.Fields(title[:i])) >= 3 {
+				return title[:i]
+			}
+		}
+	}
+	return title
+}
+
+// pruneBoilerplate removes the elements that are never content: scripts,
+// navigation, forms, hidden elements and those whose class or id marks them
+// as banners, sidebars, comments and the like. It returns the text of the
+// first byline it removes.
+func pruneBoilerplate(doc *html.Node) string {
+	var byline string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; {
+			next := c.NextSibling
+			if c.Type == html.CommentNode {
+				n.RemoveChild(c)
+			} else if c.Type == html.ElementNode {
+				if isBoilerplate(c) {
+					n.RemoveChild(c)
+				} else if b := bylineText(c); b != "" {
+					if byline == "" {
+						byline = b
+					}
+					n.RemoveChild(c)
+				} else {
+					walk(c)
+				}
+			}
+			c = next
+		}
+	}
+	walk(doc)
+	return byline
+}
+
+func isBoilerplate(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Svg, atom.Iframe, atom.Nav, atom.Footer,
+		atom.Form, atom.Button, atom.Input, atom.Select, atom.Textarea, atom.Aside, atom.Dialog, atom.Link, atom.Meta:
+		return true
+	case atom.Html, atom.Body, atom.Article, atom.Main, atom.A:
+		return false
+	}
+	if _, hidden := attrOK(n, "hidden"); hidden || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	if style := strings.ReplaceAll(attr(n, "style"), " ", ""); strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+		return true
+	}
+	switch attr(n, "role") {
+	case "navigation", "banner", "complementary", "contentinfo", "dialog", "alertdialog", "menu", "menubar":
+		return true
+	}
+	if n.DataAtom == atom.Header && !hasDescendant(n, atom.H1) {
+		return true
+	}
+	match := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyCandidate.MatchString(match) && !maybeCandidate.MatchString(match)
+}
+
+// bylineText returns the text of a byline element, such as
+// <span class="author">, or "".
+func bylineText(n *html.Node) string {
+	if attr(n, "rel") != "author" && attr(n, "itemprop") != "author" && !bylineClass.MatchString(attr(n, "class")+" "+attr(n, "id")) {
+		return ""
+	}
+	text := strings.Join(strings.Fields(textContent(n)), " ")
+	if text == "" || utf8.RuneCountInString(text) > 100 {
+		return ""
+	}
+	return text
+}
+
+// articleContent returns the elements holding a page's main content: its
+// <article> or <main> with the most text if it has enough, otherwise the
+// best element by Readability's scoring of paragraphs, with the siblings
+// that continue it. Failing both it returns the body.
+func articleContent(doc *html.Node) []*html.Node {
+	var semantic *html.Node
+	semanticLen := 0
+	forEachElement(doc, func(n *html.Node) {
+		if n.DataAtom == atom.Article || n.DataAtom == atom.Main || attr(n, "role") == "main" {
+			if l := textLength(n); l > semanticLen {
+				semantic, semanticLen = n, l
+			}
+		}
+	})
+	if semanticLen >= minArticleChars {
+		return []*html.Node{semantic}
+	}
+
+	scores := make(map[*html.Node]float64)
+	var order []*html.Node
+	add := func(n *html.Node, s float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			order = append(order, n)
+		}
+		scores[n] += s
+	}
+	forEachElement(doc, func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+		case atom.Div:
+			if hasBlockChild(n) {
+				return
+			}
+		default:
+			return
+		}
+		text := strings.Join(strings.Fields(textContent(n)), " ")
+		l := utf8.RuneCountInString(text)
+		if l < 25 {
+			return
+		}
+		s := 1 + float64(strings.Count(text, ",")) + min(float64(l/100), 3)
+		add(n.Parent, s)
+		if n.Parent != nil {
+			add(n.Parent.Parent, s/2)
+		}
+	})
+	var top *html.Node
+	for _, n := range order {
+		scores[n] *= 1 - linkDensity(n)
+		if top == nil || scores[n] > scores[top] {
+			top = n
+		}
+	}
+	if top == nil || textLength(top) < minArticleChars/2 {
+		if body := findElement(doc, atom.Body); body != nil {
+			return []*html.Node{body}
+		}
+		return []*html.Node{doc}
+	}
+	if top.Parent == nil {
+		return []*html.Node{top}
+	}
+
+	threshold := max(10, scores[top]*0.2)
+	var nodes []*html.Node
+	for c := top.Parent.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		include := c == top
+		if s, ok := scores[c]; ok && s >= threshold {
+			include = true
+		}
+		if c.DataAtom == atom.P && textLength(c) >= 80 && linkDensity(c) < 0.25 {
+			include = true
+		}
+		if include {
+			nodes = append(nodes, c)
+		}
+	}
+	return nodes
+}
+
+func initialScore(n *html.Node) float64 {
+	var s float64
+	switch n.DataAtom {
+	case atom.Div:
+		s = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		s = 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		s = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		s = -5
+	}
+	for _, v := range []string{attr(n, "class"), attr(n, "id")} {
+		if v == "" {
+			continue
+		}
+		if negativeClass.MatchString(v) {
+			s -= 25
+		}
+		if positiveClass.MatchString(v) {
+			s += 25
+		}
+	}
+	return s
+}
+
+// cleanConditionally removes the lists, tables and divs within the content
+// that are mostly links, such as "read next" blocks and tag clouds.
+func cleanConditionally(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode {
+			switch c.DataAtom {
+			case atom.Ul, atom.Ol, atom.Div, atom.Section, atom.Table:
+				if d := linkDensity(c); d > 0.5 || (d > 0.2 && textLength(c) < 25) {
+					n.RemoveChild(c)
+					c = next
+					continue
+				}
+			}
+			cleanConditionally(c)
+		}
+		c = next
+	}
+}
+
+// firstTime returns the moment given by the first <time datetime> in n.
+func firstTime(n *html.Node) time.Time {
+	var t time.Time
+	forEachElement(n, func(e *html.Node) {
+		if t.IsZero() && e.DataAtom == atom.Time {
+			t = parseFeedDate(attr(e, "datetime"))
+		}
+	})
+	return t
+}
+
+func linkDensity(n *html.Node) float64 {
+	total := textLength(n)
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	forEachElement(n, func(e *html.Node) {
+		if e.DataAtom == atom.A {
+			links += textLength(e)
+		}
+	})
+	return float64(min(links, total)) / float64(total)
+}
+
+// textLength is the length of n's text, with spacing collapsed.
+func textLength(n *html.Node) int {
+	return utf8.RuneCountInString(strings.Join(strings.Fields(textContent(n)), " "))
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && (c.DataAtom == atom.Script || c.DataAtom == atom.Style) && n.DataAtom != atom.Script {
+			continue
+		}
+		sb.WriteString(textContent(c))
+		if c.Type == html.ElementNode && isBlock(c.DataAtom) {
+			sb.WriteString(" ")
+		}
+	}
+	return sb.String()
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && (isBlock(c.DataAtom) || c.DataAtom == atom.Li) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDescendant(n *html.Node, a atom.Atom) bool {
+	return findElement(n, a) != nil
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	var found *html.Node
+	forEachElement(n, func(e *html.Node) {
+		if found == nil && e.DataAtom == a {
+			found = e
+		}
+	})
+	return found
+}
+
+// forEachElement calls fn for n, if it is an element, and each element
+// within it, in document order.
+func forEachElement(n *html.Node, fn func(*html.Node)) {
+	if n.Type == html.ElementNode {
+		fn(n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		forEachElement(c, fn)
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	v, _ := attrOK(n, key)
+	return v
+}
+
+func attrOK(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testArticlePage = `<!DOCTYPE html><html><head>
+<title>Go 1.26 is released | The Go Blog</title>
+<meta property="og:site_name" content="The Go Blog">
+<meta name="description" content="Faster maps and a new GC.">
+<script type="application/ld+json">{"@context":"https://schema.org","@graph":[
+ {"@type":"WebSite","name":"The Go Blog"},
+ {"@type":"BlogPosting","headline":"Go 1.26 is released","author":[{"@type":"Person","name":"Jane Doe"},{"@type":"Person","name":"Ian Roe"}],"datePublished":"2026-02-10T17:00:00Z"}]}</script>
+</head><body>
+<header><a href="/">Home</a> <a href="/blog">Blog</a></header>
+<div class="cookie-consent">We use cookies to improve your experience. <button>Accept</button></div>
+<div class="layout">
+<article>
+  <h1>Go 1.26 is released</h1>
+  <p class="byline">By Jane Doe</p>
+  <p>Today the Go team is very happy to announce the release of Go 1.26, which you can get from the download page.</p>
+  <h2>Maps</h2>
+  <p>Maps are faster, using Swiss tables throughout, and the new garbage collector cuts pause times for large heaps.</p>
+  <p>As always, the release notes have the full list of changes, and we thank everyone who contributed.</p>
+  <ul class="tags"><li><a href="/t/go">go</a></li><li><a href="/t/release">release</a></li></ul>
+</article>
+<aside><h3>Popular</h3><a href="/x">Something else entirely</a></aside>
+</div>
+<footer>© 2026 Google</footer>
+</body></html>`
+
+const testDivPage = `<html><head><title>Notes</title></head><body>
+<div id="top-menu"><a href="/a">Products</a><a href="/b">Pricing</a><a href="/c">Docs</a></div>
+<div class="wrapper">
+ <div class="post-body">
+  <p>Spatial indexes let a database answer "what is near here" without scanning every row, which matters once tables grow.</p>
+  <p>PostGIS builds them with GiST, splitting space into nested bounding boxes, so a query visits only the boxes that overlap.</p>
+  <p>Rebuild them after bulk loads, and check with EXPLAIN that your queries actually use them.</p>
+ </div>
+ <div class="share-links"><a href="/tw">Share on X</a> <a href="/fb">Share on Facebook</a></div>
+</div>
+<div class="footer-links"><a href="/privacy">Privacy</a></div>
+</body></html>`
+
+func TestScrapePage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch r.URL.Path {
+		case "/article":
+			_, _ = w.Write([]byte(testArticlePage))
+		case "/notes":
+			_, _ = w.Write([]byte(testDivPage))
+		case "/premium":
+			_, _ = w.Write([]byte(`<html><head><script type="application/ld+json">{"@type":"NewsArticle","headline":"Members only","isAccessibleForFree":"False","hasPart":{"@type":"WebPageElement","isAccessibleForFree":false,"cssSelector":".paywall"}}</script></head><body><article><p>Subscribe to read the rest.</p></article></body></html>`))
+		case "/file.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	a, err := ScrapePage(ctx, srv.URL+"/article")
+	if err != nil {
+		t.Fatalf("ScrapePage failed: %v", err)
+	}
+	if a.Title != "Go 1.26 is released" || a.Byline != "Jane Doe, Ian Roe" || a.SiteName != "The Go Blog" || a.Description != "Faster maps and a new GC." {
+		t.Errorf("unexpected metadata: %+v", a)
+	}
+	if !a.Published.Equal(time.Date(2026, 2, 10, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("published = %v", a.Published)
+	}
+	want := "Today the Go team is very happy to announce the release of Go 1.26, which you can get from the download page.\n\n## Maps\n\nMaps are faster, using Swiss tables throughout, and the new garbage collector cuts pause times for large heaps.\n\nAs always, the release notes have the full list of changes, and we thank everyone who contributed."
+	if a.Text != want {
+		t.Errorf("text = %q, want %q", a.Text, want)
+	}
+
+	a, err = ScrapePage(ctx, srv.URL+"/notes")
+	if err != nil {
+		t.Fatalf("ScrapePage failed: %v", err)
+	}
+	if a.Title != "Notes" || !strings.HasPrefix(a.Text, "Spatial indexes") || !strings.HasSuffix(a.Text, "actually use them.") {
+		t.Errorf("unexpected scored article: %+v", a)
+	}
+	for _, junk := range []string{"Pricing", "Share on", "Privacy"} {
+		if strings.Contains(a.Text, junk) {
+			t.Errorf("boilerplate %q kept: %q", junk, a.Text)
+		}
+	}
+
+	if _, err := ScrapePage(ctx, srv.URL+"/file.pdf"); err == nil || !strings.Contains(err.Error(), "not HTML") {
+		t.Errorf("expected a not-HTML error, got %v", err)
+	}
+	var status PageStatusError
+	if _, err := ScrapePage(ctx, srv.URL+"/missing"); !errors.As(err, &status) || status.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a 404 status error, got %v", err)
+	}
+	a, err = ScrapePage(ctx, srv.URL+"/premium")
+	if err != nil || !a.Paywalled {
+		t.Errorf("expected a paywalled article, got %+v, %v", a, err)
+	}
+}
+
+func TestTrimSiteName(t *testing.T) {
+	cases := []struct{ title, site, want string }{
+		{"Go 1.26 is released | The Go Blog", "The Go Blog", "Go 1.26 is released"},
+		{"Go 1.26 is released - Go team", "", "Go 1.26 is released"},
+		{"Pros - Cons", "", "Pros - Cons"},
+		{"News — Example", "Other", "News — Example"},
+	}
+	for _, c := range cases {
+		if got := trimSiteName(c.title, c.site); got != c.want {
+			t.Errorf("trimSiteName(%q, %q) = %q, want %q", c.title, c.site, got, c.want)
+		}
+	}
+}
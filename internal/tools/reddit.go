@@ -0,0 +1,282 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	redditTimeout = 20 * time.Second
+	// maxRedditBytes caps the listing and comment responses read.
+	maxRedditBytes = 5 << 20
+	// redditCommentPosts is how many of a subreddit's top posts get their
+	// comments read, to keep within Reddit's rate limits.
+	redditCommentPosts = 5
+	// maxRedditText caps the self text and comments kept per post.
+	maxRedditText = 500
+
+	DefaultRedditPosts    = 10
+	DefaultRedditComments = 3
+)
+
+var (
+	// redditPublic, redditOAuth and redditTokenURL are Reddit's endpoints;
+	// tests point them at a local server.
+	redditPublic   = "https://www.reddit.com"
+	redditOAuth    = "https://oauth.reddit.com"
+	redditTokenURL = "https://www.reddit.com/api/v1/access_token"
+)
+
+// RedditPost is a subreddit post with its top comments.
+type RedditPost struct {
+	ID          string
+	Subreddit   string
+	Title       string
+	URL         string // the linked page, or the discussion for self posts
+	Permalink   string // the discussion
+	Text        string // the self text
+	Score       int
+	NumComments int
+	Created     time.Time
+	Comments    []RedditComment
+}
+
+// RedditComment is a top-level comment on a post.
+type RedditComment struct {
+	Author string
+	Body   string
+	Score  int
+}
+
+// Item returns the post as a feed item, so digests deduplicate it with
+// the feeds linking the same page.
+func (p RedditPost) Item() RSSItem {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d points, %d comments, discussion: %s", p.Score, p.NumComments, p.Permalink))
+	if p.Text != "" {
+		sb.WriteString("\n" + p.Text)
+	}
+	for _, c := range p.Comments {
+		sb.WriteString(fmt.Sprintf("\nTop comment (%d points): %s", c.Score, c.Body))
+	}
+	return RSSItem{Title: p.Title, Link: p.URL, Description: sb.String(), Published: p.Created, Feed: "r/" + p.Subreddit}
+}
+
+// Reddit reads subreddits through Reddit's JSON API, with application-only
+// OAuth when it has credentials.
+type Reddit struct {
+	client *http.Client
+	base   string
+	oauth  bool
+}
+
+// NewReddit returns a Reddit client. Without a client ID it reads the
+// public JSON pages, which Reddit limits to a few requests a minute.
+func NewReddit(clientID, clientSecret string) *Reddit {
+	client := NewSafeClient(redditTimeout)
+	if clientID == "" {
+		return &Reddit{client: client, base: redditPublic}
+	}
+	cc := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     redditTokenURL,
+		AuthStyle:    oauth2.AuthStyleInHeader,
+	}
+	// Tokens are fetched with the same (safe) client and reused until they
+	// expire.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+	oauthClient := oauth2.NewClient(ctx, cc.TokenSource(ctx))
+	oauthClient.Timeout = redditTimeout
+	return &Reddit{client: oauthClient, base: redditOAuth, oauth: true}
+}
+
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Kind string          `json:"kind"`
+			Data json.RawMessage `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+type redditPostData struct {
+	ID          string  `json:"id"`
+	Subreddit   string  `json:"subreddit"`
+	Title       string  `json:"title"`
+	URL         string  `json:"url"`
+	Permalink   string  `json:"permalink"`
+	SelfText    string  `json:"selftext"`
+	IsSelf      bool    `json:"is_self"`
+	Stickied    bool    `json:"stickied"`
+	Over18      bool    `json:"over_18"`
+	Score       int     `json:"score"`
+	NumComments int     `json:"num_comments"`
+	CreatedUTC  float64 `json:"created_utc"`
+}
+
+// TopPosts returns a subreddit's top posts of period (hour, day, week,
+// month, year or all), reading the top comments of the first few. Pinned
+// and NSFW posts are left out.
+func (r *Reddit) TopPosts(ctx context.Context, subreddit, period string, posts, comments int) ([]RedditPost, error) {
+	if posts <= 0 {
+		posts = DefaultRedditPosts
+	}
+	if period == "" {
+		period = "day"
+	}
+	q := url.Values{"t": {period}, "limit": {strconv.Itoa(min(posts, 100))}, "raw_json": {"1"}}
+	var listing redditListing
+	if err := r.get(ctx, "/r/"+subreddit+"/top", q, &listing); err != nil {
+		return nil, err
+	}
+	var out []RedditPost
+	for _, child := range listing.Data.Children {
+		if child.Kind != "t3" {
+			continue
+		}
+		var d redditPostData
+		if err := json.Unmarshal(child.Data, &d); err != nil {
+			return nil, fmt.Errorf("failed to decode Reddit post: %w", err)
+		}
+		if d.Stickied || d.Over18 {
+			continue
+		}
+		p := RedditPost{
+			ID:          d.ID,
+			Subreddit:   d.Subreddit,
+			Title:       strings.TrimSpace(d.Title),
+			URL:         d.URL,
+			Permalink:   "https://www.reddit.com" + d.Permalink,
+			Text:        redditSnippet(d.SelfText),
+			Score:       d.Score,
+			NumComments: d.NumComments,
+			Created:     time.Unix(int64(d.CreatedUTC), 0),
+		}
+		if d.IsSelf || p.URL == "" {
+			p.URL = p.Permalink
+		}
+		out = append(out, p)
+	}
+	if comments >= 0 {
+		if comments == 0 {
+			comments = DefaultRedditComments
+		}
+		for i := range out[:min(len(out), redditCommentPosts)] {
+			if out[i].NumComments == 0 {
+				continue
+			}
+			c, err := r.topComments(ctx, out[i].Subreddit, out[i].ID, comments)
+			if err != nil {
+				// The posts are still worth having without their comments.
+				slog.Warn("Failed to read Reddit comments", "subreddit", subreddit, "post", out[i].ID, "error", err)
+				continue
+			}
+			out[i].Comments = c
+		}
+	}
+	return out, nil
+}
+
+// topComments returns the n top-level comments with the highest score.
+func (r *Reddit) topComments(ctx context.Context, subreddit, id string, n int) ([]RedditComment, error) {
+	q := url.Values{"sort": {"top"}, "limit": {strconv.Itoa(n)}, "depth": {"1"}, "raw_json": {"1"}}
+	var listings []redditListing
+	if err := r.get(ctx, "/r/"+subreddit+"/comments/"+id, q, &listings); err != nil {
+		return nil, err
+	}
+	if len(listings) < 2 {
+		return nil, nil
+	}
+	var out []RedditComment
+	for _, child := range listings[1].Data.Children {
+		if child.Kind != "t1" || len(out) == n {
+			continue
+		}
+		var d struct {
+			Author   string `json:"author"`
+			Body     string `json:"body"`
+			Score    int    `json:"score"`
+			Stickied bool   `json:"stickied"`
+		}
+		if err := json.Unmarshal(child.Data, &d); err != nil {
+			return nil, fmt.Errorf("failed to decode Reddit comment: %w", err)
+		}
+		// Moderator notes are pinned; deleted and removed comments keep a
+		// placeholder body.
+		if d.Stickied || d.Body == "[deleted]" || d.Body == "[removed]" {
+			continue
+		}
+		out = append(out, RedditComment{Author: d.Author, Body: redditSnippet(d.Body), Score: d.Score})
+	}
+	return out, nil
+}
+
+// FetchSubreddits returns the top posts of subreddits created after since
+// as feed items, deduplicated and newest first like FetchFeeds.
+func (r *Reddit) FetchSubreddits(ctx context.Context, subreddits []string, period string, posts, comments int, since time.Time) []RSSItem {
+	var all []RSSItem
+	for _, s := range subreddits {
+		top, err := r.TopPosts(ctx, s, period, posts, comments)
+		if err != nil {
+			slog.Warn("Failed to fetch subreddit", "subreddit", s, "error", err)
+			continue
+		}
+		for _, p := range top {
+			if p.Created.After(since) {
+				all = append(all, p.Item())
+			}
+		}
+	}
+	return MergeFeedItems(all)
+}
+
+func (r *Reddit) get(ctx context.Context, path string, q url.Values, v any) error {
+	// The OAuth API serves JSON without the .json suffix.
+	endpoint := r.base + path + ".json?" + q.Encode()
+	if r.oauth {
+		endpoint = r.base + path + "?" + q.Encode()
+	}
+	if err := ValidateURL(ctx, endpoint); err != nil {
+		return fmt.Errorf("invalid Reddit URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Reddit request: %w", err)
+	}
+	// Reddit blocks generic user agents.
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("reddit request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("reddit returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRedditBytes)).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode Reddit response: %w", err)
+	}
+	return nil
+}
+
+// redditSnippet flattens Markdown text to one line, truncated.
+func redditSnippet(s string) string {
+	s = strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+	if r := []rune(s); len(r) > maxRedditText {
+		s = string(r[:maxRedditText]) + "…"
+	}
+	return s
+}
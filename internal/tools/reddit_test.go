@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRedditTop = `{"data":{"children":[
+{"kind":"t3","data":{"id":"pin","subreddit":"golang","title":"Weekly thread","stickied":true,"permalink":"/r/golang/comments/pin/"}},
+{"kind":"t3","data":{"id":"a1","subreddit":"golang","title":"Go 1.26 released","url":"https://go.dev/blog/go1.26","permalink":"/r/golang/comments/a1/go_126/","score":900,"num_comments":2,"created_utc":1773162000}},
+{"kind":"t3","data":{"id":"b2","subreddit":"golang","title":"Ask: generics & iterators","is_self":true,"selftext":"How do\n\nthey mix?","url":"https://www.reddit.com/r/golang/comments/b2/ask/","permalink":"/r/golang/comments/b2/ask/","score":40,"num_comments":0,"created_utc":1773158400}}
+]}}`
+
+const testRedditComments = `[{"data":{"children":[]}},{"data":{"children":[
+{"kind":"t1","data":{"author":"AutoModerator","body":"Rules","stickied":true,"score":1}},
+{"kind":"t1","data":{"author":"gopher","body":"Finally, iterators!","score":120}},
+{"kind":"t1","data":{"author":"x","body":"[deleted]","score":3}},
+{"kind":"more","data":{}}
+]}}]`
+
+func TestReddit(t *testing.T) {
+	var paths []string
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		auth = append(auth, r.Header.Get("Authorization"))
+		switch {
+		case r.URL.Path == "/api/v1/access_token":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "id" || pass != "secret" || r.FormValue("grant_type") != "client_credentials" {
+				t.Errorf("unexpected token request %v %v", r.Header, r.Form)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"tok","token_type":"bearer","expires_in":3600}`))
+		case strings.HasSuffix(r.URL.Path, "/top.json"), strings.HasSuffix(r.URL.Path, "/top"):
+			if r.URL.Query().Get("t") != "week" || r.URL.Query().Get("raw_json") != "1" {
+				t.Errorf("unexpected query %q", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(testRedditTop))
+		case strings.Contains(r.URL.Path, "/comments/a1"):
+			_, _ = w.Write([]byte(testRedditComments))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	oldPublic, oldOAuth, oldToken := redditPublic, redditOAuth, redditTokenURL
+	redditPublic, redditOAuth, redditTokenURL = srv.URL, srv.URL+"/oauth", srv.URL+"/api/v1/access_token"
+	defer func() { redditPublic, redditOAuth, redditTokenURL = oldPublic, oldOAuth, oldToken }()
+	ctx := context.Background()
+
+	posts, err := NewReddit("", "").TopPosts(ctx, "golang", "week", 0, 0)
+	if err != nil {
+		t.Fatalf("TopPosts failed: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("expected the pinned post left out, got %+v", posts)
+	}
+	if posts[0].URL != "https://go.dev/blog/go1.26" || posts[0].Permalink != "https://www.reddit.com/r/golang/comments/a1/go_126/" || !posts[0].Created.Equal(time.Unix(1773162000, 0)) {
+		t.Errorf("unexpected link post %+v", posts[0])
+	}
+	if len(posts[0].Comments) != 1 || posts[0].Comments[0].Body != "Finally, iterators!" {
+		t.Errorf("unexpected comments %+v", posts[0].Comments)
+	}
+	if posts[1].Title != "Ask: generics & iterators" || posts[1].Text != "How do they mix?" || posts[1].URL != posts[1].Permalink {
+		t.Errorf("unexpected self post %+v", posts[1])
+	}
+	if paths[0] != "/r/golang/top.json" || len(paths) != 2 {
+		t.Errorf("expected one comments request after the listing, got %v", paths)
+	}
+
+	item := posts[0].Item()
+	if item.Feed != "r/golang" || !strings.Contains(item.Description, "900 points, 2 comments") || !strings.Contains(item.Description, "Top comment (120 points): Finally, iterators!") {
+		t.Errorf("unexpected item %+v", item)
+	}
+
+	// With credentials, requests go to the OAuth API with a bearer token.
+	paths, auth = nil, nil
+	items := NewReddit("id", "secret").FetchSubreddits(ctx, []string{"golang"}, "week", 5, -1, time.Unix(1773160000, 0))
+	if len(items) != 1 || items[0].Title != "Go 1.26 released" {
+		t.Errorf("expected only the newer post, got %+v", items)
+	}
+	if len(paths) != 2 || paths[1] != "/oauth/r/golang/top" || auth[1] != "Bearer tok" {
+		t.Errorf("unexpected requests %v %v", paths, auth)
+	}
+}
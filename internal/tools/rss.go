@@ -0,0 +1,305 @@
+package tools
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxFeedBytes caps how much of a feed response is read.
+	maxFeedBytes = 5 << 20
+
+	feedTimeout = 20 * time.Second
+)
+
+// RSSItem is a single entry from an RSS 2.0 or Atom feed.
+type RSSItem struct {
+	Title       string
+	Link        string
+	Description string
+	Published   time.Time // zero if the feed did not provide a date
+	Feed        string    // title of the feed the item came from
+	FeedURL     string    // URL of that feed, if read from one
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			GUID        string `xml:"guid"`
+			Description string `xml:"description"`
+			PubDate     string `xml:"pubDate"`
+			Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomDocument struct {
+	Title   string `xml:"title"`
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// FetchRSS downloads and parses an RSS 2.0 or Atom feed.
+func FetchRSS(ctx context.Context, feedURL string) ([]RSSItem, error) {
+	res, err := FetchFeed(ctx, feedURL, "", "")
+	return res.Items, err
+}
+
+// FeedResult is a fetched feed.
+type FeedResult struct {
+	Title string
+	Items []RSSItem
+	// ETag and LastModified are the validators to send with the next fetch.
+	ETag         string
+	LastModified string
+	// NotModified reports that the feed is unchanged since the fetch the
+	// validators came from; Items is then empty.
+	NotModified bool
+}
+
+// FetchFeed downloads and parses an RSS 2.0 or Atom feed, as a conditional
+// request when given the validators of an earlier fetch.
+func FetchFeed(ctx context.Context, feedURL, etag, lastModified string) (FeedResult, error) {
+	if err := ValidateURL(ctx, feedURL); err != nil {
+		return FeedResult{}, fmt.Errorf("invalid feed URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return FeedResult{}, fmt.Errorf("failed to create feed request: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	req.Header.Set("User-Agent", userAgent)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := newWebClient(feedTimeout).Do(req)
+	if err != nil {
+		return FeedResult{}, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return FeedResult{ETag: etag, LastModified: lastModified, NotModified: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return FeedResult{}, fmt.Errorf("feed returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return FeedResult{}, fmt.Errorf("failed to read feed: %w", err)
+	}
+	title, items, err := parseFeed(body, feedURL)
+	if err != nil {
+		return FeedResult{}, err
+	}
+	return FeedResult{Title: title, Items: items, ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}, nil
+}
+
+// parseFeed detects the feed flavour from the root element and parses it,
+// returning the feed's title and items.
+func parseFeed(body []byte, feedURL string) (string, []RSSItem, error) {
+	var root struct{ XMLName xml.Name }
+	if err := xml.Unmarshal(body, &root); err != nil {
+		return "", nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	var title string
+	var items []RSSItem
+	switch root.XMLName.Local {
+	case "rss", "RDF":
+		var doc rssDocument
+		if err := xml.Unmarshal(body, &doc); err != nil {
+			return "", nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+		}
+		title = strings.TrimSpace(doc.Channel.Title)
+		for _, it := range doc.Channel.Items {
+			link := strings.TrimSpace(it.Link)
+			if link == "" && strings.HasPrefix(it.GUID, "http") {
+				link = strings.TrimSpace(it.GUID)
+			}
+			date := it.PubDate
+			if date == "" {
+				date = it.Date
+			}
+			items = append(items, RSSItem{
+				Title:       strings.TrimSpace(it.Title),
+				Link:        resolveLink(feedURL, link),
+				Description: strings.TrimSpace(it.Description),
+				Published:   parseFeedDate(date),
+				Feed:        title,
+				FeedURL:     feedURL,
+			})
+		}
+	case "feed":
+		var doc atomDocument
+		if err := xml.Unmarshal(body, &doc); err != nil {
+			return "", nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+		}
+		title = strings.TrimSpace(doc.Title)
+		for _, e := range doc.Entries {
+			var link string
+			for _, l := range e.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = l.Href
+					break
+				}
+			}
+			date := e.Published
+			if date == "" {
+				date = e.Updated
+			}
+			desc := e.Summary
+			if desc == "" {
+				desc = e.Content
+			}
+			items = append(items, RSSItem{
+				Title:       strings.TrimSpace(e.Title),
+				Link:        resolveLink(feedURL, strings.TrimSpace(link)),
+				Description: strings.TrimSpace(desc),
+				Published:   parseFeedDate(date),
+				Feed:        title,
+				FeedURL:     feedURL,
+			})
+		}
+	default:
+		return "", nil, fmt.Errorf("unsupported feed format %q", root.XMLName.Local)
+	}
+	return title, items, nil
+}
+
+func resolveLink(base, link string) string {
+	if link == "" {
+		return ""
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return link
+	}
+	u, err := b.Parse(link)
+	if err != nil {
+		return link
+	}
+	return u.String()
+}
+
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseFeedDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// FetchFeeds fetches all feeds and returns their items published after since
+// (items without a date are kept), deduplicated and newest first. Feeds that
+// fail are logged and skipped.
+func FetchFeeds(ctx context.Context, feedURLs []string, since time.Time) []RSSItem {
+	var all []RSSItem
+	for _, u := range feedURLs {
+		items, err := FetchRSS(ctx, u)
+		if err != nil {
+			slog.Warn("Failed to fetch feed", "url", u, "error", err)
+			continue
+		}
+		for _, it := range items {
+			if it.Published.IsZero() || it.Published.After(since) {
+				all = append(all, it)
+			}
+		}
+	}
+	return MergeFeedItems(all)
+}
+
+// MergeFeedItems deduplicates the items of several sources, such as feeds
+// and subreddits, and sorts them newest first.
+func MergeFeedItems(items ...[]RSSItem) []RSSItem {
+	var all []RSSItem
+	for _, list := range items {
+		all = append(all, list...)
+	}
+	all = deduplicateRSSItems(all)
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Published.After(all[j].Published) })
+	return all
+}
+
+// deduplicateRSSItems drops items that share a normalized link or, for
+// link-less items, a title. The first occurrence wins.
+func deduplicateRSSItems(items []RSSItem) []RSSItem {
+	seen := make(map[string]bool, len(items))
+	out := items[:0]
+	for _, it := range items {
+		key := normalizeItemLink(it.Link)
+		if key == "" {
+			key = "title:" + strings.ToLower(strings.Join(strings.Fields(it.Title), " "))
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, it)
+	}
+	return out
+}
+
+// normalizeItemLink lowercases the host, drops the fragment, tracking
+// parameters and trailing slash so the same article from two feeds matches.
+func normalizeItemLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(link)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+	u.Host = strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	u.Fragment = ""
+	q := u.Query()
+	for k := range q {
+		if strings.HasPrefix(k, "utm_") {
+			q.Del(k)
+		}
+	}
+	u.RawQuery = q.Encode()
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testRSS = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Go Blog</title>
+<item><title>Go 1.26 released</title><link>https://go.dev/blog/go1.26?utm_source=rss</link><pubDate>Tue, 10 Mar 2026 17:00:00 +0000</pubDate><description>&lt;p&gt;Big release&lt;/p&gt;</description></item>
+<item><title>Old post</title><link>https://go.dev/blog/old</link><pubDate>Mon, 02 Jan 2006 15:04:05 +0000</pubDate></item>
+</channel></rss>`
+
+const testAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>Mirror</title>
+<entry><title>Go 1.26 released (mirror)</title><link rel="alternate" href="http://www.go.dev/blog/go1.26/"/><updated>2026-03-10T18:00:00Z</updated><summary>Same article</summary></entry>
+<entry><title>Relative link</title><link href="/posts/1"/><published>2026-03-11T08:00:00Z</published></entry>
+</feed>`
+
+func TestParseFeed(t *testing.T) {
+	_, items, err := parseFeed([]byte(testRSS), "https://go.dev/blog/feed.xml")
+	if err != nil {
+		t.Fatalf("parseFeed RSS failed: %v", err)
+	}
+	if len(items) != 2 || items[0].Feed != "Go Blog" || items[0].Published.Day() != 10 {
+		t.Errorf("unexpected RSS items: %+v", items)
+	}
+
+	_, items, err = parseFeed([]byte(testAtom), "https://mirror.example/atom.xml")
+	if err != nil {
+		t.Fatalf("parseFeed Atom failed: %v", err)
+	}
+	if len(items) != 2 || items[1].Link != "https://mirror.example/posts/1" {
+		t.Errorf("unexpected Atom items: %+v", items)
+	}
+
+	if _, _, err := parseFeed([]byte(`<html></html>`), "https://x"); err == nil {
+		t.Error("expected error for non-feed document")
+	}
+}
+
+func TestDeduplicateRSSItems(t *testing.T) {
+	items := deduplicateRSSItems([]RSSItem{
+		{Title: "A", Link: "https://go.dev/blog/go1.26?utm_source=rss"},
+		{Title: "A mirror", Link: "http://www.go.dev/blog/go1.26/"},
+		{Title: "No  link"},
+		{Title: "no link"},
+		{Title: "B", Link: "https://go.dev/blog/b"},
+	})
+	if len(items) != 3 {
+		t.Fatalf("expected 3 unique items, got %+v", items)
+	}
+	if items[0].Title != "A" {
+		t.Errorf("expected first occurrence to win, got %q", items[0].Title)
+	}
+}
+
+func TestFetchFeeds(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rss", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(testRSS)) })
+	mux.HandleFunc("/atom", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(testAtom)) })
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) })
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	items := FetchFeeds(context.Background(), []string{ts.URL + "/rss", ts.URL + "/atom", ts.URL + "/broken"}, since)
+
+	// The old post is filtered by date and the mirror is a duplicate.
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", items)
+	}
+	if items[0].Title != "Relative link" || items[1].Title != "Go 1.26 released" {
+		t.Errorf("expected newest first, got %q, %q", items[0].Title, items[1].Title)
+	}
+}
+
+func TestFetchFeedConditional(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Tue, 10 Mar 2026 07:00:00 GMT")
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	res, err := FetchFeed(ctx, ts.URL, "", "")
+	if err != nil {
+		t.Fatalf("FetchFeed failed: %v", err)
+	}
+	if res.NotModified || res.Title != "Go Blog" || len(res.Items) == 0 || res.ETag != `"v1"` || res.LastModified == "" {
+		t.Errorf("unexpected result %+v", res)
+	}
+	res, err = FetchFeed(ctx, ts.URL, res.ETag, res.LastModified)
+	if err != nil || !res.NotModified || len(res.Items) != 0 || res.ETag != `"v1"` {
+		t.Errorf("expected not modified, got %+v, %v", res, err)
+	}
+}
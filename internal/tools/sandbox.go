@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultSandboxPythonImage = "python:3.13-alpine"
+	DefaultSandboxGoImage     = "golang:1.25-alpine"
+	defaultSandboxMemoryMB    = 256
+	defaultSandboxCPUs        = 1
+	defaultSandboxTimeout     = 30 * time.Second
+	// maxSnippetBytes caps the code run, and maxRunOutput the output kept
+	// from each of stdout and stderr.
+	maxSnippetBytes = 64 << 10
+	maxRunOutput    = 16 << 10
+)
+
+// Sandbox runs code snippets in throwaway Docker containers: no network,
+// a read-only root with a small /tmp, no capabilities, an unprivileged
+// user, and limits on memory, CPU, processes and time. Zero fields take
+// the defaults.
+type Sandbox struct {
+	Docker      string
+	PythonImage string
+	GoImage     string
+	MemoryMB    int
+	CPUs        float64
+	Timeout     time.Duration
+}
+
+// RunResult is the outcome of a snippet.
+type RunResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	TimedOut bool
+	Duration time.Duration
+}
+
+// runContainer runs the Docker CLI with stdin; replaced in tests.
+var runContainer = func(ctx context.Context, name string, args []string, stdin string) (stdout, stderr []byte, exitCode int, err error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var out, errOut limitedBuffer
+	cmd.Stdout, cmd.Stderr = &out, &errOut
+	cmd.WaitDelay = 5 * time.Second
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return out.Bytes(), errOut.Bytes(), exitErr.ExitCode(), nil
+	}
+	return out.Bytes(), errOut.Bytes(), 0, err
+}
+
+// Run runs code, in Python or Go, and returns its output. Errors are for
+// failing to run it at all; a snippet that fails has a non-zero ExitCode.
+func (s Sandbox) Run(ctx context.Context, language, code string) (RunResult, error) {
+	if strings.TrimSpace(code) == "" {
+		return RunResult{}, fmt.Errorf("code is required")
+	}
+	if len(code) > maxSnippetBytes {
+		return RunResult{}, fmt.Errorf("code is longer than %d KB", maxSnippetBytes>>10)
+	}
+	name := "ravenbot-run-" + uuid.NewString()[:8]
+	args, err := s.args(name, language)
+	if err != nil {
+		return RunResult{}, err
+	}
+	docker := s.Docker
+	if docker == "" {
+		docker = "docker"
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultSandboxTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	stdout, stderr, exit, err := runContainer(runCtx, docker, args, code)
+	res := RunResult{Stdout: string(stdout), Stderr: string(stderr), ExitCode: exit, Duration: time.Since(start)}
+	if runCtx.Err() != nil {
+		// Killing the CLI leaves the container running.
+		rmCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, out, _, err := runContainer(rmCtx, docker, []string{"rm", "-f", name}, ""); err != nil {
+			slog.Warn("Sandbox: failed to remove container", "name", name, "error", err, "output", string(out))
+		}
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+		res.TimedOut = true
+		return res, nil
+	}
+	if err != nil {
+		return res, fmt.Errorf("failed to run %s: %w", docker, err)
+	}
+	// Docker itself exits 125 when it can't start the container.
+	if res.ExitCode == 125 {
+		return res, fmt.Errorf("failed to start the sandbox: %s", strings.TrimSpace(res.Stderr))
+	}
+	return res, nil
+}
+
+// args returns the docker run arguments for a snippet in language.
+func (s Sandbox) args(name, language string) ([]string, error) {
+	var image string
+	var command []string
+	tmp := "64m"
+	switch strings.ToLower(language) {
+	case "python", "python3", "py":
+		image = s.PythonImage
+		if image == "" {
+			image = DefaultSandboxPythonImage
+		}
+		command = []string{"python3", "-"}
+	case "go", "golang":
+		image = s.GoImage
+		if image == "" {
+			image = DefaultSandboxGoImage
+		}
+		// The build cache needs room, and the toolchain must not download.
+		tmp = "512m"
+		command = []string{"sh", "-c", "cat > /tmp/main.go && cd /tmp && GOCACHE=/tmp/cache GOPATH=/tmp/go GOTOOLCHAIN=local go run main.go"}
+	default:
+		return nil, fmt.Errorf("unsupported language %q: must be python or go", language)
+	}
+	memory := s.MemoryMB
+	if memory <= 0 {
+		memory = defaultSandboxMemoryMB
+	}
+	cpus := s.CPUs
+	if cpus <= 0 {
+		cpus = defaultSandboxCPUs
+	}
+	args := []string{
+		"run", "--rm", "-i", "--name", name,
+		"--network", "none",
+		"--memory", fmt.Sprintf("%dm", memory), "--memory-swap", fmt.Sprintf("%dm", memory),
+		"--cpus", strconv.FormatFloat(cpus, 'f', -1, 64),
+		"--pids-limit", "64",
+		"--read-only", "--tmpfs", "/tmp:rw,exec,size=" + tmp,
+		"--cap-drop", "ALL", "--security-opt", "no-new-privileges",
+		"--user", "65534:65534", "-e", "HOME=/tmp", "-w", "/tmp",
+		image,
+	}
+	return append(args, command...), nil
+}
+
+// limitedBuffer keeps the first maxRunOutput bytes written to it.
+type limitedBuffer struct {
+	bytes.Buffer
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := maxRunOutput - b.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	if b.truncated {
+		return append(b.Buffer.Bytes(), "\n[… output truncated]"...)
+	}
+	return b.Buffer.Bytes()
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSandboxRun(t *testing.T) {
+	var calls [][]string
+	var stdin string
+	oldRun := runContainer
+	defer func() { runContainer = oldRun }()
+	runContainer = func(ctx context.Context, name string, args []string, in string) ([]byte, []byte, int, error) {
+		calls = append(calls, append([]string{name}, args...))
+		if args[0] == "rm" {
+			return nil, nil, 0, nil
+		}
+		stdin = in
+		if strings.Contains(in, "sleep") {
+			<-ctx.Done()
+			return nil, nil, -1, ctx.Err()
+		}
+		return []byte("4\n"), nil, 0, nil
+	}
+	ctx := context.Background()
+
+	s := Sandbox{MemoryMB: 128, CPUs: 0.5}
+	res, err := s.Run(ctx, "python", "print(2+2)")
+	if err != nil || res.Stdout != "4\n" || res.ExitCode != 0 || res.TimedOut {
+		t.Fatalf("Run = %+v, %v", res, err)
+	}
+	args := calls[0]
+	if args[0] != "docker" || stdin != "print(2+2)" || args[len(args)-3] != DefaultSandboxPythonImage {
+		t.Errorf("unexpected command %v", args)
+	}
+	for _, want := range [][]string{{"--network", "none"}, {"--memory", "128m"}, {"--cpus", "0.5"}, {"--cap-drop", "ALL"}} {
+		if i := slices.Index(args, want[0]); i < 0 || args[i+1] != want[1] {
+			t.Errorf("expected %s %s in %v", want[0], want[1], args)
+		}
+	}
+	if !slices.Contains(args, "--read-only") || !slices.Contains(args, "--rm") {
+		t.Errorf("expected a read-only, removed container: %v", args)
+	}
+
+	calls = nil
+	s.Timeout = 10 * time.Millisecond
+	res, err = s.Run(ctx, "go", "package main\nfunc main() { sleep() }")
+	if err != nil || !res.TimedOut {
+		t.Fatalf("expected a timeout, got %+v, %v", res, err)
+	}
+	name := calls[0][slices.Index(calls[0], "--name")+1]
+	if len(calls) != 2 || !slices.Equal(calls[1], []string{"docker", "rm", "-f", name}) {
+		t.Errorf("expected the container to be removed: %v", calls)
+	}
+
+	if _, err := s.Run(ctx, "ruby", "puts 1"); err == nil {
+		t.Error("expected error for an unsupported language")
+	}
+	if _, err := s.Run(ctx, "python", " "); err == nil {
+		t.Error("expected error for empty code")
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	var b limitedBuffer
+	_, _ = b.Write([]byte(strings.Repeat("x", maxRunOutput-1)))
+	_, _ = b.Write([]byte("yz"))
+	if got := string(b.Bytes()); !strings.HasSuffix(got, "xy\n[… output truncated]") {
+		t.Errorf("unexpected output tail %q", got[len(got)-30:])
+	}
+}
@@ -0,0 +1,325 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	searchTimeout = 20 * time.Second
+	// maxSearchBytes caps the search API responses read.
+	maxSearchBytes = 2 << 20
+	// DefaultSearchResults is how many results a provider returns unless
+	// configured otherwise.
+	DefaultSearchResults = 8
+	// searchCooldown is how long a provider that failed is passed over
+	// while others are left to try.
+	searchCooldown = 2 * time.Minute
+)
+
+// SearchProvider looks a query up on the web, returning Markdown for the
+// model: a list of results with their links, or a grounded answer.
+type SearchProvider interface {
+	Name() string
+	Search(ctx context.Context, query string) (string, error)
+}
+
+// SearchResult is one web page returned by a search API.
+type SearchResult struct {
+	Title   string
+	URL     string
+	Snippet string
+}
+
+// formatResults lists results as Markdown links with their snippets.
+func formatResults(provider, query string, results []SearchResult) (string, error) {
+	if len(results) == 0 {
+		return "", fmt.Errorf("%s found no results for %q", provider, query)
+	}
+	var sb strings.Builder
+	for i, r := range results {
+		sb.WriteString(fmt.Sprintf("%d. [%s](%s)\n", i+1, strings.TrimSpace(r.Title), r.URL))
+		if s := strings.Join(strings.Fields(r.Snippet), " "); s != "" {
+			sb.WriteString("   " + s + "\n")
+		}
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// GeminiSearch is a grounded Google Search through the Gemini API; see
+// WebSearch.
+type GeminiSearch struct {
+	APIKey string
+	Model  string
+}
+
+func (GeminiSearch) Name() string { return "gemini" }
+
+func (g GeminiSearch) Search(ctx context.Context, query string) (string, error) {
+	return WebSearch(ctx, g.APIKey, g.Model, query)
+}
+
+// SearxNG searches through a SearxNG instance's JSON API (its settings
+// must enable the json format).
+type SearxNG struct {
+	URL        string // base URL of the instance, e.g. http://searxng:8080
+	MaxResults int
+}
+
+func (SearxNG) Name() string { return "searxng" }
+
+func (s SearxNG) Search(ctx context.Context, query string) (string, error) {
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	q := url.Values{"q": {query}, "format": {"json"}}
+	if err := getSearchJSON(ctx, s.Name(), strings.TrimSuffix(s.URL, "/")+"/search?"+q.Encode(), nil, &resp); err != nil {
+		return "", err
+	}
+	var results []SearchResult
+	for _, r := range resp.Results {
+		results = append(results, SearchResult{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return formatResults(s.Name(), query, limitResults(results, s.MaxResults))
+}
+
+// BraveSearch uses the Brave Search API.
+type BraveSearch struct {
+	APIKey     string
+	URL        string // defaults to the public API
+	MaxResults int
+}
+
+func (BraveSearch) Name() string { return "brave" }
+
+func (b BraveSearch) Search(ctx context.Context, query string) (string, error) {
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	q := url.Values{"q": {query}, "count": {strconv.Itoa(resultCount(b.MaxResults, 20))}}
+	endpoint := orDefault(b.URL, "https://api.search.brave.com/res/v1/web/search")
+	if err := getSearchJSON(ctx, b.Name(), endpoint+"?"+q.Encode(), http.Header{"X-Subscription-Token": {b.APIKey}}, &resp); err != nil {
+		return "", err
+	}
+	var results []SearchResult
+	for _, r := range resp.Web.Results {
+		results = append(results, SearchResult{Title: r.Title, URL: r.URL, Snippet: stripTags(r.Description)})
+	}
+	return formatResults(b.Name(), query, results)
+}
+
+// GoogleCSE uses the Google Custom Search JSON API with a Programmable
+// Search Engine.
+type GoogleCSE struct {
+	APIKey     string
+	CX         string // the search engine ID
+	URL        string // defaults to the public API
+	MaxResults int
+}
+
+func (GoogleCSE) Name() string { return "google" }
+
+func (g GoogleCSE) Search(ctx context.Context, query string) (string, error) {
+	var resp struct {
+		Items []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+		} `json:"items"`
+	}
+	q := url.Values{"key": {g.APIKey}, "cx": {g.CX}, "q": {query}, "num": {strconv.Itoa(resultCount(g.MaxResults, 10))}}
+	endpoint := orDefault(g.URL, "https://www.googleapis.com/customsearch/v1")
+	if err := getSearchJSON(ctx, g.Name(), endpoint+"?"+q.Encode(), nil, &resp); err != nil {
+		return "", err
+	}
+	var results []SearchResult
+	for _, r := range resp.Items {
+		results = append(results, SearchResult{Title: r.Title, URL: r.Link, Snippet: r.Snippet})
+	}
+	return formatResults(g.Name(), query, results)
+}
+
+// BingSearch uses the Bing Web Search API.
+type BingSearch struct {
+	APIKey     string
+	URL        string // defaults to the public API
+	MaxResults int
+}
+
+func (BingSearch) Name() string { return "bing" }
+
+func (b BingSearch) Search(ctx context.Context, query string) (string, error) {
+	var resp struct {
+		WebPages struct {
+			Value []struct {
+				Name    string `json:"name"`
+				URL     string `json:"url"`
+				Snippet string `json:"snippet"`
+			} `json:"value"`
+		} `json:"webPages"`
+	}
+	q := url.Values{"q": {query}, "count": {strconv.Itoa(resultCount(b.MaxResults, 50))}}
+	endpoint := orDefault(b.URL, "https://api.bing.microsoft.com/v7.0/search")
+	if err := getSearchJSON(ctx, b.Name(), endpoint+"?"+q.Encode(), http.Header{"Ocp-Apim-Subscription-Key": {b.APIKey}}, &resp); err != nil {
+		return "", err
+	}
+	var results []SearchResult
+	for _, r := range resp.WebPages.Value {
+		results = append(results, SearchResult{Title: r.Name, URL: r.URL, Snippet: r.Snippet})
+	}
+	return formatResults(b.Name(), query, results)
+}
+
+// getSearchJSON fetches a search API response into v.
+func getSearchJSON(ctx context.Context, provider, endpoint string, header http.Header, v any) error {
+	if err := ValidateURL(ctx, endpoint); err != nil {
+		return fmt.Errorf("invalid %s URL: %w", provider, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", provider, err)
+	}
+	for k, vals := range header {
+		req.Header[k] = vals
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := NewSafeClient(searchTimeout).Do(req)
+	if err != nil {
+		return fmt.Errorf("%s search failed: %w", provider, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s search returned status %d", provider, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSearchBytes)).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", provider, err)
+	}
+	return nil
+}
+
+// resultCount is the number of results to ask for: n, or the default,
+// capped at the API's limit.
+func resultCount(n, limit int) int {
+	if n <= 0 {
+		n = DefaultSearchResults
+	}
+	return min(n, limit)
+}
+
+func limitResults(results []SearchResult, n int) []SearchResult {
+	return results[:min(len(results), resultCount(n, len(results)))]
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// stripTags removes the <strong> highlighting Brave puts in snippets.
+func stripTags(s string) string {
+	var sb strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// FallbackSearch tries its providers in order until one returns results.
+// A provider that fails is passed over for a while (rate limits usually
+// last a few minutes), unless every other one has failed too.
+type FallbackSearch struct {
+	providers []SearchProvider
+
+	mu          sync.Mutex
+	failedUntil map[int]time.Time // by index in providers
+	now         func() time.Time
+}
+
+// NewFallbackSearch returns a search over providers, in order of
+// preference.
+func NewFallbackSearch(providers ...SearchProvider) *FallbackSearch {
+	return &FallbackSearch{providers: providers, failedUntil: make(map[int]time.Time), now: time.Now}
+}
+
+func (f *FallbackSearch) Name() string {
+	names := make([]string, 0, len(f.providers))
+	for _, p := range f.providers {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+func (f *FallbackSearch) Search(ctx context.Context, query string) (string, error) {
+	if len(f.providers) == 0 {
+		return "", fmt.Errorf("no web search provider is configured")
+	}
+	var errs []error
+	for _, i := range f.order() {
+		p := f.providers[i]
+		text, err := p.Search(ctx, query)
+		if err == nil {
+			f.mu.Lock()
+			delete(f.failedUntil, i)
+			f.mu.Unlock()
+			return text, nil
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		slog.Warn("Web search provider failed, trying the next", "provider", p.Name(), "error", err)
+		f.mu.Lock()
+		f.failedUntil[i] = f.now().Add(searchCooldown)
+		f.mu.Unlock()
+		errs = append(errs, err)
+	}
+	return "", fmt.Errorf("web search failed: %w", errors.Join(errs...))
+}
+
+// order returns the indexes of the providers to try: the ones that have
+// not failed recently, then the others.
+func (f *FallbackSearch) order() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	var ready, cooling []int
+	for i := range f.providers {
+		if until, ok := f.failedUntil[i]; ok && now.Before(until) {
+			cooling = append(cooling, i)
+		} else {
+			ready = append(ready, i)
+		}
+	}
+	return append(ready, cooling...)
+}
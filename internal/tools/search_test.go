@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchProviders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "go release" {
+			t.Errorf("%s: unexpected query %q", r.URL.Path, q.Get("q"))
+		}
+		switch r.URL.Path {
+		case "/search":
+			if q.Get("format") != "json" {
+				t.Errorf("searxng: unexpected format %q", q.Get("format"))
+			}
+			_, _ = w.Write([]byte(`{"results":[{"title":"Go 1.26","url":"https://go.dev/doc/go1.26","content":"Release\n  notes"},{"title":"Extra","url":"https://go.dev/x"}]}`))
+		case "/brave":
+			if r.Header.Get("X-Subscription-Token") != "brave-key" {
+				t.Errorf("brave: missing key")
+			}
+			_, _ = w.Write([]byte(`{"web":{"results":[{"title":"Go 1.26","url":"https://go.dev/doc/go1.26","description":"<strong>Release</strong> notes"}]}}`))
+		case "/google":
+			if q.Get("key") != "google-key" || q.Get("cx") != "engine" || q.Get("num") != "8" {
+				t.Errorf("google: unexpected query %v", q)
+			}
+			_, _ = w.Write([]byte(`{"items":[{"title":"Go 1.26","link":"https://go.dev/doc/go1.26","snippet":"Release notes"}]}`))
+		case "/bing":
+			if r.Header.Get("Ocp-Apim-Subscription-Key") != "bing-key" {
+				t.Errorf("bing: missing key")
+			}
+			_, _ = w.Write([]byte(`{"webPages":{"value":[{"name":"Go 1.26","url":"https://go.dev/doc/go1.26","snippet":"Release notes"}]}}`))
+		case "/empty":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	want := "1. [Go 1.26](https://go.dev/doc/go1.26)\n   Release notes"
+	for _, p := range []SearchProvider{
+		SearxNG{URL: srv.URL + "/", MaxResults: 1},
+		BraveSearch{APIKey: "brave-key", URL: srv.URL + "/brave"},
+		GoogleCSE{APIKey: "google-key", CX: "engine", URL: srv.URL + "/google"},
+		BingSearch{APIKey: "bing-key", URL: srv.URL + "/bing"},
+	} {
+		got, err := p.Search(context.Background(), "go release")
+		if err != nil {
+			t.Errorf("%s: %v", p.Name(), err)
+		} else if got != want {
+			t.Errorf("%s: got %q, want %q", p.Name(), got, want)
+		}
+	}
+
+	if _, err := (BraveSearch{URL: srv.URL + "/missing"}).Search(context.Background(), "go release"); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("expected status error, got %v", err)
+	}
+	if _, err := (GoogleCSE{URL: srv.URL + "/empty"}).Search(context.Background(), "go release"); err == nil || !strings.Contains(err.Error(), "no results") {
+		t.Errorf("expected no results error, got %v", err)
+	}
+}
+
+type stubSearch struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (s *stubSearch) Name() string { return s.name }
+
+func (s *stubSearch) Search(context.Context, string) (string, error) {
+	s.calls++
+	if s.err != nil {
+		return "", s.err
+	}
+	return s.name + " results", nil
+}
+
+func TestFallbackSearch(t *testing.T) {
+	primary := &stubSearch{name: "brave", err: errors.New("status 429")}
+	backup := &stubSearch{name: "searxng"}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	f := NewFallbackSearch(primary, backup)
+	f.now = func() time.Time { return now }
+
+	got, err := f.Search(context.Background(), "q")
+	if err != nil || got != "searxng results" {
+		t.Fatalf("expected fallback results, got %q, %v", got, err)
+	}
+
+	// The failed provider is passed over while it cools down.
+	if _, err := f.Search(context.Background(), "q"); err != nil || primary.calls != 1 {
+		t.Errorf("expected brave to be skipped, calls = %d, err = %v", primary.calls, err)
+	}
+
+	// Then it is tried first again.
+	primary.err = nil
+	now = now.Add(searchCooldown)
+	if got, _ := f.Search(context.Background(), "q"); got != "brave results" {
+		t.Errorf("expected brave again after the cooldown, got %q", got)
+	}
+
+	backup.err = errors.New("connection refused")
+	primary.err = errors.New("status 429")
+	if _, err := f.Search(context.Background(), "q"); err == nil || !strings.Contains(err.Error(), "status 429") || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected both errors, got %v", err)
+	}
+	if f.Name() != "brave, searxng" {
+		t.Errorf("unexpected name %q", f.Name())
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -133,9 +135,75 @@ func normalizePort(port string) string {
 	return strconv.Itoa(p)
 }
 
+// HostAccess lists hosts exempt from the SSRF checks (Allow), such as a
+// Grafana on the LAN, and hosts never fetched (Deny). Entries are host
+// names or IP addresses; "*.example.com" matches the subdomains of
+// example.com but not example.com itself. Deny wins over Allow.
+type HostAccess struct {
+	Allow []string
+	Deny  []string
+}
+
+// hostAccess is the HostAccess of ValidateURL and NewSafeClient.
+var hostAccess atomic.Pointer[HostAccess]
+
+// SetHostAccess changes the hosts ValidateURL and NewSafeClient allow
+// despite their restricted address or port, and those they refuse.
+func SetHostAccess(a HostAccess) {
+	norm := func(list []string) []string {
+		out := make([]string, 0, len(list))
+		for _, h := range list {
+			if h = normalizeHost(h); h != "" {
+				out = append(out, h)
+			}
+		}
+		return out
+	}
+	hostAccess.Store(&HostAccess{Allow: norm(a.Allow), Deny: norm(a.Deny)})
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	return strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+}
+
+// checkHost reports whether host is allowed despite the SSRF checks, or
+// an error if it is denied.
+func checkHost(host string) (allowed bool, err error) {
+	a := hostAccess.Load()
+	if a == nil {
+		return false, nil
+	}
+	host = normalizeHost(host)
+	if matchHost(a.Deny, host) {
+		return false, fmt.Errorf("denied host: %s", host)
+	}
+	return matchHost(a.Allow, host), nil
+}
+
+// matchHost reports whether host matches one of the patterns.
+func matchHost(patterns []string, host string) bool {
+	for _, p := range patterns {
+		if domain, ok := strings.CutPrefix(p, "*."); ok {
+			if strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		} else if host == p {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateURL checks if a URL is safe for fetching by blocking restricted IP ranges and ports.
+// Hosts set with SetHostAccess are allowed or refused regardless.
 func ValidateURL(ctx context.Context, urlStr string) error {
 	if os.Getenv("ALLOW_LOCAL_URLS") == "true" {
+		if u, err := url.Parse(urlStr); err == nil {
+			if _, err := checkHost(u.Hostname()); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 	u, err := url.Parse(urlStr)
@@ -147,6 +215,9 @@ func ValidateURL(ctx context.Context, urlStr string) error {
 	if host == "" {
 		return fmt.Errorf("empty host in URL")
 	}
+	if allowed, err := checkHost(host); err != nil || allowed {
+		return err
+	}
 
 	// Port-based SSRF protection
 	if port := u.Port(); port != "" {
@@ -185,7 +256,11 @@ func NewSafeClient(timeout time.Duration) *http.Client {
 			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
 				host, port, _ := net.SplitHostPort(addr)
 
-				allowLocal := os.Getenv("ALLOW_LOCAL_URLS") == "true"
+				allowed, err := checkHost(host)
+				if err != nil {
+					return nil, err
+				}
+				allowLocal := allowed || os.Getenv("ALLOW_LOCAL_URLS") == "true"
 
 				// Defense-in-depth: check port against blacklist even in DialContext
 				if blockedPorts[normalizePort(port)] && !allowLocal {
@@ -150,11 +150,12 @@ func TestValidateURL(t *testing.T) {
 		{"Allow local URLs", "http://127.0.0.1", true, false},
 	}
 
+	// Later tests rely on the value TestMain set.
+	defer func() { _ = os.Setenv("ALLOW_LOCAL_URLS", "true") }()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.allowLocal {
 				_ = os.Setenv("ALLOW_LOCAL_URLS", "true")
-				defer func() { _ = os.Unsetenv("ALLOW_LOCAL_URLS") }()
 			} else {
 				_ = os.Unsetenv("ALLOW_LOCAL_URLS")
 			}
@@ -166,3 +167,61 @@ func TestValidateURL(t *testing.T) {
 		})
 	}
 }
+
+func TestHostAccess(t *testing.T) {
+	SetHostAccess(HostAccess{Allow: []string{"127.0.0.1", "*.Grafana.Test."}, Deny: []string{"*.blocked.test", "[::1]"}})
+	defer SetHostAccess(HostAccess{})
+
+	for host, want := range map[string]bool{
+		"grafana.test":         false,
+		"dash.grafana.test":    true,
+		"a.b.grafana.test":     true,
+		"evilgrafana.test":     false,
+		"127.0.0.1":            true,
+		"127.0.0.2":            false,
+		"ads.blocked.test":     false,
+		"blocked.test.example": false,
+	} {
+		if got, _ := checkHost(host); got != want {
+			t.Errorf("checkHost(%q) allowed = %v, want %v", host, got, want)
+		}
+	}
+	if _, err := checkHost("ADS.Blocked.Test."); err == nil {
+		t.Error("expected a denied subdomain to be refused")
+	}
+
+	// Denied hosts are refused even where local URLs are allowed.
+	if err := ValidateURL(context.Background(), "http://ads.blocked.test/x"); err == nil || !strings.Contains(err.Error(), "denied host") {
+		t.Errorf("expected the denied host to be refused, got %v", err)
+	}
+	if err := ValidateURL(context.Background(), "http://blocked.test/x"); err != nil {
+		t.Errorf("expected the parent domain to stay allowed, got %v", err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	_ = os.Setenv("ALLOW_LOCAL_URLS", "false")
+	defer func() { _ = os.Setenv("ALLOW_LOCAL_URLS", "true") }()
+
+	// An allowed host skips the restricted address and port checks.
+	if err := ValidateURL(context.Background(), "http://127.0.0.1:3000/d/cpu"); err != nil {
+		t.Errorf("expected the allowed host to pass, got %v", err)
+	}
+	if err := ValidateURL(context.Background(), "http://10.0.0.1/"); err == nil {
+		t.Error("expected other private addresses to stay blocked")
+	}
+	resp, err := NewSafeClient(5 * time.Second).Get(ts.URL)
+	if err != nil {
+		t.Fatalf("expected the allowed host to be reachable, got %v", err)
+	}
+	_ = resp.Body.Close()
+
+	SetHostAccess(HostAccess{Deny: []string{"127.0.0.1"}})
+	_ = os.Setenv("ALLOW_LOCAL_URLS", "true")
+	if _, err := NewSafeClient(5 * time.Second).Get(ts.URL); err == nil || !strings.Contains(err.Error(), "denied host") {
+		t.Errorf("expected the denied host to be refused when dialing, got %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	weatherTimeout = 15 * time.Second
+	// maxWeatherBytes caps the Open-Meteo responses read.
+	maxWeatherBytes = 1 << 20
+	// DefaultForecastDays is how many days a forecast covers unless asked
+	// otherwise, and maxForecastDays the most it covers.
+	DefaultForecastDays = 3
+	maxForecastDays     = 7
+)
+
+var (
+	// openMeteoGeocode and openMeteoForecast are the Open-Meteo APIs;
+	// tests point them at a local server.
+	openMeteoGeocode  = "https://geocoding-api.open-meteo.com/v1/search"
+	openMeteoForecast = "https://api.open-meteo.com/v1/forecast"
+)
+
+// Place is a geocoded location.
+type Place struct {
+	Name      string  `json:"name"`
+	Admin1    string  `json:"admin1"` // state or region
+	Country   string  `json:"country"`
+	Code      string  `json:"country_code"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (p Place) String() string {
+	parts := []string{p.Name}
+	for _, s := range []string{p.Admin1, p.Country} {
+		if s != "" && s != p.Name {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Geocode finds a place by name with the Open-Meteo geocoding API. The
+// name may be qualified after a comma ("Springfield, Illinois",
+// "Paris, FR"), or be a "latitude,longitude" pair.
+func Geocode(ctx context.Context, location string) (Place, error) {
+	location = strings.TrimSpace(location)
+	if lat, lon, ok := parseCoordinates(location); ok {
+		return Place{Name: location, Latitude: lat, Longitude: lon}, nil
+	}
+	name, qualifier, _ := strings.Cut(location, ",")
+	name, qualifier = strings.TrimSpace(name), strings.ToLower(strings.TrimSpace(qualifier))
+	if name == "" {
+		return Place{}, fmt.Errorf("location is required")
+	}
+	var resp struct {
+		Results []Place `json:"results"`
+	}
+	q := url.Values{"name": {name}, "count": {"10"}, "language": {"en"}, "format": {"json"}}
+	if err := getWeatherJSON(ctx, openMeteoGeocode+"?"+q.Encode(), &resp); err != nil {
+		return Place{}, err
+	}
+	if len(resp.Results) == 0 {
+		return Place{}, fmt.Errorf("no place named %q found", name)
+	}
+	// Results are ordered by population; the qualifier picks among them.
+	if qualifier != "" {
+		region := qualifier
+		if state, ok := usStates[strings.ToUpper(qualifier)]; ok {
+			region = strings.ToLower(state)
+		}
+		for _, p := range resp.Results {
+			if strings.ToLower(p.Admin1) == region || strings.ToLower(p.Country) == qualifier || strings.ToLower(p.Code) == qualifier {
+				return p, nil
+			}
+		}
+	}
+	return resp.Results[0], nil
+}
+
+func parseCoordinates(s string) (lat, lon float64, ok bool) {
+	a, b, found := strings.Cut(s, ",")
+	if !found {
+		return 0, 0, false
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	lon, err2 := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if err1 != nil || err2 != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// Forecast returns the current weather and a daily forecast for place as
+// Markdown, in imperial units (°F, mph, inches) if imperial.
+func Forecast(ctx context.Context, place Place, days int, imperial bool) (string, error) {
+	if days <= 0 {
+		days = DefaultForecastDays
+	}
+	days = min(days, maxForecastDays)
+	q := url.Values{
+		"latitude":      {strconv.FormatFloat(place.Latitude, 'f', 4, 64)},
+		"longitude":     {strconv.FormatFloat(place.Longitude, 'f', 4, 64)},
+		"current":       {"temperature_2m,apparent_temperature,relative_humidity_2m,weather_code,wind_speed_10m"},
+		"daily":         {"weather_code,temperature_2m_max,temperature_2m_min,precipitation_sum,precipitation_probability_max,wind_speed_10m_max"},
+		"timezone":      {"auto"},
+		"forecast_days": {strconv.Itoa(days)},
+	}
+	temp, speed, rain := "°C", "km/h", "mm"
+	if imperial {
+		q.Set("temperature_unit", "fahrenheit")
+		q.Set("wind_speed_unit", "mph")
+		q.Set("precipitation_unit", "inch")
+		temp, speed, rain = "°F", "mph", "in"
+	}
+	var resp struct {
+		Timezone string `json:"timezone"`
+		Current  struct {
+			Time        string  `json:"time"`
+			Temperature float64 `json:"temperature_2m"`
+			FeelsLike   float64 `json:"apparent_temperature"`
+			Humidity    float64 `json:"relative_humidity_2m"`
+			Code        int     `json:"weather_code"`
+			Wind        float64 `json:"wind_speed_10m"`
+		} `json:"current"`
+		Daily struct {
+			Time          []string  `json:"time"`
+			Code          []int     `json:"weather_code"`
+			Max           []float64 `json:"temperature_2m_max"`
+			Min           []float64 `json:"temperature_2m_min"`
+			Precipitation []float64 `json:"precipitation_sum"`
+			Chance        []float64 `json:"precipitation_probability_max"`
+			Wind          []float64 `json:"wind_speed_10m_max"`
+		} `json:"daily"`
+	}
+	if err := getWeatherJSON(ctx, openMeteoForecast+"?"+q.Encode(), &resp); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	c := resp.Current
+	sb.WriteString(fmt.Sprintf("**Weather for %s** (as of %s %s)\n", place, strings.Replace(c.Time, "T", " ", 1), resp.Timezone))
+	sb.WriteString(fmt.Sprintf("Now: %s, %.0f%s (feels like %.0f%s), humidity %.0f%%, wind %.0f %s\n", weatherCode(c.Code), c.Temperature, temp, c.FeelsLike, temp, c.Humidity, c.Wind, speed))
+	d := resp.Daily
+	for i, day := range d.Time {
+		if i >= len(d.Code) || i >= len(d.Max) || i >= len(d.Min) {
+			break
+		}
+		label := day
+		if t, err := time.Parse("2006-01-02", day); err == nil {
+			label = t.Format("Mon Jan 2")
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s, %.0f–%.0f%s", label, weatherCode(d.Code[i]), d.Min[i], d.Max[i], temp))
+		if i < len(d.Chance) && i < len(d.Precipitation) && (d.Chance[i] > 0 || d.Precipitation[i] > 0) {
+			sb.WriteString(fmt.Sprintf(", %.0f%% chance of precipitation (%.2g %s)", d.Chance[i], d.Precipitation[i], rain))
+		}
+		if i < len(d.Wind) {
+			sb.WriteString(fmt.Sprintf(", wind up to %.0f %s", d.Wind[i], speed))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+func getWeatherJSON(ctx context.Context, endpoint string, v any) error {
+	if err := ValidateURL(ctx, endpoint); err != nil {
+		return fmt.Errorf("invalid weather URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create weather request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := NewSafeClient(weatherTimeout).Do(req)
+	if err != nil {
+		return fmt.Errorf("weather request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body := io.LimitReader(resp.Body, maxWeatherBytes)
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Reason string `json:"reason"`
+		}
+		_ = json.NewDecoder(body).Decode(&e)
+		if e.Reason != "" {
+			return fmt.Errorf("Open-Meteo returned status %d: %s", resp.StatusCode, e.Reason)
+		}
+		return fmt.Errorf("Open-Meteo returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode weather response: %w", err)
+	}
+	return nil
+}
+
+// weatherCode describes a WMO weather interpretation code.
+func weatherCode(code int) string {
+	switch code {
+	case 0:
+		return "clear sky"
+	case 1:
+		return "mainly clear"
+	case 2:
+		return "partly cloudy"
+	case 3:
+		return "overcast"
+	case 45, 48:
+		return "fog"
+	case 51, 53, 55:
+		return "drizzle"
+	case 56, 57:
+		return "freezing drizzle"
+	case 61:
+		return "light rain"
+	case 63:
+		return "rain"
+	case 65:
+		return "heavy rain"
+	case 66, 67:
+		return "freezing rain"
+	case 71:
+		return "light snow"
+	case 73:
+		return "snow"
+	case 75:
+		return "heavy snow"
+	case 77:
+		return "snow grains"
+	case 80, 81:
+		return "rain showers"
+	case 82:
+		return "violent rain showers"
+	case 85, 86:
+		return "snow showers"
+	case 95:
+		return "thunderstorm"
+	case 96, 99:
+		return "thunderstorm with hail"
+	default:
+		return fmt.Sprintf("weather code %d", code)
+	}
+}
+
+// usStates maps US state abbreviations to the region names Open-Meteo
+// gives, so "Dallas, TX" finds the right Dallas.
+var usStates = map[string]string{
+	"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas", "CA": "California",
+	"CO": "Colorado", "CT": "Connecticut", "DE": "Delaware", "FL": "Florida", "GA": "Georgia",
+	"HI": "Hawaii", "ID": "Idaho", "IL": "Illinois", "IN": "Indiana", "IA": "Iowa",
+	"KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana", "ME": "Maine", "MD": "Maryland",
+	"MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota", "MS": "Mississippi", "MO": "Missouri",
+	"MT": "Montana", "NE": "Nebraska", "NV": "Nevada", "NH": "New Hampshire", "NJ": "New Jersey",
+	"NM": "New Mexico", "NY": "New York", "NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio",
+	"OK": "Oklahoma", "OR": "Oregon", "PA": "Pennsylvania", "RI": "Rhode Island", "SC": "South Carolina",
+	"SD": "South Dakota", "TN": "Tennessee", "TX": "Texas", "UT": "Utah", "VT": "Vermont",
+	"VA": "Virginia", "WA": "Washington", "WV": "West Virginia", "WI": "Wisconsin", "WY": "Wyoming",
+	"DC": "District of Columbia",
+}
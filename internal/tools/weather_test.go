@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWeather(t *testing.T) {
+	var forecastQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			if !strings.EqualFold(r.URL.Query().Get("name"), "Dallas") {
+				_, _ = w.Write([]byte(`{}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[
+				{"name":"Dallas","admin1":"Georgia","country":"United States","country_code":"US","latitude":33.92,"longitude":-84.84},
+				{"name":"Dallas","admin1":"Texas","country":"United States","country_code":"US","latitude":32.78,"longitude":-96.81}]}`))
+		case "/forecast":
+			forecastQuery = r.URL.RawQuery
+			_, _ = w.Write([]byte(`{"timezone":"America/Chicago",
+				"current":{"time":"2026-03-10T07:00","temperature_2m":58.2,"apparent_temperature":55.9,"relative_humidity_2m":71,"weather_code":2,"wind_speed_10m":9.4},
+				"daily":{"time":["2026-03-10","2026-03-11"],"weather_code":[61,0],"temperature_2m_max":[72.1,75],"temperature_2m_min":[55,57.6],"precipitation_sum":[0.12,0],"precipitation_probability_max":[60,0],"wind_speed_10m_max":[15,8]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":true,"reason":"Invalid request"}`))
+		}
+	}))
+	defer srv.Close()
+	oldGeocode, oldForecast := openMeteoGeocode, openMeteoForecast
+	openMeteoGeocode, openMeteoForecast = srv.URL+"/search", srv.URL+"/forecast"
+	defer func() { openMeteoGeocode, openMeteoForecast = oldGeocode, oldForecast }()
+	ctx := context.Background()
+
+	for location, want := range map[string]string{
+		"Dallas":           "Georgia",
+		"Dallas, TX":       "Texas",
+		"dallas , texas":   "Texas",
+		"Dallas, Atlantis": "Georgia",
+	} {
+		if p, err := Geocode(ctx, location); err != nil || p.Admin1 != want {
+			t.Errorf("Geocode(%q) = %+v, %v; want %s", location, p, err, want)
+		}
+	}
+	if p, err := Geocode(ctx, "32.78, -96.81"); err != nil || p.Latitude != 32.78 || p.Longitude != -96.81 {
+		t.Errorf("unexpected coordinates %+v, %v", p, err)
+	}
+	if _, err := Geocode(ctx, "Nowhere"); err == nil || !strings.Contains(err.Error(), "no place named") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	place, _ := Geocode(ctx, "Dallas, TX")
+	got, err := Forecast(ctx, place, 0, true)
+	if err != nil {
+		t.Fatalf("Forecast failed: %v", err)
+	}
+	want := "**Weather for Dallas, Texas, United States** (as of 2026-03-10 07:00 America/Chicago)\n" +
+		"Now: partly cloudy, 58°F (feels like 56°F), humidity 71%, wind 9 mph\n" +
+		"- Tue Mar 10: light rain, 55–72°F, 60% chance of precipitation (0.12 in), wind up to 15 mph\n" +
+		"- Wed Mar 11: clear sky, 58–75°F, wind up to 8 mph\n"
+	if got != want {
+		t.Errorf("unexpected forecast:\n%s\nwant:\n%s", got, want)
+	}
+	if !strings.Contains(forecastQuery, "temperature_unit=fahrenheit") || !strings.Contains(forecastQuery, "forecast_days=3") || !strings.Contains(forecastQuery, "latitude=32.7800") {
+		t.Errorf("unexpected query %q", forecastQuery)
+	}
+
+	openMeteoForecast = srv.URL + "/broken"
+	if _, err := Forecast(ctx, place, 10, false); err == nil || !strings.Contains(err.Error(), "400: Invalid request") {
+		t.Errorf("expected the API's reason, got %v", err)
+	}
+}
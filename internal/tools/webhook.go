@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	webhookTimeout = 30 * time.Second
+
+	// WebhookSignatureHeader carries the HMAC-SHA256 of the request body,
+	// as "sha256=<hex>", when a webhook has a secret.
+	WebhookSignatureHeader = "X-Ravenbot-Signature-256"
+	// WebhookTimestampHeader is the Unix time the request was signed at;
+	// it is part of the signed message, so receivers can reject replays.
+	WebhookTimestampHeader = "X-Ravenbot-Timestamp"
+)
+
+// SignWebhook returns the signature of a webhook body sent at timestamp:
+// "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by
+// secret.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// PostWebhook sends payload as JSON to url. With a secret, the body is
+// signed (see SignWebhook). Any status other than 2xx is an error.
+func PostWebhook(ctx context.Context, url, secret, event string, payload any) error {
+	if err := ValidateURL(ctx, url); err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Ravenbot-Event", event)
+	if secret != "" {
+		now := time.Now().Unix()
+		req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(now, 10))
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(secret, now, body))
+	}
+
+	resp, err := NewSafeClient(webhookTimeout).Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if msg := strings.TrimSpace(string(detail)); msg != "" {
+			return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, msg)
+		}
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPostWebhook(t *testing.T) {
+	var body []byte
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		if strings.Contains(string(body), "reject") {
+			http.Error(w, "bad payload", http.StatusUnprocessableEntity)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if err := PostWebhook(ctx, srv.URL, "s3cret", "job.report", map[string]string{"markdown": "# Hello"}); err != nil {
+		t.Fatalf("PostWebhook failed: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(body, &got); err != nil || got["markdown"] != "# Hello" {
+		t.Errorf("unexpected body %s (%v)", body, err)
+	}
+	if header.Get("Content-Type") != "application/json" || header.Get("X-Ravenbot-Event") != "job.report" {
+		t.Errorf("unexpected headers: %v", header)
+	}
+	ts, err := strconv.ParseInt(header.Get(WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("bad timestamp header: %v", err)
+	}
+	if sig := header.Get(WebhookSignatureHeader); sig != SignWebhook("s3cret", ts, body) {
+		t.Errorf("signature %q does not verify", sig)
+	}
+	if SignWebhook("other", ts, body) == SignWebhook("s3cret", ts, body) {
+		t.Error("expected the signature to depend on the secret")
+	}
+
+	if err := PostWebhook(ctx, srv.URL, "", "job.report", map[string]string{}); err != nil {
+		t.Fatalf("PostWebhook without a secret failed: %v", err)
+	}
+	if header.Get(WebhookSignatureHeader) != "" {
+		t.Error("expected no signature without a secret")
+	}
+
+	err = PostWebhook(ctx, srv.URL, "", "job.report", map[string]string{"markdown": "reject"})
+	if err == nil || !strings.Contains(err.Error(), "422: bad payload") {
+		t.Errorf("expected the status and body in the error, got %v", err)
+	}
+	if err := PostWebhook(ctx, "ftp://example.com/hook", "", "job.report", nil); err == nil {
+		t.Error("expected a non-HTTP URL to be rejected")
+	}
+}
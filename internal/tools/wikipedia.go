@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	wikipediaTimeout = 15 * time.Second
+	// maxWikipediaBytes caps the API responses read.
+	maxWikipediaBytes = 1 << 20
+
+	DefaultWikipediaResults = 5
+	maxWikipediaResults     = 10
+)
+
+// wikipediaURL is the base of each language's Wikipedia, with {lang} for
+// the language code; tests point it at a local server.
+var wikipediaURL = "https://{lang}.wikipedia.org"
+
+var wikipediaLang = regexp.MustCompile(`^[a-z]{2,3}(-[a-z]{2,8})?$`)
+
+// WikipediaResult is an article matching a search.
+type WikipediaResult struct {
+	Title       string
+	Description string
+	Excerpt     string
+}
+
+// WikipediaSummary is the lead of an article.
+type WikipediaSummary struct {
+	Title          string
+	Description    string
+	Extract        string
+	URL            string
+	Disambiguation bool
+	Modified       time.Time
+}
+
+// SearchWikipedia finds the articles matching query in the Wikipedia of
+// lang ("en" if empty), best first: at most limit (5 by default, at most
+// 10).
+func SearchWikipedia(ctx context.Context, lang, query string, limit int) ([]WikipediaResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if limit <= 0 {
+		limit = DefaultWikipediaResults
+	}
+	limit = min(limit, maxWikipediaResults)
+	base, err := wikipediaBase(lang)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Pages []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Excerpt     string `json:"excerpt"`
+		} `json:"pages"`
+	}
+	q := url.Values{"q": {query}, "limit": {strconv.Itoa(limit)}}
+	if _, err := getWikipediaJSON(ctx, base+"/w/rest.php/v1/search/page?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	results := make([]WikipediaResult, 0, len(resp.Pages))
+	for _, p := range resp.Pages {
+		results = append(results, WikipediaResult{Title: p.Title, Description: p.Description, Excerpt: strings.Join(strings.Fields(html.UnescapeString(stripTags(p.Excerpt))), " ")})
+	}
+	return results, nil
+}
+
+// GetWikipediaSummary returns the lead of the article title in the
+// Wikipedia of lang ("en" if empty), following redirects.
+func GetWikipediaSummary(ctx context.Context, lang, title string) (WikipediaSummary, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return WikipediaSummary{}, fmt.Errorf("title is required")
+	}
+	base, err := wikipediaBase(lang)
+	if err != nil {
+		return WikipediaSummary{}, err
+	}
+	var resp struct {
+		Type        string `json:"type"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Extract     string `json:"extract"`
+		Timestamp   string `json:"timestamp"`
+		ContentURLs struct {
+			Desktop struct {
+				Page string `json:"page"`
+			} `json:"desktop"`
+		} `json:"content_urls"`
+	}
+	endpoint := base + "/api/rest_v1/page/summary/" + url.PathEscape(strings.ReplaceAll(title, " ", "_")) + "?redirect=true"
+	status, err := getWikipediaJSON(ctx, endpoint, &resp)
+	if status == http.StatusNotFound {
+		return WikipediaSummary{}, fmt.Errorf("no Wikipedia article named %q", title)
+	}
+	if err != nil {
+		return WikipediaSummary{}, err
+	}
+	s := WikipediaSummary{
+		Title:          resp.Title,
+		Description:    resp.Description,
+		Extract:        strings.TrimSpace(resp.Extract),
+		URL:            resp.ContentURLs.Desktop.Page,
+		Disambiguation: resp.Type == "disambiguation",
+	}
+	s.Modified, _ = time.Parse(time.RFC3339, resp.Timestamp)
+	return s, nil
+}
+
+func wikipediaBase(lang string) (string, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		lang = "en"
+	}
+	if !wikipediaLang.MatchString(lang) {
+		return "", fmt.Errorf("invalid Wikipedia language %q", lang)
+	}
+	return strings.ReplaceAll(wikipediaURL, "{lang}", lang), nil
+}
+
+// getWikipediaJSON decodes the response from endpoint into v, returning
+// the status code as well so callers can tell a missing article.
+func getWikipediaJSON(ctx context.Context, endpoint string, v any) (int, error) {
+	if err := ValidateURL(ctx, endpoint); err != nil {
+		return 0, fmt.Errorf("invalid Wikipedia URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Wikipedia request: %w", err)
+	}
+	// Wikimedia asks API clients to identify themselves.
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+	resp, err := newCachedClient(wikipediaTimeout).Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("Wikipedia request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body := io.LimitReader(resp.Body, maxWikipediaBytes)
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("Wikipedia returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to decode Wikipedia response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
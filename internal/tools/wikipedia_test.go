@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWikipedia(t *testing.T) {
+	var summaryPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/es/w/rest.php/v1/search/page":
+			if r.URL.Query().Get("q") != "raspberry pi" || r.URL.Query().Get("limit") != "2" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"pages":[
+				{"id":1,"key":"Raspberry_Pi","title":"Raspberry Pi","description":"Computadora de placa única","excerpt":"La <span class=\"searchmatch\">Raspberry</span> <span class=\"searchmatch\">Pi</span> es &quot;barata&quot;"},
+				{"id":2,"key":"Raspberry_Pi_OS","title":"Raspberry Pi OS","excerpt":"Sistema operativo"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/en/api/rest_v1/page/summary/"):
+			summaryPath = r.URL.EscapedPath()
+			switch strings.TrimPrefix(r.URL.Path, "/en/api/rest_v1/page/summary/") {
+			case "Go_(programming_language)":
+				_, _ = w.Write([]byte(`{"type":"standard","title":"Go (programming language)","description":"Programming language","extract":"Go is a high-level general purpose programming language. ","timestamp":"2026-09-30T12:00:00Z","content_urls":{"desktop":{"page":"https://en.wikipedia.org/wiki/Go_(programming_language)"}}}`))
+			case "Mercury":
+				_, _ = w.Write([]byte(`{"type":"disambiguation","title":"Mercury","extract":"Mercury may refer to:"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"type":"https://mediawiki.org/wiki/HyperSwitch/errors/not_found"}`))
+			}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+	old := wikipediaURL
+	wikipediaURL = srv.URL + "/{lang}"
+	defer func() { wikipediaURL = old }()
+	ctx := context.Background()
+
+	results, err := SearchWikipedia(ctx, "ES", "raspberry pi", 2)
+	if err != nil {
+		t.Fatalf("SearchWikipedia failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Title != "Raspberry Pi" || results[0].Excerpt != `La Raspberry Pi es "barata"` {
+		t.Errorf("unexpected results %+v", results)
+	}
+
+	s, err := GetWikipediaSummary(ctx, "", "Go (programming language)")
+	if err != nil {
+		t.Fatalf("GetWikipediaSummary failed: %v", err)
+	}
+	if s.Title != "Go (programming language)" || s.Disambiguation || s.Extract != "Go is a high-level general purpose programming language." ||
+		s.URL == "" || s.Modified.Year() != 2026 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+	if summaryPath != "/en/api/rest_v1/page/summary/Go_%28programming_language%29" {
+		t.Errorf("unexpected summary path %s", summaryPath)
+	}
+	if s, err := GetWikipediaSummary(ctx, "en", "Mercury"); err != nil || !s.Disambiguation {
+		t.Errorf("expected a disambiguation page, got %+v, %v", s, err)
+	}
+	if _, err := GetWikipediaSummary(ctx, "en", "No such article"); err == nil || !strings.Contains(err.Error(), "no Wikipedia article") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, err := SearchWikipedia(ctx, "en/../x", "go", 0); err == nil || !strings.Contains(err.Error(), "invalid Wikipedia language") {
+		t.Errorf("expected language error, got %v", err)
+	}
+	if _, err := SearchWikipedia(ctx, "en", " ", 0); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+}
@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxWorkspaceFileBytes caps the size of a file read or written in the
+	// workspace, and maxWorkspaceBytes all the files in it.
+	MaxWorkspaceFileBytes = 1 << 20
+	maxWorkspaceBytes     = 100 << 20
+	// maxWorkspaceEntries caps the entries a listing shows.
+	maxWorkspaceEntries = 200
+)
+
+// Workspace is the directory the agent's file tools are confined to. Names
+// are slash-separated and relative to it; they are resolved with os.Root,
+// so neither ".." nor symbolic links lead outside.
+type Workspace struct {
+	Dir string
+}
+
+// WorkspaceEntry is a file or directory in a workspace listing.
+type WorkspaceEntry struct {
+	Name     string
+	Dir      bool
+	Size     int64
+	Modified time.Time
+}
+
+// open opens the workspace root, creating the directory on first use.
+func (w Workspace) open() (*os.Root, error) {
+	if w.Dir == "" {
+		return nil, fmt.Errorf("no workspace directory is configured")
+	}
+	if err := os.MkdirAll(w.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	root, err := os.OpenRoot(w.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workspace: %w", err)
+	}
+	return root, nil
+}
+
+// cleanName makes name relative to the workspace, so "/notes.md" is the
+// file at its top.
+func cleanName(name string) string {
+	name = path.Clean("/" + strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if name == "/" {
+		return "."
+	}
+	return name[1:]
+}
+
+// ReadFile returns the text of the file name.
+func (w Workspace) ReadFile(name string) (string, error) {
+	root, err := w.open()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = root.Close() }()
+	name = cleanName(name)
+	f, err := root.Open(name)
+	if err != nil {
+		return "", workspaceError(name, err)
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return "", workspaceError(name, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", name)
+	}
+	if info.Size() > MaxWorkspaceFileBytes {
+		return "", fmt.Errorf("%s is larger than %d KB", name, MaxWorkspaceFileBytes>>10)
+	}
+	data, err := io.ReadAll(io.LimitReader(f, MaxWorkspaceFileBytes))
+	if err != nil {
+		return "", workspaceError(name, err)
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("%s is not a text file", name)
+	}
+	return string(data), nil
+}
+
+// WriteFile writes content to the file name, or appends it if appendTo,
+// creating the file and its directories as needed. It returns the file's
+// new size.
+func (w Workspace) WriteFile(name, content string, appendTo bool) (int64, error) {
+	root, err := w.open()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = root.Close() }()
+	name = cleanName(name)
+	if name == "." {
+		return 0, fmt.Errorf("a file name is required")
+	}
+	var existing int64
+	if info, err := root.Stat(name); err == nil {
+		if info.IsDir() {
+			return 0, fmt.Errorf("%s is a directory", name)
+		}
+		existing = info.Size()
+	}
+	size := int64(len(content))
+	if appendTo {
+		size += existing
+	}
+	if size > MaxWorkspaceFileBytes {
+		return 0, fmt.Errorf("%s would be larger than %d KB", name, MaxWorkspaceFileBytes>>10)
+	}
+	total, err := workspaceSize(root)
+	if err != nil {
+		return 0, err
+	}
+	if total-existing+size > maxWorkspaceBytes {
+		return 0, fmt.Errorf("the workspace is full (%d MB)", maxWorkspaceBytes>>20)
+	}
+	if dir := path.Dir(name); dir != "." {
+		if err := root.MkdirAll(dir, 0755); err != nil {
+			return 0, workspaceError(dir, err)
+		}
+	}
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendTo {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := root.OpenFile(name, flag, 0644)
+	if err != nil {
+		return 0, workspaceError(name, err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return 0, workspaceError(name, err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, workspaceError(name, err)
+	}
+	return size, nil
+}
+
+// List returns the entries of the directory dir ("" for the top),
+// directories first.
+func (w Workspace) List(dir string) ([]WorkspaceEntry, error) {
+	root, err := w.open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = root.Close() }()
+	dir = cleanName(dir)
+	entries, err := fs.ReadDir(root.FS(), dir)
+	if err != nil {
+		return nil, workspaceError(dir, err)
+	}
+	out := make([]WorkspaceEntry, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, WorkspaceEntry{Name: e.Name(), Dir: e.IsDir(), Size: info.Size(), Modified: info.ModTime()})
+	}
+	slices.SortFunc(out, func(a, b WorkspaceEntry) int {
+		if a.Dir != b.Dir {
+			if a.Dir {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	if len(out) > maxWorkspaceEntries {
+		out = out[:maxWorkspaceEntries]
+	}
+	return out, nil
+}
+
+// workspaceSize adds up the sizes of the files in the workspace.
+func workspaceSize(root *os.Root) (int64, error) {
+	var total int64
+	err := fs.WalkDir(root.FS(), ".", func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure workspace: %w", err)
+	}
+	return total, nil
+}
+
+// workspaceError describes a file error without the workspace's own path.
+func workspaceError(name string, err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("%s does not exist", name)
+	case strings.Contains(err.Error(), "path escapes from parent"):
+		return fmt.Errorf("%s is outside the workspace", name)
+	default:
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			return fmt.Errorf("%s: %w", name, pe.Err)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkspace(t *testing.T) {
+	base := t.TempDir()
+	w := Workspace{Dir: filepath.Join(base, "workspace")}
+
+	if _, err := w.WriteFile("drafts/report.md", "# Part 1\n", false); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if size, err := w.WriteFile("/drafts/report.md", "# Part 2\n", true); err != nil || size != 18 {
+		t.Fatalf("append = %d, %v", size, err)
+	}
+	if text, err := w.ReadFile("drafts/report.md"); err != nil || text != "# Part 1\n# Part 2\n" {
+		t.Errorf("ReadFile = %q, %v", text, err)
+	}
+	if _, err := w.WriteFile("notes.txt", "x", false); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	entries, err := w.List("")
+	if err != nil || len(entries) != 2 || entries[0].Name != "drafts" || !entries[0].Dir || entries[1].Name != "notes.txt" || entries[1].Size != 1 {
+		t.Errorf("List = %+v, %v", entries, err)
+	}
+
+	// ".." stays inside the workspace, and links can't lead out of it.
+	if _, err := w.WriteFile("../escape.txt", "x", false); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "escape.txt")); err == nil {
+		t.Error("a file was written outside the workspace")
+	}
+	if err := os.WriteFile(filepath.Join(base, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(base, "secret.txt"), filepath.Join(w.Dir, "link.txt")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	if _, err := w.ReadFile("link.txt"); err == nil || !strings.Contains(err.Error(), "outside the workspace") {
+		t.Errorf("expected an error reading through a link, got %v", err)
+	}
+
+	if _, err := w.WriteFile("big.txt", strings.Repeat("x", MaxWorkspaceFileBytes+1), false); err == nil {
+		t.Error("expected an error for an oversized file")
+	}
+	if _, err := w.ReadFile("missing.md"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected a missing file error, got %v", err)
+	}
+	if _, err := w.ReadFile("drafts"); err == nil {
+		t.Error("expected an error reading a directory")
+	}
+}